@@ -2,6 +2,7 @@ package ecs
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
@@ -22,6 +23,7 @@ import (
 	"github.com/hashicorp/waypoint-plugin-sdk/docs"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/builtin/docker"
+	"github.com/hashicorp/waypoint/internal/pkg/resource"
 )
 
 type Platform struct {
@@ -43,6 +45,21 @@ func (p *Platform) DestroyFunc() interface{} {
 	return p.Destroy
 }
 
+// PlanFunc implements plugin.Planner
+func (p *Platform) PlanFunc() interface{} {
+	return p.Plan
+}
+
+// ScaleFunc implements plugin.Scaler
+func (p *Platform) ScaleFunc() interface{} {
+	return p.Scale
+}
+
+// ResourcesFunc implements plugin.DeclaredResources
+func (p *Platform) ResourcesFunc() interface{} {
+	return p.Resources
+}
+
 // ValidateAuthFunc implements component.Authenticator
 func (p *Platform) ValidateAuthFunc() interface{} {
 	return p.ValidateAuth
@@ -196,6 +213,7 @@ func (p *Platform) Deploy(
 	src *component.Source,
 	img *docker.Image,
 	deployConfig *component.DeploymentConfig,
+	labels *component.LabelSet,
 	ui terminal.UI,
 ) (*Deployment, error) {
 	var (
@@ -244,7 +262,7 @@ func (p *Platform) Deploy(
 		},
 
 		Run: func(s LifecycleStatus) error {
-			dep, err = p.Launch(ctx, s, log, ui, sess, src, img, deployConfig, role, cluster, logGroup)
+			dep, err = p.Launch(ctx, s, log, ui, sess, src, img, deployConfig, labels, role, cluster, logGroup)
 			return err
 		},
 
@@ -524,24 +542,45 @@ func createSG(
 	return groupId, nil
 }
 
-func (p *Platform) Launch(
+// Plan returns the task definition that Deploy would register for the
+// image, without connecting to AWS. The execution role is looked up or
+// created when deploying, so it isn't part of the plan.
+func (p *Platform) Plan(
 	ctx context.Context,
-	s LifecycleStatus,
-	L hclog.Logger,
-	ui terminal.UI,
-	sess *session.Session,
-	app *component.Source,
+	log hclog.Logger,
+	src *component.Source,
 	img *docker.Image,
 	deployConfig *component.DeploymentConfig,
-	roleArn, clusterName, logGroup string,
-) (*Deployment, error) {
-	id, err := component.Id()
+	labels *component.LabelSet,
+) (string, error) {
+	logGroup := p.config.LogGroup
+	if logGroup == "" {
+		logGroup = "waypoint-logs"
+	}
+
+	input, err := p.taskDefinition(src, img, deployConfig, labels, "", logGroup)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	ecsSvc := ecs.New(sess)
+	data, err := json.MarshalIndent(input, "", "  ")
+	if err != nil {
+		return "", err
+	}
 
+	return string(data), nil
+}
+
+// taskDefinition returns the task definition to register for the image.
+// This is shared by Launch and Plan. The execution role is left unset if
+// roleArn is empty.
+func (p *Platform) taskDefinition(
+	app *component.Source,
+	img *docker.Image,
+	deployConfig *component.DeploymentConfig,
+	labels *component.LabelSet,
+	roleArn, logGroup string,
+) (*ecs.RegisterTaskDefinitionInput, error) {
 	streamPrefix := fmt.Sprintf("waypoint-%d", time.Now().Nanosecond())
 
 	env := []*ecs.KeyValuePair{
@@ -578,8 +617,6 @@ func (p *Platform) Launch(
 		},
 	}
 
-	L.Debug("registring task definition", "id", id)
-
 	var cpuShares int
 
 	runtime := aws.String("FARGATE")
@@ -645,27 +682,87 @@ func (p *Platform) Launch(
 
 	family := "waypoint-" + app.App
 
-	s.Status("Registering Task definition: %s", family)
-
-	taskOut, err := ecsSvc.RegisterTaskDefinition(&ecs.RegisterTaskDefinitionInput{
+	input := &ecs.RegisterTaskDefinitionInput{
 		ContainerDefinitions: []*ecs.ContainerDefinition{&def},
 
-		ExecutionRoleArn: aws.String(roleArn),
-		Cpu:              cpus,
-		Memory:           aws.String(mems),
-		Family:           aws.String(family),
+		Cpu:    cpus,
+		Memory: aws.String(mems),
+		Family: aws.String(family),
 
 		NetworkMode:             aws.String("awsvpc"),
 		RequiresCompatibilities: []*string{runtime},
 
-		Tags: []*ecs.Tag{
-			{
-				Key:   aws.String("waypoint-app"),
-				Value: aws.String(app.App),
-			},
+		Tags: ecsTags(app, labels),
+	}
+	if roleArn != "" {
+		input.ExecutionRoleArn = aws.String(roleArn)
+	}
+
+	return input, nil
+}
+
+// ecsTags returns the tags of the resources of the app: the labels of the
+// operation and "waypoint-app", sorted by key. Labels with keys that AWS
+// reserves are skipped.
+func ecsTags(app *component.Source, labels *component.LabelSet) []*ecs.Tag {
+	var keys []string
+	if labels != nil {
+		for k := range labels.Labels {
+			if k != "waypoint-app" && !strings.HasPrefix(strings.ToLower(k), "aws:") {
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	tags := []*ecs.Tag{
+		{
+			Key:   aws.String("waypoint-app"),
+			Value: aws.String(app.App),
 		},
-	})
+	}
+	for _, k := range keys {
+		tags = append(tags, &ecs.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(labels.Labels[k]),
+		})
+	}
+
+	return tags
+}
+
+func (p *Platform) Launch(
+	ctx context.Context,
+	s LifecycleStatus,
+	L hclog.Logger,
+	ui terminal.UI,
+	sess *session.Session,
+	app *component.Source,
+	img *docker.Image,
+	deployConfig *component.DeploymentConfig,
+	labels *component.LabelSet,
+	roleArn, clusterName, logGroup string,
+) (*Deployment, error) {
+	id, err := component.Id()
+	if err != nil {
+		return nil, err
+	}
+
+	ecsSvc := ecs.New(sess)
+
+	L.Debug("registring task definition", "id", id)
+
+	family := "waypoint-" + app.App
 
+	input, err := p.taskDefinition(app, img, deployConfig, labels, roleArn, logGroup)
+	if err != nil {
+		return nil, err
+	}
+	runtime := input.RequiresCompatibilities[0]
+
+	s.Status("Registering Task definition: %s", family)
+
+	taskOut, err := ecsSvc.RegisterTaskDefinition(input)
 	if err != nil {
 		return nil, err
 	}
@@ -991,6 +1088,8 @@ func (p *Platform) Launch(
 				TargetGroupArn: tgArn,
 			},
 		},
+		Tags:          ecsTags(app, labels),
+		PropagateTags: aws.String(ecs.PropagateTagsService),
 	})
 
 	if err != nil {
@@ -1011,6 +1110,82 @@ func (p *Platform) Launch(
 	return dep, nil
 }
 
+// Scale changes the desired count of the ECS service.
+func (p *Platform) Scale(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+	replicas int32,
+	ui terminal.UI,
+) error {
+	st := ui.Status()
+	defer st.Close()
+
+	sess, err := session.NewSession(aws.NewConfig().WithRegion(p.config.Region))
+	if err != nil {
+		return err
+	}
+
+	st.Update(fmt.Sprintf("Scaling ECS service to %d tasks...", replicas))
+	log.Debug("updating service desired count", "arn", deployment.ServiceArn, "count", replicas)
+	_, err = ecs.New(sess).UpdateServiceWithContext(ctx, &ecs.UpdateServiceInput{
+		Cluster:      &deployment.Cluster,
+		Service:      &deployment.ServiceArn,
+		DesiredCount: aws.Int64(int64(replicas)),
+	})
+	if err != nil {
+		return err
+	}
+
+	st.Step(terminal.StatusOK, fmt.Sprintf("ECS service scaled to %d tasks", replicas))
+	return nil
+}
+
+// Resources returns the ECS service with its running tasks, and the load
+// balancer resources that route to it.
+func (p *Platform) Resources(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+) ([]*resource.Resource, error) {
+	sess, err := session.NewSession(aws.NewConfig().WithRegion(p.config.Region))
+	if err != nil {
+		return nil, err
+	}
+
+	service := &resource.Resource{Type: "service", Name: deployment.ServiceArn, State: "missing"}
+	resp, err := ecs.New(sess).DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+		Cluster:  &deployment.Cluster,
+		Services: []*string{&deployment.ServiceArn},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Services) > 0 {
+		s := resp.Services[0]
+		service.State = fmt.Sprintf("%s, %d/%d tasks running",
+			strings.ToLower(aws.StringValue(s.Status)),
+			aws.Int64Value(s.RunningCount),
+			aws.Int64Value(s.DesiredCount))
+	}
+
+	resources := []*resource.Resource{
+		{Type: "cluster", Name: deployment.Cluster},
+		service,
+		{Type: "task definition", Name: deployment.TaskArn},
+	}
+	if deployment.TargetGroupArn != "" {
+		resources = append(resources, &resource.Resource{
+			Type: "target group", Name: deployment.TargetGroupArn})
+	}
+	if deployment.LoadBalancerArn != "" {
+		resources = append(resources, &resource.Resource{
+			Type: "load balancer", Name: deployment.LoadBalancerArn})
+	}
+
+	return resources, nil
+}
+
 func (p *Platform) Destroy(
 	ctx context.Context,
 	log hclog.Logger,
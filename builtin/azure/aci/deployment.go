@@ -6,9 +6,11 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2020-04-01/network"
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2015-11-01/subscriptions"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/hashicorp/waypoint-plugin-sdk/component"
 )
 
@@ -109,3 +111,40 @@ func (d *Deployment) createOrUpdate(ctx context.Context, auth autorest.Authorize
 
 	return response.Result(*c)
 }
+
+// createNetworkProfile creates or updates a network profile which attaches container
+// groups to the given subnet and returns the id of the profile. The profile is named
+// after the container group so subsequent deployments reuse it.
+func (d *Deployment) createNetworkProfile(ctx context.Context, auth autorest.Authorizer, location, subnetID string) (string, error) {
+	c := network.NewProfilesClient(d.ContainerGroup.SubscriptionId)
+	c.Authorizer = auth
+
+	name := d.ContainerGroup.Name + "-network-profile"
+	profile := network.Profile{
+		Location: &location,
+		ProfilePropertiesFormat: &network.ProfilePropertiesFormat{
+			ContainerNetworkInterfaceConfigurations: &[]network.ContainerNetworkInterfaceConfiguration{
+				{
+					Name: to.StringPtr(d.ContainerGroup.Name + "-nic"),
+					ContainerNetworkInterfaceConfigurationPropertiesFormat: &network.ContainerNetworkInterfaceConfigurationPropertiesFormat{
+						IPConfigurations: &[]network.IPConfigurationProfile{
+							{
+								Name: to.StringPtr(d.ContainerGroup.Name + "-ip"),
+								IPConfigurationProfilePropertiesFormat: &network.IPConfigurationProfilePropertiesFormat{
+									Subnet: &network.Subnet{ID: &subnetID},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := c.CreateOrUpdate(ctx, d.ContainerGroup.ResourceGroup, name, profile)
+	if err != nil {
+		return "", fmt.Errorf("Unable to create or update network profile: %s", err)
+	}
+
+	return to.String(result.ID), nil
+}
@@ -181,6 +181,29 @@ To update the location you will need to manually destroy and recreate the resour
 		OsType: containerinstance.Linux,
 	}
 
+	// If we are deploying into a virtual network, the container group is attached
+	// to the subnet through a network profile. Container groups in a virtual network
+	// can only have a private IP address and do not support DNS name labels.
+	if p.config.VirtualNetwork != nil {
+		profileID := p.config.VirtualNetwork.NetworkProfileID
+		if profileID == "" {
+			log.Info("Creating network profile for subnet", "subnet", p.config.VirtualNetwork.SubnetID)
+			st.Update("Configuring network profile for the virtual network subnet")
+
+			profileID, err = deployment.createNetworkProfile(ctx, auth, p.config.Location, p.config.VirtualNetwork.SubnetID)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "Unable to create network profile for subnet: %s", err)
+			}
+		}
+
+		containerGroup.ContainerGroupProperties.NetworkProfile = &containerinstance.ContainerGroupNetworkProfile{
+			ID: &profileID,
+		}
+		containerGroup.ContainerGroupProperties.IPAddress = &containerinstance.IPAddress{
+			Type: containerinstance.Private,
+		}
+	}
+
 	// Add the tags
 	var tags = map[string]*string{
 		"_waypoint_hashicorp_com_nonce": to.StringPtr(time.Now().UTC().Format(time.RFC3339Nano)),
@@ -195,8 +218,20 @@ To update the location you will need to manually destroy and recreate the resour
 		})
 	}
 
-	// Add the managed identity if set
-	if p.config.ManagedIdentity != "" {
+	// Add the managed identities if set
+	switch {
+	case p.config.SystemAssignedIdentity && p.config.ManagedIdentity != "":
+		containerGroup.Identity = &containerinstance.ContainerGroupIdentity{
+			Type: containerinstance.SystemAssignedUserAssigned,
+			UserAssignedIdentities: map[string]*containerinstance.ContainerGroupIdentityUserAssignedIdentitiesValue{
+				p.config.ManagedIdentity: &containerinstance.ContainerGroupIdentityUserAssignedIdentitiesValue{},
+			},
+		}
+	case p.config.SystemAssignedIdentity:
+		containerGroup.Identity = &containerinstance.ContainerGroupIdentity{
+			Type: containerinstance.SystemAssigned,
+		}
+	case p.config.ManagedIdentity != "":
 		containerGroup.Identity = &containerinstance.ContainerGroupIdentity{
 			Type: containerinstance.UserAssigned,
 			UserAssignedIdentities: map[string]*containerinstance.ContainerGroupIdentityUserAssignedIdentitiesValue{
@@ -297,11 +332,18 @@ To update the location you will need to manually destroy and recreate the resour
 				}
 
 				if v.AzureFileShare != nil {
+					// if there is no key in the config use the environment so that
+					// the key does not need to be stored in the waypoint.hcl
+					key := v.AzureFileShare.StorageAccountKey
+					if key == "" {
+						key = os.Getenv("AZURE_STORAGE_KEY")
+					}
+
 					vol.AzureFile = &containerinstance.AzureFileVolume{
 						ShareName:          &v.AzureFileShare.Name,
 						ReadOnly:           &v.ReadOnly,
 						StorageAccountName: &v.AzureFileShare.StorageAccountName,
-						StorageAccountKey:  &v.AzureFileShare.StorageAccountKey,
+						StorageAccountKey:  &key,
 					}
 				}
 
@@ -348,8 +390,15 @@ To update the location you will need to manually destroy and recreate the resour
 
 	ports := *containerGroupResult.IPAddress.Ports
 	if len(ports) > 0 {
+		// Container groups in a virtual network do not have a FQDN, so
+		// fall back to the private IP address.
+		host := to.String(containerGroupResult.IPAddress.Fqdn)
+		if host == "" {
+			host = to.String(containerGroupResult.IPAddress.IP)
+		}
+
 		// Only set the URL if there is a port
-		deployment.Url = fmt.Sprintf("http://%s:%d", *containerGroupResult.IPAddress.Fqdn, *ports[0].Port)
+		deployment.Url = fmt.Sprintf("http://%s:%d", host, *ports[0].Port)
 
 		// Clear the status before we print the url
 		st.Close()
@@ -377,6 +426,7 @@ To update the location you will need to manually destroy and recreate the resour
 // AZURE_SUBSCRIPTION_ID = Subscription ID for your Azure account [required]
 // REGISTRY_USERNAME = Username for container registry, required when using a private registry
 // REGISTRY_PASSWORD = Password for container registry, required when using a private registry
+// AZURE_STORAGE_KEY = Storage account key for Azure file share volumes that do not set storage_account_key
 type Config struct {
 	// ResourceGroup is the resource group to deploy to.
 	ResourceGroup string `hcl:"resource_group,attr"`
@@ -395,6 +445,14 @@ type Config struct {
 	// Note: ManagedIdentity can not be used to authorize Container Instances to pull from private Container registries in Azure
 	ManagedIdentity string `hcl:"managed_identity,optional"`
 
+	// SystemAssignedIdentity enables the system assigned managed identity for
+	// the container group. This can be used together with ManagedIdentity.
+	SystemAssignedIdentity bool `hcl:"system_assigned_identity,optional"`
+
+	// VirtualNetwork deploys the container group into an existing virtual network
+	// subnet rather than exposing it with a public IP address.
+	VirtualNetwork *VirtualNetwork `hcl:"virtual_network,block"`
+
 	// Port the applications is listening on.
 	Ports []int `hcl:"ports,optional"`
 
@@ -410,6 +468,18 @@ type Config struct {
 	Volumes []Volume `hcl:"volume,block" validate:"dive"`
 }
 
+// VirtualNetwork defines the virtual network subnet to deploy the container
+// group into. Only one of SubnetID or NetworkProfileID can be set.
+type VirtualNetwork struct {
+	// SubnetID is the resource id of the subnet, the subnet must be delegated
+	// to Microsoft.ContainerInstance/containerGroups. A network profile for the
+	// subnet is created if it does not exist.
+	SubnetID string `hcl:"subnet_id,optional"`
+
+	// NetworkProfileID is the resource id of an existing network profile
+	NetworkProfileID string `hcl:"network_profile_id,optional"`
+}
+
 // RegistryCredentials are the user credentials needed to
 // authenticate with a container registry.
 type RegistryCredentials struct {
@@ -448,8 +518,9 @@ type AzureFileShareVolume struct {
 	Name string `hcl:"name,attr"`
 	// Storage account name
 	StorageAccountName string `hcl:"storage_account_name,attr"`
-	// Storage account key to access the storage
-	StorageAccountKey string `hcl:"storage_account_key,attr"`
+	// Storage account key to access the storage, if not set the environment
+	// variable AZURE_STORAGE_KEY is used
+	StorageAccountKey string `hcl:"storage_account_key,optional"`
 }
 
 // GitRepoVolume allows the mounting of a Git repository
@@ -519,6 +590,38 @@ deploy {
 		"the managed identity assigned to the container group",
 	)
 
+	doc.SetField(
+		"system_assigned_identity",
+		"enable the system assigned managed identity for the container group",
+		docs.Summary(
+			"this can be combined with managed_identity to assign both a system",
+			"and a user assigned identity",
+		),
+	)
+
+	doc.SetField(
+		"virtual_network",
+		"deploy the container group into an existing virtual network subnet",
+		docs.Summary(
+			"container groups in a virtual network only have a private IP address",
+			"and can not be used with managed identities",
+		),
+	)
+
+	doc.SetField(
+		"virtual_network.subnet_id",
+		"the resource id of the subnet to deploy to",
+		docs.Summary(
+			"the subnet must be delegated to Microsoft.ContainerInstance/containerGroups,",
+			"a network profile is created for the subnet if one does not exist",
+		),
+	)
+
+	doc.SetField(
+		"virtual_network.network_profile_id",
+		"the resource id of an existing network profile to use instead of subnet_id",
+	)
+
 	doc.SetField(
 		"ports",
 		"the ports the container is listening on, the first port in this list will be used by the entrypoint binary to direct traffic to your application",
@@ -575,6 +678,10 @@ deploy {
 	doc.SetField(
 		"volume.azure_file_share",
 		"the details for the Azure file share volume",
+		docs.Summary(
+			"if storage_account_key is not set the environment variable",
+			"AZURE_STORAGE_KEY is used",
+		),
 	)
 
 	doc.SetField(
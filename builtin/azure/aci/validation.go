@@ -20,10 +20,14 @@ func validateLocationAvailable(location string, validLocations []string) error {
 var errInvalidMemoryValue = fmt.Errorf("Memory allocated to a Cloud run instance must a minimum of 512MB and less than 16384MB (16GB)\n")
 var errInvalidCPUCount = fmt.Errorf("Invalid value for CPUCount, it is currently only possible to specify '1-4' CPUs\n")
 var errInvalidVolume = fmt.Errorf("Container instance volumes must have one of 'azure_file_share' or 'git_repo' fields set\n")
+var errInvalidVirtualNetwork = fmt.Errorf("Container instance virtual networks must have one of 'subnet_id' or 'network_profile_id' fields set\n")
+var errVirtualNetworkIdentity = fmt.Errorf("Container instances deployed to a virtual network do not support managed identities\n")
 
 func validateConfig(c Config) error {
 	v := validator.New()
 	v.RegisterStructValidation(validationVolumeStruct, Volume{})
+	v.RegisterStructValidation(validationVirtualNetworkStruct, VirtualNetwork{})
+	v.RegisterStructValidation(validationConfigStruct, Config{})
 
 	err := v.Struct(c)
 
@@ -37,8 +41,13 @@ func validateConfig(c Config) error {
 				errorMessage += errInvalidCPUCount.Error()
 			}
 
-			if err.Tag() == "one_of" {
+			switch err.Tag() {
+			case "one_of":
 				errorMessage += errInvalidVolume.Error()
+			case "vnet_one_of":
+				errorMessage += errInvalidVirtualNetwork.Error()
+			case "vnet_identity":
+				errorMessage += errVirtualNetworkIdentity.Error()
 			}
 		}
 
@@ -62,6 +71,25 @@ func validationVolumeStruct(sl validator.StructLevel) {
 	}
 }
 
+func validationVirtualNetworkStruct(sl validator.StructLevel) {
+	vnet := sl.Current().Interface().(VirtualNetwork)
+
+	// Error if both or none are set
+	if (vnet.SubnetID == "") == (vnet.NetworkProfileID == "") {
+		sl.ReportError(vnet, "virtual_network", "VirtualNetwork", "vnet_one_of", "")
+	}
+}
+
+func validationConfigStruct(sl validator.StructLevel) {
+	c := sl.Current().Interface().(Config)
+
+	// Azure does not support managed identities for container groups
+	// in a virtual network
+	if c.VirtualNetwork != nil && (c.ManagedIdentity != "" || c.SystemAssignedIdentity) {
+		sl.ReportError(c.VirtualNetwork, "virtual_network", "VirtualNetwork", "vnet_identity", "")
+	}
+}
+
 // return the server component from an image name
 func parseDockerServer(image string) string {
 	n, err := reference.ParseNamed(image)
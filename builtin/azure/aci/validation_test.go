@@ -78,6 +78,42 @@ func TestValidateConfig(t *testing.T) {
 			},
 			true,
 		},
+		"Valid with a virtual network subnet": {
+			Config{
+				Location: "europe-north1",
+				VirtualNetwork: &VirtualNetwork{
+					SubnetID: "/subscriptions/abc/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/aci",
+				},
+			},
+			true,
+		},
+		"Error when no virtual network fields are set": {
+			Config{
+				Location:       "europe-north1",
+				VirtualNetwork: &VirtualNetwork{},
+			},
+			false,
+		},
+		"Error when both virtual network fields are set": {
+			Config{
+				Location: "europe-north1",
+				VirtualNetwork: &VirtualNetwork{
+					SubnetID:         "subnet",
+					NetworkProfileID: "profile",
+				},
+			},
+			false,
+		},
+		"Error when virtual network is used with a managed identity": {
+			Config{
+				Location:               "europe-north1",
+				SystemAssignedIdentity: true,
+				VirtualNetwork: &VirtualNetwork{
+					NetworkProfileID: "profile",
+				},
+			},
+			false,
+		},
 	}
 
 	for name, tc := range tests {
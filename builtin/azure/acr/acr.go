@@ -0,0 +1,12 @@
+// Package acr contains components for storing images in Azure Container Registry.
+package acr
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk"
+)
+
+// Options are the SDK options to use for instantiation for
+// the Azure ACR plugin.
+var Options = []sdk.Option{
+	sdk.WithComponents(&Registry{}),
+}
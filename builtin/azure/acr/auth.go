@@ -0,0 +1,103 @@
+package acr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+// acrTokenUsername is the username used to authenticate with a registry when
+// the password is a refresh token obtained from an Azure AD access token.
+const acrTokenUsername = "00000000-0000-0000-0000-000000000000"
+
+// registryCredentials returns the username and password used to push to the
+// registry. If service principal credentials are set in the environment these
+// are used directly, otherwise a managed identity token is exchanged for a
+// registry refresh token.
+func registryCredentials(ctx context.Context, loginServer, identityClientID string) (string, string, error) {
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	clientSecret := os.Getenv("AZURE_CLIENT_SECRET")
+	if clientID != "" && clientSecret != "" {
+		return clientID, clientSecret, nil
+	}
+
+	msiEndpoint, err := adal.GetMSIVMEndpoint()
+	if err != nil {
+		return "", "", fmt.Errorf("Unable to get managed identity endpoint: %s", err)
+	}
+
+	resource := azure.PublicCloud.ResourceManagerEndpoint
+
+	var spt *adal.ServicePrincipalToken
+	if identityClientID != "" {
+		spt, err = adal.NewServicePrincipalTokenFromMSIWithUserAssignedID(msiEndpoint, resource, identityClientID)
+	} else {
+		spt, err = adal.NewServicePrincipalTokenFromMSI(msiEndpoint, resource)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("Unable to create managed identity token: %s", err)
+	}
+
+	if err := spt.RefreshWithContext(ctx); err != nil {
+		return "", "", fmt.Errorf(
+			"Unable to authenticate with a managed identity, ensure AZURE_CLIENT_ID and "+
+				"AZURE_CLIENT_SECRET are set or a managed identity is available: %s", err)
+	}
+
+	token, err := exchangeToken(ctx, loginServer, os.Getenv("AZURE_TENANT_ID"), spt.OAuthToken())
+	if err != nil {
+		return "", "", err
+	}
+
+	return acrTokenUsername, token, nil
+}
+
+// exchangeToken exchanges an Azure AD access token for a refresh token which
+// can be used as the password to authenticate with the registry.
+func exchangeToken(ctx context.Context, loginServer, tenant, accessToken string) (string, error) {
+	form := url.Values{
+		"grant_type":   {"access_token"},
+		"service":      {loginServer},
+		"access_token": {accessToken},
+	}
+	if tenant != "" {
+		form.Set("tenant", tenant)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("https://%s/oauth2/exchange", loginServer),
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Unable to exchange token with registry: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Unable to exchange token with registry, received status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("Unable to decode registry token response: %s", err)
+	}
+
+	return result.RefreshToken, nil
+}
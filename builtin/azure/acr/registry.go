@@ -0,0 +1,287 @@
+package acr
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/builtin/docker"
+	"github.com/mattn/go-isatty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Registry represents access to an Azure Container Registry.
+type Registry struct {
+	config Config
+}
+
+// Config implements Configurable
+func (r *Registry) Config() (interface{}, error) {
+	return &r.config, nil
+}
+
+// PushFunc implements component.Registry
+func (r *Registry) PushFunc() interface{} {
+	return r.Push
+}
+
+// Push pushes an image to the registry.
+func (r *Registry) Push(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	img *docker.Image,
+	ui terminal.UI,
+) (*docker.Image, error) {
+	stdout, _, err := ui.OutputWriters()
+	if err != nil {
+		return nil, err
+	}
+
+	loginServer := loginServer(r.config.Name)
+
+	// Make sure the registry is replicated to every configured location
+	// before we push so the image is available in each region.
+	if len(r.config.Replications) > 0 {
+		subscriptionID := r.config.SubscriptionID
+		if subscriptionID == "" {
+			subscriptionID = os.Getenv("AZURE_SUBSCRIPTION_ID")
+		}
+
+		if subscriptionID == "" || r.config.ResourceGroup == "" {
+			return nil, status.Error(
+				codes.FailedPrecondition,
+				"Registry replications require 'resource_group' and either 'subscription_id' or the environment variable 'AZURE_SUBSCRIPTION_ID' to be set",
+			)
+		}
+
+		st := ui.Status()
+		st.Update("Checking registry replications")
+		err := ensureReplications(ctx, log, subscriptionID, r.config.ResourceGroup, registryName(loginServer), r.config.Replications)
+		st.Close()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Unable to configure registry replications: %s", err)
+		}
+	}
+
+	cli, err := client.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	cli.NegotiateAPIVersion(ctx)
+
+	// Repositories are created by the registry on the first push, so there is
+	// nothing to create up front.
+	repository := r.config.Repository
+	if repository == "" {
+		repository = src.App
+	}
+
+	target := &docker.Image{Image: loginServer + "/" + repository, Tag: r.config.Tag}
+
+	ui.Output("Tagging Docker image: %s => %s", img.Name(), target.Name())
+
+	err = cli.ImageTag(ctx, img.Name(), target.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := reference.ParseNormalizedNamed(target.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	username, password, err := registryCredentials(ctx, loginServer, r.config.ManagedIdentityClientID)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	authInfo := map[string]string{
+		"username":      username,
+		"password":      password,
+		"serveraddress": loginServer,
+	}
+
+	authData, err := json.Marshal(authInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedAuth := base64.StdEncoding.EncodeToString(authData)
+
+	options := types.ImagePushOptions{
+		RegistryAuth: encodedAuth,
+	}
+
+	responseBody, err := cli.ImagePush(ctx, reference.FamiliarString(ref), options)
+	if err != nil {
+		return nil, err
+	}
+
+	defer responseBody.Close()
+
+	var (
+		termFd uintptr
+		isTerm bool
+	)
+
+	if f, ok := stdout.(*os.File); ok {
+		termFd = f.Fd()
+		isTerm = isatty.IsTerminal(termFd)
+	}
+
+	err = jsonmessage.DisplayJSONMessagesStream(responseBody, stdout, termFd, isTerm, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ui.Output("Docker image pushed: %s", target.Name())
+
+	return target, nil
+}
+
+// loginServer returns the login server for a registry, name can either be
+// the name of the registry or its login server.
+func loginServer(name string) string {
+	if strings.Contains(name, ".") {
+		return strings.ToLower(name)
+	}
+
+	return strings.ToLower(name) + ".azurecr.io"
+}
+
+// registryName returns the name of the registry resource from a login server.
+func registryName(loginServer string) string {
+	return strings.SplitN(loginServer, ".", 2)[0]
+}
+
+// Config is the configuration structure for the registry.
+// In addition to HCL defined configuration the following environment variables
+// are also valid
+// AZURE_CLIENT_ID = Client ID of the service principal used to push images
+// AZURE_CLIENT_SECRET = Client secret of the service principal used to push images
+// AZURE_TENANT_ID = Tenant ID used when exchanging a managed identity token
+// AZURE_SUBSCRIPTION_ID = Subscription ID of the registry, used for replications
+type Config struct {
+	// Name is the name or login server of the registry
+	Name string `hcl:"name,attr"`
+
+	// Repository to store the image into, defaults to the name of the application
+	Repository string `hcl:"repository,optional"`
+
+	// Tag is the tag to apply to the image.
+	Tag string `hcl:"tag,attr"`
+
+	// ManagedIdentityClientID is the client id of the user assigned managed
+	// identity to authenticate with when no service principal is set.
+	ManagedIdentityClientID string `hcl:"managed_identity_client_id,optional"`
+
+	// Replications is the list of locations the registry is replicated to.
+	Replications []string `hcl:"replications,optional"`
+
+	// ResourceGroup of the registry, required when setting replications.
+	ResourceGroup string `hcl:"resource_group,optional"`
+
+	// Azure subscription id, if not set the plugin will attempt to use the
+	// environment variable AZURE_SUBSCRIPTION_ID
+	SubscriptionID string `hcl:"subscription_id,optional"`
+}
+
+func (r *Registry) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&Config{}))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description("Store a docker image within an Azure Container Registry")
+
+	doc.Example(
+		`
+registry {
+    use "azure-acr" {
+      name = "myregistry"
+      repository = "waypoint-example"
+      tag = "latest"
+
+      resource_group = "resource-group-name"
+      replications = ["westeurope", "eastus"]
+    }
+}
+`)
+
+	doc.Input("docker.Image")
+	doc.Output("docker.Image")
+
+	doc.SetField(
+		"name",
+		"the name or login server of the registry",
+		docs.Summary(
+			"a registry name such as 'myregistry' is pushed to 'myregistry.azurecr.io'",
+		),
+	)
+
+	doc.SetField(
+		"repository",
+		"the repository to store the image into",
+		docs.Summary(
+			"defaults to the name of the application, the repository is created",
+			"by the registry on the first push",
+		),
+	)
+
+	doc.SetField(
+		"tag",
+		"the docker tag to assign to the new image",
+	)
+
+	doc.SetField(
+		"managed_identity_client_id",
+		"the client id of a user assigned managed identity to authenticate with",
+		docs.Summary(
+			"images are pushed with the service principal set in AZURE_CLIENT_ID and",
+			"AZURE_CLIENT_SECRET, or the managed identity of the host if these are not set",
+		),
+	)
+
+	doc.SetField(
+		"replications",
+		"the locations the registry is replicated to",
+		docs.Summary(
+			"replications are created in any of these locations that do not have one,",
+			"geo-replication requires a registry using the Premium SKU",
+		),
+	)
+
+	doc.SetField(
+		"resource_group",
+		"the resource group of the registry",
+		docs.Summary("this is required when replications are set"),
+	)
+
+	doc.SetField(
+		"subscription_id",
+		"the Azure subscription id of the registry",
+		docs.Summary("if not set uses the environment variable AZURE_SUBSCRIPTION_ID"),
+		docs.EnvVar("AZURE_SUBSCRIPTION_ID"),
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Registry     = (*Registry)(nil)
+	_ component.Configurable = (*Registry)(nil)
+	_ component.Documented   = (*Registry)(nil)
+)
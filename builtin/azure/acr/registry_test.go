@@ -0,0 +1,22 @@
+package acr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginServer(t *testing.T) {
+	require.Equal(t, "myregistry.azurecr.io", loginServer("MyRegistry"))
+	require.Equal(t, "myregistry.azurecr.cn", loginServer("myregistry.azurecr.cn"))
+}
+
+func TestRegistryName(t *testing.T) {
+	require.Equal(t, "myregistry", registryName("myregistry.azurecr.io"))
+	require.Equal(t, "myregistry", registryName("myregistry"))
+}
+
+func TestNormalizeLocation(t *testing.T) {
+	require.Equal(t, "westus", normalizeLocation("West US"))
+	require.Equal(t, "eastus", normalizeLocation("eastus"))
+}
@@ -0,0 +1,73 @@
+package acr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerregistry/mgmt/2019-05-01/containerregistry"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/hashicorp/go-hclog"
+)
+
+// ensureReplications creates a replication of the registry in each of the
+// given locations which does not already have one. Geo-replication is only
+// available for registries using the Premium SKU.
+func ensureReplications(
+	ctx context.Context,
+	log hclog.Logger,
+	subscriptionID, resourceGroup, registryName string,
+	locations []string,
+) error {
+	authorizer, err := auth.NewAuthorizerFromEnvironment()
+	if err != nil {
+		return fmt.Errorf("Unable to create authorizer: %s", err)
+	}
+
+	c := containerregistry.NewReplicationsClient(subscriptionID)
+	c.Authorizer = authorizer
+
+	existing := map[string]bool{}
+	page, err := c.List(ctx, resourceGroup, registryName)
+	if err != nil {
+		return fmt.Errorf("Unable to list registry replications: %s", err)
+	}
+	for page.NotDone() {
+		for _, r := range page.Values() {
+			if r.Location != nil {
+				existing[normalizeLocation(*r.Location)] = true
+			}
+		}
+
+		if err := page.NextWithContext(ctx); err != nil {
+			return fmt.Errorf("Unable to list registry replications: %s", err)
+		}
+	}
+
+	for _, loc := range locations {
+		loc := normalizeLocation(loc)
+		if existing[loc] {
+			continue
+		}
+
+		log.Info("Creating registry replication", "location", loc)
+		future, err := c.Create(ctx, resourceGroup, registryName, loc, containerregistry.Replication{
+			Location: &loc,
+		})
+		if err != nil {
+			return fmt.Errorf("Unable to create registry replication in %s: %s", loc, err)
+		}
+
+		if err := future.WaitForCompletionRef(ctx, c.Client); err != nil {
+			return fmt.Errorf("Error waiting for registry replication in %s to complete: %s", loc, err)
+		}
+	}
+
+	return nil
+}
+
+// normalizeLocation converts a display location like "West US" to the
+// location name "westus" returned by the API.
+func normalizeLocation(loc string) string {
+	return strings.ToLower(strings.ReplaceAll(loc, " ", ""))
+}
@@ -0,0 +1,14 @@
+// Package appservice contains components for deploying to Azure App Service.
+package appservice
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk"
+)
+
+//go:generate protoc -I ../../../.. --go_opt=plugins=grpc --go_out=../../../.. waypoint/builtin/azure/appservice/plugin.proto
+
+// Options are the SDK options to use for instantiation for
+// the Azure App Service plugin.
+var Options = []sdk.Option{
+	sdk.WithComponents(&Platform{}, &Releaser{}),
+}
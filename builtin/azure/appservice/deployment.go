@@ -0,0 +1,203 @@
+package appservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2015-11-01/subscriptions"
+	"github.com/Azure/azure-sdk-for-go/services/web/mgmt/2019-08-01/web"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+)
+
+var _ component.Deployment = (*Deployment)(nil)
+
+func (d *Deployment) appsClient(auth autorest.Authorizer) *web.AppsClient {
+	appsClient := web.NewAppsClient(d.WebApp.SubscriptionId)
+	appsClient.Authorizer = auth
+
+	return &appsClient
+}
+
+// authenticate sets up the authorizer, first using the environment and then
+// falling back to the credentials of the Azure CLI.
+func (d *Deployment) authenticate(ctx context.Context) (autorest.Authorizer, error) {
+	authorizer, err := auth.NewAuthorizerFromEnvironment()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create authorizer: %s", err)
+	}
+
+	// we need to timeout this request as this request never fails when we have
+	// invalid credentials
+	timeoutContext, cf := context.WithTimeout(ctx, 15*time.Second)
+	defer cf()
+
+	if err := d.checkAuth(timeoutContext, authorizer); err == nil {
+		return authorizer, nil
+	}
+
+	timeoutContext, cf2 := context.WithTimeout(ctx, 15*time.Second)
+	defer cf2()
+
+	// the environment variable auth has failed fall back to CLI auth
+	authorizer, err = auth.NewAuthorizerFromCLI()
+	if err != nil {
+		return authorizer, err
+	}
+
+	if err := d.checkAuth(timeoutContext, authorizer); err == nil {
+		return authorizer, nil
+	}
+
+	return nil, fmt.Errorf(
+		"Unable to authenticate with the Azure API, ensure you have your credentials set as environment variables, " +
+			"or you have logged in using the 'az' command line tool",
+	)
+}
+
+// checkAuth validates the authorizer by listing the locations for the subscription.
+func (d *Deployment) checkAuth(ctx context.Context, auth autorest.Authorizer) error {
+	subscriptionClient := subscriptions.NewClient()
+	subscriptionClient.Authorizer = auth
+
+	_, err := subscriptionClient.ListLocations(ctx, d.WebApp.SubscriptionId)
+	return err
+}
+
+// getSite returns the web app, or the given slot of the web app if slot is not empty.
+func (d *Deployment) getSite(ctx context.Context, auth autorest.Authorizer, slot string) (web.Site, error) {
+	c := d.appsClient(auth)
+
+	if slot == "" {
+		return c.Get(ctx, d.WebApp.ResourceGroup, d.WebApp.Name)
+	}
+
+	return c.GetSlot(ctx, d.WebApp.ResourceGroup, d.WebApp.Name, slot)
+}
+
+// createSite creates the web app, or the given slot of the web app if slot is not empty.
+func (d *Deployment) createSite(ctx context.Context, auth autorest.Authorizer, slot string, site web.Site) (web.Site, error) {
+	c := d.appsClient(auth)
+
+	if slot == "" {
+		future, err := c.CreateOrUpdate(ctx, d.WebApp.ResourceGroup, d.WebApp.Name, site)
+		if err != nil {
+			return web.Site{}, fmt.Errorf("Unable to create web app: %s", err)
+		}
+
+		if err := future.WaitForCompletionRef(ctx, c.Client); err != nil {
+			return web.Site{}, fmt.Errorf("Error waiting for web app creation to complete: %s", err)
+		}
+
+		return future.Result(*c)
+	}
+
+	future, err := c.CreateOrUpdateSlot(ctx, d.WebApp.ResourceGroup, d.WebApp.Name, site, slot)
+	if err != nil {
+		return web.Site{}, fmt.Errorf("Unable to create deployment slot: %s", err)
+	}
+
+	if err := future.WaitForCompletionRef(ctx, c.Client); err != nil {
+		return web.Site{}, fmt.Errorf("Error waiting for deployment slot creation to complete: %s", err)
+	}
+
+	return future.Result(*c)
+}
+
+// updateContainer sets the container image and app settings of the web app, or
+// of the given slot of the web app if slot is not empty. Changing the site
+// configuration restarts the app with the new image.
+func (d *Deployment) updateContainer(
+	ctx context.Context,
+	auth autorest.Authorizer,
+	slot string,
+	linuxFxVersion string,
+	settings map[string]*string,
+) error {
+	c := d.appsClient(auth)
+
+	config := web.SiteConfigResource{
+		SiteConfig: &web.SiteConfig{
+			LinuxFxVersion: &linuxFxVersion,
+		},
+	}
+	appSettings := web.StringDictionary{
+		Properties: settings,
+	}
+
+	if slot == "" {
+		if _, err := c.UpdateApplicationSettings(ctx, d.WebApp.ResourceGroup, d.WebApp.Name, appSettings); err != nil {
+			return fmt.Errorf("Unable to update app settings: %s", err)
+		}
+
+		if _, err := c.UpdateConfiguration(ctx, d.WebApp.ResourceGroup, d.WebApp.Name, config); err != nil {
+			return fmt.Errorf("Unable to update container configuration: %s", err)
+		}
+
+		return nil
+	}
+
+	if _, err := c.UpdateApplicationSettingsSlot(ctx, d.WebApp.ResourceGroup, d.WebApp.Name, appSettings, slot); err != nil {
+		return fmt.Errorf("Unable to update app settings: %s", err)
+	}
+
+	if _, err := c.UpdateConfigurationSlot(ctx, d.WebApp.ResourceGroup, d.WebApp.Name, config, slot); err != nil {
+		return fmt.Errorf("Unable to update container configuration: %s", err)
+	}
+
+	return nil
+}
+
+// getLinuxFxVersion returns the image that the web app, or the given slot
+// of the web app if slot is not empty, is configured to run.
+func (d *Deployment) getLinuxFxVersion(ctx context.Context, auth autorest.Authorizer, slot string) (string, error) {
+	c := d.appsClient(auth)
+
+	var config web.SiteConfigResource
+	var err error
+	if slot == "" {
+		config, err = c.GetConfiguration(ctx, d.WebApp.ResourceGroup, d.WebApp.Name)
+	} else {
+		config, err = c.GetConfigurationSlot(ctx, d.WebApp.ResourceGroup, d.WebApp.Name, slot)
+	}
+	if err != nil {
+		return "", err
+	}
+	if config.SiteConfig == nil {
+		return "", nil
+	}
+
+	return to.String(config.LinuxFxVersion), nil
+}
+
+// swapWithProduction swaps the deployment slot into production and waits
+// for the swap to complete.
+func (d *Deployment) swapWithProduction(ctx context.Context, auth autorest.Authorizer) error {
+	c := d.appsClient(auth)
+
+	future, err := c.SwapSlotWithProduction(ctx, d.WebApp.ResourceGroup, d.WebApp.Name, web.CsmSlotEntity{
+		TargetSlot:   to.StringPtr(d.Slot),
+		PreserveVnet: to.BoolPtr(true),
+	})
+	if err != nil {
+		return fmt.Errorf("Unable to swap slot with production: %s", err)
+	}
+
+	if err := future.WaitForCompletionRef(ctx, c.Client); err != nil {
+		return fmt.Errorf("Error waiting for slot swap to complete: %s", err)
+	}
+
+	return nil
+}
+
+// siteURL returns the URL of a web app or slot.
+func siteURL(site web.Site) string {
+	if site.SiteProperties == nil || to.String(site.DefaultHostName) == "" {
+		return ""
+	}
+
+	return "https://" + to.String(site.DefaultHostName)
+}
@@ -0,0 +1,420 @@
+package appservice
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/datadir"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/builtin/docker"
+
+	"github.com/Azure/azure-sdk-for-go/services/web/mgmt/2019-08-01/web"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// Platform is the Platform implementation for Azure App Service.
+type Platform struct {
+	config Config
+}
+
+// Config implements Configurable
+func (p *Platform) Config() (interface{}, error) {
+	return &p.config, nil
+}
+
+// DeployFunc implements component.Platform
+func (p *Platform) DeployFunc() interface{} {
+	return p.Deploy
+}
+
+// DefaultReleaserFunc implements component.PlatformReleaser
+func (p *Platform) DefaultReleaserFunc() interface{} {
+	return func() *Releaser { return &Releaser{} }
+}
+
+// ConfigSet is called after a configuration has been decoded
+// we can use this to validate the config
+func (p *Platform) ConfigSet(config interface{}) error {
+	c, ok := config.(*Config)
+	if !ok {
+		// this should never happen
+		return fmt.Errorf("Invalid configuration, expected *appservice.Config, got %s", reflect.TypeOf(config))
+	}
+
+	return validateConfig(*c)
+}
+
+// Deploy deploys an image to an Azure Web App. If a slot is configured the
+// image is deployed to the slot and made active by the releaser swapping the
+// slot with production, otherwise the image is deployed to production.
+func (p *Platform) Deploy(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	img *docker.Image,
+	dir *datadir.Component,
+	deployConfig *component.DeploymentConfig,
+	ui terminal.UI,
+) (*Deployment, error) {
+
+	// if there is no subscription id in the deployment config try and fetch it from the environment
+	if p.config.SubscriptionID == "" {
+		p.config.SubscriptionID = os.Getenv("AZURE_SUBSCRIPTION_ID")
+	}
+
+	// if we do not have a subscription id, return an error
+	if p.config.SubscriptionID == "" {
+		return nil, status.Error(
+			codes.FailedPrecondition,
+			"Please set either your Azure subscription ID in the deployment config, or set the environment variable 'AZURE_SUBSCRIPTION_ID'",
+		)
+	}
+
+	name := p.config.Name
+	if name == "" {
+		name = src.App
+	}
+
+	deployment := &Deployment{
+		WebApp: &Deployment_WebApp{
+			ResourceGroup:  p.config.ResourceGroup,
+			Name:           name,
+			SubscriptionId: p.config.SubscriptionID,
+		},
+		Slot: p.config.Slot,
+	}
+
+	auth, err := deployment.authenticate(ctx)
+	if err != nil {
+		return nil, status.Error(
+			codes.Unauthenticated,
+			err.Error(),
+		)
+	}
+
+	// We'll update the user in real time
+	st := ui.Status()
+	defer st.Close()
+
+	// Web Apps for Containers run the image set in the linuxFxVersion of the
+	// site configuration.
+	linuxFxVersion := "DOCKER|" + img.Name()
+	deployment.LinuxFxVersion = linuxFxVersion
+
+	log.Info("Checking if web app already exists", "webapp", name)
+	st.Update("Checking if web app is already created")
+	site, err := deployment.getSite(ctx, auth, "")
+	if err != nil {
+		if !isNotFound(site.Response) {
+			return nil, status.Errorf(codes.Internal, "Unable to check if web app already exists: %s", err)
+		}
+
+		if p.config.AppServicePlan == "" {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"The web app '%s' does not exist, set 'app_service_plan' to create it",
+				name,
+			)
+		}
+
+		location := p.config.Location
+		if location == "" {
+			location = "eastus"
+		}
+
+		log.Info("Web app not found, creating new web app", "webapp", name)
+		st.Update("Creating new web app")
+		site, err = deployment.createSite(ctx, auth, "", web.Site{
+			Location: &location,
+			Kind:     to.StringPtr("app,linux,container"),
+			SiteProperties: &web.SiteProperties{
+				ServerFarmID: to.StringPtr(planID(p.config.SubscriptionID, p.config.ResourceGroup, p.config.AppServicePlan)),
+				Reserved:     to.BoolPtr(true),
+				HTTPSOnly:    to.BoolPtr(true),
+				SiteConfig: &web.SiteConfig{
+					LinuxFxVersion: &linuxFxVersion,
+				},
+			},
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Unable to create web app: %s", err)
+		}
+	}
+
+	// Deploying to a slot requires the slot to exist, slots are created in the
+	// same App Service plan and location as the production web app.
+	if deployment.Slot != "" {
+		log.Info("Checking if deployment slot already exists", "slot", deployment.Slot)
+		st.Update("Checking if deployment slot is already created")
+		slotSite, err := deployment.getSite(ctx, auth, deployment.Slot)
+		if err != nil {
+			if !isNotFound(slotSite.Response) {
+				return nil, status.Errorf(codes.Internal, "Unable to check if deployment slot already exists: %s", err)
+			}
+
+			if site.SiteProperties == nil {
+				return nil, status.Errorf(codes.Internal, "Unable to read the App Service plan of web app '%s'", name)
+			}
+
+			log.Info("Deployment slot not found, creating new deployment slot", "slot", deployment.Slot)
+			st.Update("Creating new deployment slot")
+			site, err = deployment.createSite(ctx, auth, deployment.Slot, web.Site{
+				Location: site.Location,
+				Kind:     site.Kind,
+				SiteProperties: &web.SiteProperties{
+					ServerFarmID: site.ServerFarmID,
+					Reserved:     site.Reserved,
+					HTTPSOnly:    site.HTTPSOnly,
+					SiteConfig: &web.SiteConfig{
+						LinuxFxVersion: &linuxFxVersion,
+					},
+				},
+			})
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "Unable to create deployment slot: %s", err)
+			}
+		}
+	}
+
+	// Build the app settings. The app settings of the web app are replaced
+	// on every deploy so the configuration remains the source of truth.
+	settings := map[string]*string{
+		// containers should not mount the shared App Service storage
+		// unless explicitly asked to
+		"WEBSITES_ENABLE_APP_SERVICE_STORAGE": to.StringPtr("false"),
+	}
+
+	for k, v := range deployConfig.Env() {
+		settings[k] = to.StringPtr(v)
+	}
+
+	for k, v := range p.config.AppSettings {
+		settings[k] = to.StringPtr(v)
+	}
+
+	// if we have a port we need to tell App Service where to route traffic
+	// and set the PORT env var so that the CEB binary can direct traffic to the
+	// correct service
+	if p.config.Port > 0 {
+		port := strconv.Itoa(p.config.Port)
+		settings["WEBSITES_PORT"] = to.StringPtr(port)
+		settings["PORT"] = to.StringPtr(port)
+	}
+
+	// do we need to add registry credentials for auth?
+	registryUser := os.Getenv("REGISTRY_USERNAME")
+	registryPass := os.Getenv("REGISTRY_PASSWORD")
+	if registryUser != "" && registryPass != "" {
+		settings["DOCKER_REGISTRY_SERVER_URL"] = to.StringPtr("https://" + parseDockerServer(img.Image))
+		settings["DOCKER_REGISTRY_SERVER_USERNAME"] = to.StringPtr(registryUser)
+		settings["DOCKER_REGISTRY_SERVER_PASSWORD"] = to.StringPtr(registryPass)
+	}
+
+	if deployment.Slot != "" {
+		st.Update(fmt.Sprintf("Deploying image to slot %q", deployment.Slot))
+	} else {
+		st.Update("Deploying image to web app")
+	}
+
+	log.Info("Updating container configuration", "image", img.Name(), "slot", deployment.Slot)
+	if err := deployment.updateContainer(ctx, auth, deployment.Slot, linuxFxVersion, settings); err != nil {
+		return nil, status.Errorf(codes.Internal, "Unable to deploy image to web app: %s", err)
+	}
+
+	// Fetch the site we deployed to so we have the host name
+	site, err = deployment.getSite(ctx, auth, deployment.Slot)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Unable to fetch web app: %s", err)
+	}
+
+	id, err := component.Id()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Unable to generate ID for the deployment: %s", err)
+	}
+
+	deployment.Id = id
+	deployment.Url = siteURL(site)
+
+	if deployment.Url != "" {
+		// Clear the status before we print the url
+		st.Close()
+		ui.Output("\nURL: %s", deployment.Url, terminal.WithSuccessStyle())
+	}
+
+	// If we have tracing enabled we just dump the full site as we know it
+	// in case we need to look up what the raw value is.
+	if log.IsTrace() {
+		bs, err := site.MarshalJSON()
+		if err != nil {
+			return nil, status.Errorf(codes.Aborted, err.Error())
+		}
+
+		log.Trace("web app JSON", "json", base64.StdEncoding.EncodeToString(bs))
+	}
+
+	return deployment, nil
+}
+
+// planID returns the resource id for an App Service plan, plan can either be
+// the name of a plan in the resource group or a full resource id.
+func planID(subscriptionID, resourceGroup, plan string) string {
+	if strings.HasPrefix(plan, "/subscriptions/") {
+		return plan
+	}
+
+	return fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Web/serverfarms/%s",
+		subscriptionID, resourceGroup, plan,
+	)
+}
+
+// isNotFound returns true if the response is a 404 from the Azure API.
+func isNotFound(resp autorest.Response) bool {
+	return resp.Response != nil && resp.StatusCode == http.StatusNotFound
+}
+
+// Config is the configuration structure for the Platform.
+// In addition to HCL defined configuration the following environment variables
+// are also valid
+// AZURE_SUBSCRIPTION_ID = Subscription ID for your Azure account [required]
+// REGISTRY_USERNAME = Username for container registry, required when using a private registry
+// REGISTRY_PASSWORD = Password for container registry, required when using a private registry
+type Config struct {
+	// ResourceGroup is the resource group of the web app.
+	ResourceGroup string `hcl:"resource_group,attr"`
+
+	// Name of the web app, defaults to the name of the application
+	Name string `hcl:"name,optional"`
+
+	// Azure subscription id, if not set plugin will attempt to use the environment variable
+	// AZURE_SUBSCRIPTION_ID
+	SubscriptionID string `hcl:"subscription_id,optional"`
+
+	// AppServicePlan is the name or resource id of the App Service plan used
+	// to create the web app if it does not exist.
+	AppServicePlan string `hcl:"app_service_plan,optional"`
+
+	// Location to create the web app in if it does not exist, this must be
+	// the location of the App Service plan.
+	Location string `hcl:"location,optional"`
+
+	// Slot is the deployment slot to deploy to. The releaser swaps the slot
+	// with production. If not set the image is deployed to production.
+	Slot string `hcl:"slot,optional" validate:"ne=production"`
+
+	// Port the application is listening on.
+	Port int `hcl:"port,optional" validate:"gte=0,lte=65535"`
+
+	// AppSettings are set on the web app and exposed to the application as
+	// environment variables. Most configuration should use the waypoint
+	// config commands.
+	AppSettings map[string]string `hcl:"app_settings,optional"`
+}
+
+func (p *Platform) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&Config{}))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description("Deploy a container to Azure App Service")
+
+	doc.Example(`
+deploy {
+  use "azure-app-service" {
+    resource_group   = "resource-group-name"
+    app_service_plan = "plan-name"
+    location         = "westus"
+    slot             = "staging"
+    port             = 8080
+
+    app_settings = {
+      "LOG_LEVEL" = "info"
+    }
+  }
+}
+
+`)
+
+	doc.SetField(
+		"resource_group",
+		"the resource group of the web app",
+	)
+
+	doc.SetField(
+		"name",
+		"the name of the web app",
+		docs.Summary("defaults to the name of the application"),
+	)
+
+	doc.SetField(
+		"subscription_id",
+		"the Azure subscription id",
+		docs.Summary("if not set uses the environment variable AZURE_SUBSCRIPTION_ID"),
+		docs.EnvVar("AZURE_SUBSCRIPTION_ID"),
+	)
+
+	doc.SetField(
+		"app_service_plan",
+		"the name or resource id of the App Service plan",
+		docs.Summary(
+			"this is only used to create the web app when it does not exist,",
+			"the plan must be a Linux plan",
+		),
+	)
+
+	doc.SetField(
+		"location",
+		"the location to create the web app in",
+		docs.Summary("this must be the location of the App Service plan"),
+		docs.Default("eastus"),
+	)
+
+	doc.SetField(
+		"slot",
+		"the deployment slot to deploy to",
+		docs.Summary(
+			"the slot is created if it does not exist and is swapped with production",
+			"on release. If not set the image is deployed directly to production",
+		),
+	)
+
+	doc.SetField(
+		"port",
+		"the port the application is listening on",
+	)
+
+	doc.SetField(
+		"app_settings",
+		"app settings to set on the web app",
+		docs.Summary(
+			"app settings are exposed to the application as environment variables.",
+			"The app settings of the web app are replaced on every deploy",
+		),
+	)
+
+	doc.Input("docker.Image")
+	doc.Output("appservice.Deployment")
+
+	return doc, nil
+}
+
+var (
+	_ component.Platform         = (*Platform)(nil)
+	_ component.PlatformReleaser = (*Platform)(nil)
+	_ component.Configurable     = (*Platform)(nil)
+	_ component.Documented       = (*Platform)(nil)
+)
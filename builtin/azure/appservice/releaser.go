@@ -0,0 +1,143 @@
+package appservice
+
+import (
+	"context"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+// Releaser is the ReleaseManager implementation for Azure App Service.
+type Releaser struct {
+	config ReleaserConfig
+}
+
+// Config implements Configurable
+func (r *Releaser) Config() (interface{}, error) {
+	return &r.config, nil
+}
+
+// ReleaseFunc implements component.ReleaseManager
+func (r *Releaser) ReleaseFunc() interface{} {
+	return r.Release
+}
+
+// Release swaps the deployment slot of the target deployment with production.
+func (r *Releaser) Release(
+	ctx context.Context,
+	log hclog.Logger,
+	ui terminal.UI,
+	target *Deployment,
+) (*Release, error) {
+	auth, err := target.authenticate(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	// We'll update the user in real time
+	st := ui.Status()
+	defer st.Close()
+
+	// Deployments without a slot are already serving production traffic
+	if target.Slot != "" {
+		swap, err := r.needsSwap(ctx, log, auth, target)
+		if err != nil {
+			return nil, err
+		}
+
+		if swap {
+			log.Info("Swapping slot with production", "slot", target.Slot)
+			st.Update("Swapping slot " + target.Slot + " with production")
+			if err := target.swapWithProduction(ctx, auth); err != nil {
+				return nil, status.Errorf(codes.Aborted, "Unable to release deployment: %s", err)
+			}
+		} else {
+			log.Info("Production already runs the deployment, not swapping", "slot", target.Slot)
+			st.Update("Production already runs this deployment")
+		}
+	}
+
+	st.Update("Getting web app information...")
+	site, err := target.getSite(ctx, auth, "")
+	if err != nil {
+		return nil, status.Errorf(codes.Aborted, "Unable to fetch web app information from Azure: %s", err)
+	}
+
+	return &Release{
+		Url: siteURL(site),
+	}, nil
+}
+
+// needsSwap returns true if the slot of the target must be swapped with
+// production to release it. Swapping is its own inverse, so releasing the
+// same deployment twice would put the previous image back in production.
+// The images of production and of the slot are checked first, and this
+// returns an error if neither runs the deployment, such as when the slot
+// was deployed to again since.
+func (r *Releaser) needsSwap(
+	ctx context.Context,
+	log hclog.Logger,
+	auth autorest.Authorizer,
+	target *Deployment,
+) (bool, error) {
+	// Deployments from before the image was recorded can't be checked
+	if target.LinuxFxVersion == "" {
+		return true, nil
+	}
+
+	prod, err := target.getLinuxFxVersion(ctx, auth, "")
+	if err != nil {
+		return false, status.Errorf(codes.Aborted,
+			"Unable to fetch the configuration of production: %s", err)
+	}
+	if prod == target.LinuxFxVersion {
+		return false, nil
+	}
+
+	slot, err := target.getLinuxFxVersion(ctx, auth, target.Slot)
+	if err != nil {
+		return false, status.Errorf(codes.Aborted,
+			"Unable to fetch the configuration of slot %q: %s", target.Slot, err)
+	}
+	if slot != target.LinuxFxVersion {
+		log.Warn("Deployment isn't in the slot or in production",
+			"slot", target.Slot, "expected", target.LinuxFxVersion, "slot_image", slot, "production_image", prod)
+		return false, status.Errorf(codes.FailedPrecondition,
+			"Slot %q runs %q rather than the image of this deployment, %q. "+
+				"Deploy again to release it.", target.Slot, slot, target.LinuxFxVersion)
+	}
+
+	return true, nil
+}
+
+// ReleaserConfig is the configuration structure for the Releaser.
+type ReleaserConfig struct{}
+
+func (r *Releaser) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&ReleaserConfig{}))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description("Swaps the deployment slot with production to make deployments active. If production already runs the deployment, the slot isn't swapped again")
+
+	doc.Input("appservice.Deployment")
+	doc.Output("appservice.Release")
+
+	return doc, nil
+}
+
+func (r *Release) URL() string { return r.Url }
+
+var (
+	_ component.ReleaseManager = (*Releaser)(nil)
+	_ component.Configurable   = (*Releaser)(nil)
+	_ component.Documented     = (*Releaser)(nil)
+	_ component.Release        = (*Release)(nil)
+)
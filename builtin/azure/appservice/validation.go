@@ -0,0 +1,49 @@
+package appservice
+
+import (
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+	"github.com/go-playground/validator"
+)
+
+var errInvalidSlot = fmt.Errorf("The production slot can not be used as a deployment slot, unset 'slot' to deploy directly to production\n")
+var errInvalidPort = fmt.Errorf("Invalid value for port, the port must be between 1 and 65535\n")
+
+func validateConfig(c Config) error {
+	v := validator.New()
+
+	err := v.Struct(c)
+
+	if err != nil {
+		errorMessage := ""
+		for _, err := range err.(validator.ValidationErrors) {
+			switch err.Namespace() {
+			case "Config.Slot":
+				errorMessage += errInvalidSlot.Error()
+			case "Config.Port":
+				errorMessage += errInvalidPort.Error()
+			}
+		}
+
+		return fmt.Errorf(errorMessage)
+	}
+
+	return nil
+}
+
+// return the server component from an image name
+func parseDockerServer(image string) string {
+	n, err := reference.ParseNamed(image)
+	if err != nil {
+		return "docker.io"
+	}
+
+	d := reference.Domain(n)
+	if d == "" {
+		// no domain, convention is main docker repo
+		d = "docker.io"
+	}
+
+	return d
+}
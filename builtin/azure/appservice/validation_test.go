@@ -0,0 +1,65 @@
+package appservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := map[string]struct {
+		input Config
+		valid bool
+	}{
+		"Valid slot": {
+			Config{
+				ResourceGroup: "rg",
+				Slot:          "staging",
+				Port:          8080,
+			},
+			true,
+		},
+		"Valid without slot": {
+			Config{
+				ResourceGroup: "rg",
+			},
+			true,
+		},
+		"Error when slot is production": {
+			Config{
+				ResourceGroup: "rg",
+				Slot:          "production",
+			},
+			false,
+		},
+		"Error when port is out of range": {
+			Config{
+				ResourceGroup: "rg",
+				Port:          70000,
+			},
+			false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateConfig(tc.input)
+
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestPlanID(t *testing.T) {
+	require.Equal(t,
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan",
+		planID("sub", "rg", "plan"),
+	)
+
+	id := "/subscriptions/other/resourceGroups/other/providers/Microsoft.Web/serverfarms/plan"
+	require.Equal(t, id, planID("sub", "rg", id))
+}
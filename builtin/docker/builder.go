@@ -1,7 +1,6 @@
 package docker
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -16,7 +15,6 @@ import (
 	"github.com/hashicorp/waypoint-plugin-sdk/component"
 	"github.com/hashicorp/waypoint-plugin-sdk/docs"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
-	"github.com/hashicorp/waypoint/internal/assets"
 	"github.com/hashicorp/waypoint/internal/pkg/epinject"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -37,6 +35,10 @@ type BuilderConfig struct {
 	// Control whether or not to inject the entrypoint binary into the resulting image
 	DisableCEB bool `hcl:"disable_entrypoint,optional"`
 
+	// EntrypointMode is how the entrypoint is added to the image, see
+	// epinject.Mode. This defaults to injecting it.
+	EntrypointMode string `hcl:"entrypoint_mode,optional"`
+
 	// Controls whether or not the image should be build with buildkit or docker v1
 	UseBuildKit bool `hcl:"buildkit,optional"`
 
@@ -74,6 +76,19 @@ build {
 		),
 	)
 
+	doc.SetField(
+		"entrypoint_mode",
+		"how the entrypoint binary is added to the image",
+		docs.Summary(
+			"\"inject\" adds the binary and makes it the entrypoint of the image,",
+			"\"binary\" only adds the binary at /waypoint-entrypoint for images",
+			"that start it themselves, and \"disable\" doesn't add it. The binary",
+			"is chosen for the platform of the image. Images for platforms that",
+			"the entrypoint isn't available for, such as Windows, must use \"disable\"",
+		),
+		docs.Default("inject"),
+	)
+
 	doc.SetField(
 		"buildkit",
 		"if set, use the buildkit builder from Docker",
@@ -87,17 +102,24 @@ func (b *Builder) Config() (interface{}, error) {
 	return &b.config, nil
 }
 
-// Build
+// Build builds the image with Docker. The labels of the operation are set
+// as labels of the image.
 func (b *Builder) Build(
 	ctx context.Context,
 	ui terminal.UI,
 	src *component.Source,
+	labels *component.LabelSet,
 ) (*Image, error) {
 	stdout, _, err := ui.OutputWriters()
 	if err != nil {
 		return nil, err
 	}
 
+	mode, err := epinject.ParseMode(b.config.EntrypointMode, b.config.DisableCEB)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
 	sg := ui.StepGroup()
 	step := sg.Add("Initializing Docker client...")
 	defer step.Abort()
@@ -154,10 +176,16 @@ func (b *Builder) Build(
 	step.Done()
 	step = sg.Add("Building image...")
 
+	var imageLabels map[string]string
+	if labels != nil {
+		imageLabels = labels.Labels
+	}
+
 	resp, err := cli.ImageBuild(ctx, buildCtx, types.ImageBuildOptions{
 		Version:    ver,
 		Dockerfile: relDockerfile,
 		Tags:       []string{result.Name()},
+		Labels:     imageLabels,
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "error building image: %s", err)
@@ -176,34 +204,10 @@ func (b *Builder) Build(
 
 	step.Done()
 
-	if !b.config.DisableCEB {
+	if mode != epinject.ModeDisable {
 		step = sg.Add("Injecting Waypoint Entrypoint...")
 
-		asset, err := assets.Asset("ceb/ceb")
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
-		}
-
-		assetInfo, err := assets.AssetInfo("ceb/ceb")
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
-		}
-
-		_, err = epinject.AlterEntrypoint(ctx, result.Name(), func(cur []string) (*epinject.NewEntrypoint, error) {
-			ep := &epinject.NewEntrypoint{
-				Entrypoint: append([]string{"/waypoint-entrypoint"}, cur...),
-				InjectFiles: map[string]epinject.InjectFile{
-					"/waypoint-entrypoint": epinject.InjectFile{
-						Reader: bytes.NewReader(asset),
-						Info:   assetInfo,
-					},
-				},
-			}
-
-			return ep, nil
-		})
-
-		if err != nil {
+		if _, err := epinject.Inject(ctx, result.Name(), mode); err != nil {
 			return nil, status.Errorf(codes.Internal, "unable to set modify Docker entrypoint: %s", err)
 		}
 
@@ -2,7 +2,7 @@ package docker
 
 import (
 	"context"
-
+	"encoding/json"
 	"fmt"
 
 	"github.com/docker/docker/api/types"
@@ -19,6 +19,8 @@ import (
 	"github.com/hashicorp/waypoint-plugin-sdk/component"
 	"github.com/hashicorp/waypoint-plugin-sdk/docs"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/pkg/finalcontext"
+	"github.com/hashicorp/waypoint/internal/pkg/resource"
 )
 
 const (
@@ -46,6 +48,21 @@ func (p *Platform) DestroyFunc() interface{} {
 	return p.Destroy
 }
 
+// PlanFunc implements plugin.Planner
+func (p *Platform) PlanFunc() interface{} {
+	return p.Plan
+}
+
+// ScaleFunc implements plugin.Scaler
+func (p *Platform) ScaleFunc() interface{} {
+	return p.Scale
+}
+
+// ResourcesFunc implements plugin.DeclaredResources
+func (p *Platform) ResourcesFunc() interface{} {
+	return p.Resources
+}
+
 // ValidateAuthFunc implements component.Authenticator
 func (p *Platform) ValidateAuthFunc() interface{} {
 	return p.ValidateAuth
@@ -72,6 +89,7 @@ func (p *Platform) Deploy(
 	job *component.JobInfo,
 	img *Image,
 	deployConfig *component.DeploymentConfig,
+	labels *component.LabelSet,
 	ui terminal.UI,
 ) (*Deployment, error) {
 	// We'll update the user in real time
@@ -129,10 +147,98 @@ func (p *Platform) Deploy(
 
 	s = sg.Add("Creating new container")
 
+	cfg, hostconfig, netconfig, err := p.containerSpec(src, job, img, deployConfig, labels, result.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := src.App + "-" + id
+
+	cr, err := cli.ContainerCreate(ctx, cfg, hostconfig, netconfig, name)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to create Docker container: %s", err)
+	}
+
+	s.Update("Starting container")
+	err = cli.ContainerStart(ctx, cr.ID, types.ContainerStartOptions{})
+	if err != nil {
+		// Remove the container so that a failed or canceled deploy doesn't
+		// leave it behind. ctx may be canceled already.
+		rmCtx, cancel := finalcontext.Context(log)
+		defer cancel()
+		if rerr := cli.ContainerRemove(rmCtx, cr.ID, types.ContainerRemoveOptions{Force: true}); rerr != nil {
+			log.Warn("error removing the container that didn't start", "err", rerr)
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, status.Errorf(codes.Internal, "unable to start Docker container: %s", err)
+	}
+	s.Done()
+
+	s = sg.Add("App deployed as container: " + name)
+	s.Done()
+
+	result.Container = cr.ID
+
+	return &result, nil
+}
+
+// Plan returns the configuration of the container that Deploy would
+// create for the image, without connecting to Docker.
+func (p *Platform) Plan(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	job *component.JobInfo,
+	img *Image,
+	deployConfig *component.DeploymentConfig,
+	labels *component.LabelSet,
+) (string, error) {
+	id, err := component.Id()
+	if err != nil {
+		return "", err
+	}
+
+	cfg, hostconfig, netconfig, err := p.containerSpec(src, job, img, deployConfig, labels, id)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"Name":             src.App + "-" + id,
+		"Config":           cfg,
+		"HostConfig":       hostconfig,
+		"NetworkingConfig": netconfig,
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// containerSpec returns the configuration of the container for the image.
+// This is shared by Deploy and Plan. The labels of the operation are set
+// as labels of the container.
+func (p *Platform) containerSpec(
+	src *component.Source,
+	job *component.JobInfo,
+	img *Image,
+	deployConfig *component.DeploymentConfig,
+	labels *component.LabelSet,
+	id string,
+) (*container.Config, *container.HostConfig, *network.NetworkingConfig, error) {
+	if p.config.ServicePort == 0 {
+		p.config.ServicePort = 3000
+	}
+
 	port := fmt.Sprint(p.config.ServicePort)
 	np, err := nat.NewPort("tcp", port)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	cfg := container.Config{
@@ -176,32 +282,19 @@ func (p *Platform) Deploy(
 		cfg.Env = append(cfg.Env, k+"="+v)
 	}
 
-	cfg.Labels = map[string]string{
-		labelId:     result.Id,
-		"app":       src.App,
-		"workspace": job.Workspace,
-	}
-
-	name := src.App + "-" + id
-
-	cr, err := cli.ContainerCreate(ctx, &cfg, &hostconfig, &netconfig, name)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "unable to create Docker container: %s", err)
-	}
-
-	s.Update("Starting container")
-	err = cli.ContainerStart(ctx, cr.ID, types.ContainerStartOptions{})
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "unable to start Docker container: %s", err)
+	// The labels that Waypoint uses to find the container take precedence
+	// over the labels of the operation.
+	cfg.Labels = map[string]string{}
+	if labels != nil {
+		for k, v := range labels.Labels {
+			cfg.Labels[k] = v
+		}
 	}
-	s.Done()
-
-	s = sg.Add("App deployed as container: " + name)
-	s.Done()
+	cfg.Labels[labelId] = id
+	cfg.Labels["app"] = src.App
+	cfg.Labels["workspace"] = job.Workspace
 
-	result.Container = cr.ID
-
-	return &result, nil
+	return &cfg, &hostconfig, &netconfig, nil
 }
 
 // Destroy deletes the K8S deployment.
@@ -235,6 +328,77 @@ func (p *Platform) Destroy(
 	})
 }
 
+// Scale starts or stops the container of the deployment. A deployment is a
+// single container, so it can only be scaled to 0 or 1 replicas.
+func (p *Platform) Scale(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+	replicas int32,
+	ui terminal.UI,
+) error {
+	if replicas > 1 {
+		return status.Errorf(codes.InvalidArgument,
+			"docker deployments run a single container and can only be scaled to 0 or 1 replicas")
+	}
+
+	cli, err := client.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return err
+	}
+
+	cli.NegotiateAPIVersion(ctx)
+
+	// We'll update the user in real time
+	st := ui.Status()
+	defer st.Close()
+
+	if replicas == 0 {
+		st.Update("Stopping container...")
+		if err := cli.ContainerStop(ctx, deployment.Container, nil); err != nil {
+			return err
+		}
+
+		st.Step(terminal.StatusOK, "Container stopped")
+		return nil
+	}
+
+	st.Update("Starting container...")
+	if err := cli.ContainerStart(ctx, deployment.Container, types.ContainerStartOptions{}); err != nil {
+		return err
+	}
+
+	st.Step(terminal.StatusOK, "Container started")
+	return nil
+}
+
+// Resources returns the container of the deployment and its state.
+func (p *Platform) Resources(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+) ([]*resource.Resource, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	cli.NegotiateAPIVersion(ctx)
+
+	result := &resource.Resource{Type: "container", Name: deployment.Container}
+	info, err := cli.ContainerInspect(ctx, deployment.Container)
+	switch {
+	case client.IsErrNotFound(err):
+		result.State = "missing"
+	case err != nil:
+		return nil, err
+	default:
+		result.State = info.State.Status
+	}
+
+	return []*resource.Resource{result}, nil
+}
+
 // Config is the configuration structure for the Platform.
 type PlatformConfig struct {
 	// The command to run in the container. This is an array of arguments
@@ -22,8 +22,10 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 // Image is the artifact type for the registry.
 type Image struct {
-	Image                string   `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
-	Tag                  string   `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Tag   string `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	// digest is the digest of the image in the registry, if it was pushed.
+	Digest               string   `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -68,6 +70,13 @@ func (m *Image) GetTag() string {
 	return ""
 }
 
+func (m *Image) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
 type Deployment struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
@@ -173,17 +182,17 @@ func init() {
 }
 
 var fileDescriptor_2440d536b1a2bd07 = []byte{
-	// 189 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x29, 0x4f, 0xac, 0x2c,
-	0xc8, 0xcf, 0xcc, 0x2b, 0xd1, 0x4f, 0x2a, 0xcd, 0xcc, 0x29, 0xc9, 0xcc, 0xd3, 0x4f, 0xc9, 0x4f,
-	0xce, 0x4e, 0x2d, 0xd2, 0x2f, 0xc8, 0x29, 0x4d, 0xcf, 0xcc, 0xd3, 0x2b, 0x28, 0xca, 0x2f, 0xc9,
-	0x17, 0x62, 0x83, 0x08, 0x2a, 0xe9, 0x73, 0xb1, 0x7a, 0xe6, 0x26, 0xa6, 0xa7, 0x0a, 0x89, 0x70,
-	0xb1, 0x66, 0x82, 0x18, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x10, 0x8e, 0x90, 0x00, 0x17,
-	0x73, 0x49, 0x62, 0xba, 0x04, 0x13, 0x58, 0x0c, 0xc4, 0x54, 0xf2, 0xe3, 0xe2, 0x72, 0x49, 0x2d,
-	0xc8, 0xc9, 0xaf, 0xcc, 0x4d, 0xcd, 0x2b, 0x11, 0xe2, 0xe3, 0x62, 0xca, 0x4c, 0x81, 0x6a, 0x61,
-	0xca, 0x4c, 0x11, 0x12, 0xe2, 0x62, 0xc9, 0x4b, 0xcc, 0x4d, 0x85, 0x6a, 0x00, 0xb3, 0x85, 0x64,
-	0xb8, 0x38, 0x93, 0xf3, 0xf3, 0x4a, 0x12, 0x33, 0xf3, 0x52, 0x8b, 0x24, 0x98, 0xc1, 0x12, 0x08,
-	0x01, 0x25, 0x69, 0x2e, 0xf6, 0xa0, 0xd4, 0x9c, 0xd4, 0xc4, 0x62, 0xb0, 0x65, 0xa5, 0x45, 0x39,
-	0x50, 0xd3, 0x40, 0x4c, 0x27, 0xc9, 0x28, 0x71, 0x1c, 0xbe, 0x49, 0x62, 0x03, 0xfb, 0xc3, 0x18,
-	0x10, 0x00, 0x00, 0xff, 0xff, 0xe4, 0x69, 0xcd, 0x4f, 0xef, 0x00, 0x00, 0x00,
+	// 192 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x75, 0x8f, 0x31, 0x0b, 0xc2, 0x30,
+	0x10, 0x46, 0x69, 0xb5, 0x95, 0xde, 0x20, 0x12, 0x44, 0x2b, 0x3a, 0x48, 0x71, 0x70, 0xb2, 0x83,
+	0xff, 0x40, 0x04, 0x71, 0x71, 0xe8, 0xe8, 0x96, 0xb6, 0x47, 0x08, 0xa6, 0x49, 0x48, 0x53, 0xa4,
+	0xff, 0x5e, 0x9b, 0x06, 0x9c, 0xdc, 0xde, 0xf7, 0x86, 0xc7, 0x1d, 0x1c, 0xde, 0xb4, 0xd7, 0x8a,
+	0x4b, 0x9b, 0x97, 0x1d, 0x17, 0x96, 0xcb, 0xbc, 0x56, 0xd5, 0x0b, 0x4d, 0xae, 0x45, 0xc7, 0xb8,
+	0x3c, 0x69, 0xa3, 0xac, 0x22, 0xf1, 0x28, 0xb3, 0x1b, 0x44, 0xf7, 0x86, 0x32, 0x24, 0x4b, 0x88,
+	0xf8, 0x00, 0x69, 0xb0, 0x0f, 0x8e, 0x49, 0x31, 0x0e, 0xb2, 0x80, 0x89, 0xa5, 0x2c, 0x0d, 0x9d,
+	0x1b, 0x90, 0xac, 0x20, 0xae, 0x39, 0xc3, 0xd6, 0xa6, 0x13, 0x27, 0xfd, 0xca, 0x1e, 0x00, 0x57,
+	0xd4, 0x42, 0xf5, 0x0d, 0x4a, 0x4b, 0xe6, 0x10, 0xf2, 0xda, 0xa7, 0xbe, 0x44, 0x08, 0x4c, 0x25,
+	0x6d, 0xd0, 0x87, 0x1c, 0x93, 0x1d, 0x24, 0x95, 0x92, 0x96, 0x72, 0x89, 0xc6, 0xc7, 0x7e, 0x22,
+	0xdb, 0xc2, 0xac, 0x40, 0x81, 0xb4, 0x75, 0x47, 0x74, 0x46, 0xf8, 0xda, 0x80, 0x97, 0xcd, 0x73,
+	0xfd, 0xe7, 0xcb, 0x32, 0x76, 0xff, 0x9d, 0x3f, 0xf5, 0x11, 0xaf, 0x4c, 0x07, 0x01, 0x00, 0x00,
 }
@@ -21,7 +21,6 @@ import (
 	"github.com/hashicorp/waypoint-plugin-sdk/docs"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	wpdocker "github.com/hashicorp/waypoint/builtin/docker"
-	"github.com/hashicorp/waypoint/internal/assets"
 	"github.com/hashicorp/waypoint/internal/pkg/epinject"
 )
 
@@ -44,6 +43,10 @@ type BuilderConfig struct {
 	// Control whether or not to inject the entrypoint binary into the resulting image
 	DisableCEB bool `hcl:"disable_entrypoint,optional"`
 
+	// EntrypointMode is how the entrypoint is added to the image, see
+	// epinject.Mode. This defaults to injecting it.
+	EntrypointMode string `hcl:"entrypoint_mode,optional"`
+
 	// The docker specific encoded authentication string to use to talk to the registry.
 	EncodedAuth string `hcl:"encoded_auth,optional"`
 }
@@ -57,8 +60,9 @@ func (b *Builder) Documentation() (*docs.Documentation, error) {
 	doc.Description(`
 Use an existing, pre-built Docker image
 
-This builder will automatically inject the Waypoint entrypoint. You
-can disable this with the "disable_entrypoint" configuration.
+This builder will automatically inject the Waypoint entrypoint for the
+platform of the image. You can change this with the "entrypoint_mode"
+configuration.
 
 If you wish to rename or retag an image, use this along with the
 "docker" registry option which will rename/retag the image and then
@@ -101,6 +105,20 @@ build {
 		),
 	)
 
+	doc.SetField(
+		"entrypoint_mode",
+		"how the entrypoint binary is added to the image",
+		docs.Summary(
+			"\"inject\" adds the binary and makes it the entrypoint of the image.",
+			"\"binary\" only adds the binary at /waypoint-entrypoint, for images",
+			"whose own entrypoint starts it. \"disable\" leaves the image as it is,",
+			"which is required for images of platforms the entrypoint doesn't",
+			"support, such as Windows. The entrypoint supports linux/amd64 and",
+			"linux/arm64 images",
+		),
+		docs.Default("inject"),
+	)
+
 	doc.SetField(
 		"encoded_auth",
 		"the authentication information to log into the docker repository",
@@ -131,6 +149,11 @@ func (b *Builder) Build(
 		return nil, err
 	}
 
+	mode, err := epinject.ParseMode(b.config.EntrypointMode, b.config.DisableCEB)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
 	sg := ui.StepGroup()
 	step := sg.Add("Initializing Docker client...")
 	defer step.Abort()
@@ -211,34 +234,10 @@ func (b *Builder) Build(
 
 	step.Done()
 
-	if !b.config.DisableCEB {
+	if mode != epinject.ModeDisable {
 		step = sg.Add("Injecting Waypoint Entrypoint...")
 
-		asset, err := assets.Asset("ceb/ceb")
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
-		}
-
-		assetInfo, err := assets.AssetInfo("ceb/ceb")
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
-		}
-
-		_, err = epinject.AlterEntrypoint(ctx, result.Name(), func(cur []string) (*epinject.NewEntrypoint, error) {
-			ep := &epinject.NewEntrypoint{
-				Entrypoint: append([]string{"/waypoint-entrypoint"}, cur...),
-				InjectFiles: map[string]epinject.InjectFile{
-					"/waypoint-entrypoint": epinject.InjectFile{
-						Reader: bytes.NewReader(asset),
-						Info:   assetInfo,
-					},
-				},
-			}
-
-			return ep, nil
-		})
-
-		if err != nil {
+		if _, err := epinject.Inject(ctx, result.Name(), mode); err != nil {
 			return nil, status.Errorf(codes.Internal, "unable to modify Docker entrypoint: %s", err)
 		}
 
@@ -130,7 +130,15 @@ func (r *Registry) Push(
 		termFd = f.Fd()
 	}
 
-	err = jsonmessage.DisplayJSONMessagesStream(responseBody, step.TermOutput(), termFd, true, nil)
+	// The digest of the pushed image is sent as the aux message.
+	aux := func(msg jsonmessage.JSONMessage) {
+		var result types.PushResult
+		if err := json.Unmarshal(*msg.Aux, &result); err == nil && result.Digest != "" {
+			target.Digest = result.Digest
+		}
+	}
+
+	err = jsonmessage.DisplayJSONMessagesStream(responseBody, step.TermOutput(), termFd, true, aux)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "unable to stream Docker logs to terminal: %s", err)
 	}
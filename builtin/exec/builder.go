@@ -0,0 +1,290 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+// Builder is the Builder implementation for exec. It runs any command to
+// build the application and captures the declared output as the artifact.
+type Builder struct {
+	config BuilderConfig
+}
+
+// Config implements Configurable
+func (b *Builder) Config() (interface{}, error) {
+	return &b.config, nil
+}
+
+// ConfigSet is called after a configuration has been decoded
+// we can use this to validate the config
+func (b *Builder) ConfigSet(config interface{}) error {
+	c, ok := config.(*BuilderConfig)
+	if !ok {
+		// this should never happen
+		return fmt.Errorf("Invalid configuration, expected *exec.BuilderConfig, got %s", reflect.TypeOf(config))
+	}
+
+	if len(c.Command) == 0 {
+		return fmt.Errorf("command must not be empty")
+	}
+
+	if c.Output == nil {
+		return fmt.Errorf("an output stanza declaring the path or image produced by the command is required")
+	}
+
+	if (c.Output.Path == "") == (c.Output.Image == "") {
+		return fmt.Errorf("exactly one of output.path or output.image must be set")
+	}
+
+	if c.Output.Tag != "" && c.Output.Image == "" {
+		return fmt.Errorf("output.tag can only be set with output.image")
+	}
+
+	return nil
+}
+
+// BuildFunc implements component.Builder
+func (b *Builder) BuildFunc() interface{} {
+	return b.Build
+}
+
+// Build runs the command and returns the declared output.
+func (b *Builder) Build(
+	ctx context.Context,
+	log hclog.Logger,
+	ui terminal.UI,
+	src *component.Source,
+) (*Artifact, error) {
+	args := make([]string, len(b.config.Command))
+	copy(args, b.config.Command)
+
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	s := sg.Add("Executing command: %s", strings.Join(args, " "))
+	defer func() {
+		if s != nil {
+			s.Abort()
+		}
+	}()
+
+	// Ensure we're executing a binary
+	if !filepath.IsAbs(args[0]) {
+		log.Debug("command is not absolute, will look up on PATH", "command", args[0])
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			log.Info("failed to find command on PATH", "command", args[0])
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"unable to find command %q: %s", args[0], err)
+		}
+
+		args[0] = path
+	}
+
+	dir := src.Path
+	if b.config.Dir != "" {
+		dir = b.config.Dir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(src.Path, dir)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stdout = s.TermOutput()
+	cmd.Stderr = cmd.Stdout
+	cmd.Env = os.Environ()
+	for k, v := range b.config.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	log.Debug("running build command", "args", args, "dir", dir)
+	if err := cmd.Run(); err != nil {
+		return nil, status.Errorf(codes.Aborted, "build command failed: %s", err)
+	}
+
+	s.Done()
+
+	// An image is referenced by name only, the command may have pushed it
+	// to a registry rather than storing it in the local Docker daemon.
+	if out := b.config.Output; out.Image != "" {
+		tag := out.Tag
+		if tag == "" {
+			tag = "latest"
+		}
+
+		s = nil
+		return &Artifact{
+			Image: out.Image,
+			Tag:   tag,
+		}, nil
+	}
+
+	s = sg.Add("Checking build output...")
+
+	path := b.config.Output.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(src.Path, path)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"build output %q was not created by the command: %s", b.config.Output.Path, err)
+	}
+
+	s.Update("Build output: %s", path)
+	s.Done()
+	s = nil
+
+	return &Artifact{
+		Path: path,
+	}, nil
+}
+
+// BuilderConfig is the configuration structure for the Builder.
+type BuilderConfig struct {
+	// The command to execute to build the application.
+	Command []string `hcl:"command,attr"`
+
+	// Dir is the working directory to set when executing the command.
+	// This will default to the path to the application in the Waypoint
+	// configuration.
+	Dir string `hcl:"dir,optional"`
+
+	// Env are additional environment variables to set for the command.
+	Env map[string]string `hcl:"env,optional"`
+
+	// Output declares the artifact created by the command.
+	Output *ConfigOutput `hcl:"output,block"`
+}
+
+type ConfigOutput struct {
+	// Path is the file or directory created by the command, relative to
+	// the application.
+	Path string `hcl:"path,optional"`
+
+	// Image is the name of the Docker image created by the command.
+	Image string `hcl:"image,optional"`
+
+	// Tag is the tag of the Docker image, defaults to "latest".
+	Tag string `hcl:"tag,optional"`
+}
+
+func (b *Builder) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&BuilderConfig{}))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description(`
+Execute any command to perform a build.
+
+This plugin lets you use any pre-existing build tool such as "make", "gradle"
+or "npm" for the build step of Waypoint. The command is run in the application
+directory and the "output" stanza declares what the command creates: either a
+path on disk or the name of a Docker image.
+
+A path output can be used with components that accept files, such as the
+"files" registry. An image output can be used with any component that accepts
+a Docker image, such as the "docker" registry or the "kubernetes" platform.
+Both can be used with the "exec" platform, which exposes the output as the
+".Input.Path" or ".Input.DockerImageFull" template variables.
+`)
+
+	doc.Example(`
+build {
+  use "exec" {
+    command = ["gradle", "assemble"]
+
+    output {
+      path = "build/libs"
+    }
+  }
+}
+`)
+
+	doc.Example(`
+build {
+  use "exec" {
+    command = ["make", "image"]
+
+    output {
+      image = "myapp"
+      tag   = "latest"
+    }
+  }
+}
+`)
+
+	doc.Input("component.Source")
+	doc.Output("exec.Artifact")
+
+	doc.SetField(
+		"command",
+		"The command to execute for the build as a list of strings.",
+	)
+
+	doc.SetField(
+		"dir",
+		"The working directory to use while executing the command.",
+		docs.Summary(
+			"This will default to the path to the application. A relative",
+			"path is relative to the application.",
+		),
+	)
+
+	doc.SetField(
+		"env",
+		"Additional environment variables to set for the command.",
+	)
+
+	doc.SetField(
+		"output",
+		"A stanza that declares the artifact created by the command.",
+		docs.Summary(
+			"Exactly one of path or image must be set.",
+		),
+	)
+
+	doc.SetField(
+		"output.path",
+		"The file or directory created by the command.",
+		docs.Summary(
+			"A relative path is relative to the application. The build fails",
+			"if the path does not exist after the command completes.",
+		),
+	)
+
+	doc.SetField(
+		"output.image",
+		"The name of the Docker image created by the command.",
+	)
+
+	doc.SetField(
+		"output.tag",
+		"The tag of the Docker image created by the command.",
+		docs.Default("latest"),
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Builder      = (*Builder)(nil)
+	_ component.Configurable = (*Builder)(nil)
+	_ component.Documented   = (*Builder)(nil)
+)
@@ -0,0 +1,83 @@
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderConfigSet(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Config BuilderConfig
+		Err    string
+	}{
+		{
+			"path output",
+			BuilderConfig{
+				Command: []string{"make"},
+				Output:  &ConfigOutput{Path: "dist"},
+			},
+			"",
+		},
+
+		{
+			"image output",
+			BuilderConfig{
+				Command: []string{"make", "image"},
+				Output:  &ConfigOutput{Image: "myapp", Tag: "v1"},
+			},
+			"",
+		},
+
+		{
+			"no command",
+			BuilderConfig{
+				Output: &ConfigOutput{Path: "dist"},
+			},
+			"command must not be empty",
+		},
+
+		{
+			"no output",
+			BuilderConfig{
+				Command: []string{"make"},
+			},
+			"output stanza",
+		},
+
+		{
+			"path and image",
+			BuilderConfig{
+				Command: []string{"make"},
+				Output:  &ConfigOutput{Path: "dist", Image: "myapp"},
+			},
+			"exactly one",
+		},
+
+		{
+			"tag without image",
+			BuilderConfig{
+				Command: []string{"make"},
+				Output:  &ConfigOutput{Path: "dist", Tag: "v1"},
+			},
+			"output.tag",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			var b Builder
+			err := b.ConfigSet(&tt.Config)
+			if tt.Err == "" {
+				require.NoError(err)
+				return
+			}
+
+			require.Error(err)
+			require.Contains(err.Error(), tt.Err)
+		})
+	}
+}
@@ -8,6 +8,11 @@ import (
 
 // Options are the SDK options to use for instantiation.
 var Options = []sdk.Option{
-	sdk.WithComponents(&Platform{}),
-	sdk.WithMappers(DockerImageMapper),
+	sdk.WithComponents(&Builder{}, &Platform{}),
+	sdk.WithMappers(
+		DockerImageMapper,
+		ArtifactMapper,
+		ArtifactImageMapper,
+		ArtifactFilesMapper,
+	),
 }
@@ -1,7 +1,10 @@
 package exec
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/waypoint/builtin/docker"
+	"github.com/hashicorp/waypoint/builtin/files"
 )
 
 // DockerImageMapper maps a docker.Image to our Input structure.
@@ -26,3 +29,38 @@ func DockerImageMapper(src *docker.Image) *Input {
 		},
 	}
 }
+
+// ArtifactMapper maps the Artifact of the exec builder to our Input structure.
+func ArtifactMapper(src *Artifact) *Input {
+	if src.Image != "" {
+		return DockerImageMapper(&docker.Image{Image: src.Image, Tag: src.Tag})
+	}
+
+	return &Input{
+		Data: map[string]*Input_Value{
+			"Path": &Input_Value{
+				Value: &Input_Value_Text{
+					Text: src.Path,
+				},
+			},
+		},
+	}
+}
+
+// ArtifactImageMapper maps an Artifact with an image output to a docker.Image.
+func ArtifactImageMapper(src *Artifact) (*docker.Image, error) {
+	if src.Image == "" {
+		return nil, fmt.Errorf("the exec build output is a path, not a Docker image")
+	}
+
+	return &docker.Image{Image: src.Image, Tag: src.Tag}, nil
+}
+
+// ArtifactFilesMapper maps an Artifact with a path output to files.Files.
+func ArtifactFilesMapper(src *Artifact) (*files.Files, error) {
+	if src.Path == "" {
+		return nil, fmt.Errorf("the exec build output is a Docker image, not a path")
+	}
+
+	return &files.Files{Path: src.Path}, nil
+}
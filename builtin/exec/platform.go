@@ -306,6 +306,13 @@ are available:
 
   - ".Input.DockerImageTag" (string) - The Docker image tag, such as "latest".
 
+#### Path Input
+
+If the build step is the "exec" builder with a path output, the following
+template variables are available:
+
+  - ".Input.Path" (string) - The absolute path to the build output.
+
 `)
 
 	doc.Example(`
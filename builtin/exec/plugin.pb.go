@@ -111,6 +111,73 @@ func (*Deployment) Descriptor() ([]byte, []int) {
 	return file_waypoint_builtin_exec_plugin_proto_rawDescGZIP(), []int{1}
 }
 
+// Artifact is the output of the exec builder. Only one of path or image
+// is set, depending on the declared output of the command.
+type Artifact struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// path is the absolute path to the output directory
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// image and tag are the Docker image created by the command
+	Image string `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Tag   string `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+}
+
+func (x *Artifact) Reset() {
+	*x = Artifact{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_waypoint_builtin_exec_plugin_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Artifact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Artifact) ProtoMessage() {}
+
+func (x *Artifact) ProtoReflect() protoreflect.Message {
+	mi := &file_waypoint_builtin_exec_plugin_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Artifact.ProtoReflect.Descriptor instead.
+func (*Artifact) Descriptor() ([]byte, []int) {
+	return file_waypoint_builtin_exec_plugin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Artifact) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Artifact) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Artifact) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
 type Input_Value struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -124,7 +191,7 @@ type Input_Value struct {
 func (x *Input_Value) Reset() {
 	*x = Input_Value{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_waypoint_builtin_exec_plugin_proto_msgTypes[3]
+		mi := &file_waypoint_builtin_exec_plugin_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -137,7 +204,7 @@ func (x *Input_Value) String() string {
 func (*Input_Value) ProtoMessage() {}
 
 func (x *Input_Value) ProtoReflect() protoreflect.Message {
-	mi := &file_waypoint_builtin_exec_plugin_proto_msgTypes[3]
+	mi := &file_waypoint_builtin_exec_plugin_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -194,9 +261,13 @@ var file_waypoint_builtin_exec_plugin_proto_rawDesc = []byte{
 	0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61,
 	0x6c, 0x75, 0x65, 0x22, 0x0c, 0x0a, 0x0a, 0x44, 0x65, 0x70, 0x6c, 0x6f, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x42, 0x17, 0x5a, 0x15, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2f, 0x62, 0x75,
-	0x69, 0x6c, 0x74, 0x69, 0x6e, 0x2f, 0x65, 0x78, 0x65, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x74, 0x22, 0x46, 0x0a, 0x08, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x42, 0x17, 0x5a, 0x15, 0x77, 0x61, 0x79,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x74, 0x69, 0x6e, 0x2f, 0x65, 0x78,
+	0x65, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -211,16 +282,17 @@ func file_waypoint_builtin_exec_plugin_proto_rawDescGZIP() []byte {
 	return file_waypoint_builtin_exec_plugin_proto_rawDescData
 }
 
-var file_waypoint_builtin_exec_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_waypoint_builtin_exec_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_waypoint_builtin_exec_plugin_proto_goTypes = []interface{}{
 	(*Input)(nil),       // 0: exec.Input
 	(*Deployment)(nil),  // 1: exec.Deployment
-	nil,                 // 2: exec.Input.DataEntry
-	(*Input_Value)(nil), // 3: exec.Input.Value
+	(*Artifact)(nil),    // 2: exec.Artifact
+	nil,                 // 3: exec.Input.DataEntry
+	(*Input_Value)(nil), // 4: exec.Input.Value
 }
 var file_waypoint_builtin_exec_plugin_proto_depIdxs = []int32{
-	2, // 0: exec.Input.data:type_name -> exec.Input.DataEntry
-	3, // 1: exec.Input.DataEntry.value:type_name -> exec.Input.Value
+	3, // 0: exec.Input.data:type_name -> exec.Input.DataEntry
+	4, // 1: exec.Input.DataEntry.value:type_name -> exec.Input.Value
 	2, // [2:2] is the sub-list for method output_type
 	2, // [2:2] is the sub-list for method input_type
 	2, // [2:2] is the sub-list for extension type_name
@@ -258,7 +330,19 @@ func file_waypoint_builtin_exec_plugin_proto_init() {
 				return nil
 			}
 		}
-		file_waypoint_builtin_exec_plugin_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+		file_waypoint_builtin_exec_plugin_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Artifact); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_waypoint_builtin_exec_plugin_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Input_Value); i {
 			case 0:
 				return &v.state
@@ -271,7 +355,7 @@ func file_waypoint_builtin_exec_plugin_proto_init() {
 			}
 		}
 	}
-	file_waypoint_builtin_exec_plugin_proto_msgTypes[3].OneofWrappers = []interface{}{
+	file_waypoint_builtin_exec_plugin_proto_msgTypes[4].OneofWrappers = []interface{}{
 		(*Input_Value_Text)(nil),
 	}
 	type x struct{}
@@ -280,7 +364,7 @@ func file_waypoint_builtin_exec_plugin_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_waypoint_builtin_exec_plugin_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
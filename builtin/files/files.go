@@ -1,4 +1,5 @@
-// Package files contains a component for validating local files.
+// Package files contains components for validating local files and
+// copying them to remote hosts.
 package files
 
 import (
@@ -10,5 +11,5 @@ import (
 // Options are the SDK options to use for instantiation for
 // the Files plugin.
 var Options = []sdk.Option{
-	sdk.WithComponents(&Builder{}, &Registry{}),
+	sdk.WithComponents(&Builder{}, &Registry{}, &Platform{}),
 }
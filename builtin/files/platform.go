@@ -0,0 +1,397 @@
+package files
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+const (
+	// releasesDir is the directory within the path that releases are
+	// stored in, currentLink points to the active release within it.
+	releasesDir = "releases"
+	currentLink = "current"
+
+	defaultKeep = 5
+)
+
+// Platform copies files to remote hosts with rsync over SSH. Every deploy
+// is stored in a new release directory and a symlink is switched to point
+// at it, so a failed deploy can be rolled back to the previous release.
+type Platform struct {
+	config PlatformConfig
+}
+
+// Config implements Configurable
+func (p *Platform) Config() (interface{}, error) {
+	return &p.config, nil
+}
+
+// ConfigSet is called after a configuration has been decoded
+// we can use this to validate the config
+func (p *Platform) ConfigSet(config interface{}) error {
+	c, ok := config.(*PlatformConfig)
+	if !ok {
+		// this should never happen
+		return fmt.Errorf("Invalid configuration, expected *files.PlatformConfig, got %s", reflect.TypeOf(config))
+	}
+
+	if len(c.Hosts) == 0 {
+		return fmt.Errorf("at least one host must be set")
+	}
+
+	if c.Path == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+
+	if c.Keep != 0 && c.Keep < 2 {
+		return fmt.Errorf("keep must be at least 2 so the previous release is available for rollback")
+	}
+
+	return nil
+}
+
+// DeployFunc implements component.Platform
+func (p *Platform) DeployFunc() interface{} {
+	return p.Deploy
+}
+
+// DestroyFunc implements component.Destroyer
+func (p *Platform) DestroyFunc() interface{} {
+	return p.Destroy
+}
+
+// PlatformConfig is the configuration structure for the Platform.
+type PlatformConfig struct {
+	// Hosts are the addresses of the hosts to copy the files to, with an
+	// optional port such as "example.com:2222".
+	Hosts []string `hcl:"hosts,attr"`
+
+	// User to connect as, defaults to the ssh configuration.
+	User string `hcl:"user,optional"`
+
+	// KeyFile is the path to the private key to authenticate with.
+	KeyFile string `hcl:"key_file,optional"`
+
+	// Path is the directory on the hosts releases are stored in.
+	Path string `hcl:"path,attr"`
+
+	// PostCommands are run on each host after the release is activated.
+	PostCommands []string `hcl:"post_commands,optional"`
+
+	// Keep is the number of releases to keep on each host.
+	Keep int `hcl:"keep,optional"`
+}
+
+// Deploy copies the files to every host and activates the new release.
+func (p *Platform) Deploy(
+	ctx context.Context,
+	log hclog.Logger,
+	files *Files,
+	ui terminal.UI,
+) (*Deployment, error) {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	// Releases are named by time so they sort in the order they were
+	// deployed.
+	release := time.Now().UTC().Format("20060102150405")
+
+	result := &Deployment{
+		Release: release,
+		Path:    p.config.Path,
+	}
+
+	// deployed tracks the hosts that have been switched to the new release
+	// so they can all be rolled back if a later host fails.
+	var deployed []*Deployment_Host
+
+	for _, addr := range p.config.Hosts {
+		r := p.remote(log, addr)
+		host := &Deployment_Host{Address: addr}
+
+		s := sg.Add("Copying files to %s...", addr)
+		err := p.deployHost(ctx, r, host, files.Path, release, s.TermOutput())
+		if err == nil {
+			s.Done()
+			deployed = append(deployed, host)
+			result.Hosts = append(result.Hosts, host)
+			continue
+		}
+
+		// The host may have been switched to the new release before the
+		// post commands failed, so it is rolled back with the others.
+		deployed = append(deployed, host)
+		s.Abort()
+
+		p.rollback(ctx, log, sg, deployed)
+		return nil, status.Errorf(codes.Aborted, "error deploying to %s: %s", addr, err)
+	}
+
+	if keep := p.keep(); keep > 0 {
+		for _, host := range result.Hosts {
+			r := p.remote(log, host.Address)
+			if err := r.run(ctx, ioutil.Discard, pruneScript(p.config.Path, keep)); err != nil {
+				// Old releases are only removed to save space, the deploy
+				// itself succeeded.
+				log.Warn("error removing old releases", "address", host.Address, "err", err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// deployHost copies the files to a new release on the host, activates it
+// and runs the post commands. host.Previous is set as soon as it is known.
+func (p *Platform) deployHost(
+	ctx context.Context,
+	r *remote,
+	host *Deployment_Host,
+	src, release string,
+	out io.Writer,
+) error {
+	// The current release may not exist yet on a new host
+	if current, err := r.output(ctx, "readlink "+shellQuote(path.Join(p.config.Path, currentLink))); err == nil {
+		host.Previous = path.Base(current)
+	}
+
+	dst := path.Join(p.config.Path, releasesDir, release)
+	if err := r.run(ctx, out, "mkdir -p "+shellQuote(dst)); err != nil {
+		return fmt.Errorf("unable to create release directory: %s", err)
+	}
+
+	// Unchanged files are hard linked from the previous release, the link
+	// destination is relative to the release directory.
+	var linkDest string
+	if host.Previous != "" {
+		linkDest = path.Join("..", host.Previous)
+	}
+
+	if err := r.sync(ctx, out, src, dst, linkDest); err != nil {
+		return fmt.Errorf("unable to copy files: %s", err)
+	}
+
+	if err := r.run(ctx, out, activateScript(p.config.Path, release)); err != nil {
+		return fmt.Errorf("unable to activate release: %s", err)
+	}
+
+	return p.runPostCommands(ctx, r, p.config.Path, out)
+}
+
+// rollback switches the hosts back to their previous release. Errors are
+// logged since we are already returning the error that caused the rollback.
+func (p *Platform) rollback(ctx context.Context, log hclog.Logger, sg terminal.StepGroup, hosts []*Deployment_Host) {
+	for _, host := range hosts {
+		if host.Previous == "" {
+			continue
+		}
+
+		s := sg.Add("Rolling back %s to release %s...", host.Address, host.Previous)
+		if err := p.activate(ctx, p.remote(log, host.Address), p.config.Path, host.Previous, s.TermOutput()); err != nil {
+			log.Error("error rolling back host", "address", host.Address, "err", err)
+			s.Abort()
+			continue
+		}
+
+		s.Done()
+	}
+}
+
+// Destroy removes the release of the deployment from every host. If the
+// release is the current one, the host is switched back to the previous
+// release first.
+func (p *Platform) Destroy(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+	ui terminal.UI,
+) error {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	base := deployment.Path
+	if base == "" {
+		base = p.config.Path
+	}
+
+	for _, host := range deployment.Hosts {
+		r := p.remote(log, host.Address)
+		s := sg.Add("Removing release %s from %s...", deployment.Release, host.Address)
+
+		current, err := r.output(ctx, "readlink "+shellQuote(path.Join(base, currentLink)))
+		if err == nil && path.Base(current) == deployment.Release {
+			if host.Previous != "" {
+				err = p.activate(ctx, r, base, host.Previous, s.TermOutput())
+			} else {
+				err = r.run(ctx, s.TermOutput(), "rm -f "+shellQuote(path.Join(base, currentLink)))
+			}
+			if err != nil {
+				s.Abort()
+				return status.Errorf(codes.Internal, "error restoring previous release on %s: %s", host.Address, err)
+			}
+		}
+
+		err = r.run(ctx, s.TermOutput(), "rm -rf "+shellQuote(path.Join(base, releasesDir, deployment.Release)))
+		if err != nil {
+			s.Abort()
+			return status.Errorf(codes.Internal, "error removing release from %s: %s", host.Address, err)
+		}
+
+		s.Done()
+	}
+
+	return nil
+}
+
+// activate switches the host to the given release and runs the post commands.
+func (p *Platform) activate(ctx context.Context, r *remote, base, release string, out io.Writer) error {
+	if err := r.run(ctx, out, activateScript(base, release)); err != nil {
+		return err
+	}
+
+	return p.runPostCommands(ctx, r, base, out)
+}
+
+func (p *Platform) runPostCommands(ctx context.Context, r *remote, base string, out io.Writer) error {
+	dir := shellQuote(path.Join(base, currentLink))
+	for _, cmd := range p.config.PostCommands {
+		if err := r.run(ctx, out, "cd "+dir+" && "+cmd); err != nil {
+			return fmt.Errorf("post command %q failed: %s", cmd, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Platform) remote(log hclog.Logger, addr string) *remote {
+	return &remote{
+		Log:     log.Named("ssh"),
+		Address: addr,
+		User:    p.config.User,
+		KeyFile: p.config.KeyFile,
+	}
+}
+
+func (p *Platform) keep() int {
+	if p.config.Keep == 0 {
+		return defaultKeep
+	}
+
+	return p.config.Keep
+}
+
+// activateScript returns the script that points the current symlink at the
+// release. The link is replaced with a rename so it is never missing.
+func activateScript(base, release string) string {
+	return fmt.Sprintf("cd %s && ln -sfn %s .%s && mv -Tf .%s %s",
+		shellQuote(base),
+		shellQuote(path.Join(releasesDir, release)),
+		currentLink, currentLink, currentLink,
+	)
+}
+
+// pruneScript returns the script that removes all but the newest keep
+// releases.
+func pruneScript(base string, keep int) string {
+	return fmt.Sprintf("cd %s && ls -1 | sort -r | tail -n +%s | xargs -r rm -rf",
+		shellQuote(path.Join(base, releasesDir)),
+		strconv.Itoa(keep+1),
+	)
+}
+
+func (p *Platform) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&PlatformConfig{}))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description(`
+Copy files to remote hosts using rsync over SSH
+
+Each deploy is copied to a new directory in "releases" within the path and
+the "current" symlink is switched to point at it. If copying the files or a
+post command fails, every host is switched back to its previous release and
+the post commands are run again. Destroying a deployment that is current
+restores the previous release.
+
+The ssh and rsync binaries must be installed, which means the configuration
+and agent of the user running Waypoint are used to connect to the hosts.
+`)
+
+	doc.Example(`
+deploy {
+  use "files" {
+    hosts = ["web1.example.com", "web2.example.com:2222"]
+    user  = "deploy"
+    path  = "/srv/myapp"
+
+    post_commands = ["sudo systemctl restart myapp"]
+  }
+}
+`)
+
+	doc.Input("files.Files")
+	doc.Output("files.Deployment")
+
+	doc.SetField(
+		"hosts",
+		"the addresses of the hosts to copy the files to",
+		docs.Summary("an address may include the port, such as 'example.com:2222'"),
+	)
+
+	doc.SetField(
+		"user",
+		"the user to connect as",
+		docs.Summary("defaults to the user in the ssh configuration"),
+	)
+
+	doc.SetField(
+		"key_file",
+		"the path to the private key to authenticate with",
+		docs.Summary("defaults to the keys in the ssh configuration and agent"),
+	)
+
+	doc.SetField(
+		"path",
+		"the directory on the hosts to store releases in",
+		docs.Summary("a relative path is relative to the home directory of the user"),
+	)
+
+	doc.SetField(
+		"post_commands",
+		"commands to run on each host after the release is activated",
+		docs.Summary(
+			"the commands are run in the current release directory,",
+			"such as 'sudo systemctl restart myapp'",
+		),
+	)
+
+	doc.SetField(
+		"keep",
+		"the number of releases to keep on each host",
+		docs.Default(strconv.Itoa(defaultKeep)),
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Platform     = (*Platform)(nil)
+	_ component.Configurable = (*Platform)(nil)
+	_ component.Destroyer    = (*Platform)(nil)
+	_ component.Documented   = (*Platform)(nil)
+)
@@ -1,79 +1,310 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.23.0
+// 	protoc        v3.11.4
 // source: waypoint/builtin/files/plugin.proto
 
 package files
 
 import (
-	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
-	math "math"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
 )
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
 
 type Files struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *Files) Reset() {
+	*x = Files{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_waypoint_builtin_files_plugin_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Files) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (m *Files) Reset()         { *m = Files{} }
-func (m *Files) String() string { return proto.CompactTextString(m) }
-func (*Files) ProtoMessage()    {}
+func (*Files) ProtoMessage() {}
+
+func (x *Files) ProtoReflect() protoreflect.Message {
+	mi := &file_waypoint_builtin_files_plugin_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Files.ProtoReflect.Descriptor instead.
 func (*Files) Descriptor() ([]byte, []int) {
-	return fileDescriptor_880353e3a77dc7cc, []int{0}
+	return file_waypoint_builtin_files_plugin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Files) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
 }
 
-func (m *Files) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Files.Unmarshal(m, b)
+// Deployment is a release directory copied to a set of remote hosts.
+type Deployment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// release is the name of the release directory
+	Release string `protobuf:"bytes,1,opt,name=release,proto3" json:"release,omitempty"`
+	// path is the base directory releases are stored in on the hosts
+	Path  string             `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Hosts []*Deployment_Host `protobuf:"bytes,3,rep,name=hosts,proto3" json:"hosts,omitempty"`
 }
-func (m *Files) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Files.Marshal(b, m, deterministic)
+
+func (x *Deployment) Reset() {
+	*x = Deployment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_waypoint_builtin_files_plugin_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *Files) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Files.Merge(m, src)
+
+func (x *Deployment) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *Files) XXX_Size() int {
-	return xxx_messageInfo_Files.Size(m)
+
+func (*Deployment) ProtoMessage() {}
+
+func (x *Deployment) ProtoReflect() protoreflect.Message {
+	mi := &file_waypoint_builtin_files_plugin_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *Files) XXX_DiscardUnknown() {
-	xxx_messageInfo_Files.DiscardUnknown(m)
+
+// Deprecated: Use Deployment.ProtoReflect.Descriptor instead.
+func (*Deployment) Descriptor() ([]byte, []int) {
+	return file_waypoint_builtin_files_plugin_proto_rawDescGZIP(), []int{1}
 }
 
-var xxx_messageInfo_Files proto.InternalMessageInfo
+func (x *Deployment) GetRelease() string {
+	if x != nil {
+		return x.Release
+	}
+	return ""
+}
 
-func (m *Files) GetPath() string {
-	if m != nil {
-		return m.Path
+func (x *Deployment) GetPath() string {
+	if x != nil {
+		return x.Path
 	}
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*Files)(nil), "files.Files")
+func (x *Deployment) GetHosts() []*Deployment_Host {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
 }
 
-func init() {
-	proto.RegisterFile("waypoint/builtin/files/plugin.proto", fileDescriptor_880353e3a77dc7cc)
+type Deployment_Host struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// address of the host, including the port if set
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// previous is the release that was current before this deployment,
+	// empty if this is the first release on the host
+	Previous string `protobuf:"bytes,2,opt,name=previous,proto3" json:"previous,omitempty"`
 }
 
-var fileDescriptor_880353e3a77dc7cc = []byte{
-	// 100 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x2e, 0x4f, 0xac, 0x2c,
-	0xc8, 0xcf, 0xcc, 0x2b, 0xd1, 0x4f, 0x2a, 0xcd, 0xcc, 0x29, 0xc9, 0xcc, 0xd3, 0x4f, 0xcb, 0xcc,
-	0x49, 0x2d, 0xd6, 0x2f, 0xc8, 0x29, 0x4d, 0xcf, 0xcc, 0xd3, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17,
-	0x62, 0x05, 0x8b, 0x29, 0x49, 0x73, 0xb1, 0xba, 0x81, 0x18, 0x42, 0x42, 0x5c, 0x2c, 0x05, 0x89,
-	0x25, 0x19, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x60, 0xb6, 0x93, 0x44, 0x94, 0x18, 0x76,
-	0xa3, 0x92, 0xd8, 0xc0, 0x86, 0x18, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0x46, 0x1a, 0xa3, 0x2a,
-	0x6b, 0x00, 0x00, 0x00,
+func (x *Deployment_Host) Reset() {
+	*x = Deployment_Host{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_waypoint_builtin_files_plugin_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Deployment_Host) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Deployment_Host) ProtoMessage() {}
+
+func (x *Deployment_Host) ProtoReflect() protoreflect.Message {
+	mi := &file_waypoint_builtin_files_plugin_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Deployment_Host.ProtoReflect.Descriptor instead.
+func (*Deployment_Host) Descriptor() ([]byte, []int) {
+	return file_waypoint_builtin_files_plugin_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *Deployment_Host) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Deployment_Host) GetPrevious() string {
+	if x != nil {
+		return x.Previous
+	}
+	return ""
+}
+
+var File_waypoint_builtin_files_plugin_proto protoreflect.FileDescriptor
+
+var file_waypoint_builtin_files_plugin_proto_rawDesc = []byte{
+	0x0a, 0x23, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x74,
+	0x69, 0x6e, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x1b, 0x0a, 0x05,
+	0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0xa6, 0x01, 0x0a, 0x0a, 0x44, 0x65,
+	0x70, 0x6c, 0x6f, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x6c, 0x65,
+	0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x6c, 0x65, 0x61,
+	0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x2c, 0x0a, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x44, 0x65,
+	0x70, 0x6c, 0x6f, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x52, 0x05, 0x68,
+	0x6f, 0x73, 0x74, 0x73, 0x1a, 0x3c, 0x0a, 0x04, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f,
+	0x75, 0x73, 0x42, 0x18, 0x5a, 0x16, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2f, 0x62,
+	0x75, 0x69, 0x6c, 0x74, 0x69, 0x6e, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_waypoint_builtin_files_plugin_proto_rawDescOnce sync.Once
+	file_waypoint_builtin_files_plugin_proto_rawDescData = file_waypoint_builtin_files_plugin_proto_rawDesc
+)
+
+func file_waypoint_builtin_files_plugin_proto_rawDescGZIP() []byte {
+	file_waypoint_builtin_files_plugin_proto_rawDescOnce.Do(func() {
+		file_waypoint_builtin_files_plugin_proto_rawDescData = protoimpl.X.CompressGZIP(file_waypoint_builtin_files_plugin_proto_rawDescData)
+	})
+	return file_waypoint_builtin_files_plugin_proto_rawDescData
+}
+
+var file_waypoint_builtin_files_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_waypoint_builtin_files_plugin_proto_goTypes = []interface{}{
+	(*Files)(nil),           // 0: files.Files
+	(*Deployment)(nil),      // 1: files.Deployment
+	(*Deployment_Host)(nil), // 2: files.Deployment.Host
+}
+var file_waypoint_builtin_files_plugin_proto_depIdxs = []int32{
+	2, // 0: files.Deployment.hosts:type_name -> files.Deployment.Host
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_waypoint_builtin_files_plugin_proto_init() }
+func file_waypoint_builtin_files_plugin_proto_init() {
+	if File_waypoint_builtin_files_plugin_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_waypoint_builtin_files_plugin_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Files); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_waypoint_builtin_files_plugin_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Deployment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_waypoint_builtin_files_plugin_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Deployment_Host); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_waypoint_builtin_files_plugin_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_waypoint_builtin_files_plugin_proto_goTypes,
+		DependencyIndexes: file_waypoint_builtin_files_plugin_proto_depIdxs,
+		MessageInfos:      file_waypoint_builtin_files_plugin_proto_msgTypes,
+	}.Build()
+	File_waypoint_builtin_files_plugin_proto = out.File
+	file_waypoint_builtin_files_plugin_proto_rawDesc = nil
+	file_waypoint_builtin_files_plugin_proto_goTypes = nil
+	file_waypoint_builtin_files_plugin_proto_depIdxs = nil
 }
@@ -0,0 +1,121 @@
+package files
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// remote runs commands on and copies files to a host using the ssh and
+// rsync binaries, so the user's ssh configuration and agent are respected.
+type remote struct {
+	Log     hclog.Logger
+	Address string
+	User    string
+	KeyFile string
+}
+
+// host returns the host and port of the address, the port is empty if the
+// address doesn't include one.
+func (r *remote) host() (string, string) {
+	host, port, err := net.SplitHostPort(r.Address)
+	if err != nil {
+		return r.Address, ""
+	}
+
+	return host, port
+}
+
+// target returns the ssh destination for the host.
+func (r *remote) target() string {
+	host, _ := r.host()
+	if r.User == "" {
+		return host
+	}
+
+	return r.User + "@" + host
+}
+
+// sshArgs returns the arguments used for every ssh connection.
+func (r *remote) sshArgs() []string {
+	// BatchMode ensures we fail rather than hang waiting for a password
+	args := []string{"-o", "BatchMode=yes"}
+	if _, port := r.host(); port != "" {
+		args = append(args, "-p", port)
+	}
+	if r.KeyFile != "" {
+		args = append(args, "-i", r.KeyFile)
+	}
+
+	return args
+}
+
+// run runs the script on the host using the login shell of the user.
+func (r *remote) run(ctx context.Context, out io.Writer, script string) error {
+	args := append(r.sshArgs(), r.target(), script)
+	r.Log.Debug("running remote command", "address", r.Address, "script", script)
+
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// output runs the script on the host and returns its trimmed stdout.
+func (r *remote) output(ctx context.Context, script string) (string, error) {
+	var buf bytes.Buffer
+	args := append(r.sshArgs(), r.target(), script)
+	r.Log.Debug("running remote command", "address", r.Address, "script", script)
+
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	cmd.Stdout = &buf
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// sync copies the contents of the local src directory into the dst
+// directory on the host. If linkDest is set, files that are unchanged
+// from that directory are hard linked rather than copied.
+func (r *remote) sync(ctx context.Context, out io.Writer, src, dst, linkDest string) error {
+	ssh := append([]string{"ssh"}, r.sshArgs()...)
+	for i, v := range ssh {
+		ssh[i] = shellQuote(v)
+	}
+
+	args := []string{
+		"--archive",
+		"--compress",
+		"--delete",
+		"--rsh", strings.Join(ssh, " "),
+	}
+	if linkDest != "" {
+		args = append(args, "--link-dest", linkDest)
+	}
+
+	// The trailing slash copies the contents of src rather than the
+	// directory itself.
+	args = append(args, strings.TrimSuffix(src, "/")+"/", r.target()+":"+dst+"/")
+	r.Log.Debug("running rsync", "address", r.Address, "args", args)
+
+	cmd := exec.CommandContext(ctx, "rsync", args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// shellQuote quotes s so it is interpreted as a single word by a POSIX shell.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=@:") == "" {
+		return s
+	}
+
+	return "'" + strings.Replace(s, "'", `'"'"'`, -1) + "'"
+}
@@ -0,0 +1,49 @@
+package files
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellQuote(t *testing.T) {
+	cases := []string{
+		"simple",
+		"/srv/my app",
+		"it's",
+		"$HOME",
+		"",
+	}
+
+	for _, tt := range cases {
+		t.Run(tt, func(t *testing.T) {
+			require := require.New(t)
+
+			// The shell should give us back exactly what we quoted
+			out, err := exec.Command("sh", "-c", "printf %s "+shellQuote(tt)).Output()
+			require.NoError(err)
+			require.Equal(tt, string(out))
+		})
+	}
+}
+
+func TestRemoteSSHArgs(t *testing.T) {
+	require := require.New(t)
+
+	r := &remote{Address: "example.com:2222", User: "deploy", KeyFile: "/tmp/key"}
+	require.Equal("deploy@example.com", r.target())
+	require.Equal([]string{"-o", "BatchMode=yes", "-p", "2222", "-i", "/tmp/key"}, r.sshArgs())
+
+	r = &remote{Address: "example.com"}
+	require.Equal("example.com", r.target())
+	require.Equal([]string{"-o", "BatchMode=yes"}, r.sshArgs())
+}
+
+func TestActivateScript(t *testing.T) {
+	require := require.New(t)
+	require.Equal(
+		"cd '/srv/my app' && ln -sfn releases/20201015000000 .current && mv -Tf .current current",
+		activateScript("/srv/my app", "20201015000000"),
+	)
+}
@@ -0,0 +1,365 @@
+package golang
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/builtin/docker"
+	"github.com/hashicorp/waypoint/internal/pkg/epinject"
+)
+
+const (
+	// DefaultBaseImage is the image the binary is added to by default.
+	DefaultBaseImage = "gcr.io/distroless/static:nonroot"
+
+	// appDir is the directory the binary is stored in within the image.
+	appDir = "/ko-app"
+)
+
+// Builder compiles a Go application and appends the binary as a layer on
+// top of a base image, without a Dockerfile or Docker daemon.
+type Builder struct {
+	config BuilderConfig
+}
+
+// BuildFunc implements component.Builder
+func (b *Builder) BuildFunc() interface{} {
+	return b.Build
+}
+
+// Config implements Configurable
+func (b *Builder) Config() (interface{}, error) {
+	return &b.config, nil
+}
+
+// BuilderConfig is the configuration structure for the builder.
+type BuilderConfig struct {
+	// Image is the repository to publish the image to.
+	Image string `hcl:"image,attr"`
+
+	// Tag is the tag to apply to the image, defaults to "latest".
+	Tag string `hcl:"tag,optional"`
+
+	// Main is the path to the main package, relative to the application.
+	Main string `hcl:"main,optional"`
+
+	// BaseImage is the image to add the binary to.
+	BaseImage string `hcl:"base_image,optional"`
+
+	// Platform is the OS and architecture to build for, as os/arch.
+	Platform string `hcl:"platform,optional"`
+
+	// Ldflags are passed to the linker when building the binary.
+	Ldflags string `hcl:"ldflags,optional"`
+
+	// BuildEnv are environment variables set when running go build.
+	BuildEnv map[string]string `hcl:"build_env,optional"`
+
+	// Local writes the image to the local Docker daemon rather than
+	// pushing it to the registry.
+	Local bool `hcl:"local,optional"`
+
+	// Control whether or not to inject the entrypoint binary into the resulting image
+	DisableCEB bool `hcl:"disable_entrypoint,optional"`
+
+	// EntrypointMode is how the entrypoint is added to the image, see
+	// epinject.Mode. This defaults to injecting it.
+	EntrypointMode string `hcl:"entrypoint_mode,optional"`
+}
+
+// Build compiles the application and publishes the image.
+func (b *Builder) Build(
+	ctx context.Context,
+	log hclog.Logger,
+	ui terminal.UI,
+	src *component.Source,
+) (*docker.Image, error) {
+	platform := b.config.Platform
+	if platform == "" {
+		platform = "linux/amd64"
+	}
+
+	parts := strings.SplitN(platform, "/", 2)
+	if len(parts) != 2 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid platform %q, expected os/arch", platform)
+	}
+	goos, goarch := parts[0], parts[1]
+
+	mode, err := epinject.ParseMode(b.config.EntrypointMode, b.config.DisableCEB)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
+	// Get the entrypoint binary for the platform before building so that
+	// we fail early if it isn't available.
+	var ceb []byte
+	if mode != epinject.ModeDisable {
+		ceb, _, err = epinject.Asset(platform)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+	}
+
+	tag := b.config.Tag
+	if tag == "" {
+		tag = "latest"
+	}
+
+	ref, err := name.NewTag(b.config.Image + ":" + tag)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid image name: %s", err)
+	}
+
+	sg := ui.StepGroup()
+	step := sg.Add("Compiling Go binary for %s...", platform)
+	defer step.Abort()
+
+	dir, err := ioutil.TempDir("", "waypoint-go")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	main := b.config.Main
+	if main == "" {
+		main = "."
+	}
+	if !strings.Contains(main, ".") {
+		// A path such as cmd/app is a directory in the application, go
+		// build would look for it in the standard library without ./
+		main = "./" + main
+	}
+
+	binary := filepath.Join(dir, src.App)
+	args := []string{"build", "-trimpath", "-o", binary}
+	if b.config.Ldflags != "" {
+		args = append(args, "-ldflags", b.config.Ldflags)
+	}
+	args = append(args, main)
+
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = src.Path
+	cmd.Stdout = step.TermOutput()
+	cmd.Stderr = step.TermOutput()
+	cmd.Env = append(os.Environ(),
+		"GOOS="+goos,
+		"GOARCH="+goarch,
+		"CGO_ENABLED=0",
+	)
+	for k, v := range b.config.BuildEnv {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	log.Debug("running go build", "args", args)
+	if err := cmd.Run(); err != nil {
+		return nil, status.Errorf(codes.Internal, "error compiling binary: %s", err)
+	}
+
+	step.Done()
+
+	baseImage := b.config.BaseImage
+	if baseImage == "" {
+		baseImage = DefaultBaseImage
+	}
+
+	step = sg.Add("Fetching base image %s...", baseImage)
+
+	baseRef, err := name.ParseReference(baseImage)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid base image: %s", err)
+	}
+
+	base, err := remote.Image(baseRef,
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithPlatform(v1.Platform{OS: goos, Architecture: goarch}),
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to fetch base image: %s", err)
+	}
+
+	step.Done()
+	step = sg.Add("Assembling image...")
+
+	appPath := path.Join(appDir, src.App)
+	bin, err := readFile(binary, appPath, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []layerFile{bin}
+	entrypoint := []string{appPath}
+
+	if mode != epinject.ModeDisable {
+		files = append(files, layerFile{Path: epinject.Path, Data: ceb, Mode: 0755})
+	}
+	if mode == epinject.ModeInject {
+		entrypoint = append([]string{epinject.Path}, entrypoint...)
+	}
+
+	layer, err := appLayer(files)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to create image layer: %s", err)
+	}
+
+	img, err := mutate.AppendLayers(base, layer)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to append image layer: %s", err)
+	}
+
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to read image config: %s", err)
+	}
+
+	config := cfg.Config.DeepCopy()
+	config.Entrypoint = entrypoint
+	config.Cmd = nil
+
+	img, err = mutate.Config(img, *config)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to set image config: %s", err)
+	}
+
+	step.Done()
+
+	if b.config.Local {
+		step = sg.Add("Writing image %s to Docker...", ref.String())
+		if _, err := daemon.Write(ref, img); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to write image to Docker: %s", err)
+		}
+	} else {
+		step = sg.Add("Pushing image %s...", ref.String())
+		if err := remote.Write(ref, img, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to push image: %s", err)
+		}
+	}
+
+	step.Done()
+
+	return &docker.Image{
+		Image: b.config.Image,
+		Tag:   tag,
+	}, nil
+}
+
+func (b *Builder) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&BuilderConfig{}))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description(`
+Build a container image for a Go application without a Dockerfile
+
+The application is compiled with the local Go toolchain and the binary is
+added as a layer on top of a base image. The image is pushed directly to the
+registry, or written to the local Docker daemon when local is set, so a
+registry stanza is not required.
+`)
+
+	doc.Example(`
+build {
+  use "go" {
+    image = "registry.example.com/myapp"
+    main  = "./cmd/myapp"
+  }
+}
+`)
+
+	doc.Input("component.Source")
+	doc.Output("docker.Image")
+
+	doc.SetField(
+		"image",
+		"the repository to publish the image to",
+	)
+
+	doc.SetField(
+		"tag",
+		"the tag to apply to the image",
+		docs.Default("latest"),
+	)
+
+	doc.SetField(
+		"main",
+		"the path to the main package, relative to the application",
+		docs.Default("."),
+	)
+
+	doc.SetField(
+		"base_image",
+		"the image to add the binary to",
+		docs.Default(DefaultBaseImage),
+	)
+
+	doc.SetField(
+		"platform",
+		"the OS and architecture to build for, as os/arch",
+		docs.Default("linux/amd64"),
+	)
+
+	doc.SetField(
+		"ldflags",
+		"flags to pass to the linker when building the binary",
+	)
+
+	doc.SetField(
+		"build_env",
+		"environment variables to set when running go build",
+		docs.Summary("cgo is disabled unless CGO_ENABLED is set here"),
+	)
+
+	doc.SetField(
+		"local",
+		"if set, the image is written to the local Docker daemon instead of being pushed",
+	)
+
+	doc.SetField(
+		"disable_entrypoint",
+		"if set, the entrypoint binary won't be injected into the image",
+		docs.Summary(
+			"The entrypoint binary is what provides extended functionality",
+			"such as logs and exec. If it is not injected at build time",
+			"the expectation is that the image already contains it",
+		),
+	)
+
+	doc.SetField(
+		"entrypoint_mode",
+		"how the entrypoint binary is added to the image",
+		docs.Summary(
+			"\"inject\" adds the binary and runs the application with it,",
+			"\"binary\" only adds the binary at /waypoint-entrypoint, and",
+			"\"disable\" doesn't add it. The entrypoint is available for the",
+			"linux/amd64 and linux/arm64 platforms, other platforms such as",
+			"windows/amd64 must use \"disable\"",
+		),
+		docs.Default("inject"),
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Builder      = (*Builder)(nil)
+	_ component.Configurable = (*Builder)(nil)
+	_ component.Documented   = (*Builder)(nil)
+)
@@ -0,0 +1,82 @@
+package golang
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+// layerFile is a file to add to the application layer.
+type layerFile struct {
+	Path string
+	Data []byte
+	Mode int64
+}
+
+// appLayer builds an image layer containing the given files. The
+// modification times are zeroed so the layer is reproducible.
+func appLayer(files []layerFile) (v1.Layer, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	dirs := map[string]bool{}
+	for _, f := range files {
+		// Add the parent directories first so they are created with
+		// known permissions.
+		var parents []string
+		for dir := path.Dir(f.Path); dir != "/" && !dirs[dir]; dir = path.Dir(dir) {
+			dirs[dir] = true
+			parents = append([]string{dir}, parents...)
+		}
+
+		for _, dir := range parents {
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     dir[1:] + "/",
+				Mode:     0755,
+				ModTime:  time.Unix(0, 0),
+			}); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.Path[1:],
+			Size:     int64(len(f.Data)),
+			Mode:     f.Mode,
+			ModTime:  time.Unix(0, 0),
+		}); err != nil {
+			return nil, err
+		}
+
+		if _, err := tw.Write(f.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	data := buf.Bytes()
+	return tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	})
+}
+
+// readFile reads a file into a layerFile at the given path in the image.
+func readFile(src, dst string, mode int64) (layerFile, error) {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return layerFile{}, err
+	}
+
+	return layerFile{Path: dst, Data: data, Mode: mode}, nil
+}
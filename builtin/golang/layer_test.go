@@ -0,0 +1,35 @@
+package golang
+
+import (
+	"archive/tar"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppLayer(t *testing.T) {
+	layer, err := appLayer([]layerFile{
+		{Path: "/ko-app/app", Data: []byte("binary"), Mode: 0755},
+		{Path: "/waypoint-entrypoint", Data: []byte("ceb"), Mode: 0755},
+	})
+	require.NoError(t, err)
+
+	r, err := layer.Uncompressed()
+	require.NoError(t, err)
+	defer r.Close()
+
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		names = append(names, h.Name)
+	}
+
+	require.Equal(t, []string{"ko-app/", "ko-app/app", "waypoint-entrypoint"}, names)
+}
@@ -0,0 +1,12 @@
+// Package golang contains a builder that compiles Go applications into
+// container images without a Dockerfile or Docker daemon.
+package golang
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk"
+)
+
+// Options are the SDK options to use for instantiation.
+var Options = []sdk.Option{
+	sdk.WithComponents(&Builder{}),
+}
@@ -46,3 +46,10 @@ func clientset(kubeconfig, context string) (*kubernetes.Clientset, string, *rest
 
 	return clientset, ns, clientconfig, nil
 }
+
+// Clientset returns a K8S clientset and configured namespace for
+// the given kubeconfig and context. This is used by other builtin
+// plugins that interact with Kubernetes.
+func Clientset(kubeconfig, context string) (*kubernetes.Clientset, string, *rest.Config, error) {
+	return clientset(kubeconfig, context)
+}
@@ -0,0 +1,400 @@
+package k8sbuild
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/go-playground/validator"
+	"github.com/hashicorp/go-hclog"
+	"github.com/oklog/ulid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/builtin/docker"
+	"github.com/hashicorp/waypoint/builtin/k8s"
+)
+
+// Builder builds a Docker image inside a Kubernetes cluster using kaniko
+// or BuildKit. This does not require a Docker daemon and pushes the image
+// directly to the registry.
+type Builder struct {
+	config BuilderConfig
+}
+
+// BuildFunc implements component.Builder
+func (b *Builder) BuildFunc() interface{} {
+	return b.Build
+}
+
+// Config implements Configurable
+func (b *Builder) Config() (interface{}, error) {
+	return &b.config, nil
+}
+
+// ConfigSet is called after a configuration has been decoded
+// we can use this to validate the config
+func (b *Builder) ConfigSet(config interface{}) error {
+	c, ok := config.(*BuilderConfig)
+	if !ok {
+		// this should never happen
+		return fmt.Errorf("Invalid configuration, expected *k8sbuild.BuilderConfig, got %s", reflect.TypeOf(config))
+	}
+
+	if err := validator.New().Struct(c); err != nil {
+		return fmt.Errorf("Invalid engine %q, engine must be one of 'kaniko' or 'buildkit'", c.Engine)
+	}
+
+	return nil
+}
+
+// BuilderConfig is the configuration structure for the builder.
+type BuilderConfig struct {
+	// Image is the repository to push the image to.
+	Image string `hcl:"image,attr"`
+
+	// Tag is the tag to apply to the image, defaults to "latest".
+	Tag string `hcl:"tag,optional"`
+
+	// The name/path to the Dockerfile if it is not the root of the project
+	Dockerfile string `hcl:"dockerfile,optional"`
+
+	// BuildArgs are passed to the Dockerfile as build arguments.
+	BuildArgs map[string]string `hcl:"build_args,optional"`
+
+	// Engine is the builder to run, either "kaniko" or "buildkit".
+	Engine string `hcl:"engine,optional" validate:"omitempty,oneof=kaniko buildkit"`
+
+	// BuilderImage overrides the image of the kaniko executor or BuildKit.
+	BuilderImage string `hcl:"builder_image,optional"`
+
+	// RegistrySecret is the name of a secret of type kubernetes.io/dockerconfigjson
+	// with the credentials used to push the image.
+	RegistrySecret string `hcl:"registry_secret,optional"`
+
+	// Cache enables the kaniko layer cache, stored in the registry.
+	Cache bool `hcl:"cache,optional"`
+
+	// Namespace to run the build pod in, defaults to the namespace of the context.
+	Namespace string `hcl:"namespace,optional"`
+
+	// KubeconfigPath is the path to the kubeconfig file. If this is
+	// blank then we default to the home directory.
+	KubeconfigPath string `hcl:"kubeconfig,optional"`
+
+	// Context specifies the kube context to use.
+	Context string `hcl:"context,optional"`
+}
+
+func (b *Builder) tag() string {
+	if b.config.Tag == "" {
+		return "latest"
+	}
+
+	return b.config.Tag
+}
+
+// Build runs the build in the cluster.
+func (b *Builder) Build(
+	ctx context.Context,
+	log hclog.Logger,
+	ui terminal.UI,
+	src *component.Source,
+) (*docker.Image, error) {
+	sg := ui.StepGroup()
+	step := sg.Add("Initializing Kubernetes client...")
+	defer step.Abort()
+
+	clientset, ns, config, err := k8s.Clientset(b.config.KubeconfigPath, b.config.Context)
+	if err != nil {
+		return nil, err
+	}
+	if b.config.Namespace != "" {
+		ns = b.config.Namespace
+	}
+
+	dockerfile := b.config.Dockerfile
+	if dockerfile != "" {
+		dockerfile = path.Join(src.Path, dockerfile)
+	}
+
+	contextDir, relDockerfile, err := build.GetContextFromLocalDir(src.Path, dockerfile)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "unable to create build context: %s", err)
+	}
+
+	excludes, err := build.ReadDockerignore(contextDir)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to read .dockerignore: %s", err)
+	}
+
+	if err := build.ValidateContextDirectory(contextDir, excludes); err != nil {
+		return nil, status.Errorf(codes.Internal, "error checking context: %s", err)
+	}
+
+	// And canonicalize dockerfile name to a platform-independent one
+	relDockerfile = archive.CanonicalTarNameForPath(relDockerfile)
+
+	excludes = build.TrimBuildFilesFromExcludes(excludes, relDockerfile, false)
+	buildCtx, err := archive.TarWithOptions(contextDir, &archive.TarOptions{
+		ExcludePatterns: excludes,
+		Compression:     archive.Gzip,
+		ChownOpts:       &idtools.Identity{UID: 0, GID: 0},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to compress context: %s", err)
+	}
+	defer buildCtx.Close()
+
+	id, err := ulid.New(ulid.Now(), rand.Reader)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to generate build id: %s", err)
+	}
+
+	// Pod names must be lowercase, use the random part of the id to
+	// keep the name short.
+	name := fmt.Sprintf("%s-build-%s", src.App, strings.ToLower(id.String()[20:]))
+	pods := clientset.CoreV1().Pods(ns)
+
+	step.Done()
+	step = sg.Add("Starting build pod %s...", name)
+
+	log.Debug("creating build pod", "name", name, "namespace", ns)
+	pod, err := pods.Create(ctx, b.buildPod(name, src.App, relDockerfile), metav1.CreateOptions{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to create build pod: %s", err)
+	}
+
+	// Always clean up the pod, even if the build was cancelled.
+	defer func() {
+		log.Debug("deleting build pod", "name", name)
+		if err := pods.Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
+			log.Warn("error deleting build pod", "name", name, "err", err)
+		}
+	}()
+
+	// Report why the pod isn't starting, such as an error pulling the
+	// builder image, so the build doesn't just hang until the timeout.
+	var waiting string
+	err = wait.PollImmediate(time.Second, 5*time.Minute, func() (bool, error) {
+		pod, err = pods.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			return true, nil
+		case corev1.PodFailed, corev1.PodSucceeded:
+			return false, fmt.Errorf("build pod exited before the build context was sent")
+		}
+
+		if v := podWaitingStatus(pod); v != "" && v != waiting {
+			waiting = v
+			step.Update("Build pod %s is having an issue starting - %s", name, waiting)
+			step.Status(terminal.StatusWarn)
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		if err == wait.ErrWaitTimeout && waiting != "" {
+			err = fmt.Errorf("timed out, last status was %s", waiting)
+		}
+
+		return nil, status.Errorf(codes.Internal, "error waiting for build pod to start: %s", err)
+	}
+
+	step.Done()
+	step = sg.Add("Building image %s:%s...", b.config.Image, b.tag())
+
+	// Attach to the build container, this sends the build context and
+	// streams the build output until the container exits.
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(name).
+		Namespace(ns).
+		SubResource("attach").
+		VersionedParams(&corev1.PodAttachOptions{
+			Container: containerName,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to attach to build pod: %s", err)
+	}
+
+	err = exec.Stream(remotecommand.StreamOptions{
+		Stdin:  buildCtx,
+		Stdout: step.TermOutput(),
+		Stderr: step.TermOutput(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error streaming build: %s", err)
+	}
+
+	if err := waitForCompletion(ctx, clientset, ns, name); err != nil {
+		return nil, status.Errorf(codes.Internal, "error building image: %s", err)
+	}
+
+	step.Done()
+
+	return &docker.Image{
+		Image: b.config.Image,
+		Tag:   b.tag(),
+	}, nil
+}
+
+// waitForCompletion waits for the build pod to exit and returns an error
+// if the build failed.
+func waitForCompletion(ctx context.Context, clientset *kubernetes.Clientset, ns, name string) error {
+	var pod *corev1.Pod
+	err := wait.PollImmediate(time.Second, 5*time.Minute, func() (bool, error) {
+		var err error
+		pod, err = clientset.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return nil
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if t := cs.State.Terminated; t != nil {
+			return fmt.Errorf("build exited with code %d: %s", t.ExitCode, t.Reason)
+		}
+	}
+
+	return fmt.Errorf("build pod failed")
+}
+
+func (b *Builder) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&BuilderConfig{}))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description(`
+Build a Docker image inside a Kubernetes cluster using kaniko or BuildKit
+
+The build runs in a pod in the cluster and does not require access to a
+Docker daemon, so it can be used from runners without privileged access.
+The image is pushed to the registry by the builder, a registry stanza is
+not required. The Waypoint entrypoint is not injected into the image.
+`)
+
+	doc.Example(`
+build {
+  use "kubernetes-build" {
+    image           = "registry.example.com/myapp"
+    tag             = "latest"
+    engine          = "kaniko"
+    registry_secret = "registry-credentials"
+  }
+}
+`)
+
+	doc.Input("component.Source")
+	doc.Output("docker.Image")
+
+	doc.SetField(
+		"image",
+		"the repository to push the image to",
+	)
+
+	doc.SetField(
+		"tag",
+		"the tag to apply to the image",
+		docs.Default("latest"),
+	)
+
+	doc.SetField(
+		"dockerfile",
+		"the path to the Dockerfile, relative to the application",
+		docs.Default("Dockerfile"),
+	)
+
+	doc.SetField(
+		"build_args",
+		"build arguments to pass to the Dockerfile",
+	)
+
+	doc.SetField(
+		"engine",
+		"the builder to run the build with, either kaniko or buildkit",
+		docs.Summary(
+			"buildkit runs a rootless BuildKit daemon in the build pod which",
+			"requires the seccomp and AppArmor profiles to be unconfined",
+		),
+		docs.Default(engineKaniko),
+	)
+
+	doc.SetField(
+		"builder_image",
+		"the image of the kaniko executor or BuildKit to run",
+	)
+
+	doc.SetField(
+		"registry_secret",
+		"the name of a kubernetes.io/dockerconfigjson secret with the registry credentials",
+	)
+
+	doc.SetField(
+		"cache",
+		"if set, kaniko caches layers in the registry",
+		docs.Summary("the cache is stored in the image repository with a /cache suffix"),
+	)
+
+	doc.SetField(
+		"namespace",
+		"the namespace to run the build pod in",
+		docs.Summary("defaults to the namespace of the current context"),
+	)
+
+	doc.SetField(
+		"kubeconfig",
+		"path to the kubeconfig file to use",
+		docs.Summary("by default uses from current user's home directory"),
+		docs.EnvVar("KUBECONFIG"),
+	)
+
+	doc.SetField(
+		"context",
+		"the kubectl context to use, as defined in the kubeconfig file",
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Builder      = (*Builder)(nil)
+	_ component.Configurable = (*Builder)(nil)
+	_ component.Documented   = (*Builder)(nil)
+)
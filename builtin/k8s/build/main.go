@@ -0,0 +1,12 @@
+// Package k8sbuild contains a builder that builds images inside a
+// Kubernetes cluster using kaniko or BuildKit.
+package k8sbuild
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk"
+)
+
+// Options are the SDK options to use for instantiation.
+var Options = []sdk.Option{
+	sdk.WithComponents(&Builder{}),
+}
@@ -0,0 +1,174 @@
+package k8sbuild
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	engineKaniko   = "kaniko"
+	engineBuildKit = "buildkit"
+
+	defaultKanikoImage   = "gcr.io/kaniko-project/executor:v1.3.0"
+	defaultBuildKitImage = "moby/buildkit:v0.8.0-rootless"
+
+	// containerName is the name of the container running the build.
+	containerName = "builder"
+
+	// dockerConfigPath is where the registry secret is mounted for BuildKit,
+	// kaniko reads its credentials from a fixed path.
+	dockerConfigPath = "/home/user/.docker"
+	kanikoConfigPath = "/kaniko/.docker"
+
+	labelBuild = "waypoint.hashicorp.com/build"
+)
+
+// buildPod returns the pod that runs the build. The build context is streamed
+// into the container over stdin as a gzipped tar archive.
+func (b *Builder) buildPod(name, app, dockerfile string) *corev1.Pod {
+	engine := b.config.Engine
+	if engine == "" {
+		engine = engineKaniko
+	}
+
+	ref := b.config.Image + ":" + b.tag()
+
+	// Sort the build args so the generated pod is stable
+	var buildArgs []string
+	for k, v := range b.config.BuildArgs {
+		buildArgs = append(buildArgs, k+"="+v)
+	}
+	sort.Strings(buildArgs)
+
+	container := corev1.Container{
+		Name:      containerName,
+		Stdin:     true,
+		StdinOnce: true,
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				labelBuild: app,
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+		},
+	}
+
+	configPath := kanikoConfigPath
+
+	switch engine {
+	case engineKaniko:
+		container.Image = defaultKanikoImage
+		container.Args = []string{
+			"--context=tar://stdin",
+			"--dockerfile=" + dockerfile,
+			"--destination=" + ref,
+		}
+		for _, arg := range buildArgs {
+			container.Args = append(container.Args, "--build-arg="+arg)
+		}
+		if b.config.Cache {
+			container.Args = append(container.Args, "--cache=true", "--cache-repo="+b.config.Image+"/cache")
+		}
+
+	case engineBuildKit:
+		configPath = dockerConfigPath
+
+		container.Image = defaultBuildKitImage
+		container.Env = []corev1.EnvVar{
+			{Name: "BUILDKITD_FLAGS", Value: "--oci-worker-no-process-sandbox"},
+		}
+
+		// The context is extracted before running the daemonless build, the
+		// build arguments are passed through as positional parameters to
+		// avoid quoting them for the shell.
+		args := []string{
+			"build",
+			"--frontend", "dockerfile.v0",
+			"--local", "context=/tmp/context",
+			"--local", "dockerfile=" + path.Join("/tmp/context", path.Dir(dockerfile)),
+			"--opt", "filename=" + path.Base(dockerfile),
+			"--output", fmt.Sprintf("type=image,name=%s,push=true", ref),
+		}
+		for _, arg := range buildArgs {
+			args = append(args, "--opt", "build-arg:"+arg)
+		}
+
+		container.Command = []string{"sh", "-c"}
+		container.Args = append([]string{
+			`mkdir -p /tmp/context && tar -xzf - -C /tmp/context && exec buildctl-daemonless.sh "$@"`,
+			"buildctl",
+		}, args...)
+
+		// Rootless BuildKit requires the default seccomp and AppArmor
+		// profiles to be disabled.
+		pod.Annotations = map[string]string{
+			"container.apparmor.security.beta.kubernetes.io/" + containerName: "unconfined",
+			"container.seccomp.security.alpha.kubernetes.io/" + containerName: "unconfined",
+		}
+	}
+
+	if b.config.BuilderImage != "" {
+		container.Image = b.config.BuilderImage
+	}
+
+	if b.config.RegistrySecret != "" {
+		pod.Spec.Volumes = []corev1.Volume{
+			{
+				Name: "registry-credentials",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: b.config.RegistrySecret,
+						Items: []corev1.KeyToPath{
+							{Key: corev1.DockerConfigJsonKey, Path: "config.json"},
+						},
+					},
+				},
+			},
+		}
+
+		container.VolumeMounts = []corev1.VolumeMount{
+			{Name: "registry-credentials", MountPath: configPath, ReadOnly: true},
+		}
+	}
+
+	pod.Spec.Containers = []corev1.Container{container}
+
+	return pod
+}
+
+// podWaitingStatus returns why the build pod hasn't started yet, or "" if
+// there is nothing to report. This is the reason the pod can't be scheduled
+// or the reason the build container is waiting, such as an error pulling
+// the builder image. Containers that are only being created aren't
+// reported since that is expected while the pod starts.
+func podWaitingStatus(pod *corev1.Pod) string {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason != "" {
+			return fmt.Sprintf("%s: %s", c.Reason, c.Message)
+		}
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		w := cs.State.Waiting
+		if w == nil || w.Reason == "" || w.Reason == "ContainerCreating" {
+			continue
+		}
+
+		if w.Message == "" {
+			return w.Reason
+		}
+
+		return fmt.Sprintf("%s: %s", w.Reason, w.Message)
+	}
+
+	return ""
+}
@@ -0,0 +1,195 @@
+package k8sbuild
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBuilderBuildPod(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Config     BuilderConfig
+		Dockerfile string
+		Image      string
+		Command    []string
+		Args       []string
+		ConfigPath string
+		Unconfined bool
+	}{
+		{
+			"kaniko by default",
+			BuilderConfig{Image: "registry/app"},
+			"Dockerfile",
+			defaultKanikoImage,
+			nil,
+			[]string{
+				"--context=tar://stdin",
+				"--dockerfile=Dockerfile",
+				"--destination=registry/app:latest",
+			},
+			"",
+			false,
+		},
+
+		{
+			"kaniko with build args and cache",
+			BuilderConfig{
+				Image:          "registry/app",
+				Tag:            "v1",
+				BuildArgs:      map[string]string{"B": "2", "A": "1"},
+				Cache:          true,
+				RegistrySecret: "creds",
+			},
+			"docker/Dockerfile.prod",
+			defaultKanikoImage,
+			nil,
+			[]string{
+				"--context=tar://stdin",
+				"--dockerfile=docker/Dockerfile.prod",
+				"--destination=registry/app:v1",
+				"--build-arg=A=1",
+				"--build-arg=B=2",
+				"--cache=true",
+				"--cache-repo=registry/app/cache",
+			},
+			kanikoConfigPath,
+			false,
+		},
+
+		{
+			"buildkit",
+			BuilderConfig{
+				Image:          "registry/app",
+				Engine:         engineBuildKit,
+				BuildArgs:      map[string]string{"A": "1"},
+				RegistrySecret: "creds",
+				BuilderImage:   "example/buildkit:latest",
+			},
+			"docker/Dockerfile.prod",
+			"example/buildkit:latest",
+			[]string{"sh", "-c"},
+			[]string{
+				`mkdir -p /tmp/context && tar -xzf - -C /tmp/context && exec buildctl-daemonless.sh "$@"`,
+				"buildctl",
+				"build",
+				"--frontend", "dockerfile.v0",
+				"--local", "context=/tmp/context",
+				"--local", "dockerfile=/tmp/context/docker",
+				"--opt", "filename=Dockerfile.prod",
+				"--output", "type=image,name=registry/app:latest,push=true",
+				"--opt", "build-arg:A=1",
+			},
+			dockerConfigPath,
+			true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			b := &Builder{config: tt.Config}
+			pod := b.buildPod("waypoint-build-1", "web", tt.Dockerfile)
+
+			require.Equal("waypoint-build-1", pod.Name)
+			require.Equal("web", pod.Labels[labelBuild])
+			require.Equal(corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+			require.Len(pod.Spec.Containers, 1)
+
+			c := pod.Spec.Containers[0]
+			require.Equal(containerName, c.Name)
+			require.True(c.Stdin)
+			require.True(c.StdinOnce)
+			require.Equal(tt.Image, c.Image)
+			require.Equal(tt.Command, c.Command)
+			require.Equal(tt.Args, c.Args)
+
+			if tt.ConfigPath == "" {
+				require.Empty(pod.Spec.Volumes)
+				require.Empty(c.VolumeMounts)
+			} else {
+				require.Len(pod.Spec.Volumes, 1)
+				require.Equal(tt.Config.RegistrySecret, pod.Spec.Volumes[0].Secret.SecretName)
+				require.Len(c.VolumeMounts, 1)
+				require.Equal(tt.ConfigPath, c.VolumeMounts[0].MountPath)
+				require.True(c.VolumeMounts[0].ReadOnly)
+			}
+
+			annotation := "container.apparmor.security.beta.kubernetes.io/" + containerName
+			if tt.Unconfined {
+				require.Equal("unconfined", pod.Annotations[annotation])
+			} else {
+				require.Empty(pod.Annotations)
+			}
+		})
+	}
+}
+
+func TestPodWaitingStatus(t *testing.T) {
+	waiting := func(reason, message string) corev1.PodStatus {
+		return corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: containerName,
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message},
+					},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		Name     string
+		Status   corev1.PodStatus
+		Expected string
+	}{
+		{
+			"pending",
+			corev1.PodStatus{Phase: corev1.PodPending},
+			"",
+		},
+
+		{
+			"creating",
+			waiting("ContainerCreating", ""),
+			"",
+		},
+
+		{
+			"image pull",
+			waiting("ImagePullBackOff", `Back-off pulling image "example/buildkit"`),
+			`ImagePullBackOff: Back-off pulling image "example/buildkit"`,
+		},
+
+		{
+			"reason only",
+			waiting("CreateContainerConfigError", ""),
+			"CreateContainerConfigError",
+		},
+
+		{
+			"unschedulable",
+			corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{
+						Type:    corev1.PodScheduled,
+						Status:  corev1.ConditionFalse,
+						Reason:  corev1.PodReasonUnschedulable,
+						Message: "0/3 nodes are available: 3 Insufficient memory.",
+					},
+				},
+			},
+			"Unschedulable: 0/3 nodes are available: 3 Insufficient memory.",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			pod := &corev1.Pod{Status: tt.Status}
+			require.Equal(t, tt.Expected, podWaitingStatus(pod))
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/component"
 )
@@ -41,4 +42,35 @@ func (d *Deployment) newDeployment(name string) *appsv1.Deployment {
 	}
 }
 
+// setLabels adds labels to the labels of the object. Labels with values
+// that aren't valid K8S label values, such as values with spaces, are
+// added as annotations instead, and labels with keys that aren't valid K8S
+// keys are skipped. Labels that the object has already, such as the name
+// that selects its pods, aren't changed.
+func setLabels(meta *metav1.ObjectMeta, labels map[string]string) {
+	for k, v := range labels {
+		if len(validation.IsQualifiedName(k)) > 0 {
+			continue
+		}
+
+		if len(validation.IsValidLabelValue(v)) > 0 {
+			if meta.Annotations == nil {
+				meta.Annotations = map[string]string{}
+			}
+			if _, ok := meta.Annotations[k]; !ok {
+				meta.Annotations[k] = v
+			}
+
+			continue
+		}
+
+		if meta.Labels == nil {
+			meta.Labels = map[string]string{}
+		}
+		if _, ok := meta.Labels[k]; !ok {
+			meta.Labels[k] = v
+		}
+	}
+}
+
 var _ component.Deployment = (*Deployment)(nil)
@@ -2,11 +2,13 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,6 +20,8 @@ import (
 	"github.com/hashicorp/waypoint-plugin-sdk/docs"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/builtin/docker"
+	"github.com/hashicorp/waypoint/internal/pkg/finalcontext"
+	"github.com/hashicorp/waypoint/internal/pkg/resource"
 )
 
 const (
@@ -45,6 +49,21 @@ func (p *Platform) DestroyFunc() interface{} {
 	return p.Destroy
 }
 
+// PlanFunc implements plugin.Planner
+func (p *Platform) PlanFunc() interface{} {
+	return p.Plan
+}
+
+// ScaleFunc implements plugin.Scaler
+func (p *Platform) ScaleFunc() interface{} {
+	return p.Scale
+}
+
+// ResourcesFunc implements plugin.DeclaredResources
+func (p *Platform) ResourcesFunc() interface{} {
+	return p.Resources
+}
+
 // ValidateAuthFunc implements component.Authenticator
 func (p *Platform) ValidateAuthFunc() interface{} {
 	return p.ValidateAuth
@@ -75,6 +94,7 @@ func (p *Platform) Deploy(
 	src *component.Source,
 	img *docker.Image,
 	deployConfig *component.DeploymentConfig,
+	labels *component.LabelSet,
 	ui terminal.UI,
 ) (*Deployment, error) {
 	// Create our deployment and set an initial ID
@@ -115,6 +135,191 @@ func (p *Platform) Deploy(
 		return nil, err
 	}
 
+	p.deploymentSpec(deployment, &result, img, deployConfig, labels)
+
+	if p.config.ServiceAccount != "" {
+		// Determine if we need to make a service account
+		saClient := clientset.CoreV1().ServiceAccounts(ns)
+		saCreate := false
+		serviceAccount, err := saClient.Get(ctx, p.config.ServiceAccount, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			serviceAccount = newServiceAccount(p.config.ServiceAccount)
+			saCreate = true
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if saCreate {
+			serviceAccount, err = saClient.Create(ctx, serviceAccount, metav1.CreateOptions{})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	dc := clientset.AppsV1().Deployments(ns)
+
+	// Create/update
+	if create {
+		log.Debug("no existing deployment, creating a new one")
+		step.Update("Creating deployment...")
+		deployment, err = dc.Create(ctx, deployment, metav1.CreateOptions{})
+	} else {
+		log.Debug("updating deployment")
+		step.Update("Updating deployment...")
+		deployment, err = dc.Update(ctx, deployment, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	step.Done()
+	step = sg.Add("Waiting for deployment...")
+
+	ps := clientset.CoreV1().Pods(ns)
+	podLabelId := fmt.Sprintf("%s=%s", labelId, result.Id)
+
+	var (
+		lastStatus    time.Time
+		detectedError string
+		k8error       string
+		reportedError string
+	)
+
+	timeout := 10 * time.Minute
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Wait on the Pod to start. This stops early if ctx is canceled, such
+	// as when the job is canceled.
+	err = wait.PollImmediateUntil(2*time.Second, func() (bool, error) {
+		dep, err := dc.Get(ctx, result.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		if time.Since(lastStatus) > 10*time.Second {
+			step.Update(fmt.Sprintf(
+				"Waiting on deployment to become available: %d/%d/%d",
+				*dep.Spec.Replicas,
+				dep.Status.UnavailableReplicas,
+				dep.Status.AvailableReplicas,
+			))
+			lastStatus = time.Now()
+		}
+
+		if dep.Status.AvailableReplicas > 0 {
+			return true, nil
+		}
+
+		pods, err := ps.List(ctx, metav1.ListOptions{
+			LabelSelector: podLabelId,
+		})
+
+		if err != nil {
+			return false, nil
+		}
+
+		for _, p := range pods.Items {
+			for _, cs := range p.Status.ContainerStatuses {
+				if cs.Ready {
+					continue
+				}
+
+				// Report why the container is waiting so that users know
+				// why the pods aren't starting. Containers that are only
+				// being created are expected while the pods start.
+				if w := cs.State.Waiting; w != nil && w.Reason != "" && w.Reason != "ContainerCreating" {
+					if w.Reason == "ImagePullBackOff" || w.Reason == "ErrImagePull" {
+						detectedError = "Pod unable to access Docker image"
+					} else {
+						detectedError = w.Reason
+					}
+					k8error = w.Message
+				}
+			}
+		}
+
+		if detectedError != "" && detectedError != reportedError {
+			step.Update("Detected pods having an issue starting - %s: %s", detectedError, k8error)
+			step.Status(terminal.StatusWarn)
+			reportedError = detectedError
+
+			// force a faster rerender
+			lastStatus = time.Time{}
+		}
+
+		return false, nil
+	}, waitCtx.Done())
+	if err != nil {
+		if err == wait.ErrWaitTimeout {
+			err = fmt.Errorf("Deployment was not able to start pods after %s", timeout)
+		}
+
+		// If the deploy was canceled, remove the deployment if we created it
+		// so that it isn't left behind without a record of it.
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			if create {
+				dctx, cancel := finalcontext.Context(log)
+				defer cancel()
+
+				log.Info("deploy canceled, deleting the deployment", "name", result.Name)
+				if derr := dc.Delete(dctx, result.Name, metav1.DeleteOptions{}); derr != nil {
+					log.Warn("error deleting the deployment", "err", derr)
+				}
+			}
+		}
+
+		return nil, err
+	}
+
+	step.Update("Deployment successfully rolled out!")
+	step.Done()
+
+	return &result, nil
+}
+
+// Plan returns the K8S deployment that Deploy would create or update for
+// the image, without connecting to the cluster.
+func (p *Platform) Plan(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	img *docker.Image,
+	deployConfig *component.DeploymentConfig,
+	labels *component.LabelSet,
+) (string, error) {
+	var result Deployment
+	id, err := component.Id()
+	if err != nil {
+		return "", err
+	}
+	result.Id = id
+	result.Name = strings.ToLower(fmt.Sprintf("%s-%s", src.App, id))
+
+	deployment := result.newDeployment(result.Name)
+	p.deploymentSpec(deployment, &result, img, deployConfig, labels)
+
+	data, err := json.MarshalIndent(deployment, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// deploymentSpec sets the spec of the K8S deployment for the image. This is
+// shared by Deploy and Plan.
+func (p *Platform) deploymentSpec(
+	deployment *appsv1.Deployment,
+	result *Deployment,
+	img *docker.Image,
+	deployConfig *component.DeploymentConfig,
+	labels *component.LabelSet,
+) {
 	if p.config.ServicePort == 0 {
 		p.config.ServicePort = 3000
 	}
@@ -148,6 +353,12 @@ func (p *Platform) Deploy(
 		deployment.Spec.Replicas = &p.config.Count
 	}
 
+	// Set the labels of the operation on the deployment and its pods.
+	if labels != nil {
+		setLabels(&deployment.ObjectMeta, labels.Labels)
+		setLabels(&deployment.Spec.Template.ObjectMeta, labels.Labels)
+	}
+
 	// Set our ID on the label. We use this ID so that we can have a key
 	// to route to multiple versions during release management.
 	deployment.Spec.Template.Labels[labelId] = result.Id
@@ -263,136 +474,45 @@ func (p *Platform) Deploy(
 
 	if p.config.ServiceAccount != "" {
 		deployment.Spec.Template.Spec.ServiceAccountName = p.config.ServiceAccount
-
-		// Determine if we need to make a service account
-		saClient := clientset.CoreV1().ServiceAccounts(ns)
-		saCreate := false
-		serviceAccount, err := saClient.Get(ctx, p.config.ServiceAccount, metav1.GetOptions{})
-		if errors.IsNotFound(err) {
-			serviceAccount = newServiceAccount(p.config.ServiceAccount)
-			saCreate = true
-			err = nil
-		}
-		if err != nil {
-			return nil, err
-		}
-
-		if saCreate {
-			serviceAccount, err = saClient.Create(ctx, serviceAccount, metav1.CreateOptions{})
-			if err != nil {
-				return nil, err
-			}
-		}
 	}
+}
 
-	dc := clientset.AppsV1().Deployments(ns)
+// Destroy deletes the K8S deployment.
+func (p *Platform) Destroy(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+	ui terminal.UI,
+) error {
+	sg := ui.StepGroup()
+	step := sg.Add("Initializing Kubernetes client...")
+	defer step.Abort()
 
-	// Create/update
-	if create {
-		log.Debug("no existing deployment, creating a new one")
-		step.Update("Creating deployment...")
-		deployment, err = dc.Create(ctx, deployment, metav1.CreateOptions{})
-	} else {
-		log.Debug("updating deployment")
-		step.Update("Updating deployment...")
-		deployment, err = dc.Update(ctx, deployment, metav1.UpdateOptions{})
-	}
+	// Get our client
+	clientset, ns, config, err := clientset(p.config.KubeconfigPath, p.config.Context)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
+	step.Update("Kubernetes client connected to %s with namespace %s", config.Host, ns)
 	step.Done()
-	step = sg.Add("Waiting for deployment...")
-
-	ps := clientset.CoreV1().Pods(ns)
-	podLabelId := fmt.Sprintf("%s=%s", labelId, result.Id)
-
-	var (
-		lastStatus    time.Time
-		detectedError string
-		k8error       string
-		reportedError bool
-	)
-
-	timeout := 10 * time.Minute
-
-	// Wait on the Pod to start
-	err = wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
-		dep, err := dc.Get(ctx, result.Name, metav1.GetOptions{})
-		if err != nil {
-			return false, err
-		}
-
-		if time.Since(lastStatus) > 10*time.Second {
-			step.Update(fmt.Sprintf(
-				"Waiting on deployment to become available: %d/%d/%d",
-				*dep.Spec.Replicas,
-				dep.Status.UnavailableReplicas,
-				dep.Status.AvailableReplicas,
-			))
-			lastStatus = time.Now()
-		}
-
-		if dep.Status.AvailableReplicas > 0 {
-			return true, nil
-		}
-
-		pods, err := ps.List(ctx, metav1.ListOptions{
-			LabelSelector: podLabelId,
-		})
-
-		if err != nil {
-			return false, nil
-		}
-
-		for _, p := range pods.Items {
-			for _, cs := range p.Status.ContainerStatuses {
-				if cs.Ready {
-					continue
-				}
-
-				if cs.State.Waiting != nil {
-					if cs.State.Waiting.Reason == "ImagePullBackOff" {
-						detectedError = "Pod unable to access Docker image"
-						k8error = cs.State.Waiting.Message
-					}
-				}
-			}
-		}
-
-		if detectedError != "" && !reportedError {
-			step.Update("Detected pods having an issue starting - %s: %s", detectedError, k8error)
-			step.Status(terminal.StatusWarn)
-			reportedError = true
-
-			// force a faster rerender
-			lastStatus = time.Time{}
-		}
-
-		// TODO: Report the statuses and events of the pods that are starting
-		// here so that users know why stuff isn't starting. Most commonly here
-		// it's going to be an error pulling the image.
+	step = sg.Add("Deleting deployment...")
 
-		return false, nil
-	})
-	if err != nil {
-		if err == wait.ErrWaitTimeout {
-			err = fmt.Errorf("Deployment was not able to start pods after %s", timeout)
-		}
-		return nil, err
+	deployclient := clientset.AppsV1().Deployments(ns)
+	if err := deployclient.Delete(ctx, deployment.Name, metav1.DeleteOptions{}); err != nil {
+		return err
 	}
 
-	step.Update("Deployment successfully rolled out!")
 	step.Done()
-
-	return &result, nil
+	return nil
 }
 
-// Destroy deletes the K8S deployment.
-func (p *Platform) Destroy(
+// Scale changes the number of replicas of the K8S deployment.
+func (p *Platform) Scale(
 	ctx context.Context,
 	log hclog.Logger,
 	deployment *Deployment,
+	replicas int32,
 	ui terminal.UI,
 ) error {
 	sg := ui.StepGroup()
@@ -407,17 +527,71 @@ func (p *Platform) Destroy(
 
 	step.Update("Kubernetes client connected to %s with namespace %s", config.Host, ns)
 	step.Done()
-	step = sg.Add("Deleting deployment...")
+	step = sg.Add("Scaling deployment to %d replicas...", replicas)
 
 	deployclient := clientset.AppsV1().Deployments(ns)
-	if err := deployclient.Delete(ctx, deployment.Name, metav1.DeleteOptions{}); err != nil {
+	scale, err := deployclient.GetScale(ctx, deployment.Name, metav1.GetOptions{})
+	if err != nil {
 		return err
 	}
 
+	scale.Spec.Replicas = replicas
+	if _, err := deployclient.UpdateScale(ctx, deployment.Name, scale, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+
+	step.Update("Deployment scaled to %d replicas", replicas)
 	step.Done()
 	return nil
 }
 
+// Resources returns the K8S deployment with its ready replicas, and the
+// service for the app if it has been released.
+func (p *Platform) Resources(
+	ctx context.Context,
+	log hclog.Logger,
+	src *component.Source,
+	deployment *Deployment,
+) ([]*resource.Resource, error) {
+	clientset, ns, _, err := clientset(p.config.KubeconfigPath, p.config.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &resource.Resource{Type: "deployment", Name: deployment.Name}
+	d, err := clientset.AppsV1().Deployments(ns).Get(ctx, deployment.Name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		result.State = "missing"
+	case err != nil:
+		return nil, err
+	default:
+		var desired int32 = 1
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+
+		result.State = fmt.Sprintf("%d/%d ready", d.Status.ReadyReplicas, desired)
+	}
+
+	resources := []*resource.Resource{result}
+
+	// The service is created by the releaser and named after the app
+	service, err := clientset.CoreV1().Services(ns).Get(ctx, src.App, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		resources = append(resources, &resource.Resource{
+			Type:  "service",
+			Name:  service.Name,
+			State: strings.ToLower(string(service.Spec.Type)),
+		})
+	}
+
+	return resources, nil
+}
+
 // Config is the configuration structure for the Platform.
 type Config struct {
 	// KubeconfigPath is the path to the kubeconfig file. If this is
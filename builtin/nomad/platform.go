@@ -39,6 +39,11 @@ func (p *Platform) DestroyFunc() interface{} {
 	return p.Destroy
 }
 
+// ScaleFunc implements plugin.Scaler
+func (p *Platform) ScaleFunc() interface{} {
+	return p.Scale
+}
+
 // ValidateAuthFunc implements component.Authenticator
 func (p *Platform) ValidateAuthFunc() interface{} {
 	return p.ValidateAuth
@@ -190,6 +195,41 @@ func (p *Platform) Destroy(
 	return err
 }
 
+// Scale changes the count of the task group of the Nomad job.
+func (p *Platform) Scale(
+	ctx context.Context,
+	log hclog.Logger,
+	deployment *Deployment,
+	replicas int32,
+	ui terminal.UI,
+) error {
+	// We'll update the user in real time
+	st := ui.Status()
+	defer st.Close()
+
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		return err
+	}
+
+	// The task group is named after the job when it is deployed.
+	st.Update(fmt.Sprintf("Scaling job to %d allocations...", replicas))
+	count := int(replicas)
+	resp, _, err := client.Jobs().Scale(
+		deployment.Name, deployment.Name, &count, "scaled by waypoint", false, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	st.Update(fmt.Sprintf("Monitoring evaluation %q", resp.EvalID))
+	if err := newMonitor(st, client).monitor(resp.EvalID); err != nil {
+		return err
+	}
+	st.Step(terminal.StatusOK, fmt.Sprintf("Job scaled to %d allocations", replicas))
+
+	return nil
+}
+
 // Config is the configuration structure for the Platform.
 type Config struct {
 	// The Nomad region to deploy to, defaults to "global"
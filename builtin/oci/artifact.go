@@ -0,0 +1,96 @@
+package oci
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// artifact is an image or image index loaded from disk. Exactly one of
+// Image or Index is set.
+type artifact struct {
+	Image v1.Image
+	Index v1.ImageIndex
+}
+
+// loadArtifact loads the artifact at path. A directory is read as an OCI
+// image layout and a file as an image tarball, as created by "docker save".
+//
+// If the layout contains a single image it is pushed as an image, otherwise
+// the whole index is pushed so every platform is kept.
+func loadArtifact(path string) (*artifact, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		img, err := tarball.ImageFromPath(path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read image tarball: %s", err)
+		}
+
+		return &artifact{Image: img}, nil
+	}
+
+	if _, err := os.Stat(filepath.Join(path, "index.json")); err != nil {
+		return nil, fmt.Errorf("%s is not an OCI image layout, index.json not found", path)
+	}
+
+	p, err := layout.FromPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read OCI image layout: %s", err)
+	}
+
+	idx, err := p.ImageIndex()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read OCI image index: %s", err)
+	}
+
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read OCI image index: %s", err)
+	}
+
+	switch len(manifest.Manifests) {
+	case 0:
+		return nil, fmt.Errorf("OCI image layout %s does not contain any images", path)
+
+	case 1:
+		desc := manifest.Manifests[0]
+		if desc.MediaType == types.OCIImageIndex || desc.MediaType == types.DockerManifestList {
+			// The layout references a nested index, such as a multi-platform
+			// image written by a build tool.
+			child, err := idx.ImageIndex(desc.Digest)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read OCI image index: %s", err)
+			}
+
+			return &artifact{Index: child}, nil
+		}
+
+		img, err := idx.Image(desc.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read image %s: %s", desc.Digest, err)
+		}
+
+		return &artifact{Image: img}, nil
+
+	default:
+		return &artifact{Index: idx}, nil
+	}
+}
+
+// Digest returns the digest of the image or index.
+func (a *artifact) Digest() (v1.Hash, error) {
+	if a.Index != nil {
+		return a.Index.Digest()
+	}
+
+	return a.Image.Digest()
+}
@@ -0,0 +1,96 @@
+package oci
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadArtifact(t *testing.T) {
+	t.Run("tarball", func(t *testing.T) {
+		require := require.New(t)
+
+		td, err := ioutil.TempDir("", "waypoint-oci")
+		require.NoError(err)
+		defer os.RemoveAll(td)
+
+		img, err := random.Image(1024, 1)
+		require.NoError(err)
+
+		ref, err := name.NewTag("example.com/app:latest")
+		require.NoError(err)
+
+		path := filepath.Join(td, "image.tar")
+		require.NoError(tarball.WriteToFile(path, ref, img))
+
+		art, err := loadArtifact(path)
+		require.NoError(err)
+		require.Nil(art.Index)
+
+		expected, err := img.Digest()
+		require.NoError(err)
+		actual, err := art.Digest()
+		require.NoError(err)
+		require.Equal(expected, actual)
+	})
+
+	t.Run("layout with one image", func(t *testing.T) {
+		require := require.New(t)
+
+		td, err := ioutil.TempDir("", "waypoint-oci")
+		require.NoError(err)
+		defer os.RemoveAll(td)
+
+		img, err := random.Image(1024, 1)
+		require.NoError(err)
+
+		p, err := layout.Write(td, empty.Index)
+		require.NoError(err)
+		require.NoError(p.AppendImage(img))
+
+		art, err := loadArtifact(td)
+		require.NoError(err)
+		require.Nil(art.Index)
+		require.NotNil(art.Image)
+	})
+
+	t.Run("layout with multiple images", func(t *testing.T) {
+		require := require.New(t)
+
+		td, err := ioutil.TempDir("", "waypoint-oci")
+		require.NoError(err)
+		defer os.RemoveAll(td)
+
+		p, err := layout.Write(td, empty.Index)
+		require.NoError(err)
+		for i := 0; i < 2; i++ {
+			img, err := random.Image(1024, 1)
+			require.NoError(err)
+			require.NoError(p.AppendImage(img))
+		}
+
+		art, err := loadArtifact(td)
+		require.NoError(err)
+		require.NotNil(art.Index)
+	})
+
+	t.Run("directory without layout", func(t *testing.T) {
+		require := require.New(t)
+
+		td, err := ioutil.TempDir("", "waypoint-oci")
+		require.NoError(err)
+		defer os.RemoveAll(td)
+
+		_, err = loadArtifact(td)
+		require.Error(err)
+		require.Contains(err.Error(), "index.json")
+	})
+}
@@ -0,0 +1,12 @@
+// Package oci contains a registry that pushes OCI image layouts and image
+// tarballs with go-containerregistry, without a Docker daemon.
+package oci
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk"
+)
+
+// Options are the SDK options to use for instantiation.
+var Options = []sdk.Option{
+	sdk.WithComponents(&Registry{}),
+}
@@ -0,0 +1,236 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint-plugin-sdk/docs"
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/builtin/docker"
+	"github.com/hashicorp/waypoint/builtin/files"
+)
+
+// Registry pushes an OCI image layout or image tarball to a registry
+// without a Docker daemon.
+type Registry struct {
+	config Config
+}
+
+// Config implements Configurable
+func (r *Registry) Config() (interface{}, error) {
+	return &r.config, nil
+}
+
+// ConfigSet is called after a configuration has been decoded
+// we can use this to validate the config
+func (r *Registry) ConfigSet(config interface{}) error {
+	c, ok := config.(*Config)
+	if !ok {
+		// this should never happen
+		return fmt.Errorf("Invalid configuration, expected *oci.Config, got %s", reflect.TypeOf(config))
+	}
+
+	if c.Token != "" && (c.Username != "" || c.Password != "") {
+		return fmt.Errorf("only one of token or username and password can be set")
+	}
+
+	if (c.Username == "") != (c.Password == "") {
+		return fmt.Errorf("username and password must be set together")
+	}
+
+	return nil
+}
+
+// PushFunc implements component.Registry
+func (r *Registry) PushFunc() interface{} {
+	return r.Push
+}
+
+// Config is the configuration structure for the registry.
+type Config struct {
+	// Image is the repository to push the image to.
+	Image string `hcl:"image,attr"`
+
+	// Tag is the tag to apply to the image.
+	Tag string `hcl:"tag,attr"`
+
+	// Username and Password authenticate with basic auth.
+	Username string `hcl:"username,optional"`
+	Password string `hcl:"password,optional"`
+
+	// Token authenticates with a bearer token.
+	Token string `hcl:"token,optional"`
+
+	// Insecure allows pushing to a registry over plain HTTP.
+	Insecure bool `hcl:"insecure,optional"`
+}
+
+// Push pushes the image at the path of the artifact to the registry.
+func (r *Registry) Push(
+	ctx context.Context,
+	log hclog.Logger,
+	src *files.Files,
+	ui terminal.UI,
+) (*docker.Image, error) {
+	sg := ui.StepGroup()
+	step := sg.Add("Reading image from %s...", src.Path)
+	defer func() { step.Abort() }()
+
+	var opts []name.Option
+	if r.config.Insecure {
+		opts = append(opts, name.Insecure)
+	}
+
+	ref, err := name.NewTag(r.config.Image+":"+r.config.Tag, opts...)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid image name: %s", err)
+	}
+
+	art, err := loadArtifact(src.Path)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "unable to load image: %s", err)
+	}
+
+	digest, err := art.Digest()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to compute image digest: %s", err)
+	}
+
+	step.Done()
+	step = sg.Add("Pushing image %s...", ref.String())
+
+	remoteOpts := []remote.Option{
+		remote.WithAuth(r.authenticator(ref)),
+	}
+
+	log.Debug("pushing image", "ref", ref.String(), "digest", digest.String(), "index", art.Index != nil)
+	if art.Index != nil {
+		err = remote.WriteIndex(ref, art.Index, remoteOpts...)
+	} else {
+		err = remote.Write(ref, art.Image, remoteOpts...)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to push image: %s", err)
+	}
+
+	step.Update("Image pushed: %s@%s", ref.String(), digest.String())
+	step.Done()
+
+	return &docker.Image{
+		Image: r.config.Image,
+		Tag:   r.config.Tag,
+	}, nil
+}
+
+// authenticator returns the authenticator for the configured credentials,
+// falling back to the credentials in the Docker configuration file.
+func (r *Registry) authenticator(ref name.Reference) authn.Authenticator {
+	switch {
+	case r.config.Token != "":
+		return &authn.Bearer{Token: r.config.Token}
+
+	case r.config.Username != "":
+		return &authn.Basic{
+			Username: r.config.Username,
+			Password: r.config.Password,
+		}
+	}
+
+	auth, err := authn.DefaultKeychain.Resolve(ref.Context().Registry)
+	if err != nil {
+		return authn.Anonymous
+	}
+
+	return auth
+}
+
+func (r *Registry) Documentation() (*docs.Documentation, error) {
+	doc, err := docs.New(docs.FromConfig(&Config{}))
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Description(`
+Push an OCI image layout or image tarball to a registry without Docker
+
+The artifact path is read as an OCI image layout if it is a directory, or as
+an image tarball as created by "docker save" if it is a file. A layout with
+more than one image, such as a multi-platform image, is pushed as an index.
+
+This is typically used with the "exec" builder for tools that write an image
+to disk rather than to a Docker daemon.
+`)
+
+	doc.Example(`
+build {
+  use "exec" {
+    command = ["buildah", "push", "myapp", "oci:image"]
+
+    output {
+      path = "image"
+    }
+  }
+
+  registry {
+    use "oci" {
+      image = "registry.example.com/myapp"
+      tag   = "latest"
+    }
+  }
+}
+`)
+
+	doc.Input("files.Files")
+	doc.Output("docker.Image")
+
+	doc.SetField(
+		"image",
+		"the repository to push the image to",
+	)
+
+	doc.SetField(
+		"tag",
+		"the tag to apply to the image",
+	)
+
+	doc.SetField(
+		"username",
+		"the username to authenticate with using basic auth",
+		docs.Summary(
+			"if neither username or token are set, the credentials for the",
+			"registry in the Docker configuration file are used",
+		),
+	)
+
+	doc.SetField(
+		"password",
+		"the password to authenticate with using basic auth",
+	)
+
+	doc.SetField(
+		"token",
+		"a bearer token to authenticate with",
+	)
+
+	doc.SetField(
+		"insecure",
+		"if set, the registry is accessed over plain HTTP",
+	)
+
+	return doc, nil
+}
+
+var (
+	_ component.Registry     = (*Registry)(nil)
+	_ component.Configurable = (*Registry)(nil)
+	_ component.Documented   = (*Registry)(nil)
+)
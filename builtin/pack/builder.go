@@ -1,9 +1,10 @@
 package pack
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/buildpacks/pack"
@@ -11,7 +12,6 @@ import (
 	"github.com/hashicorp/waypoint-plugin-sdk/component"
 	"github.com/hashicorp/waypoint-plugin-sdk/docs"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
-	"github.com/hashicorp/waypoint/internal/assets"
 	"github.com/hashicorp/waypoint/internal/pkg/epinject"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -33,14 +33,41 @@ type BuilderConfig struct {
 	// Control whether or not to inject the entrypoint binary into the resulting image
 	DisableCEB bool `hcl:"disable_entrypoint,optional"`
 
+	// EntrypointMode is how the entrypoint is added to the image, see
+	// epinject.Mode. This defaults to injecting it.
+	EntrypointMode string `hcl:"entrypoint_mode,optional"`
+
 	// The Buildpack builder image to use, defaults to the standard heroku one.
 	Builder string `hcl:"builder,optional"`
 
+	// The exact buildpacks to use, in order. If set, the builder will run these
+	// buildpacks in the specified order instead of detecting them. Entries can be
+	// buildpack IDs, local paths relative to the application or URIs.
+	Buildpacks []string `hcl:"buildpacks,optional"`
+
+	// Trust the builder so the lifecycle runs in a single container with access
+	// to registry credentials. This should only be set for builders that are
+	// known to be safe.
+	TrustBuilder bool `hcl:"trust_builder,optional"`
+
+	// The run image to use for the resulting image, defaults to the run image
+	// of the builder's stack.
+	RunImage string `hcl:"run_image,optional"`
+
 	// Environment variables that are meant to configure the application in a static
 	// way. This might be control an image that has mulitple modes of operation,
 	// selected via environment variable. Most configuration should use the waypoint
 	// config commands.
 	StaticEnvVars map[string]string `hcl:"static_environment,optional"`
+
+	// Environment variables that are only exposed to the buildpacks during the
+	// build, such as BP_JVM_VERSION. These take precedence over static_environment.
+	BuildEnv map[string]string `hcl:"build_env,optional"`
+
+	// Clear the buildpack layer cache before building. The cache is kept in
+	// Docker volumes named after the image so dependencies are reused between
+	// builds of the same application.
+	ClearCache bool `hcl:"clear_cache,optional"`
 }
 
 const DefaultBuilder = "heroku/buildpacks:18"
@@ -61,6 +88,11 @@ func (b *Builder) Build(
 	jobInfo *component.JobInfo,
 	src *component.Source,
 ) (*DockerImage, error) {
+	mode, err := epinject.ParseMode(b.config.EntrypointMode, b.config.DisableCEB)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
 	builder := b.config.Builder
 	if builder == "" {
 		builder = DefaultBuilder
@@ -86,10 +118,14 @@ func (b *Builder) Build(
 	step.Done()
 
 	err = client.Build(ctx, pack.BuildOptions{
-		Image:   src.App,
-		Builder: builder,
-		AppPath: src.Path,
-		Env:     b.config.StaticEnvVars,
+		Image:        src.App,
+		Builder:      builder,
+		AppPath:      src.Path,
+		Env:          buildEnv(&b.config),
+		Buildpacks:   buildpacks(src.Path, b.config.Buildpacks),
+		TrustBuilder: b.config.TrustBuilder,
+		RunImage:     b.config.RunImage,
+		ClearCache:   b.config.ClearCache,
 		FileFilter: func(file string) bool {
 			// Do not include the bolt.db or bolt.db.lock
 			// These files hold the local state when Waypoint is running without a server
@@ -132,6 +168,13 @@ func (b *Builder) Build(
 		}
 	}
 
+	// Export the bill of materials reported by the buildpacks so it is
+	// stored with the build.
+	sbom, err := buildSBOM(src.App, info)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to generate SBOM: %s", err)
+	}
+
 	labels["common/languages"] = strings.Join(languages, ",")
 	labels["common/buildpack-stack"] = info.StackID
 
@@ -155,34 +198,11 @@ func (b *Builder) Build(
 		}
 	}
 
-	if !b.config.DisableCEB {
+	if mode != epinject.ModeDisable {
 		inject := sg.Add("Injecting entrypoint binary to image")
 		defer inject.Abort()
 
-		asset, err := assets.Asset("ceb/ceb")
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
-		}
-
-		assetInfo, err := assets.AssetInfo("ceb/ceb")
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to restore custom entry point binary: %s", err)
-		}
-
-		imageId, err := epinject.AlterEntrypoint(ctx, src.App+":latest", func(cur []string) (*epinject.NewEntrypoint, error) {
-			ep := &epinject.NewEntrypoint{
-				Entrypoint: append([]string{"/waypoint-entrypoint"}, cur...),
-				InjectFiles: map[string]epinject.InjectFile{
-					"/waypoint-entrypoint": epinject.InjectFile{
-						Reader: bytes.NewReader(asset),
-						Info:   assetInfo,
-					},
-				},
-			}
-
-			return ep, nil
-		})
-
+		imageId, err := epinject.Inject(ctx, src.App+":latest", mode)
 		if err != nil {
 			return nil, err
 		}
@@ -203,9 +223,51 @@ func (b *Builder) Build(
 		Image:       src.App,
 		Tag:         "latest", // It always tags latest
 		BuildLabels: labels,
+		Sbom:        sbom,
 	}, nil
 }
 
+// buildEnv returns the environment of the build, build_env takes
+// precedence over static_environment.
+func buildEnv(cfg *BuilderConfig) map[string]string {
+	env := map[string]string{}
+	for k, v := range cfg.StaticEnvVars {
+		env[k] = v
+	}
+	for k, v := range cfg.BuildEnv {
+		env[k] = v
+	}
+
+	return env
+}
+
+// buildpacks returns the buildpacks to pass to pack. Buildpacks that are
+// paths relative to the application are made absolute since pack resolves
+// them relative to the working directory of the process.
+func buildpacks(appPath string, bps []string) []string {
+	if len(bps) == 0 {
+		return nil
+	}
+
+	result := make([]string, len(bps))
+	for i, bp := range bps {
+		result[i] = bp
+
+		if strings.Contains(bp, "://") || filepath.IsAbs(bp) {
+			continue
+		}
+
+		path := filepath.Join(appPath, bp)
+		if _, err := os.Stat(path); err == nil {
+			if abs, err := filepath.Abs(path); err == nil {
+				result[i] = abs
+			}
+		}
+	}
+
+	return result
+}
+
 func (b *Builder) Documentation() (*docs.Documentation, error) {
 	doc, err := docs.New(docs.FromConfig(&BuilderConfig{}))
 	if err != nil {
@@ -219,6 +281,11 @@ build {
   use "pack" {
 	builder     = "heroku/buildpacks:18"
 	disable_entrypoint = false
+	buildpacks  = ["heroku/nodejs", "./buildpacks/custom"]
+
+	build_env = {
+	  "BP_JVM_VERSION" = "11"
+	}
   }
 }
 `)
@@ -241,12 +308,68 @@ build {
 		),
 	)
 
+	doc.SetField(
+		"entrypoint_mode",
+		"how the entrypoint binary is added to the image",
+		docs.Summary(
+			"\"inject\" adds the binary and runs the buildpack process with it,",
+			"\"binary\" only adds the binary at /waypoint-entrypoint, and",
+			"\"disable\" builds the image without it. The binary is chosen for",
+			"the platform of the run image",
+		),
+		docs.Default("inject"),
+	)
+
 	doc.SetField(
 		"builder",
 		"The buildpack builder image to use",
 		docs.Default(DefaultBuilder),
 	)
 
+	doc.SetField(
+		"buildpacks",
+		"the buildpacks to run, in order",
+		docs.Summary(
+			"if set, these buildpacks are used instead of the buildpacks detected",
+			"by the builder. Entries can be buildpack IDs, paths relative to the",
+			"application or URIs of buildpack archives",
+		),
+	)
+
+	doc.SetField(
+		"trust_builder",
+		"if set, the builder is trusted and the lifecycle runs in a single container",
+		docs.Summary(
+			"trusted builders have access to registry credentials, only set this",
+			"for builders that are known to be safe",
+		),
+	)
+
+	doc.SetField(
+		"build_env",
+		"environment variables to expose to the buildpacks during the build",
+		docs.Summary(
+			"use this to pass build flags such as BP_JVM_VERSION. These take",
+			"precedence over static_environment",
+		),
+	)
+
+	doc.SetField(
+		"clear_cache",
+		"if set, the buildpack layer cache is cleared before building",
+		docs.Summary(
+			"the cache is kept in Docker volumes named after the image, so",
+			"dependencies such as the Maven or npm caches are reused between",
+			"builds of the same application unless this is set",
+		),
+	)
+
+	doc.SetField(
+		"run_image",
+		"the run image to base the resulting image on",
+		docs.Summary("defaults to the run image of the builder's stack"),
+	)
+
 	doc.SetField(
 		"static_environment",
 		"environment variables to expose to the buildpack",
@@ -0,0 +1,44 @@
+package pack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildEnv(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Config   BuilderConfig
+		Expected map[string]string
+	}{
+		{
+			"empty",
+			BuilderConfig{},
+			map[string]string{},
+		},
+
+		{
+			"static only",
+			BuilderConfig{
+				StaticEnvVars: map[string]string{"PORT": "3000"},
+			},
+			map[string]string{"PORT": "3000"},
+		},
+
+		{
+			"build env overrides static",
+			BuilderConfig{
+				StaticEnvVars: map[string]string{"PORT": "3000", "BP_JVM_VERSION": "8"},
+				BuildEnv:      map[string]string{"BP_JVM_VERSION": "11"},
+			},
+			map[string]string{"PORT": "3000", "BP_JVM_VERSION": "11"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require.Equal(t, tt.Expected, buildEnv(&tt.Config))
+		})
+	}
+}
@@ -34,6 +34,9 @@ type DockerImage struct {
 	Image       string            `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
 	Tag         string            `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
 	BuildLabels map[string]string `protobuf:"bytes,3,rep,name=build_labels,json=buildLabels,proto3" json:"build_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// sbom is the software bill of materials for the image, generated
+	// from the bill of materials reported by the buildpacks.
+	Sbom *SBOM `protobuf:"bytes,4,opt,name=sbom,proto3" json:"sbom,omitempty"`
 }
 
 func (x *DockerImage) Reset() {
@@ -89,12 +92,77 @@ func (x *DockerImage) GetBuildLabels() map[string]string {
 	return nil
 }
 
+func (x *DockerImage) GetSbom() *SBOM {
+	if x != nil {
+		return x.Sbom
+	}
+	return nil
+}
+
+// SBOM is a software bill of materials document.
+type SBOM struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// format of the document, currently always "cyclonedx-json".
+	Format string `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	// data is the encoded document.
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *SBOM) Reset() {
+	*x = SBOM{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_waypoint_builtin_pack_plugin_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SBOM) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SBOM) ProtoMessage() {}
+
+func (x *SBOM) ProtoReflect() protoreflect.Message {
+	mi := &file_waypoint_builtin_pack_plugin_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SBOM.ProtoReflect.Descriptor instead.
+func (*SBOM) Descriptor() ([]byte, []int) {
+	return file_waypoint_builtin_pack_plugin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SBOM) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *SBOM) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 var File_waypoint_builtin_pack_plugin_proto protoreflect.FileDescriptor
 
 var file_waypoint_builtin_pack_plugin_proto_rawDesc = []byte{
 	0x0a, 0x22, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x74,
 	0x69, 0x6e, 0x2f, 0x70, 0x61, 0x63, 0x6b, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x70, 0x61, 0x63, 0x6b, 0x22, 0xbc, 0x01, 0x0a, 0x0b, 0x44,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x70, 0x61, 0x63, 0x6b, 0x22, 0xdc, 0x01, 0x0a, 0x0b, 0x44,
 	0x6f, 0x63, 0x6b, 0x65, 0x72, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d,
 	0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65,
 	0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74,
@@ -102,13 +170,18 @@ var file_waypoint_builtin_pack_plugin_proto_rawDesc = []byte{
 	0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x61, 0x63, 0x6b, 0x2e,
 	0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x2e, 0x42, 0x75, 0x69, 0x6c,
 	0x64, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x62, 0x75,
-	0x69, 0x6c, 0x64, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x42, 0x75, 0x69,
+	0x69, 0x6c, 0x64, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x73, 0x62, 0x6f,
+	0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x61, 0x63, 0x6b, 0x2e, 0x53,
+	0x42, 0x4f, 0x4d, 0x52, 0x04, 0x73, 0x62, 0x6f, 0x6d, 0x1a, 0x3e, 0x0a, 0x10, 0x42, 0x75, 0x69,
 	0x6c, 0x64, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
 	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
 	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x17, 0x5a, 0x15, 0x77, 0x61, 0x79,
-	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x74, 0x69, 0x6e, 0x2f, 0x70, 0x61,
-	0x63, 0x6b, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x32, 0x0a, 0x04, 0x53, 0x42, 0x4f,
+	0x4d, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x42, 0x17, 0x5a,
+	0x15, 0x77, 0x61, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x74, 0x69,
+	0x6e, 0x2f, 0x70, 0x61, 0x63, 0x6b, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -123,18 +196,20 @@ func file_waypoint_builtin_pack_plugin_proto_rawDescGZIP() []byte {
 	return file_waypoint_builtin_pack_plugin_proto_rawDescData
 }
 
-var file_waypoint_builtin_pack_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_waypoint_builtin_pack_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_waypoint_builtin_pack_plugin_proto_goTypes = []interface{}{
 	(*DockerImage)(nil), // 0: pack.DockerImage
-	nil,                 // 1: pack.DockerImage.BuildLabelsEntry
+	(*SBOM)(nil),        // 1: pack.SBOM
+	nil,                 // 2: pack.DockerImage.BuildLabelsEntry
 }
 var file_waypoint_builtin_pack_plugin_proto_depIdxs = []int32{
-	1, // 0: pack.DockerImage.build_labels:type_name -> pack.DockerImage.BuildLabelsEntry
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 0: pack.DockerImage.build_labels:type_name -> pack.DockerImage.BuildLabelsEntry
+	1, // 1: pack.DockerImage.sbom:type_name -> pack.SBOM
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_waypoint_builtin_pack_plugin_proto_init() }
@@ -155,6 +230,18 @@ func file_waypoint_builtin_pack_plugin_proto_init() {
 				return nil
 			}
 		}
+		file_waypoint_builtin_pack_plugin_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SBOM); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -162,7 +249,7 @@ func file_waypoint_builtin_pack_plugin_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_waypoint_builtin_pack_plugin_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,84 @@
+package pack
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/buildpacks/pack"
+)
+
+// sbomFormatCycloneDX is the format of SBOM documents generated by the builder.
+const sbomFormatCycloneDX = "cyclonedx-json"
+
+type cdxDocument struct {
+	BOMFormat   string         `json:"bomFormat"`
+	SpecVersion string         `json:"specVersion"`
+	Version     int            `json:"version"`
+	Metadata    cdxMetadata    `json:"metadata"`
+	Components  []cdxComponent `json:"components"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxComponent struct {
+	BOMRef    string `json:"bom-ref,omitempty"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	Publisher string `json:"publisher,omitempty"`
+	PURL      string `json:"purl,omitempty"`
+}
+
+// buildSBOM converts the bill of materials the buildpacks reported for the
+// image into a CycloneDX document. Each entry becomes a component published
+// by the buildpack that contributed it.
+func buildSBOM(image string, info *pack.ImageInfo) (*SBOM, error) {
+	doc := cdxDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.2",
+		Version:     1,
+		Metadata: cdxMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Component: cdxComponent{
+				Type: "container",
+				Name: image,
+			},
+		},
+		Components: []cdxComponent{},
+	}
+
+	for _, entry := range info.BOM {
+		version := entry.Version
+		if version == "" {
+			// Newer buildpacks report the version in the metadata
+			if v, ok := entry.Metadata["version"].(string); ok {
+				version = v
+			}
+		}
+
+		// Some buildpacks report the package URL of the dependency
+		purl, _ := entry.Metadata["purl"].(string)
+
+		doc.Components = append(doc.Components, cdxComponent{
+			BOMRef:    entry.Buildpack.ID + "/" + entry.Name,
+			Type:      "library",
+			Name:      entry.Name,
+			Version:   version,
+			Publisher: entry.Buildpack.ID,
+			PURL:      purl,
+		})
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SBOM{
+		Format: sbomFormatCycloneDX,
+		Data:   data,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package pack
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/buildpacks/pack"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSBOM(t *testing.T) {
+	cases := []struct {
+		Name     string
+		BOM      string
+		Expected []cdxComponent
+	}{
+		{
+			"no BOM",
+			`null`,
+			[]cdxComponent{},
+		},
+
+		{
+			"no entries",
+			`[]`,
+			[]cdxComponent{},
+		},
+
+		{
+			"version on the entry",
+			`[{
+				"name": "node",
+				"version": "14.15.1",
+				"buildpack": {"id": "paketo-buildpacks/node-engine", "version": "0.1.0"}
+			}]`,
+			[]cdxComponent{
+				{
+					BOMRef:    "paketo-buildpacks/node-engine/node",
+					Type:      "library",
+					Name:      "node",
+					Version:   "14.15.1",
+					Publisher: "paketo-buildpacks/node-engine",
+				},
+			},
+		},
+
+		{
+			"version in the metadata",
+			`[{
+				"name": "jre",
+				"metadata": {"version": "11.0.9"},
+				"buildpack": {"id": "paketo-buildpacks/bellsoft-liberica"}
+			}]`,
+			[]cdxComponent{
+				{
+					BOMRef:    "paketo-buildpacks/bellsoft-liberica/jre",
+					Type:      "library",
+					Name:      "jre",
+					Version:   "11.0.9",
+					Publisher: "paketo-buildpacks/bellsoft-liberica",
+				},
+			},
+		},
+
+		{
+			"purl in the metadata",
+			`[{
+				"name": "jre",
+				"metadata": {
+					"version": "11.0.9",
+					"purl": "pkg:generic/bellsoft-jre@11.0.9?arch=amd64"
+				},
+				"buildpack": {"id": "paketo-buildpacks/bellsoft-liberica"}
+			}]`,
+			[]cdxComponent{
+				{
+					BOMRef:    "paketo-buildpacks/bellsoft-liberica/jre",
+					Type:      "library",
+					Name:      "jre",
+					Version:   "11.0.9",
+					Publisher: "paketo-buildpacks/bellsoft-liberica",
+					PURL:      "pkg:generic/bellsoft-jre@11.0.9?arch=amd64",
+				},
+			},
+		},
+
+		{
+			"no version or purl",
+			`[{
+				"name": "launcher",
+				"metadata": {"version": 2, "purl": false},
+				"buildpack": {"id": "paketo-buildpacks/procfile"}
+			}]`,
+			[]cdxComponent{
+				{
+					BOMRef:    "paketo-buildpacks/procfile/launcher",
+					Type:      "library",
+					Name:      "launcher",
+					Publisher: "paketo-buildpacks/procfile",
+				},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			var info pack.ImageInfo
+			require.NoError(json.Unmarshal([]byte(`{"BOM": `+tt.BOM+`}`), &info))
+
+			sbom, err := buildSBOM("example/app:latest", &info)
+			require.NoError(err)
+			require.Equal(sbomFormatCycloneDX, sbom.Format)
+
+			var doc cdxDocument
+			require.NoError(json.Unmarshal(sbom.Data, &doc))
+			require.Equal("CycloneDX", doc.BOMFormat)
+			require.Equal("1.2", doc.SpecVersion)
+			require.Equal(cdxComponent{
+				Type: "container",
+				Name: "example/app:latest",
+			}, doc.Metadata.Component)
+			require.NotEmpty(doc.Metadata.Timestamp)
+			require.Equal(tt.Expected, doc.Components)
+		})
+	}
+}
@@ -41,6 +41,10 @@ func realMain() int {
 	err := ceb.Run(ctx,
 		ceb.WithEnvDefaults(),
 		ceb.WithExec(args))
+	if code, ok := ceb.ExitCode(err); ok {
+		// The child command exited, so we exit the same way.
+		return code
+	}
 	if err != nil {
 		fmt.Fprintf(flag.CommandLine.Output(),
 			"Error initializing Waypoint entrypoint: %s\n", formatError(err))
@@ -1,6 +1,7 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
 // ceb/ceb (20.086MB)
+// ceb/ceb-linux-arm64 (19.333MB)
 
 // +build !assetsembedded
 
@@ -48,6 +49,24 @@ func cebCeb() (*asset, error) {
 	return a, err
 }
 
+// cebCebLinuxArm64 reads file data from disk. It returns an error on failure.
+func cebCebLinuxArm64() (*asset, error) {
+	path := filepath.Join(rootDir, "ceb/ceb-linux-arm64")
+	name := "ceb/ceb-linux-arm64"
+	bytes, err := bindataRead(path, name)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		err = fmt.Errorf("Error reading asset info %s at %s: %w", name, path, err)
+	}
+
+	a := &asset{bytes: bytes, info: fi}
+	return a, err
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -139,7 +158,8 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"ceb/ceb": cebCeb,
+	"ceb/ceb":             cebCeb,
+	"ceb/ceb-linux-arm64": cebCebLinuxArm64,
 }
 
 // AssetDir returns the file names below a certain
@@ -184,7 +204,8 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"ceb": &bintree{nil, map[string]*bintree{
-		"ceb": &bintree{cebCeb, map[string]*bintree{}},
+		"ceb":             &bintree{cebCeb, map[string]*bintree{}},
+		"ceb-linux-arm64": &bintree{cebCebLinuxArm64, map[string]*bintree{}},
 	}},
 }}
 
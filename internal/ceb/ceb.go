@@ -3,11 +3,18 @@ package ceb
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/hashicorp/go-hclog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -22,9 +29,15 @@ const (
 	envServerAddr          = "WAYPOINT_SERVER_ADDR"
 	envServerTls           = "WAYPOINT_SERVER_TLS"
 	envServerTlsSkipVerify = "WAYPOINT_SERVER_TLS_SKIP_VERIFY"
+	envServerTlsCA         = "WAYPOINT_SERVER_TLS_CA"
+	envServerTlsCAFile     = "WAYPOINT_SERVER_TLS_CA_FILE"
+	envServerTlsCertFile   = "WAYPOINT_SERVER_TLS_CERT_FILE"
+	envServerTlsKeyFile    = "WAYPOINT_SERVER_TLS_KEY_FILE"
 	envCEBDisable          = "WAYPOINT_CEB_DISABLE"
 	envCEBServerRequired   = "WAYPOINT_CEB_SERVER_REQUIRED"
 	envCEBToken            = "WAYPOINT_CEB_INVITE_TOKEN"
+	envCEBLogBufferDir     = "WAYPOINT_CEB_LOG_BUFFER_DIR"
+	envCEBLogBufferSize    = "WAYPOINT_CEB_LOG_BUFFER_SIZE"
 )
 
 const (
@@ -41,6 +54,24 @@ type CEB struct {
 	childCmd     *exec.Cmd
 	execIdx      int64
 
+	// config is the latest config from the server. restartCh receives the
+	// environment to restart the child command with when the dynamic config
+	// changes and signalCh the signals to send it when config files change.
+	configLock  sync.Mutex
+	config      *pb.EntrypointConfig
+	refreshOnce sync.Once
+	restartCh   chan []string
+	signalCh    chan os.Signal
+
+	// readyCh is closed once the app passes its first health check, or
+	// right away if it has none. See runHealthChecks.
+	readyCh   chan struct{}
+	readyOnce sync.Once
+
+	// child is the process of the child command for reporting metrics.
+	childLock sync.Mutex
+	child     childProcess
+
 	cleanupFunc func()
 }
 
@@ -49,7 +80,7 @@ type CEB struct {
 // This will run until the context is cancelled. If the context is cancelled,
 // we will attempt to gracefully exit the underlying program and attempt to
 // clean up all resources.
-func Run(ctx context.Context, os ...Option) error {
+func Run(ctx context.Context, opts ...Option) error {
 	// Create our ID
 	id, err := server.Id()
 	if err != nil {
@@ -59,15 +90,18 @@ func Run(ctx context.Context, os ...Option) error {
 
 	// Defaults, initialization
 	ceb := &CEB{
-		id:      id,
-		logger:  hclog.L(),
-		context: ctx,
+		id:        id,
+		logger:    hclog.L(),
+		context:   ctx,
+		restartCh: make(chan []string),
+		signalCh:  make(chan os.Signal),
+		readyCh:   make(chan struct{}),
 	}
 	defer ceb.Close()
 
 	// Set our options
 	var cfg config
-	for _, o := range os {
+	for _, o := range opts {
 		err := o(ceb, &cfg)
 		if err != nil {
 			return err
@@ -102,19 +136,76 @@ func Run(ctx context.Context, os ...Option) error {
 		}
 	}
 
-	// Run our subprocess
-	errCh := ceb.execChildCmd(ctx)
-	select {
-	case err := <-errCh:
-		return err
-
-	case <-ctx.Done():
-		ceb.logger.Info("received cancellation request, gracefully exiting")
-		ceb.childCmd.Process.Kill()
-		<-errCh
+	// If we are PID 1, such as in a container, orphaned processes are
+	// reparented to us and we have to reap them.
+	if os.Getpid() == 1 {
+		go reapZombies(ctx, ceb.logger.Named("reaper"))
 	}
 
-	return nil
+	// Run our subprocess. If the dynamic config changes, it is restarted,
+	// and if a config file changes, it is signaled. If it crashes, it is
+	// restarted if the restart policy allows it.
+	errCh := ceb.execChildCmd(ctx)
+	started := time.Now()
+	crashes := 0
+	for {
+		select {
+		case err := <-errCh:
+			ceb.configLock.Lock()
+			policy, perr := restartPolicyFor(&cfg, ceb.config)
+			ceb.configLock.Unlock()
+			if perr != nil {
+				ceb.logger.Warn("invalid restart policy, using the defaults for invalid settings", "err", perr)
+			}
+
+			if !crashed(err) || !policy.RestartOnCrash {
+				return err
+			}
+
+			// Crashes only count as in a row if the child didn't run for
+			// long in between.
+			if time.Since(started) > crashResetTime {
+				crashes = 0
+			}
+			crashes++
+			if policy.MaxRetries > 0 && crashes > policy.MaxRetries {
+				ceb.logger.Error("child process crashed too many times, exiting",
+					"retries", policy.MaxRetries)
+				return err
+			}
+
+			backoff := crashBackoff(crashes)
+			ceb.logger.Info("child process crashed, restarting", "backoff", backoff, "crashes", crashes)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return nil
+			}
+
+			if err := ceb.replaceChildCmd(ctx, &cfg, ceb.childCmd.Env); err != nil {
+				return err
+			}
+			errCh = ceb.execChildCmd(ctx)
+			started = time.Now()
+
+		case env := <-ceb.restartCh:
+			errCh, err = ceb.restartChildCmd(ctx, &cfg, errCh, env)
+			if err != nil {
+				return err
+			}
+
+		case sig := <-ceb.signalCh:
+			if err := ceb.childCmd.Process.Signal(sig); err != nil {
+				ceb.logger.Warn("error signaling child process", "signal", sig, "err", err)
+			}
+
+		case <-ctx.Done():
+			ceb.logger.Info("received cancellation request, gracefully exiting")
+			ceb.childCmd.Process.Kill()
+			<-errCh
+			return nil
+		}
+	}
 }
 
 // Close cleans up any resources created by the CEB and should be called
@@ -153,7 +244,52 @@ type config struct {
 	ServerTlsSkipVerify bool
 	InviteToken         string
 
+	// ServerTlsCAs verify the server instead of the system roots if set and
+	// ServerTlsCert is the client certificate to connect with.
+	ServerTlsCAs  *x509.CertPool
+	ServerTlsCert *tls.Certificate
+
+	// ServerProxy is the HTTP proxy to connect to the server through. If
+	// this isn't set, HTTPS_PROXY and NO_PROXY are used. See serverProxy.
+	ServerProxy *url.URL
+
 	URLServicePort int
+
+	// ReloadSignal, StopSignal, StopTimeout, RestartOnCrash and
+	// RestartMaxRetries are the restart policy from the environment.
+	// Config variables with the same names take precedence. See
+	// restartPolicyFor.
+	ReloadSignal      string
+	StopSignal        string
+	StopTimeout       string
+	RestartOnCrash    string
+	RestartMaxRetries string
+
+	// LogBufferDir and LogBufferSize are where and how much of the logs are
+	// buffered on disk while the entrypoint is disconnected from the
+	// server. See logBuffer.
+	LogBufferDir  string
+	LogBufferSize uint64
+
+	// DisableExec, DisableLogs, DisableURL and DisableLogParsing disable
+	// single features of the entrypoint from the environment. Config
+	// variables with the same names take precedence. See
+	// disabledFeaturesFor.
+	DisableExec       string
+	DisableLogs       string
+	DisableURL        string
+	DisableLogParsing string
+
+	// HealthCheck and HealthInterval are the health check from the
+	// environment. Config variables with the same names take precedence.
+	// See healthCheckFor.
+	HealthCheck    string
+	HealthInterval string
+
+	// ConfigRefreshInterval is how often the dynamic config is read again
+	// from the environment. A config variable with the same name takes
+	// precedence. See configRefreshIntervalFor.
+	ConfigRefreshInterval string
 }
 
 type Option func(*CEB, *config) error
@@ -183,6 +319,67 @@ func WithEnvDefaults() Option {
 		cfg.ServerTls = os.Getenv(envServerTls) != ""
 		cfg.ServerTlsSkipVerify = os.Getenv(envServerTlsSkipVerify) != ""
 		cfg.InviteToken = os.Getenv(envCEBToken)
+		cfg.ReloadSignal = os.Getenv(envCEBReloadSignal)
+		cfg.StopSignal = os.Getenv(envCEBStopSignal)
+		cfg.StopTimeout = os.Getenv(envCEBStopTimeout)
+		cfg.RestartOnCrash = os.Getenv(envCEBRestartOnCrash)
+		cfg.RestartMaxRetries = os.Getenv(envCEBRestartMaxRetries)
+		cfg.DisableExec = os.Getenv(envCEBDisableExec)
+		cfg.DisableLogs = os.Getenv(envCEBDisableLogs)
+		cfg.DisableURL = os.Getenv(envCEBDisableURL)
+		cfg.DisableLogParsing = os.Getenv(envCEBDisableLogParsing)
+		cfg.HealthCheck = os.Getenv(envCEBHealthCheck)
+		cfg.HealthInterval = os.Getenv(envCEBHealthInterval)
+		cfg.ConfigRefreshInterval = os.Getenv(envCEBConfigRefreshInterval)
+
+		cfg.LogBufferDir = os.Getenv(envCEBLogBufferDir)
+		cfg.LogBufferSize = defaultLogBufferSize
+		if v := os.Getenv(envCEBLogBufferSize); v != "" {
+			size, err := humanize.ParseBytes(v)
+			if err != nil {
+				return fmt.Errorf("Invalid value of %s: %s", envCEBLogBufferSize, err)
+			}
+
+			cfg.LogBufferSize = size
+		}
+
+		// The CAs can be set in the environment directly, such as from a
+		// secret, or read from a file in the image. Both can be set.
+		if v := os.Getenv(envServerTlsCA); v != "" {
+			cfg.ServerTlsCAs = x509.NewCertPool()
+			if !cfg.ServerTlsCAs.AppendCertsFromPEM([]byte(v)) {
+				return fmt.Errorf("No certificates found in %s", envServerTlsCA)
+			}
+		}
+		if path := os.Getenv(envServerTlsCAFile); path != "" {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("Error reading %s: %s", envServerTlsCAFile, err)
+			}
+
+			if cfg.ServerTlsCAs == nil {
+				cfg.ServerTlsCAs = x509.NewCertPool()
+			}
+			if !cfg.ServerTlsCAs.AppendCertsFromPEM(data) {
+				return fmt.Errorf("No certificates found in %s", path)
+			}
+		}
+		if v := os.Getenv(envCEBServerProxy); v != "" {
+			proxy, err := parseProxy(v)
+			if err != nil {
+				return fmt.Errorf("Invalid value of %s: %s", envCEBServerProxy, err)
+			}
+
+			cfg.ServerProxy = proxy
+		}
+		if path := os.Getenv(envServerTlsCertFile); path != "" {
+			cert, err := tls.LoadX509KeyPair(path, os.Getenv(envServerTlsKeyFile))
+			if err != nil {
+				return fmt.Errorf("Error loading the client certificate: %s", err)
+			}
+
+			cfg.ServerTlsCert = &cert
+		}
 		cfg.disable = os.Getenv(envCEBDisable) != ""
 
 		ceb.deploymentId = os.Getenv(envDeploymentId)
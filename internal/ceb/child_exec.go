@@ -2,9 +2,12 @@ package ceb
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -35,15 +38,23 @@ func (ceb *CEB) execChildCmd(ctx context.Context) <-chan error {
 		"args", cmd.Args,
 	)
 	log.Info("starting child process")
-	if err := cmd.Start(); err != nil {
+	if err := startChild(cmd, cmd.Start); err != nil {
 		ch <- status.Errorf(codes.Aborted,
 			"failed to execute subprocess: %s", err)
 		return ch
 	}
 
+	ceb.childLock.Lock()
+	ceb.child = childProcess{
+		pid:     cmd.Process.Pid,
+		started: time.Now(),
+		starts:  ceb.child.starts + 1,
+	}
+	ceb.childLock.Unlock()
+
 	// Start a goroutine to wait for completion
 	go func() {
-		err := cmd.Wait()
+		err := waitChild(cmd)
 		if err == nil {
 			log.Info("subprocess gracefully exited")
 		} else {
@@ -56,6 +67,22 @@ func (ceb *CEB) execChildCmd(ctx context.Context) <-chan error {
 	return ch
 }
 
+// ExitCode returns the exit code of the child command if err is from it
+// exiting, so that the entrypoint can exit the same way. If it was killed
+// by a signal, this is 128 plus the signal number like in shells.
+func ExitCode(err error) (int, bool) {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 0, false
+	}
+
+	if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal()), true
+	}
+
+	return exitErr.ExitCode(), true
+}
+
 func (ceb *CEB) buildCmd(ctx context.Context, args []string) (*exec.Cmd, error) {
 	// Avoid a crash below by verifying we got some arguments.
 	if len(args) == 0 {
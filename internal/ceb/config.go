@@ -2,6 +2,7 @@ package ceb
 
 import (
 	"context"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -10,6 +11,11 @@ import (
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
+// serverShutdownDelay is how long the entrypoint waits to reconnect after
+// the server said that it is shutting down, so that it doesn't reconnect
+// to the server that is going away.
+var serverShutdownDelay = 2 * time.Second
+
 func (ceb *CEB) initConfigStream(ctx context.Context, cfg *config, isRetry bool) error {
 	log := ceb.logger.Named("config")
 
@@ -41,16 +47,50 @@ func (ceb *CEB) initConfigStream(ctx context.Context, cfg *config, isRetry bool)
 	if err != nil {
 		return err
 	}
+	if resp.ServerShutdown {
+		// We connected to a server that is going away, so try again once
+		// the next one is up.
+		log.Info("server is shutting down, will reconnect when it is back")
+		go func() {
+			select {
+			case <-time.After(serverShutdownDelay):
+				ceb.initConfigStream(ctx, cfg, true)
+			case <-ctx.Done():
+			}
+		}()
+		return nil
+	}
 	log.Trace("first config received")
 
-	// Modify childCmd to contain any passed variables as environment variables
-	for _, cv := range resp.Config.EnvVars {
-		ceb.childCmd.Env = append(ceb.childCmd.Env, cv.Name+"="+cv.Value)
+	// Modify childCmd to contain any passed variables as environment variables.
+	// Dynamic variables are read from their sources, and if some can't be read
+	// we still start the child command so that we don't block it.
+	static, dynamic, files, err := ceb.configEnv(ctx, resp.Config)
+	if err != nil {
+		log.Warn("error reading dynamic config, some variables will not be set", "err", err)
 	}
+	ceb.childCmd.Env = append(ceb.childCmd.Env, static...)
+	ceb.childCmd.Env = append(ceb.childCmd.Env, dynamic...)
+
+	// Write the config files before the child command starts. If we're
+	// reconnecting, the child command is already running and is signaled
+	// if they changed.
+	if isRetry {
+		ceb.updateConfigFiles(ctx, files)
+	} else if _, err := ceb.writeConfigFiles(files); err != nil {
+		log.Warn("error writing config files, some files will not be written", "err", err)
+	}
+
+	// Refresh the dynamic variables in the background. This is only started
+	// once even if we reconnect.
+	ceb.setConfig(resp.Config)
+	ceb.refreshOnce.Do(func() { go ceb.refreshConfig(ctx, cfg, dynamic) })
 
 	// If we have URL service configuration, start it. We start this in a goroutine
 	// since we don't need to block starting up our application on this.
-	if url := resp.Config.UrlService; url != nil {
+	if url := resp.Config.UrlService; url != nil && disabledFeaturesFor(cfg, resp.Config).URL {
+		log.Info("URL service disabled, will not register with URL service")
+	} else if url != nil {
 		go func() {
 			if err := ceb.initURLService(ctx, cfg.URLServicePort, url); err != nil {
 				log.Warn("error starting URL service", "err", err)
@@ -62,7 +102,7 @@ func (ceb *CEB) initConfigStream(ctx context.Context, cfg *config, isRetry bool)
 
 	// Start the watcher
 	ch := make(chan *pb.EntrypointConfig)
-	go ceb.watchConfig(ch)
+	go ceb.watchConfig(ctx, cfg, ch)
 
 	// Send the first config which will trigger setup
 	ch <- resp.Config
@@ -76,12 +116,23 @@ func (ceb *CEB) initConfigStream(ctx context.Context, cfg *config, isRetry bool)
 }
 
 // watchConfig sits in a goroutine receiving the new configurations from the
-// server.
-func (ceb *CEB) watchConfig(ch <-chan *pb.EntrypointConfig) {
+// server. Config files are updated right away since changing them doesn't
+// restart the child command.
+func (ceb *CEB) watchConfig(ctx context.Context, cfg *config, ch <-chan *pb.EntrypointConfig) {
+	log := ceb.logger.Named("config_watch")
 	for config := range ch {
-		// Start the exec sessions if we have any
+		ceb.setConfig(config)
+
+		_, _, files, err := ceb.configEnv(ctx, configFilesOnly(config))
+		if err != nil {
+			log.Warn("error reading dynamic config, some files will not be updated", "err", err)
+		}
+		ceb.updateConfigFiles(ctx, files)
+
+		// Start the exec sessions if we have any. If exec is disabled, the
+		// sessions are still opened to tell the server that they failed.
 		if len(config.Exec) > 0 {
-			ceb.startExecGroup(config.Exec)
+			ceb.startExecGroup(config.Exec, disabledFeaturesFor(cfg, config).Exec)
 		}
 	}
 }
@@ -96,6 +147,7 @@ func (ceb *CEB) recvConfig(
 	defer log.Trace("exiting receive goroutine")
 	defer close(ch)
 
+	shutdown := false
 	for {
 		// If the context is closed, exit
 		if ctx.Err() != nil {
@@ -106,8 +158,22 @@ func (ceb *CEB) recvConfig(
 		resp, err := client.Recv()
 		if err != nil {
 			// If we get the unavailable error then the connection died.
-			// We restablish the connection.
-			if status.Code(err) == codes.Unavailable {
+			// We restablish the connection. If the server told us that it
+			// is shutting down, this is expected and we wait for the next
+			// server before reconnecting.
+			if shutdown {
+				log.Info("server shut down, reconnecting")
+				select {
+				case <-time.After(serverShutdownDelay):
+				case <-ctx.Done():
+					return
+				}
+
+				err = reconnect()
+				if err == nil {
+					return
+				}
+			} else if status.Code(err) == codes.Unavailable {
 				log.Error("ceb disconnected from server, attempting reconnect")
 				err = reconnect()
 
@@ -123,6 +189,12 @@ func (ceb *CEB) recvConfig(
 			}
 		}
 
+		if resp.ServerShutdown {
+			log.Info("server is shutting down, will reconnect when it is back")
+			shutdown = true
+			continue
+		}
+
 		log.Info("new configuration received")
 		ch <- resp.Config
 	}
@@ -0,0 +1,151 @@
+package ceb
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-multierror"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// configFile is a config variable that is written to a file rather than
+// set as an environment variable.
+type configFile struct {
+	*pb.ConfigVar_FileVal
+
+	name  string
+	value string
+}
+
+// mode returns the permissions of the file.
+func (f *configFile) mode() os.FileMode {
+	if f.Mode == 0 {
+		return 0644
+	}
+
+	return os.FileMode(f.Mode).Perm()
+}
+
+// signal returns the signal to send when the file changes, or nil if
+// there is none.
+func (f *configFile) signal() (os.Signal, error) {
+	if f.Signal == "" {
+		return nil, nil
+	}
+
+	return parseSignal(f.Signal)
+}
+
+// write writes the file if its contents or permissions are different. The
+// file is written to a temporary file in the same directory first and
+// then renamed, so the child command never reads a partial file. This
+// returns true if the file was written.
+func (f *configFile) write() (bool, error) {
+	data := []byte(f.value)
+	mode := f.mode()
+	if info, err := os.Stat(f.Path); err == nil && info.Mode().Perm() == mode {
+		current, err := ioutil.ReadFile(f.Path)
+		if err == nil && bytes.Equal(current, data) {
+			return false, nil
+		}
+	}
+
+	dir := filepath.Dir(f.Path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(f.Path)+".*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+
+	return true, os.Rename(tmp.Name(), f.Path)
+}
+
+// writeConfigFiles writes the files that changed and returns the signals
+// to send to the child command for them. Each signal is only returned
+// once even if several files with it changed. If some files can't be
+// written, the others are still written.
+func (ceb *CEB) writeConfigFiles(files []*configFile) ([]os.Signal, error) {
+	log := ceb.logger.Named("config_file")
+
+	var result []os.Signal
+	var resultErr error
+	seen := map[os.Signal]bool{}
+	for _, f := range files {
+		sig, err := f.signal()
+		if err != nil {
+			resultErr = multierror.Append(resultErr, multierror.Prefix(err, f.name+":"))
+			continue
+		}
+
+		changed, err := f.write()
+		if err != nil {
+			resultErr = multierror.Append(resultErr, multierror.Prefix(err, f.name+":"))
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		log.Info("config file written", "name", f.name, "path", f.Path)
+		if sig != nil && !seen[sig] {
+			seen[sig] = true
+			result = append(result, sig)
+		}
+	}
+
+	return result, resultErr
+}
+
+// configFilesOnly returns the config with only the variables that are
+// written to files, so that the dynamic environment variables aren't read
+// when only the files are updated.
+func configFilesOnly(config *pb.EntrypointConfig) *pb.EntrypointConfig {
+	result := &pb.EntrypointConfig{ConfigSources: config.ConfigSources}
+	for _, cv := range config.EnvVars {
+		if cv.File != nil {
+			result.EnvVars = append(result.EnvVars, cv)
+		}
+	}
+
+	return result
+}
+
+// updateConfigFiles writes the files that changed and sends their signals
+// to the child command, which must have been started.
+func (ceb *CEB) updateConfigFiles(ctx context.Context, files []*configFile) {
+	log := ceb.logger.Named("config_file")
+
+	signals, err := ceb.writeConfigFiles(files)
+	if err != nil {
+		log.Warn("error writing config files", "err", err)
+	}
+
+	for _, sig := range signals {
+		log.Info("config file changed, signaling child command", "signal", sig)
+		select {
+		case ceb.signalCh <- sig:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
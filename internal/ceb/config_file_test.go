@@ -0,0 +1,119 @@
+package ceb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/internal/configsource"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestCEBConfigEnv_files(t *testing.T) {
+	require := require.New(t)
+
+	configsource.FromString["test"] = func(config map[string]string) (configsource.Sourcer, error) {
+		return testSourcer(config["prefix"]), nil
+	}
+	defer delete(configsource.FromString, "test")
+
+	ceb := &CEB{logger: hclog.L()}
+	static, dynamic, files, err := ceb.configEnv(context.Background(), &pb.EntrypointConfig{
+		EnvVars: []*pb.ConfigVar{
+			{Name: "PORT", Value: "8080"},
+			{
+				Name:  "APP_CONFIG",
+				Value: "port: 8080",
+				File:  &pb.ConfigVar_FileVal{Path: "/etc/app/config.yml"},
+			},
+			{
+				Name: "DB_CERT",
+				Dynamic: &pb.ConfigVar_DynamicVal{
+					From:   "test",
+					Config: map[string]string{"key": "cert"},
+				},
+				File: &pb.ConfigVar_FileVal{Path: "/etc/app/db.pem", Mode: 0600},
+			},
+		},
+		ConfigSources: []*pb.ConfigSource{{
+			Type:   "test",
+			Config: map[string]string{"prefix": "value-"},
+		}},
+	})
+	require.NoError(err)
+
+	// Files aren't set as environment variables
+	require.Equal([]string{"PORT=8080"}, static)
+	require.Empty(dynamic)
+	require.Len(files, 2)
+	require.Equal("port: 8080", files[0].value)
+	require.Equal("value-cert", files[1].value)
+	require.Equal(os.FileMode(0600), files[1].mode())
+}
+
+func TestCEBWriteConfigFiles(t *testing.T) {
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "ceb-config-file")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	ceb := &CEB{logger: hclog.L()}
+	path := filepath.Join(td, "app", "config.yml")
+	file := func(value string) []*configFile {
+		return []*configFile{{
+			ConfigVar_FileVal: &pb.ConfigVar_FileVal{Path: path, Mode: 0600, Signal: "hup"},
+			name:              "APP_CONFIG",
+			value:             value,
+		}}
+	}
+
+	// The file and its directory are created
+	signals, err := ceb.writeConfigFiles(file("port: 8080"))
+	require.NoError(err)
+	require.Equal([]os.Signal{syscall.SIGHUP}, signals)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal("port: 8080", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	// A file that didn't change isn't written or signaled
+	signals, err = ceb.writeConfigFiles(file("port: 8080"))
+	require.NoError(err)
+	require.Empty(signals)
+
+	// A file that changed is replaced
+	signals, err = ceb.writeConfigFiles(file("port: 9090"))
+	require.NoError(err)
+	require.Equal([]os.Signal{syscall.SIGHUP}, signals)
+
+	data, err = ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal("port: 9090", string(data))
+
+	// No temporary files are left behind
+	entries, err := ioutil.ReadDir(filepath.Dir(path))
+	require.NoError(err)
+	require.Len(entries, 1)
+
+	// Unknown signals are an error and the file isn't written
+	files := file("port: 7070")
+	files[0].Signal = "SIGNOPE"
+	_, err = ceb.writeConfigFiles(files)
+	require.Error(err)
+	require.Contains(err.Error(), "unknown signal")
+
+	data, err = ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal("port: 9090", string(data))
+}
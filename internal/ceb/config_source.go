@@ -0,0 +1,236 @@
+package ceb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/hashicorp/waypoint/internal/configsource"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const envCEBConfigRefreshInterval = "WAYPOINT_CEB_CONFIG_REFRESH_INTERVAL"
+
+var (
+	// configRefreshInterval is how often the dynamic config variables are
+	// read again from their sources unless the setting changes it. If any
+	// of them changed, the child command is restarted with the new values.
+	configRefreshInterval = 5 * time.Minute
+
+	// childStopTimeout is how long the child command has to exit when it
+	// is restarted before it is killed, unless the restart policy sets it.
+	childStopTimeout = 30 * time.Second
+)
+
+// configEnv returns the environment variables for the config. Dynamic
+// variables are read from their sources and returned separately, and the
+// variables that are written to files are returned as files. If some
+// can't be read, the others are still returned along with the error.
+func (ceb *CEB) configEnv(ctx context.Context, config *pb.EntrypointConfig) ([]string, []string, []*configFile, error) {
+	sources := map[string]*pb.ConfigSource{}
+	for _, source := range config.ConfigSources {
+		sources[source.Type] = source
+	}
+
+	var static, dynamic []string
+	var files []*configFile
+	var resultErr error
+	sourcers := map[string]configsource.Sourcer{}
+	for _, cv := range config.EnvVars {
+		if cv.Dynamic == nil {
+			if cv.File != nil {
+				files = append(files, &configFile{ConfigVar_FileVal: cv.File, name: cv.Name, value: cv.Value})
+				continue
+			}
+
+			static = append(static, cv.Name+"="+cv.Value)
+			continue
+		}
+
+		from := cv.Dynamic.From
+		sourcer, ok := sourcers[from]
+		if !ok {
+			var err error
+			sourcer, err = configsource.New(from, sources[from].GetConfig())
+			if err != nil {
+				resultErr = multierror.Append(resultErr, err)
+			}
+
+			// We keep the failed sources too so we don't retry them
+			// for every variable.
+			sourcers[from] = sourcer
+		}
+		if sourcer == nil {
+			continue
+		}
+
+		value, err := sourcer.Read(ctx, cv.Dynamic.Config)
+		if err != nil {
+			resultErr = multierror.Append(resultErr, multierror.Prefix(err, cv.Name+":"))
+			continue
+		}
+
+		if cv.File != nil {
+			files = append(files, &configFile{ConfigVar_FileVal: cv.File, name: cv.Name, value: value})
+			continue
+		}
+
+		dynamic = append(dynamic, cv.Name+"="+value)
+	}
+
+	sort.Strings(dynamic)
+	return static, dynamic, files, resultErr
+}
+
+// setConfig stores the latest config received from the server, which is
+// used when the dynamic variables are refreshed.
+func (ceb *CEB) setConfig(config *pb.EntrypointConfig) {
+	ceb.configLock.Lock()
+	defer ceb.configLock.Unlock()
+	ceb.config = config
+}
+
+// configRefreshIntervalFor returns how often the dynamic variables of the
+// config are read again. See settings for where the setting is read from.
+// The default is used for an invalid setting, which is returned as an
+// error.
+func configRefreshIntervalFor(cfg *config, ec *pb.EntrypointConfig) (time.Duration, error) {
+	values := settings(ec, map[string]string{
+		envCEBConfigRefreshInterval: cfg.ConfigRefreshInterval,
+	})
+
+	v := values[envCEBConfigRefreshInterval]
+	if v == "" {
+		return configRefreshInterval, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return configRefreshInterval, fmt.Errorf(
+			"%s: must be a duration such as \"5m\", got %q", envCEBConfigRefreshInterval, v)
+	}
+
+	return d, nil
+}
+
+// refreshConfig reads the dynamic variables of the latest config again
+// until ctx is done. The interval is read from the latest config each
+// time, see configRefreshIntervalFor. If any of them changed, the child
+// command is restarted. dynamic are the values the child command was
+// started with. Files are rewritten if they changed, which only signals
+// the child command.
+func (ceb *CEB) refreshConfig(ctx context.Context, cfg *config, dynamic []string) {
+	log := ceb.logger.Named("config_refresh")
+
+	for {
+		ceb.configLock.Lock()
+		interval, err := configRefreshIntervalFor(cfg, ceb.config)
+		ceb.configLock.Unlock()
+		if err != nil {
+			log.Warn("invalid refresh interval, using the default", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(interval):
+		}
+
+		ceb.configLock.Lock()
+		config := ceb.config
+		ceb.configLock.Unlock()
+
+		// Only restart if we read everything so that a source that is
+		// briefly unavailable doesn't remove variables.
+		static, newDynamic, files, err := ceb.configEnv(ctx, config)
+
+		// The files that were read are updated even if others couldn't
+		// be since each file is replaced on its own.
+		ceb.updateConfigFiles(ctx, files)
+		if err != nil {
+			log.Warn("error reading dynamic config, will retry", "err", err)
+			continue
+		}
+		if reflect.DeepEqual(newDynamic, dynamic) {
+			continue
+		}
+
+		log.Info("dynamic config changed, restarting child command")
+		select {
+		case ceb.restartCh <- append(static, newDynamic...):
+			dynamic = newDynamic
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// restartChildCmd stops the child command that sends to errCh and starts
+// it again with the environment variables in env. If the restart policy
+// has a reload signal, the child command is only sent that signal and
+// keeps running.
+func (ceb *CEB) restartChildCmd(ctx context.Context, cfg *config, errCh <-chan error, env []string) (<-chan error, error) {
+	log := ceb.logger.Named("child")
+
+	ceb.configLock.Lock()
+	policy, err := restartPolicyFor(cfg, ceb.config)
+	ceb.configLock.Unlock()
+	if err != nil {
+		log.Warn("invalid restart policy, using the defaults for invalid settings", "err", err)
+	}
+
+	if policy.ReloadSignal != nil {
+		log.Info("signaling child process to reload", "signal", policy.ReloadSignal)
+		if err := ceb.childCmd.Process.Signal(policy.ReloadSignal); err != nil {
+			log.Warn("error signaling child process", "err", err)
+		}
+
+		return errCh, nil
+	}
+
+	// Ask the child to exit so it can drain, and kill it if it doesn't
+	// in time.
+	log.Info("stopping child process", "signal", policy.StopSignal, "timeout", policy.StopTimeout)
+	if err := ceb.childCmd.Process.Signal(policy.StopSignal); err != nil {
+		log.Debug("error signaling child process, killing it", "err", err)
+		ceb.childCmd.Process.Kill()
+	}
+	select {
+	case <-errCh:
+	case <-time.After(policy.StopTimeout):
+		log.Warn("child process didn't exit in time, killing it")
+		ceb.childCmd.Process.Kill()
+		<-errCh
+	}
+
+	if err := ceb.replaceChildCmd(ctx, cfg, append(os.Environ(), env...)); err != nil {
+		return nil, err
+	}
+
+	return ceb.execChildCmd(ctx), nil
+}
+
+// replaceChildCmd replaces the child command, which must have exited, with
+// a new one with the environment env so that it can be started again.
+func (ceb *CEB) replaceChildCmd(ctx context.Context, cfg *config, env []string) error {
+	cmd, err := ceb.buildCmd(ctx, cfg.ExecArgs)
+	if err != nil {
+		return err
+	}
+
+	// Keep the output of the previous command which is also streamed to
+	// the server.
+	cmd.Env = env
+	cmd.Stdout = ceb.childCmd.Stdout
+	cmd.Stderr = ceb.childCmd.Stderr
+	ceb.childCmd = cmd
+
+	return nil
+}
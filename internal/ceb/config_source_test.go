@@ -0,0 +1,87 @@
+package ceb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/internal/configsource"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestCEBConfigEnv(t *testing.T) {
+	require := require.New(t)
+
+	configsource.FromString["test"] = func(config map[string]string) (configsource.Sourcer, error) {
+		return testSourcer(config["prefix"]), nil
+	}
+	defer delete(configsource.FromString, "test")
+
+	ceb := &CEB{logger: hclog.L()}
+	dynamic := func(name, key string) *pb.ConfigVar {
+		return &pb.ConfigVar{
+			Name: name,
+			Dynamic: &pb.ConfigVar_DynamicVal{
+				From:   "test",
+				Config: map[string]string{"key": key},
+			},
+		}
+	}
+
+	static, env, files, err := ceb.configEnv(context.Background(), &pb.EntrypointConfig{
+		EnvVars: []*pb.ConfigVar{
+			{Name: "PORT", Value: "8080"},
+			dynamic("PASSWORD", "password"),
+			dynamic("MISSING", ""),
+		},
+		ConfigSources: []*pb.ConfigSource{{
+			Type:   "test",
+			Config: map[string]string{"prefix": "value-"},
+		}},
+	})
+
+	// The variables that could be read are still returned
+	require.Error(err)
+	require.Contains(err.Error(), "MISSING")
+	require.Equal([]string{"PORT=8080"}, static)
+	require.Equal([]string{"PASSWORD=value-password"}, env)
+	require.Empty(files)
+}
+
+func TestConfigRefreshIntervalFor(t *testing.T) {
+	require := require.New(t)
+
+	// Defaults
+	interval, err := configRefreshIntervalFor(&config{}, nil)
+	require.NoError(err)
+	require.Equal(configRefreshInterval, interval)
+
+	// Config variables take precedence over the environment
+	interval, err = configRefreshIntervalFor(&config{ConfigRefreshInterval: "1h"}, &pb.EntrypointConfig{
+		EnvVars: []*pb.ConfigVar{
+			{Name: envCEBConfigRefreshInterval, Value: "30s"},
+		},
+	})
+	require.NoError(err)
+	require.Equal(30*time.Second, interval)
+
+	// Invalid intervals use the default
+	interval, err = configRefreshIntervalFor(&config{ConfigRefreshInterval: "0s"}, nil)
+	require.Error(err)
+	require.Equal(configRefreshInterval, interval)
+}
+
+// testSourcer returns the key of a variable with a prefix.
+type testSourcer string
+
+func (s testSourcer) Read(ctx context.Context, config map[string]string) (string, error) {
+	if config["key"] == "" {
+		return "", fmt.Errorf("key must be set")
+	}
+
+	return string(s) + config["key"], nil
+}
@@ -17,7 +17,7 @@ import (
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
-func (ceb *CEB) startExecGroup(es []*pb.EntrypointConfig_Exec) {
+func (ceb *CEB) startExecGroup(es []*pb.EntrypointConfig_Exec, disabled bool) {
 	idx := ceb.execIdx
 	for _, exec := range es {
 		// Ignore exec sessions we already have
@@ -31,14 +31,14 @@ func (ceb *CEB) startExecGroup(es []*pb.EntrypointConfig_Exec) {
 		}
 
 		// Start our session
-		go ceb.startExec(exec)
+		go ceb.startExec(exec, disabled)
 	}
 
 	// Store our exec index
 	ceb.execIdx = idx
 }
 
-func (ceb *CEB) startExec(execConfig *pb.EntrypointConfig_Exec) {
+func (ceb *CEB) startExec(execConfig *pb.EntrypointConfig_Exec, disabled bool) {
 	log := ceb.logger.Named("exec").With("index", execConfig.Index)
 
 	// Open the stream
@@ -64,6 +64,30 @@ func (ceb *CEB) startExec(execConfig *pb.EntrypointConfig_Exec) {
 		return
 	}
 
+	// If exec is disabled, we refuse the session so that it fails rather
+	// than waiting for us.
+	if disabled {
+		log.Warn("exec is disabled, refusing exec session")
+		if err := client.Send(&pb.EntrypointExecRequest{
+			Event: &pb.EntrypointExecRequest_Error_{
+				Error: &pb.EntrypointExecRequest_Error{
+					Error: status.New(codes.PermissionDenied,
+						"exec is disabled for this deployment").Proto(),
+				},
+			},
+		}); err != nil {
+			log.Warn("error sending error message", "err", err)
+		}
+
+		return
+	}
+
+	// Copy sessions don't run a command.
+	if execConfig.Copy != nil {
+		ceb.startCopy(log, client, execConfig.Copy)
+		return
+	}
+
 	// Build our command
 	cmd, err := ceb.buildCmd(ceb.context, execConfig.Args)
 	if err != nil {
@@ -132,11 +156,15 @@ func (ceb *CEB) startExec(execConfig *pb.EntrypointConfig_Exec) {
 		}
 
 		// Start with a pty
-		ptyFile, err = pty.StartWithSize(cmd, &pty.Winsize{
-			Rows: uint16(ptyReq.WindowSize.Rows),
-			Cols: uint16(ptyReq.WindowSize.Cols),
-			X:    uint16(ptyReq.WindowSize.Width),
-			Y:    uint16(ptyReq.WindowSize.Height),
+		err = startChild(cmd, func() error {
+			var err error
+			ptyFile, err = pty.StartWithSize(cmd, &pty.Winsize{
+				Rows: uint16(ptyReq.WindowSize.Rows),
+				Cols: uint16(ptyReq.WindowSize.Cols),
+				X:    uint16(ptyReq.WindowSize.Width),
+				Y:    uint16(ptyReq.WindowSize.Height),
+			})
+			return err
 		})
 		if err != nil {
 			log.Warn("error building exec command", "err", err)
@@ -162,7 +190,7 @@ func (ceb *CEB) startExec(execConfig *pb.EntrypointConfig_Exec) {
 		go io.Copy(ptyFile, stdin)
 		go io.Copy(stdout, ptyFile)
 	} else {
-		if err := cmd.Start(); err != nil {
+		if err := startChild(cmd, cmd.Start); err != nil {
 			log.Warn("error building exec command", "err", err)
 			st, ok := status.FromError(err)
 			if !ok {
@@ -186,7 +214,7 @@ func (ceb *CEB) startExec(execConfig *pb.EntrypointConfig_Exec) {
 	// concurrent events happening below.
 	cmdExitCh := make(chan error, 1)
 	go func() {
-		cmdExitCh <- cmd.Wait()
+		cmdExitCh <- waitChild(cmd)
 	}()
 
 	for {
@@ -198,7 +226,17 @@ func (ceb *CEB) startExec(execConfig *pb.EntrypointConfig_Exec) {
 				log.Trace("input received", "data", event.Input)
 				io.Copy(stdinW, bytes.NewReader(event.Input))
 
+			case *pb.EntrypointExecResponse_InputEof:
+				// The client has no more input, so we close stdin.
+				log.Trace("input closed")
+				stdinW.Close()
+
 			case *pb.EntrypointExecResponse_Winch:
+				// Without a pty there is no window to resize.
+				if ptyFile == nil {
+					continue
+				}
+
 				log.Debug("window size change event, changing")
 
 				sz := pty.Winsize{
@@ -0,0 +1,152 @@
+package ceb
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// copyChunkSize is the size of the output messages of a file copied from
+// the instance.
+const copyChunkSize = 32 * 1024
+
+// startCopy copies a file to or from the instance for an exec session
+// that was opened with a copy. The session exits with code 0 once the
+// file is copied, or with an error.
+func (ceb *CEB) startCopy(
+	log hclog.Logger,
+	client pb.Waypoint_EntrypointExecStreamClient,
+	c *pb.ExecStreamRequest_FileCopy,
+) {
+	log = log.With("path", c.Path, "direction", c.Direction.String())
+	log.Info("starting file copy")
+
+	var err error
+	switch c.Direction {
+	case pb.ExecStreamRequest_FileCopy_FROM_INSTANCE:
+		err = copyFromInstance(c.Path, ceb.execOutputWriter(client, pb.EntrypointExecRequest_Output_STDOUT))
+
+	case pb.ExecStreamRequest_FileCopy_TO_INSTANCE:
+		err = copyToInstance(c.Path, os.FileMode(c.Mode), func() ([]byte, bool, error) {
+			for {
+				resp, err := client.Recv()
+				if err != nil {
+					return nil, false, err
+				}
+
+				switch event := resp.Event.(type) {
+				case *pb.EntrypointExecResponse_Input:
+					return event.Input, false, nil
+
+				case *pb.EntrypointExecResponse_InputEof:
+					return nil, true, nil
+				}
+			}
+		})
+	}
+
+	if err != nil {
+		log.Warn("error copying file", "err", err)
+		st, ok := status.FromError(err)
+		if !ok {
+			st = status.New(codes.Unknown, err.Error())
+		}
+
+		if err := client.Send(&pb.EntrypointExecRequest{
+			Event: &pb.EntrypointExecRequest_Error_{
+				Error: &pb.EntrypointExecRequest_Error{
+					Error: st.Proto(),
+				},
+			},
+		}); err != nil {
+			log.Warn("error sending error message", "err", err)
+		}
+
+		return
+	}
+
+	log.Info("file copied")
+	if err := client.Send(&pb.EntrypointExecRequest{
+		Event: &pb.EntrypointExecRequest_Exit_{
+			Exit: &pb.EntrypointExecRequest_Exit{},
+		},
+	}); err != nil {
+		log.Warn("error sending exit message", "err", err)
+	}
+}
+
+// copyFromInstance writes the contents of the file at path to w.
+func copyFromInstance(path string, w io.Writer) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return status.Errorf(codes.NotFound, "file %q doesn't exist", path)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return status.Errorf(codes.InvalidArgument,
+			"%q is a directory, only files can be copied", path)
+	}
+
+	_, err = io.CopyBuffer(w, f, make([]byte, copyChunkSize))
+	return err
+}
+
+// copyToInstance writes the file at path with the data from next until it
+// returns true at the end of the file. The data is written to a temporary
+// file in the same directory first and then renamed so that the file is
+// never partially written, like config files.
+func copyToInstance(path string, mode os.FileMode, next func() ([]byte, bool, error)) error {
+	if mode == 0 {
+		mode = 0644
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	for {
+		data, eof, err := next()
+		if err != nil {
+			return err
+		}
+		if eof {
+			break
+		}
+
+		if _, err := tmp.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tmp.Chmod(mode.Perm()); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,83 @@
+package ceb
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCopyFromInstance(t *testing.T) {
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "ceb-copy")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	path := filepath.Join(td, "app.log")
+	data := bytes.Repeat([]byte("line\n"), copyChunkSize)
+	require.NoError(ioutil.WriteFile(path, data, 0644))
+
+	var buf bytes.Buffer
+	require.NoError(copyFromInstance(path, &buf))
+	require.Equal(data, buf.Bytes())
+
+	// Missing files and directories are an error
+	err = copyFromInstance(filepath.Join(td, "nope"), &buf)
+	require.Equal(codes.NotFound, status.Code(err))
+
+	err = copyFromInstance(td, &buf)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestCopyToInstance(t *testing.T) {
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "ceb-copy")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	// next returns the chunks and then the end of the file, or err.
+	next := func(chunks []string, err error) func() ([]byte, bool, error) {
+		return func() ([]byte, bool, error) {
+			if len(chunks) == 0 {
+				return nil, err == nil, err
+			}
+
+			chunk := chunks[0]
+			chunks = chunks[1:]
+			return []byte(chunk), false, nil
+		}
+	}
+
+	// The file and its directory are created
+	path := filepath.Join(td, "app", "config.yml")
+	require.NoError(copyToInstance(path, 0600, next([]string{"port: ", "8080"}, nil)))
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal("port: 8080", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	// If the stream fails, the file isn't changed
+	err = copyToInstance(path, 0, next([]string{"port: 9090"}, errors.New("disconnected")))
+	require.Error(err)
+
+	data, err = ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal("port: 8080", string(data))
+
+	// No temporary files are left behind
+	entries, err := ioutil.ReadDir(filepath.Dir(path))
+	require.NoError(err)
+	require.Len(entries, 1)
+}
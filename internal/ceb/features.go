@@ -0,0 +1,57 @@
+package ceb
+
+import (
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	envCEBDisableExec       = "WAYPOINT_CEB_DISABLE_EXEC"
+	envCEBDisableLogs       = "WAYPOINT_CEB_DISABLE_LOGS"
+	envCEBDisableURL        = "WAYPOINT_CEB_DISABLE_URL"
+	envCEBDisableLogParsing = "WAYPOINT_CEB_DISABLE_LOG_PARSING"
+)
+
+// disabledFeatures are the features of the entrypoint that are disabled
+// while the others keep working, unlike WAYPOINT_CEB_DISABLE which
+// disables all of them.
+type disabledFeatures struct {
+	// Exec refuses exec sessions such as from "waypoint exec".
+	Exec bool
+
+	// Logs doesn't stream the logs of the child command to the server.
+	Logs bool
+
+	// URL doesn't register the app with the URL service.
+	URL bool
+
+	// LogParsing sends the logs as text without parsing structured lines
+	// for their level and fields.
+	LogParsing bool
+}
+
+// disabledFeaturesFor returns the features that are disabled for the
+// config. See settings for where each setting is read from. Like
+// WAYPOINT_CEB_DISABLE, a feature is disabled if its setting is any
+// non-empty value.
+func disabledFeaturesFor(cfg *config, ec *pb.EntrypointConfig) *disabledFeatures {
+	values := settings(ec, map[string]string{
+		envCEBDisableExec:       cfg.DisableExec,
+		envCEBDisableLogs:       cfg.DisableLogs,
+		envCEBDisableURL:        cfg.DisableURL,
+		envCEBDisableLogParsing: cfg.DisableLogParsing,
+	})
+
+	return &disabledFeatures{
+		Exec:       values[envCEBDisableExec] != "",
+		Logs:       values[envCEBDisableLogs] != "",
+		URL:        values[envCEBDisableURL] != "",
+		LogParsing: values[envCEBDisableLogParsing] != "",
+	}
+}
+
+// disabled returns the features that are disabled for the latest config.
+func (ceb *CEB) disabled(cfg *config) *disabledFeatures {
+	ceb.configLock.Lock()
+	defer ceb.configLock.Unlock()
+	return disabledFeaturesFor(cfg, ceb.config)
+}
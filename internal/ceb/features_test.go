@@ -0,0 +1,26 @@
+package ceb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestDisabledFeaturesFor(t *testing.T) {
+	require := require.New(t)
+
+	// Everything is enabled by default
+	require.Equal(&disabledFeatures{}, disabledFeaturesFor(&config{}, nil))
+
+	// Config variables such as from waypoint.hcl take precedence over the
+	// environment
+	disabled := disabledFeaturesFor(&config{DisableLogs: "1"}, &pb.EntrypointConfig{
+		EnvVars: []*pb.ConfigVar{
+			{Name: envCEBDisableExec, Value: "true"},
+			{Name: envCEBDisableLogs, Value: ""},
+		},
+	})
+	require.Equal(&disabledFeatures{Exec: true}, disabled)
+}
@@ -0,0 +1,297 @@
+package ceb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	envCEBHealthCheck    = "WAYPOINT_CEB_HEALTH_CHECK"
+	envCEBHealthInterval = "WAYPOINT_CEB_HEALTH_INTERVAL"
+)
+
+var (
+	// healthCheckInterval is how often the app is checked unless the
+	// setting changes it.
+	healthCheckInterval = 10 * time.Second
+
+	// healthCheckTimeout is the longest a check can take before it fails.
+	// Checks also time out after the interval if it is shorter.
+	healthCheckTimeout = 5 * time.Second
+
+	// healthCheckFailures is the number of checks in a row that must fail
+	// before a ready instance is unhealthy.
+	healthCheckFailures = 3
+)
+
+// healthCheck checks the app and returns an error if it isn't healthy.
+type healthCheck func(ctx context.Context) error
+
+// parseHealthCheck returns the health check for the setting. The checks
+// are:
+//
+//   - "http" or "http:<path>" - An HTTP GET of the path on the port of the
+//     app must respond with a 2xx or 3xx status. The path defaults to "/".
+//   - "http://<address>/<path>" - The same for any URL.
+//   - "tcp" or "tcp:<port>" - A TCP connection to the port, which defaults
+//     to the port of the app, must succeed.
+//   - "exec:<command>" - The command must exit with status zero. It is
+//     run with "sh -c".
+//
+// This returns nil if v is empty.
+func parseHealthCheck(v string, port int) (healthCheck, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://") {
+		return httpHealthCheck(v), nil
+	}
+
+	kind := v
+	var arg string
+	if idx := strings.IndexByte(v, ':'); idx != -1 {
+		kind, arg = v[:idx], v[idx+1:]
+	}
+
+	switch kind {
+	case "http":
+		if arg == "" {
+			arg = "/"
+		}
+		if !strings.HasPrefix(arg, "/") {
+			arg = "/" + arg
+		}
+
+		return httpHealthCheck(fmt.Sprintf("http://127.0.0.1:%d%s", port, arg)), nil
+
+	case "tcp":
+		if arg != "" {
+			p, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid port %q", envCEBHealthCheck, arg)
+			}
+
+			port = p
+		}
+
+		addr := fmt.Sprintf("127.0.0.1:%d", port)
+		return func(ctx context.Context) error {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+
+			return conn.Close()
+		}, nil
+
+	case "exec":
+		if arg == "" {
+			return nil, fmt.Errorf("%s: exec requires a command", envCEBHealthCheck)
+		}
+
+		return func(ctx context.Context) error {
+			var out bytes.Buffer
+			cmd := exec.CommandContext(ctx, "sh", "-c", arg)
+			cmd.Env = os.Environ()
+			cmd.Stdout = &out
+			cmd.Stderr = &out
+			err := startChild(cmd, cmd.Start)
+			if err == nil {
+				err = waitChild(cmd)
+			}
+			if err != nil {
+				output := strings.TrimSpace(out.String())
+				if len(output) > 200 {
+					output = output[:200] + "..."
+				}
+				if output != "" {
+					return fmt.Errorf("%s: %s", err, output)
+				}
+
+				return err
+			}
+
+			return nil
+		}, nil
+
+	default:
+		return nil, fmt.Errorf(
+			"%s: must be http, tcp or exec, got %q", envCEBHealthCheck, v)
+	}
+}
+
+// httpHealthCheck returns a check that gets the URL.
+func httpHealthCheck(url string) healthCheck {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("GET %s responded with %s", url, resp.Status)
+		}
+
+		return nil
+	}
+}
+
+// healthCheckFor returns the health check and interval for the config.
+// See settings for where they are read from. This returns a nil check if
+// the app has none.
+func healthCheckFor(cfg *config, ec *pb.EntrypointConfig) (healthCheck, time.Duration, error) {
+	values := settings(ec, map[string]string{
+		envCEBHealthCheck:    cfg.HealthCheck,
+		envCEBHealthInterval: cfg.HealthInterval,
+	})
+
+	interval := healthCheckInterval
+	if v := values[envCEBHealthInterval]; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, interval, fmt.Errorf(
+				"%s: must be a duration such as \"10s\", got %q", envCEBHealthInterval, v)
+		}
+
+		interval = d
+	}
+
+	check, err := parseHealthCheck(values[envCEBHealthCheck], cfg.URLServicePort)
+	return check, interval, err
+}
+
+// healthState is the health of the instance from the results of its
+// checks.
+type healthState struct {
+	State    pb.Instance_Health_State
+	Message  string
+	failures int
+}
+
+// record updates the state with the result of a check. The instance is
+// starting until a check passes and is then ready until several checks in
+// a row fail.
+func (h *healthState) record(err error) {
+	if err == nil {
+		h.State = pb.Instance_Health_READY
+		h.Message = ""
+		h.failures = 0
+		return
+	}
+
+	h.Message = err.Error()
+	h.failures++
+	switch h.State {
+	case pb.Instance_Health_UNKNOWN:
+		h.State = pb.Instance_Health_STARTING
+
+	case pb.Instance_Health_READY:
+		if h.failures >= healthCheckFailures {
+			h.State = pb.Instance_Health_UNHEALTHY
+		}
+	}
+}
+
+// initHealthCheck starts checking the health of the app in the background
+// and reporting it to the server.
+func (ceb *CEB) initHealthCheck(ctx context.Context, cfg *config) {
+	go ceb.runHealthChecks(ctx, cfg)
+}
+
+// runHealthChecks checks the app until ctx is done. The check is read
+// from the latest config each time so that it can be changed without a
+// deployment. Apps without a check are ready as soon as they start.
+func (ceb *CEB) runHealthChecks(ctx context.Context, cfg *config) {
+	log := ceb.logger.Named("health")
+
+	state := &healthState{}
+	for {
+		ceb.configLock.Lock()
+		ec := ceb.config
+		ceb.configLock.Unlock()
+
+		check, interval, err := healthCheckFor(cfg, ec)
+		if err != nil {
+			log.Warn("invalid health check setting", "error", err)
+		}
+
+		switch {
+		case check != nil:
+			timeout := healthCheckTimeout
+			if interval < timeout {
+				timeout = interval
+			}
+
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			err := check(checkCtx)
+			cancel()
+
+			previous := state.State
+			state.record(err)
+			if state.State != previous {
+				log.Info("health changed", "state", state.State.String(), "message", state.Message)
+			}
+			if state.State == pb.Instance_Health_READY {
+				ceb.setReady()
+			}
+
+			ceb.reportHealth(ctx, state)
+
+		case state.State != pb.Instance_Health_UNKNOWN:
+			// The check was removed, so the health isn't known anymore.
+			state = &healthState{}
+			ceb.reportHealth(ctx, state)
+			ceb.setReady()
+
+		default:
+			ceb.setReady()
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reportHealth sends the health to the server. This is done after every
+// check since the server forgets the health if it restarts.
+func (ceb *CEB) reportHealth(ctx context.Context, state *healthState) {
+	_, err := ceb.client.EntrypointHealth(ctx, &pb.EntrypointHealthRequest{
+		InstanceId: ceb.id,
+		Health: &pb.Instance_Health{
+			State:   state.State,
+			Message: state.Message,
+			Time:    ptypes.TimestampNow(),
+		},
+	})
+	if err != nil {
+		ceb.logger.Named("health").Debug("error reporting health", "error", err)
+	}
+}
+
+// setReady marks that the app is ready, which starts the URL service.
+func (ceb *CEB) setReady() {
+	ceb.readyOnce.Do(func() { close(ceb.readyCh) })
+}
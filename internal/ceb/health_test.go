@@ -0,0 +1,184 @@
+package ceb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestParseHealthCheck(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Value string
+		Nil   bool
+		Err   string
+	}{
+		{"empty", "", true, ""},
+		{"http", "http", false, ""},
+		{"http path", "http:/healthz", false, ""},
+		{"http url", "http://localhost:8080/healthz", false, ""},
+		{"tcp", "tcp", false, ""},
+		{"tcp port", "tcp:8080", false, ""},
+		{"tcp invalid port", "tcp:http", true, "invalid port"},
+		{"exec", "exec:true", false, ""},
+		{"exec without command", "exec:", true, "requires a command"},
+		{"unknown", "grpc", true, "must be http, tcp or exec"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			check, err := parseHealthCheck(tt.Value, 5000)
+			if tt.Err != "" {
+				require.Error(err)
+				require.Contains(err.Error(), tt.Err)
+			} else {
+				require.NoError(err)
+			}
+			require.Equal(tt.Nil, check == nil)
+		})
+	}
+}
+
+func TestHealthCheck_http(t *testing.T) {
+	require := require.New(t)
+
+	healthy := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" || !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	require.NoError(err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(err)
+
+	check, err := parseHealthCheck("http:/healthz", port)
+	require.NoError(err)
+	require.NoError(check(context.Background()))
+
+	healthy = false
+	err = check(context.Background())
+	require.Error(err)
+	require.Contains(err.Error(), "503")
+
+	// The root path is checked by default
+	check, err = parseHealthCheck("http", port)
+	require.NoError(err)
+	require.Error(check(context.Background()))
+}
+
+func TestHealthCheck_tcp(t *testing.T) {
+	require := require.New(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	check, err := parseHealthCheck("tcp", port)
+	require.NoError(err)
+	require.NoError(check(context.Background()))
+
+	// Another port can be checked
+	check, err = parseHealthCheck("tcp:"+strconv.Itoa(port), 1)
+	require.NoError(err)
+	require.NoError(check(context.Background()))
+
+	ln.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.Error(check(ctx))
+}
+
+func TestHealthCheck_exec(t *testing.T) {
+	require := require.New(t)
+
+	check, err := parseHealthCheck("exec:true", 0)
+	require.NoError(err)
+	require.NoError(check(context.Background()))
+
+	// The output is included in the error
+	check, err = parseHealthCheck("exec:echo not ready; exit 1", 0)
+	require.NoError(err)
+	err = check(context.Background())
+	require.Error(err)
+	require.Contains(err.Error(), "not ready")
+}
+
+func TestHealthCheckFor(t *testing.T) {
+	require := require.New(t)
+
+	cfg := &config{URLServicePort: 5000, HealthInterval: "1m"}
+
+	// No check by default
+	check, interval, err := healthCheckFor(cfg, nil)
+	require.NoError(err)
+	require.Nil(check)
+	require.Equal(time.Minute, interval)
+
+	// Config variables take precedence over the environment
+	check, interval, err = healthCheckFor(cfg, &pb.EntrypointConfig{
+		EnvVars: []*pb.ConfigVar{
+			{Name: envCEBHealthCheck, Value: "tcp"},
+			{Name: envCEBHealthInterval, Value: "5s"},
+		},
+	})
+	require.NoError(err)
+	require.NotNil(check)
+	require.Equal(5*time.Second, interval)
+
+	// Invalid intervals are an error
+	cfg.HealthInterval = "often"
+	_, interval, err = healthCheckFor(cfg, nil)
+	require.Error(err)
+	require.Equal(healthCheckInterval, interval)
+}
+
+func TestHealthState(t *testing.T) {
+	require := require.New(t)
+
+	failed := errors.New("connection refused")
+
+	// The instance is starting until a check passes
+	var state healthState
+	for i := 0; i < healthCheckFailures+1; i++ {
+		state.record(failed)
+		require.Equal(pb.Instance_Health_STARTING, state.State)
+	}
+	require.Equal("connection refused", state.Message)
+
+	state.record(nil)
+	require.Equal(pb.Instance_Health_READY, state.State)
+	require.Empty(state.Message)
+
+	// It is unhealthy only after several failures in a row
+	for i := 0; i < healthCheckFailures-1; i++ {
+		state.record(failed)
+		require.Equal(pb.Instance_Health_READY, state.State)
+	}
+	state.record(nil)
+	for i := 0; i < healthCheckFailures-1; i++ {
+		state.record(failed)
+		require.Equal(pb.Instance_Health_READY, state.State)
+	}
+	state.record(failed)
+	require.Equal(pb.Instance_Health_UNHEALTHY, state.State)
+
+	// It is ready again once a check passes
+	state.record(nil)
+	require.Equal(pb.Instance_Health_READY, state.State)
+}
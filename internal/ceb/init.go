@@ -47,12 +47,19 @@ RETRY_INIT:
 		return err
 	}
 
-	// Initialize our log stream
-	// NOTE(mitchellh): at some point we want this to be configurable
-	// but for now we're just going for it.
-	if err := ceb.initLogStream(ctx, cfg); err != nil {
+	// Initialize our log stream unless it is disabled. This is only read
+	// when we start since the output of the child command is set then.
+	if ceb.disabled(cfg).Logs {
+		log.Info("log streaming disabled, logs will not be sent to the server")
+	} else if err := ceb.initLogStream(ctx, cfg); err != nil {
 		return err
 	}
 
+	// Check the health of the app and report it to the server.
+	ceb.initHealthCheck(ctx, cfg)
+
+	// Report the resource usage of the app to the server.
+	ceb.initMetrics(ctx)
+
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/hashicorp/go-hclog"
@@ -12,9 +13,14 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/hashicorp/waypoint/internal/pkg/structlog"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
+// logReconnectDelay is how long the log stream waits before it reconnects
+// after it was disconnected or failed to connect.
+var logReconnectDelay = 2 * time.Second
+
 func (ceb *CEB) initLogStream(ctx context.Context, cfg *config) error {
 	log := ceb.logger.Named("log")
 
@@ -29,6 +35,10 @@ func (ceb *CEB) initLogStream(ctx context.Context, cfg *config) error {
 	ceb.childCmd.Stdout = io.MultiWriter(w, ceb.childCmd.Stdout)
 	ceb.childCmd.Stderr = io.MultiWriter(w, ceb.childCmd.Stderr)
 
+	// Structured lines, such as JSON, are parsed for their level so that
+	// they can be filtered. Like logs, this is only checked when we start.
+	parse := !ceb.disabled(cfg).LogParsing
+
 	// We need to start a goroutine to read from our pipe. If we don't
 	// read from the pipe the child command will get a SIGPIPE and could
 	// exit/crash if it doesn't handle it. So even if we don't have a
@@ -52,6 +62,13 @@ func (ceb *CEB) initLogStream(ctx context.Context, cfg *config) error {
 				Timestamp: ptypes.TimestampNow(),
 				Line:      line,
 			}
+			if parse {
+				if parsed, ok := structlog.Parse(line); ok {
+					entry.Level = parsed.Level
+					entry.Message = parsed.Message
+					entry.Fields = parsed.Fields
+				}
+			}
 
 			// Send the entry. We never block here because blocking the
 			// pipe is worse. The channel is buffered to help with this.
@@ -64,56 +81,107 @@ func (ceb *CEB) initLogStream(ctx context.Context, cfg *config) error {
 
 	// Start up our server stream. We do this in a goroutine cause we don't
 	// want to block the child command startup on it.
-	go ceb.initLogStreamSender(log, ctx, entryCh)
+	go ceb.initLogStreamSender(log, ctx, cfg, entryCh)
 
 	return nil
 }
 
+// initLogStreamSender sends the log entries to the server until ctx is
+// done. While it is disconnected, the entries are written to a log buffer
+// on disk and they are sent first once it reconnects.
 func (ceb *CEB) initLogStreamSender(
 	log hclog.Logger,
 	ctx context.Context,
+	cfg *config,
 	entryCh <-chan *pb.LogBatch_Entry,
-) error {
-	// Open our log stream
-	log.Debug("connecting to log stream")
-	client, err := ceb.client.EntrypointLogStream(ctx, grpc.WaitForReady(true))
-	if err != nil {
-		return status.Errorf(codes.Aborted,
-			"failed to open a log stream: %s", err)
+) {
+	buf := newLogBuffer(cfg.LogBufferDir, int64(cfg.LogBufferSize))
+	defer buf.Close()
+
+	// connect opens the log stream in the background after delay and
+	// sends it to connCh, retrying until it succeeds or ctx is done. This
+	// is done in the background so that we keep buffering entries while
+	// we wait for the server.
+	connCh := make(chan pb.Waypoint_EntrypointLogStreamClient, 1)
+	var connect func(delay time.Duration)
+	connect = func(delay time.Duration) {
+		go func() {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+
+			log.Debug("connecting to log stream")
+			client, err := ceb.client.EntrypointLogStream(ctx, grpc.WaitForReady(true))
+			if err != nil {
+				log.Warn("failed to open a log stream, will retry", "error", err)
+				connect(logReconnectDelay)
+				return
+			}
+			ceb.cleanup(func() { client.CloseAndRecv() })
+
+			connCh <- client
+		}()
 	}
-	ceb.cleanup(func() { client.CloseAndRecv() })
-	log.Trace("log stream connected")
+	connect(0)
 
 	// NOTE(mitchellh): Lots of improvements we can make here one day:
 	//   - we can coalesce entryCh receives to send less log updates
-	//   - during reconnect we can buffer entryCh receives
-	go func() {
-		for {
-			var entry *pb.LogBatch_Entry
-			select {
-			case <-ctx.Done():
-				return
+	var client pb.Waypoint_EntrypointLogStreamClient
+	send := func(entries []*pb.LogBatch_Entry) error {
+		return client.Send(&pb.EntrypointLogBatch{
+			InstanceId: ceb.id,
+			Lines:      entries,
+		})
+	}
+	disconnected := func(err error) {
+		log.Error("log stream disconnected from server, buffering logs until reconnected",
+			"error", err)
+		client = nil
+		connect(logReconnectDelay)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
 
-			case entry = <-entryCh:
+		case client = <-connCh:
+			log.Trace("log stream connected")
+
+			// Send what we buffered while we were disconnected first so
+			// the entries stay in order.
+			n := buf.Len()
+			if err := buf.Replay(send); err != nil {
+				disconnected(err)
+				continue
+			}
+			if n > 0 {
+				log.Info("sent logs buffered while disconnected", "lines", n)
+			}
+			if buf.dropped > 0 {
+				log.Warn("log buffer was full, some lines were dropped while disconnected",
+					"lines", buf.dropped)
+				buf.dropped = 0
 			}
 
-			err := client.Send(&pb.EntrypointLogBatch{
-				InstanceId: ceb.id,
-				Lines:      []*pb.LogBatch_Entry{entry},
-			})
-			if err == io.EOF || status.Code(err) == codes.Unavailable {
-				log.Error("log stream disconnected from server, attempting reconnect")
-				err = ceb.initLogStreamSender(log, ctx, entryCh)
+		case entry := <-entryCh:
+			if client != nil {
+				err := send([]*pb.LogBatch_Entry{entry})
 				if err == nil {
-					return
+					continue
+				}
+				if err != io.EOF && status.Code(err) != codes.Unavailable {
+					log.Warn("error sending logs", "error", err)
 				}
+
+				disconnected(err)
 			}
-			if err != nil {
-				log.Warn("error sending logs", "error", err)
-				return
+
+			if err := buf.Write(entry); err != nil {
+				log.Warn("error buffering logs", "error", err)
 			}
 		}
-	}()
-
-	return nil
+	}
 }
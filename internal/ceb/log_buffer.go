@@ -0,0 +1,159 @@
+package ceb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	// defaultLogBufferSize is the default maximum size of the log buffer.
+	defaultLogBufferSize = 10 * 1024 * 1024
+
+	// logReplayBatchSize is the number of buffered entries that are sent
+	// to the server in each batch when they are replayed.
+	logReplayBatchSize = 100
+)
+
+// logBuffer keeps log entries on disk while the entrypoint is disconnected
+// from the server, so that they can be sent with their original timestamps
+// when it reconnects. The buffer is bounded by its size. Entries that don't
+// fit are dropped and counted.
+type logBuffer struct {
+	dir string
+	max int64
+
+	f       *os.File
+	size    int64 // bytes written to f
+	offset  int64 // bytes of f that were already replayed
+	count   int   // entries in f that haven't been replayed
+	dropped int   // entries that didn't fit since the last replay
+}
+
+// newLogBuffer returns a buffer that keeps entries in a temporary file in
+// dir, or the default temporary directory if dir is empty, up to max
+// bytes. If max is zero, every entry is dropped.
+func newLogBuffer(dir string, max int64) *logBuffer {
+	return &logBuffer{dir: dir, max: max}
+}
+
+// Write adds the entry to the buffer. The file is created when the first
+// entry is written.
+func (b *logBuffer) Write(entry *pb.LogBatch_Entry) error {
+	data, err := proto.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	var header [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(header[:], uint64(len(data)))
+	if b.size+int64(n+len(data)) > b.max {
+		b.dropped++
+		return nil
+	}
+
+	if b.f == nil {
+		b.f, err = ioutil.TempFile(b.dir, "waypoint-ceb-logs-")
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := b.f.WriteAt(append(header[:n], data...), b.size); err != nil {
+		return err
+	}
+
+	b.size += int64(n + len(data))
+	b.count++
+	return nil
+}
+
+// Len returns the number of entries that haven't been replayed.
+func (b *logBuffer) Len() int {
+	return b.count
+}
+
+// Replay calls send with the buffered entries in order, in batches. If
+// send fails, the entries of that batch and later ones are kept so that
+// the next replay starts with them. Once every entry is sent, the file is
+// removed.
+func (b *logBuffer) Replay(send func([]*pb.LogBatch_Entry) error) error {
+	if b.f == nil {
+		return nil
+	}
+
+	r := bufio.NewReader(io.NewSectionReader(b.f, b.offset, b.size-b.offset))
+	var batch []*pb.LogBatch_Entry
+	var batchSize int64
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := send(batch); err != nil {
+			return err
+		}
+
+		b.offset += batchSize
+		b.count -= len(batch)
+		batch = nil
+		batchSize = 0
+		return nil
+	}
+
+	for {
+		length, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		data := make([]byte, length)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return err
+		}
+
+		var entry pb.LogBatch_Entry
+		if err := proto.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+
+		var header [binary.MaxVarintLen64]byte
+		batch = append(batch, &entry)
+		batchSize += int64(binary.PutUvarint(header[:], length)) + int64(length)
+		if len(batch) >= logReplayBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	return b.Close()
+}
+
+// Close removes the file of the buffer. Entries that weren't replayed are
+// lost.
+func (b *logBuffer) Close() error {
+	if b.f == nil {
+		return nil
+	}
+
+	b.f.Close()
+	err := os.Remove(b.f.Name())
+	b.f = nil
+	b.size = 0
+	b.offset = 0
+	b.count = 0
+	return err
+}
@@ -0,0 +1,104 @@
+package ceb
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestLogBuffer(t *testing.T) {
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "ceb-log-buffer")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	buf := newLogBuffer(td, 1024*1024)
+	defer buf.Close()
+
+	// Entries keep the time they were logged
+	start := time.Now().Add(-time.Minute)
+	total := logReplayBatchSize + 10
+	for i := 0; i < total; i++ {
+		ts, err := ptypes.TimestampProto(start.Add(time.Duration(i) * time.Second))
+		require.NoError(err)
+		require.NoError(buf.Write(&pb.LogBatch_Entry{
+			Timestamp: ts,
+			Line:      fmt.Sprintf("line %d\n", i),
+		}))
+	}
+	require.Equal(total, buf.Len())
+
+	// The second batch fails, so only the first one is sent
+	var sent []*pb.LogBatch_Entry
+	calls := 0
+	err = buf.Replay(func(entries []*pb.LogBatch_Entry) error {
+		calls++
+		if calls > 1 {
+			return errors.New("disconnected")
+		}
+
+		sent = append(sent, entries...)
+		return nil
+	})
+	require.Error(err)
+	require.Len(sent, logReplayBatchSize)
+	require.Equal(total-logReplayBatchSize, buf.Len())
+
+	// The next replay starts where the last one stopped
+	require.NoError(buf.Replay(func(entries []*pb.LogBatch_Entry) error {
+		sent = append(sent, entries...)
+		return nil
+	}))
+	require.Len(sent, total)
+	require.Equal(0, buf.Len())
+	for i, entry := range sent {
+		require.Equal(fmt.Sprintf("line %d\n", i), entry.Line)
+
+		ts, err := ptypes.Timestamp(entry.Timestamp)
+		require.NoError(err)
+		require.True(ts.Equal(start.Add(time.Duration(i) * time.Second)))
+	}
+
+	// The file is removed once everything is sent
+	files, err := ioutil.ReadDir(td)
+	require.NoError(err)
+	require.Empty(files)
+}
+
+func TestLogBuffer_full(t *testing.T) {
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "ceb-log-buffer")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	buf := newLogBuffer(td, 64)
+	defer buf.Close()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(buf.Write(&pb.LogBatch_Entry{
+			Timestamp: ptypes.TimestampNow(),
+			Line:      fmt.Sprintf("line %d\n", i),
+		}))
+	}
+
+	// The entries that didn't fit are dropped, so the oldest are kept
+	require.True(buf.Len() > 0)
+	require.Equal(10, buf.Len()+buf.dropped)
+
+	var sent []*pb.LogBatch_Entry
+	require.NoError(buf.Replay(func(entries []*pb.LogBatch_Entry) error {
+		sent = append(sent, entries...)
+		return nil
+	}))
+	require.Equal("line 0\n", sent[0].Line)
+}
@@ -0,0 +1,170 @@
+package ceb
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var (
+	// metricsInterval is how often the metrics are reported.
+	metricsInterval = 30 * time.Second
+
+	// clockTicks is the number of clock ticks per second that CPU time is
+	// counted in by /proc. This is 100 on every Linux platform we run on
+	// and can't be read without cgo.
+	clockTicks = 100.0
+)
+
+// childProcess is the process of the child command for metrics. This is
+// updated each time the child command is started.
+type childProcess struct {
+	pid     int
+	started time.Time
+	starts  int
+}
+
+// processUsage is the resource usage of a process.
+type processUsage struct {
+	CPUSeconds float64
+	RSSBytes   uint64
+}
+
+// readProcessUsage reads the usage of the process from /proc.
+func readProcessUsage(pid int) (*processUsage, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	stat, err := ioutil.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return nil, err
+	}
+
+	// The stat is "pid (name) state ...", and the name can have spaces and
+	// parentheses. utime and stime are the 14th and 15th fields.
+	s := string(stat)
+	idx := strings.LastIndexByte(s, ')')
+	if idx == -1 {
+		return nil, fmt.Errorf("invalid stat for process %d", pid)
+	}
+	fields := strings.Fields(s[idx+1:])
+	if len(fields) < 13 {
+		return nil, fmt.Errorf("invalid stat for process %d", pid)
+	}
+
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	// statm is in pages and the second field is the resident set.
+	statm, err := ioutil.ReadFile(filepath.Join(dir, "statm"))
+	if err != nil {
+		return nil, err
+	}
+	fields = strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid statm for process %d", pid)
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &processUsage{
+		CPUSeconds: float64(utime+stime) / clockTicks,
+		RSSBytes:   pages * uint64(os.Getpagesize()),
+	}, nil
+}
+
+// initMetrics starts reporting the metrics of the child command to the
+// server in the background.
+func (ceb *CEB) initMetrics(ctx context.Context) {
+	go ceb.runMetrics(ctx)
+}
+
+// runMetrics reports the metrics until ctx is done.
+func (ceb *CEB) runMetrics(ctx context.Context) {
+	log := ceb.logger.Named("metrics")
+
+	var last *pb.Instance_Metrics
+	var lastPid int
+	for {
+		ceb.childLock.Lock()
+		child := ceb.child
+		ceb.childLock.Unlock()
+
+		if child.pid != 0 {
+			metrics := &pb.Instance_Metrics{Time: ptypes.TimestampNow()}
+			if child.starts > 0 {
+				metrics.Restarts = uint32(child.starts - 1)
+			}
+			if ts, err := ptypes.TimestampProto(child.started); err == nil {
+				metrics.StartTime = ts
+			}
+
+			// The usage can't be read outside of Linux, so only the restarts
+			// and uptime are reported.
+			usage, err := readProcessUsage(child.pid)
+			if err != nil {
+				log.Trace("error reading process usage", "error", err)
+			} else {
+				metrics.CpuSeconds = usage.CPUSeconds
+				metrics.RssBytes = usage.RSSBytes
+				metrics.CpuPercent = cpuPercent(last, metrics, lastPid == child.pid)
+			}
+
+			_, err = ceb.client.EntrypointMetrics(ctx, &pb.EntrypointMetricsRequest{
+				InstanceId: ceb.id,
+				Metrics:    metrics,
+			})
+			if err != nil {
+				log.Debug("error reporting metrics", "error", err)
+			}
+
+			last, lastPid = metrics, child.pid
+		}
+
+		select {
+		case <-time.After(metricsInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cpuPercent returns the CPU usage between the previous metrics and the
+// current ones. This is zero if there are no previous metrics for the same
+// process.
+func cpuPercent(prev, cur *pb.Instance_Metrics, sameProcess bool) float64 {
+	if prev == nil || !sameProcess {
+		return 0
+	}
+
+	prevTime, err := ptypes.Timestamp(prev.Time)
+	if err != nil {
+		return 0
+	}
+	curTime, err := ptypes.Timestamp(cur.Time)
+	if err != nil {
+		return 0
+	}
+
+	elapsed := curTime.Sub(prevTime).Seconds()
+	if elapsed <= 0 || cur.CpuSeconds < prev.CpuSeconds {
+		return 0
+	}
+
+	return (cur.CpuSeconds - prev.CpuSeconds) / elapsed * 100
+}
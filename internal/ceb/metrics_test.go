@@ -0,0 +1,49 @@
+package ceb
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestReadProcessUsage(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("process usage is only read on Linux")
+	}
+
+	require := require.New(t)
+
+	usage, err := readProcessUsage(os.Getpid())
+	require.NoError(err)
+	require.True(usage.RSSBytes > 0)
+	require.True(usage.CPUSeconds >= 0)
+
+	// Processes that don't exist are an error
+	_, err = readProcessUsage(-1)
+	require.Error(err)
+}
+
+func TestCPUPercent(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	metrics := func(t time.Time, cpu float64) *pb.Instance_Metrics {
+		ts, err := ptypes.TimestampProto(t)
+		require.NoError(err)
+		return &pb.Instance_Metrics{Time: ts, CpuSeconds: cpu}
+	}
+
+	prev := metrics(now, 10)
+	cur := metrics(now.Add(10*time.Second), 15)
+	require.Equal(50.0, cpuPercent(prev, cur, true))
+
+	// There is no usage without previous metrics of the same process
+	require.Equal(0.0, cpuPercent(nil, cur, true))
+	require.Equal(0.0, cpuPercent(prev, cur, false))
+}
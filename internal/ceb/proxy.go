@@ -0,0 +1,150 @@
+package ceb
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const envCEBServerProxy = "WAYPOINT_CEB_SERVER_PROXY"
+
+// serverProxy returns the HTTP proxy to connect to the server at addr
+// through, or nil to connect directly. The proxy is cfg.ServerProxy if it
+// is set, or else it is read from HTTPS_PROXY and NO_PROXY like other
+// HTTPS clients.
+func serverProxy(cfg *config, addr string) (*url.URL, error) {
+	if cfg.ServerProxy != nil {
+		return cfg.ServerProxy, nil
+	}
+
+	return http.ProxyFromEnvironment(&http.Request{
+		URL: &url.URL{Scheme: "https", Host: addr},
+	})
+}
+
+// parseProxy parses the URL of an HTTP proxy. The scheme defaults to
+// "http" like HTTPS_PROXY.
+func parseProxy(v string) (*url.URL, error) {
+	if !strings.Contains(v, "://") {
+		v = "http://" + v
+	}
+
+	u, err := url.Parse(v)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("proxy scheme must be http or https, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("proxy %q has no host", v)
+	}
+
+	return u, nil
+}
+
+// redactProxy returns the proxy URL without its password for logging.
+func redactProxy(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	redacted := *u
+	if _, ok := redacted.User.Password(); ok {
+		redacted.User = url.UserPassword(redacted.User.Username(), "redacted")
+	}
+
+	return redacted.String()
+}
+
+// dialProxy connects to addr through the HTTP proxy with a CONNECT request.
+// The connection to the server, including TLS, is then made over the
+// returned connection. An https proxy is connected to with TLS and
+// verified with the system roots. Credentials in the proxy URL are sent
+// with basic auth.
+func dialProxy(ctx context.Context, proxy *url.URL, addr string) (net.Conn, error) {
+	host := proxy.Host
+	if proxy.Port() == "" {
+		port := "80"
+		if proxy.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(proxy.Hostname(), port)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	if proxy.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: proxy.Hostname()})
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if u := proxy.User; u != nil {
+		password, _ := u.Password()
+		req.Header.Set("Proxy-Authorization", "Basic "+
+			base64.StdEncoding.EncodeToString([]byte(u.Username()+":"+password)))
+	}
+
+	// Stop waiting on the proxy if ctx is done before it responds.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy refused connection to %s: %s", addr, resp.Status)
+	}
+
+	// The proxy may have sent the start of the server's response already.
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+
+	return conn, nil
+}
+
+// bufferedConn is a connection whose first reads come from a buffer.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
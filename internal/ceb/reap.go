@@ -0,0 +1,142 @@
+package ceb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// children are the processes that the entrypoint started and waits for
+// itself with exec.Cmd, so that the reaper leaves them alone. The lock is
+// held while a process is started so that it can't be reaped before it is
+// added.
+var children = struct {
+	sync.Mutex
+	pids map[int]struct{}
+}{pids: map[int]struct{}{}}
+
+// startChild starts the process of cmd with start, which is usually
+// cmd.Start, and tracks it until waitChild is called.
+func startChild(cmd *exec.Cmd, start func() error) error {
+	children.Lock()
+	defer children.Unlock()
+
+	if err := start(); err != nil {
+		return err
+	}
+
+	children.pids[cmd.Process.Pid] = struct{}{}
+	return nil
+}
+
+// waitChild waits for the process of cmd started with startChild.
+func waitChild(cmd *exec.Cmd) error {
+	err := cmd.Wait()
+
+	if cmd.Process != nil {
+		children.Lock()
+		defer children.Unlock()
+		delete(children.pids, cmd.Process.Pid)
+	}
+
+	return err
+}
+
+// reapZombies reaps processes that exited and were reparented to the
+// entrypoint until ctx is done. This is only needed if the entrypoint is
+// PID 1, which is the parent of every orphaned process in a container.
+func reapZombies(ctx context.Context, log hclog.Logger) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGCHLD)
+	defer signal.Stop(ch)
+
+	log.Debug("reaping zombie processes")
+	for {
+		select {
+		case <-ch:
+			reapOrphans(log)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reapOrphans reaps the zombie children of the entrypoint that it didn't
+// start itself.
+func reapOrphans(log hclog.Logger) {
+	children.Lock()
+	defer children.Unlock()
+
+	pids, err := zombieChildren()
+	if err != nil {
+		log.Warn("error listing zombie processes", "error", err)
+		return
+	}
+
+	for _, pid := range pids {
+		if _, ok := children.pids[pid]; ok {
+			continue
+		}
+
+		var ws syscall.WaitStatus
+		if _, err := syscall.Wait4(pid, &ws, syscall.WNOHANG, nil); err != nil {
+			log.Debug("error reaping process", "pid", pid, "error", err)
+			continue
+		}
+
+		log.Trace("reaped zombie process", "pid", pid)
+	}
+}
+
+// zombieChildren returns the processes whose parent is the entrypoint
+// that exited and must be reaped. These are read from /proc.
+func zombieChildren() ([]int, error) {
+	entries, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	ppid := os.Getpid()
+	var result []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// The process may have exited since we listed them.
+		data, err := ioutil.ReadFile(filepath.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+
+		// The stat is "pid (name) state ppid ...", and the name can have
+		// spaces and parentheses.
+		stat := string(data)
+		idx := strings.LastIndexByte(stat, ')')
+		if idx == -1 {
+			continue
+		}
+		fields := strings.Fields(stat[idx+1:])
+		if len(fields) < 2 || fields[0] != "Z" {
+			continue
+		}
+		if parent, err := strconv.Atoi(fields[1]); err != nil || parent != ppid {
+			continue
+		}
+
+		result = append(result, pid)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package ceb
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReapOrphans(t *testing.T) {
+	require := require.New(t)
+
+	isZombie := func(pid int) func() bool {
+		return func() bool {
+			pids, err := zombieChildren()
+			require.NoError(err)
+			for _, p := range pids {
+				if p == pid {
+					return true
+				}
+			}
+
+			return false
+		}
+	}
+
+	// A child we started ourselves isn't reaped, so it can be waited for
+	tracked := exec.Command("true")
+	require.NoError(startChild(tracked, tracked.Start))
+	require.Eventually(isZombie(tracked.Process.Pid), 2*time.Second, 10*time.Millisecond)
+	reapOrphans(hclog.L())
+	require.NoError(waitChild(tracked))
+
+	// Any other zombie child is reaped
+	orphan := exec.Command("true")
+	require.NoError(orphan.Start())
+	pid := orphan.Process.Pid
+	require.Eventually(isZombie(pid), 2*time.Second, 10*time.Millisecond)
+	reapOrphans(hclog.L())
+
+	var ws syscall.WaitStatus
+	_, err := syscall.Wait4(pid, &ws, syscall.WNOHANG, nil)
+	require.Equal(syscall.ECHILD, err)
+}
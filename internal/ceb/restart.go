@@ -0,0 +1,210 @@
+package ceb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	envCEBReloadSignal = "WAYPOINT_CEB_RELOAD_SIGNAL"
+	envCEBStopSignal   = "WAYPOINT_CEB_STOP_SIGNAL"
+	envCEBStopTimeout  = "WAYPOINT_CEB_STOP_TIMEOUT"
+
+	envCEBRestartOnCrash    = "WAYPOINT_CEB_RESTART_ON_CRASH"
+	envCEBRestartMaxRetries = "WAYPOINT_CEB_RESTART_MAX_RETRIES"
+)
+
+var (
+	// crashBackoffMin and crashBackoffMax bound how long the entrypoint
+	// waits before restarting a child command that crashed. The wait
+	// doubles after each crash in a row.
+	crashBackoffMin = 1 * time.Second
+	crashBackoffMax = 1 * time.Minute
+
+	// crashResetTime is how long the child command must run for its
+	// crashes to no longer count as in a row.
+	crashResetTime = 1 * time.Minute
+
+	// defaultRestartMaxRetries is how many times in a row a child command
+	// that crashed is restarted by default.
+	defaultRestartMaxRetries = 5
+)
+
+// signals are the signals that can be sent to the child command by their
+// name.
+var signals = map[string]os.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGTERM": syscall.SIGTERM,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
+// parseSignal returns the signal with the name, such as "SIGHUP". The
+// name isn't case sensitive and the "SIG" prefix is optional.
+func parseSignal(v string) (os.Signal, error) {
+	name := strings.ToUpper(v)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+
+	sig, ok := signals[name]
+	if !ok {
+		var names []string
+		for k := range signals {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("unknown signal %q, must be one of: %s",
+			v, strings.Join(names, ", "))
+	}
+
+	return sig, nil
+}
+
+// settings returns the values of entrypoint settings, such as the restart
+// policy. Each setting is read from the static config variable with its
+// name if there is one, so that it can be set for an app with "waypoint
+// config set", or else it has its value in values, which are usually from
+// the environment of the entrypoint.
+func settings(ec *pb.EntrypointConfig, values map[string]string) map[string]string {
+	result := map[string]string{}
+	for k, v := range values {
+		result[k] = v
+	}
+
+	for _, cv := range ec.GetEnvVars() {
+		if _, ok := result[cv.Name]; ok && cv.Dynamic == nil && cv.File == nil {
+			result[cv.Name] = cv.Value
+		}
+	}
+
+	return result
+}
+
+// restartPolicy is how the child command is restarted when the dynamic
+// config changes.
+type restartPolicy struct {
+	// ReloadSignal, if set, is sent to the child command instead of
+	// restarting it, for apps that reload their config themselves. The
+	// new environment variables are only set when the child command is
+	// next started.
+	ReloadSignal os.Signal
+
+	// StopSignal asks the child command to exit before it is restarted.
+	// If it doesn't exit within StopTimeout, such as to finish the requests
+	// it is serving, it is killed.
+	StopSignal  os.Signal
+	StopTimeout time.Duration
+
+	// RestartOnCrash restarts the child command if it exits with a
+	// non-zero status or is killed by a signal, up to MaxRetries times in
+	// a row. If MaxRetries is zero, it is always restarted.
+	RestartOnCrash bool
+	MaxRetries     int
+}
+
+// restartPolicyFor returns the restart policy for the config. See settings
+// for where each setting is read from. The defaults are used for invalid
+// settings, which are returned as an error.
+func restartPolicyFor(cfg *config, ec *pb.EntrypointConfig) (*restartPolicy, error) {
+	result := &restartPolicy{
+		StopSignal:  syscall.SIGTERM,
+		StopTimeout: childStopTimeout,
+		MaxRetries:  defaultRestartMaxRetries,
+	}
+
+	values := settings(ec, map[string]string{
+		envCEBReloadSignal:      cfg.ReloadSignal,
+		envCEBStopSignal:        cfg.StopSignal,
+		envCEBStopTimeout:       cfg.StopTimeout,
+		envCEBRestartOnCrash:    cfg.RestartOnCrash,
+		envCEBRestartMaxRetries: cfg.RestartMaxRetries,
+	})
+
+	var resultErr error
+	if v := values[envCEBReloadSignal]; v != "" {
+		sig, err := parseSignal(v)
+		if err != nil {
+			resultErr = multierror.Append(resultErr, multierror.Prefix(err, envCEBReloadSignal+":"))
+		} else {
+			result.ReloadSignal = sig
+		}
+	}
+
+	if v := values[envCEBStopSignal]; v != "" {
+		sig, err := parseSignal(v)
+		if err != nil {
+			resultErr = multierror.Append(resultErr, multierror.Prefix(err, envCEBStopSignal+":"))
+		} else {
+			result.StopSignal = sig
+		}
+	}
+
+	if v := values[envCEBStopTimeout]; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			resultErr = multierror.Append(resultErr, fmt.Errorf(
+				"%s: must be a duration such as \"30s\", got %q", envCEBStopTimeout, v))
+		} else {
+			result.StopTimeout = d
+		}
+	}
+
+	if v := values[envCEBRestartOnCrash]; v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			resultErr = multierror.Append(resultErr, fmt.Errorf(
+				"%s: must be true or false, got %q", envCEBRestartOnCrash, v))
+		} else {
+			result.RestartOnCrash = b
+		}
+	}
+
+	if v := values[envCEBRestartMaxRetries]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			resultErr = multierror.Append(resultErr, fmt.Errorf(
+				"%s: must be a number of retries, got %q", envCEBRestartMaxRetries, v))
+		} else {
+			result.MaxRetries = n
+		}
+	}
+
+	return result, resultErr
+}
+
+// crashed returns true if err is from the child command exiting with a
+// non-zero status or being killed by a signal. Errors starting it aren't
+// crashes since a restart wouldn't fix them.
+func crashed(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr)
+}
+
+// crashBackoff returns how long to wait before restarting the child
+// command after it crashed the given number of times in a row.
+func crashBackoff(crashes int) time.Duration {
+	d := crashBackoffMin
+	for i := 1; i < crashes && d < crashBackoffMax; i++ {
+		d *= 2
+	}
+	if d > crashBackoffMax {
+		d = crashBackoffMax
+	}
+
+	return d
+}
@@ -0,0 +1,186 @@
+package ceb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestRestartPolicyFor(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		require := require.New(t)
+
+		policy, err := restartPolicyFor(&config{}, nil)
+		require.NoError(err)
+		require.Nil(policy.ReloadSignal)
+		require.Equal(syscall.SIGTERM, policy.StopSignal)
+		require.Equal(childStopTimeout, policy.StopTimeout)
+		require.False(policy.RestartOnCrash)
+		require.Equal(defaultRestartMaxRetries, policy.MaxRetries)
+	})
+
+	t.Run("restart on crash", func(t *testing.T) {
+		require := require.New(t)
+
+		policy, err := restartPolicyFor(&config{
+			RestartOnCrash:    "true",
+			RestartMaxRetries: "10",
+		}, &pb.EntrypointConfig{
+			EnvVars: []*pb.ConfigVar{{Name: envCEBRestartMaxRetries, Value: "0"}},
+		})
+		require.NoError(err)
+		require.True(policy.RestartOnCrash)
+		require.Equal(0, policy.MaxRetries)
+
+		_, err = restartPolicyFor(&config{
+			RestartOnCrash:    "sometimes",
+			RestartMaxRetries: "-1",
+		}, nil)
+		require.Error(err)
+		require.Contains(err.Error(), envCEBRestartOnCrash)
+		require.Contains(err.Error(), envCEBRestartMaxRetries)
+	})
+
+	t.Run("config variables take precedence", func(t *testing.T) {
+		require := require.New(t)
+
+		policy, err := restartPolicyFor(&config{
+			StopSignal:  "SIGINT",
+			StopTimeout: "10s",
+		}, &pb.EntrypointConfig{
+			EnvVars: []*pb.ConfigVar{
+				{Name: envCEBStopTimeout, Value: "2m"},
+				{Name: envCEBReloadSignal, Value: "hup"},
+			},
+		})
+		require.NoError(err)
+		require.Equal(syscall.SIGHUP, policy.ReloadSignal)
+		require.Equal(syscall.SIGINT, policy.StopSignal)
+		require.Equal(2*time.Minute, policy.StopTimeout)
+	})
+
+	t.Run("invalid settings use the defaults", func(t *testing.T) {
+		require := require.New(t)
+
+		policy, err := restartPolicyFor(&config{
+			StopSignal:  "SIGNOPE",
+			StopTimeout: "soon",
+		}, nil)
+		require.Error(err)
+		require.Contains(err.Error(), envCEBStopSignal)
+		require.Contains(err.Error(), envCEBStopTimeout)
+		require.Equal(syscall.SIGTERM, policy.StopSignal)
+		require.Equal(childStopTimeout, policy.StopTimeout)
+	})
+}
+
+func TestCEBRestartChildCmd(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// start runs a child command that ignores SIGUSR1 and SIGTERM.
+	start := func(t *testing.T, ec *pb.EntrypointConfig) (*CEB, *config, <-chan error) {
+		cfg := &config{ExecArgs: []string{"sh", "-c", "trap '' USR1 TERM; sleep 30"}}
+		ceb := &CEB{logger: hclog.L(), config: ec}
+		require.NoError(t, ceb.initChildCmd(ctx, cfg))
+
+		errCh := ceb.execChildCmd(ctx)
+		t.Cleanup(func() { ceb.childCmd.Process.Kill() })
+
+		// Give the shell time to set its traps
+		time.Sleep(200 * time.Millisecond)
+		return ceb, cfg, errCh
+	}
+
+	t.Run("reload signal", func(t *testing.T) {
+		require := require.New(t)
+
+		ceb, cfg, errCh := start(t, &pb.EntrypointConfig{
+			EnvVars: []*pb.ConfigVar{{Name: envCEBReloadSignal, Value: "SIGUSR1"}},
+		})
+		cmd := ceb.childCmd
+
+		// The child command keeps running
+		newErrCh, err := ceb.restartChildCmd(ctx, cfg, errCh, []string{"PORT=8080"})
+		require.NoError(err)
+		require.Equal(errCh, newErrCh)
+		require.Equal(cmd, ceb.childCmd)
+
+		select {
+		case err := <-errCh:
+			t.Fatalf("child command exited: %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+
+	t.Run("stop timeout", func(t *testing.T) {
+		require := require.New(t)
+
+		ceb, cfg, errCh := start(t, &pb.EntrypointConfig{
+			EnvVars: []*pb.ConfigVar{{Name: envCEBStopTimeout, Value: "100ms"}},
+		})
+		cmd := ceb.childCmd
+
+		// The child command ignores SIGTERM so it is killed after the
+		// timeout and started again.
+		started := time.Now()
+		newErrCh, err := ceb.restartChildCmd(ctx, cfg, errCh, []string{"PORT=8080"})
+		require.NoError(err)
+		require.NotEqual(cmd, ceb.childCmd)
+		require.NotEqual(errCh, newErrCh)
+		require.Contains(ceb.childCmd.Env, "PORT=8080")
+		require.Less(int64(time.Since(started)), int64(childStopTimeout))
+	})
+}
+
+func TestCrashBackoff(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(crashBackoffMin, crashBackoff(1))
+	require.Equal(2*crashBackoffMin, crashBackoff(2))
+	require.Equal(4*crashBackoffMin, crashBackoff(3))
+	require.Equal(crashBackoffMax, crashBackoff(100))
+}
+
+func TestRun_restartOnCrash(t *testing.T) {
+	require := require.New(t)
+
+	defer func(d time.Duration) { crashBackoffMin = d }(crashBackoffMin)
+	crashBackoffMin = 10 * time.Millisecond
+
+	td, err := ioutil.TempDir("", "ceb-crash")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+	path := filepath.Join(td, "runs")
+
+	// The child is started once and then restarted up to the max retries,
+	// and the entrypoint exits with its status.
+	err = Run(context.Background(),
+		WithExec([]string{"sh", "-c", "echo run >> " + path + "; exit 3"}),
+		func(ceb *CEB, cfg *config) error {
+			cfg.disable = true
+			cfg.RestartOnCrash = "true"
+			cfg.RestartMaxRetries = "2"
+			return nil
+		},
+	)
+	require.Error(err)
+
+	code, ok := ExitCode(err)
+	require.True(ok)
+	require.Equal(3, code)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal(3, strings.Count(string(data), "run"))
+}
@@ -3,6 +3,7 @@ package ceb
 import (
 	"context"
 	"crypto/tls"
+	"net"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
@@ -27,11 +28,29 @@ func (ceb *CEB) dialServer(ctx context.Context, cfg *config, isRetry bool) error
 	if !cfg.ServerTls {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
 	} else {
-		if cfg.ServerTlsSkipVerify {
-			grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(
-				credentials.NewTLS(&tls.Config{InsecureSkipVerify: true}),
-			))
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: cfg.ServerTlsSkipVerify,
+			RootCAs:            cfg.ServerTlsCAs,
 		}
+		if cfg.ServerTlsCert != nil {
+			tlsConfig.Certificates = []tls.Certificate{*cfg.ServerTlsCert}
+		}
+
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(
+			credentials.NewTLS(tlsConfig),
+		))
+	}
+
+	// Connect through a proxy if one is configured for the server.
+	proxy, err := serverProxy(cfg, cfg.ServerAddr)
+	if err != nil {
+		ceb.logger.Warn("invalid proxy, connecting to server directly", "err", err)
+		proxy = nil
+	}
+	if proxy != nil {
+		grpcOpts = append(grpcOpts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialProxy(ctx, proxy, addr)
+		}))
 	}
 
 	// Connect to this server
@@ -39,6 +58,7 @@ func (ceb *CEB) dialServer(ctx context.Context, cfg *config, isRetry bool) error
 		"addr", cfg.ServerAddr,
 		"tls", cfg.ServerTls,
 		"tls_skip_verify", cfg.ServerTlsSkipVerify,
+		"proxy", redactProxy(proxy),
 	)
 	conn, err := grpc.DialContext(ctx, cfg.ServerAddr, grpcOpts...)
 	if err != nil {
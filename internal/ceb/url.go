@@ -27,6 +27,13 @@ func (ceb *CEB) initURLService(ctx context.Context, port int, cfg *pb.Entrypoint
 		"labels", cfg.Labels,
 	)
 
+	// Don't route traffic to the app until it is ready.
+	select {
+	case <-ceb.readyCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
 	g, err := agent.NewAgent(L.Named("agent"))
 	if err != nil {
 		// NewAgent should never fail, this just sets some fields and returns
@@ -23,7 +23,7 @@ func (c *ArtifactBuildCommand) Run(args []string) int {
 	if err := c.Init(
 		WithArgs(args),
 		WithFlags(c.Flags()),
-		WithSingleApp(),
+		WithMultiApp(),
 	); err != nil {
 		return 1
 	}
@@ -52,6 +52,8 @@ func (c *ArtifactBuildCommand) Flags() *flag.Sets {
 			Default: true,
 			Usage:   "Push the artifact to the configured registry.",
 		})
+
+		initParallelFlag(f, &c.flagParallel)
 	})
 }
 
@@ -74,5 +76,9 @@ Alias: waypoint build
 
   Build a new versioned artifact from source.
 
+  If the project has multiple apps and -app isn't given, all apps are
+  built. Apps start after the apps they depend on with "depends_on", and
+  with -parallel several apps are built at once.
+
 ` + c.Flags().Help())
 }
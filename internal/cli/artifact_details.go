@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// labelGitCommit is the label that is set on operations with the git
+// commit of the project when it ran.
+const labelGitCommit = "waypoint/git-commit"
+
+// artifactImage returns the image reference and the digest of an artifact.
+// The artifact can only be decoded if it is from a builtin plugin, so both
+// are empty for other plugins or artifacts that aren't images.
+func artifactImage(a *pb.Artifact) (image, digest string) {
+	if a == nil || a.Artifact == nil {
+		return "", ""
+	}
+
+	var dyn ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(a.Artifact, &dyn); err != nil {
+		return "", ""
+	}
+
+	if v, ok := dyn.Message.(interface {
+		GetImage() string
+		GetTag() string
+	}); ok {
+		image = v.GetImage()
+		if tag := v.GetTag(); tag != "" {
+			image += ":" + tag
+		}
+	}
+	if v, ok := dyn.Message.(interface{ GetDigest() string }); ok {
+		digest = v.GetDigest()
+	}
+
+	return image, digest
+}
+
+// shortDigest returns the digest shortened the way docker shows image IDs.
+func shortDigest(digest string) string {
+	digest = strings.TrimPrefix(digest, "sha256:")
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+
+	return digest
+}
+
+// shortCommit returns the git commit shortened the way git shows it.
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+
+	return commit
+}
+
+// statusDuration returns how long the operation took, or an empty string
+// if it isn't complete.
+func statusDuration(s *pb.Status) string {
+	start, err := ptypes.Timestamp(s.GetStartTime())
+	if err != nil {
+		return ""
+	}
+	end, err := ptypes.Timestamp(s.GetCompleteTime())
+	if err != nil {
+		return ""
+	}
+
+	d := end.Sub(start)
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+
+	return d.Round(time.Second).String()
+}
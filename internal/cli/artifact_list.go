@@ -68,7 +68,8 @@ func (c *ArtifactListCommand) Run(args []string) int {
 
 		const bullet = "●"
 
-		table := terminal.NewTable("", "ID", "Registry", "Details", "Started", "Completed")
+		table := terminal.NewTable("", "ID", "Registry", "Builder", "Image", "Digest",
+			"Git Commit", "Duration", "Started", "Details")
 		for _, b := range resp.Artifacts {
 			// Determine our bullet
 			status := ""
@@ -88,12 +89,21 @@ func (c *ArtifactListCommand) Run(args []string) int {
 			}
 
 			// Parse our times
-			var startTime, completeTime string
+			var startTime string
 			if t, err := ptypes.Timestamp(b.Status.StartTime); err == nil {
 				startTime = humanize.Time(t)
 			}
-			if t, err := ptypes.Timestamp(b.Status.CompleteTime); err == nil {
-				completeTime = humanize.Time(t)
+
+			image, digest := artifactImage(b.Artifact)
+
+			// The git commit is set on the build that was pushed
+			var builder, commit string
+			if b.Build != nil {
+				builder = b.Build.Component.Name
+				commit = b.Build.Labels[labelGitCommit]
+			}
+			if v, ok := b.Labels[labelGitCommit]; ok {
+				commit = v
 			}
 
 			var (
@@ -141,9 +151,13 @@ func (c *ArtifactListCommand) Run(args []string) int {
 					status,
 					c.flagId.FormatId(b.Sequence, b.Id),
 					b.Component.Name,
-					details[0],
+					builder,
+					image,
+					shortDigest(digest),
+					shortCommit(commit),
+					statusDuration(b.Status),
 					startTime,
-					completeTime,
+					details[0],
 				}, []string{
 					statusColor,
 				},
@@ -151,13 +165,13 @@ func (c *ArtifactListCommand) Run(args []string) int {
 
 			if len(details[1:]) > 0 {
 				for _, dr := range details[1:] {
-					table.Rich([]string{"", "", "", dr}, nil)
+					table.Rich(artifactDetailsRow(dr), nil)
 				}
 			}
 
 			if len(extraDetails) > 0 {
 				for _, dr := range extraDetails {
-					table.Rich([]string{"", "", "", dr}, nil)
+					table.Rich(artifactDetailsRow(dr), nil)
 				}
 			}
 		}
@@ -173,6 +187,12 @@ func (c *ArtifactListCommand) Run(args []string) int {
 	return 0
 }
 
+// artifactDetailsRow returns a row of the table with only the details column
+// set, for the details after the first.
+func artifactDetailsRow(detail string) []string {
+	return []string{"", "", "", "", "", "", "", "", "", detail}
+}
+
 func (c *ArtifactListCommand) displayJson(artifacts []*pb.PushedArtifact) error {
 	var output []map[string]interface{}
 
@@ -187,6 +207,10 @@ func (c *ArtifactListCommand) displayJson(artifacts []*pb.PushedArtifact) error
 		i["status"] = c.statusJson(art.Status)
 		i["workspace"] = art.Workspace.Workspace
 		i["build"] = c.buildJson(art.Build)
+		if image, digest := artifactImage(art.Artifact); image != "" {
+			i["image"] = image
+			i["digest"] = digest
+		}
 
 		output = append(output, i)
 	}
@@ -268,5 +292,8 @@ Usage: waypoint artifact list [options]
   Lists the artifacts that are pushed to a registry. This does not
   list the artifacts that are just part of local builds.
 
+  The image and digest are shown for image artifacts of builtin plugins.
+  The digest is set once an image is pushed to a remote registry.
+
 ` + c.Flags().Help())
 }
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type AuditListCommand struct {
+	*baseCommand
+
+	flagProject string
+	flagLimit   int
+	flagJSON    bool
+}
+
+func (c *AuditListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	req := &pb.ListAuditEventsRequest{}
+	if c.flagProject != "" {
+		req.Project = &pb.Ref_Project{Project: c.flagProject}
+	}
+
+	// Read pages until we have enough events or there are no more.
+	var events []*pb.AuditEvent
+	for {
+		if c.flagLimit > 0 {
+			req.PageSize = uint32(c.flagLimit - len(events))
+		}
+
+		resp, err := c.project.Client().ListAuditEvents(c.Ctx, req)
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		events = append(events, resp.Events...)
+		if resp.NextPageToken == "" || (c.flagLimit > 0 && len(events) >= c.flagLimit) {
+			break
+		}
+
+		req.PageToken = resp.NextPageToken
+	}
+
+	if c.flagJSON {
+		for _, event := range events {
+			data, err := protojson.Marshal(event)
+			if err != nil {
+				c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+				return 1
+			}
+
+			c.ui.Output(string(data))
+		}
+
+		return 0
+	}
+
+	table := terminal.NewTable("Time", "User", "Endpoint", "Target", "Error")
+	for _, event := range events {
+		var t string
+		if ts, err := ptypes.Timestamp(event.Time); err == nil {
+			t = ts.Local().Format("2006-01-02 15:04:05")
+		}
+
+		var target []string
+		for _, v := range []string{event.Project, event.Application} {
+			if v != "" {
+				target = append(target, v)
+			}
+		}
+		if event.Workspace != "" {
+			target = append(target, "("+event.Workspace+")")
+		}
+
+		var colors []string
+		if event.Error != "" {
+			colors = []string{"", "", "", "", terminal.Red}
+		}
+
+		table.Rich([]string{
+			t,
+			event.User,
+			event.Endpoint,
+			strings.Join(target, " "),
+			event.Error,
+		}, colors)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *AuditListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage:      "Only list events for this project.",
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "limit",
+			Target:  &c.flagLimit,
+			Default: 50,
+			Usage:   "Maximum number of events to list. If this is zero, all events are listed.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "json",
+			Target: &c.flagJSON,
+			Usage:  "Output each event as a line of JSON, including the request summary.",
+		})
+	})
+}
+
+func (c *AuditListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *AuditListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *AuditListCommand) Synopsis() string {
+	return "List the audit log of changes to the server."
+}
+
+func (c *AuditListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint audit list [options]
+
+  List the audit log of changes to the server, newest first.
+
+  The server records every call that changes it and every call that was
+  denied, including who made the call and the project it was for. This
+  requires admin permission.
+
+` + c.Flags().Help())
+}
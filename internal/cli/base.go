@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/adrg/xdg"
 	"github.com/hashicorp/go-hclog"
@@ -47,6 +49,10 @@ type baseCommand struct {
 	cfg    *config.Config
 	cfgCtx *hcl.EvalContext
 
+	// cfgVars are the values assigned to the input variables of cfg as HCL
+	// expressions. They are sent with jobs so runners use the same values.
+	cfgVars map[string]string
+
 	// UI is used to write to the CLI.
 	ui terminal.UI
 
@@ -72,6 +78,10 @@ type baseCommand struct {
 	// flagPlain is whether the output should be in plain mode.
 	flagPlain bool
 
+	// flagParallel is the number of apps DoApp operates on at once. This
+	// is set by commands that support -parallel.
+	flagParallel int
+
 	// flagLabels are set via -label if flagSetOperation is set.
 	flagLabels map[string]string
 
@@ -82,6 +92,16 @@ type baseCommand struct {
 	// flagRemoteSource are the remote data source overrides for jobs.
 	flagRemoteSource map[string]string
 
+	// flagVars and flagVarFiles assign input variables of the configuration
+	// if flagSetOperation is set.
+	flagVars     []string
+	flagVarFiles []string
+
+	// flagTimeout is the maximum time operations can run if flagSetOperation
+	// is set. Ctx is canceled after this, which cancels the job.
+	flagTimeout time.Duration
+	cancelCtx   context.CancelFunc
+
 	// flagApp is the app to target.
 	flagApp string
 
@@ -91,6 +111,10 @@ type baseCommand struct {
 	// flagConnection contains manual flag-based connection info.
 	flagConnection clicontext.Config
 
+	// flagContext is the name of the context to use for this invocation
+	// rather than the default context.
+	flagContext string
+
 	// args that were present after parsing flags
 	args []string
 
@@ -101,6 +125,10 @@ type baseCommand struct {
 // Close cleans up any resources that the command created. This should be
 // defered by any CLI command that embeds baseCommand in the Run command.
 func (c *baseCommand) Close() error {
+	if c.cancelCtx != nil {
+		c.cancelCtx()
+	}
+
 	// Close our UI if it implements it. The glint-based UI does for example
 	// to finish up all the CLI output.
 	if closer, ok := c.ui.(io.Closer); ok && closer != nil {
@@ -145,26 +173,30 @@ func (c *baseCommand) Init(opts ...Option) error {
 	}
 	c.args = baseCfg.Flags.Args()
 
+	// Bound the command with the operation timeout. Operations stream their
+	// jobs with Ctx, so the job is canceled once this expires.
+	if c.flagTimeout > 0 {
+		c.Ctx, c.cancelCtx = context.WithTimeout(c.Ctx, c.flagTimeout)
+	}
+
 	// Reset the UI to plain if that was set
 	if c.flagPlain {
-		c.ui = terminal.NonInteractiveUI(c.Ctx)
+		c.ui = newPlainUI()
 	}
 
 	// With the flags we now know what workspace we're targeting
 	c.refWorkspace = &pb.Ref_Workspace{Workspace: c.flagWorkspace}
 
 	// Setup our base config path
-	homeConfigPath, err := xdg.ConfigFile("waypoint/.ignore")
+	homeConfigPath, err := homeConfigDir()
 	if err != nil {
 		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 		return err
 	}
-	homeConfigPath = filepath.Dir(homeConfigPath)
 	c.Log.Debug("home configuration directory", "path", homeConfigPath)
 
 	// Setup our base directory for context management
-	contextStorage, err := clicontext.NewStorage(
-		clicontext.WithDir(filepath.Join(homeConfigPath, "context")))
+	contextStorage, err := newContextStorage(homeConfigPath)
 	if err != nil {
 		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 		return err
@@ -253,9 +285,10 @@ func (c *baseCommand) Init(opts ...Option) error {
 	}
 
 	// If this is a single app mode then make sure that we only have
-	// one app or that we have an app target.
+	// one app or that we have an app target. In multi app mode, no app
+	// target means all of them.
 	if baseCfg.AppTargetRequired {
-		if c.refApp == nil {
+		if c.refApp == nil && !(baseCfg.AppTargetMulti && len(c.cfg.Apps) > 1) {
 			if len(c.cfg.Apps) != 1 {
 				c.ui.Output(errAppModeSingle, terminal.WithErrorStyle())
 				return ErrSentinel
@@ -276,6 +309,10 @@ func (c *baseCommand) Init(opts ...Option) error {
 // parallelization, waiting, and error handling. Your code should be
 // thread-safe.
 //
+// Apps are called after the apps they depend on with depends_on, up to
+// flagParallel at a time. Apps that depend on an app that failed are
+// skipped.
+//
 // If any error is returned, the caller should just exit. The error handling
 // including messaging to the user is handling by this function call.
 //
@@ -283,40 +320,198 @@ func (c *baseCommand) Init(opts ...Option) error {
 // the callback closure properties to cancel the passed in context. This
 // will stop any remaining callbacks and exit early.
 func (c *baseCommand) DoApp(ctx context.Context, f func(context.Context, *clientpkg.App) error) error {
+	// The apps are in dependency order so that running them one at a time
+	// respects depends_on. Dependencies are ignored if only one app is
+	// targeted.
 	var appTargets []string
+	deps := map[string][]string{}
 	if c.refApp != nil {
 		appTargets = []string{c.refApp.Application}
 	} else if c.cfg != nil {
-		for _, appCfg := range c.cfg.Apps {
+		apps, err := c.cfg.AppsOrdered()
+		if err != nil {
+			return err
+		}
+
+		for _, appCfg := range apps {
 			appTargets = append(appTargets, appCfg.Name)
+			deps[appCfg.Name] = appCfg.DependsOn
 		}
 	}
 
 	var apps []*clientpkg.App
 	for _, appName := range appTargets {
-		app := c.project.App(appName)
+		app := c.app(appName)
 		c.Log.Debug("will operate on app", "name", appName)
 		apps = append(apps, app)
 	}
 
-	// Just a serialize loop for now, one day we'll parallelize.
-	var finalErr error
+	parallel := c.flagParallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		finalErr error
+		failed   = map[string]bool{}
+		done     = map[string]chan struct{}{}
+		sem      = make(chan struct{}, parallel)
+	)
 	for _, app := range apps {
-		// Support cancellation
-		if err := ctx.Err(); err != nil {
-			return err
+		done[app.Ref().Application] = make(chan struct{})
+	}
+
+	// run waits for the apps that the app depends on and then for a free
+	// slot, and then calls f.
+	run := func(app *clientpkg.App) {
+		name := app.Ref().Application
+		defer close(done[name])
+
+		// If an app this depends on failed, we skip this app since it
+		// likely needs the result.
+		for _, dep := range deps[name] {
+			select {
+			case <-done[dep]:
+			case <-ctx.Done():
+				return
+			}
+
+			lock.Lock()
+			depFailed := failed[dep]
+			failed[name] = depFailed
+			lock.Unlock()
+
+			if depFailed {
+				app.UI.Output("Skipping app %q since app %q that it depends on failed.",
+					name, dep, terminal.WithErrorStyle())
+				return
+			}
+		}
+
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+		case <-ctx.Done():
+			return
 		}
 
 		if err := f(ctx, app); err != nil {
+			lock.Lock()
+			defer lock.Unlock()
+
+			failed[name] = true
 			if err != ErrSentinel {
 				finalErr = multierror.Append(finalErr, err)
 			}
 		}
 	}
 
+	for _, app := range apps {
+		// Support cancellation
+		if ctx.Err() != nil {
+			break
+		}
+
+		// With a parallelism of one we run each app in order. Otherwise
+		// each app waits in its own goroutine.
+		if parallel == 1 {
+			run(app)
+			continue
+		}
+
+		wg.Add(1)
+		go func(app *clientpkg.App) {
+			defer wg.Done()
+			run(app)
+		}(app)
+	}
+
+	wg.Wait()
+	if ctx.Err() == context.DeadlineExceeded && c.flagTimeout > 0 {
+		c.ui.Output("The operation didn't complete within the timeout of %s and was canceled.",
+			c.flagTimeout, terminal.WithErrorStyle())
+	}
+	if err := ctx.Err(); err != nil && finalErr == nil {
+		return err
+	}
+
 	return finalErr
 }
 
+// app returns the client for the app. Remote jobs of the app target the
+// runners that its runner stanzas select.
+func (c *baseCommand) app(name string) *clientpkg.App {
+	app := c.project.App(name)
+	if c.cfg == nil {
+		return app
+	}
+
+	appCfg, ok := c.cfg.AppConfig(name)
+	if !ok {
+		return app
+	}
+
+	app.RunnerLabels = func(job *pb.Job) map[string]string {
+		// The overrides of the app are matched with the labels of the
+		// operation the same way as the runner, which adds the labels of
+		// the project and app to the labels of the job.
+		labels := map[string]string{}
+		for _, m := range []map[string]string{c.cfg.Labels, appCfg.Labels, job.Labels} {
+			for k, v := range m {
+				labels[k] = v
+			}
+		}
+
+		scoped := appCfg.Scoped(job.Workspace.GetWorkspace(), labels)
+		return scoped.RunnerLabels(jobStage(job))
+	}
+
+	return app
+}
+
+// artifactLabels returns the labels that the artifact to deploy for the
+// app must have, which select the variant of the build matrix that its
+// deploy stanza selects.
+func (c *baseCommand) artifactLabels(name string) map[string]string {
+	if c.cfg == nil {
+		return nil
+	}
+
+	appCfg, ok := c.cfg.AppConfig(name)
+	if !ok {
+		return nil
+	}
+
+	labels := map[string]string{}
+	for _, m := range []map[string]string{c.cfg.Labels, appCfg.Labels, c.flagLabels} {
+		for k, v := range m {
+			labels[k] = v
+		}
+	}
+
+	return appCfg.Scoped(c.project.WorkspaceRef().Workspace, labels).ArtifactLabels()
+}
+
+// jobStage returns the stage of the app that the job runs, such as
+// "build", or empty if the job isn't part of a stage.
+func jobStage(job *pb.Job) string {
+	switch job.Operation.(type) {
+	case *pb.Job_Build, *pb.Job_Push:
+		return "build"
+
+	case *pb.Job_Deploy, *pb.Job_Destroy:
+		return "deploy"
+
+	case *pb.Job_Release:
+		return "release"
+
+	default:
+		return ""
+	}
+}
+
 // logError logs an error and outputs it to the UI.
 func (c *baseCommand) logError(log hclog.Logger, prefix string, err error) {
 	if err == ErrSentinel {
@@ -342,23 +537,36 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 			Name:    "plain",
 			Target:  &c.flagPlain,
 			Default: false,
-			Usage:   "Plain output: no colors, no animation.",
+			Usage: "Plain output: no colors, no animation. Each line is " +
+				"timestamped and progress is written as lines, which is best for " +
+				"CI logs. This is the default if the CI env var is set.",
 		})
 
 		f.StringVar(&flag.StringVar{
-			Name:    "app",
-			Target:  &c.flagApp,
-			Default: "",
+			Name:       "app",
+			Target:     &c.flagApp,
+			Completion: c.predictApps(),
+			Default:    "",
 			Usage: "App to target. Certain commands require a single app target for " +
 				"Waypoint configurations with multiple apps. If you have a single app, " +
 				"then this can be ignored.",
 		})
 
 		f.StringVar(&flag.StringVar{
-			Name:    "workspace",
-			Target:  &c.flagWorkspace,
-			Default: "default",
-			Usage:   "Workspace to operate in.",
+			Name:       "workspace",
+			Target:     &c.flagWorkspace,
+			Completion: c.predictWorkspaces(),
+			Default:    "default",
+			Usage:      "Workspace to operate in.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:       "context",
+			Target:     &c.flagContext,
+			Completion: c.predictContexts(),
+			Usage: "Context to use to connect to the server. This defaults to " +
+				"WAYPOINT_CONTEXT if set, otherwise the default context that is set " +
+				"with \"waypoint context use\".",
 		})
 	}
 
@@ -378,6 +586,28 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 				"unless 'runner.default' is set in your configuration.",
 		})
 
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "var",
+			Target: &c.flagVars,
+			Usage: "Set an input variable of the configuration, such as \"image=nginx\". " +
+				"Can be specified multiple times.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "var-file",
+			Target: &c.flagVarFiles,
+			Usage: "File with values for input variables of the configuration. Can be " +
+				"specified multiple times. Values set with -var take precedence.",
+		})
+
+		f.DurationVar(&flag.DurationVar{
+			Name:   "timeout",
+			Target: &c.flagTimeout,
+			Usage: "Maximum time the operation can run, such as \"30m\". If it " +
+				"runs longer, it's canceled along with its job on the runner. " +
+				"There is no limit by default.",
+		})
+
 		f.StringMapVar(&flag.StringMapVar{
 			Name:   "remote-source",
 			Target: &c.flagRemoteSource,
@@ -418,6 +648,18 @@ func (c *baseCommand) flagSet(bit flagSetBit, f func(*flag.Sets)) *flag.Sets {
 	return set
 }
 
+// initParallelFlag adds the -parallel flag for commands that operate on
+// multiple apps with DoApp.
+func initParallelFlag(f *flag.Set, target *int) {
+	f.IntVar(&flag.IntVar{
+		Name:    "parallel",
+		Target:  target,
+		Default: 1,
+		Usage: "Number of apps to operate on at once when the project has multiple " +
+			"apps. Apps still wait for the apps they depend on.",
+	})
+}
+
 // flagSetBit is used with baseCommand.flagSet
 type flagSetBit uint
 
@@ -438,3 +680,21 @@ so you can specify the app to target using the "-app" flag.
 
 	reAppTarget = regexp.MustCompile(`^(?P<project>[-0-9A-Za-z_]+)/(?P<app>[-0-9A-Za-z_]+)$`)
 )
+
+// homeConfigDir returns the directory of the configuration of the CLI in
+// the home directory of the user, such as the contexts.
+func homeConfigDir() (string, error) {
+	path, err := xdg.ConfigFile("waypoint/.ignore")
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Dir(path), nil
+}
+
+// newContextStorage returns the storage for CLI contexts in the given home
+// configuration directory.
+func newContextStorage(homeConfigPath string) (*clicontext.Storage, error) {
+	return clicontext.NewStorage(
+		clicontext.WithDir(filepath.Join(homeConfigPath, "context")))
+}
@@ -3,10 +3,9 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 
-	"github.com/hashicorp/hcl/v2/hclsimple"
-
 	"github.com/hashicorp/waypoint/internal/clicontext"
 	clientpkg "github.com/hashicorp/waypoint/internal/client"
 	configpkg "github.com/hashicorp/waypoint/internal/config"
@@ -52,8 +51,13 @@ func (c *baseCommand) initConfigPath() (string, error) {
 func (c *baseCommand) initConfigLoad(path string) (*configpkg.Config, error) {
 	c.cfgCtx = configpkg.EvalContext(filepath.Dir(path))
 
-	var cfg configpkg.Config
-	if err := hclsimple.DecodeFile(path, c.cfgCtx, &cfg); err != nil {
+	vars, err := c.initConfigVars()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := configpkg.Load(path, c.cfgCtx, vars)
+	if err != nil {
 		return nil, err
 	}
 
@@ -67,7 +71,45 @@ func (c *baseCommand) initConfigLoad(path string) (*configpkg.Config, error) {
 		return nil, err
 	}
 
-	return &cfg, nil
+	// Keep the assigned values of declared variables for jobs. Values from
+	// the environment are included since remote runners don't have it.
+	c.cfgVars = map[string]string{}
+	values := c.cfgCtx.Variables["var"]
+	for _, v := range cfg.Variables {
+		if _, ok := vars[v.Name]; ok {
+			c.cfgVars[v.Name] = configpkg.VariableExpr(values.GetAttr(v.Name))
+		}
+	}
+
+	return cfg, nil
+}
+
+// initConfigVars returns the values assigned to input variables. Values
+// from WP_VAR_ environment variables are overridden by variable files,
+// which are overridden by -var, each in the order they are given.
+func (c *baseCommand) initConfigVars() (map[string]*configpkg.VariableValue, error) {
+	result := configpkg.EnvVariables(os.Environ())
+	for _, path := range c.flagVarFiles {
+		values, err := configpkg.LoadVariableFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, v := range values {
+			result[name] = v
+		}
+	}
+
+	for _, raw := range c.flagVars {
+		name, v, err := configpkg.ParseVariableFlag(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		result[name] = v
+	}
+
+	return result, nil
 }
 
 // initClient initializes the client.
@@ -82,7 +124,7 @@ func (c *baseCommand) initClient() (*clientpkg.Project, error) {
 	var err error
 	connectOpts := []serverclient.ConnectOption{
 		serverclient.FromContextConfig(flagConnection),
-		serverclient.FromContext(c.contextStorage, ""),
+		serverclient.FromContext(c.contextStorage, c.flagContext),
 		serverclient.FromEnv(),
 	}
 	c.clientContext, err = serverclient.ContextConfig(connectOpts...)
@@ -98,6 +140,7 @@ func (c *baseCommand) initClient() (*clientpkg.Project, error) {
 		clientpkg.WithWorkspaceRef(c.refWorkspace),
 		clientpkg.WithLabels(c.flagLabels),
 		clientpkg.WithSourceOverrides(c.flagRemoteSource),
+		clientpkg.WithVariables(c.cfgVars),
 	}
 	if !c.flagRemote {
 		opts = append(opts, clientpkg.WithLocal())
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type BuildInspectCommand struct {
+	*baseCommand
+
+	flagJson bool
+}
+
+func (c *BuildInspectCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	ref := &pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: args[0]},
+	}
+	if v, err := strconv.ParseUint(args[0], 10, 64); err == nil {
+		ref.Target = &pb.Ref_Operation_Sequence{
+			Sequence: &pb.Ref_OperationSeq{
+				Application: c.refApp,
+				Number:      v,
+			},
+		}
+	}
+
+	build, err := c.project.Client().GetBuild(c.Ctx, &pb.GetBuildRequest{Ref: ref})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	if c.flagJson {
+		if err := outputJson(build); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	values := []terminal.NamedValue{
+		{Name: "ID", Value: build.Id},
+		{Name: "Sequence", Value: build.Sequence},
+		{Name: "Application", Value: build.Application.Application},
+		{Name: "Workspace", Value: build.Workspace.Workspace},
+		{Name: "Builder", Value: build.Component.Name},
+		{Name: "Status", Value: strings.ToLower(build.Status.State.String())},
+	}
+	if ts, err := ptypes.Timestamp(build.Status.StartTime); err == nil {
+		values = append(values, terminal.NamedValue{
+			Name: "Started", Value: ts.Local().Format("2006-01-02 15:04:05")})
+	}
+	if d := statusDuration(build.Status); d != "" {
+		values = append(values, terminal.NamedValue{Name: "Duration", Value: d})
+	}
+	if image, digest := artifactImage(build.Artifact); image != "" {
+		values = append(values, terminal.NamedValue{Name: "Image", Value: image})
+		if digest != "" {
+			values = append(values, terminal.NamedValue{Name: "Digest", Value: digest})
+		}
+	}
+	if v := build.Labels[labelGitCommit]; v != "" {
+		values = append(values, terminal.NamedValue{Name: "Git Commit", Value: v})
+	}
+	if build.JobId != "" {
+		values = append(values, terminal.NamedValue{Name: "Job ID", Value: build.JobId})
+	}
+	if err := build.Status.Error; err != nil {
+		values = append(values, terminal.NamedValue{Name: "Error", Value: err.Message})
+	}
+
+	c.ui.NamedValues(values, terminal.WithInfoStyle())
+
+	if len(build.Labels) > 0 {
+		keys := make([]string, 0, len(build.Labels))
+		for k := range build.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		labels := make([]terminal.NamedValue, 0, len(keys))
+		for _, k := range keys {
+			labels = append(labels, terminal.NamedValue{Name: k, Value: build.Labels[k]})
+		}
+
+		c.ui.Output("Labels", terminal.WithHeaderStyle())
+		c.ui.NamedValues(labels, terminal.WithInfoStyle())
+	}
+
+	return 0
+}
+
+func (c *BuildInspectCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *BuildInspectCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *BuildInspectCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *BuildInspectCommand) Synopsis() string {
+	return "Show the details of a build."
+}
+
+func (c *BuildInspectCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint build inspect [options] ID
+
+  Show the details of a build, including the artifact and all its labels.
+
+  The build is given by its ID or sequence number.
+
+` + c.Flags().Help())
+}
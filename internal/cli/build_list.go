@@ -7,6 +7,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	clientpkg "github.com/hashicorp/waypoint/internal/client"
@@ -20,6 +21,7 @@ type BuildListCommand struct {
 	*baseCommand
 
 	flagWorkspaceAll bool
+	flagJson         bool
 	flagId           idFormat
 }
 
@@ -53,9 +55,18 @@ func (c *BuildListCommand) Run(args []string) int {
 		}
 		sort.Sort(serversort.BuildStartDesc(resp.Builds))
 
+		if c.flagJson {
+			var msgs []proto.Message
+			for _, b := range resp.Builds {
+				msgs = append(msgs, b)
+			}
+
+			return outputJsonList(msgs)
+		}
+
 		const bullet = "●"
 
-		table := terminal.NewTable("", "ID", "Workspace", "Builder", "Started", "Completed")
+		table := terminal.NewTable("", "ID", "Workspace", "Builder", "Image", "Digest", "Git Commit", "Duration", "Started")
 		for _, b := range resp.Builds {
 			// Determine our bullet
 			status := ""
@@ -75,21 +86,23 @@ func (c *BuildListCommand) Run(args []string) int {
 			}
 
 			// Parse our times
-			var startTime, completeTime string
+			var startTime string
 			if t, err := ptypes.Timestamp(b.Status.StartTime); err == nil {
 				startTime = humanize.Time(t)
 			}
-			if t, err := ptypes.Timestamp(b.Status.CompleteTime); err == nil {
-				completeTime = humanize.Time(t)
-			}
+
+			image, digest := artifactImage(b.Artifact)
 
 			table.Rich([]string{
 				status,
 				c.flagId.FormatId(b.Sequence, b.Id),
 				b.Workspace.Workspace,
 				b.Component.Name,
+				image,
+				shortDigest(digest),
+				shortCommit(b.Labels[labelGitCommit]),
+				statusDuration(b.Status),
 				startTime,
-				completeTime,
 			}, []string{
 				statusColor,
 			})
@@ -115,6 +128,7 @@ func (c *BuildListCommand) Flags() *flag.Sets {
 			Usage:  "List builds in all workspaces for this project and application.",
 		})
 
+		initJsonFlag(f, &c.flagJson)
 		initIdFormat(f, &c.flagId)
 	})
 }
@@ -133,8 +147,13 @@ func (c *BuildListCommand) Synopsis() string {
 
 func (c *BuildListCommand) Help() string {
 	return formatHelp(`
-Usage: waypoint artifact list-builds [options]
+Usage: waypoint build list [options]
+Alias: waypoint artifact list-builds
+
   List builds.
 
+  The image and digest are shown for builds of image artifacts by builtin
+  plugins. Use "waypoint build inspect" to see all the details of a build.
+
 ` + c.Flags().Help())
 }
@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/serverclient"
+)
+
+// completeTimeout is how long completion waits for the server, so that a
+// server that can't be reached doesn't hang the shell.
+const completeTimeout = 2 * time.Second
+
+// predictServer returns a predictor that completes with the values that fn
+// returns from the server. Flags aren't parsed during completion, so this
+// connects with the context or env vars like commands do by default. If
+// anything fails, nothing is completed.
+func (c *baseCommand) predictServer(
+	fn func(context.Context, pb.WaypointClient, complete.Args) ([]string, error),
+) complete.Predictor {
+	return complete.PredictFunc(func(args complete.Args) []string {
+		homeConfigPath, err := homeConfigDir()
+		if err != nil {
+			return nil
+		}
+		st, err := newContextStorage(homeConfigPath)
+		if err != nil {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(c.Ctx, completeTimeout)
+		defer cancel()
+
+		conn, err := serverclient.Connect(ctx,
+			serverclient.FromContext(st, completeFlag(args, "context")),
+			serverclient.FromEnv(),
+		)
+		if err != nil {
+			return nil
+		}
+		defer conn.Close()
+
+		result, err := fn(ctx, pb.NewWaypointClient(conn), args)
+		if err != nil {
+			return nil
+		}
+
+		return result
+	})
+}
+
+// predictProjects completes the names of the projects on the server.
+func (c *baseCommand) predictProjects() complete.Predictor {
+	return c.predictServer(func(ctx context.Context, client pb.WaypointClient, _ complete.Args) ([]string, error) {
+		resp, err := client.ListProjects(ctx, &empty.Empty{})
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]string, 0, len(resp.Projects))
+		for _, p := range resp.Projects {
+			result = append(result, p.Project)
+		}
+
+		return result, nil
+	})
+}
+
+// predictWorkspaces completes the names of the workspaces on the server.
+func (c *baseCommand) predictWorkspaces() complete.Predictor {
+	return c.predictServer(func(ctx context.Context, client pb.WaypointClient, _ complete.Args) ([]string, error) {
+		resp, err := client.ListWorkspaces(ctx, &empty.Empty{})
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]string, 0, len(resp.Workspaces))
+		for _, w := range resp.Workspaces {
+			result = append(result, w.Name)
+		}
+
+		return result, nil
+	})
+}
+
+// predictRunners completes the IDs of the runners known to the server.
+func (c *baseCommand) predictRunners() complete.Predictor {
+	return c.predictServer(func(ctx context.Context, client pb.WaypointClient, _ complete.Args) ([]string, error) {
+		resp, err := client.ListRunners(ctx, &empty.Empty{})
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]string, 0, len(resp.Runners))
+		for _, r := range resp.Runners {
+			result = append(result, r.Id)
+		}
+
+		return result, nil
+	})
+}
+
+// predictApps completes the names of the apps in the configuration of the
+// current directory.
+func (c *baseCommand) predictApps() complete.Predictor {
+	return complete.PredictFunc(func(complete.Args) []string {
+		cfg, err := c.initConfig(true)
+		if err != nil || cfg == nil {
+			return nil
+		}
+
+		result := make([]string, 0, len(cfg.Apps))
+		for _, app := range cfg.Apps {
+			result = append(result, app.Name)
+		}
+
+		return result
+	})
+}
+
+// predictDeployments completes the sequence numbers of the deployments of
+// the app in the configuration of the current directory. If there are
+// multiple apps, the app must be given with -app.
+func (c *baseCommand) predictDeployments() complete.Predictor {
+	return c.predictServer(func(ctx context.Context, client pb.WaypointClient, args complete.Args) ([]string, error) {
+		cfg, err := c.initConfig(true)
+		if err != nil || cfg == nil {
+			return nil, err
+		}
+
+		app := completeFlag(args, "app")
+		if app == "" && len(cfg.Apps) == 1 {
+			app = cfg.Apps[0].Name
+		}
+		if app == "" {
+			return nil, nil
+		}
+
+		workspace := completeFlag(args, "workspace")
+		if workspace == "" {
+			workspace = "default"
+		}
+
+		resp, err := client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
+			Application: &pb.Ref_Application{
+				Project:     cfg.Project,
+				Application: app,
+			},
+			Workspace:     &pb.Ref_Workspace{Workspace: workspace},
+			PhysicalState: pb.Operation_CREATED,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]string, 0, len(resp.Deployments))
+		for _, d := range resp.Deployments {
+			result = append(result, strconv.FormatUint(d.Sequence, 10))
+		}
+
+		return result, nil
+	})
+}
+
+// predictContexts completes the names of the CLI contexts.
+func (c *baseCommand) predictContexts() complete.Predictor {
+	return complete.PredictFunc(func(complete.Args) []string {
+		homeConfigPath, err := homeConfigDir()
+		if err != nil {
+			return nil
+		}
+		st, err := newContextStorage(homeConfigPath)
+		if err != nil {
+			return nil
+		}
+
+		result, err := st.List()
+		if err != nil {
+			return nil
+		}
+
+		return result
+	})
+}
+
+// completeFlag returns the value of the flag with the given name in the
+// words that are already typed, or an empty string if it isn't set.
+func completeFlag(args complete.Args, name string) string {
+	words := args.Completed
+	for i, w := range words {
+		if !strings.HasPrefix(w, "-") {
+			continue
+		}
+
+		w = strings.TrimLeft(w, "-")
+		if w == name && i+1 < len(words) {
+			return words[i+1]
+		}
+		if strings.HasPrefix(w, name+"=") {
+			return strings.TrimPrefix(w, name+"=")
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// readConfigFile reads the variables of a file to import with "config set".
+// Files ending in ".json" are an object of names to values as written by
+// "config get -json". Other files are dotenv files.
+func readConfigFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var result map[string]string
+		if err := json.NewDecoder(f).Decode(&result); err != nil {
+			return nil, fmt.Errorf("%s must be a JSON object of names to string values: %s", path, err)
+		}
+
+		return result, nil
+	}
+
+	result, err := parseDotenv(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return result, nil
+}
+
+// parseDotenv parses the variables of a dotenv file. Each line is a
+// NAME=value pair that may start with "export". Values may be quoted, and
+// double quoted values may contain escapes. Blank lines and lines starting
+// with # are ignored, as are comments after unquoted values.
+func parseDotenv(r io.Reader) (map[string]string, error) {
+	result := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimSpace(strings.TrimPrefix(text, "export "))
+
+		idx := strings.IndexByte(text, '=')
+		if idx <= 0 {
+			return nil, fmt.Errorf("line %d: variables must be in the form NAME=value", line)
+		}
+		name := strings.TrimSpace(text[:idx])
+		value := strings.TrimSpace(text[idx+1:])
+
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			v, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid quoted value for %s", line, name)
+			}
+			value = v
+
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+
+		result[name] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// writeDotenv writes the variables as a dotenv file that parseDotenv reads,
+// sorted by name.
+func writeDotenv(w io.Writer, vars map[string]string) error {
+	names := make([]string, 0, len(vars))
+	for k := range vars {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", name, strconv.Quote(vars[name])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
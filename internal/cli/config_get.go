@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/clierrors"
@@ -15,8 +17,12 @@ import (
 type ConfigGetCommand struct {
 	*baseCommand
 
-	json bool
-	raw  bool
+	json   bool
+	dotenv bool
+	raw    bool
+
+	flagScopeWorkspace string
+	flagScopeLabels    map[string]string
 }
 
 func (c *ConfigGetCommand) Run(args []string) int {
@@ -46,6 +52,10 @@ func (c *ConfigGetCommand) Run(args []string) int {
 	req := &pb.ConfigGetRequest{
 		Scope:  &pb.ConfigGetRequest_Project{Project: c.project.Ref()},
 		Prefix: prefix,
+		Labels: c.flagScopeLabels,
+	}
+	if c.flagScopeWorkspace != "" {
+		req.Workspace = &pb.Ref_Workspace{Workspace: c.flagScopeWorkspace}
 	}
 	if c.flagApp != "" {
 		req.Scope = &pb.ConfigGetRequest_Application{
@@ -81,6 +91,26 @@ func (c *ConfigGetCommand) Run(args []string) int {
 		return 0
 	}
 
+	if c.dotenv {
+		out, _, err := c.project.UI.OutputWriters()
+		if err != nil {
+			c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		vars := map[string]string{}
+		for _, cv := range resp.Variables {
+			vars[cv.Name] = cv.Value
+		}
+
+		if err := writeDotenv(out, vars); err != nil {
+			c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
 	if c.raw {
 		// Get our direct stdout handle cause we're going to be writing colors
 		// and want color detection to work.
@@ -102,18 +132,46 @@ func (c *ConfigGetCommand) Run(args []string) int {
 		return 0
 	}
 
-	table := terminal.NewTable("Scope", "Name", "Value")
+	table := terminal.NewTable("Scope", "Workspace", "Labels", "Name", "Value")
 	for _, v := range resp.Variables {
 		var app string
 		if scope, ok := v.Scope.(*pb.ConfigVar_Application); ok {
 			app = scope.Application.Application
 		}
 
+		var labels []string
+		for k, v := range v.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+
+		// Dynamic variables show where their value is read from.
+		value := v.Value
+		if v.Dynamic != nil {
+			var config []string
+			for k, v := range v.Dynamic.Config {
+				config = append(config, k+"="+v)
+			}
+			sort.Strings(config)
+
+			value = fmt.Sprintf("<%s: %s>", v.Dynamic.From, strings.Join(config, ", "))
+		}
+
+		// File variables show their path since their contents are often
+		// several lines.
+		if v.File != nil {
+			value = fmt.Sprintf("<file: %s>", v.File.Path)
+		}
+
 		table.Rich([]string{
 			app,
+			v.Workspace.GetWorkspace(),
+			strings.Join(labels, ", "),
 			v.Name,
-			v.Value,
+			value,
 		}, []string{
+			"",
+			"",
 			"",
 			terminal.Green,
 			"",
@@ -134,11 +192,29 @@ func (c *ConfigGetCommand) Flags() *flag.Sets {
 			Usage:  "Output in JSON",
 		})
 
+		f.BoolVar(&flag.BoolVar{
+			Name:   "dotenv",
+			Target: &c.dotenv,
+			Usage:  "Output as a dotenv file that \"config set -file\" can import",
+		})
+
 		f.BoolVar(&flag.BoolVar{
 			Name:   "raw",
 			Target: &c.raw,
 			Usage:  "Output the value for the named variable only (disables prefix matching)",
 		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "scope-workspace",
+			Target: &c.flagScopeWorkspace,
+			Usage:  "Resolve the variables for a deployment in this workspace.",
+		})
+
+		f.StringMapVar(&flag.StringMapVar{
+			Name:   "scope-label",
+			Target: &c.flagScopeLabels,
+			Usage:  "Resolve the variables for a deployment with this label. Can be specified multiple times.",
+		})
 	})
 }
 
@@ -164,5 +240,14 @@ Usage: waypoint config-get [prefix]
   By specifying the "-app" flag you can look at config variables for
   a specific application rather than the project.
 
+  By default all variables are listed, including those set for specific
+  workspaces and labels. With the "-scope-workspace" or "-scope-label"
+  flags, only the variables a deployment in the workspace with the labels
+  would get are shown.
+
+  The "-json" and "-dotenv" flags output the names and values of the
+  variables in a format that "config set -file" can import. Use them with
+  the scope flags so that each name has a single value.
+
 ` + c.Flags().Help())
 }
@@ -3,10 +3,13 @@ package cli
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/configsource"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/posener/complete"
@@ -14,6 +17,15 @@ import (
 
 type ConfigSetCommand struct {
 	*baseCommand
+
+	flagScopeWorkspace string
+	flagScopeLabels    map[string]string
+	flagFrom           string
+	flagFromConfig     map[string]string
+	flagFile           string
+	flagToFile         string
+	flagToFileMode     string
+	flagToFileSignal   string
 }
 
 func (c *ConfigSetCommand) Run(args []string) int {
@@ -25,42 +37,95 @@ func (c *ConfigSetCommand) Run(args []string) int {
 		return 1
 	}
 
-	if len(c.args) == 0 {
-		fmt.Fprintf(os.Stderr, "config-set requires at least one key=value entry")
+	if len(c.args) == 0 && c.flagFile == "" {
+		fmt.Fprintf(os.Stderr, "config-set requires at least one key=value entry or -file")
+		return 1
+	}
+	if c.flagFile != "" && c.flagFrom != "" {
+		fmt.Fprintf(os.Stderr, "-file can't be used with -from since the file has the values")
 		return 1
 	}
+	if c.flagToFile != "" && (c.flagFile != "" || len(c.args) != 1) {
+		fmt.Fprintf(os.Stderr, "-to-file requires exactly one variable")
+		return 1
+	}
+
+	// Variables written to a file have the path, mode and signal from the
+	// flags.
+	var file *pb.ConfigVar_FileVal
+	if c.flagToFile != "" {
+		mode := uint64(0644)
+		if c.flagToFileMode != "" {
+			var err error
+			mode, err = strconv.ParseUint(c.flagToFileMode, 8, 32)
+			if err != nil || mode > 0777 {
+				fmt.Fprintf(os.Stderr, "-to-file-mode must be octal permissions such as 0600")
+				return 1
+			}
+		}
+
+		file = &pb.ConfigVar_FileVal{
+			Path:   c.flagToFile,
+			Mode:   uint32(mode),
+			Signal: c.flagToFileSignal,
+		}
+	}
 
 	// Get our API client
 	client := c.project.Client()
 
 	var req pb.ConfigSetRequest
 
-	for _, arg := range c.args {
-		idx := strings.IndexByte(arg, '=')
-		if idx == -1 || idx == 0 {
-			fmt.Fprintf(os.Stderr, "variables must be in the form key=value")
+	// The variables of the file are set first so that the arguments can
+	// override them.
+	if c.flagFile != "" {
+		vars, err := readConfigFile(c.flagFile)
+		if err != nil {
+			c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return 1
 		}
 
-		configVar := &pb.ConfigVar{
-			Name:  arg[:idx],
-			Value: arg[idx+1:],
+		names := make([]string, 0, len(vars))
+		for k := range vars {
+			names = append(names, k)
 		}
+		sort.Strings(names)
 
-		if c.flagApp == "" {
-			configVar.Scope = &pb.ConfigVar_Project{
-				Project: c.project.Ref(),
+		for _, name := range names {
+			req.Variables = append(req.Variables, c.scope(&pb.ConfigVar{
+				Name:  name,
+				Value: vars[name],
+			}))
+		}
+	}
+
+	for _, arg := range c.args {
+		var configVar *pb.ConfigVar
+		if c.flagFrom != "" {
+			// Dynamic variables are only named since their value is read
+			// from the source.
+			configVar = &pb.ConfigVar{
+				Name: arg,
+				Dynamic: &pb.ConfigVar_DynamicVal{
+					From:   c.flagFrom,
+					Config: c.flagFromConfig,
+				},
 			}
 		} else {
-			configVar.Scope = &pb.ConfigVar_Application{
-				Application: &pb.Ref_Application{
-					Project:     c.project.Ref().Project,
-					Application: c.flagApp,
-				},
+			idx := strings.IndexByte(arg, '=')
+			if idx == -1 || idx == 0 {
+				fmt.Fprintf(os.Stderr, "variables must be in the form key=value")
+				return 1
+			}
+
+			configVar = &pb.ConfigVar{
+				Name:  arg[:idx],
+				Value: arg[idx+1:],
 			}
 		}
 
-		req.Variables = append(req.Variables, configVar)
+		configVar.File = file
+		req.Variables = append(req.Variables, c.scope(configVar))
 	}
 
 	_, err := client.SetConfig(c.Ctx, &req)
@@ -69,11 +134,95 @@ func (c *ConfigSetCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.flagFile != "" {
+		c.ui.Output("Set %d variables.", len(req.Variables), terminal.WithSuccessStyle())
+	}
+
 	return 0
 }
 
+// scope sets the scope of the variable from the flags.
+func (c *ConfigSetCommand) scope(configVar *pb.ConfigVar) *pb.ConfigVar {
+	if c.flagApp == "" {
+		configVar.Scope = &pb.ConfigVar_Project{
+			Project: c.project.Ref(),
+		}
+	} else {
+		configVar.Scope = &pb.ConfigVar_Application{
+			Application: &pb.Ref_Application{
+				Project:     c.project.Ref().Project,
+				Application: c.flagApp,
+			},
+		}
+	}
+
+	if c.flagScopeWorkspace != "" {
+		configVar.Workspace = &pb.Ref_Workspace{Workspace: c.flagScopeWorkspace}
+	}
+	configVar.Labels = c.flagScopeLabels
+
+	return configVar
+}
+
 func (c *ConfigSetCommand) Flags() *flag.Sets {
-	return c.flagSet(0, nil)
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "scope-workspace",
+			Target: &c.flagScopeWorkspace,
+			Usage:  "Only set the variable for deployments in this workspace.",
+		})
+
+		f.StringMapVar(&flag.StringMapVar{
+			Name:   "scope-label",
+			Target: &c.flagScopeLabels,
+			Usage: "Only set the variable for deployments with this label. " +
+				"Can be specified multiple times, and deployments must have all the labels.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "from",
+			Target: &c.flagFrom,
+			Usage: "Read the value of the variables from this config source when the " +
+				"application starts instead of storing it on the server.",
+			Completion: complete.PredictSet(configsource.Types()...),
+		})
+
+		f.StringMapVar(&flag.StringMapVar{
+			Name:   "from-config",
+			Target: &c.flagFromConfig,
+			Usage:  "Where the source reads the value from, such as the path and key of a secret. Can be specified multiple times.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:       "file",
+			Target:     &c.flagFile,
+			Completion: complete.PredictFiles("*"),
+			Usage: "Set the variables of a dotenv file, or of a JSON file as written by " +
+				"\"config get -json\" if the name ends in .json.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "to-file",
+			Target: &c.flagToFile,
+			Usage: "Write the variable to a file at this absolute path in the deployment " +
+				"instead of setting it as an environment variable.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "to-file-mode",
+			Target:  &c.flagToFileMode,
+			Default: "0644",
+			Usage:   "The permissions of the file of -to-file, in octal.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "to-file-signal",
+			Target: &c.flagToFileSignal,
+			Usage: "Send this signal to the application when the file of -to-file " +
+				"changes, such as SIGHUP. If this isn't set, the application isn't notified.",
+		})
+	})
 }
 
 func (c *ConfigSetCommand) AutocompleteArgs() complete.Predictor {
@@ -90,7 +239,7 @@ func (c *ConfigSetCommand) Synopsis() string {
 
 func (c *ConfigSetCommand) Help() string {
 	return formatHelp(`
-Usage: waypoint config-set <name> <value>
+Usage: waypoint config-set [<name>=<value>...]
 
   Set a config variable that will be available to deployments as an
   environment variable.
@@ -98,5 +247,38 @@ Usage: waypoint config-set <name> <value>
   This will scope the variable to the entire project by default.
   Specify the "-app" flag to set a config variable for a specific app.
 
-`)
+  The "-scope-workspace" and "-scope-label" flags set a variable only for
+  deployments in a workspace or with labels. Application variables take
+  precedence over project variables. Within those, a variable for the
+  workspace takes precedence over one for all workspaces, and then a
+  variable with more matching labels over one with fewer.
+
+  With the "-from" flag, the variables are named without values and are
+  read from a config source by the entrypoint when the application starts
+  and again periodically. If they change, the application is restarted.
+  The "-from-config" flags tell the source where the value is:
+
+    waypoint config set -from=vault -from-config=path=secret/data/db \
+      -from-config=key=password DATABASE_PASSWORD
+
+  With the "-file" flag, the variables of a file are set, such as one
+  written by "config get -json" or "config get -dotenv" for another app:
+
+    waypoint config set -file=.env
+
+  Variables given as arguments are set after those of the file.
+
+  With the "-to-file" flag, the entrypoint writes the variable to a file
+  instead of setting an environment variable, for applications that read
+  their configuration from files. When the value changes, the file is
+  replaced and the application is sent the "-to-file-signal" signal:
+
+    waypoint config set -to-file=/etc/app/config.yml \
+      -to-file-signal=SIGHUP APP_CONFIG="$(cat config.yml)"
+
+  Setting a variable to an empty value deletes it. The scope flags must
+  match those it was set with. See "config unset" to delete variables by
+  name or pattern.
+
+` + c.Flags().Help())
 }
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/configsource"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ConfigSourceSetCommand struct {
+	*baseCommand
+
+	flagType   string
+	flagConfig map[string]string
+	flagDelete bool
+}
+
+func (c *ConfigSourceSetCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if c.flagType == "" || len(c.args) > 0 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	_, err := c.project.Client().SetConfigSource(c.Ctx, &pb.SetConfigSourceRequest{
+		ConfigSource: &pb.ConfigSource{
+			Type:   c.flagType,
+			Config: c.flagConfig,
+			Delete: c.flagDelete,
+		},
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagDelete {
+		c.ui.Output("Configuration of config source %s deleted.", c.flagType, terminal.WithSuccessStyle())
+	} else {
+		c.ui.Output("Configuration of config source %s set.", c.flagType, terminal.WithSuccessStyle())
+	}
+
+	return 0
+}
+
+func (c *ConfigSourceSetCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "type",
+			Target:     &c.flagType,
+			Usage:      "Type of the config source: " + strings.Join(configsource.Types(), ", ") + ".",
+			Completion: complete.PredictSet(configsource.Types()...),
+		})
+
+		f.StringMapVar(&flag.StringMapVar{
+			Name:   "config",
+			Target: &c.flagConfig,
+			Usage:  "Configuration of the source, such as its address. Can be specified multiple times.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "delete",
+			Target: &c.flagDelete,
+			Usage:  "Delete the configuration of the source so that it uses its defaults.",
+		})
+	})
+}
+
+func (c *ConfigSourceSetCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ConfigSourceSetCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ConfigSourceSetCommand) Synopsis() string {
+	return "Configure a source of dynamic config variables."
+}
+
+func (c *ConfigSourceSetCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint config source-set -type=<type> [-config=key=value...]
+
+  Configure a source that dynamic config variables are read from.
+
+  The configuration replaces any previous configuration of the source.
+  It is sent to the entrypoints of deployments that have variables from
+  the source, which read the values when the application starts. Sources
+  that aren't configured use their defaults, such as the environment of
+  the entrypoint. This requires admin permission.
+
+  The "vault" source is configured with "addr", "token" and "namespace".
+  The "aws-ssm" source is configured with "region". The "gcp-secretmanager"
+  source is configured with "credentials_file".
+
+` + c.Flags().Help())
+}
+
+type ConfigSourceGetCommand struct {
+	*baseCommand
+
+	flagType string
+}
+
+func (c *ConfigSourceGetCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().GetConfigSource(c.Ctx, &pb.GetConfigSourceRequest{
+		Type: c.flagType,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	table := terminal.NewTable("Type", "Config")
+	for _, source := range resp.ConfigSources {
+		var config []string
+		for k, v := range source.Config {
+			config = append(config, k+"="+v)
+		}
+		sort.Strings(config)
+
+		table.Rich([]string{
+			source.Type,
+			strings.Join(config, ", "),
+		}, []string{
+			terminal.Green,
+			"",
+		})
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *ConfigSourceGetCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "type",
+			Target:     &c.flagType,
+			Usage:      "Only show the configuration of this type of source.",
+			Completion: complete.PredictSet(configsource.Types()...),
+		})
+	})
+}
+
+func (c *ConfigSourceGetCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ConfigSourceGetCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ConfigSourceGetCommand) Synopsis() string {
+	return "Show the configuration of config sources."
+}
+
+func (c *ConfigSourceGetCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint config source-get [-type=<type>]
+
+  Show the configuration of the sources that dynamic config variables
+  are read from. This includes credentials, so it requires admin
+  permission.
+
+` + c.Flags().Help())
+}
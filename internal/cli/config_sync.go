@@ -0,0 +1,337 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ConfigSyncCommand struct {
+	*baseCommand
+
+	flagDryRun bool
+	flagPrune  bool
+}
+
+// configSyncScope is the project or an app with the config blocks that
+// declare its variables.
+type configSyncScope struct {
+	name   string
+	get    *pb.ConfigGetRequest
+	set    func(*pb.ConfigVar)
+	blocks []*configpkg.ConfigVars
+}
+
+// configSyncChange is a change to a variable on the server.
+type configSyncChange struct {
+	action string
+	scope  string
+	v      *pb.ConfigVar
+}
+
+func (c *ConfigSyncCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithConfig(false),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) > 0 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	scopes := c.scopes()
+	if len(scopes) == 0 {
+		c.ui.Output("No config blocks are in the waypoint.hcl, so there is nothing to sync.",
+			terminal.WithWarningStyle())
+		return 0
+	}
+
+	var changes []configSyncChange
+	for _, scope := range scopes {
+		scopeChanges, err := c.diff(scope)
+		if err != nil {
+			c.ui.Output("Error syncing the variables of the %s: %s", scope.name,
+				clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		changes = append(changes, scopeChanges...)
+	}
+	if len(changes) == 0 {
+		c.ui.Output("The variables on the server match the waypoint.hcl.", terminal.WithSuccessStyle())
+		return 0
+	}
+
+	// Values aren't shown since they are often secrets
+	tbl := terminal.NewTable("Change", "Scope", "Name", "Workspace", "Labels")
+	var req pb.ConfigSetRequest
+	for _, change := range changes {
+		tbl.Rich([]string{
+			change.action,
+			change.scope,
+			change.v.Name,
+			change.v.Workspace.GetWorkspace(),
+			configSyncLabels(change.v.Labels),
+		}, nil)
+
+		req.Variables = append(req.Variables, change.v)
+	}
+	c.ui.Table(tbl)
+
+	if c.flagDryRun {
+		return 0
+	}
+
+	if _, err := c.project.Client().SetConfig(c.Ctx, &req); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Synced %d variables.", len(changes), terminal.WithSuccessStyle())
+	return 0
+}
+
+// scopes returns the project and apps that have config blocks. Scopes
+// without config blocks aren't changed.
+func (c *ConfigSyncCommand) scopes() []*configSyncScope {
+	var result []*configSyncScope
+	project := c.project.Ref()
+	if len(c.cfg.Config) > 0 && c.flagApp == "" {
+		result = append(result, &configSyncScope{
+			name: "project",
+			get: &pb.ConfigGetRequest{
+				Scope: &pb.ConfigGetRequest_Project{Project: project},
+			},
+			set: func(v *pb.ConfigVar) {
+				v.Scope = &pb.ConfigVar_Project{Project: project}
+			},
+			blocks: c.cfg.Config,
+		})
+	}
+
+	for _, app := range c.cfg.Apps {
+		if len(app.Config) == 0 || (c.flagApp != "" && app.Name != c.flagApp) {
+			continue
+		}
+
+		ref := &pb.Ref_Application{Project: project.Project, Application: app.Name}
+		result = append(result, &configSyncScope{
+			name: fmt.Sprintf("app %q", app.Name),
+			get: &pb.ConfigGetRequest{
+				Scope: &pb.ConfigGetRequest_Application{Application: ref},
+			},
+			set: func(v *pb.ConfigVar) {
+				v.Scope = &pb.ConfigVar_Application{Application: ref}
+			},
+			blocks: app.Config,
+		})
+	}
+
+	return result
+}
+
+// diff returns the changes that make the variables of the scope on the
+// server match its config blocks.
+func (c *ConfigSyncCommand) diff(scope *configSyncScope) ([]configSyncChange, error) {
+	// The variables that are declared, by their name, workspace and labels
+	want := map[string]*pb.ConfigVar{}
+	var keys []string
+	for _, block := range scope.blocks {
+		values, err := block.Values(c.cfgCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		var vars []*pb.ConfigVar
+		for name, value := range values.Env {
+			vars = append(vars, &pb.ConfigVar{Name: name, Value: value})
+		}
+		for _, d := range values.Dynamic {
+			vars = append(vars, &pb.ConfigVar{
+				Name: d.Name,
+				Dynamic: &pb.ConfigVar_DynamicVal{
+					From:   d.From,
+					Config: d.Config,
+				},
+			})
+		}
+		for _, f := range values.File {
+			mode, err := f.FileMode()
+			if err != nil {
+				return nil, err
+			}
+
+			v := &pb.ConfigVar{
+				Name:  f.Name,
+				Value: f.Value,
+				File: &pb.ConfigVar_FileVal{
+					Path:   f.Path,
+					Mode:   mode,
+					Signal: f.Signal,
+				},
+			}
+			if f.From != "" {
+				v.Dynamic = &pb.ConfigVar_DynamicVal{
+					From:   f.From,
+					Config: f.Config,
+				}
+			}
+
+			vars = append(vars, v)
+		}
+
+		for _, v := range vars {
+			scope.set(v)
+			if block.Workspace != "" {
+				v.Workspace = &pb.Ref_Workspace{Workspace: block.Workspace}
+			}
+			v.Labels = block.Labels
+
+			key := configSyncKey(v)
+			if _, ok := want[key]; ok {
+				return nil, fmt.Errorf("%q is declared more than once for the same "+
+					"workspace and labels", v.Name)
+			}
+
+			want[key] = v
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	resp, err := c.project.Client().GetConfig(c.Ctx, scope.get)
+	if err != nil {
+		return nil, err
+	}
+
+	have := map[string]*pb.ConfigVar{}
+	for _, v := range resp.Variables {
+		have[configSyncKey(v)] = v
+	}
+
+	var result []configSyncChange
+	for _, key := range keys {
+		v := want[key]
+		current, ok := have[key]
+		switch {
+		case !ok:
+			result = append(result, configSyncChange{action: "add", scope: scope.name, v: v})
+		case current.Value != v.Value ||
+			!proto.Equal(current.Dynamic, v.Dynamic) ||
+			!proto.Equal(current.File, v.File):
+			result = append(result, configSyncChange{action: "update", scope: scope.name, v: v})
+		}
+	}
+
+	if c.flagPrune {
+		var removed []configSyncChange
+		for key, v := range have {
+			if _, ok := want[key]; ok {
+				continue
+			}
+
+			// Variables are deleted by setting them to an empty value with
+			// the scope they were set with.
+			removed = append(removed, configSyncChange{
+				action: "remove",
+				scope:  scope.name,
+				v: &pb.ConfigVar{
+					Name:      v.Name,
+					Scope:     v.Scope,
+					Workspace: v.Workspace,
+					Labels:    v.Labels,
+				},
+			})
+		}
+		sort.Slice(removed, func(i, j int) bool {
+			return configSyncKey(removed[i].v) < configSyncKey(removed[j].v)
+		})
+
+		result = append(result, removed...)
+	}
+
+	return result, nil
+}
+
+// configSyncKey returns the key that identifies a variable within a scope.
+func configSyncKey(v *pb.ConfigVar) string {
+	return strings.Join([]string{v.Name, v.Workspace.GetWorkspace(), configSyncLabels(v.Labels)}, "\x00")
+}
+
+// configSyncLabels formats labels as sorted k=v pairs.
+func configSyncLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func (c *ConfigSyncCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "dry-run",
+			Target: &c.flagDryRun,
+			Usage:  "Show the changes without making them.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "prune",
+			Target:  &c.flagPrune,
+			Default: true,
+			Usage: "Remove variables on the server that aren't declared for the project " +
+				"or app. Only the project and apps with config blocks are changed.",
+		})
+	})
+}
+
+func (c *ConfigSyncCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ConfigSyncCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ConfigSyncCommand) Synopsis() string {
+	return "Make the config variables on the server match the waypoint.hcl."
+}
+
+func (c *ConfigSyncCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint config sync [options]
+
+  Make the config variables on the server match the config blocks of the
+  waypoint.hcl.
+
+  Config blocks at the top level declare the variables of the project and
+  config blocks in an app declare the variables of the app. Values can use
+  functions such as file() and templatefile(), which are only evaluated by
+  this command. Variables in dynamic blocks are read from a config source
+  when the app starts. Variables in file blocks are written to files by the
+  entrypoint rather than set as environment variables.
+
+  Variables that are missing or have a different value on the server are
+  set. Variables on the server that aren't declared are removed unless
+  -prune is false. Only the project and apps with config blocks are
+  changed. With "-app", only that app is synced.
+
+` + c.Flags().Help())
+}
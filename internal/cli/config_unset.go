@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"path"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ConfigUnsetCommand struct {
+	*baseCommand
+
+	flagScopeWorkspace string
+	flagScopeLabels    map[string]string
+}
+
+func (c *ConfigUnsetCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) == 0 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+	for _, pattern := range c.args {
+		if _, err := path.Match(pattern, ""); err != nil {
+			c.ui.Output("Invalid pattern %q: %s", pattern, err, terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	// Get our API client
+	client := c.project.Client()
+
+	// Get all the variables of the scope, including the variants for
+	// workspaces and labels, so we can match them.
+	getReq := &pb.ConfigGetRequest{
+		Scope: &pb.ConfigGetRequest_Project{Project: c.project.Ref()},
+	}
+	if c.flagApp != "" {
+		getReq.Scope = &pb.ConfigGetRequest_Application{
+			Application: &pb.Ref_Application{
+				Project:     c.project.Ref().Project,
+				Application: c.flagApp,
+			},
+		}
+	}
+	resp, err := client.GetConfig(c.Ctx, getReq)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Variables are deleted by setting them to an empty value with the
+	// scope they were set with.
+	var req pb.ConfigSetRequest
+	for _, v := range resp.Variables {
+		if !c.matches(v) {
+			continue
+		}
+
+		req.Variables = append(req.Variables, &pb.ConfigVar{
+			Name:      v.Name,
+			Scope:     v.Scope,
+			Workspace: v.Workspace,
+			Labels:    v.Labels,
+		})
+	}
+	if len(req.Variables) == 0 {
+		c.ui.Output("No variables match.", terminal.WithWarningStyle())
+		return 0
+	}
+
+	if _, err := client.SetConfig(c.Ctx, &req); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	for _, v := range req.Variables {
+		c.ui.Output("Unset %s", v.Name, terminal.WithSuccessStyle())
+	}
+
+	return 0
+}
+
+// matches returns true if the variable has the scope of the flags and its
+// name matches one of the patterns.
+func (c *ConfigUnsetCommand) matches(v *pb.ConfigVar) bool {
+	switch scope := v.Scope.(type) {
+	case *pb.ConfigVar_Project:
+		if c.flagApp != "" {
+			return false
+		}
+	case *pb.ConfigVar_Application:
+		if c.flagApp == "" || scope.Application.Application != c.flagApp {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if v.Workspace.GetWorkspace() != c.flagScopeWorkspace {
+		return false
+	}
+	if len(v.Labels) != len(c.flagScopeLabels) {
+		return false
+	}
+	for k, value := range c.flagScopeLabels {
+		if v.Labels[k] != value {
+			return false
+		}
+	}
+
+	for _, pattern := range c.args {
+		if ok, _ := path.Match(pattern, v.Name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *ConfigUnsetCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "scope-workspace",
+			Target: &c.flagScopeWorkspace,
+			Usage:  "Unset the variables that were set for this workspace.",
+		})
+
+		f.StringMapVar(&flag.StringMapVar{
+			Name:   "scope-label",
+			Target: &c.flagScopeLabels,
+			Usage:  "Unset the variables that were set for these labels. Can be specified multiple times.",
+		})
+	})
+}
+
+func (c *ConfigUnsetCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ConfigUnsetCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ConfigUnsetCommand) Synopsis() string {
+	return "Unset config variables."
+}
+
+func (c *ConfigUnsetCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint config unset [options] <name>...
+
+  Unset config variables by name. Names may be glob patterns, such as
+  "DB_*", to unset all the variables that match:
+
+    waypoint config unset 'DB_*' API_KEY
+
+  This unsets the variables of the project by default. Specify the "-app"
+  flag to unset the variables of a specific app. The "-scope-workspace"
+  and "-scope-label" flags must match those the variables were set with.
+
+` + c.Flags().Help())
+}
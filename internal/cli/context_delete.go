@@ -85,7 +85,7 @@ func (c *ContextDeleteCommand) Flags() *flag.Sets {
 }
 
 func (c *ContextDeleteCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictContexts()
 }
 
 func (c *ContextDeleteCommand) AutocompleteFlags() complete.Flags {
@@ -44,7 +44,7 @@ func (c *ContextRenameCommand) Flags() *flag.Sets {
 }
 
 func (c *ContextRenameCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictContexts()
 }
 
 func (c *ContextRenameCommand) AutocompleteFlags() complete.Flags {
@@ -55,7 +55,7 @@ func (c *ContextUseCommand) Flags() *flag.Sets {
 }
 
 func (c *ContextUseCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictContexts()
 }
 
 func (c *ContextUseCommand) AutocompleteFlags() complete.Flags {
@@ -107,7 +107,7 @@ func (c *ContextVerifyCommand) Flags() *flag.Sets {
 }
 
 func (c *ContextVerifyCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictContexts()
 }
 
 func (c *ContextVerifyCommand) AutocompleteFlags() complete.Flags {
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	clientpkg "github.com/hashicorp/waypoint/internal/client"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	"github.com/hashicorp/waypoint/internal/server/execclient"
+)
+
+type CopyCommand struct {
+	*baseCommand
+
+	flagDeployment string
+	flagMode       string
+	flagTimeout    time.Duration
+}
+
+// copyRemote is a path on an instance in the arguments of cp.
+type copyRemote struct {
+	InstanceId string
+	Path       string
+}
+
+// parseCopyRemote parses an argument of cp as a path on an instance. These
+// are "INSTANCE:PATH", or ":PATH" for any instance of the deployment. The
+// path must be absolute. This returns nil if the argument is a local path.
+func parseCopyRemote(v string) *copyRemote {
+	idx := strings.IndexByte(v, ':')
+	if idx == -1 || !path.IsAbs(v[idx+1:]) {
+		return nil
+	}
+
+	return &copyRemote{InstanceId: v[:idx], Path: v[idx+1:]}
+}
+
+func (c *CopyCommand) Run(args []string) int {
+	flagSet := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flagSet),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+
+	args = flagSet.Args()
+	if len(args) != 2 {
+		c.ui.Output("A source and a destination are required.\n\n"+c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	src, dst := parseCopyRemote(args[0]), parseCopyRemote(args[1])
+	if (src == nil) == (dst == nil) {
+		c.ui.Output("Exactly one of the source and destination must be a path on an "+
+			"instance, such as \":/app/config.yml\".\n\n"+c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	mode, err := strconv.ParseUint(c.flagMode, 8, 32)
+	if err != nil || mode > 0777 {
+		c.ui.Output("-mode must be octal permissions such as 0600", terminal.WithErrorStyle())
+		return 1
+	}
+
+	ctx := c.Ctx
+	if c.flagTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.flagTimeout)
+		defer cancel()
+	}
+
+	client := c.project.Client()
+	err = c.DoApp(ctx, func(ctx context.Context, app *clientpkg.App) error {
+		remote := src
+		if remote == nil {
+			remote = dst
+		}
+
+		deployment, err := execTargetDeployment(ctx, client, app, c.flagDeployment, remote.InstanceId)
+		if err != nil {
+			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+		if deployment == nil {
+			app.UI.Output("No successful deployments found.", terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		cp := &execclient.CopyClient{
+			Logger:       c.Log,
+			Context:      ctx,
+			Client:       client,
+			DeploymentId: deployment.Id,
+			InstanceId:   remote.InstanceId,
+			Path:         remote.Path,
+			Mode:         os.FileMode(mode),
+		}
+
+		// A local path of "-" is stdin or stdout.
+		if dst != nil {
+			cp.Reader = os.Stdin
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+					return ErrSentinel
+				}
+				defer f.Close()
+
+				cp.Reader = f
+			}
+		} else {
+			cp.Writer = os.Stdout
+			if args[1] != "-" {
+				f, err := os.Create(args[1])
+				if err != nil {
+					app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+					return ErrSentinel
+				}
+				defer f.Close()
+
+				cp.Writer = f
+			}
+		}
+
+		if err := cp.Run(); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				app.UI.Output("The copy didn't finish within the timeout of %s.",
+					c.flagTimeout, terminal.WithErrorStyle())
+				return ErrSentinel
+			}
+
+			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+func (c *CopyCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "deployment",
+			Target:     &c.flagDeployment,
+			Completion: c.predictDeployments(),
+			Usage: "ID or sequence number of the deployment to copy to or from. " +
+				"This defaults to the latest deployment.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "mode",
+			Target:  &c.flagMode,
+			Default: "0644",
+			Usage:   "Permissions in octal of a file copied to an instance.",
+		})
+
+		f.DurationVar(&flag.DurationVar{
+			Name:   "timeout",
+			Target: &c.flagTimeout,
+			Usage:  "Stop the copy if it doesn't finish within this duration. By default there is no timeout.",
+		})
+	})
+}
+
+func (c *CopyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *CopyCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *CopyCommand) Synopsis() string {
+	return "Copy a file to or from a running application instance"
+}
+
+func (c *CopyCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint cp [options] SOURCE DESTINATION
+
+  Copy a file to or from a running application instance.
+
+  One of the source and destination is a path on an instance and the other
+  is a local path. Paths on an instance are "INSTANCE:PATH", where PATH is
+  absolute. Use ":PATH" to copy to or from any instance of the deployment,
+  which is chosen like for "waypoint exec". Use "-" as the local path to
+  copy from stdin or to stdout.
+
+  The file is copied by the entrypoint, so the instance doesn't need any
+  tools installed. Copying is disabled along with exec if the deployment
+  sets WAYPOINT_CEB_DISABLE_EXEC.
+
+  Examples:
+
+    $ waypoint cp ./config.yml :/app/config.yml
+    $ waypoint cp 01EXAMPLEINSTANCE:/var/log/app.log ./app.log
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,36 @@
+# The name of your project. A project typically maps 1:1 to a VCS repository.
+# This name must be unique for your Waypoint server.
+project = "{{ .Project }}"
+
+# A Go application built with the Dockerfile in this directory and
+# deployed to Kubernetes.
+app "{{ .App }}" {
+    build {
+        use "docker" {}
+
+        # Kubernetes pulls the image from a registry, so set this to a
+        # registry that your cluster can access.
+        registry {
+            use "docker" {
+                image = "registry.example.com/{{ .App }}"
+                tag   = "latest"
+            }
+        }
+    }
+
+    # Deploy to the namespace of the current kubectl context.
+    deploy {
+        use "kubernetes" {
+            probe_path   = "/"
+            service_port = 3000
+        }
+    }
+
+    # Expose the app with a load balancer in front of the deployment.
+    release {
+        use "kubernetes" {
+            load_balancer = true
+            port          = 80
+        }
+    }
+}
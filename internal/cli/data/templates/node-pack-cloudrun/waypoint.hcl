@@ -0,0 +1,35 @@
+# The name of your project. A project typically maps 1:1 to a VCS repository.
+# This name must be unique for your Waypoint server.
+project = "{{ .Project }}"
+
+# A Node.js application built with Cloud Native Buildpacks and deployed to
+# Google Cloud Run. No Dockerfile is needed since the buildpacks detect the
+# package.json.
+app "{{ .App }}" {
+    build {
+        use "pack" {}
+
+        # Cloud Run pulls the image from Google Container Registry. Replace
+        # my-gcp-project with the ID of your GCP project.
+        registry {
+            use "docker" {
+                image = "gcr.io/my-gcp-project/{{ .App }}"
+                tag   = "latest"
+            }
+        }
+    }
+
+    deploy {
+        use "google-cloud-run" {
+            project  = "my-gcp-project"
+            location = "us-east1"
+
+            # Allow requests without authentication so the app is public.
+            unauthenticated = true
+        }
+    }
+
+    release {
+        use "google-cloud-run" {}
+    }
+}
@@ -0,0 +1,20 @@
+# The name of your project. A project typically maps 1:1 to a VCS repository.
+# This name must be unique for your Waypoint server.
+project = "{{ .Project }}"
+
+# A static site. The files in this directory are copied to web servers
+# over SSH.
+app "{{ .App }}" {
+    build {
+        use "files" {}
+    }
+
+    # Replace the hosts and path with the web servers and document root
+    # that serve the site.
+    deploy {
+        use "files" {
+            hosts = ["www.example.com"]
+            path  = "/var/www/{{ .App }}"
+        }
+    }
+}
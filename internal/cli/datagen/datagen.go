@@ -1,6 +1,10 @@
 // Code generated by go-bindata.
 // sources:
 // data/init.tpl.hcl
+// data/templates/go-docker-k8s/Dockerfile
+// data/templates/go-docker-k8s/waypoint.hcl
+// data/templates/node-pack-cloudrun/waypoint.hcl
+// data/templates/static-files/waypoint.hcl
 // DO NOT EDIT!
 
 package datagen
@@ -88,6 +92,86 @@ func initTplHcl() (*asset, error) {
 	return a, nil
 }
 
+var _templatesGoDockerK8sDockerfile = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3d\x8d\xcb\x0a\xc2\x30\x14\x44\xf7\xf7\x2b\x06\xdd\xda\x87\x88\x1b\xc1\x85\x8f\x28\xa2\x36\x25\x56\x54\x44\x24\xd5\x54\x03\xb5\x09\x6d\x14\xfc\x7b\x1f\x45\x77\xc3\x70\xce\xcc\x44\xf0\x25\x2e\x26\x97\xc5\xa5\xd7\xf6\xdb\x5d\x4f\xe6\x56\x17\x0a\x83\x15\xd2\xbb\xce\xcf\x44\x1b\x2e\xe6\xe3\x99\x40\x50\x95\x27\x1a\xf1\x78\x07\x1f\x3e\x89\x75\x84\xd1\x94\x1f\x59\x34\x18\x2e\xd8\xb8\x1f\xbe\x47\x6a\x03\x9e\x41\x90\xea\x22\x90\xd6\xbe\x41\x9a\x7c\x1e\xea\x55\xaa\x7d\xcf\xcb\x4a\x73\xeb\xd7\xf4\x1f\xfd\x05\xa2\x26\x36\xf2\x69\x8d\x2e\x1c\x2a\xe5\x2a\xc4\x5c\x24\x70\x06\xee\xaa\x60\x4d\xe9\x60\xb2\x6f\xae\x54\xf9\xd0\x27\xd5\x42\x27\x0c\x43\xa4\x4f\x9c\x55\x26\xef\xb9\xf3\x89\x6d\x63\xbe\x62\xdf\x9e\x58\x94\x88\x5d\xcc\x67\x51\x82\x7d\xe3\x77\xd2\x38\xd0\x0b\x0b\xb3\x8f\x2d\xfa\x00\x00\x00"
+
+func templatesGoDockerK8sDockerfileBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesGoDockerK8sDockerfile,
+		"templates/go-docker-k8s/Dockerfile",
+	)
+}
+
+func templatesGoDockerK8sDockerfile() (*asset, error) {
+	bytes, err := templatesGoDockerK8sDockerfileBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/go-docker-k8s/Dockerfile", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesGoDockerK8sWaypointHcl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x92\x41\x8f\xd3\x30\x10\x85\xef\xf9\x15\xa3\xf6\x8a\xb2\x5d\x71\x41\x48\x3d\x54\x2c\xe2\xb0\x97\x95\x40\x70\x5c\x4d\x9c\x69\xd7\xd4\xb1\x8d\x3d\x86\x46\x55\xfe\x3b\xe3\x38\x0d\xcd\x56\x2b\xe1\x43\xe2\xc4\xf6\x9b\x6f\x9e\xdf\x1a\xbe\xbd\x10\x58\xec\x08\xdc\x1e\x7a\x97\x02\xf8\xe0\x7e\x92\xe2\x1a\x76\x97\x29\x70\xef\xb5\x42\x63\x7a\xe8\xd0\x47\xb8\xff\x78\x0f\xec\x00\xe1\xfb\xa7\xaf\x10\xc8\xbb\xa8\xd9\x85\xbe\xae\xd6\xa2\xa6\x63\x91\xeb\x52\x64\x68\x08\x92\xd5\xbf\x12\xc1\xde\x85\x22\xff\x03\x7b\xef\xb4\x65\x88\x14\x7e\x53\xa8\xab\x4b\x91\x2d\xac\xce\x67\xa8\x9f\xa6\xcf\x61\x58\x55\x22\xb8\x83\x2f\x52\xc9\x7b\x23\x00\xac\x9d\x85\x26\x69\xc3\xf0\x47\xf3\x0b\xb0\xa0\x3f\x38\x75\xa4\xb0\xd7\x86\x40\x5b\xf9\x23\xe5\x5b\x1d\x44\x40\x80\x00\x6d\x2b\x12\x2d\x79\xe3\x7a\x6a\x33\xf3\x63\x6a\x28\x58\x62\x8a\x75\x25\xaa\xa5\xe4\x4e\x26\x52\x0e\xce\x15\xc8\xc8\x05\xda\x69\x9e\x47\x8a\x04\xab\x76\x2c\x23\x5b\x86\x6a\x5e\x58\x5f\xa9\x81\x4f\xc6\xc4\x91\x48\x77\x78\x90\x7e\x83\xeb\xc4\xa1\x40\x07\x1d\x39\xf4\xef\x20\x3a\xe9\x98\x0b\x61\x36\xef\x4a\xe6\xb2\x49\x16\x91\x8b\x4b\xca\x88\x7d\x24\x6f\xb4\x80\x4a\x51\x14\xde\xcb\x81\x79\xfb\x3f\xc6\x5b\xce\xc5\x52\x1e\x05\x4b\x4c\xbe\x1c\xaf\xe9\x84\x9d\x37\x54\x2b\xd7\xdd\x5d\xd9\x70\x73\x92\xf1\x20\x4f\x39\x69\x50\x3a\xe5\xe5\x86\xa1\x5a\xce\x26\x7f\xd6\xf0\x30\xba\x9e\x5b\xe5\x29\x61\xd1\xa3\x1a\x63\x96\x7f\xa8\x14\x02\x49\x0a\x8e\x62\xa1\x62\x03\xca\x59\xa6\x13\x97\x2e\xcb\x8d\xbd\xbe\x83\xe3\xec\xf6\xeb\xfe\x24\x42\x0d\x3d\x7b\x94\x4c\x8c\xa0\x77\x4b\xc6\x9c\x34\xad\x64\x83\x0b\x39\x66\xef\x37\x9b\xcd\x5b\xd4\x9f\x4f\x92\x66\x1a\x09\x73\x3c\xc6\x98\x21\x18\x87\x2d\x34\x68\xd0\x2a\xb9\x13\xc9\x99\xdc\xae\xb0\x4f\xad\x14\xda\x4e\xba\x29\xf4\x81\x0c\xa1\x88\xfc\x37\x7e\x96\x7f\x9e\xe5\xb7\xc0\x21\xd1\xb2\xbf\x0c\x3e\x8f\x2d\x7c\xb8\xe5\x1f\xaa\xbf\x82\x6c\xe1\x82\xcb\x03\x00\x00"
+
+func templatesGoDockerK8sWaypointHclBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesGoDockerK8sWaypointHcl,
+		"templates/go-docker-k8s/waypoint.hcl",
+	)
+}
+
+func templatesGoDockerK8sWaypointHcl() (*asset, error) {
+	bytes, err := templatesGoDockerK8sWaypointHclBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/go-docker-k8s/waypoint.hcl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesNodePackCloudrunWaypointHcl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x92\x4d\x6f\xdb\x30\x0c\x86\xef\xfe\x15\x84\x73\xb6\x8b\x5c\x07\xf4\x90\xa6\x40\xb1\x4b\x51\x74\x43\x7b\x56\x6c\xc6\x51\x2b\x8b\x9a\x44\xb5\x30\x02\xff\xf7\x52\xfe\x6a\x9c\xed\x30\x1d\x0c\x5a\x22\x5f\xbe\x7a\xc4\x0d\xfc\x3e\x21\x58\xd5\x22\xd0\x11\x3a\x8a\x1e\x9c\xa7\x37\xac\xb8\x84\xdd\x1c\x02\x77\x4e\x57\xca\x98\x0e\x5a\xe5\x02\x6c\x7f\x6c\x81\x09\x14\xbc\xec\x7f\x81\x47\x47\x41\x33\xf9\xae\xcc\x36\xa2\xa6\xc3\x28\xd7\xc6\xc0\x70\x40\x88\x56\xff\x89\x08\x47\xf2\xa3\xfc\xab\xea\x1c\x69\xcb\x10\xd0\x7f\xa0\x2f\xb3\xb9\xc9\x2d\xe4\xe7\x33\x94\x4f\xd3\x6f\xdf\xe7\x99\x08\xee\xe0\x91\x6a\x2c\xdf\x02\x28\xe7\x8c\xb8\x60\x4d\x16\x0e\x51\x1b\x86\x4f\xcd\x27\xd8\x1b\x8a\x35\x3c\xca\xfe\x07\xc2\x9d\xec\xd7\x4e\x55\xef\x92\x6e\x6b\xa8\xd1\x19\xea\xb0\x16\xb7\x22\xf5\x40\xd4\x18\x9c\x0a\x9e\xa3\x2d\x45\x1a\xee\xa9\x7a\x47\x7f\xd4\x72\x90\x9c\x23\xd6\x92\x1e\xb4\xad\x10\x58\xc8\x1c\xbe\x05\x6b\xe4\x81\xc5\x09\x45\x2b\x6d\xa9\x26\xf9\x22\x5b\x66\x62\x6d\x34\xbf\x93\x40\x8c\xc3\x39\x03\x59\x43\xf1\x14\xa7\x15\x03\x42\x9e\x2a\x25\xa1\xcf\x96\xed\xcd\xb7\x25\x70\xd1\x98\x30\x74\xd6\xad\xe8\xc3\xd1\x53\xbb\x18\x27\xcb\x4a\x5b\xf4\xf0\x8c\x8d\x0e\x2c\xc4\x25\x72\x46\x55\x78\xa1\xd5\x76\x45\x53\xb9\x62\xa6\x3a\x30\x4a\x7a\x3f\xef\x97\x17\x7e\xd8\x3f\x2d\xaf\xbc\x54\xfa\x49\xf3\xc2\xef\xe2\xb9\x1e\x20\xe5\x57\x47\x69\x8d\x2e\xe5\xe9\x9a\xca\x97\x9a\x6e\xd6\xdd\x6f\x2e\x98\xfc\x55\xca\xaa\x91\xaf\x94\x1a\xc5\x18\x78\x9d\xd0\x67\xeb\x68\xc2\x35\x3e\xe8\x35\xd2\x66\xe0\x53\x54\x89\x62\xe1\xa3\xbd\x36\x3a\xb3\x48\xcd\xd6\x06\xd7\x4d\x0d\x4d\xe3\x25\x79\x31\x14\xa8\x02\x6f\xf3\x6c\x95\x22\x03\x69\x0c\x7d\x0a\x2d\x19\xea\xc0\x61\xe0\x4b\x91\x41\x45\x81\x6c\x79\x1e\xd0\x40\x03\xf4\x34\x18\x32\x56\x2e\x1e\x64\x74\xcb\x35\x58\x7b\x51\x22\x33\x77\x0b\xec\x23\xfe\xfb\xda\x1e\x8d\x98\xc1\xff\xb8\xf7\x5c\xd6\x67\x5f\x5a\x07\x05\x74\xda\x03\x00\x00"
+
+func templatesNodePackCloudrunWaypointHclBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesNodePackCloudrunWaypointHcl,
+		"templates/node-pack-cloudrun/waypoint.hcl",
+	)
+}
+
+func templatesNodePackCloudrunWaypointHcl() (*asset, error) {
+	bytes, err := templatesNodePackCloudrunWaypointHclBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/node-pack-cloudrun/waypoint.hcl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesStaticFilesWaypointHcl = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x90\xbd\x6e\xc3\x30\x0c\x84\x77\x3f\xc5\xc1\xde\x15\x64\x2d\x90\x21\xe8\xd2\xb1\x68\x8a\x76\x28\x3a\x28\x32\x03\xab\xb0\x4d\x55\x3f\x71\x8d\xc0\xef\x5e\x5a\x76\x82\x74\x28\x07\x8b\xa6\x4e\xf7\x91\xac\xf0\xda\x10\x7a\xdd\x11\xf8\x84\x91\x93\x87\xf3\xfc\x45\x26\x2a\xec\xaf\x29\xe2\xe8\xac\xd1\x6d\x3b\xa2\xd3\x2e\x60\xfb\xb0\x45\x64\x68\xbc\x3d\x1e\xe0\xc9\x71\xb0\x91\xfd\xa8\x8a\x4a\xdc\x6c\x58\xec\xba\x14\x22\x8e\x84\xd4\xdb\xef\x44\x38\xb1\x5f\xec\xdf\xf5\xe8\xd8\xf6\x11\x81\xfc\x99\xbc\x2a\xae\x90\x1d\xca\xcb\x05\xea\x79\xfd\x9d\xa6\xb2\x10\xc3\x3d\x42\xd4\xd1\x1a\x08\x83\x54\xee\xf6\x64\x5b\x0a\xb0\x3d\xe2\x0c\xab\xad\x17\xb9\xe0\xa1\x3d\xc1\xb0\xb3\x54\xcf\xdd\x0d\x74\x5c\x11\x41\x6c\x58\x4e\x1c\x0e\x4f\xaa\xd0\xce\x2d\xa0\xbd\x24\x02\xc1\xa5\x80\xc4\x31\xd9\xb6\x5e\xf3\x39\x52\x20\x94\x99\x24\x8a\x29\x97\xa7\x22\x1f\x15\x5e\xc8\xb5\xda\x90\xf0\x09\x0d\x87\x18\xa0\xfb\x1a\x4e\xc7\x06\x83\x95\xcf\x5c\xbf\xc3\xe7\xdb\x9a\x4d\xea\x48\xc6\xf6\xcc\x71\xf5\x89\x8d\x5e\xd7\x90\x9f\xe4\x09\xf3\x55\x2d\x00\x1e\xff\xeb\xe6\x56\x9d\x63\xe1\xef\xf0\x51\x0e\xc3\xa0\xe8\x47\x77\xae\x25\x65\xb8\x2b\x3f\xff\xe8\x72\x77\xf3\x8e\x37\x67\xed\x37\xa2\xdd\xdc\xed\xe0\xa6\xbc\x0e\x3a\x15\xbf\xd8\x94\x46\x26\x19\x02\x00\x00"
+
+func templatesStaticFilesWaypointHclBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesStaticFilesWaypointHcl,
+		"templates/static-files/waypoint.hcl",
+	)
+}
+
+func templatesStaticFilesWaypointHcl() (*asset, error) {
+	bytes, err := templatesStaticFilesWaypointHclBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/static-files/waypoint.hcl", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -140,7 +224,11 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"init.tpl.hcl": initTplHcl,
+	"init.tpl.hcl":                              initTplHcl,
+	"templates/go-docker-k8s/Dockerfile":        templatesGoDockerK8sDockerfile,
+	"templates/go-docker-k8s/waypoint.hcl":      templatesGoDockerK8sWaypointHcl,
+	"templates/node-pack-cloudrun/waypoint.hcl": templatesNodePackCloudrunWaypointHcl,
+	"templates/static-files/waypoint.hcl":       templatesStaticFilesWaypointHcl,
 }
 
 // AssetDir returns the file names below a certain
@@ -185,6 +273,18 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"init.tpl.hcl": &bintree{initTplHcl, map[string]*bintree{}},
+	"templates": &bintree{nil, map[string]*bintree{
+		"go-docker-k8s": &bintree{nil, map[string]*bintree{
+			"Dockerfile": &bintree{templatesGoDockerK8sDockerfile, map[string]*bintree{}},
+			"waypoint.hcl": &bintree{templatesGoDockerK8sWaypointHcl, map[string]*bintree{}},
+		}},
+		"node-pack-cloudrun": &bintree{nil, map[string]*bintree{
+			"waypoint.hcl": &bintree{templatesNodePackCloudrunWaypointHcl, map[string]*bintree{}},
+		}},
+		"static-files": &bintree{nil, map[string]*bintree{
+			"waypoint.hcl": &bintree{templatesStaticFilesWaypointHcl, map[string]*bintree{}},
+		}},
+	}},
 }}
 
 // RestoreAsset restores an asset under the given directory
@@ -17,6 +17,7 @@ type DeploymentCreateCommand struct {
 	*baseCommand
 
 	flagRelease bool
+	flagDryRun  bool
 }
 
 func (c *DeploymentCreateCommand) Run(args []string) int {
@@ -36,12 +37,29 @@ func (c *DeploymentCreateCommand) Run(args []string) int {
 		push, err := client.GetLatestPushedArtifact(ctx, &pb.GetLatestPushedArtifactRequest{
 			Application: app.Ref(),
 			Workspace:   c.project.WorkspaceRef(),
+			Labels:      c.artifactLabels(app.Ref().Application),
 		})
 		if err != nil {
 			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
 
+		// A dry run only shows what would be deployed
+		if c.flagDryRun {
+			app.UI.Output("Planning deploy...", terminal.WithHeaderStyle())
+			result, err := app.Deploy(ctx, &pb.Job_DeployOp{
+				Artifact: push,
+				DryRun:   true,
+			})
+			if err != nil {
+				app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+				return ErrSentinel
+			}
+
+			outputPlan(app.UI, result.Plan)
+			return nil
+		}
+
 		// Push it
 		app.UI.Output("Deploying...", terminal.WithHeaderStyle())
 		result, err := app.Deploy(ctx, &pb.Job_DeployOp{
@@ -119,6 +137,14 @@ func (c *DeploymentCreateCommand) Flags() *flag.Sets {
 			Usage:   "Release this deployment immedately.",
 			Default: true,
 		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "dry-run",
+			Target: &c.flagDryRun,
+			Usage: "Show the resources the deployment would create, such as the " +
+				"container spec or Kubernetes manifest, without deploying. This is " +
+				"supported by the kubernetes, aws-ecs and docker platforms.",
+		})
 	})
 }
 
@@ -143,9 +169,21 @@ Alias: waypoint deploy
   pushed artifact by default. You can view a list of recent artifacts
   using the "artifact list" command.
 
+  With -dry-run, the resources that would be created are shown and
+  nothing is deployed or released.
+
 ` + c.Flags().Help())
 }
 
+// outputPlan outputs the plan of a dry run deploy.
+func outputPlan(ui terminal.UI, plan string) {
+	ui.Output("")
+	ui.Output("The deployment would create the following resources. Nothing was deployed.",
+		terminal.WithSuccessStyle())
+	ui.Output("")
+	ui.Output(plan)
+}
+
 const (
 	deployURLService = `
 The deploy was successful! A Waypoint deployment URL is shown below. This
@@ -23,7 +23,6 @@ type DeploymentDestroyCommand struct {
 }
 
 func (c *DeploymentDestroyCommand) Run(args []string) int {
-	ctx := c.Ctx
 	flags := c.Flags()
 
 	// Initialize. If we fail, we just exit since Init handles the UI.
@@ -34,6 +33,7 @@ func (c *DeploymentDestroyCommand) Run(args []string) int {
 	); err != nil {
 		return 1
 	}
+	ctx := c.Ctx
 	args = flags.Args()
 
 	// Determine the deployments to delete
@@ -65,7 +65,7 @@ func (c *DeploymentDestroyCommand) Run(args []string) int {
 		}
 
 		// Get our app client
-		app := c.project.App(deployment.Application.Application)
+		app := c.app(deployment.Application.Application)
 
 		c.ui.Output("Destroying deployment: %s", deployment.Id, terminal.WithInfoStyle())
 		if err := app.Destroy(ctx, &pb.Job_DestroyOp{
@@ -189,7 +189,7 @@ func (c *DeploymentDestroyCommand) Flags() *flag.Sets {
 }
 
 func (c *DeploymentDestroyCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictNothing
+	return c.predictDeployments()
 }
 
 func (c *DeploymentDestroyCommand) AutocompleteFlags() complete.Flags {
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type DeploymentDiffCommand struct {
+	*baseCommand
+
+	flagId idFormat
+}
+
+func (c *DeploymentDiffCommand) Run(args []string) int {
+	ctx := c.Ctx
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	client := c.project.Client()
+	var from, to *pb.Ref_Operation
+	switch len(args) {
+	case 0:
+		// Compare the two latest deployments of the workspace
+		resp, err := client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
+			Application: c.refApp,
+			Workspace:   c.project.WorkspaceRef(),
+			Order: &pb.OperationOrder{
+				Order: pb.OperationOrder_START_TIME,
+				Desc:  true,
+				Limit: 2,
+			},
+		})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+		if len(resp.Deployments) < 2 {
+			c.ui.Output("At least two deployments are needed to compare.", terminal.WithErrorStyle())
+			return 1
+		}
+
+		from = c.deploymentRef(resp.Deployments[1].Id)
+		to = c.deploymentRef(resp.Deployments[0].Id)
+
+	case 2:
+		from = c.deploymentRef(args[0])
+		to = c.deploymentRef(args[1])
+
+	default:
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	resp, err := client.DiffDeployments(ctx, &pb.DiffDeploymentsRequest{From: from, To: to})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Changes from deployment %s to %s",
+		c.flagId.FormatId(resp.From.Sequence, resp.From.Id),
+		c.flagId.FormatId(resp.To.Sequence, resp.To.Id),
+		terminal.WithHeaderStyle())
+	if len(resp.Changes) == 0 {
+		c.ui.Output("No changes.")
+		return 0
+	}
+
+	var pluginConfig *pb.DiffDeploymentsResponse_Change
+	table := terminal.NewTable("Change", "Name", "From", "To")
+	for _, change := range resp.Changes {
+		if change.Kind == pb.DiffDeploymentsResponse_Change_PLUGIN_CONFIG {
+			// This is shown as a diff below the table.
+			pluginConfig = change
+			table.Rich([]string{"plugin config", "", "", "(see below)"}, nil)
+			continue
+		}
+
+		kind := strings.Replace(strings.ToLower(change.Kind.String()), "_", " ", -1)
+		table.Rich([]string{
+			kind,
+			change.Name,
+			diffValue(change.From),
+			diffValue(change.To),
+		}, []string{
+			"",
+			terminal.Green,
+			"",
+			"",
+		})
+	}
+	c.ui.Table(table)
+
+	if pluginConfig != nil {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(pluginConfig.From),
+			B:        difflib.SplitLines(pluginConfig.To),
+			FromFile: "deployment " + strconv.FormatUint(resp.From.Sequence, 10),
+			ToFile:   "deployment " + strconv.FormatUint(resp.To.Sequence, 10),
+			Context:  3,
+		})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		c.ui.Output("Plugin configuration", terminal.WithHeaderStyle())
+		c.ui.Output(diff)
+	}
+
+	return 0
+}
+
+// deploymentRef returns the reference to a deployment by ID or sequence
+// number of the app.
+func (c *DeploymentDiffCommand) deploymentRef(id string) *pb.Ref_Operation {
+	if v, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return &pb.Ref_Operation{
+			Target: &pb.Ref_Operation_Sequence{
+				Sequence: &pb.Ref_OperationSeq{
+					Application: c.refApp,
+					Number:      v,
+				},
+			},
+		}
+	}
+
+	return &pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: id},
+	}
+}
+
+// diffValue formats a value of a change, which is empty if it was added
+// or removed.
+func diffValue(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+
+	return v
+}
+
+func (c *DeploymentDiffCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initIdFormat(f, &c.flagId)
+	})
+}
+
+func (c *DeploymentDiffCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *DeploymentDiffCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *DeploymentDiffCommand) Synopsis() string {
+	return "Show what changed between two deployments."
+}
+
+func (c *DeploymentDiffCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint deployment diff [options] [FROM TO]
+
+  Show what changed between two deployments of an app.
+
+  The deployments are given by their sequence number or ID. They may be
+  in different workspaces. If no deployments are given, the two latest
+  deployments in the workspace are compared.
+
+  This compares the artifact digest, the platform and its configuration,
+  the labels, and the config variables that applied to the deployments
+  when they were created. Config variables are compared by fingerprint,
+  so their values aren't shown.
+
+` + c.Flags().Help())
+}
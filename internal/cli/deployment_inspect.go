@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type DeploymentInspectCommand struct {
+	*baseCommand
+
+	flagJson      bool
+	flagResources bool
+}
+
+func (c *DeploymentInspectCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+	ctx := c.Ctx
+	args = flags.Args()
+
+	if len(args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	ref := &pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: args[0]},
+	}
+	if v, err := strconv.ParseUint(args[0], 10, 64); err == nil {
+		ref.Target = &pb.Ref_Operation_Sequence{
+			Sequence: &pb.Ref_OperationSeq{
+				Application: c.refApp,
+				Number:      v,
+			},
+		}
+	}
+
+	deployment, err := c.project.Client().GetDeployment(ctx, &pb.GetDeploymentRequest{
+		Ref:         ref,
+		LoadDetails: pb.Deployment_BUILD,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	if c.flagJson {
+		if err := outputJson(deployment); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	values := []terminal.NamedValue{
+		{Name: "ID", Value: deployment.Id},
+		{Name: "Sequence", Value: deployment.Sequence},
+		{Name: "Application", Value: deployment.Application.Application},
+		{Name: "Workspace", Value: deployment.Workspace.Workspace},
+		{Name: "Platform", Value: deployment.Component.Name},
+		{Name: "State", Value: strings.ToLower(deployment.State.String())},
+		{Name: "Status", Value: strings.ToLower(deployment.Status.State.String())},
+		{Name: "Artifact ID", Value: deployment.ArtifactId},
+	}
+	if ts, err := ptypes.Timestamp(deployment.Status.StartTime); err == nil {
+		values = append(values, terminal.NamedValue{
+			Name: "Started", Value: ts.Local().Format("2006-01-02 15:04:05")})
+	}
+	if d := statusDuration(deployment.Status); d != "" {
+		values = append(values, terminal.NamedValue{Name: "Duration", Value: d})
+	}
+	if build := deployment.Preload.GetBuild(); build != nil {
+		if image, _ := artifactImage(build.Artifact); image != "" {
+			values = append(values, terminal.NamedValue{Name: "Image", Value: image})
+		}
+	}
+	if url := deployment.Preload.GetDeployUrl(); url != "" {
+		values = append(values, terminal.NamedValue{Name: "URL", Value: "https://" + url})
+	}
+	if deployment.JobId != "" {
+		values = append(values, terminal.NamedValue{Name: "Job ID", Value: deployment.JobId})
+	}
+	if err := deployment.Status.Error; err != nil {
+		values = append(values, terminal.NamedValue{Name: "Error", Value: err.Message})
+	}
+
+	c.ui.NamedValues(values, terminal.WithInfoStyle())
+
+	if len(deployment.Labels) > 0 {
+		keys := make([]string, 0, len(deployment.Labels))
+		for k := range deployment.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		labels := make([]terminal.NamedValue, 0, len(keys))
+		for _, k := range keys {
+			labels = append(labels, terminal.NamedValue{Name: k, Value: deployment.Labels[k]})
+		}
+
+		c.ui.Output("Labels", terminal.WithHeaderStyle())
+		c.ui.NamedValues(labels, terminal.WithInfoStyle())
+	}
+
+	if !c.flagResources || deployment.State == pb.Operation_DESTROYED {
+		return 0
+	}
+
+	// Ask the platform for the resources. This runs a job since only the
+	// platform plugin knows how to find them.
+	c.ui.Output("Resources", terminal.WithHeaderStyle())
+	app := c.app(deployment.Application.Application)
+	result, err := app.Inspect(ctx, &pb.Job_InspectOp{
+		Deployment: deployment,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	if len(result.Resources) == 0 {
+		c.ui.Output("The platform didn't declare any resources for this deployment.")
+		return 0
+	}
+
+	tbl := terminal.NewTable("Type", "Name", "State")
+	for _, r := range result.Resources {
+		tbl.Rich([]string{r.Type, r.Name, r.State}, nil)
+	}
+
+	c.ui.Table(tbl)
+	return 0
+}
+
+func (c *DeploymentInspectCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOperation, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initJsonFlag(f, &c.flagJson)
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "resources",
+			Target:  &c.flagResources,
+			Default: true,
+			Usage: "Show the resources on the platform and their current state. This " +
+				"runs a job to query the platform.",
+		})
+	})
+}
+
+func (c *DeploymentInspectCommand) AutocompleteArgs() complete.Predictor {
+	return c.predictDeployments()
+}
+
+func (c *DeploymentInspectCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *DeploymentInspectCommand) Synopsis() string {
+	return "Show the details of a deployment and its platform resources."
+}
+
+func (c *DeploymentInspectCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint deployment inspect [options] ID
+
+  Show the details of a deployment, including its labels and the resources
+  on the platform that make it up.
+
+  The deployment is given by its ID or sequence number. The resources, such
+  as the container, the Kubernetes deployment and service, or the ECS
+  service and load balancer ARNs, are listed by the kubernetes, aws-ecs and
+  docker platforms along with their current state. With -json, only the
+  deployment is output.
+
+` + c.Flags().Help())
+}
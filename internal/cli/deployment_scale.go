@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"strconv"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type DeploymentScaleCommand struct {
+	*baseCommand
+}
+
+func (c *DeploymentScaleCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+	ctx := c.Ctx
+	args = flags.Args()
+
+	if len(args) != 2 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	replicas, err := strconv.ParseInt(args[1], 10, 32)
+	if err != nil || replicas < 0 {
+		c.ui.Output("The number of replicas must be a non-negative integer.", terminal.WithErrorStyle())
+		return 1
+	}
+
+	ref := &pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: args[0]},
+	}
+	if v, err := strconv.ParseUint(args[0], 10, 64); err == nil {
+		ref.Target = &pb.Ref_Operation_Sequence{
+			Sequence: &pb.Ref_OperationSeq{
+				Application: c.refApp,
+				Number:      v,
+			},
+		}
+	}
+
+	deployment, err := c.project.Client().GetDeployment(ctx, &pb.GetDeploymentRequest{
+		Ref: ref,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Get our app client
+	app := c.app(deployment.Application.Application)
+
+	c.ui.Output("Scaling deployment %s to %d replicas", deployment.Id, replicas, terminal.WithHeaderStyle())
+	if err := app.Scale(ctx, &pb.Job_ScaleOp{
+		Deployment: deployment,
+		Replicas:   int32(replicas),
+	}); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	return 0
+}
+
+func (c *DeploymentScaleCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOperation, nil)
+}
+
+func (c *DeploymentScaleCommand) AutocompleteArgs() complete.Predictor {
+	return c.predictDeployments()
+}
+
+func (c *DeploymentScaleCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *DeploymentScaleCommand) Synopsis() string {
+	return "Change the number of replicas of a deployment."
+}
+
+func (c *DeploymentScaleCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint deployment scale [options] ID REPLICAS
+
+  Change the number of replicas of a deployment without deploying again.
+
+  The deployment is given by its ID or sequence number. Scaling is
+  supported by the kubernetes, nomad, aws-ecs and docker platforms. A
+  docker deployment is a single container, so it can only be scaled to
+  0 or 1 replicas, which stops or starts the container.
+
+` + c.Flags().Help())
+}
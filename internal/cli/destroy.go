@@ -2,6 +2,9 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strconv"
 
 	"github.com/golang/protobuf/ptypes/empty"
 
@@ -14,8 +17,18 @@ import (
 
 type DestroyCommand struct {
 	*baseCommand
+
+	flagDeployment    string
+	flagRelease       string
+	flagReleasesOnly  bool
+	flagAllWorkspaces bool
+	flagAutoApprove   bool
 }
 
+// destroyProtectedRe matches the names of workspaces that require typing
+// the workspace name to confirm a destroy.
+var destroyProtectedRe = regexp.MustCompile(`^prod(uction)?(-.*)?$`)
+
 func (c *DestroyCommand) Run(args []string) int {
 	// Initialize. If we fail, we just exit since Init handles the UI.
 	if err := c.Init(
@@ -26,16 +39,50 @@ func (c *DestroyCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Only one target can be given
+	targets := 0
+	for _, v := range []bool{
+		c.flagDeployment != "", c.flagRelease != "", c.flagReleasesOnly,
+	} {
+		if v {
+			targets++
+		}
+	}
+	if targets > 1 {
+		c.ui.Output("Only one of -deployment, -release and -releases-only may be given.",
+			terminal.WithErrorStyle())
+		return 1
+	}
+	if c.flagAllWorkspaces && (c.flagDeployment != "" || c.flagRelease != "") {
+		c.ui.Output("The -all-workspaces flag can't be used with -deployment or -release.",
+			terminal.WithErrorStyle())
+		return 1
+	}
+
 	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
-		if err := app.Destroy(ctx, &pb.Job_DestroyOp{
-			Target: &pb.Job_DestroyOp_Workspace{
-				Workspace: &empty.Empty{},
-			},
-		}); err != nil {
-			c.ui.Output("Error destroying: %s", err.Error(), terminal.WithErrorStyle())
+		op, ws, err := c.destroyTarget(ctx, app)
+		if err != nil {
+			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
 
+		for _, w := range ws {
+			if !c.confirm(w) {
+				return ErrSentinel
+			}
+		}
+
+		for _, w := range ws {
+			if len(ws) > 1 {
+				app.UI.Output("Destroying in workspace %q", w.Workspace, terminal.WithHeaderStyle())
+			}
+
+			if err := app.DestroyWorkspace(ctx, op, w); err != nil {
+				app.UI.Output("Error destroying: %s", err.Error(), terminal.WithErrorStyle())
+				return ErrSentinel
+			}
+		}
+
 		app.UI.Output("Destroy successful!", terminal.WithSuccessStyle())
 		return nil
 	})
@@ -50,8 +97,181 @@ func (c *DestroyCommand) Run(args []string) int {
 	return 0
 }
 
+// destroyTarget returns the destroy operation for the flags and the
+// workspaces to run it in.
+func (c *DestroyCommand) destroyTarget(
+	ctx context.Context,
+	app *clientpkg.App,
+) (*pb.Job_DestroyOp, []*pb.Ref_Workspace, error) {
+	client := c.project.Client()
+
+	switch {
+	case c.flagDeployment != "":
+		deployment, err := client.GetDeployment(ctx, &pb.GetDeploymentRequest{
+			Ref: c.operationRef(app, c.flagDeployment),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// IDs are unique across apps, so check that it is from this app
+		if !destroySameApp(deployment.Application, app.Ref()) {
+			return nil, nil, fmt.Errorf(
+				"The deployment %q belongs to the app %q, not %q. Target its app with -app.",
+				c.flagDeployment, deployment.Application.GetApplication(), app.Ref().Application)
+		}
+
+		return &pb.Job_DestroyOp{
+			Target: &pb.Job_DestroyOp_Deployment{Deployment: deployment},
+		}, []*pb.Ref_Workspace{deployment.Workspace}, nil
+
+	case c.flagRelease != "":
+		release, err := client.GetRelease(ctx, &pb.GetReleaseRequest{
+			Ref: c.operationRef(app, c.flagRelease),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !destroySameApp(release.Application, app.Ref()) {
+			return nil, nil, fmt.Errorf(
+				"The release %q belongs to the app %q, not %q. Target its app with -app.",
+				c.flagRelease, release.Application.GetApplication(), app.Ref().Application)
+		}
+
+		return &pb.Job_DestroyOp{
+			Target: &pb.Job_DestroyOp_Release{Release: release},
+		}, []*pb.Ref_Workspace{release.Workspace}, nil
+	}
+
+	op := &pb.Job_DestroyOp{
+		Target: &pb.Job_DestroyOp_Workspace{
+			Workspace: &empty.Empty{},
+		},
+	}
+	if c.flagReleasesOnly {
+		op.Target = &pb.Job_DestroyOp_Releases{
+			Releases: &empty.Empty{},
+		}
+	}
+
+	if !c.flagAllWorkspaces {
+		return op, []*pb.Ref_Workspace{c.project.WorkspaceRef()}, nil
+	}
+
+	// Find all the workspaces the app has been used in
+	resp, err := client.ListWorkspaces(ctx, &empty.Empty{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ws []*pb.Ref_Workspace
+	for _, w := range resp.Workspaces {
+		for _, a := range w.Applications {
+			if destroySameApp(a.Application, app.Ref()) {
+				ws = append(ws, &pb.Ref_Workspace{Workspace: w.Name})
+				break
+			}
+		}
+	}
+	if len(ws) == 0 {
+		return nil, nil, fmt.Errorf(
+			"The app %q hasn't been used in any workspace.", app.Ref().Application)
+	}
+
+	return op, ws, nil
+}
+
+// destroySameApp returns true if a and b refer to the same app.
+func destroySameApp(a, b *pb.Ref_Application) bool {
+	return a.GetProject() == b.GetProject() &&
+		a.GetApplication() == b.GetApplication()
+}
+
+// operationRef returns the reference for an operation given by ID or
+// sequence number.
+func (c *DestroyCommand) operationRef(app *clientpkg.App, id string) *pb.Ref_Operation {
+	if v, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return &pb.Ref_Operation{
+			Target: &pb.Ref_Operation_Sequence{
+				Sequence: &pb.Ref_OperationSeq{
+					Application: app.Ref(),
+					Number:      v,
+				},
+			},
+		}
+	}
+
+	return &pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: id},
+	}
+}
+
+// confirm asks the user to type the name of the workspace if it is a
+// production workspace. This returns true if the destroy can continue.
+func (c *DestroyCommand) confirm(ws *pb.Ref_Workspace) bool {
+	if c.flagAutoApprove || !destroyProtectedRe.MatchString(ws.Workspace) {
+		return true
+	}
+
+	if !c.ui.Interactive() {
+		c.ui.Output("Workspace %q is a production workspace. Destroying in it "+
+			"requires confirmation, so -auto-approve must be set when not "+
+			"running interactively.", ws.Workspace, terminal.WithErrorStyle())
+		return false
+	}
+
+	result, err := c.ui.Input(&terminal.Input{
+		Prompt: fmt.Sprintf("Workspace %q is a production workspace. "+
+			"Type the workspace name to confirm the destroy: ", ws.Workspace),
+		Style: terminal.WarningBoldStyle,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return false
+	}
+	if result != ws.Workspace {
+		c.ui.Output("The workspace name didn't match, nothing was destroyed.",
+			terminal.WithErrorStyle())
+		return false
+	}
+
+	return true
+}
+
 func (c *DestroyCommand) Flags() *flag.Sets {
 	return c.flagSet(flagSetOperation, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "deployment",
+			Target:     &c.flagDeployment,
+			Completion: c.predictDeployments(),
+			Usage:      "Only destroy the deployment with this ID or sequence number.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "release",
+			Target: &c.flagRelease,
+			Usage:  "Only destroy the release with this ID or sequence number.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "releases-only",
+			Target: &c.flagReleasesOnly,
+			Usage:  "Only destroy the releases, leaving the deployments.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "all-workspaces",
+			Target: &c.flagAllWorkspaces,
+			Usage:  "Destroy in every workspace the app has been used in.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "auto-approve",
+			Target: &c.flagAutoApprove,
+			Usage:  "Don't ask to confirm destroying in production workspaces.",
+		})
 	})
 }
 
@@ -73,8 +293,14 @@ Usage: waypoint destroy [options]
   as part of deploys and releases are destroyed. For example, any load balancers,
   VMs, containers, etc.
 
-  This targets one app in one workspace. You must call this for each workspace
-  you've used if you want to destroy everything.
+  This targets one app in one workspace by default. Use -all-workspaces to
+  destroy the app in every workspace it has been used in. A single deployment
+  or release can be destroyed with -deployment or -release, and only the
+  releases with -releases-only.
+
+  Destroying in a production workspace, one named "prod" or "production" or
+  starting with "prod-" or "production-", requires typing the name of the
+  workspace to confirm unless -auto-approve is set.
 
 ` + c.Flags().Help())
 }
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// destroyTestUI is an interactive terminal.UI that answers every input
+// with input.
+type destroyTestUI struct {
+	*plainUI
+
+	input    string
+	prompted bool
+}
+
+func (u *destroyTestUI) Input(*terminal.Input) (string, error) {
+	u.prompted = true
+	return u.input, nil
+}
+
+func (u *destroyTestUI) Interactive() bool {
+	return true
+}
+
+func TestDestroyProtectedRe(t *testing.T) {
+	cases := []struct {
+		Workspace string
+		Protected bool
+	}{
+		{"prod", true},
+		{"production", true},
+		{"prod-eu", true},
+		{"production-us-east", true},
+		{"default", false},
+		{"staging", false},
+		{"preprod", false},
+		{"products", false},
+		{"prod_eu", false},
+		{"Prod", false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Workspace, func(t *testing.T) {
+			require.Equal(t, tt.Protected, destroyProtectedRe.MatchString(tt.Workspace))
+		})
+	}
+}
+
+func TestDestroyCommandConfirm(t *testing.T) {
+	cases := []struct {
+		Name        string
+		Workspace   string
+		AutoApprove bool
+		Interactive bool
+		Input       string
+		Confirmed   bool
+		Prompted    bool
+	}{
+		{
+			"unprotected workspace",
+			"default",
+			false,
+			false,
+			"",
+			true,
+			false,
+		},
+
+		{
+			"auto approve",
+			"prod",
+			true,
+			false,
+			"",
+			true,
+			false,
+		},
+
+		{
+			"accept",
+			"prod",
+			false,
+			true,
+			"prod",
+			true,
+			true,
+		},
+
+		{
+			"reject",
+			"prod",
+			false,
+			true,
+			"production",
+			false,
+			true,
+		},
+
+		{
+			"non-interactive",
+			"prod",
+			false,
+			false,
+			"",
+			false,
+			false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			var out bytes.Buffer
+			plain := &plainUI{stdout: &out, stderr: &out}
+			ui := &destroyTestUI{plainUI: plain, input: tt.Input}
+
+			c := &DestroyCommand{
+				baseCommand:     &baseCommand{ui: plain},
+				flagAutoApprove: tt.AutoApprove,
+			}
+			if tt.Interactive {
+				c.ui = ui
+			}
+
+			ws := &pb.Ref_Workspace{Workspace: tt.Workspace}
+			require.Equal(tt.Confirmed, c.confirm(ws))
+			require.Equal(tt.Prompted, ui.prompted)
+			if tt.Confirmed {
+				require.Empty(out.String())
+			} else {
+				require.NotEmpty(out.String())
+			}
+		})
+	}
+}
+
+func TestDestroySameApp(t *testing.T) {
+	app := func(project, name string) *pb.Ref_Application {
+		return &pb.Ref_Application{Project: project, Application: name}
+	}
+
+	cases := []struct {
+		Name string
+		A, B *pb.Ref_Application
+		Same bool
+	}{
+		{"same app", app("p", "web"), app("p", "web"), true},
+		{"other app", app("p", "web"), app("p", "worker"), false},
+		{"other project", app("p", "web"), app("q", "web"), false},
+		{"missing", app("p", "web"), nil, false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require.Equal(t, tt.Same, destroySameApp(tt.A, tt.B))
+		})
+	}
+}
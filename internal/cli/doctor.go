@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/golang/protobuf/ptypes/empty"
+	goversion "github.com/hashicorp/go-version"
+	"github.com/posener/complete"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/version"
+)
+
+// doctorDockerPlugins and doctorKubernetesPlugins are the builtin plugins
+// that need a Docker daemon or a Kubernetes context on the machine that
+// runs them.
+var (
+	doctorDockerPlugins     = []string{"docker", "docker-pull", "pack"}
+	doctorKubernetesPlugins = []string{"kubernetes"}
+)
+
+type DoctorCommand struct {
+	*baseCommand
+
+	// fixes are the fixes for the findings, in the order they were found.
+	fixes []string
+
+	// failed is true if any check failed rather than only warned.
+	failed bool
+}
+
+func (c *DoctorCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI. The
+	// config and client are set up by the checks so that problems with
+	// them are findings rather than errors.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		return 1
+	}
+
+	sg := c.ui.StepGroup()
+	cfg := c.checkConfig(sg)
+	c.checkPlugins(sg, cfg)
+	if c.checkServer(sg) && cfg != nil {
+		c.checkAuth(sg, cfg)
+	}
+	sg.Wait()
+
+	if len(c.fixes) == 0 {
+		c.ui.Output("\nNo problems found.", terminal.WithSuccessStyle())
+		return 0
+	}
+
+	c.ui.Output("")
+	c.ui.Output("Findings", terminal.WithHeaderStyle())
+	for _, fix := range c.fixes {
+		c.ui.Output("- "+fix, terminal.WithWarningStyle())
+	}
+
+	if c.failed {
+		return 1
+	}
+
+	return 0
+}
+
+// warn marks the step as a warning with the fix for it.
+func (c *DoctorCommand) warn(s terminal.Step, msg, fix string) {
+	s.Update(msg)
+	s.Status(terminal.StatusWarn)
+	s.Done()
+	c.fixes = append(c.fixes, fix)
+}
+
+// fail marks the step as failed with the fix for it.
+func (c *DoctorCommand) fail(s terminal.Step, msg, fix string) {
+	s.Update(msg)
+	s.Status(terminal.StatusError)
+	s.Done()
+	c.fixes = append(c.fixes, fix)
+	c.failed = true
+}
+
+// ok marks the step as successful.
+func (c *DoctorCommand) ok(s terminal.Step, msg string, args ...interface{}) {
+	s.Update(msg, args...)
+	s.Status(terminal.StatusOK)
+	s.Done()
+}
+
+// checkConfig checks that the configuration in the current directory is
+// valid. This returns nil if there is no valid configuration.
+func (c *DoctorCommand) checkConfig(sg terminal.StepGroup) *config.Config {
+	s := sg.Add("Checking the configuration...")
+
+	path, err := c.initConfigPath()
+	if err != nil {
+		c.fail(s, "Unable to look for the configuration",
+			fmt.Sprintf("Looking for waypoint.hcl failed: %s", clierrors.Humanize(err)))
+		return nil
+	}
+	if path == "" {
+		c.warn(s, "No waypoint.hcl found",
+			"There is no waypoint.hcl in this directory or its parents, so the "+
+				"plugins and credentials of a project couldn't be checked. Run "+
+				"\"waypoint doctor\" in a project, or \"waypoint init\" to create one.")
+		return nil
+	}
+
+	cfg, err := c.initConfigLoad(path)
+	if err != nil {
+		c.fail(s, "The configuration is invalid",
+			fmt.Sprintf("%s is invalid: %s", path, clierrors.Humanize(err)))
+		return nil
+	}
+
+	c.refProject = &pb.Ref_Project{Project: cfg.Project}
+	c.ok(s, "Configuration %s is valid", path)
+	return cfg
+}
+
+// checkPlugins checks the local prerequisites of the configured plugins,
+// such as a Docker daemon. If there is no configuration, Docker is checked
+// since it is used by default.
+func (c *DoctorCommand) checkPlugins(sg terminal.StepGroup, cfg *config.Config) {
+	uses := map[string]bool{"docker": cfg == nil}
+	if cfg != nil {
+		for _, p := range cfg.Plugins() {
+			uses[p.Name] = true
+		}
+	}
+	usesAny := func(names []string) bool {
+		for _, n := range names {
+			if uses[n] {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if usesAny(doctorDockerPlugins) {
+		s := sg.Add("Checking the Docker daemon...")
+		if err := c.checkDocker(); err != nil {
+			c.fail(s, "Unable to connect to the Docker daemon",
+				fmt.Sprintf("The Docker daemon can't be reached: %s. Start Docker, or set "+
+					"DOCKER_HOST to the address of the daemon.", clierrors.Humanize(err)))
+		} else {
+			c.ok(s, "Docker daemon is running")
+		}
+	}
+
+	if usesAny(doctorKubernetesPlugins) {
+		s := sg.Add("Checking the Kubernetes context...")
+		raw, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(),
+			&clientcmd.ConfigOverrides{},
+		).RawConfig()
+		switch {
+		case err != nil:
+			c.fail(s, "Unable to load the kubeconfig",
+				fmt.Sprintf("The kubeconfig can't be loaded: %s", clierrors.Humanize(err)))
+		case raw.CurrentContext == "":
+			c.fail(s, "No Kubernetes context is set",
+				"The kubeconfig has no current context. Set one with "+
+					"\"kubectl config use-context\".")
+		case raw.Contexts[raw.CurrentContext] == nil:
+			c.fail(s, "The Kubernetes context doesn't exist",
+				fmt.Sprintf("The current context %q isn't in the kubeconfig. Set another "+
+					"with \"kubectl config use-context\".", raw.CurrentContext))
+		default:
+			c.ok(s, "Kubernetes context is %q", raw.CurrentContext)
+		}
+	}
+}
+
+// checkDocker pings the Docker daemon.
+func (c *DoctorCommand) checkDocker() error {
+	cli, err := client.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	cli.NegotiateAPIVersion(c.Ctx)
+	_, err = cli.Ping(c.Ctx)
+	return err
+}
+
+// checkServer checks that the server can be reached and that its version
+// matches the CLI. This returns true if the server can be used.
+func (c *DoctorCommand) checkServer(sg terminal.StepGroup) bool {
+	s := sg.Add("Checking the connection to the server...")
+
+	project, err := c.initClient()
+	if err != nil {
+		c.fail(s, "Unable to connect to the server",
+			fmt.Sprintf("The server can't be reached: %s. Check the server address "+
+				"with \"waypoint context verify\", or install a server with "+
+				"\"waypoint install\".", clierrors.Humanize(err)))
+		return false
+	}
+	c.project = project
+
+	resp, err := project.Client().GetVersionInfo(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.fail(s, "Unable to get the server version",
+			fmt.Sprintf("The server version can't be read: %s", clierrors.Humanize(err)))
+		return false
+	}
+	c.ok(s, "Connected to the server")
+
+	s = sg.Add("Checking the server version...")
+	local := version.GetVersion().VersionNumber()
+	server := resp.Info.Version
+	if skew, ok := doctorVersionSkew(local, server); ok && skew {
+		c.warn(s, fmt.Sprintf("Server version %s doesn't match the CLI", server),
+			fmt.Sprintf("The server is %s and the CLI is %s. Upgrade the older one so "+
+				"features and fixes match on both.", server, local))
+	} else {
+		c.ok(s, "Server version is %s", server)
+	}
+
+	return true
+}
+
+// checkAuth checks that the plugins of each app are authenticated. This
+// runs a job, so it checks the credentials where the runner is.
+func (c *DoctorCommand) checkAuth(sg terminal.StepGroup, cfg *config.Config) {
+	for _, appCfg := range cfg.Apps {
+		s := sg.Add("Checking the credentials of the plugins of app %q...", appCfg.Name)
+
+		app := c.app(appCfg.Name)
+		result, err := app.Auth(c.Ctx, &pb.Job_AuthOp{
+			CheckOnly: true,
+		})
+		if err != nil {
+			c.fail(s, fmt.Sprintf("Unable to check the credentials of app %q", appCfg.Name),
+				fmt.Sprintf("Checking the credentials of app %q failed: %s",
+					appCfg.Name, clierrors.Humanize(err)))
+			continue
+		}
+
+		var names []string
+		for _, r := range result.Results {
+			if !r.CheckResult {
+				names = append(names, fmt.Sprintf("%s %q",
+					strings.ToLower(r.Component.Type.String()), r.Component.Name))
+			}
+		}
+		if len(names) > 0 {
+			c.fail(s, fmt.Sprintf("Plugins of app %q aren't authenticated", appCfg.Name),
+				fmt.Sprintf("The %s of app %q can't authenticate. Check the credentials "+
+					"for them, such as the cloud CLI login or API keys in the environment.",
+					strings.Join(names, ", "), appCfg.Name))
+			continue
+		}
+
+		c.ok(s, "Plugins of app %q are authenticated", appCfg.Name)
+	}
+}
+
+// doctorVersionSkew returns true if the versions differ in the major or
+// minor version. ok is false if either version can't be parsed, such as for
+// development builds.
+func doctorVersionSkew(a, b string) (skew bool, ok bool) {
+	va, err := goversion.NewVersion(a)
+	if err != nil {
+		return false, false
+	}
+	vb, err := goversion.NewVersion(b)
+	if err != nil {
+		return false, false
+	}
+
+	sa, sb := va.Segments(), vb.Segments()
+	return sa[0] != sb[0] || sa[1] != sb[1], true
+}
+
+func (c *DoctorCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *DoctorCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *DoctorCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *DoctorCommand) Synopsis() string {
+	return "Check the environment for common problems."
+}
+
+func (c *DoctorCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint doctor [options]
+
+  Check the environment for common problems and print how to fix them.
+
+  This checks the waypoint.hcl in the current directory, the Docker daemon
+  and the Kubernetes context if the configured plugins need them, the
+  connection to the server, whether the server version matches the CLI,
+  and whether the configured plugins are authenticated.
+
+  This exits with a non-zero status if any check fails. Warnings, such as
+  a version mismatch, don't fail the command.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type EventsCommand struct {
+	*baseCommand
+
+	flagProject string
+	flagTypes   []string
+	flagJSON    bool
+}
+
+func (c *EventsCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithConfig(true),
+	); err != nil {
+		return 1
+	}
+
+	// Stream the events of the project we're in unless one is given.
+	req := &pb.StreamEventsRequest{}
+	if c.flagProject != "" {
+		req.Project = &pb.Ref_Project{Project: c.flagProject}
+	} else if c.refProject != nil {
+		req.Project = c.refProject
+	}
+	if c.flagApp != "" && req.Project != nil {
+		req.Application = &pb.Ref_Application{
+			Project:     req.Project.Project,
+			Application: c.flagApp,
+		}
+	}
+
+	for _, name := range c.flagTypes {
+		typ, ok := pb.Event_Type_value[strings.ToUpper(name)]
+		if !ok || typ == int32(pb.Event_UNKNOWN) {
+			c.ui.Output("Invalid event type %q. Must be one of: %s.",
+				name, strings.Join(eventTypeNames(), ", "), terminal.WithErrorStyle())
+			return 1
+		}
+
+		req.Types = append(req.Types, pb.Event_Type(typ))
+	}
+
+	stream, err := c.project.Client().StreamEvents(c.Ctx, req)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			return 0
+		}
+		if err != nil {
+			if clierrors.IsCanceled(err) {
+				return 0
+			}
+
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		if c.flagJSON {
+			data, err := protojson.Marshal(ev)
+			if err != nil {
+				c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+				return 1
+			}
+
+			c.ui.Output(string(data))
+			continue
+		}
+
+		var t string
+		if ts, err := ptypes.Timestamp(ev.Time); err == nil {
+			t = ts.Local().Format("2006-01-02 15:04:05")
+		}
+
+		c.ui.Output("%s %-21s %s/%s (%s) %s", t,
+			strings.ToLower(ev.Type.String()),
+			ev.Application.GetProject(),
+			ev.Application.GetApplication(),
+			ev.Workspace.GetWorkspace(),
+			eventSummary(ev))
+	}
+}
+
+// eventSummary returns a short description of the subject of the event.
+func eventSummary(ev *pb.Event) string {
+	switch subject := ev.Subject.(type) {
+	case *pb.Event_Job:
+		return fmt.Sprintf("job %s %s",
+			subject.Job.Id, strings.ToLower(subject.Job.State.String()))
+	case *pb.Event_Deployment:
+		return fmt.Sprintf("deployment v%d %s",
+			subject.Deployment.Sequence,
+			strings.ToLower(subject.Deployment.Status.GetState().String()))
+	case *pb.Event_Release:
+		return fmt.Sprintf("release v%d %s",
+			subject.Release.Sequence,
+			strings.ToLower(subject.Release.Status.GetState().String()))
+	case *pb.Event_Instance:
+		return fmt.Sprintf("instance %s of deployment %s",
+			subject.Instance.Id, subject.Instance.DeploymentId)
+	default:
+		return ""
+	}
+}
+
+// eventTypeNames returns the names of the event types for flags.
+func eventTypeNames() []string {
+	var result []string
+	for i := int32(1); ; i++ {
+		name, ok := pb.Event_Type_name[i]
+		if !ok {
+			return result
+		}
+
+		result = append(result, strings.ToLower(name))
+	}
+}
+
+func (c *EventsCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage: "Only stream the events of this project. This defaults to the " +
+				"current project. Outside of a project, the events of all projects are streamed.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "type",
+			Target: &c.flagTypes,
+			Usage: "Type of the events to stream. One of: " +
+				strings.Join(eventTypeNames(), ", ") + ". Can be specified " +
+				"multiple times. All events are streamed if this isn't set.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "json",
+			Target: &c.flagJSON,
+			Usage:  "Output each event as a line of JSON, including its subject.",
+		})
+	})
+}
+
+func (c *EventsCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *EventsCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *EventsCommand) Synopsis() string {
+	return "Stream the events of the server as they happen."
+}
+
+func (c *EventsCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint events [options]
+
+  Stream the events of the server as they happen, such as jobs changing
+  state, deployments and releases completing and instances connecting.
+
+  The events of the project in the current directory are streamed unless
+  -project is set. Use -json to process the events with other tools.
+
+` + c.Flags().Help())
+}
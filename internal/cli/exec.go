@@ -3,8 +3,12 @@ package cli
 import (
 	"context"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/posener/complete"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	clientpkg "github.com/hashicorp/waypoint/internal/client"
@@ -16,6 +20,11 @@ import (
 
 type ExecCommand struct {
 	*baseCommand
+
+	flagDeployment string
+	flagInstance   string
+	flagTTY        bool
+	flagTimeout    time.Duration
 }
 
 func (c *ExecCommand) Run(args []string) int {
@@ -31,25 +40,27 @@ func (c *ExecCommand) Run(args []string) int {
 	}
 
 	args = flagSet.Args()
+	if len(args) == 0 {
+		c.ui.Output("A command to execute is required.\n\n"+c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	ctx := c.Ctx
+	if c.flagTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.flagTimeout)
+		defer cancel()
+	}
 
 	var exitCode int
 	client := c.project.Client()
-	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
-		// Get the latest deployment
-		resp, err := client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
-			Application: app.Ref(),
-			Order: &pb.OperationOrder{
-				Limit: 1,
-				Order: pb.OperationOrder_COMPLETE_TIME,
-				Desc:  true,
-			},
-			PhysicalState: pb.Operation_CREATED,
-		})
+	err := c.DoApp(ctx, func(ctx context.Context, app *clientpkg.App) error {
+		deployment, err := execTargetDeployment(ctx, client, app, c.flagDeployment, c.flagInstance)
 		if err != nil {
 			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
-		if len(resp.Deployments) == 0 {
+		if deployment == nil {
 			app.UI.Output("No successful deployments found.", terminal.WithErrorStyle())
 			return ErrSentinel
 		}
@@ -59,8 +70,10 @@ func (c *ExecCommand) Run(args []string) int {
 			UI:            c.ui,
 			Context:       ctx,
 			Client:        client,
-			DeploymentId:  resp.Deployments[0].Id,
-			DeploymentSeq: resp.Deployments[0].Sequence,
+			DeploymentId:  deployment.Id,
+			DeploymentSeq: deployment.Sequence,
+			InstanceId:    c.flagInstance,
+			DisablePTY:    !c.flagTTY,
 			Args:          args,
 			Stdin:         os.Stdin,
 			Stdout:        os.Stdout,
@@ -72,6 +85,11 @@ func (c *ExecCommand) Run(args []string) int {
 			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
+		if ctx.Err() == context.DeadlineExceeded {
+			c.ui.Output("The command didn't finish within the timeout of %s.",
+				c.flagTimeout, terminal.WithErrorStyle())
+			return ErrSentinel
+		}
 
 		return nil
 	})
@@ -82,8 +100,116 @@ func (c *ExecCommand) Run(args []string) int {
 	return exitCode
 }
 
+// execTargetDeployment returns the deployment to exec into. This is the
+// deployment with the ID or sequence number deploymentId, the deployment of
+// the instance instanceId, or the latest deployment that is still created.
+// This returns nil if there is no such deployment.
+func execTargetDeployment(
+	ctx context.Context,
+	client pb.WaypointClient,
+	app *clientpkg.App,
+	deploymentId, instanceId string,
+) (*pb.Deployment, error) {
+	if id := deploymentId; id != "" {
+		ref := &pb.Ref_Operation{
+			Target: &pb.Ref_Operation_Id{Id: id},
+		}
+		if v, err := strconv.ParseUint(id, 10, 64); err == nil {
+			ref.Target = &pb.Ref_Operation_Sequence{
+				Sequence: &pb.Ref_OperationSeq{
+					Application: app.Ref(),
+					Number:      v,
+				},
+			}
+		}
+
+		return client.GetDeployment(ctx, &pb.GetDeploymentRequest{Ref: ref})
+	}
+
+	if id := instanceId; id != "" {
+		var token string
+		for {
+			resp, err := client.ListInstances(ctx, &pb.ListInstancesRequest{
+				Scope: &pb.ListInstancesRequest_Application_{
+					Application: &pb.ListInstancesRequest_Application{
+						Application: app.Ref(),
+					},
+				},
+				PageToken: token,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, inst := range resp.Instances {
+				if inst.Id == id {
+					return client.GetDeployment(ctx, &pb.GetDeploymentRequest{
+						Ref: &pb.Ref_Operation{
+							Target: &pb.Ref_Operation_Id{Id: inst.DeploymentId},
+						},
+					})
+				}
+			}
+
+			token = resp.NextPageToken
+			if token == "" {
+				return nil, status.Errorf(codes.NotFound,
+					"Instance %q of the app isn't connected to the server.", id)
+			}
+		}
+	}
+
+	// Get the latest deployment
+	resp, err := client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
+		Application: app.Ref(),
+		Order: &pb.OperationOrder{
+			Limit: 1,
+			Order: pb.OperationOrder_COMPLETE_TIME,
+			Desc:  true,
+		},
+		PhysicalState: pb.Operation_CREATED,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Deployments) == 0 {
+		return nil, nil
+	}
+
+	return resp.Deployments[0], nil
+}
+
 func (c *ExecCommand) Flags() *flag.Sets {
-	return c.flagSet(0, nil)
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "deployment",
+			Target:     &c.flagDeployment,
+			Completion: c.predictDeployments(),
+			Usage: "ID or sequence number of the deployment to execute in. This " +
+				"defaults to the latest deployment.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "instance",
+			Target: &c.flagInstance,
+			Usage: "ID of the instance to execute in. By default, the instance " +
+				"of the deployment with the fewest exec sessions is used.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "tty",
+			Target:  &c.flagTTY,
+			Usage:   "Allocate a TTY when the output is a terminal. Set this to false for non-interactive commands.",
+			Default: true,
+		})
+
+		f.DurationVar(&flag.DurationVar{
+			Name:   "timeout",
+			Target: &c.flagTimeout,
+			Usage:  "Stop the command if it doesn't finish within this duration. By default there is no timeout.",
+		})
+	})
 }
 
 func (c *ExecCommand) AutocompleteArgs() complete.Predictor {
@@ -100,9 +226,20 @@ func (c *ExecCommand) Synopsis() string {
 
 func (c *ExecCommand) Help() string {
 	return formatHelp(`
-Usage: waypoint exec [options]
+Usage: waypoint exec [options] [--] COMMAND [ARGS...]
 
   Execute a command in the context of a running application instance.
 
+  The command and its arguments are passed to the instance as-is, without
+  a shell. Use "--" before the command if its arguments start with "-" so
+  they aren't parsed as flags of exec.
+
+  Use -tty=false to run a command non-interactively, for example when
+  piping its output to another command.
+
+  With a TTY, the terminal of the command is resized along with the local
+  one. Without a TTY, the command sees the end of its input once stdin is
+  closed, so input can be piped to it. To copy files, see "waypoint cp".
+
 ` + c.Flags().Help())
 }
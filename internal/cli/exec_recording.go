@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ExecRecordingListCommand struct {
+	*baseCommand
+
+	flagProject    string
+	flagDeployment string
+	flagJson       bool
+}
+
+func (c *ExecRecordingListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	req := &pb.ListExecRecordingsRequest{DeploymentId: c.flagDeployment}
+	if c.flagProject != "" && c.flagApp != "" {
+		req.Application = &pb.Ref_Application{
+			Project:     c.flagProject,
+			Application: c.flagApp,
+		}
+	}
+
+	resp, err := c.project.Client().ListExecRecordings(c.Ctx, req)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range resp.Recordings {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(resp.Recordings) == 0 {
+		c.ui.Output("No exec sessions were recorded.")
+		return 0
+	}
+
+	table := terminal.NewTable("ID", "Started", "User", "App", "Deployment", "Command", "Exit Code")
+	for _, r := range resp.Recordings {
+		var app string
+		if r.Application != nil {
+			app = r.Application.Project + "/" + r.Application.Application
+		}
+
+		table.Rich([]string{
+			r.Id,
+			execRecordingTime(r.StartTime),
+			r.User,
+			app,
+			r.DeploymentId,
+			strings.Join(r.Args, " "),
+			strconv.Itoa(int(r.ExitCode)),
+		}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *ExecRecordingListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage:      "Project of the app given with -app to list the sessions of.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:       "deployment",
+			Target:     &c.flagDeployment,
+			Completion: c.predictDeployments(),
+			Usage:      "Only list the sessions of the deployment with this ID.",
+		})
+
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *ExecRecordingListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ExecRecordingListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ExecRecordingListCommand) Synopsis() string {
+	return "List the recorded exec sessions."
+}
+
+func (c *ExecRecordingListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint exec-recording list [options]
+
+  List the recorded exec sessions, newest first.
+
+  Sessions are only recorded if the server is started with recording
+  enabled. This requires admin permission.
+
+` + c.Flags().Help())
+}
+
+type ExecRecordingGetCommand struct {
+	*baseCommand
+
+	flagJSON bool
+}
+
+func (c *ExecRecordingGetCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	r, err := c.project.Client().GetExecRecording(c.Ctx, &pb.GetExecRecordingRequest{
+		Id: c.args[0],
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagJSON {
+		data, err := protojson.Marshal(r)
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		c.ui.Output(string(data))
+		return 0
+	}
+
+	c.ui.Output("Exec session %s", r.Id, terminal.WithHeaderStyle())
+	c.ui.NamedValues([]terminal.NamedValue{
+		{Name: "User", Value: r.User},
+		{Name: "Deployment", Value: r.DeploymentId},
+		{Name: "Instance", Value: r.InstanceId},
+		{Name: "Command", Value: strings.Join(r.Args, " ")},
+		{Name: "Started", Value: execRecordingTime(r.StartTime)},
+		{Name: "Ended", Value: execRecordingTime(r.EndTime)},
+		{Name: "Exit Code", Value: r.ExitCode},
+	}, terminal.WithInfoStyle())
+
+	c.ui.Output("Transcript", terminal.WithHeaderStyle())
+	for _, e := range r.Events {
+		channel := strings.ToLower(e.Channel.String())
+		for _, line := range strings.Split(strings.TrimRight(string(e.Data), "\r\n"), "\n") {
+			c.ui.Output("%s %-6s %s", execRecordingTime(e.Time), channel, strings.TrimRight(line, "\r"))
+		}
+	}
+	if r.Truncated {
+		c.ui.Output("The transcript reached the size limit of the server and the "+
+			"rest of the session wasn't recorded.", terminal.WithWarningStyle())
+	}
+
+	return 0
+}
+
+func (c *ExecRecordingGetCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "json",
+			Target: &c.flagJSON,
+			Usage:  "Output the recording as JSON.",
+		})
+	})
+}
+
+func (c *ExecRecordingGetCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ExecRecordingGetCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ExecRecordingGetCommand) Synopsis() string {
+	return "Show the transcript of a recorded exec session."
+}
+
+func (c *ExecRecordingGetCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint exec-recording get [options] ID
+
+  Show the transcript of a recorded exec session.
+
+  The transcript has the input and output of the session with the time
+  of each. Input isn't recorded if the server is configured to skip it.
+  This requires admin permission.
+
+` + c.Flags().Help())
+}
+
+// execRecordingTime formats a time of a recording in the local time zone.
+func execRecordingTime(t *timestamp.Timestamp) string {
+	ts, err := ptypes.Timestamp(t)
+	if err != nil {
+		return ""
+	}
+
+	return ts.Local().Format("2006-01-02 15:04:05")
+}
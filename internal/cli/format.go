@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
 )
 
@@ -26,3 +30,55 @@ func (i *idFormat) FormatId(seq uint64, long string) string {
 
 	return strconv.FormatUint(seq, 10)
 }
+
+// initJsonFlag adds the -json flag to commands that can output the
+// messages they show as JSON with outputJson or outputJsonList.
+func initJsonFlag(f *flag.Set, target *bool) {
+	f.BoolVar(&flag.BoolVar{
+		Name:   "json",
+		Target: target,
+		Usage:  "Output as JSON. The fields are named as in the API.",
+	})
+}
+
+// outputJson prints msg as indented JSON. The fields have the names of the
+// API rather than the columns of the table, so the output stays the same
+// for scripts when the table changes.
+func outputJson(msg proto.Message) error {
+	data, err := jsonMarshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return printJson(data)
+}
+
+// outputJsonList prints the messages as an indented JSON array. This prints
+// an empty array rather than nothing if there are no messages.
+func outputJsonList(msgs []proto.Message) error {
+	list := []json.RawMessage{}
+	for _, msg := range msgs {
+		data, err := jsonMarshal(msg)
+		if err != nil {
+			return err
+		}
+
+		list = append(list, data)
+	}
+
+	return printJson(list)
+}
+
+func jsonMarshal(msg proto.Message) (json.RawMessage, error) {
+	return protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+}
+
+func printJson(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
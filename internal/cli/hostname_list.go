@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/clierrors"
@@ -11,8 +12,19 @@ import (
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
+// hostnameTargetLabels are the labels that the server sets for the target
+// of a hostname, in the order of their columns.
+var hostnameTargetLabels = []string{
+	"waypoint.hashicorp.com/project",
+	"waypoint.hashicorp.com/app",
+	"waypoint.hashicorp.com/workspace",
+	":deployment",
+}
+
 type HostnameListCommand struct {
 	*baseCommand
+
+	flagJson bool
 }
 
 func (c *HostnameListCommand) Run(args []string) int {
@@ -31,13 +43,42 @@ func (c *HostnameListCommand) Run(args []string) int {
 		return 1
 	}
 
-	table := terminal.NewTable("Hostname", "FQDN", "Labels")
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range resp.Hostnames {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	table := terminal.NewTable("Hostname", "FQDN", "Project", "App", "Workspace", "Deployment", "Labels")
 	for _, hostname := range resp.Hostnames {
-		table.Rich([]string{
-			hostname.Hostname,
-			hostname.Fqdn,
-			fmt.Sprintf("%v", hostname.TargetLabels),
-		}, nil)
+		// The labels of the target are shown in their own columns and
+		// any other labels are shown together.
+		labels := map[string]string{}
+		for k, v := range hostname.TargetLabels {
+			labels[k] = v
+		}
+		target := make([]string, len(hostnameTargetLabels))
+		for i, k := range hostnameTargetLabels {
+			target[i] = labels[k]
+			delete(labels, k)
+		}
+
+		var extra string
+		if len(labels) > 0 {
+			extra = fmt.Sprintf("%v", labels)
+		}
+
+		table.Rich(append(
+			append([]string{hostname.Hostname, hostname.Fqdn}, target...),
+			extra,
+		), nil)
 	}
 
 	c.ui.Table(table)
@@ -45,7 +86,10 @@ func (c *HostnameListCommand) Run(args []string) int {
 }
 
 func (c *HostnameListCommand) Flags() *flag.Sets {
-	return c.flagSet(0, nil)
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initJsonFlag(f, &c.flagJson)
+	})
 }
 
 func (c *HostnameListCommand) AutocompleteArgs() complete.Predictor {
@@ -62,11 +106,12 @@ func (c *HostnameListCommand) Synopsis() string {
 
 func (c *HostnameListCommand) Help() string {
 	return formatHelp(`
-Usage: waypoint hostname delete HOSTNAME
+Usage: waypoint hostname list [options]
 
   List all registered hostnames.
 
-  This will list all the registered hostnames for all applications.
+  This will list all the registered hostnames for all applications. The
+  deployment is only set for hostnames that route to a single deployment.
 
-`)
+` + c.Flags().Help())
 }
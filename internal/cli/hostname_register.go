@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	clientpkg "github.com/hashicorp/waypoint/internal/client"
@@ -13,6 +14,8 @@ import (
 
 type HostnameRegisterCommand struct {
 	*baseCommand
+
+	flagDeployment string
 }
 
 func (c *HostnameRegisterCommand) Run(args []string) int {
@@ -32,16 +35,49 @@ func (c *HostnameRegisterCommand) Run(args []string) int {
 
 	client := c.project.Client()
 	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
-		resp, err := client.CreateHostname(ctx, &pb.CreateHostnameRequest{
-			Hostname: hostname,
-			Target: &pb.Hostname_Target{
-				Target: &pb.Hostname_Target_Application{
-					Application: &pb.Hostname_TargetApp{
+		target := &pb.Hostname_Target{
+			Target: &pb.Hostname_Target_Application{
+				Application: &pb.Hostname_TargetApp{
+					Application: app.Ref(),
+					Workspace:   c.project.WorkspaceRef(),
+				},
+			},
+		}
+
+		// If we're targeting a deployment, route only to that deployment.
+		if c.flagDeployment != "" {
+			ref := &pb.Ref_Operation{
+				Target: &pb.Ref_Operation_Id{Id: c.flagDeployment},
+			}
+			if v, err := strconv.ParseUint(c.flagDeployment, 10, 64); err == nil {
+				ref.Target = &pb.Ref_Operation_Sequence{
+					Sequence: &pb.Ref_OperationSeq{
 						Application: app.Ref(),
-						Workspace:   c.project.WorkspaceRef(),
+						Number:      v,
 					},
+				}
+			}
+
+			deployment, err := client.GetDeployment(ctx, &pb.GetDeploymentRequest{
+				Ref: ref,
+			})
+			if err != nil {
+				app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+				return ErrSentinel
+			}
+
+			target.Target = &pb.Hostname_Target_Deployment{
+				Deployment: &pb.Hostname_TargetDeployment{
+					Application: deployment.Application,
+					Workspace:   deployment.Workspace,
+					Sequence:    deployment.Sequence,
 				},
-			},
+			}
+		}
+
+		resp, err := client.CreateHostname(ctx, &pb.CreateHostnameRequest{
+			Hostname: hostname,
+			Target:   target,
 		})
 		if err != nil {
 			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
@@ -59,7 +95,16 @@ func (c *HostnameRegisterCommand) Run(args []string) int {
 }
 
 func (c *HostnameRegisterCommand) Flags() *flag.Sets {
-	return c.flagSet(0, nil)
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "deployment",
+			Target:     &c.flagDeployment,
+			Completion: c.predictDeployments(),
+			Usage: "ID or sequence number of a deployment to route to. By default " +
+				"the hostname routes to the released deployment of the app.",
+		})
+	})
 }
 
 func (c *HostnameRegisterCommand) AutocompleteArgs() complete.Predictor {
@@ -76,13 +121,17 @@ func (c *HostnameRegisterCommand) Synopsis() string {
 
 func (c *HostnameRegisterCommand) Help() string {
 	return formatHelp(`
-Usage: waypoint hostname register [hostname]
+Usage: waypoint hostname register [options] [hostname]
 
   Register a hostname with the URL service to route to your apps.
 
+  The hostname is a custom subdomain. If it isn't given, a hostname is
+  generated. The hostname routes to the app in the current workspace, or
+  to a single deployment with -deployment.
+
   The URL service must be enabled and configured with the Waypoint server.
   This will output the fully qualified domain name that should begin
   routing immediately.
 
-`)
+` + c.Flags().Help())
 }
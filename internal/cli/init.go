@@ -27,9 +27,10 @@ import (
 type InitCommand struct {
 	*baseCommand
 
-	from   string
-	into   string
-	update bool
+	from     string
+	into     string
+	template string
+	update   bool
 
 	project *clientpkg.Project
 	cfg     *configpkg.Config
@@ -130,6 +131,14 @@ func (c *InitCommand) Run(args []string) int {
 		return 0
 	}
 
+	if c.template != "" {
+		if !c.initTemplate() {
+			return 1
+		}
+
+		return 0
+	}
+
 	path, err := c.initConfigPath()
 	if err != nil {
 		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
@@ -299,11 +308,47 @@ func (c *InitCommand) validateProject() bool {
 			}
 		}
 
+		var retention *pb.Project_Retention
+		if r := c.cfg.Retention; r != nil {
+			retention = &pb.Project_Retention{
+				MaxCount: r.MaxCount,
+				MaxAge:   r.MaxAge,
+			}
+		}
+
+		var jobs *pb.Project_Jobs
+		if j := c.cfg.Jobs; j != nil {
+			jobs = &pb.Project_Jobs{
+				Priority:      j.Priority,
+				MaxConcurrent: j.MaxConcurrent,
+			}
+		}
+
+		var poll *pb.Project_Poll
+		if p := c.cfg.Runner.Poll; p != nil {
+			poll = &pb.Project_Poll{
+				Enabled:  p.Enabled,
+				Interval: p.Interval,
+			}
+		}
+
+		remoteEnabled := c.cfg.Runner.Enabled
+		if project != nil && !remoteEnabled && ds == nil && poll == nil {
+			// The runner settings may have been set with "waypoint project
+			// apply" instead, so we keep them if the config has none.
+			remoteEnabled = project.RemoteEnabled
+			ds = project.DataSource
+			poll = project.DataSourcePoll
+		}
+
 		resp, err := client.UpsertProject(c.Ctx, &pb.UpsertProjectRequest{
 			Project: &pb.Project{
-				Name:          ref.Project,
-				RemoteEnabled: c.cfg.Runner.Enabled,
-				DataSource:    ds,
+				Name:           ref.Project,
+				RemoteEnabled:  remoteEnabled,
+				DataSource:     ds,
+				DataSourcePoll: poll,
+				Retention:      retention,
+				Jobs:           jobs,
 			},
 		})
 		if err != nil {
@@ -317,17 +362,24 @@ func (c *InitCommand) validateProject() bool {
 
 	pt := &serverptypes.Project{Project: project}
 	for _, app := range c.cfg.Apps {
-		if pt.App(app.Name) >= 0 {
-			continue
-		}
+		if idx := pt.App(app.Name); idx >= 0 {
+			// The path globs are stored on the server so that it can
+			// decide which apps a push changed.
+			if initPathGlobsEqual(project.Applications[idx].PathGlobs, app.PathGlobs) {
+				continue
+			}
 
-		// Missing an application, register it.
-		s.Status(terminal.StatusWarn)
-		s.Update("Application %q is not registered with the server. Registering...", app.Name)
+			s.Update("Updating the path globs of application %q...", app.Name)
+		} else {
+			// Missing an application, register it.
+			s.Status(terminal.StatusWarn)
+			s.Update("Application %q is not registered with the server. Registering...", app.Name)
+		}
 
 		_, err := client.UpsertApplication(c.Ctx, &pb.UpsertApplicationRequest{
-			Project: ref,
-			Name:    app.Name,
+			Project:   ref,
+			Name:      app.Name,
+			PathGlobs: app.PathGlobs,
 		})
 		if err != nil {
 			c.stepError(s, initStepProject, err)
@@ -342,6 +394,21 @@ func (c *InitCommand) validateProject() bool {
 	return true
 }
 
+// initPathGlobsEqual returns true if the path globs of an app on the
+// server are the same as in the configuration.
+func initPathGlobsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (c *InitCommand) validatePlugins() bool {
 	sg := c.ui.StepGroup()
 	defer sg.Wait()
@@ -368,7 +435,7 @@ func (c *InitCommand) validateAuth() bool {
 
 	failures := false
 	for _, appcfg := range c.cfg.Apps {
-		app := c.project.App(appcfg.Name)
+		app := c.app(appcfg.Name)
 
 		ref := app.Ref()
 		s.Update("Checking auth for app: %q", ref.Application)
@@ -563,6 +630,14 @@ func (c *InitCommand) Flags() *flag.Sets {
 			Usage:   "Where to write the application fetched via -from",
 		})
 
+		f.StringVar(&flag.StringVar{
+			Name:   "template",
+			Target: &c.template,
+			Usage: "Create a waypoint.hcl, and a Dockerfile if the template has one, from a " +
+				"template. This is the name of a builtin template or the URL of a Git " +
+				"repository with a waypoint.hcl in its root.",
+		})
+
 		f.BoolVar(&flag.BoolVar{
 			Name:    "update",
 			Target:  &c.update,
@@ -598,6 +673,16 @@ Usage: waypoint init [options]
   This command is always safe to run multiple times. This command will never
   delete your configuration or any data in the server.
 
+  With -template, this creates the configuration from a template instead of
+  the sample configuration. The builtin templates are:
+
+    go-docker-k8s       Go app built with a Dockerfile, deployed to Kubernetes
+    node-pack-cloudrun  Node.js app built with buildpacks, deployed to Cloud Run
+    static-files        Static files copied to web servers over SSH
+
+  A template can also be fetched from a URL such as
+  "git::https://github.com/example/template.git".
+
 ` + c.Flags().Help())
 }
 
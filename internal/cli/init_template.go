@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	getter "github.com/hashicorp/go-getter"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/cli/datagen"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+)
+
+// initTemplateDir is the directory of the builtin templates in datagen.
+// Each template is a directory with a waypoint.hcl and optionally a
+// Dockerfile.
+const initTemplateDir = "templates"
+
+// initTemplateFiles are the files that are written from a template, if the
+// template has them.
+var initTemplateFiles = []string{configpkg.Filename, "Dockerfile"}
+
+// initTemplateData is the data the template files are rendered with.
+type initTemplateData struct {
+	// Project and App are both the name of the current directory, made
+	// into a valid name.
+	Project string
+	App     string
+}
+
+// initTemplates returns the names of the builtin templates.
+func initTemplates() []string {
+	names, err := datagen.AssetDir(initTemplateDir)
+	if err != nil {
+		// Should never happen because they are embedded.
+		panic(err)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// initTemplate writes the files of the template given with -template to
+// the current directory. The template is the name of a builtin template or
+// a URL of a repository that has the files in its root.
+func (c *InitCommand) initTemplate() bool {
+	if _, err := os.Stat(configpkg.Filename); err == nil {
+		c.ui.Output("A %s already exists in this directory and won't be overwritten.",
+			configpkg.Filename, terminal.WithErrorStyle())
+		return false
+	}
+
+	files, err := c.templateFiles(c.template)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return false
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return false
+	}
+	name := initTemplateName(filepath.Base(pwd))
+	data := &initTemplateData{Project: name, App: name}
+
+	for _, f := range initTemplateFiles {
+		contents, ok := files[f]
+		if !ok {
+			continue
+		}
+
+		if _, err := os.Stat(f); err == nil {
+			c.ui.Output("Keeping the existing %s.", f, terminal.WithWarningStyle())
+			continue
+		}
+
+		tpl, err := template.New(f).Parse(string(contents))
+		if err != nil {
+			c.ui.Output("Error parsing %s of the template: %s", f, err, terminal.WithErrorStyle())
+			return false
+		}
+
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, data); err != nil {
+			c.ui.Output("Error rendering %s of the template: %s", f, err, terminal.WithErrorStyle())
+			return false
+		}
+
+		if err := ioutil.WriteFile(f, buf.Bytes(), 0644); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return false
+		}
+
+		c.ui.Output("Created %s", f, terminal.WithSuccessStyle())
+	}
+
+	c.ui.Output("")
+	c.ui.Output("Waypoint configuration created from template %q!", c.template,
+		terminal.WithStyle(terminal.SuccessBoldStyle))
+	c.ui.Output(strings.TrimSpace(`
+Review the settings in "waypoint.hcl", such as the registry and the
+deployment target, and then run "waypoint init" again to validate the
+configuration and initialize your project.
+`),
+		terminal.WithSuccessStyle(),
+	)
+
+	return true
+}
+
+// templateFiles returns the contents of the files of a template by name.
+func (c *InitCommand) templateFiles(name string) (map[string][]byte, error) {
+	result := map[string][]byte{}
+
+	// Builtin templates
+	for _, t := range initTemplates() {
+		if t != name {
+			continue
+		}
+
+		for _, f := range initTemplateFiles {
+			data, err := datagen.Asset(path.Join(initTemplateDir, t, f))
+			if err == nil {
+				result[f] = data
+			}
+		}
+
+		return result, nil
+	}
+
+	// Anything else must be a URL, since a typo in a builtin name
+	// shouldn't be treated as a local path.
+	if !strings.Contains(name, "::") && !strings.Contains(name, "://") && !strings.Contains(name, "/") {
+		return nil, fmt.Errorf(
+			"Unknown template %q. The builtin templates are: %s. A Git URL may also be given.",
+			name, strings.Join(initTemplates(), ", "))
+	}
+
+	td, err := ioutil.TempDir("", "waypoint-template")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(td)
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	dst := filepath.Join(td, "template")
+	client := &getter.Client{
+		Src: name,
+		Dst: dst,
+		Pwd: pwd,
+		Dir: true,
+	}
+	if err := client.Get(); err != nil {
+		return nil, fmt.Errorf("Error fetching template: %s", err)
+	}
+
+	for _, f := range initTemplateFiles {
+		data, err := ioutil.ReadFile(filepath.Join(dst, f))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result[f] = data
+	}
+	if _, ok := result[configpkg.Filename]; !ok {
+		return nil, fmt.Errorf("The template at %q has no %s.", name, configpkg.Filename)
+	}
+
+	return result, nil
+}
+
+var initTemplateNameRe = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// initTemplateName returns a project and app name from the name of a
+// directory.
+func initTemplateName(dir string) string {
+	name := strings.Trim(initTemplateNameRe.ReplaceAllString(strings.ToLower(dir), "-"), "-")
+	if name == "" {
+		return "my-app"
+	}
+
+	return name
+}
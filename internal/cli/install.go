@@ -371,6 +371,16 @@ func (c *InstallCommand) Run(args []string) int {
 				terminal.WithErrorStyle(),
 			)
 
+			return 1
+		}
+	case "linux":
+		contextConfig, advertiseAddr, httpAddr, err = serverinstall.InstallLinux(ctx, c.ui, &c.config)
+		if err != nil {
+			c.ui.Output(
+				"Error installing server as a systemd service: %s", clierrors.Humanize(err),
+				terminal.WithErrorStyle(),
+			)
+
 			return 1
 		}
 	default:
@@ -562,7 +572,7 @@ func (c *InstallCommand) Flags() *flag.Sets {
 			Name:    "platform",
 			Target:  &c.platform,
 			Default: "kubernetes",
-			Usage:   "Platform to install the server into.",
+			Usage:   "Platform to install the server into. One of kubernetes, nomad, docker, or linux.",
 		})
 
 		f.StringVar(&flag.StringVar{
@@ -579,6 +589,7 @@ func (c *InstallCommand) Flags() *flag.Sets {
 		})
 
 		serverinstall.NomadFlags(f)
+		serverinstall.LinuxFlags(f)
 	})
 }
 
@@ -591,7 +602,7 @@ func (c *InstallCommand) AutocompleteFlags() complete.Flags {
 }
 
 func (c *InstallCommand) Synopsis() string {
-	return "Install the Waypoint server to Kubernetes, Nomad, or Docker"
+	return "Install the Waypoint server to Kubernetes, Nomad, Docker, or a Linux host"
 }
 
 func (c *InstallCommand) Help() string {
@@ -599,7 +610,10 @@ func (c *InstallCommand) Help() string {
 Usage: waypoint server install [options]
 Alias: waypoint install
 
-  Installs a Waypoint server to an existing Kubernetes cluster.
+  Installs a Waypoint server to an existing Kubernetes cluster. Use the
+  "-platform" flag to install to Nomad or Docker instead. The "linux" platform
+  installs the server as a systemd service on the host running this command,
+  which must be run as root.
 
   By default, this will also automatically create a new default CLI context
   (see "waypoint context") so the CLI will be configured to use the newly
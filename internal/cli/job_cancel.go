@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type JobCancelCommand struct {
+	*baseCommand
+}
+
+func (c *JobCancelCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) == 0 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	client := c.project.Client()
+	for _, id := range args {
+		_, err := client.CancelJob(c.Ctx, &pb.CancelJobRequest{JobId: id})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		c.ui.Output("Cancellation of job %q requested.", id, terminal.WithSuccessStyle())
+	}
+
+	return 0
+}
+
+func (c *JobCancelCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *JobCancelCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *JobCancelCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *JobCancelCommand) Synopsis() string {
+	return "Cancel jobs."
+}
+
+func (c *JobCancelCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint job cancel [options] ID...
+
+  Cancel one or more jobs.
+
+  Queued jobs are canceled right away. Running jobs are canceled by their
+  runner, which may take some time. Use "waypoint job inspect" to see when
+  the job is complete. Completed jobs aren't changed.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+)
+
+type JobGetStreamCommand struct {
+	*baseCommand
+}
+
+func (c *JobGetStreamCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if _, err := c.project.StreamJob(c.Ctx, args[0], c.ui); err != nil {
+		if clierrors.IsCanceled(err) {
+			return 0
+		}
+
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Job %q completed.", args[0], terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *JobGetStreamCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *JobGetStreamCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *JobGetStreamCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *JobGetStreamCommand) Synopsis() string {
+	return "Show the output of a job."
+}
+
+func (c *JobGetStreamCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint job get-stream [options] ID
+
+  Show the output of a job and wait for it to complete.
+
+  This attaches to a job that is queued or running, for example after
+  the command that queued it was interrupted. The output the job had
+  already written is shown first. Interrupting this command doesn't
+  cancel the job. For a job that is complete, its stored output is shown.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type JobInspectCommand struct {
+	*baseCommand
+
+	flagJson bool
+}
+
+func (c *JobInspectCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	job, err := c.project.Client().GetJob(c.Ctx, &pb.GetJobRequest{JobId: args[0]})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	if c.flagJson {
+		if err := outputJson(job); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	values := []terminal.NamedValue{
+		{Name: "ID", Value: job.Id},
+		{Name: "Operation", Value: jobOperation(job)},
+		{Name: "Project", Value: job.Application.GetProject()},
+		{Name: "Application", Value: job.Application.GetApplication()},
+		{Name: "Workspace", Value: job.Workspace.GetWorkspace()},
+		{Name: "State", Value: jobState(job)},
+	}
+	switch target := job.TargetRunner.GetTarget().(type) {
+	case *pb.Ref_Runner_Any:
+		values = append(values, terminal.NamedValue{Name: "Target Runner", Value: "any"})
+	case *pb.Ref_Runner_Id:
+		values = append(values, terminal.NamedValue{Name: "Target Runner", Value: target.Id.Id})
+	case *pb.Ref_Runner_Labels:
+		values = append(values, terminal.NamedValue{
+			Name:  "Target Runner",
+			Value: "labels " + configSyncLabels(target.Labels.Labels),
+		})
+	}
+	if id := job.AssignedRunner.GetId(); id != "" {
+		values = append(values, terminal.NamedValue{Name: "Assigned Runner", Value: id})
+	}
+	for _, t := range []struct {
+		name string
+		ts   *timestamp.Timestamp
+	}{
+		{"Queued", job.QueueTime},
+		{"Assigned", job.AssignTime},
+		{"Started", job.AckTime},
+		{"Completed", job.CompleteTime},
+		{"Canceled", job.CancelTime},
+		{"Expires", job.ExpireTime},
+	} {
+		if ts, err := ptypes.Timestamp(t.ts); err == nil {
+			values = append(values, terminal.NamedValue{
+				Name: t.name, Value: ts.Local().Format("2006-01-02 15:04:05")})
+		}
+	}
+	if err := job.Error; err != nil {
+		values = append(values, terminal.NamedValue{Name: "Error", Value: err.Message})
+	}
+
+	c.ui.NamedValues(values, terminal.WithInfoStyle())
+
+	if len(job.Labels) > 0 {
+		keys := make([]string, 0, len(job.Labels))
+		for k := range job.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		labels := make([]terminal.NamedValue, 0, len(keys))
+		for _, k := range keys {
+			labels = append(labels, terminal.NamedValue{Name: k, Value: job.Labels[k]})
+		}
+
+		c.ui.Output("Labels", terminal.WithHeaderStyle())
+		c.ui.NamedValues(labels, terminal.WithInfoStyle())
+	}
+
+	return 0
+}
+
+func (c *JobInspectCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *JobInspectCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *JobInspectCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *JobInspectCommand) Synopsis() string {
+	return "Show the details of a job."
+}
+
+func (c *JobInspectCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint job inspect [options] ID
+
+  Show the details of a job, including its state, the runner it is
+  assigned to and when it changed state.
+
+` + c.Flags().Help())
+}
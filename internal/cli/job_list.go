@@ -0,0 +1,210 @@
+package cli
+
+import (
+	stdflag "flag"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type JobListCommand struct {
+	*baseCommand
+
+	flagProject string
+	flagStates  []string
+	flagLimit   int
+	flagJson    bool
+}
+
+func (c *JobListCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithConfig(true),
+	); err != nil {
+		return 1
+	}
+
+	// The workspace flag always has a value so we only filter by it if
+	// it is given.
+	set := map[string]bool{}
+	flags.Visit(func(f *stdflag.Flag) { set[f.Name] = true })
+
+	// List the jobs of the project we're in unless one is given.
+	req := &pb.ListJobsRequest{Limit: uint32(c.flagLimit)}
+	if c.flagProject != "" {
+		req.Application = &pb.Ref_Application{Project: c.flagProject}
+	} else if c.refProject != nil {
+		req.Application = &pb.Ref_Application{Project: c.refProject.Project}
+	}
+	if c.flagApp != "" && req.Application != nil {
+		req.Application.Application = c.flagApp
+	}
+	if set["workspace"] {
+		req.Workspace = c.refWorkspace
+	}
+
+	for _, name := range c.flagStates {
+		state, ok := pb.Job_State_value[strings.ToUpper(name)]
+		if !ok || state == int32(pb.Job_UNKNOWN) {
+			c.ui.Output("Invalid job state %q. Must be one of: %s.",
+				name, strings.Join(jobStateNames(), ", "), terminal.WithErrorStyle())
+			return 1
+		}
+
+		req.State = append(req.State, pb.Job_State(state))
+	}
+
+	resp, err := c.project.Client().XListJobs(c.Ctx, req)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range resp.Jobs {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(resp.Jobs) == 0 {
+		c.ui.Output("No jobs found.", terminal.WithWarningStyle())
+		return 0
+	}
+
+	table := terminal.NewTable("ID", "Operation", "Project", "App", "Workspace", "State", "Queued")
+	for _, job := range resp.Jobs {
+		var queued string
+		if t, err := ptypes.Timestamp(job.QueueTime); err == nil {
+			queued = humanize.Time(t)
+		}
+
+		var colors []string
+		if job.State == pb.Job_ERROR {
+			colors = []string{"", "", "", "", "", terminal.Red, ""}
+		}
+
+		table.Rich([]string{
+			job.Id,
+			jobOperation(job),
+			job.Application.GetProject(),
+			job.Application.GetApplication(),
+			job.Workspace.GetWorkspace(),
+			jobState(job),
+			queued,
+		}, colors)
+	}
+
+	c.ui.Table(table)
+
+	return 0
+}
+
+// jobOperation returns the name of the operation of the job, such
+// as "build" or "deploy".
+func jobOperation(job *pb.Job) string {
+	m := job.ProtoReflect()
+	fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("operation"))
+	if fd == nil {
+		return ""
+	}
+
+	return string(fd.Name())
+}
+
+// jobState returns the state of the job, with the queue position if it
+// is queued.
+func jobState(job *pb.Job) string {
+	state := strings.ToLower(job.State.String())
+	if job.State == pb.Job_QUEUED && job.QueuePosition > 0 {
+		state += " (#" + strconv.FormatUint(uint64(job.QueuePosition), 10) + ")"
+	}
+
+	return state
+}
+
+// jobStateNames returns the names of the job states for flags.
+func jobStateNames() []string {
+	var result []string
+	for i := int32(1); ; i++ {
+		name, ok := pb.Job_State_name[i]
+		if !ok {
+			return result
+		}
+
+		result = append(result, strings.ToLower(name))
+	}
+}
+
+func (c *JobListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage: "Only list the jobs of this project. This defaults to the " +
+				"current project. Outside of a project, the jobs of all projects are listed.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "state",
+			Target: &c.flagStates,
+			Usage: "Only list jobs in this state. Can be specified multiple times. " +
+				"One of: " + strings.Join(jobStateNames(), ", ") + ".",
+			Completion: complete.PredictSet(jobStateNames()...),
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "limit",
+			Target:  &c.flagLimit,
+			Default: 50,
+			Usage:   "Maximum number of jobs to list. If this is zero, all jobs are listed.",
+		})
+
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *JobListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *JobListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *JobListCommand) Synopsis() string {
+	return "List the jobs of remote operations."
+}
+
+func (c *JobListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint job list [options]
+
+  List the jobs that run operations on runners, most recently queued first.
+
+  Queued jobs show their position in the queue. Use "waypoint job get-stream"
+  to show the output of a job.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/mr-tron/base58"
+	"github.com/posener/complete"
+	"github.com/skratchdot/open-golang/open"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type LoginCommand struct {
+	*baseCommand
+
+	flagCallbackAddr   string
+	flagNoBrowser      bool
+	flagContext        string
+	flagContextDefault bool
+}
+
+func (c *LoginCommand) Run(args []string) int {
+	ctx := c.Ctx
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	// Listen for the redirect from the provider before we send the user
+	// there so the port is known to be available.
+	ln, err := net.Listen("tcp", c.flagCallbackAddr)
+	if err != nil {
+		c.ui.Output(
+			"Error listening for the login callback on %s: %s",
+			c.flagCallbackAddr, err.Error(),
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+	defer ln.Close()
+	redirectURI := fmt.Sprintf("http://%s/oidc/callback", c.flagCallbackAddr)
+
+	state, err := loginRandom()
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	nonce, err := loginRandom()
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	client := c.project.Client()
+	urlResp, err := client.GetOIDCAuthURL(ctx, &pb.GetOIDCAuthURLRequest{
+		RedirectUri: redirectURI,
+		State:       state,
+		Nonce:       nonce,
+	})
+	if err != nil {
+		c.ui.Output(
+			"Error starting login: %s",
+			clierrors.Humanize(err),
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+
+	c.ui.Output("Complete the login in your browser. If it doesn't open, visit:")
+	c.ui.Output("")
+	c.ui.Output("  %s", urlResp.Url)
+	c.ui.Output("")
+	if !c.flagNoBrowser {
+		open.Run(urlResp.Url)
+	}
+
+	code, err := loginWaitCallback(ctx, ln, state)
+	if err != nil {
+		c.ui.Output(
+			"Error logging in: %s",
+			clierrors.Humanize(err),
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+
+	resp, err := client.CompleteOIDCAuth(ctx, &pb.CompleteOIDCAuthRequest{
+		RedirectUri: redirectURI,
+		Nonce:       nonce,
+		Code:        code,
+	})
+	if err != nil {
+		c.ui.Output(
+			"Error logging in: %s",
+			clierrors.Humanize(err),
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+
+	name := resp.User.Username
+	if name == "" {
+		name = resp.User.Email
+	}
+	if name == "" {
+		name = resp.User.Id
+	}
+
+	// If we aren't storing a context, output the token
+	if c.flagContext == "" {
+		c.ui.Output(resp.Token)
+		return 0
+	}
+
+	// Get our current context config and set our new token
+	config := *c.clientContext
+	config.Server.RequireAuth = true
+	config.Server.AuthToken = resp.Token
+
+	if err := c.contextStorage.Set(c.flagContext, &config); err != nil {
+		c.ui.Output(
+			"Error setting the CLI context: %s",
+			clierrors.Humanize(err),
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+	if c.flagContextDefault {
+		if err := c.contextStorage.SetDefault(c.flagContext); err != nil {
+			c.ui.Output(
+				"Error setting the CLI context: %s",
+				clierrors.Humanize(err),
+				terminal.WithErrorStyle(),
+			)
+			return 1
+		}
+	}
+
+	c.ui.Output("Logged in as %s. The token is stored in the CLI context %q.",
+		name, c.flagContext, terminal.WithSuccessStyle())
+	return 0
+}
+
+// loginWaitCallback serves the redirect from the OIDC provider on ln and
+// returns the code once the provider redirects back with a matching state.
+func loginWaitCallback(ctx context.Context, ln net.Listener, state string) (string, error) {
+	type result struct {
+		code string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oidc/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		var res result
+		switch {
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("the provider returned an error: %s %s",
+				q.Get("error"), q.Get("error_description"))
+
+		case subtle.ConstantTimeCompare([]byte(q.Get("state")), []byte(state)) != 1:
+			// This isn't the redirect for our request, ignore it. This
+			// protects against another site sending the browser here.
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+
+		case q.Get("code") == "":
+			res.err = fmt.Errorf("the provider did not return a code")
+
+		default:
+			res.code = q.Get("code")
+		}
+
+		if res.err != nil {
+			http.Error(w, res.err.Error(), http.StatusBadRequest)
+		} else {
+			io.WriteString(w, strings.TrimSpace(loginSuccessPage))
+		}
+
+		select {
+		case resultCh <- res:
+		default:
+		}
+	})
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	select {
+	case res := <-resultCh:
+		return res.code, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// loginRandom returns a random value for the state or nonce.
+func loginRandom() (string, error) {
+	var buf [32]byte
+	if _, err := io.ReadFull(rand.Reader, buf[:]); err != nil {
+		return "", err
+	}
+
+	return base58.Encode(buf[:]), nil
+}
+
+func (c *LoginCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetConnection, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "callback-addr",
+			Target: &c.flagCallbackAddr,
+			Usage: "Address to listen on for the redirect from the OIDC provider. " +
+				"This must match a redirect URI allowed by the server.",
+			Default: "localhost:8250",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "no-browser",
+			Target: &c.flagNoBrowser,
+			Usage:  "Don't open the login URL in a browser, only output it.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "context-create",
+			Target: &c.flagContext,
+			Usage: "Create or update a CLI context with the token for this login. " +
+				"If this is an empty string, the token is output instead.",
+			Default: "login",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "context-set-default",
+			Target:  &c.flagContextDefault,
+			Default: true,
+			Usage: "Set the context as the default CLI context. This " +
+				"only has an effect if -context-create is non-empty.",
+		})
+	})
+}
+
+func (c *LoginCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *LoginCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *LoginCommand) Synopsis() string {
+	return "Log in to the server with OIDC"
+}
+
+func (c *LoginCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint login [options]
+
+  Log in to a server using the OIDC provider configured for the server.
+
+  This opens a browser to log in with the provider. Once logged in, the
+  provider redirects back to a local address that this command listens on,
+  and the server returns a token for your user. The token is stored in a
+  CLI context, which is set as the default.
+
+  The server must be configured with OIDC. Tokens from this command expire,
+  after which this command must be run again.
+
+` + c.Flags().Help())
+}
+
+const loginSuccessPage = `
+<!DOCTYPE html>
+<html>
+<head><title>Waypoint</title></head>
+<body>
+<p>You are logged in to Waypoint. You can close this window and return to the CLI.</p>
+</body>
+</html>
+`
@@ -2,19 +2,34 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/posener/complete"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	clientpkg "github.com/hashicorp/waypoint/internal/client"
 	"github.com/hashicorp/waypoint/internal/clierrors"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	"github.com/hashicorp/waypoint/internal/pkg/structlog"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
 type LogsCommand struct {
 	*baseCommand
+
+	flagSince string
+	flagUntil string
+	flagLimit int
+	flagOp    string
+	flagLevel string
 }
 
 var headerColor = color.New(color.FgCyan)
@@ -29,8 +44,19 @@ func (c *LogsCommand) Run(args []string) int {
 		return 1
 	}
 
+	// If an operation is given, we show the output of its job instead.
+	if c.flagOp != "" {
+		return c.operation()
+	}
+
+	// If a time range is given, we show the lines the server kept
+	// instead of following the logs.
+	if c.flagSince != "" || c.flagUntil != "" {
+		return c.history()
+	}
+
 	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
-		lv, err := app.Logs(ctx)
+		lv, err := app.Logs(ctx, c.flagLevel)
 		if err != nil {
 			if !clierrors.IsCanceled(err) {
 				app.UI.Output("Error reading logs: %s", err, terminal.WithErrorStyle())
@@ -52,30 +78,77 @@ func (c *LogsCommand) Run(args []string) int {
 			}
 
 			for _, event := range batch {
-				event.Message = strings.TrimSuffix(event.Message, "\n")
-
-				// We use this format rather than regular RFC3339Nano because we use .0
-				// instead of .9, which preserves the spacing so the output is always
-				// lined up
-				ts := event.Timestamp.Format("2006-01-02T15:04:05.000Z07:00")
-				short := event.Partition
-				if len(short) > 6 {
-					short = short[len(short)-6:]
-				}
+				c.outputLine(event.Timestamp, event.Partition, event.Message)
+			}
+		}
 
-				header := headerColor.Sprintf("%s %s: ", ts, short)
-				if strings.IndexByte(event.Message, '\n') != -1 {
-					parts := strings.Split(event.Message, "\n")
-
-					for _, part := range parts {
-						m := header + part
-						c.ui.Output(m)
-					}
-				} else {
-					m := header + event.Message
-					c.ui.Output(m)
-				}
+		return nil
+	})
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+// history shows the lines that the server kept within the time range of
+// the flags.
+func (c *LogsCommand) history() int {
+	var timeRange pb.TimeRange
+	for _, v := range []struct {
+		Flag  string
+		Value string
+		Dst   **timestamp.Timestamp
+	}{
+		{"-since", c.flagSince, &timeRange.After},
+		{"-until", c.flagUntil, &timeRange.Before},
+	} {
+		if v.Value == "" {
+			continue
+		}
+
+		t, err := parseLogTime(v.Value)
+		if err != nil {
+			c.ui.Output("Invalid %s value: %s", v.Flag, err, terminal.WithErrorStyle())
+			return 1
+		}
+
+		*v.Dst, err = ptypes.TimestampProto(t)
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
+		resp, err := c.project.Client().GetLogs(ctx, &pb.GetLogsRequest{
+			Scope: &pb.GetLogsRequest_Application{
+				Application: &pb.GetLogStreamRequest_Application{
+					Application: app.Ref(),
+					Workspace:   c.project.WorkspaceRef(),
+				},
+			},
+			TimeRange: &timeRange,
+			Limit:     uint32(c.flagLimit),
+			Level:     c.flagLevel,
+		})
+		if err != nil {
+			app.UI.Output("Error reading logs: %s", clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		if resp.Truncated {
+			app.UI.Output("Only the latest %d lines in the time range are shown.",
+				len(resp.Lines), terminal.WithWarningStyle())
+		}
+
+		for _, line := range resp.Lines {
+			ts, err := ptypes.Timestamp(line.Entry.Timestamp)
+			if err != nil {
+				ts = time.Time{}
 			}
+
+			c.outputLine(ts, line.InstanceId, line.Entry.Line)
 		}
 
 		return nil
@@ -87,8 +160,163 @@ func (c *LogsCommand) Run(args []string) int {
 	return 0
 }
 
+// operation shows the output of the job of the operation given with -op.
+// The server stores the output of completed jobs, so this works after the
+// command that ran the operation is gone.
+func (c *LogsCommand) operation() int {
+	jobId, err := c.operationJobId(c.Ctx)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if _, err := c.project.StreamJob(c.Ctx, jobId, c.ui); err != nil {
+		if clierrors.IsCanceled(err) {
+			return 0
+		}
+
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	return 0
+}
+
+// operationJobId returns the ID of the job for -op. The value is a job ID,
+// or the ID or sequence number of a build, deployment or release of the app.
+func (c *LogsCommand) operationJobId(ctx context.Context) (string, error) {
+	client := c.project.Client()
+
+	_, err := client.GetJob(ctx, &pb.GetJobRequest{JobId: c.flagOp})
+	if err == nil {
+		return c.flagOp, nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return "", err
+	}
+
+	ref := &pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: c.flagOp},
+	}
+	if v, err := strconv.ParseUint(c.flagOp, 10, 64); err == nil {
+		ref.Target = &pb.Ref_Operation_Sequence{
+			Sequence: &pb.Ref_OperationSeq{
+				Application: c.refApp,
+				Number:      v,
+			},
+		}
+	}
+
+	// Try each kind of operation in the order they happen
+	for _, get := range []func() (string, error){
+		func() (string, error) {
+			v, err := client.GetBuild(ctx, &pb.GetBuildRequest{Ref: ref})
+			return v.GetJobId(), err
+		},
+		func() (string, error) {
+			v, err := client.GetDeployment(ctx, &pb.GetDeploymentRequest{Ref: ref})
+			return v.GetJobId(), err
+		},
+		func() (string, error) {
+			v, err := client.GetRelease(ctx, &pb.GetReleaseRequest{Ref: ref})
+			return v.GetJobId(), err
+		},
+	} {
+		jobId, err := get()
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if jobId == "" {
+			return "", fmt.Errorf("The operation %q wasn't run by a job, so there is "+
+				"no output for it.", c.flagOp)
+		}
+
+		return jobId, nil
+	}
+
+	return "", fmt.Errorf("No job, build, deployment or release was found for %q.", c.flagOp)
+}
+
+// outputLine outputs a log line of an instance.
+func (c *LogsCommand) outputLine(t time.Time, instanceId, message string) {
+	message = strings.TrimSuffix(message, "\n")
+
+	// We use this format rather than regular RFC3339Nano because we use .0
+	// instead of .9, which preserves the spacing so the output is always
+	// lined up
+	ts := t.Format("2006-01-02T15:04:05.000Z07:00")
+	short := instanceId
+	if len(short) > 6 {
+		short = short[len(short)-6:]
+	}
+
+	header := headerColor.Sprintf("%s %s: ", ts, short)
+	for _, part := range strings.Split(message, "\n") {
+		c.ui.Output(header + part)
+	}
+}
+
+// parseLogTime parses the value of -since or -until, which is either a
+// duration before now such as "1h" or an RFC3339 time.
+func parseLogTime(v string) (time.Time, error) {
+	if d, err := time.ParseDuration(v); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be a duration such as \"1h\" or an RFC3339 time")
+	}
+
+	return t, nil
+}
+
 func (c *LogsCommand) Flags() *flag.Sets {
-	return c.flagSet(0, nil)
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "since",
+			Target: &c.flagSince,
+			Usage: "Show the lines the server kept since this time instead of " +
+				"following the logs. This is a duration such as \"1h\" or an " +
+				"RFC3339 time.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "until",
+			Target: &c.flagUntil,
+			Usage: "Show the lines the server kept until this time instead of " +
+				"following the logs. This is a duration such as \"10m\" or an " +
+				"RFC3339 time.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "op",
+			Target: &c.flagOp,
+			Usage: "Show the output of the job of an operation instead of the app logs. " +
+				"This is a job ID, or the ID or sequence number of a build, " +
+				"deployment or release.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "level",
+			Target: &c.flagLevel,
+			Usage: "Only show the lines with at least this level, such as \"warn\" " +
+				"for warnings and errors. Lines are only known to have a level if " +
+				"the app logs JSON or logfmt.",
+			Completion: complete.PredictSet(structlog.Levels...),
+		})
+
+		f.IntVar(&flag.IntVar{
+			Name:    "limit",
+			Target:  &c.flagLimit,
+			Default: 1000,
+			Usage:   "Maximum number of lines to show with -since or -until. The latest lines are shown.",
+		})
+	})
 }
 
 func (c *LogsCommand) AutocompleteArgs() complete.Predictor {
@@ -116,5 +344,19 @@ Usage: waypoint logs [options]
   characters of the instance ID. This can be used to trace any logs back
   to a specific deployment or filter out certain log messages.
 
+  With -since or -until, the lines that the server kept in the time range
+  are shown instead and the command exits. How many lines are kept and
+  for how long is configured on the server.
+
+  With -level, only the lines with at least the level are shown, such as
+  "-level=error" for errors. The entrypoint parses the lines that the app
+  logs as JSON or logfmt for their level, and the lines are filtered by the
+  server. Lines that aren't structured or have no level aren't shown.
+
+  With -op, the output of the job that ran a build, deploy or release is
+  shown instead. The server stores the output of completed jobs, so this
+  shows the output of past operations. If the job is still running, this
+  waits for it to complete.
+
 ` + c.Flags().Help())
 }
@@ -12,12 +12,12 @@ import (
 	"sort"
 	"text/tabwriter"
 
+	"github.com/fatih/color"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 	"github.com/mitchellh/cli"
 	"github.com/mitchellh/go-glint"
 
-	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/pkg/signalcontext"
 	"github.com/hashicorp/waypoint/internal/version"
 )
@@ -28,6 +28,13 @@ const (
 
 	// EnvPlain is the env var that can be set to force plain output mode.
 	EnvPlain = "WAYPOINT_PLAIN"
+
+	// EnvCI is the env var that CI systems set. Output is plain when it is set.
+	EnvCI = "CI"
+
+	// EnvNoColor is the env var that can be set to disable colors. See
+	// https://no-color.org.
+	EnvNoColor = "NO_COLOR"
 )
 
 var (
@@ -116,18 +123,24 @@ func Commands(
 		globalOptions: opts,
 	}
 
-	// Set plain mode if set
-	if os.Getenv(EnvPlain) != "" {
+	// Set plain mode if set, or if we're running in CI
+	if os.Getenv(EnvPlain) != "" || os.Getenv(EnvCI) != "" {
 		baseCommand.globalOptions = append(baseCommand.globalOptions,
-			WithUI(terminal.NonInteractiveUI(ctx)))
+			WithUI(newPlainUI()))
+	}
+
+	// Disable colors everywhere if asked to
+	if os.Getenv(EnvNoColor) != "" {
+		color.NoColor = true
 	}
 
 	// aliases is a list of command aliases we have. The key is the CLI
 	// command (the alias) and the value is the existing target command.
 	aliases := map[string]string{
-		"build":   "artifact build",
-		"deploy":  "deployment deploy",
-		"install": "server install",
+		"build":                "artifact build",
+		"deploy":               "deployment deploy",
+		"install":              "server install",
+		"artifact list-builds": "build list",
 	}
 
 	// start building our commands
@@ -155,6 +168,19 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+
+		"cp": func() (cli.Command, error) {
+			return &CopyCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"status": func() (cli.Command, error) {
+			return &StatusCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
 		"config": func() (cli.Command, error) {
 			return &helpCommand{
 				SynopsisText: helpText["config"][0],
@@ -171,11 +197,36 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"config unset": func() (cli.Command, error) {
+			return &ConfigUnsetCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"config sync": func() (cli.Command, error) {
+			return &ConfigSyncCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"config source-get": func() (cli.Command, error) {
+			return &ConfigSourceGetCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"config source-set": func() (cli.Command, error) {
+			return &ConfigSourceSetCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 		"logs": func() (cli.Command, error) {
 			return &LogsCommand{
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"events": func() (cli.Command, error) {
+			return &EventsCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 
 		"artifact": func() (cli.Command, error) {
 			return &helpCommand{
@@ -195,14 +246,32 @@ func Commands(
 			}, nil
 		},
 
-		"artifact list-builds": func() (cli.Command, error) {
+		"artifact push": func() (cli.Command, error) {
+			return &ArtifactPushCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"build list": func() (cli.Command, error) {
 			return &BuildListCommand{
 				baseCommand: baseCommand,
 			}, nil
 		},
 
-		"artifact push": func() (cli.Command, error) {
-			return &ArtifactPushCommand{
+		"build inspect": func() (cli.Command, error) {
+			return &BuildInspectCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"pipeline": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["pipeline"][0],
+				HelpText:     helpText["pipeline"][1],
+			}, nil
+		},
+		"pipeline run": func() (cli.Command, error) {
+			return &PipelineRunCommand{
 				baseCommand: baseCommand,
 			}, nil
 		},
@@ -226,18 +295,60 @@ func Commands(
 			}, nil
 		},
 
+		"deployment diff": func() (cli.Command, error) {
+			return &DeploymentDiffCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"deployment inspect": func() (cli.Command, error) {
+			return &DeploymentInspectCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
 		"deployment list": func() (cli.Command, error) {
 			return &DeploymentListCommand{
 				baseCommand: baseCommand,
 			}, nil
 		},
 
+		"deployment scale": func() (cli.Command, error) {
+			return &DeploymentScaleCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"login": func() (cli.Command, error) {
+			return &LoginCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
 		"release": func() (cli.Command, error) {
 			return &ReleaseCreateCommand{
 				baseCommand: baseCommand,
 			}, nil
 		},
 
+		"release demote": func() (cli.Command, error) {
+			return &ReleaseDemoteCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"release list": func() (cli.Command, error) {
+			return &ReleaseListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"release promote": func() (cli.Command, error) {
+			return &ReleasePromoteCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
 		"server": func() (cli.Command, error) {
 			return &helpCommand{
 				SynopsisText: helpText["server"][0],
@@ -254,11 +365,36 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"server upgrade": func() (cli.Command, error) {
+			return &ServerUpgradeCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 		"server run": func() (cli.Command, error) {
 			return &ServerRunCommand{
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"server maintenance": func() (cli.Command, error) {
+			return &ServerMaintenanceCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"server prune": func() (cli.Command, error) {
+			return &ServerPruneCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"server snapshot": func() (cli.Command, error) {
+			return &ServerSnapshotCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"server restore": func() (cli.Command, error) {
+			return &ServerRestoreCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 		"server config-set": func() (cli.Command, error) {
 			return &ServerConfigSetCommand{
 				baseCommand: baseCommand,
@@ -320,6 +456,205 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"token list": func() (cli.Command, error) {
+			return &TokenListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"token revoke": func() (cli.Command, error) {
+			return &TokenRevokeCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"notification": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["notification"][0],
+				HelpText:     helpText["notification"][1],
+			}, nil
+		},
+		"notification create": func() (cli.Command, error) {
+			return &NotificationCreateCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"notification list": func() (cli.Command, error) {
+			return &NotificationListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"notification delete": func() (cli.Command, error) {
+			return &NotificationDeleteCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"trigger": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["trigger"][0],
+				HelpText:     helpText["trigger"][1],
+			}, nil
+		},
+		"trigger create": func() (cli.Command, error) {
+			return &TriggerCreateCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"trigger list": func() (cli.Command, error) {
+			return &TriggerListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"trigger delete": func() (cli.Command, error) {
+			return &TriggerDeleteCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"project": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["project"][0],
+				HelpText:     helpText["project"][1],
+			}, nil
+		},
+		"project apply": func() (cli.Command, error) {
+			return &ProjectApplyCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"project list": func() (cli.Command, error) {
+			return &ProjectListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"project inspect": func() (cli.Command, error) {
+			return &ProjectInspectCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"webhook": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["webhook"][0],
+				HelpText:     helpText["webhook"][1],
+			}, nil
+		},
+		"webhook create": func() (cli.Command, error) {
+			return &WebhookCreateCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"webhook list": func() (cli.Command, error) {
+			return &WebhookListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"webhook delete": func() (cli.Command, error) {
+			return &WebhookDeleteCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"audit": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["audit"][0],
+				HelpText:     helpText["audit"][1],
+			}, nil
+		},
+		"audit list": func() (cli.Command, error) {
+			return &AuditListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"exec-recording": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["exec-recording"][0],
+				HelpText:     helpText["exec-recording"][1],
+			}, nil
+		},
+		"exec-recording list": func() (cli.Command, error) {
+			return &ExecRecordingListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"exec-recording get": func() (cli.Command, error) {
+			return &ExecRecordingGetCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"organization": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["organization"][0],
+				HelpText:     helpText["organization"][1],
+			}, nil
+		},
+		"organization create": func() (cli.Command, error) {
+			return &OrganizationCreateCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"organization list": func() (cli.Command, error) {
+			return &OrganizationListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"organization delete": func() (cli.Command, error) {
+			return &OrganizationDeleteCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+
+		"user": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["user"][0],
+				HelpText:     helpText["user"][1],
+			}, nil
+		},
+		"user list": func() (cli.Command, error) {
+			return &UserListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"user grant": func() (cli.Command, error) {
+			return &UserPermissionCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"user revoke": func() (cli.Command, error) {
+			return &UserPermissionCommand{
+				baseCommand: baseCommand,
+				revoke:      true,
+			}, nil
+		},
+
+		"job": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["job"][0],
+				HelpText:     helpText["job"][1],
+			}, nil
+		},
+		"job list": func() (cli.Command, error) {
+			return &JobListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"job inspect": func() (cli.Command, error) {
+			return &JobInspectCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"job cancel": func() (cli.Command, error) {
+			return &JobCancelCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"job get-stream": func() (cli.Command, error) {
+			return &JobGetStreamCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 
 		"runner": func() (cli.Command, error) {
 			return &helpCommand{
@@ -332,6 +667,58 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+		"runner install": func() (cli.Command, error) {
+			return &RunnerInstallCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"runner list": func() (cli.Command, error) {
+			return &RunnerListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"runner inspect": func() (cli.Command, error) {
+			return &RunnerInspectCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"runner adopt": func() (cli.Command, error) {
+			return &RunnerAdoptCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"runner reject": func() (cli.Command, error) {
+			return &RunnerAdoptCommand{
+				baseCommand: baseCommand,
+				reject:      true,
+			}, nil
+		},
+		"runner forget": func() (cli.Command, error) {
+			return &RunnerForgetCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"runner profile": func() (cli.Command, error) {
+			return &helpCommand{
+				SynopsisText: helpText["runner profile"][0],
+				HelpText:     helpText["runner profile"][1],
+			}, nil
+		},
+		"runner profile set": func() (cli.Command, error) {
+			return &RunnerProfileSetCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"runner profile list": func() (cli.Command, error) {
+			return &RunnerProfileListCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
+		"runner profile delete": func() (cli.Command, error) {
+			return &RunnerProfileDeleteCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 
 		"context": func() (cli.Command, error) {
 			return &helpCommand{
@@ -386,6 +773,12 @@ func Commands(
 				baseCommand: baseCommand,
 			}, nil
 		},
+
+		"doctor": func() (cli.Command, error) {
+			return &DoctorCommand{
+				baseCommand: baseCommand,
+			}, nil
+		},
 	}
 
 	// register our aliases
@@ -593,6 +986,26 @@ Waypoint will search for artifacts to pass to the deployment phase.
 `,
 	},
 
+	"audit": {
+		"Audit log of changes to the server",
+		`
+Audit log of changes to the server.
+
+The server records every call that changes it, such as queueing jobs,
+setting config and creating tokens, as well as every call that was denied.
+`,
+	},
+
+	"exec-recording": {
+		"Recorded exec sessions",
+		`
+Recorded exec sessions.
+
+If the server is configured to record them, the input and output of every
+"waypoint exec" session is kept on the server until its retention ends.
+`,
+	},
+
 	"config": {
 		"Application configuration management",
 		`
@@ -614,7 +1027,19 @@ Manage configurations for accessing Waypoint servers.
 
 A context contains all the configuration to connect to a single Waypoint
 server. The Waypoint CLI can have multiple contexts to make it easy to switch
-between different Waypoint servers.
+between different Waypoint servers. The default context is used unless
+another is given with the -context flag or the WAYPOINT_CONTEXT env var.
+`,
+	},
+
+	"pipeline": {
+		"Pipeline execution",
+		`
+Run the pipelines of an app.
+
+A pipeline is a named sequence of steps declared in the app in the
+waypoint.hcl. Steps run operations such as builds and deploys, commands
+on runners, and approval gates.
 `,
 	},
 
@@ -642,6 +1067,38 @@ For more information see: https://waypointproject.io/docs/url
 `,
 	},
 
+	"notification": {
+		"Notifications of deployments, releases and failed jobs",
+		`
+Notifications of deployments, releases and failed jobs.
+
+The server sends notifications of the events of a project to Slack or
+any webhook, so teams learn about deployments without polling.
+`,
+	},
+
+	"organization": {
+		"Organizations of teams sharing a server",
+		`
+Organizations of teams sharing a server.
+
+Organizations let a single server serve multiple teams. The projects,
+users, tokens and hostnames of an organization can't be seen or used by
+the other organizations.
+`,
+	},
+
+	"job": {
+		"Job management",
+		`
+Job management.
+
+Operations such as builds and deploys are queued as jobs and run by
+runners. These commands show the jobs that are queued, running or
+complete, cancel them and show their output.
+`,
+	},
+
 	"runner": {
 		"Runner management",
 		`
@@ -650,6 +1107,23 @@ Runner management.
 Runners are used to execute remote operations for Waypoint. If you're new
 to Waypoint, you generally *do not need* runners and you can ignore this
 entire section.
+
+Connected runners and the runners that have been seen are listed with
+"waypoint runner list". Operators can adopt or reject runners to control
+whether they may be assigned jobs, and route jobs to runners with runner
+profiles.
+`,
+	},
+
+	"runner profile": {
+		"Runner profile management",
+		`
+Runner profile management.
+
+Runner profiles route jobs to the runners that are able to run them, such
+as runners with Docker or in a specific cloud or network zone. Jobs that
+a profile matches target the runners of the profile by ID or by the
+labels that runners registered with.
 `,
 	},
 
@@ -673,6 +1147,52 @@ Authenticate and invite collaborators.
 
 Tokens are the primary form of authentication to Waypoint. Everyone who
 accesses a Waypoint server requires a token.
+
+Tokens can be named, restricted, given a lifetime and revoked. Use
+"waypoint token list" to see the tokens of the server.
+`,
+	},
+
+	"trigger": {
+		"Run operations on a schedule",
+		`
+Run operations on a schedule.
+
+Triggers queue build, deploy or up jobs for an app on a cron schedule,
+such as a nightly rebuild of the main branch. The jobs run on remote
+runners.
+`,
+	},
+
+	"user": {
+		"User and permission management",
+		`
+User and permission management.
+
+Users are created when they log in with OIDC. Users have no access until
+they're granted permissions for projects and workspaces.
+`,
+	},
+
+	"project": {
+		"Project management",
+		`
+Project management.
+
+Projects are registered with the server by "waypoint init". These commands
+manage the settings of a project on the server, such as the data source of
+remote operations, including for projects without a local waypoint.hcl file.
+`,
+	},
+
+	"webhook": {
+		"Run operations when Git pushes are received",
+		`
+Run operations when Git pushes are received.
+
+Webhooks queue build, deploy or up jobs for the apps of a project when
+GitHub, GitLab or another system reports a push to a Git ref. The jobs run
+on remote runners.
 `,
 	},
 }
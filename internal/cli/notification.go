@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type NotificationCreateCommand struct {
+	*baseCommand
+
+	flagId      string
+	flagName    string
+	flagProject string
+	flagEvents  []string
+	flagSlack   string
+	flagWebhook string
+	flagSecret  string
+}
+
+func (c *NotificationCreateCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithConfig(true),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 0 || (c.flagSlack == "") == (c.flagWebhook == "") {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	project := c.refProject
+	if c.flagProject != "" {
+		project = &pb.Ref_Project{Project: c.flagProject}
+	}
+	if project == nil {
+		c.ui.Output("A project must be set with -project or a waypoint.hcl file.",
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	sink := &pb.NotificationSink{
+		Id:      c.flagId,
+		Name:    c.flagName,
+		Project: project,
+	}
+	for _, name := range c.flagEvents {
+		typ, ok := pb.Event_Type_value[strings.ToUpper(name)]
+		if !ok || typ == int32(pb.Event_UNKNOWN) {
+			c.ui.Output("Invalid event %q. Must be one of: %s.",
+				name, strings.Join(eventTypeNames(), ", "), terminal.WithErrorStyle())
+			return 1
+		}
+
+		sink.Events = append(sink.Events, pb.Event_Type(typ))
+	}
+
+	if c.flagSlack != "" {
+		sink.Sink = &pb.NotificationSink_Slack_{
+			Slack: &pb.NotificationSink_Slack{Url: c.flagSlack},
+		}
+	} else {
+		sink.Sink = &pb.NotificationSink_Webhook_{
+			Webhook: &pb.NotificationSink_Webhook{
+				Url:    c.flagWebhook,
+				Secret: c.flagSecret,
+			},
+		}
+	}
+
+	resp, err := c.project.Client().UpsertNotificationSink(c.Ctx, &pb.UpsertNotificationSinkRequest{
+		Sink: sink,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Notification sink %s saved.", resp.Sink.Id, terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *NotificationCreateCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "slack",
+			Target: &c.flagSlack,
+			Usage:  "URL of a Slack incoming webhook to post notifications to.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "webhook",
+			Target: &c.flagWebhook,
+			Usage: "URL to POST notifications to as JSON. Either this or -slack " +
+				"must be set.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "secret",
+			Target: &c.flagSecret,
+			Usage: "Secret to sign the requests to -webhook with. The signature " +
+				"is set in the X-Waypoint-Signature header.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "event",
+			Target: &c.flagEvents,
+			Usage: "Event to send notifications of. One of: " +
+				strings.Join(eventTypeNames(), ", ") + ". Can be specified multiple " +
+				"times. If this isn't set, notifications of jobs, deployments and " +
+				"releases are sent.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage:      "Project to send the notifications of. This defaults to the current project.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "name",
+			Target: &c.flagName,
+			Usage:  "Name of the sink to show in the notification list.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "id",
+			Target: &c.flagId,
+			Usage:  "ID of an existing sink to update instead of creating a new one.",
+		})
+	})
+}
+
+func (c *NotificationCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NotificationCreateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *NotificationCreateCommand) Synopsis() string {
+	return "Send notifications of the events of a project."
+}
+
+func (c *NotificationCreateCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint notification create [options]
+
+  Send notifications of the events of a project to Slack or a webhook.
+
+  Notifications are sent when deployments and releases complete or fail,
+  and when jobs fail. Set either -slack or -webhook.
+
+` + c.Flags().Help())
+}
+
+type NotificationListCommand struct {
+	*baseCommand
+
+	flagProject string
+	flagJson    bool
+}
+
+func (c *NotificationListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithConfig(true),
+	); err != nil {
+		return 1
+	}
+
+	// List the sinks of the project we're in unless one is given.
+	req := &pb.ListNotificationSinksRequest{}
+	if c.flagProject != "" {
+		req.Project = &pb.Ref_Project{Project: c.flagProject}
+	} else if c.refProject != nil {
+		req.Project = c.refProject
+	}
+
+	resp, err := c.project.Client().ListNotificationSinks(c.Ctx, req)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range resp.Sinks {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	table := terminal.NewTable("ID", "Name", "Project", "Sink", "Events", "Last Sent", "Error")
+	for _, sink := range resp.Sinks {
+		target := "webhook " + sink.GetWebhook().GetUrl()
+		if slack := sink.GetSlack(); slack != nil {
+			target = "slack"
+		}
+
+		events := "job, deployment, release"
+		if len(sink.Events) > 0 {
+			var names []string
+			for _, typ := range sink.Events {
+				names = append(names, strings.ToLower(typ.String()))
+			}
+			events = strings.Join(names, ", ")
+		}
+
+		var last string
+		if ts, err := ptypes.Timestamp(sink.LastTime); err == nil {
+			last = ts.Local().Format("2006-01-02 15:04")
+		}
+
+		var colors []string
+		if sink.LastError != "" {
+			colors = []string{"", "", "", "", "", "", terminal.Red}
+		}
+
+		table.Rich([]string{
+			sink.Id,
+			sink.Name,
+			sink.Project.GetProject(),
+			target,
+			events,
+			last,
+			sink.LastError,
+		}, colors)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *NotificationListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage:      "Only list the sinks of this project.",
+		})
+
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *NotificationListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NotificationListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *NotificationListCommand) Synopsis() string {
+	return "List the notification sinks and the result of the last notification."
+}
+
+func (c *NotificationListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint notification list [options]
+
+  List the notification sinks and the result of the last notification.
+  The sinks of the project in the current directory are listed unless
+  -project is set.
+
+` + c.Flags().Help())
+}
+
+type NotificationDeleteCommand struct {
+	*baseCommand
+}
+
+func (c *NotificationDeleteCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	_, err := c.project.Client().DeleteNotificationSink(c.Ctx, &pb.DeleteNotificationSinkRequest{
+		Id: c.args[0],
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Notification sink %s deleted.", c.args[0], terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *NotificationDeleteCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *NotificationDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NotificationDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *NotificationDeleteCommand) Synopsis() string {
+	return "Delete a notification sink."
+}
+
+func (c *NotificationDeleteCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint notification delete [options] ID
+
+  Delete a notification sink. No more notifications are sent to it.
+
+`)
+}
@@ -34,6 +34,17 @@ func WithSingleApp() Option {
 	}
 }
 
+// WithMultiApp is like WithSingleApp but if there are multiple apps and
+// none is targeted with `-app`, all apps are targeted.
+func WithMultiApp() Option {
+	return func(c *baseConfig) {
+		c.AppTargetRequired = true
+		c.AppTargetMulti = true
+		c.Config = false
+		c.Client = true
+	}
+}
+
 // WithNoConfig configures the CLI to not expect any project configuration.
 // This will not read any configuration files.
 func WithNoConfig() Option {
@@ -72,5 +83,6 @@ type baseConfig struct {
 	ConfigOptional    bool
 	Client            bool
 	AppTargetRequired bool
+	AppTargetMulti    bool
 	UI                terminal.UI
 }
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type OrganizationCreateCommand struct {
+	*baseCommand
+}
+
+func (c *OrganizationCreateCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	resp, err := c.project.Client().UpsertOrganization(c.Ctx, &pb.UpsertOrganizationRequest{
+		Organization: &pb.Organization{Name: c.args[0]},
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Organization %s created.", resp.Organization.Name, terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *OrganizationCreateCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *OrganizationCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OrganizationCreateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OrganizationCreateCommand) Synopsis() string {
+	return "Create an organization."
+}
+
+func (c *OrganizationCreateCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint organization create NAME
+
+  Create an organization.
+
+  Add users to the organization with "waypoint user grant -organization"
+  and create tokens for it with "waypoint token new -organization". The
+  projects they create are in the organization. This requires admin
+  permission for the whole server.
+
+`)
+}
+
+type OrganizationListCommand struct {
+	*baseCommand
+
+	flagJson bool
+}
+
+func (c *OrganizationListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().ListOrganizations(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range resp.Organizations {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	table := terminal.NewTable("Name", "Created")
+	for _, o := range resp.Organizations {
+		var created string
+		if t, err := ptypes.Timestamp(o.CreateTime); err == nil {
+			created = t.Local().Format("2006-01-02 15:04")
+		}
+
+		table.Rich([]string{o.Name, created}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *OrganizationListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *OrganizationListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OrganizationListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OrganizationListCommand) Synopsis() string {
+	return "List the organizations."
+}
+
+func (c *OrganizationListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint organization list [options]
+
+  List the organizations. Callers in an organization only see their own.
+
+` + c.Flags().Help())
+}
+
+type OrganizationDeleteCommand struct {
+	*baseCommand
+}
+
+func (c *OrganizationDeleteCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	_, err := c.project.Client().DeleteOrganization(c.Ctx, &pb.DeleteOrganizationRequest{
+		Name: c.args[0],
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Organization %s deleted.", c.args[0], terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *OrganizationDeleteCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *OrganizationDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OrganizationDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *OrganizationDeleteCommand) Synopsis() string {
+	return "Delete an organization."
+}
+
+func (c *OrganizationDeleteCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint organization delete NAME
+
+  Delete an organization. The organization must not have any projects.
+  This requires admin permission for the whole server.
+
+`)
+}
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	clientpkg "github.com/hashicorp/waypoint/internal/client"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type PipelineRunCommand struct {
+	*baseCommand
+
+	flagAutoApprove bool
+}
+
+func (c *PipelineRunCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+	name := c.args[0]
+
+	if c.cfg == nil {
+		c.ui.Output("A waypoint.hcl with the pipeline is required.", terminal.WithErrorStyle())
+		return 1
+	}
+
+	appCfg, ok := c.cfg.AppConfig(c.refApp.Application)
+	if !ok {
+		c.ui.Output("App %q isn't in the configuration.", c.refApp.Application,
+			terminal.WithErrorStyle())
+		return 1
+	}
+	pipeline, ok := appCfg.Pipeline(name)
+	if !ok {
+		c.ui.Output("App %q has no pipeline %q.", appCfg.Name, name, terminal.WithErrorStyle())
+		return 1
+	}
+
+	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
+		return c.run(ctx, app, pipeline)
+	})
+	if err != nil {
+		if err != ErrSentinel {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		}
+
+		return 1
+	}
+
+	return 0
+}
+
+// run runs the steps of the pipeline in order and stops at the first step
+// that fails or isn't approved.
+func (c *PipelineRunCommand) run(
+	ctx context.Context,
+	app *clientpkg.App,
+	pipeline *configpkg.Pipeline,
+) error {
+	// The results of earlier steps that later steps use. If they are nil,
+	// the latest ones are used.
+	var push *pb.PushedArtifact
+	var deployment *pb.Deployment
+
+	for i, step := range pipeline.Steps {
+		app.UI.Output("Step %d/%d: %s", i+1, len(pipeline.Steps), step.Name,
+			terminal.WithHeaderStyle())
+
+		var err error
+		switch {
+		case step.Approval != "":
+			if !c.approve(app, step) {
+				app.UI.Output("The pipeline was stopped at step %q.", step.Name,
+					terminal.WithErrorStyle())
+				return ErrSentinel
+			}
+
+		case len(step.Exec) > 0:
+			err = app.PipelineStep(ctx, &pb.Job_PipelineStepOp{
+				Pipeline: pipeline.Name,
+				Step:     step.Name,
+			})
+
+		case step.Operation == "build":
+			var result *pb.Job_BuildResult
+			result, err = app.Build(ctx, &pb.Job_BuildOp{})
+			if err == nil && len(result.Matrix) == 0 {
+				// With a build matrix, the deploy step looks up the
+				// artifact of the variant that it deploys.
+				push = result.Push
+			}
+
+		case step.Operation == "deploy":
+			if push == nil {
+				push, err = c.project.Client().GetLatestPushedArtifact(ctx,
+					&pb.GetLatestPushedArtifactRequest{
+						Application: app.Ref(),
+						Workspace:   c.project.WorkspaceRef(),
+						Labels:      c.artifactLabels(app.Ref().Application),
+					})
+				if err != nil {
+					break
+				}
+			}
+
+			var result *pb.Job_DeployResult
+			result, err = app.Deploy(ctx, &pb.Job_DeployOp{Artifact: push})
+			if err == nil {
+				deployment = result.Deployment
+			}
+
+		case step.Operation == "release":
+			if deployment == nil {
+				deployment, err = c.latestDeployment(ctx, app)
+				if err != nil {
+					break
+				}
+			}
+
+			var result *pb.Job_ReleaseResult
+			result, err = app.Release(ctx, &pb.Job_ReleaseOp{
+				Deployment: deployment,
+				Prune:      true,
+			})
+			if err == nil && result.Release.Url != "" {
+				app.UI.Output("Release URL: %s", result.Release.Url, terminal.WithSuccessStyle())
+			}
+		}
+		if err != nil {
+			app.UI.Output("Step %q failed: %s", step.Name, clierrors.Humanize(err),
+				terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+	}
+
+	app.UI.Output("\nPipeline %q completed.", pipeline.Name, terminal.WithSuccessStyle())
+	return nil
+}
+
+// latestDeployment returns the latest deployment of the app in the
+// workspace that hasn't been destroyed.
+func (c *PipelineRunCommand) latestDeployment(
+	ctx context.Context,
+	app *clientpkg.App,
+) (*pb.Deployment, error) {
+	resp, err := c.project.Client().ListDeployments(ctx, &pb.ListDeploymentsRequest{
+		Application:   app.Ref(),
+		Workspace:     c.project.WorkspaceRef(),
+		PhysicalState: pb.Operation_CREATED,
+		Order: &pb.OperationOrder{
+			Limit: 1,
+			Order: pb.OperationOrder_COMPLETE_TIME,
+			Desc:  true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Deployments) == 0 {
+		return nil, errors.New(strings.TrimSpace(releaseNoDeploys))
+	}
+
+	return resp.Deployments[0], nil
+}
+
+// approve asks the user to approve an approval step. This returns true if
+// the pipeline can continue.
+func (c *PipelineRunCommand) approve(app *clientpkg.App, step *configpkg.PipelineStep) bool {
+	if c.flagAutoApprove {
+		app.UI.Output("Approved with -auto-approve.", terminal.WithInfoStyle())
+		return true
+	}
+
+	if !c.ui.Interactive() {
+		app.UI.Output("Step %q requires approval, so -auto-approve must be set "+
+			"when not running interactively.", step.Name, terminal.WithErrorStyle())
+		return false
+	}
+
+	result, err := c.ui.Input(&terminal.Input{
+		Prompt: step.Approval + " Only 'yes' will be accepted to approve: ",
+		Style:  terminal.WarningBoldStyle,
+	})
+	if err != nil {
+		app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return false
+	}
+
+	return strings.ToLower(strings.TrimSpace(result)) == "yes"
+}
+
+func (c *PipelineRunCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOperation, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "auto-approve",
+			Target: &c.flagAutoApprove,
+			Usage:  "Approve every approval step without asking.",
+		})
+	})
+}
+
+func (c *PipelineRunCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *PipelineRunCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *PipelineRunCommand) Synopsis() string {
+	return "Run a pipeline of an app."
+}
+
+func (c *PipelineRunCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint pipeline run [options] NAME
+
+  Run the pipeline of an app with the given name. Pipelines are declared
+  with "pipeline" stanzas in the app in the waypoint.hcl.
+
+  The steps of the pipeline run in order and the pipeline stops at the
+  first step that fails. Build, deploy and release steps run the operation
+  as "waypoint build", "waypoint deploy" and "waypoint release" do. A deploy
+  step uses the artifact of an earlier build step and a release step the
+  deployment of an earlier deploy step, or the latest ones if the pipeline
+  doesn't have those steps. Exec steps run their command on a runner in the
+  directory of the app.
+
+  Approval steps ask to continue and stop the pipeline unless "yes" is
+  entered. When not running interactively, -auto-approve is required to
+  continue past them.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,488 @@
+package cli
+
+import (
+	stdflag "flag"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ProjectApplyCommand struct {
+	*baseCommand
+
+	flagProject      string
+	flagRemote       bool
+	flagDataSource   string
+	flagGitURL       string
+	flagGitRef       string
+	flagGitPath      string
+	flagPoll         bool
+	flagPollInterval string
+	flagPollOp       string
+	flagPollApps     []string
+	flagRegisterApps []string
+}
+
+func (c *ProjectApplyCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithConfig(true),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 0 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	ref := c.refProject
+	if c.flagProject != "" {
+		ref = &pb.Ref_Project{Project: c.flagProject}
+	}
+	if ref == nil {
+		c.ui.Output("A project must be set with -project or a waypoint.hcl file.",
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Only the settings of the flags that are set are changed.
+	set := map[string]bool{}
+	flags.Visit(func(f *stdflag.Flag) { set[f.Name] = true })
+
+	client := c.project.Client()
+	project := &pb.Project{Name: ref.Project}
+	resp, err := client.GetProject(c.Ctx, &pb.GetProjectRequest{Project: ref})
+	if err == nil {
+		project = resp.Project
+	} else if status.Code(err) != codes.NotFound {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if set["remote"] {
+		project.RemoteEnabled = c.flagRemote
+	}
+
+	if set["data-source"] {
+		switch c.flagDataSource {
+		case "git":
+			if _, ok := project.DataSource.GetSource().(*pb.Job_DataSource_Git); !ok {
+				project.DataSource = &pb.Job_DataSource{
+					Source: &pb.Job_DataSource_Git{Git: &pb.Job_Git{}},
+				}
+			}
+		case "local":
+			project.DataSource = &pb.Job_DataSource{
+				Source: &pb.Job_DataSource_Local{Local: &pb.Job_Local{}},
+			}
+		case "":
+			project.DataSource = nil
+		default:
+			c.ui.Output("Invalid data source %q. Must be one of: git, local.",
+				c.flagDataSource, terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	if set["git-url"] || set["git-ref"] || set["git-path"] {
+		git, ok := project.DataSource.GetSource().(*pb.Job_DataSource_Git)
+		if !ok {
+			c.ui.Output("The Git flags require a Git data source. Set -data-source=git.",
+				terminal.WithErrorStyle())
+			return 1
+		}
+
+		if set["git-url"] {
+			git.Git.Url = c.flagGitURL
+		}
+		if set["git-ref"] {
+			git.Git.Ref = c.flagGitRef
+		}
+		if set["git-path"] {
+			git.Git.Path = c.flagGitPath
+		}
+	}
+
+	if set["poll"] || set["poll-interval"] || set["poll-op"] || set["poll-app"] {
+		poll := project.DataSourcePoll
+		if poll == nil {
+			poll = &pb.Project_Poll{}
+			project.DataSourcePoll = poll
+		}
+
+		if set["poll"] {
+			poll.Enabled = c.flagPoll
+		}
+		if set["poll-interval"] {
+			poll.Interval = c.flagPollInterval
+		}
+		if set["poll-op"] {
+			op, ok := pb.Trigger_Operation_value[strings.ToUpper(c.flagPollOp)]
+			if !ok || op == int32(pb.Trigger_UNKNOWN) {
+				c.ui.Output("Invalid operation %q. Must be one of: build, deploy, up.",
+					c.flagPollOp, terminal.WithErrorStyle())
+				return 1
+			}
+
+			poll.Operation = pb.Trigger_Operation(op)
+		}
+		if set["poll-app"] {
+			poll.Applications = c.flagPollApps
+		}
+		if c.refWorkspace != nil {
+			poll.Workspace = c.refWorkspace
+		}
+	}
+
+	upsertResp, err := client.UpsertProject(c.Ctx, &pb.UpsertProjectRequest{Project: project})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Register the apps, since a project that is only deployed remotely
+	// may never have been initialized with a waypoint.hcl file.
+	for _, app := range c.flagRegisterApps {
+		_, err := client.UpsertApplication(c.Ctx, &pb.UpsertApplicationRequest{
+			Project: ref,
+			Name:    app,
+		})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	c.ui.Output("Project %q saved.", upsertResp.Project.Name, terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *ProjectApplyCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage:      "Project to change. This defaults to the current project.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "remote",
+			Target: &c.flagRemote,
+			Usage:  "Allow operations of the project to run on remote runners.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "data-source",
+			Target: &c.flagDataSource,
+			Usage: "Where remote runners get the project from. One of: git, local. " +
+				"Set this to an empty value to remove the data source.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "git-url",
+			Target: &c.flagGitURL,
+			Usage:  "URL of the Git repository of the project.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "git-ref",
+			Target: &c.flagGitRef,
+			Usage:  "Git branch, tag or commit to check out. This defaults to the default branch.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "git-path",
+			Target: &c.flagGitPath,
+			Usage:  "Subdirectory of the repository with the waypoint.hcl file.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "poll",
+			Target: &c.flagPoll,
+			Usage: "Poll the Git repository and run an operation for the apps of " +
+				"the project when the ref changes.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "poll-interval",
+			Target: &c.flagPollInterval,
+			Usage:  "How often the repository is polled, such as \"5m\". This defaults to \"30s\".",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "poll-op",
+			Target: &c.flagPollOp,
+			Usage:  "Operation to run when the ref changes. One of: build, deploy, up. This defaults to up.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "poll-app",
+			Target: &c.flagPollApps,
+			Usage: "App to run the operation for when the ref changes. Can be " +
+				"specified multiple times. This defaults to every app of the project.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "register-app",
+			Target: &c.flagRegisterApps,
+			Usage:  "Register an app with the project. Can be specified multiple times.",
+		})
+	})
+}
+
+func (c *ProjectApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ProjectApplyCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ProjectApplyCommand) Synopsis() string {
+	return "Create or change the settings of a project."
+}
+
+func (c *ProjectApplyCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint project apply [options]
+
+  Create or change the settings of a project on the server.
+
+  This configures remote operations without a local waypoint.hcl file,
+  such as for a project that is only deployed from Git. Only the settings
+  of the flags that are given are changed. "waypoint init" sets the same
+  settings from the runner block of the waypoint.hcl file when it has one.
+
+  With -poll, the server checks the Git ref of the project every
+  -poll-interval and runs the operation on a remote runner for the new
+  commit when the ref changes. The jobs run in the workspace of -workspace.
+
+` + c.Flags().Help())
+}
+
+type ProjectInspectCommand struct {
+	*baseCommand
+
+	flagProject string
+	flagJson    bool
+}
+
+func (c *ProjectInspectCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithConfig(true),
+	); err != nil {
+		return 1
+	}
+
+	ref := c.refProject
+	if c.flagProject != "" {
+		ref = &pb.Ref_Project{Project: c.flagProject}
+	}
+	if ref == nil {
+		c.ui.Output("A project must be set with -project or a waypoint.hcl file.",
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	resp, err := c.project.Client().GetProject(c.Ctx, &pb.GetProjectRequest{Project: ref})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	project := resp.Project
+	if c.flagJson {
+		if err := outputJson(project); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	var apps []string
+	for _, app := range project.Applications {
+		apps = append(apps, app.Name)
+	}
+
+	values := []terminal.NamedValue{
+		{Name: "Project", Value: project.Name},
+		{Name: "Applications", Value: strings.Join(apps, ", ")},
+		{Name: "Remote Enabled", Value: project.RemoteEnabled},
+	}
+	if project.Organization != "" {
+		values = append(values, terminal.NamedValue{Name: "Organization", Value: project.Organization})
+	}
+
+	switch src := project.DataSource.GetSource().(type) {
+	case *pb.Job_DataSource_Git:
+		values = append(values,
+			terminal.NamedValue{Name: "Data Source", Value: "git"},
+			terminal.NamedValue{Name: "Git URL", Value: src.Git.Url},
+			terminal.NamedValue{Name: "Git Ref", Value: src.Git.Ref},
+			terminal.NamedValue{Name: "Git Path", Value: src.Git.Path},
+		)
+	case *pb.Job_DataSource_Local:
+		values = append(values, terminal.NamedValue{Name: "Data Source", Value: "local"})
+	default:
+		values = append(values, terminal.NamedValue{Name: "Data Source", Value: "(none)"})
+	}
+
+	if poll := project.DataSourcePoll; poll != nil {
+		values = append(values,
+			terminal.NamedValue{Name: "Poll Enabled", Value: poll.Enabled},
+			terminal.NamedValue{Name: "Poll Interval", Value: poll.Interval},
+			terminal.NamedValue{Name: "Poll Operation", Value: strings.ToLower(poll.Operation.String())},
+			terminal.NamedValue{Name: "Poll Workspace", Value: poll.Workspace.GetWorkspace()},
+		)
+		if ts, err := ptypes.Timestamp(poll.LastTime); err == nil {
+			values = append(values,
+				terminal.NamedValue{Name: "Last Poll", Value: ts.Local().Format("2006-01-02 15:04:05")},
+				terminal.NamedValue{Name: "Last Commit", Value: poll.LastCommit},
+				terminal.NamedValue{Name: "Last Jobs", Value: strings.Join(poll.LastJobIds, ", ")},
+			)
+		}
+		if poll.LastError != "" {
+			values = append(values, terminal.NamedValue{Name: "Last Error", Value: poll.LastError})
+		}
+	}
+
+	c.ui.NamedValues(values, terminal.WithInfoStyle())
+	return 0
+}
+
+func (c *ProjectInspectCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage:      "Project to show. This defaults to the current project.",
+		})
+
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *ProjectInspectCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ProjectInspectCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ProjectInspectCommand) Synopsis() string {
+	return "Show the settings of a project."
+}
+
+func (c *ProjectInspectCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint project inspect [options]
+
+  Show the settings of a project on the server, including the data source
+  of remote operations and the result of the last poll of the data source.
+
+` + c.Flags().Help())
+}
+
+type ProjectListCommand struct {
+	*baseCommand
+
+	flagJson bool
+}
+
+func (c *ProjectListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().ListProjects(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	sort.Slice(resp.Projects, func(i, j int) bool {
+		return resp.Projects[i].Project < resp.Projects[j].Project
+	})
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, p := range resp.Projects {
+			msgs = append(msgs, p)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(resp.Projects) == 0 {
+		c.ui.Output("No projects found.")
+		return 0
+	}
+
+	table := terminal.NewTable("Name")
+	for _, p := range resp.Projects {
+		table.Rich([]string{p.Project}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *ProjectListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *ProjectListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ProjectListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ProjectListCommand) Synopsis() string {
+	return "List the projects on the server."
+}
+
+func (c *ProjectListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint project list [options]
+
+  List the projects on the server.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	clientpkg "github.com/hashicorp/waypoint/internal/client"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ReleaseDemoteCommand struct {
+	*baseCommand
+}
+
+func (c *ReleaseDemoteCommand) Run(args []string) int {
+	defer c.Close()
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+	if len(flags.Args()) > 0 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	client := c.project.Client()
+	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
+		resp, err := client.ListReleases(ctx, &pb.ListReleasesRequest{
+			Application: app.Ref(),
+			Workspace:   c.project.WorkspaceRef(),
+			Order: &pb.OperationOrder{
+				Order: pb.OperationOrder_COMPLETE_TIME,
+				Desc:  true,
+			},
+		})
+		if err != nil {
+			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		// Only successful releases were ever live
+		var releases []*pb.Release
+		for _, r := range resp.Releases {
+			if r.Status.GetState() == pb.Status_SUCCESS {
+				releases = append(releases, r)
+			}
+		}
+		if len(releases) == 0 || releases[0].Labels[labelPromotedFrom] == "" {
+			app.UI.Output(strings.TrimSpace(releaseNotPromoted),
+				c.project.WorkspaceRef().Workspace, terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+		current := releases[0]
+
+		// Find the deployment that was released before the promotion and
+		// still exists.
+		var deploy *pb.Deployment
+		for _, r := range releases[1:] {
+			if r.DeploymentId == current.DeploymentId {
+				continue
+			}
+
+			d, err := client.GetDeployment(ctx, &pb.GetDeploymentRequest{
+				Ref: &pb.Ref_Operation{
+					Target: &pb.Ref_Operation_Id{Id: r.DeploymentId},
+				},
+			})
+			if err != nil {
+				app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+				return ErrSentinel
+			}
+			if d.State == pb.Operation_CREATED {
+				deploy = d
+				break
+			}
+		}
+		if deploy == nil {
+			app.UI.Output(strings.TrimSpace(releaseNoPrevious),
+				c.project.WorkspaceRef().Workspace, terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		app.UI.Output("Demoting deployment %s and releasing deployment %s...",
+			current.DeploymentId, deploy.Id, terminal.WithHeaderStyle())
+		result, err := app.Release(ctx, &pb.Job_ReleaseOp{
+			Deployment: deploy,
+		})
+		if err != nil {
+			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		if result.Release.Url == "" {
+			app.UI.Output("\n"+strings.TrimSpace(releaseNoUrl),
+				deploy.Id,
+				terminal.WithSuccessStyle())
+			return nil
+		}
+
+		app.UI.Output("\nRelease URL: %s", result.Release.Url, terminal.WithSuccessStyle())
+		return nil
+	})
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+func (c *ReleaseDemoteCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOperation, nil)
+}
+
+func (c *ReleaseDemoteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ReleaseDemoteCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ReleaseDemoteCommand) Synopsis() string {
+	return "Undo the latest promotion to a workspace."
+}
+
+func (c *ReleaseDemoteCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint release demote [options]
+
+  Undo the latest promotion to the workspace by releasing the deployment
+  that was released before it.
+
+  The current release of the workspace must be from "waypoint release
+  promote". The promoted deployment isn't destroyed, so it can be released
+  again or destroyed with "waypoint deployment destroy".
+
+` + c.Flags().Help())
+}
+
+const (
+	releaseNotPromoted = `
+The current release of the workspace %q wasn't promoted, so there is
+nothing to demote. Use "waypoint release" to release another deployment.
+`
+
+	releaseNoPrevious = `
+No deployment that was released before the promotion to the workspace %q
+still exists, so the promotion can't be undone.
+`
+)
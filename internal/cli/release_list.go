@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	clientpkg "github.com/hashicorp/waypoint/internal/client"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ReleaseListCommand struct {
+	*baseCommand
+
+	flagWorkspaceAll bool
+	flagJson         bool
+	flagId           idFormat
+}
+
+func (c *ReleaseListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+
+	// Get our API client
+	client := c.project.Client()
+
+	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
+		var wsRef *pb.Ref_Workspace
+		if !c.flagWorkspaceAll {
+			wsRef = c.project.WorkspaceRef()
+		}
+
+		// List releases
+		resp, err := client.ListReleases(ctx, &pb.ListReleasesRequest{
+			Application: app.Ref(),
+			Workspace:   wsRef,
+			Order: &pb.OperationOrder{
+				Order: pb.OperationOrder_START_TIME,
+				Desc:  true,
+			},
+			LoadDetails: pb.Release_DEPLOYMENT,
+		})
+		if err != nil {
+			c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		if c.flagJson {
+			var msgs []proto.Message
+			for _, r := range resp.Releases {
+				msgs = append(msgs, r)
+			}
+
+			return outputJsonList(msgs)
+		}
+
+		const bullet = "●"
+
+		table := terminal.NewTable("", "ID", "Workspace", "Deployment", "Releaser", "URL", "Started", "Completed")
+		for _, r := range resp.Releases {
+			// Determine our bullet
+			status := ""
+			statusColor := ""
+			switch r.Status.State {
+			case pb.Status_RUNNING:
+				status = bullet
+				statusColor = terminal.Yellow
+
+			case pb.Status_SUCCESS:
+				status = "✔"
+				statusColor = terminal.Green
+
+			case pb.Status_ERROR:
+				status = "✖"
+				statusColor = terminal.Red
+			}
+
+			// Parse our times
+			var startTime, completeTime string
+			if t, err := ptypes.Timestamp(r.Status.StartTime); err == nil {
+				startTime = humanize.Time(t)
+			}
+			if t, err := ptypes.Timestamp(r.Status.CompleteTime); err == nil {
+				completeTime = humanize.Time(t)
+			}
+
+			deployment := r.DeploymentId
+			if d := r.Preload.GetDeployment(); d != nil {
+				deployment = c.flagId.FormatId(d.Sequence, d.Id)
+			}
+
+			table.Rich([]string{
+				status,
+				c.flagId.FormatId(r.Sequence, r.Id),
+				r.Workspace.Workspace,
+				deployment,
+				r.Component.GetName(),
+				r.Url,
+				startTime,
+				completeTime,
+			}, []string{
+				statusColor,
+			})
+		}
+
+		c.ui.Table(table)
+
+		return nil
+	})
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+func (c *ReleaseListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "workspace-all",
+			Target: &c.flagWorkspaceAll,
+			Usage:  "List releases in all workspaces for this project and application.",
+		})
+
+		initJsonFlag(f, &c.flagJson)
+		initIdFormat(f, &c.flagId)
+	})
+}
+
+func (c *ReleaseListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ReleaseListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ReleaseListCommand) Synopsis() string {
+	return "List releases."
+}
+
+func (c *ReleaseListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint release list [options]
+
+  List the releases of an app, latest first.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/posener/complete"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	clientpkg "github.com/hashicorp/waypoint/internal/client"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	// labelPromotedFrom is the label set on deployments and releases that
+	// were promoted with the ID of the deployment they were promoted from.
+	labelPromotedFrom = "waypoint/promoted-from"
+
+	// labelPromotedFromWorkspace is the label set with the workspace of the
+	// deployment they were promoted from.
+	labelPromotedFromWorkspace = "waypoint/promoted-from-workspace"
+)
+
+type ReleasePromoteCommand struct {
+	*baseCommand
+
+	flagTo      string
+	flagRelease bool
+}
+
+func (c *ReleasePromoteCommand) Run(args []string) int {
+	defer c.Close()
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) > 1 || c.flagTo == "" {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+	if c.flagTo == c.project.WorkspaceRef().Workspace {
+		c.ui.Output("The workspace to promote to must be different from the current "+
+			"workspace %q.", c.flagTo, terminal.WithErrorStyle())
+		return 1
+	}
+
+	client := c.project.Client()
+	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
+		deploy, err := c.deployment(ctx, app, args)
+		if err != nil {
+			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+		if deploy.State != pb.Operation_CREATED {
+			app.UI.Output("Deployment specified is not available (state=%s)", deploy.State,
+				terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		push, err := client.GetPushedArtifact(ctx, &pb.GetPushedArtifactRequest{
+			Ref: &pb.Ref_Operation{
+				Target: &pb.Ref_Operation_Id{Id: deploy.ArtifactId},
+			},
+		})
+		if err != nil {
+			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		// Carry the labels of the deployment forward, such as the git
+		// commit, and record where it was promoted from.
+		labels := map[string]string{}
+		for k, v := range deploy.Labels {
+			labels[k] = v
+		}
+		labels[labelPromotedFrom] = deploy.Id
+		labels[labelPromotedFromWorkspace] = deploy.Workspace.Workspace
+
+		ws := &pb.Ref_Workspace{Workspace: c.flagTo}
+		app.UI.Output("Promoting deployment %s from workspace %q to %q...",
+			deploy.Id, deploy.Workspace.Workspace, c.flagTo, terminal.WithHeaderStyle())
+		result, err := app.DeployWorkspace(ctx, &pb.Job_DeployOp{
+			Artifact: push,
+		}, ws, labels)
+		if err != nil {
+			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		if !c.flagRelease {
+			app.UI.Output("\nDeployment %s created in workspace %q.",
+				result.Deployment.Id, c.flagTo, terminal.WithSuccessStyle())
+			return nil
+		}
+
+		// Previous deployments aren't pruned so that the promotion can be
+		// undone with "release demote".
+		app.UI.Output("Releasing...", terminal.WithHeaderStyle())
+		releaseResult, err := app.ReleaseWorkspace(ctx, &pb.Job_ReleaseOp{
+			Deployment: result.Deployment,
+		}, ws, labels)
+		if err != nil {
+			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+
+		if releaseResult.Release.Url == "" {
+			app.UI.Output("\n"+strings.TrimSpace(releaseNoUrl),
+				result.Deployment.Id,
+				terminal.WithSuccessStyle())
+			return nil
+		}
+
+		app.UI.Output("\nRelease URL: %s", releaseResult.Release.Url, terminal.WithSuccessStyle())
+		return nil
+	})
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+// deployment returns the deployment to promote. This is the deployment given
+// by ID or sequence number, or the released deployment of the workspace.
+func (c *ReleasePromoteCommand) deployment(
+	ctx context.Context,
+	app *clientpkg.App,
+	args []string,
+) (*pb.Deployment, error) {
+	client := c.project.Client()
+
+	if len(args) == 0 {
+		release, err := client.GetLatestRelease(ctx, &pb.GetLatestReleaseRequest{
+			Application: app.Ref(),
+			Workspace:   c.project.WorkspaceRef(),
+		})
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.NotFound,
+				"No release was found in workspace %q. Specify the deployment to promote.",
+				c.project.WorkspaceRef().Workspace)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return client.GetDeployment(ctx, &pb.GetDeploymentRequest{
+			Ref: &pb.Ref_Operation{
+				Target: &pb.Ref_Operation_Id{Id: release.DeploymentId},
+			},
+		})
+	}
+
+	ref := &pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: args[0]},
+	}
+	if v, err := strconv.ParseUint(args[0], 10, 64); err == nil {
+		ref.Target = &pb.Ref_Operation_Sequence{
+			Sequence: &pb.Ref_OperationSeq{
+				Application: app.Ref(),
+				Number:      v,
+			},
+		}
+	}
+
+	return client.GetDeployment(ctx, &pb.GetDeploymentRequest{Ref: ref})
+}
+
+func (c *ReleasePromoteCommand) Flags() *flag.Sets {
+	return c.flagSet(flagSetOperation, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "to",
+			Target:     &c.flagTo,
+			Completion: c.predictWorkspaces(),
+			Usage:      "Workspace to promote the deployment to. This is required.",
+		})
+		f.BoolVar(&flag.BoolVar{
+			Name:    "release",
+			Target:  &c.flagRelease,
+			Default: true,
+			Usage:   "Release the new deployment after deploying it.",
+		})
+	})
+}
+
+func (c *ReleasePromoteCommand) AutocompleteArgs() complete.Predictor {
+	return c.predictDeployments()
+}
+
+func (c *ReleasePromoteCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ReleasePromoteCommand) Synopsis() string {
+	return "Deploy and release a deployment in another workspace."
+}
+
+func (c *ReleasePromoteCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint release promote [options] -to=WORKSPACE [id]
+
+  Deploy the artifact of a deployment to another workspace and release it,
+  without building again.
+
+  This defaults to the released deployment of the current workspace. Other
+  deployments can be specified by sequence number or long ID. The labels of
+  the deployment are carried forward, and the new deployment and release
+  are labeled with the deployment and workspace they were promoted from.
+
+  Deployments in the target workspace aren't pruned, so the promotion can
+  be undone with "waypoint release demote".
+
+` + c.Flags().Help())
+}
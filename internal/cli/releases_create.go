@@ -164,10 +164,11 @@ func (c *ReleaseCreateCommand) Flags() *flag.Sets {
 			Default: false,
 		})
 		f.StringVar(&flag.StringVar{
-			Name:    "deployment",
-			Aliases: []string{"d"},
-			Target:  &c.flagDeployment,
-			Usage:   "Release the specified deployment.",
+			Name:       "deployment",
+			Aliases:    []string{"d"},
+			Target:     &c.flagDeployment,
+			Completion: c.predictDeployments(),
+			Usage:      "Release the specified deployment.",
 		})
 	})
 }
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// RunnerAdoptCommand adopts runners, or rejects them if reject is set.
+type RunnerAdoptCommand struct {
+	*baseCommand
+
+	reject bool
+}
+
+func (c *RunnerAdoptCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) == 0 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	client := c.project.Client()
+	for _, id := range args {
+		_, err := client.AdoptRunner(c.Ctx, &pb.AdoptRunnerRequest{
+			RunnerId: id,
+			Adopt:    !c.reject,
+		})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		if c.reject {
+			c.ui.Output("Runner %q rejected.", id, terminal.WithSuccessStyle())
+		} else {
+			c.ui.Output("Runner %q adopted.", id, terminal.WithSuccessStyle())
+		}
+	}
+
+	return 0
+}
+
+func (c *RunnerAdoptCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *RunnerAdoptCommand) AutocompleteArgs() complete.Predictor {
+	return c.predictRunners()
+}
+
+func (c *RunnerAdoptCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RunnerAdoptCommand) Synopsis() string {
+	if c.reject {
+		return "Reject runners so they are never assigned jobs."
+	}
+
+	return "Adopt runners so they may be assigned jobs."
+}
+
+func (c *RunnerAdoptCommand) Help() string {
+	if c.reject {
+		return formatHelp(`
+Usage: waypoint runner reject [options] ID...
+
+  Reject one or more runners. Rejected runners are never assigned jobs,
+  even if they reconnect, until they're adopted.
+
+` + c.Flags().Help())
+	}
+
+	return formatHelp(`
+Usage: waypoint runner adopt [options] ID...
+
+  Adopt one or more runners so that they may be assigned jobs.
+
+  Runners that are pending adoption are assigned jobs unless the server
+  requires adoption with "waypoint server config-set -require-runner-adoption".
+  Runners keep their adoption state when they reconnect with the same ID.
+
+` + c.Flags().Help())
+}
@@ -1,20 +1,32 @@
 package cli
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
+	"strconv"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/posener/complete"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
 	runnerpkg "github.com/hashicorp/waypoint/internal/runner"
+	"github.com/hashicorp/waypoint/internal/server"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/hashicorp/waypoint/internal/serverclient"
 )
 
 type RunnerAgentCommand struct {
 	*baseCommand
+
+	flagMaxJobs int
+	flagId      string
+	flagLabels  map[string]string
 }
 
 func (c *RunnerAgentCommand) Run(args []string) int {
@@ -30,13 +42,13 @@ func (c *RunnerAgentCommand) Run(args []string) int {
 	); err != nil {
 		return 1
 	}
+	if c.flagMaxJobs < 1 {
+		c.flagMaxJobs = 1
+	}
 
 	// Connect to the server
 	log.Info("sourcing credentials and connecting to the Waypoint server")
-	conn, err := serverclient.Connect(ctx,
-		serverclient.FromContext(c.contextStorage, ""),
-		serverclient.FromEnv(),
-	)
+	conn, err := c.connect(ctx, "")
 	if err != nil {
 		c.ui.Output(
 			"Error connecting to the Waypoint server: %s", err.Error(),
@@ -44,12 +56,12 @@ func (c *RunnerAgentCommand) Run(args []string) int {
 		)
 		return 1
 	}
-	client := pb.NewWaypointClient(conn)
 
 	// Output information to the user
 	c.ui.Output("Runner configuration:", terminal.WithHeaderStyle())
 	c.ui.NamedValues([]terminal.NamedValue{
 		{Name: "Server address", Value: conn.Target()},
+		{Name: "Max concurrent jobs", Value: strconv.Itoa(c.flagMaxJobs)},
 	})
 	c.ui.Output("Runner logs:", terminal.WithHeaderStyle())
 	c.ui.Output("")
@@ -80,55 +92,179 @@ func (c *RunnerAgentCommand) Run(args []string) int {
 		}
 	}
 
+	// Run the runner until we quit. Servers that share their database
+	// with other servers only assign jobs while they are the leader, and
+	// the others send us to the leader. If the server we were sent to goes
+	// away, we connect to the configured address again.
+	addr := ""
+	for {
+		reconnect, next, err := c.runAgent(ctx, log, conn, addr != "")
+		conn.Close()
+		if err != nil {
+			log.Error("error running the runner", "err", err)
+			return 1
+		}
+		if !reconnect {
+			return 0
+		}
+
+		for {
+			addr = next
+			log.Info("reconnecting to the Waypoint server", "addr", addr)
+			conn, err = c.connect(ctx, addr)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return 0
+			}
+
+			log.Error("error connecting to the Waypoint server", "err", err)
+			select {
+			case <-time.After(2 * time.Second):
+			case <-ctx.Done():
+				return 0
+			}
+
+			next = ""
+		}
+	}
+}
+
+// connect connects to the configured server, or to addr if it isn't empty
+// with the rest of the configured settings.
+func (c *RunnerAgentCommand) connect(ctx context.Context, addr string) (*grpc.ClientConn, error) {
+	opts := []serverclient.ConnectOption{
+		serverclient.FromContext(c.contextStorage, ""),
+		serverclient.FromEnv(),
+	}
+	if addr != "" {
+		opts = append(opts, serverclient.Addr(addr))
+	}
+
+	return serverclient.Connect(ctx, opts...)
+}
+
+// runAgent registers a runner with conn and runs jobs until ctx is done or
+// the runner must reconnect. If it must reconnect, this returns true and
+// the address to connect to, which is empty for the configured address.
+// redirected is true if conn is to a server we were sent to.
+func (c *RunnerAgentCommand) runAgent(
+	ctx context.Context,
+	log hclog.Logger,
+	conn *grpc.ClientConn,
+	redirected bool,
+) (bool, string, error) {
 	// Create our runner
 	log.Info("initializing the runner")
-	runner, err := runnerpkg.New(
-		runnerpkg.WithClient(client),
+	opts := []runnerpkg.Option{
+		runnerpkg.WithClient(pb.NewWaypointClient(conn)),
 		runnerpkg.WithLogger(log.Named("runner")),
-	)
+		runnerpkg.WithLabels(c.flagLabels),
+	}
+	if c.flagId != "" {
+		opts = append(opts, runnerpkg.WithId(c.flagId))
+	}
+	runner, err := runnerpkg.New(opts...)
 	if err != nil {
-		c.ui.Output(
-			"Error initializing the runner: %s", err.Error(),
-			terminal.WithErrorStyle(),
-		)
-		return 1
+		return false, "", fmt.Errorf("error initializing the runner: %s", err)
 	}
 
 	// Start the runner
 	log.Info("starting runner")
 	if err := runner.Start(); err != nil {
-		log.Error("error starting runner", "err", err)
-		return 1
+		if redirected && status.Code(err) == codes.Unavailable {
+			log.Warn("error starting runner with the leader", "err", err)
+			return true, "", nil
+		}
+
+		return false, "", fmt.Errorf("error starting runner: %s", err)
 	}
 
-	// Accept jobs in goroutine so that we can interrupt it.
-	go func() {
-		for {
-			if err := runner.Accept(ctx); err != nil {
-				if err == runnerpkg.ErrClosed {
-					return
-				}
+	// Accept jobs in goroutines so that we can interrupt them. Each
+	// goroutine runs one job at a time.
+	reconnectCh := make(chan string, 1)
+	for i := 0; i < c.flagMaxJobs; i++ {
+		go func() {
+			for {
+				if err := runner.Accept(ctx); err != nil {
+					if err == runnerpkg.ErrClosed {
+						return
+					}
+
+					log.Error("error running job", "err", err)
 
-				log.Error("error running job", "err", err)
+					// Reconnect to the leader if we are sent to it, or
+					// to the configured address if the server we were
+					// sent to is unavailable.
+					leader := server.LeaderAddr(err)
+					if leader != "" || (redirected && status.Code(err) == codes.Unavailable) {
+						select {
+						case reconnectCh <- leader:
+						default:
+						}
+
+						return
+					}
+
+					// The server may not be the leader, or may be restarting.
+					// Wait before reconnecting rather than retrying in a loop.
+					if status.Code(err) == codes.Unavailable {
+						select {
+						case <-time.After(2 * time.Second):
+						case <-ctx.Done():
+						}
+					}
+				}
 			}
-		}
-	}()
+		}()
+	}
 
 	// Wait for end
-	<-ctx.Done()
+	reconnect := false
+	var addr string
+	select {
+	case <-ctx.Done():
+		log.Info("quit request received, gracefully stopping runner")
+
+	case addr = <-reconnectCh:
+		reconnect = true
+		log.Info("stopping runner to reconnect")
+	}
 
-	// Gracefully close
-	log.Info("quit request received, gracefully stopping runner")
+	// Gracefully close. Running jobs are completed first.
 	if err := runner.Close(); err != nil {
+		if !reconnect {
+			return false, "", fmt.Errorf("error stopping runner: %s", err)
+		}
+
 		log.Error("error stopping runner", "err", err)
-		return 1
 	}
 
-	return 0
+	return reconnect, addr, nil
 }
 
 func (c *RunnerAgentCommand) Flags() *flag.Sets {
-	return c.flagSet(0, nil)
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.IntVar(&flag.IntVar{
+			Name:    "max-jobs",
+			Target:  &c.flagMaxJobs,
+			Default: 1,
+			Usage:   "Maximum number of jobs this runner executes at the same time.",
+		})
+		f.StringVar(&flag.StringVar{
+			Name:   "id",
+			Target: &c.flagId,
+			Usage: "ID of the runner. This defaults to a new ID each time the runner starts.\n" +
+				"Set this so that the runner keeps its adoption state when it restarts.",
+		})
+		f.StringMapVar(&flag.StringMapVar{
+			Name:   "label",
+			Target: &c.flagLabels,
+			Usage:  "Labels to register the runner with. Can be specified multiple times.",
+		})
+	})
 }
 
 func (c *RunnerAgentCommand) AutocompleteArgs() complete.Predictor {
@@ -149,5 +285,8 @@ Usage: waypoint runner agent [options]
 
   Run a runner for executing remote operations.
 
+  The runner executes one job at a time unless -max-jobs is set. Jobs are
+  assigned by the server in order of priority and then queue time.
+
 ` + c.Flags().Help())
 }
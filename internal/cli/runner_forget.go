@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type RunnerForgetCommand struct {
+	*baseCommand
+}
+
+func (c *RunnerForgetCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) == 0 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	client := c.project.Client()
+	for _, id := range args {
+		_, err := client.ForgetRunner(c.Ctx, &pb.ForgetRunnerRequest{RunnerId: id})
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		c.ui.Output("Runner %q forgotten.", id, terminal.WithSuccessStyle())
+	}
+
+	return 0
+}
+
+func (c *RunnerForgetCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *RunnerForgetCommand) AutocompleteArgs() complete.Predictor {
+	return c.predictRunners()
+}
+
+func (c *RunnerForgetCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RunnerForgetCommand) Synopsis() string {
+	return "Forget runners that are no longer connected."
+}
+
+func (c *RunnerForgetCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint runner forget [options] ID...
+
+  Forget one or more runners that are no longer connected. Their adoption
+  state is deleted, so if they connect again they're pending adoption.
+
+  Connected runners can't be forgotten.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type RunnerInspectCommand struct {
+	*baseCommand
+
+	flagJson bool
+}
+
+func (c *RunnerInspectCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	r, err := c.project.Client().GetRunner(c.Ctx, &pb.GetRunnerRequest{RunnerId: args[0]})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	if c.flagJson {
+		if err := outputJson(r); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	values := []terminal.NamedValue{
+		{Name: "ID", Value: r.Id},
+		{Name: "Status", Value: runnerStatus(r)},
+		{Name: "Adoption", Value: runnerAdoption(r)},
+	}
+	if ts, err := ptypes.Timestamp(r.FirstSeen); err == nil {
+		values = append(values, terminal.NamedValue{
+			Name: "First Seen", Value: ts.Local().Format("2006-01-02 15:04:05")})
+	}
+	if ts, err := ptypes.Timestamp(r.LastSeen); err == nil {
+		values = append(values, terminal.NamedValue{
+			Name: "Last Seen", Value: ts.Local().Format("2006-01-02 15:04:05")})
+	}
+
+	c.ui.NamedValues(values, terminal.WithInfoStyle())
+
+	if len(r.Labels) > 0 {
+		keys := make([]string, 0, len(r.Labels))
+		for k := range r.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		labels := make([]terminal.NamedValue, 0, len(keys))
+		for _, k := range keys {
+			labels = append(labels, terminal.NamedValue{Name: k, Value: r.Labels[k]})
+		}
+
+		c.ui.Output("Labels", terminal.WithHeaderStyle())
+		c.ui.NamedValues(labels, terminal.WithInfoStyle())
+	}
+
+	if len(r.Components) > 0 {
+		table := terminal.NewTable("Type", "Plugin")
+		for _, comp := range r.Components {
+			table.Rich([]string{comp.Type.String(), comp.Name}, nil)
+		}
+
+		c.ui.Output("Plugins", terminal.WithHeaderStyle())
+		c.ui.Table(table)
+	}
+
+	return 0
+}
+
+func (c *RunnerInspectCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *RunnerInspectCommand) AutocompleteArgs() complete.Predictor {
+	return c.predictRunners()
+}
+
+func (c *RunnerInspectCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RunnerInspectCommand) Synopsis() string {
+	return "Show the details of a runner."
+}
+
+func (c *RunnerInspectCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint runner inspect [options] ID
+
+  Show the details of a runner, including its labels and the plugins
+  it has.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	"github.com/hashicorp/waypoint/internal/serverclient"
+	"github.com/hashicorp/waypoint/internal/serverinstall"
+)
+
+type RunnerInstallCommand struct {
+	*baseCommand
+
+	platform string
+}
+
+func (c *RunnerInstallCommand) Run(args []string) int {
+	defer c.Close()
+	ctx := c.Ctx
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+		WithClient(false),
+	); err != nil {
+		return 1
+	}
+
+	// The runner connects to the same server as the CLI, so we source the
+	// connection information the same way the runner agent would.
+	cfg, err := serverclient.ContextConfig(
+		serverclient.FromContext(c.contextStorage, ""),
+		serverclient.FromEnv(),
+	)
+	if err != nil {
+		c.ui.Output(
+			"Error loading the server connection information: %s", clierrors.Humanize(err),
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+	if cfg.Server.Address == "" {
+		c.ui.Output(
+			"No Waypoint server is configured. Connect the CLI to a server with\n"+
+				"\"waypoint context\" or set %s and try again.", serverclient.EnvServerAddr,
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+
+	// A token from the environment is only read when connecting, so it
+	// isn't part of the context config.
+	if cfg.Server.AuthToken == "" {
+		cfg.Server.AuthToken = os.Getenv(serverclient.EnvServerToken)
+	}
+
+	switch c.platform {
+	case "linux":
+		if err := serverinstall.InstallLinuxRunner(ctx, c.ui, cfg); err != nil {
+			c.ui.Output(
+				"Error installing runner as a systemd service: %s", clierrors.Humanize(err),
+				terminal.WithErrorStyle(),
+			)
+			return 1
+		}
+	default:
+		c.ui.Output(
+			"Unknown runner platform: %s", c.platform,
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+
+	c.ui.Output("Waypoint runner successfully installed!", terminal.WithSuccessStyle())
+	c.ui.Output("The runner will connect to: %s", cfg.Server.Address)
+	return 0
+}
+
+func (c *RunnerInstallCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:    "platform",
+			Target:  &c.platform,
+			Default: "linux",
+			Usage:   "Platform to install the runner into. Only linux is supported.",
+		})
+
+		serverinstall.LinuxFlags(f)
+	})
+}
+
+func (c *RunnerInstallCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *RunnerInstallCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RunnerInstallCommand) Synopsis() string {
+	return "Install a runner as a service on this host."
+}
+
+func (c *RunnerInstallCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint runner install [options]
+
+  Install a runner as a service on this host.
+
+  The "linux" platform installs the runner as a systemd service running as
+  a dedicated system user. The runner connects to the server configured for
+  the current CLI context, or the server set with the WAYPOINT_SERVER_*
+  environment variables. This command must be run as root.
+
+` + c.Flags().Help())
+}
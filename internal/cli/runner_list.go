@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type RunnerListCommand struct {
+	*baseCommand
+
+	flagJson bool
+}
+
+func (c *RunnerListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().ListRunners(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range resp.Runners {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(resp.Runners) == 0 {
+		c.ui.Output("No runners have connected to the server.", terminal.WithWarningStyle())
+		return 0
+	}
+
+	table := terminal.NewTable("ID", "Status", "Adoption", "Last Seen", "Labels")
+	for _, r := range resp.Runners {
+		var lastSeen string
+		if t, err := ptypes.Timestamp(r.LastSeen); err == nil {
+			lastSeen = humanize.Time(t)
+		}
+
+		table.Rich([]string{
+			r.Id,
+			runnerStatus(r),
+			runnerAdoption(r),
+			lastSeen,
+			runnerLabels(r),
+		}, nil)
+	}
+
+	c.ui.Table(table)
+
+	return 0
+}
+
+// runnerStatus returns whether the runner is connected.
+func runnerStatus(r *pb.Runner) string {
+	if r.Online {
+		return "online"
+	}
+
+	return "offline"
+}
+
+// runnerAdoption returns the adoption state of the runner. Local runners
+// only run the jobs of the CLI that started them so they're shown as local.
+func runnerAdoption(r *pb.Runner) string {
+	if r.ByIdOnly {
+		return "local"
+	}
+
+	return strings.ToLower(r.AdoptionState.String())
+}
+
+// runnerLabels returns the labels of the runner as key=value pairs sorted
+// by key.
+func runnerLabels(r *pb.Runner) string {
+	return labelPairs(r.Labels)
+}
+
+// labelPairs returns the labels as key=value pairs sorted by key.
+func labelPairs(labels map[string]string) string {
+	result := make([]string, 0, len(labels))
+	for k, v := range labels {
+		result = append(result, k+"="+v)
+	}
+	sort.Strings(result)
+
+	return strings.Join(result, ", ")
+}
+
+func (c *RunnerListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *RunnerListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *RunnerListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RunnerListCommand) Synopsis() string {
+	return "List the runners known to the server."
+}
+
+func (c *RunnerListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint runner list [options]
+
+  List the runners that are connected to the server or that have been
+  connected and aren't forgotten.
+
+  Runners that are pending adoption are assigned jobs unless the server
+  requires adoption with "waypoint server config-set -require-runner-adoption".
+  Rejected runners are never assigned jobs.
+
+` + c.Flags().Help())
+}
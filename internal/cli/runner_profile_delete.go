@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type RunnerProfileDeleteCommand struct {
+	*baseCommand
+}
+
+func (c *RunnerProfileDeleteCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) == 0 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	names := map[string]bool{}
+	for _, name := range args {
+		names[name] = true
+	}
+
+	err := updateRunnerProfiles(c.Ctx, c.project.Client(), func(profiles []*pb.ServerConfig_RunnerProfile) ([]*pb.ServerConfig_RunnerProfile, error) {
+		var result []*pb.ServerConfig_RunnerProfile
+		found := map[string]bool{}
+		for _, p := range profiles {
+			if names[p.Name] {
+				found[p.Name] = true
+				continue
+			}
+
+			result = append(result, p)
+		}
+
+		// Nothing is deleted if any of the profiles don't exist.
+		for _, name := range args {
+			if !found[name] {
+				return nil, fmt.Errorf("runner profile %q not found", name)
+			}
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	for _, name := range args {
+		c.ui.Output("Runner profile %q deleted.", name, terminal.WithSuccessStyle())
+	}
+
+	return 0
+}
+
+func (c *RunnerProfileDeleteCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *RunnerProfileDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *RunnerProfileDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RunnerProfileDeleteCommand) Synopsis() string {
+	return "Delete runner profiles."
+}
+
+func (c *RunnerProfileDeleteCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint runner profile delete [options] NAME...
+
+  Delete one or more runner profiles. The jobs that they matched target
+  any runner again, unless another profile matches them. Jobs that are
+  already queued keep their target.
+
+` + c.Flags().Help())
+}
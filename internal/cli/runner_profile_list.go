@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type RunnerProfileListCommand struct {
+	*baseCommand
+
+	flagJson bool
+}
+
+func (c *RunnerProfileListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().GetServerConfig(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	profiles := resp.Config.GetRunnerProfiles()
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range profiles {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(profiles) == 0 {
+		c.ui.Output("No runner profiles are set.", terminal.WithWarningStyle())
+		return 0
+	}
+
+	// Profiles are listed in the order that they match jobs.
+	table := terminal.NewTable("Name", "Project", "App", "Workspace", "Job Labels", "Target")
+	for _, p := range profiles {
+		var target string
+		switch t := p.TargetRunner.GetTarget().(type) {
+		case *pb.Ref_Runner_Id:
+			target = "id: " + t.Id.Id
+
+		case *pb.Ref_Runner_Labels:
+			target = "labels: " + labelPairs(t.Labels.Labels)
+		}
+
+		table.Rich([]string{
+			p.Name,
+			p.Project,
+			p.Application,
+			p.Workspace,
+			labelPairs(p.JobLabels),
+			target,
+		}, nil)
+	}
+
+	c.ui.Table(table)
+
+	return 0
+}
+
+func (c *RunnerProfileListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *RunnerProfileListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *RunnerProfileListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RunnerProfileListCommand) Synopsis() string {
+	return "List the runner profiles of the server."
+}
+
+func (c *RunnerProfileListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint runner profile list [options]
+
+  List the runner profiles of the server in the order that they're
+  matched to jobs. Empty columns match every job.
+
+` + c.Flags().Help())
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type RunnerProfileSetCommand struct {
+	*baseCommand
+
+	flagMatchProject string
+	flagMatchApp     string
+	flagMatchWs      string
+	flagMatchLabels  map[string]string
+	flagTargetId     string
+	flagTargetLabel  map[string]string
+}
+
+func (c *RunnerProfileSetCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(flags),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	profile := &pb.ServerConfig_RunnerProfile{
+		Name:        args[0],
+		Project:     c.flagMatchProject,
+		Application: c.flagMatchApp,
+		Workspace:   c.flagMatchWs,
+		JobLabels:   c.flagMatchLabels,
+	}
+	switch {
+	case c.flagTargetId != "" && len(c.flagTargetLabel) > 0:
+		c.ui.Output("Only one of -target-id and -target-label may be set.", terminal.WithErrorStyle())
+		return 1
+
+	case c.flagTargetId != "":
+		profile.TargetRunner = &pb.Ref_Runner{
+			Target: &pb.Ref_Runner_Id{
+				Id: &pb.Ref_RunnerId{Id: c.flagTargetId},
+			},
+		}
+
+	case len(c.flagTargetLabel) > 0:
+		profile.TargetRunner = &pb.Ref_Runner{
+			Target: &pb.Ref_Runner_Labels{
+				Labels: &pb.Ref_RunnerLabels{Labels: c.flagTargetLabel},
+			},
+		}
+
+	default:
+		c.ui.Output("One of -target-id or -target-label must be set.", terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Replace the profile with the same name, or add it last so that the
+	// profiles that are already set keep matching first.
+	err := updateRunnerProfiles(c.Ctx, c.project.Client(), func(profiles []*pb.ServerConfig_RunnerProfile) ([]*pb.ServerConfig_RunnerProfile, error) {
+		for i, p := range profiles {
+			if p.Name == profile.Name {
+				profiles[i] = profile
+				return profiles, nil
+			}
+		}
+
+		return append(profiles, profile), nil
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Runner profile %q set.", profile.Name, terminal.WithSuccessStyle())
+	return 0
+}
+
+// updateRunnerProfiles changes the runner profiles of the server config
+// with f. The other settings of the config are kept. If f returns an
+// error, the config isn't changed.
+func updateRunnerProfiles(
+	ctx context.Context,
+	client pb.WaypointClient,
+	f func([]*pb.ServerConfig_RunnerProfile) ([]*pb.ServerConfig_RunnerProfile, error),
+) error {
+	resp, err := client.GetServerConfig(ctx, &empty.Empty{})
+	if err != nil {
+		return err
+	}
+	cfg := resp.Config
+	if cfg == nil {
+		cfg = &pb.ServerConfig{}
+	}
+
+	// The config must have an advertise address to be set. An empty one
+	// is what "waypoint server config-set" sets by default, which means
+	// that entrypoints don't connect to the server.
+	if len(cfg.AdvertiseAddrs) == 0 {
+		cfg.AdvertiseAddrs = []*pb.ServerConfig_AdvertiseAddr{{}}
+	}
+
+	cfg.RunnerProfiles, err = f(cfg.RunnerProfiles)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.SetServerConfig(ctx, &pb.SetServerConfigRequest{
+		Config: cfg,
+	})
+	return err
+}
+
+func (c *RunnerProfileSetCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "match-project",
+			Target: &c.flagMatchProject,
+			Usage:  "Only match the jobs of this project.",
+		})
+		f.StringVar(&flag.StringVar{
+			Name:   "match-app",
+			Target: &c.flagMatchApp,
+			Usage:  "Only match the jobs of this application.",
+		})
+		f.StringVar(&flag.StringVar{
+			Name:   "match-workspace",
+			Target: &c.flagMatchWs,
+			Usage:  "Only match the jobs in this workspace.",
+		})
+		f.StringMapVar(&flag.StringMapVar{
+			Name:   "match-label",
+			Target: &c.flagMatchLabels,
+			Usage: "Only match the jobs with this label. Can be specified multiple\n" +
+				"times, in which case jobs must have all of the labels.",
+		})
+		f.StringVar(&flag.StringVar{
+			Name:       "target-id",
+			Target:     &c.flagTargetId,
+			Completion: c.predictRunners(),
+			Usage:      "ID of the runner that matching jobs target.",
+		})
+		f.StringMapVar(&flag.StringMapVar{
+			Name:   "target-label",
+			Target: &c.flagTargetLabel,
+			Usage: "Label of the runners that matching jobs target. Can be specified\n" +
+				"multiple times, in which case runners must have all of the labels.",
+		})
+	})
+}
+
+func (c *RunnerProfileSetCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *RunnerProfileSetCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *RunnerProfileSetCommand) Synopsis() string {
+	return "Set a runner profile to route jobs to runners."
+}
+
+func (c *RunnerProfileSetCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint runner profile set [options] NAME
+
+  Set a runner profile on the server. Jobs that the profile matches
+  target the runners of the profile, such as a runner by ID or the
+  runners with labels that they registered with "waypoint runner agent
+  -label". This routes jobs that need Docker, a cloud or a network zone
+  to the runners that are able to run them.
+
+  Jobs that target any runner are matched to the profiles in the order
+  the profiles were added and target the runners of the first one that
+  matches. Jobs that target runners themselves, such as with the runner
+  block of an app, aren't changed. A profile without -match flags matches
+  every job.
+
+  If a profile with the name exists, it is replaced.
+
+` + c.Flags().Help())
+}
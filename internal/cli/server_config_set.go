@@ -1,6 +1,9 @@
 package cli
 
 import (
+	stdflag "flag"
+
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/clierrors"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
@@ -11,26 +14,47 @@ import (
 type ServerConfigSetCommand struct {
 	*baseCommand
 
-	flagAdvertiseAddr pb.ServerConfig_AdvertiseAddr
+	flagAdvertiseAddr         pb.ServerConfig_AdvertiseAddr
+	flagRequireRunnerAdoption bool
 }
 
 func (c *ServerConfigSetCommand) Run(args []string) int {
+	flags := c.Flags()
+
 	// Initialize. If we fail, we just exit since Init handles the UI.
 	if err := c.Init(
 		WithArgs(args),
-		WithFlags(c.Flags()),
+		WithFlags(flags),
 	); err != nil {
 		return 1
 	}
 
-	cfg := &pb.ServerConfig{
-		AdvertiseAddrs: []*pb.ServerConfig_AdvertiseAddr{
+	// Only the settings of the flags that are set are changed.
+	set := map[string]bool{}
+	flags.Visit(func(f *stdflag.Flag) { set[f.Name] = true })
+
+	client := c.project.Client()
+	resp, err := client.GetServerConfig(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	cfg := resp.Config
+	if cfg == nil {
+		cfg = &pb.ServerConfig{}
+	}
+
+	if len(cfg.AdvertiseAddrs) == 0 || set["advertise-addr"] ||
+		set["advertise-tls"] || set["advertise-tls-skip-verify"] {
+		cfg.AdvertiseAddrs = []*pb.ServerConfig_AdvertiseAddr{
 			&c.flagAdvertiseAddr,
-		},
+		}
+	}
+	if set["require-runner-adoption"] {
+		cfg.RequireRunnerAdoption = c.flagRequireRunnerAdoption
 	}
 
-	client := c.project.Client()
-	_, err := client.SetServerConfig(c.Ctx, &pb.SetServerConfigRequest{
+	_, err = client.SetServerConfig(c.Ctx, &pb.SetServerConfigRequest{
 		Config: cfg,
 	})
 	if err != nil {
@@ -65,6 +89,12 @@ func (c *ServerConfigSetCommand) Flags() *flag.Sets {
 			Usage:   "Do not verify the TLS certificate presented by the server.",
 			Default: false,
 		})
+		f.BoolVar(&flag.BoolVar{
+			Name:   "require-runner-adoption",
+			Target: &c.flagRequireRunnerAdoption,
+			Usage: "If true, runners aren't assigned jobs until they're adopted with\n" +
+				"\"waypoint runner adopt\". Local runners don't need to be adopted.",
+		})
 	})
 }
 
@@ -88,7 +118,7 @@ Usage: waypoint server config-set [options]
 
   The configuration that can be set here is different from the configuration
   given via the startup file. This configuration is persisted in the server
-  database.
+  database. Only the settings of the flags that are given are changed.
 
 ` + c.Flags().Help())
 }
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"github.com/dustin/go-humanize"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ServerMaintenanceCommand struct {
+	*baseCommand
+
+	flagCheckOnly bool
+}
+
+func (c *ServerMaintenanceCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().RunMaintenance(c.Ctx, &pb.RunMaintenanceRequest{
+		CheckOnly: c.flagCheckOnly,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if len(resp.Problems) > 0 {
+		c.ui.Output("The database integrity check found problems:", terminal.WithErrorStyle())
+		for _, p := range resp.Problems {
+			c.ui.Output("  - %s", p, terminal.WithErrorStyle())
+		}
+		c.ui.Output(
+			"The database wasn't compacted. Restore a snapshot of the server to repair it.",
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("The database integrity check found no problems.", terminal.WithSuccessStyle())
+	if !resp.Compacted {
+		c.ui.Output("The database is %s, with %s of free space.",
+			humanize.Bytes(uint64(resp.SizeBefore)), humanize.Bytes(uint64(resp.FreeBefore)))
+		return 0
+	}
+
+	c.ui.Output("Compacted the database from %s to %s.",
+		humanize.Bytes(uint64(resp.SizeBefore)), humanize.Bytes(uint64(resp.SizeAfter)),
+		terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *ServerMaintenanceCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "check-only",
+			Target: &c.flagCheckOnly,
+			Usage:  "Only check the integrity of the database without compacting it.",
+		})
+	})
+}
+
+func (c *ServerMaintenanceCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServerMaintenanceCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ServerMaintenanceCommand) Synopsis() string {
+	return "Check the integrity of the server database and compact it."
+}
+
+func (c *ServerMaintenanceCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server maintenance [options]
+
+  Check the integrity of the server database and compact it to reclaim
+  the space left by deleted records.
+
+  The database is only compacted if the check finds no problems. Calls to
+  the server wait while the database is compacted, which takes longer for
+  larger databases. The server also does this every day if at least half of
+  the database, and at least 64 MB, is free space. This requires admin
+  permission.
+
+  This is only supported for servers that store their data in a local
+  file. PostgreSQL databases are maintained with their own tools.
+
+` + c.Flags().Help())
+}
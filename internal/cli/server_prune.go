@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ServerPruneCommand struct {
+	*baseCommand
+
+	flagProject string
+}
+
+func (c *ServerPruneCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	req := &pb.PruneOperationsRequest{}
+	if c.flagProject != "" {
+		req.Project = &pb.Ref_Project{Project: c.flagProject}
+	}
+
+	resp, err := c.project.Client().PruneOperations(c.Ctx, req)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Pruned %d builds, %d pushed artifacts, %d deployments, %d releases and %d jobs.",
+		resp.Builds, resp.PushedArtifacts, resp.Deployments, resp.Releases, resp.Jobs,
+		terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *ServerPruneCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage:      "Only prune this project. By default all projects are pruned.",
+		})
+	})
+}
+
+func (c *ServerPruneCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServerPruneCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ServerPruneCommand) Synopsis() string {
+	return "Delete old records according to the retention policy of projects."
+}
+
+func (c *ServerPruneCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server prune [options]
+
+  Delete the records of old builds, deployments, releases and jobs that are
+  outside the retention policy of their project.
+
+  The retention policy is set with the "retention" stanza of waypoint.hcl.
+  Projects without a policy are never pruned. The server also prunes
+  records every hour, this command prunes them immediately.
+
+` + c.Flags().Help())
+}
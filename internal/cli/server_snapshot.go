@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type ServerSnapshotCommand struct {
+	*baseCommand
+}
+
+func (c *ServerSnapshotCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+	path := c.args[0]
+
+	client, err := c.project.Client().CreateSnapshot(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Write to stdout if requested, otherwise to the file. We remove the
+	// file if the snapshot fails so that a partial snapshot isn't mistaken
+	// for a valid one.
+	var w io.Writer = os.Stdout
+	var f *os.File
+	if path != "-" {
+		f, err = os.Create(path)
+		if err != nil {
+			c.ui.Output("Error creating snapshot file: %s", err, terminal.WithErrorStyle())
+			return 1
+		}
+
+		w = f
+	}
+
+	err = snapshotReceive(client, w)
+	if f != nil {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if f != nil {
+		c.ui.Output("Snapshot written to %s.", path, terminal.WithSuccessStyle())
+	}
+
+	return 0
+}
+
+// snapshotReceive writes the snapshot chunks to w until the server is done.
+func snapshotReceive(client pb.Waypoint_CreateSnapshotClient, w io.Writer) error {
+	for {
+		resp, err := client.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(resp.Chunk); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *ServerSnapshotCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *ServerSnapshotCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("")
+}
+
+func (c *ServerSnapshotCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ServerSnapshotCommand) Synopsis() string {
+	return "Write a snapshot of the server data to a file."
+}
+
+func (c *ServerSnapshotCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server snapshot [options] FILE
+
+  Write a snapshot of the server data to FILE, or to stdout if FILE is "-".
+
+  The snapshot is consistent and can be taken while the server is running.
+  It can be restored with "waypoint server restore". Snapshots contain
+  secrets such as config values and the keys used to sign tokens, so keep
+  them safe. This requires admin permission.
+
+`)
+}
+
+type ServerRestoreCommand struct {
+	*baseCommand
+}
+
+func (c *ServerRestoreCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+	path := c.args[0]
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			c.ui.Output("Error opening snapshot file: %s", err, terminal.WithErrorStyle())
+			return 1
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	client, err := c.project.Client().RestoreSnapshot(c.Ctx)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			// The buffer is reused so the chunk must be copied.
+			chunk := append([]byte(nil), buf[:n]...)
+			if err := client.Send(&pb.RestoreSnapshotRequest{Chunk: chunk}); err != nil {
+				// The server returns the reason from CloseAndRecv.
+				break
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			c.ui.Output("Error reading snapshot file: %s", err, terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	if _, err := client.CloseAndRecv(); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Snapshot restored.", terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *ServerRestoreCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *ServerRestoreCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("")
+}
+
+func (c *ServerRestoreCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ServerRestoreCommand) Synopsis() string {
+	return "Restore the server data from a snapshot."
+}
+
+func (c *ServerRestoreCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server restore [options] FILE
+
+  Replace the server data with a snapshot created by "waypoint server
+  snapshot". Use "-" to read the snapshot from stdin.
+
+  All existing data is replaced, including users and tokens. The snapshot
+  is validated before it is applied, so an incomplete snapshot leaves the
+  server unchanged. This requires admin permission.
+
+`)
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/server"
+)
+
+// tlsReloadInterval is how often the TLS files of listeners are checked
+// for changes.
+const tlsReloadInterval = 30 * time.Second
+
+// tlsReloader serves the TLS certificate and client CAs of a listener and
+// loads them again when their files change, so that certificates can be
+// rotated without restarting the server and dropping the streams of
+// runners and entrypoints.
+type tlsReloader struct {
+	log hclog.Logger
+	cfg *config.Listener
+
+	lock      sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	cas       *server.ClientCAs
+	modTimes  map[string]time.Time
+}
+
+// newTLSReloader loads the TLS files of the listener. If the listener has
+// no certificate file, cert is served instead.
+func newTLSReloader(log hclog.Logger, cfg *config.Listener, cert *tls.Certificate) (*tlsReloader, error) {
+	r := &tlsReloader{log: log, cfg: cfg, cert: cert}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload loads the TLS files again if any of them changed since they
+// were last loaded. If loading fails, the previous files are still used.
+func (r *tlsReloader) Reload() error {
+	files := map[string]string{
+		"cert":          r.cfg.TLSCertFile,
+		"key":           r.cfg.TLSKeyFile,
+		"runner CA":     r.cfg.TLSRunnerCAFile,
+		"entrypoint CA": r.cfg.TLSEntrypointCAFile,
+	}
+
+	changed := false
+	modTimes := map[string]time.Time{}
+	for _, path := range files {
+		if path == "" {
+			continue
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		modTimes[path] = fi.ModTime()
+		if r.modTimes == nil || !r.modTimes[path].Equal(fi.ModTime()) {
+			changed = true
+		}
+	}
+	if !changed && r.modTimes != nil {
+		return nil
+	}
+
+	cert := r.cert
+	if r.cfg.TLSCertFile != "" {
+		c, err := tls.LoadX509KeyPair(r.cfg.TLSCertFile, r.cfg.TLSKeyFile)
+		if err != nil {
+			return err
+		}
+
+		cert = &c
+	}
+
+	clientCAs := x509.NewCertPool()
+	cas := &server.ClientCAs{}
+	for _, ca := range []struct {
+		Path string
+		Pool **x509.CertPool
+	}{
+		{r.cfg.TLSRunnerCAFile, &cas.Runner},
+		{r.cfg.TLSEntrypointCAFile, &cas.Entrypoint},
+	} {
+		if ca.Path == "" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(ca.Path)
+		if err != nil {
+			return err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) || !clientCAs.AppendCertsFromPEM(data) {
+			return fmt.Errorf("no certificates found in %s", ca.Path)
+		}
+
+		*ca.Pool = pool
+	}
+	if cas.Runner == nil && cas.Entrypoint == nil {
+		clientCAs = nil
+		cas = nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.modTimes != nil {
+		r.log.Info("TLS files changed and were reloaded")
+	}
+	r.cert = cert
+	r.clientCAs = clientCAs
+	r.cas = cas
+	r.modTimes = modTimes
+
+	return nil
+}
+
+// Run checks the TLS files for changes until ctx is done.
+func (r *tlsReloader) Run(ctx context.Context) {
+	ticker := time.NewTicker(tlsReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := r.Reload(); err != nil {
+				r.log.Warn("error reloading TLS files, the previous files are still used", "err", err)
+			}
+		}
+	}
+}
+
+// TLSConfig returns the TLS configuration for the listener. Each
+// connection uses the files that are loaded when it is accepted.
+func (r *tlsReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.lock.RLock()
+			defer r.lock.RUnlock()
+
+			cfg := &tls.Config{Certificates: []tls.Certificate{*r.cert}}
+			if r.clientCAs != nil {
+				// Other clients such as the CLI don't have certificates,
+				// so they are only required by the endpoints of runners
+				// and entrypoints.
+				cfg.ClientCAs = r.clientCAs
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+			}
+
+			return cfg, nil
+		},
+	}
+}
+
+// ClientCAs returns the client CAs for server.WithClientCAs.
+func (r *tlsReloader) ClientCAs() *server.ClientCAs {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.cas
+}
@@ -0,0 +1,346 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clicontext"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/serverclient"
+	"github.com/hashicorp/waypoint/internal/serverinstall"
+)
+
+// serverUpgradeFuncs are the functions that upgrade a server installed with
+// "waypoint install" by platform.
+var serverUpgradeFuncs = map[string]func(
+	context.Context, terminal.UI, *serverinstall.Config,
+) (*serverinstall.UpgradeResult, error){
+	"docker":     serverinstall.UpgradeDocker,
+	"kubernetes": serverinstall.UpgradeKubernetes,
+	"nomad":      serverinstall.UpgradeNomad,
+}
+
+type ServerUpgradeCommand struct {
+	*baseCommand
+
+	config       serverinstall.Config
+	platform     string
+	snapshot     bool
+	snapshotPath string
+	rollback     bool
+	timeout      time.Duration
+}
+
+func (c *ServerUpgradeCommand) Run(args []string) int {
+	ctx := c.Ctx
+	log := c.Log.Named("upgrade")
+	defer c.Close()
+
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	upgrade, ok := serverUpgradeFuncs[c.platform]
+	if !ok {
+		c.ui.Output(
+			"Unknown server platform: %s. Upgrades are supported for docker, kubernetes, and nomad.",
+			c.platform,
+			terminal.WithErrorStyle(),
+		)
+		return 1
+	}
+
+	client := c.project.Client()
+	resp, err := client.GetVersionInfo(ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	c.ui.Output("Current server version: %s", resp.Info.Version, terminal.WithInfoStyle())
+
+	// Snapshot first so that the data can be restored if the new version
+	// can't read it or changes it in a way the old version can't.
+	if c.snapshot {
+		c.ui.Output("Writing a snapshot of the server data to %s...", c.snapshotPath,
+			terminal.WithHeaderStyle())
+		if err := c.writeSnapshot(ctx, client); err != nil {
+			c.ui.Output("Error writing the snapshot, the server wasn't upgraded: %s",
+				clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	c.ui.Output("Upgrading the server to %s...", c.config.ServerImage, terminal.WithHeaderStyle())
+	result, err := upgrade(ctx, c.ui, &c.config)
+	if err != nil {
+		c.ui.Output("Error upgrading the server: %s\n\n%s",
+			clierrors.Humanize(err), c.snapshotHelp(), terminal.WithErrorStyle())
+		return 1
+	}
+	log.Info("server upgraded", "previous", result.PreviousImage, "image", c.config.ServerImage)
+
+	if result.Address != "" {
+		if err := c.updateAddress(result.Address); err != nil {
+			c.ui.Output("Error updating the CLI context with the new server address %s: %s",
+				result.Address, clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+	}
+
+	version, err := c.waitHealthy(ctx)
+	if err == nil {
+		c.ui.Output("\nServer upgraded to %s.", version, terminal.WithSuccessStyle())
+		return 0
+	}
+
+	c.ui.Output("The upgraded server didn't become healthy: %s", clierrors.Humanize(err),
+		terminal.WithErrorStyle())
+	if !c.rollback || result.PreviousImage == "" {
+		c.ui.Output(c.snapshotHelp(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Rolling back the server to %s...", result.PreviousImage, terminal.WithHeaderStyle())
+	c.config.ServerImage = result.PreviousImage
+	rollback, err := upgrade(ctx, c.ui, &c.config)
+	if err == nil && rollback.Address != "" {
+		err = c.updateAddress(rollback.Address)
+	}
+	if err == nil {
+		_, err = c.waitHealthy(ctx)
+	}
+	if err != nil {
+		c.ui.Output("Error rolling back the server: %s\n\n%s",
+			clierrors.Humanize(err), c.snapshotHelp(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("\nThe server was rolled back to %s.\n\n%s",
+		result.PreviousImage, c.snapshotHelp(), terminal.WithWarningStyle())
+	return 1
+}
+
+// writeSnapshot writes a snapshot of the server data to the snapshot path.
+func (c *ServerUpgradeCommand) writeSnapshot(ctx context.Context, client pb.WaypointClient) error {
+	stream, err := client.CreateSnapshot(ctx, &empty.Empty{})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(c.snapshotPath)
+	if err != nil {
+		return err
+	}
+
+	err = snapshotReceive(stream, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(c.snapshotPath)
+	}
+
+	return err
+}
+
+// snapshotHelp returns how to restore the data if the upgrade failed.
+func (c *ServerUpgradeCommand) snapshotHelp() string {
+	if !c.snapshot {
+		return strings.TrimSpace(errUpgradeNoSnapshot)
+	}
+
+	return fmt.Sprintf(strings.TrimSpace(errUpgradeSnapshot), c.snapshotPath)
+}
+
+// updateAddress sets the new address of the server on the CLI context.
+func (c *ServerUpgradeCommand) updateAddress(addr string) error {
+	c.clientContext.Server.Address = addr
+
+	name := c.flagContext
+	if name == "" {
+		var err error
+		name, err = c.contextStorage.Default()
+		if err != nil {
+			return err
+		}
+	}
+	if name == "" {
+		return nil
+	}
+
+	cfg, err := c.contextStorage.Load(name)
+	if err != nil {
+		return err
+	}
+	cfg.Server.Address = addr
+
+	c.ui.Output("Updating the address of the server in CLI context %q to %s", name, addr,
+		terminal.WithInfoStyle())
+	return c.contextStorage.Set(name, cfg)
+}
+
+// waitHealthy waits for the server to answer requests again and returns
+// the version it reports.
+func (c *ServerUpgradeCommand) waitHealthy(ctx context.Context) (string, error) {
+	sg := c.ui.StepGroup()
+	defer sg.Wait()
+
+	s := sg.Add("Waiting for the server to be healthy...")
+	defer func() { s.Abort() }()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		version, err := c.version(ctx, c.clientContext)
+		if err == nil {
+			s.Update("Server is healthy")
+			s.Done()
+			return version, nil
+		}
+		lastErr = err
+
+		select {
+		case <-time.After(2 * time.Second):
+		case <-ctx.Done():
+			return "", lastErr
+		}
+	}
+}
+
+// version connects to the server with a new connection and returns its
+// version. A new connection is used since the existing one may have been
+// to the old server.
+func (c *ServerUpgradeCommand) version(ctx context.Context, cfg *clicontext.Config) (string, error) {
+	conn, err := serverclient.Connect(ctx,
+		serverclient.FromContextConfig(cfg),
+		serverclient.FromEnv(),
+		serverclient.Timeout(5*time.Second),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	resp, err := pb.NewWaypointClient(conn).GetVersionInfo(ctx, &empty.Empty{})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Info.Version, nil
+}
+
+func (c *ServerUpgradeCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:    "platform",
+			Target:  &c.platform,
+			Default: "kubernetes",
+			Usage:   "Platform the server was installed to. One of kubernetes, nomad, or docker.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "server-image",
+			Target:  &c.config.ServerImage,
+			Usage:   "Docker image to upgrade the server to.",
+			Default: "hashicorp/waypoint:latest",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "namespace",
+			Target:  &c.config.Namespace,
+			Usage:   "Kubernetes namespace the server is installed in.",
+			Default: "default",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "snapshot",
+			Target:  &c.snapshot,
+			Default: true,
+			Usage:   "Write a snapshot of the server data before upgrading.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "snapshot-path",
+			Target:  &c.snapshotPath,
+			Default: fmt.Sprintf("waypoint-server-snapshot-%d.snap", time.Now().Unix()),
+			Usage: "Path to write the snapshot to. The default value will be suffixed " +
+				"with a timestamp at the time the command is executed.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "rollback",
+			Target:  &c.rollback,
+			Default: true,
+			Usage:   "Roll back to the previous image if the upgraded server doesn't become healthy.",
+		})
+
+		f.DurationVar(&flag.DurationVar{
+			Name:    "timeout",
+			Target:  &c.timeout,
+			Default: 5 * time.Minute,
+			Usage:   "How long to wait for the upgraded server to become healthy.",
+		})
+
+		serverinstall.NomadFlags(f)
+	})
+}
+
+func (c *ServerUpgradeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServerUpgradeCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *ServerUpgradeCommand) Synopsis() string {
+	return "Upgrade a server installed to Kubernetes, Nomad, or Docker."
+}
+
+func (c *ServerUpgradeCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint server upgrade [options]
+
+  Upgrade a server that was installed with "waypoint install" to a new
+  image. Use the "-platform" flag for servers installed to Nomad or Docker.
+
+  A snapshot of the server data is written first. The server is then
+  changed to run the new image with the same data, and this waits for it
+  to answer requests. If it doesn't become healthy within the timeout, the
+  previous image is restored. If the server address changes, such as for
+  Nomad, the current CLI context is updated.
+
+  The CLI must be able to reach the platform the server runs on with the
+  same configuration as "waypoint install". This requires admin permission
+  on the server to write the snapshot.
+
+` + c.Flags().Help())
+}
+
+var (
+	errUpgradeSnapshot = `
+A snapshot of the server data from before the upgrade is at %s.
+If the data was changed, restore it with "waypoint server restore".
+`
+
+	errUpgradeNoSnapshot = `
+No snapshot was written before the upgrade since -snapshot was false.
+`
+)
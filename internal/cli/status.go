@@ -0,0 +1,489 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type StatusCommand struct {
+	*baseCommand
+
+	flagAllProjects bool
+	flagJson        bool
+	flagVerbose     bool
+	flagId          idFormat
+}
+
+// appStatus is the status of an app in a workspace.
+type appStatus struct {
+	App        *pb.Ref_Application
+	Deployment *pb.Deployment
+	Release    *pb.Release
+	Instances  int
+	Health     string
+
+	// Starting and Unhealthy are the number of instances that report
+	// that state from their health checks.
+	Starting  int
+	Unhealthy int
+
+	// InstanceList are the connected instances with the health and
+	// metrics that they report.
+	InstanceList []*pb.Instance
+}
+
+// The health of an app, from the latest deployment and release.
+const (
+	statusHealthy     = "ready"
+	statusNotDeployed = "not deployed"
+	statusDeploying   = "deploying"
+	statusDestroyed   = "destroyed"
+	statusError       = "error"
+	statusNotReleased = "not released"
+	statusNoInstances = "no instances"
+	statusStarting    = "starting"
+	statusUnhealthy   = "unhealthy"
+)
+
+func (c *StatusCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithConfig(true),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) > 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	apps, err := c.targetApps()
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	var result []*appStatus
+	for _, app := range apps {
+		s, err := c.appStatus(c.Ctx, app)
+		if err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		result = append(result, s)
+	}
+
+	if c.flagJson {
+		if err := c.displayJson(result); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(result) == 0 {
+		c.ui.Output("No apps found.")
+		return 0
+	}
+
+	tbl := terminal.NewTable("Project", "App", "Deployment", "Release", "Instances", "URL", "Health")
+	for _, s := range result {
+		deployment, release := "", ""
+		if d := s.Deployment; d != nil {
+			deployment = c.flagId.FormatId(d.Sequence, d.Id)
+			if t, err := ptypes.Timestamp(d.Status.GetStartTime()); err == nil {
+				deployment += " (" + humanize.Time(t) + ")"
+			}
+		}
+		if r := s.Release; r != nil {
+			release = c.flagId.FormatId(r.Sequence, r.Id)
+		}
+
+		healthColor := terminal.Yellow
+		switch s.Health {
+		case statusHealthy:
+			healthColor = terminal.Green
+		case statusError, statusUnhealthy:
+			healthColor = terminal.Red
+		case statusNotDeployed, statusDestroyed:
+			healthColor = ""
+		}
+
+		tbl.Rich([]string{
+			s.App.Project,
+			s.App.Application,
+			deployment,
+			release,
+			strconv.Itoa(s.Instances),
+			s.Release.GetUrl(),
+			s.Health,
+		}, []string{
+			"",
+			"",
+			"",
+			"",
+			"",
+			"",
+			healthColor,
+		})
+	}
+
+	c.ui.Output("Status of the apps in workspace %q", c.project.WorkspaceRef().Workspace,
+		terminal.WithHeaderStyle())
+	c.ui.Table(tbl)
+
+	if c.flagVerbose {
+		c.displayInstances(result)
+	}
+
+	return 0
+}
+
+// displayInstances shows the health and metrics of each instance.
+func (c *StatusCommand) displayInstances(result []*appStatus) {
+	for _, s := range result {
+		if len(s.InstanceList) == 0 {
+			continue
+		}
+
+		tbl := terminal.NewTable("Instance", "Deployment", "Health", "CPU", "Memory", "Restarts", "Uptime")
+		for _, inst := range s.InstanceList {
+			health := strings.ToLower(inst.Health.GetState().String())
+			if inst.Health == nil {
+				health = ""
+			}
+
+			cpu, memory, restarts, uptime := "", "", "", ""
+			if m := inst.Metrics; m != nil {
+				cpu = fmt.Sprintf("%.1f%%", m.CpuPercent)
+				memory = humanize.IBytes(m.RssBytes)
+				restarts = strconv.Itoa(int(m.Restarts))
+				if t, err := ptypes.Timestamp(m.StartTime); err == nil {
+					uptime = humanize.RelTime(t, time.Now(), "", "")
+				}
+			}
+
+			tbl.Rich([]string{
+				inst.Id,
+				inst.DeploymentId,
+				health,
+				cpu,
+				memory,
+				restarts,
+				strings.TrimSpace(uptime),
+			}, nil)
+		}
+
+		c.ui.Output("Instances of %s/%s", s.App.Project, s.App.Application,
+			terminal.WithHeaderStyle())
+		c.ui.Table(tbl)
+	}
+}
+
+// targetApps returns the apps to show the status of. These are the apps of
+// the project or app given as an argument, the apps of the project in the
+// current directory, or the apps of every project.
+func (c *StatusCommand) targetApps() ([]*pb.Ref_Application, error) {
+	client := c.project.Client()
+
+	var projects []*pb.Ref_Project
+	switch {
+	case len(c.args) == 1:
+		parts := strings.SplitN(c.args[0], "/", 2)
+		if len(parts) == 2 {
+			return []*pb.Ref_Application{{Project: parts[0], Application: parts[1]}}, nil
+		}
+
+		projects = []*pb.Ref_Project{{Project: parts[0]}}
+
+	case c.refProject != nil && !c.flagAllProjects:
+		projects = []*pb.Ref_Project{c.refProject}
+
+	default:
+		resp, err := client.ListProjects(c.Ctx, &empty.Empty{})
+		if err != nil {
+			return nil, err
+		}
+
+		projects = resp.Projects
+	}
+
+	var result []*pb.Ref_Application
+	for _, ref := range projects {
+		resp, err := client.GetProject(c.Ctx, &pb.GetProjectRequest{Project: ref})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, app := range resp.Project.Applications {
+			result = append(result, &pb.Ref_Application{
+				Project:     resp.Project.Name,
+				Application: app.Name,
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Project != result[j].Project {
+			return result[i].Project < result[j].Project
+		}
+
+		return result[i].Application < result[j].Application
+	})
+
+	return result, nil
+}
+
+// appStatus gets the latest deployment, release and connected instances of
+// the app in the current workspace.
+func (c *StatusCommand) appStatus(ctx context.Context, app *pb.Ref_Application) (*appStatus, error) {
+	client := c.project.Client()
+	ws := c.project.WorkspaceRef()
+	result := &appStatus{App: app}
+
+	deployments, err := client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
+		Application: app,
+		Workspace:   ws,
+		Order: &pb.OperationOrder{
+			Order: pb.OperationOrder_START_TIME,
+			Desc:  true,
+			Limit: 1,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(deployments.Deployments) > 0 {
+		result.Deployment = deployments.Deployments[0]
+	}
+
+	release, err := client.GetLatestRelease(ctx, &pb.GetLatestReleaseRequest{
+		Application: app,
+		Workspace:   ws,
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+	if err == nil {
+		result.Release = release
+	}
+
+	var token string
+	for {
+		resp, err := client.ListInstances(ctx, &pb.ListInstancesRequest{
+			Scope: &pb.ListInstancesRequest_Application_{
+				Application: &pb.ListInstancesRequest_Application{
+					Application: app,
+					Workspace:   ws,
+				},
+			},
+			PageToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result.Instances += len(resp.Instances)
+		result.InstanceList = append(result.InstanceList, resp.Instances...)
+		for _, inst := range resp.Instances {
+			switch inst.Health.GetState() {
+			case pb.Instance_Health_STARTING:
+				result.Starting++
+			case pb.Instance_Health_UNHEALTHY:
+				result.Unhealthy++
+			}
+		}
+
+		token = resp.NextPageToken
+		if token == "" {
+			break
+		}
+	}
+
+	result.Health = statusHealth(result)
+	return result, nil
+}
+
+// statusHealth summarizes the status of an app.
+func statusHealth(s *appStatus) string {
+	d := s.Deployment
+	switch {
+	case d == nil:
+		return statusNotDeployed
+
+	case d.Status.GetState() == pb.Status_RUNNING:
+		return statusDeploying
+
+	case d.Status.GetState() == pb.Status_ERROR,
+		s.Release.GetStatus().GetState() == pb.Status_ERROR:
+		return statusError
+
+	case d.State == pb.Operation_DESTROYED:
+		return statusDestroyed
+
+	case d.HasEntrypointConfig && s.Instances == 0:
+		// Deployments with the entrypoint report their instances, so
+		// none being connected means the app isn't running.
+		return statusNoInstances
+
+	case s.Unhealthy > 0:
+		return statusUnhealthy
+
+	case s.Starting > 0:
+		return statusStarting
+
+	case s.Release == nil || s.Release.DeploymentId != d.Id:
+		return statusNotReleased
+	}
+
+	return statusHealthy
+}
+
+func (c *StatusCommand) displayJson(result []*appStatus) error {
+	output := []map[string]interface{}{}
+	for _, s := range result {
+		i := map[string]interface{}{}
+		i["project"] = s.App.Project
+		i["application"] = s.App.Application
+		i["workspace"] = c.project.WorkspaceRef().Workspace
+		i["instances"] = s.Instances
+		i["health"] = s.Health
+		i["instances_starting"] = s.Starting
+		i["instances_unhealthy"] = s.Unhealthy
+
+		instances := []map[string]interface{}{}
+		for _, inst := range s.InstanceList {
+			v := map[string]interface{}{
+				"id":            inst.Id,
+				"deployment_id": inst.DeploymentId,
+				"health":        inst.Health.GetState().String(),
+			}
+			if m := inst.Metrics; m != nil {
+				metrics := map[string]interface{}{
+					"cpu_seconds": m.CpuSeconds,
+					"cpu_percent": m.CpuPercent,
+					"rss_bytes":   m.RssBytes,
+					"restarts":    m.Restarts,
+				}
+				if t, err := ptypes.Timestamp(m.StartTime); err == nil {
+					metrics["uptime_seconds"] = int64(time.Since(t).Seconds())
+				}
+
+				v["metrics"] = metrics
+			}
+
+			instances = append(instances, v)
+		}
+		i["instance_list"] = instances
+
+		if d := s.Deployment; d != nil {
+			i["deployment"] = map[string]interface{}{
+				"id":             d.Id,
+				"sequence":       d.Sequence,
+				"component":      d.Component.GetName(),
+				"physical_state": d.State.String(),
+				"status":         d.Status.GetState().String(),
+			}
+		}
+		if r := s.Release; r != nil {
+			i["release"] = map[string]interface{}{
+				"id":            r.Id,
+				"sequence":      r.Sequence,
+				"deployment_id": r.DeploymentId,
+				"url":           r.Url,
+				"status":        r.Status.GetState().String(),
+			}
+		}
+
+		output = append(output, i)
+	}
+
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+func (c *StatusCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "all-projects",
+			Target: &c.flagAllProjects,
+			Usage:  "Show the apps of every project, even in the directory of a project.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "json",
+			Target: &c.flagJson,
+			Usage:  "Output the status as JSON.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "verbose",
+			Aliases: []string{"V"},
+			Target:  &c.flagVerbose,
+			Usage:   "Show the health and resource usage of each instance.",
+		})
+
+		initIdFormat(f, &c.flagId)
+	})
+}
+
+func (c *StatusCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *StatusCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *StatusCommand) Synopsis() string {
+	return "Show the status of apps."
+}
+
+func (c *StatusCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint status [options] [PROJECT[/APP]]
+
+  Show the status of apps in the current workspace.
+
+  For each app, this shows the latest deployment and release, how many
+  instances of the app are connected to the server, and the health of the
+  app based on those. The health is "ready" if the latest deployment
+  succeeded and is released. If the app has a health check, the health is
+  "starting" or "unhealthy" if any instance reports that state.
+
+  With -verbose, this also shows the health and resource usage that each
+  instance reports, such as its CPU and memory usage, restarts and uptime.
+
+  Without arguments, this shows the apps of the project in the current
+  directory, or the apps of every project outside of a project directory.
+
+` + c.Flags().Help())
+}
@@ -4,18 +4,24 @@ import (
 	"strings"
 	"time"
 
-	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/clierrors"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
 )
 
 type GetInviteCommand struct {
 	*baseCommand
 
-	duration time.Duration
+	duration         time.Duration
+	flagName         string
+	flagScope        string
+	flagVerb         string
+	flagRunner       bool
+	flagOrganization string
 }
 
 func (c *GetInviteCommand) Run(args []string) int {
@@ -28,11 +34,22 @@ func (c *GetInviteCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Restrict the token if any of the permission flags are set
+	perms, err := tokenPermissions(c.flagScope, c.flagVerb)
+	if err != nil {
+		c.project.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
 	// Get our API client
 	client := c.project.Client()
 
 	resp, err := client.GenerateInviteToken(c.Ctx, &pb.InviteTokenRequest{
-		Duration: c.duration.String(),
+		Duration:     c.duration.String(),
+		Permissions:  perms,
+		Name:         c.flagName,
+		Runner:       c.flagRunner,
+		Organization: c.flagOrganization,
 	})
 	if err != nil {
 		c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
@@ -52,6 +69,8 @@ func (c *GetInviteCommand) Flags() *flag.Sets {
 			Usage:   "How long the invite token will valid for, starting now.",
 			Default: 5 * time.Minute,
 		})
+
+		tokenFlags(f, &c.flagName, &c.flagScope, &c.flagVerb, &c.flagRunner, &c.flagOrganization)
 	})
 }
 
@@ -73,6 +92,10 @@ Usage: waypoint token invite [options]
 
   Request a new invite token. This token can be exchanged for a normal token to login.
 
+  The token has the same permissions as your token. It can be restricted
+  further with the -scope and -verb flags, but it can't have
+  more access than your token. Use -runner for tokens that runners use.
+
 ` + c.Flags().Help())
 }
 
@@ -149,6 +172,13 @@ Usage: waypoint token exchange [options]
 
 type GetTokenCommand struct {
 	*baseCommand
+
+	flagLifetime     time.Duration
+	flagName         string
+	flagScope        string
+	flagVerb         string
+	flagRunner       bool
+	flagOrganization string
 }
 
 func (c *GetTokenCommand) Run(args []string) int {
@@ -161,10 +191,27 @@ func (c *GetTokenCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Restrict the token if any of the permission flags are set
+	perms, err := tokenPermissions(c.flagScope, c.flagVerb)
+	if err != nil {
+		c.project.UI.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	req := &pb.LoginTokenRequest{
+		Name:         c.flagName,
+		Permissions:  perms,
+		Runner:       c.flagRunner,
+		Organization: c.flagOrganization,
+	}
+	if c.flagLifetime > 0 {
+		req.Duration = c.flagLifetime.String()
+	}
+
 	// Get our API client
 	client := c.project.Client()
 
-	resp, err := client.GenerateLoginToken(c.Ctx, &empty.Empty{})
+	resp, err := client.GenerateLoginToken(c.Ctx, req)
 	if err != nil {
 		c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 		return 1
@@ -175,7 +222,17 @@ func (c *GetTokenCommand) Run(args []string) int {
 }
 
 func (c *GetTokenCommand) Flags() *flag.Sets {
-	return c.flagSet(0, func(set *flag.Sets) {})
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.DurationVar(&flag.DurationVar{
+			Name:   "lifetime",
+			Target: &c.flagLifetime,
+			Usage: "How long the token will be valid for, starting now. " +
+				"If this isn't set, the token is valid for the login token TTL of the server.",
+		})
+
+		tokenFlags(f, &c.flagName, &c.flagScope, &c.flagVerb, &c.flagRunner, &c.flagOrganization)
+	})
 }
 
 func (c *GetTokenCommand) AutocompleteArgs() complete.Predictor {
@@ -196,7 +253,263 @@ Usage: waypoint token new [options]
 
   Request a new token to log into the server.
 
+  The token has the same permissions as your token. It can be restricted
+  further with the -scope and -verb flags, but it can't have more access
+  than your token. Give tokens a name with -name so that they can be
+  found with "waypoint token list" and revoked if they leak.
+
 ` + c.Flags().Help()
 
 	return strings.TrimSpace(helpText)
 }
+
+type TokenListCommand struct {
+	*baseCommand
+
+	flagEntrypoint bool
+	flagJson       bool
+}
+
+func (c *TokenListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().ListTokens(c.Ctx, &pb.ListTokensRequest{
+		IncludeEntrypoint: c.flagEntrypoint,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range resp.Tokens {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	formatTime := func(t time.Time, err error) string {
+		if err != nil {
+			return ""
+		}
+
+		return t.Local().Format("2006-01-02 15:04")
+	}
+
+	table := terminal.NewTable("ID", "Name", "User", "Type", "Created", "Expires", "Permissions", "Organization")
+	for _, t := range resp.Tokens {
+		var kind []string
+		if t.Invite {
+			kind = append(kind, "invite")
+		}
+		switch {
+		case t.Entrypoint:
+			kind = append(kind, "entrypoint")
+		case t.Runner:
+			kind = append(kind, "runner")
+		case !t.Invite:
+			kind = append(kind, "login")
+		}
+
+		expires := formatTime(ptypes.Timestamp(t.ValidUntil))
+		var colors []string
+		if t.RevokeTime != nil {
+			expires = "revoked " + formatTime(ptypes.Timestamp(t.RevokeTime))
+			colors = []string{"", "", "", "", "", terminal.Red, "", ""}
+		}
+
+		var perms []string
+		for _, p := range t.Permissions {
+			perms = append(perms, formatPermission(p))
+		}
+
+		table.Rich([]string{
+			t.Id,
+			t.Name,
+			t.User,
+			strings.Join(kind, " "),
+			formatTime(ptypes.Timestamp(t.CreateTime)),
+			expires,
+			strings.Join(perms, ", "),
+			t.Organization,
+		}, colors)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *TokenListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "entrypoint",
+			Target: &c.flagEntrypoint,
+			Usage:  "Include the tokens of deployment entrypoints.",
+		})
+
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *TokenListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TokenListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *TokenListCommand) Synopsis() string {
+	return "List the tokens created by the server."
+}
+
+func (c *TokenListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint token list [options]
+
+  List the tokens created by the server, oldest first.
+
+  The tokens themselves are never shown, only their ID and what they can
+  access. Use the ID with "waypoint token revoke" to revoke a token. This
+  requires admin permission.
+
+` + c.Flags().Help())
+}
+
+type TokenRevokeCommand struct {
+	*baseCommand
+}
+
+func (c *TokenRevokeCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// Token IDs are much shorter than tokens so we accept either.
+	req := &pb.RevokeTokenRequest{
+		Target: &pb.RevokeTokenRequest_Id{Id: c.args[0]},
+	}
+	if len(c.args[0]) > tokenIdMaxLen {
+		req.Target = &pb.RevokeTokenRequest_Token{Token: c.args[0]}
+	}
+
+	if _, err := c.project.Client().RevokeToken(c.Ctx, req); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Token revoked.", terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *TokenRevokeCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *TokenRevokeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TokenRevokeCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *TokenRevokeCommand) Synopsis() string {
+	return "Revoke a token."
+}
+
+func (c *TokenRevokeCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint token revoke [options] ID
+
+  Revoke a token so that the server no longer accepts it.
+
+  ID is the ID of the token from "waypoint token list" or the token itself.
+  You can always revoke your own token, revoking any other token requires
+  admin permission. Revoking an invite token doesn't revoke the tokens that
+  it was already exchanged for.
+
+`)
+}
+
+// tokenIdMaxLen is the maximum length of a token ID. Longer arguments are
+// treated as the token itself.
+const tokenIdMaxLen = 32
+
+// tokenFlags adds the flags that restrict new tokens to f.
+func tokenFlags(f *flag.Set, name, scope, verb *string, runner *bool, org *string) {
+	f.StringVar(&flag.StringVar{
+		Name:   "name",
+		Target: name,
+		Usage:  "A name that describes what the token is for.",
+	})
+
+	f.StringVar(&flag.StringVar{
+		Name:   "scope",
+		Target: scope,
+		Usage: "Restrict the token to a project or workspace in the form " +
+			"PROJECT/WORKSPACE. Either part can be \"*\" for all.",
+	})
+
+	f.StringVar(&flag.StringVar{
+		Name:   "verb",
+		Target: verb,
+		Usage: "Restrict the token to this verb: read, deploy or admin. " +
+			"This defaults to admin if only -scope is set.",
+	})
+
+	f.BoolVar(&flag.BoolVar{
+		Name:   "runner",
+		Target: runner,
+		Usage:  "Restrict the token to the APIs that runners use.",
+	})
+
+	f.StringVar(&flag.StringVar{
+		Name:   "organization",
+		Target: org,
+		Usage: "Restrict the token to the projects of this organization. Tokens " +
+			"created by callers in an organization are always in that organization.",
+	})
+}
+
+// tokenPermissions returns the permissions that a new token is restricted
+// to by the -scope and -verb flags, if any.
+func tokenPermissions(scope, verb string) ([]*pb.Permission, error) {
+	if scope == "" && verb == "" {
+		return nil, nil
+	}
+	if verb == "" {
+		verb = "admin"
+	}
+
+	perm, err := parsePermission(verb, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*pb.Permission{perm}, nil
+}
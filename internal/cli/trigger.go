@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type TriggerCreateCommand struct {
+	*baseCommand
+
+	flagId        string
+	flagName      string
+	flagSchedule  string
+	flagOperation string
+	flagRef       string
+}
+
+func (c *TriggerCreateCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithSingleApp(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 0 || c.flagSchedule == "" {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	op, ok := pb.Trigger_Operation_value[strings.ToUpper(c.flagOperation)]
+	if !ok || op == int32(pb.Trigger_UNKNOWN) {
+		c.ui.Output("Invalid operation %q. Must be one of: build, deploy, up.",
+			c.flagOperation, terminal.WithErrorStyle())
+		return 1
+	}
+
+	t := &pb.Trigger{
+		Id:          c.flagId,
+		Name:        c.flagName,
+		Application: c.refApp,
+		Workspace:   c.refWorkspace,
+		Schedule:    c.flagSchedule,
+		Operation:   pb.Trigger_Operation(op),
+	}
+	if c.flagRef != "" {
+		t.DataSourceOverrides = map[string]string{"ref": c.flagRef}
+	}
+
+	resp, err := c.project.Client().UpsertTrigger(c.Ctx, &pb.UpsertTriggerRequest{
+		Trigger: t,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	var next string
+	if ts, err := ptypes.Timestamp(resp.Trigger.NextTime); err == nil {
+		next = ts.Local().Format("2006-01-02 15:04")
+	}
+
+	c.ui.Output("Trigger %s saved. The next run is at %s.", resp.Trigger.Id, next,
+		terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *TriggerCreateCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "schedule",
+			Target: &c.flagSchedule,
+			Usage: "Cron expression of when to run, such as \"0 2 * * *\" for every " +
+				"night at 2am UTC. Shortcuts such as \"@daily\" are also supported. " +
+				"This is required.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "op",
+			Target:  &c.flagOperation,
+			Default: "up",
+			Usage:   "Operation to run. One of: build, deploy, up.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "name",
+			Target: &c.flagName,
+			Usage:  "Name of the trigger to show in the trigger list.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "ref",
+			Target: &c.flagRef,
+			Usage: "Git ref to use for the jobs, such as a branch name. This " +
+				"overrides the ref of the project data source.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "id",
+			Target: &c.flagId,
+			Usage:  "ID of an existing trigger to update instead of creating a new one.",
+		})
+	})
+}
+
+func (c *TriggerCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TriggerCreateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *TriggerCreateCommand) Synopsis() string {
+	return "Run an operation for the app on a schedule."
+}
+
+func (c *TriggerCreateCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint trigger create [options]
+
+  Create a trigger that runs an operation for the app and workspace on a
+  schedule.
+
+  The server queues the jobs of the trigger on a remote runner, so the
+  project must have a data source such as Git. Runs that are missed while
+  the server is down are started once when the server starts again.
+
+  The schedule is a cron expression in UTC. For example, to build and
+  deploy the "main" branch every night:
+
+    waypoint trigger create -schedule="0 2 * * *" -op=up -ref=main
+
+` + c.Flags().Help())
+}
+
+type TriggerListCommand struct {
+	*baseCommand
+
+	flagProject string
+	flagJson    bool
+}
+
+func (c *TriggerListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithConfig(true),
+	); err != nil {
+		return 1
+	}
+
+	// List the triggers of the project we're in unless one is given.
+	req := &pb.ListTriggersRequest{}
+	if c.flagProject != "" {
+		req.Project = &pb.Ref_Project{Project: c.flagProject}
+	} else if c.refProject != nil {
+		req.Project = c.refProject
+	}
+
+	resp, err := c.project.Client().ListTriggers(c.Ctx, req)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range resp.Triggers {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	table := terminal.NewTable(
+		"ID", "Name", "App", "Workspace", "Schedule", "Operation", "Next Run", "Last Job", "Error")
+	for _, t := range resp.Triggers {
+		var next string
+		if ts, err := ptypes.Timestamp(t.NextTime); err == nil {
+			next = ts.Local().Format("2006-01-02 15:04")
+		}
+
+		var colors []string
+		if t.LastError != "" {
+			colors = []string{"", "", "", "", "", "", "", "", terminal.Red}
+		}
+
+		table.Rich([]string{
+			t.Id,
+			t.Name,
+			t.Application.GetApplication(),
+			t.Workspace.GetWorkspace(),
+			t.Schedule,
+			strings.ToLower(t.Operation.String()),
+			next,
+			t.LastJobId,
+			t.LastError,
+		}, colors)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *TriggerListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage:      "Only list the triggers of this project.",
+		})
+
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *TriggerListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TriggerListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *TriggerListCommand) Synopsis() string {
+	return "List the triggers and the result of their last run."
+}
+
+func (c *TriggerListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint trigger list [options]
+
+  List the triggers and the result of their last run. The triggers of the
+  project in the current directory are listed unless -project is set.
+
+` + c.Flags().Help())
+}
+
+type TriggerDeleteCommand struct {
+	*baseCommand
+}
+
+func (c *TriggerDeleteCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	_, err := c.project.Client().DeleteTrigger(c.Ctx, &pb.DeleteTriggerRequest{
+		Id: c.args[0],
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Trigger %s deleted.", c.args[0], terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *TriggerDeleteCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *TriggerDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TriggerDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *TriggerDeleteCommand) Synopsis() string {
+	return "Delete a trigger."
+}
+
+func (c *TriggerDeleteCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint trigger delete [options] ID
+
+  Delete a trigger. Jobs that the trigger already queued aren't canceled.
+
+`)
+}
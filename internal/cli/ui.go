@@ -2,6 +2,9 @@ package cli
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,14 +12,22 @@ import (
 	"github.com/hashicorp/waypoint/internal/clierrors"
 	"github.com/hashicorp/waypoint/internal/pkg/flag"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/serverclient"
 	"github.com/posener/complete"
 	"github.com/skratchdot/open-golang/open"
 )
 
+// uiDefaultPort is the HTTP port that the server installs listen on.
+const uiDefaultPort = 9702
+
 type UICommand struct {
 	*baseCommand
 
 	flagAuthenticate bool
+	flagAddress      string
+	flagPortForward  bool
+	flagListen       string
+	flagOpen         bool
 }
 
 func (c *UICommand) Run(args []string) int {
@@ -31,6 +42,7 @@ func (c *UICommand) Run(args []string) int {
 
 	if c.project.Local() {
 		c.project.UI.Output("Waypoint must be configured in server mode to access the UI", terminal.WithWarningStyle())
+		return 1
 	}
 
 	// Get our API client
@@ -52,37 +64,65 @@ func (c *UICommand) Run(args []string) int {
 		inviteToken = resp.Token
 	}
 
-	// Get our default context (used context)
-	name, err := c.contextStorage.Default()
-	if err != nil {
-		c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
-		return 1
+	var ln net.Listener
+	uiAddr := c.flagAddress
+	if c.flagPortForward {
+		var err error
+		ln, err = net.Listen("tcp", c.flagListen)
+		if err != nil {
+			c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+		defer ln.Close()
+
+		uiAddr = "http://" + ln.Addr().String()
+	} else if uiAddr == "" {
+		// The HTTP address of the server isn't known to the client, so we
+		// assume the default HTTP port on the host of the context.
+		host := c.clientContext.Server.Address
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		uiAddr = fmt.Sprintf("https://%s", net.JoinHostPort(host, strconv.Itoa(uiDefaultPort)))
 	}
+	uiAddr = strings.TrimSuffix(uiAddr, "/")
 
-	ctxConfig, err := c.contextStorage.Load(name)
-	if err != nil {
-		c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
-		return 1
+	if c.flagAuthenticate {
+		uiAddr = fmt.Sprintf("%s/auth/invite?token=%s&cli=true", uiAddr, url.QueryEscape(inviteToken))
+	}
+
+	if c.flagOpen {
+		c.ui.Output("Opening browser", terminal.WithStyle(terminal.HeaderStyle))
+		if err := open.Run(uiAddr); err != nil {
+			c.ui.Output("Unable to open the browser: %s", clierrors.Humanize(err),
+				terminal.WithWarningStyle())
+		}
 	}
+	c.ui.Output("UI address: %s", uiAddr, terminal.WithInfoStyle())
 
-	// todo(mitchellh: current default port is hardcoded, cannot configure http address)
-	addr := strings.Split(ctxConfig.Server.Address, ":")[0]
-	// Default Docker platform HTTP port, for now
-	port := 9702
+	if ln == nil {
+		return 0
+	}
+
+	// Forward the UI over a connection of our own since the connection of
+	// the project is closed with the project.
+	conn, err := serverclient.Connect(c.Ctx,
+		serverclient.FromContextConfig(c.clientContext),
+		serverclient.FromEnv(),
+	)
 	if err != nil {
 		c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 		return 1
 	}
+	defer conn.Close()
 
-	c.ui.Output("Opening browser", terminal.WithStyle(terminal.HeaderStyle))
-
-	uiAddr := fmt.Sprintf("https://%s:%d", addr, port)
-	if c.flagAuthenticate {
-		uiAddr = fmt.Sprintf("%s/auth/invite?token=%s&cli=true", uiAddr, inviteToken)
+	c.ui.Output("Forwarding the UI to the server. Press Ctrl-C to stop.", terminal.WithInfoStyle())
+	if err := uiForward(c.Ctx, c.Log, conn, ln); err != nil {
+		c.project.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
 	}
 
-	open.Run(uiAddr)
-
 	return 0
 }
 
@@ -93,10 +133,39 @@ func (c *UICommand) Flags() *flag.Sets {
 		f.BoolVar(&flag.BoolVar{
 			Name:    "authenticate",
 			Target:  &c.flagAuthenticate,
-			Default: false,
-			Usage:   "Creates a new invite token and passes it to the UI for authorization",
+			Default: true,
+			Usage: "Create a short-lived invite token and pass it to the UI so the " +
+				"browser is logged in without copying a token.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "address",
+			Target: &c.flagAddress,
+			Usage: "HTTP address of the UI, such as https://waypoint.example.com:9702. " +
+				"This defaults to the host of the server address on port 9702.",
 		})
 
+		f.BoolVar(&flag.BoolVar{
+			Name:   "port-forward",
+			Target: &c.flagPortForward,
+			Usage: "Serve the UI locally and forward its requests to the server over " +
+				"the gRPC connection of the CLI. Use this when the HTTP address of the " +
+				"server isn't reachable, such as on a private network.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "listen",
+			Target:  &c.flagListen,
+			Default: "127.0.0.1:0",
+			Usage:   "Address to serve the UI on with -port-forward. The default picks a free port.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:    "open",
+			Target:  &c.flagOpen,
+			Default: true,
+			Usage:   "Open the UI in the browser.",
+		})
 	})
 }
 
@@ -116,8 +185,16 @@ func (c *UICommand) Help() string {
 	return formatHelp(`
 Usage: waypoint ui [options]
 
-  Opens the new UI. When provided a flag, will automatically open the
-  token invite page with an invite token for authentication.
+  Open the web UI in the browser.
+
+  By default, this creates an invite token that is valid for two minutes
+  and opens the invite page with it, so the browser logs in without a
+  token being copied.
+
+  With -port-forward, the CLI serves the UI itself and forwards its
+  requests to the server over the same connection as other commands. This
+  works for servers whose HTTP address isn't reachable, such as on a
+  private network. The command runs until it is interrupted.
 
 ` + c.Flags().Help())
 }
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	assetfs "github.com/elazarl/go-bindata-assetfs"
+	"github.com/hashicorp/go-hclog"
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// uiForward serves the web UI embedded in the CLI on the listener and
+// forwards its API requests to the server over conn. This lets the UI be
+// used when only the gRPC address of the server is reachable, such as for
+// servers on private networks. This blocks until ctx is done.
+func uiForward(ctx context.Context, log hclog.Logger, conn *grpc.ClientConn, ln net.Listener) error {
+	// The local gRPC server has no services of its own. Every call is
+	// forwarded as raw frames to the server.
+	grpcServer := grpc.NewServer(
+		grpc.CustomCodec(uiForwardCodec{}),
+		grpc.UnknownServiceHandler(uiForwardHandler(conn)),
+	)
+	defer grpcServer.Stop()
+
+	grpcWrapped := grpcweb.WrapServer(grpcServer,
+		grpcweb.WithCorsForRegisteredEndpointsOnly(false),
+		grpcweb.WithOriginFunc(func(string) bool { return true }),
+		grpcweb.WithAllowNonRootResource(true),
+	)
+
+	uifs := http.FileServer(&assetfs.AssetFS{
+		Asset:     gen.Asset,
+		AssetDir:  gen.AssetDir,
+		AssetInfo: gen.AssetInfo,
+		Prefix:    "ui/dist",
+		Fallback:  "index.html",
+	})
+
+	httpSrv := &http.Server{
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/grpc") {
+				grpcWrapped.ServeHTTP(w, r)
+				return
+			}
+
+			uifs.ServeHTTP(w, r)
+		}),
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("serving the UI", "addr", ln.Addr().String())
+		errCh <- httpSrv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		httpSrv.Shutdown(shutdownCtx)
+		return nil
+	}
+}
+
+// uiForwardHandler returns a stream handler that forwards the call to conn.
+func uiForwardHandler(conn *grpc.ClientConn) grpc.StreamHandler {
+	return func(srv interface{}, ss grpc.ServerStream) error {
+		method, ok := grpc.MethodFromServerStream(ss)
+		if !ok {
+			return fmt.Errorf("unable to determine the method of the request")
+		}
+
+		// Forward the metadata except for the token. The connection has the
+		// token of the CLI so the UI is authenticated the same way.
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		md = md.Copy()
+		md.Delete("authorization")
+
+		ctx, cancel := context.WithCancel(ss.Context())
+		defer cancel()
+
+		cs, err := conn.NewStream(
+			metadata.NewOutgoingContext(ctx, md),
+			&grpc.StreamDesc{ServerStreams: true, ClientStreams: true},
+			method,
+			grpc.ForceCodec(uiForwardCodec{}),
+		)
+		if err != nil {
+			return err
+		}
+
+		// Send the requests from the UI to the server
+		go func() {
+			for {
+				var frame uiForwardFrame
+				if err := ss.RecvMsg(&frame); err != nil {
+					if err != io.EOF {
+						cancel()
+					}
+
+					cs.CloseSend()
+					return
+				}
+
+				if err := cs.SendMsg(&frame); err != nil {
+					return
+				}
+			}
+		}()
+
+		// Send the responses from the server to the UI
+		header, err := cs.Header()
+		if err != nil {
+			return err
+		}
+		if err := ss.SendHeader(header); err != nil {
+			return err
+		}
+
+		for {
+			var frame uiForwardFrame
+			if err := cs.RecvMsg(&frame); err != nil {
+				ss.SetTrailer(cs.Trailer())
+				if err == io.EOF {
+					return nil
+				}
+
+				return err
+			}
+
+			if err := ss.SendMsg(&frame); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// uiForwardFrame is a message that is forwarded without being decoded.
+type uiForwardFrame struct {
+	payload []byte
+}
+
+// uiForwardCodec is a codec that passes uiForwardFrame payloads through.
+type uiForwardCodec struct{}
+
+func (uiForwardCodec) Marshal(v interface{}) ([]byte, error) {
+	frame, ok := v.(*uiForwardFrame)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+
+	return frame.payload, nil
+}
+
+func (uiForwardCodec) Unmarshal(data []byte, v interface{}) error {
+	frame, ok := v.(*uiForwardFrame)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+
+	frame.payload = append([]byte(nil), data...)
+	return nil
+}
+
+func (uiForwardCodec) Name() string {
+	return "proto"
+}
+
+func (c uiForwardCodec) String() string {
+	return c.Name()
+}
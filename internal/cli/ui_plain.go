@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+// plainTimeFormat is the format of the timestamp at the start of each line
+// of plain output.
+const plainTimeFormat = time.RFC3339
+
+// plainUI is the terminal.UI for plain output, such as in CI. Every message
+// is written as timestamped lines, and statuses and steps are written as a
+// new line each time they change rather than redrawn with a spinner. This
+// keeps the output complete and readable in logs.
+type plainUI struct {
+	mu     sync.Mutex
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// newPlainUI returns a plainUI that writes to stdout and stderr.
+func newPlainUI() *plainUI {
+	return &plainUI{
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+}
+
+func (u *plainUI) Input(input *terminal.Input) (string, error) {
+	return "", terminal.ErrNonInteractive
+}
+
+func (u *plainUI) Interactive() bool {
+	return false
+}
+
+// Output writes each line of the message with a timestamp. Headers are
+// prefixed so that they stand out, and errors are written to stderr.
+func (u *plainUI) Output(msg string, raw ...interface{}) {
+	msg, style, w := terminal.Interpret(msg, raw...)
+
+	prefix := ""
+	switch style {
+	case terminal.HeaderStyle:
+		prefix = "==> "
+	case terminal.ErrorStyle, terminal.ErrorBoldStyle:
+		prefix = "! "
+		if w == nil {
+			w = u.stderr
+		}
+	case terminal.WarningStyle, terminal.WarningBoldStyle:
+		prefix = "warning: "
+	}
+	if w == nil {
+		w = u.stdout
+	}
+
+	u.lines(w, prefix, msg)
+}
+
+func (u *plainUI) NamedValues(values []terminal.NamedValue, opts ...terminal.Option) {
+	var buf bytes.Buffer
+	tr := tabwriter.NewWriter(&buf, 1, 8, 0, ' ', tabwriter.AlignRight)
+	for _, nv := range values {
+		fmt.Fprintf(tr, "  %s: \t%s\n", nv.Name, fmt.Sprintf("%v", nv.Value))
+	}
+	tr.Flush()
+
+	u.Output(strings.TrimRight(buf.String(), "\n"), opts...)
+}
+
+// OutputWriters returns writers that add a timestamp to each line.
+func (u *plainUI) OutputWriters() (stdout io.Writer, stderr io.Writer, err error) {
+	return u.writer(u.stdout, ""), u.writer(u.stderr, ""), nil
+}
+
+func (u *plainUI) Table(tbl *terminal.Table, opts ...terminal.Option) {
+	var buf bytes.Buffer
+	tr := tabwriter.NewWriter(&buf, 1, 8, 2, ' ', 0)
+	if len(tbl.Headers) > 0 {
+		fmt.Fprintln(tr, strings.Join(tbl.Headers, "\t"))
+	}
+	for _, row := range tbl.Rows {
+		values := make([]string, len(row))
+		for i, ent := range row {
+			values[i] = ent.Value
+		}
+
+		fmt.Fprintln(tr, strings.Join(values, "\t"))
+	}
+	tr.Flush()
+
+	u.Output(strings.TrimRight(buf.String(), "\n"), opts...)
+}
+
+func (u *plainUI) Status() terminal.Status {
+	return &plainStatus{ui: u}
+}
+
+func (u *plainUI) StepGroup() terminal.StepGroup {
+	return &plainStepGroup{ui: u}
+}
+
+// lines writes each line of msg to w with a timestamp and the prefix.
+func (u *plainUI) lines(w io.Writer, prefix, msg string) {
+	ts := time.Now().Format(plainTimeFormat)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, line := range strings.Split(msg, "\n") {
+		fmt.Fprintf(w, "%s %s%s\n", ts, prefix, line)
+	}
+}
+
+// writer returns a writer that writes each complete line to w with a
+// timestamp and the prefix.
+func (u *plainUI) writer(w io.Writer, prefix string) *plainWriter {
+	return &plainWriter{ui: u, w: w, prefix: prefix}
+}
+
+// plainWriter buffers writes and writes them as lines with plainUI.lines.
+type plainWriter struct {
+	ui     *plainUI
+	w      io.Writer
+	prefix string
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *plainWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	if i := bytes.LastIndexByte(w.buf, '\n'); i >= 0 {
+		w.ui.lines(w.w, w.prefix, string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes any partial line that is left.
+func (w *plainWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.ui.lines(w.w, w.prefix, string(w.buf))
+		w.buf = nil
+	}
+}
+
+// plainStatus writes a line for each status update that changes the message.
+type plainStatus struct {
+	ui  *plainUI
+	msg string
+}
+
+func (s *plainStatus) Update(msg string) {
+	if msg == s.msg {
+		return
+	}
+
+	s.msg = msg
+	s.ui.lines(s.ui.stdout, "", msg)
+}
+
+func (s *plainStatus) Step(status string, msg string) {
+	s.msg = ""
+	s.ui.lines(s.ui.stdout, "["+status+"] ", msg)
+}
+
+func (s *plainStatus) Close() error {
+	return nil
+}
+
+// plainStepGroup writes a line for each step as it starts and finishes.
+// Steps may run in parallel, so the output of steps is indented and each
+// line is written as a whole.
+type plainStepGroup struct {
+	ui *plainUI
+	wg sync.WaitGroup
+}
+
+func (g *plainStepGroup) Add(str string, args ...interface{}) terminal.Step {
+	g.wg.Add(1)
+
+	step := &plainStep{group: g}
+	step.Update(str, args...)
+	return step
+}
+
+func (g *plainStepGroup) Wait() {
+	g.wg.Wait()
+}
+
+type plainStep struct {
+	group  *plainStepGroup
+	msg    string
+	status string
+	out    *plainWriter
+	done   bool
+}
+
+func (s *plainStep) TermOutput() io.Writer {
+	if s.out == nil {
+		s.out = s.group.ui.writer(s.group.ui.stdout, "  ")
+	}
+
+	return s.out
+}
+
+func (s *plainStep) Update(str string, args ...interface{}) {
+	msg := fmt.Sprintf(str, args...)
+	if msg == s.msg {
+		return
+	}
+
+	s.msg = msg
+	s.group.ui.lines(s.group.ui.stdout, "", msg)
+}
+
+func (s *plainStep) Status(status string) {
+	s.status = status
+}
+
+func (s *plainStep) Done() {
+	if s.done {
+		return
+	}
+
+	status := s.status
+	if status == "" {
+		status = terminal.StatusOK
+	}
+
+	s.finish(status)
+}
+
+func (s *plainStep) Abort() {
+	if s.done {
+		return
+	}
+
+	s.finish(terminal.StatusAbort)
+}
+
+func (s *plainStep) finish(status string) {
+	s.done = true
+	if s.out != nil {
+		s.out.Flush()
+	}
+
+	s.group.ui.lines(s.group.ui.stdout, "["+status+"] ", s.msg)
+	s.group.wg.Done()
+}
+
+var (
+	_ terminal.UI        = (*plainUI)(nil)
+	_ terminal.Status    = (*plainStatus)(nil)
+	_ terminal.StepGroup = (*plainStepGroup)(nil)
+	_ terminal.Step      = (*plainStep)(nil)
+)
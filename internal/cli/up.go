@@ -2,7 +2,13 @@ package cli
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/posener/complete"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	clientpkg "github.com/hashicorp/waypoint/internal/client"
@@ -13,22 +19,180 @@ import (
 
 type UpCommand struct {
 	*baseCommand
+
+	flagWatch        bool
+	flagWatchDelay   time.Duration
+	flagDryRun       bool
+	flagSkipBuild    bool
+	flagFromArtifact string
+	flagOnly         []string
+
+	// stages are the stages to run, from the flags.
+	stages map[string]bool
 }
 
+// upStages are the stages of up in the order they run.
+var upStages = []string{"build", "deploy", "release"}
+
 func (c *UpCommand) Run(args []string) int {
 	// Initialize. If we fail, we just exit since Init handles the UI.
 	if err := c.Init(
 		WithArgs(args),
 		WithFlags(c.Flags()),
-		WithSingleApp(),
+		WithMultiApp(),
 	); err != nil {
 		return 1
 	}
 
+	if c.flagWatch && c.flagRemote {
+		c.ui.Output("The -watch flag can't be used with remote runners since they "+
+			"don't run with the local files.", terminal.WithErrorStyle())
+		return 1
+	}
+
+	if err := c.initStages(); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if err := c.checkDependencies(); err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	ret := c.upApps()
+	if !c.flagWatch {
+		return ret
+	}
+
+	// Watch the directory of the configuration, which is the root of the
+	// project. The runner reads the configuration and files for every job
+	// so changes to waypoint.hcl are picked up too.
+	path, err := c.initConfigPath()
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+	root := filepath.Dir(path)
+
+	c.ui.Output("Watching %s for changes. Press Ctrl-C to stop.", root, terminal.WithHeaderStyle())
+	err = watchSource(c.Ctx, c.Log.Named("watch"), root, c.flagWatchDelay, func(changed []string) {
+		c.ui.Output("")
+		c.ui.Output("Changes detected: %s", watchSummary(changed), terminal.WithHeaderStyle())
+		c.upApps()
+		c.ui.Output("Watching %s for changes. Press Ctrl-C to stop.", root, terminal.WithHeaderStyle())
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	return 0
+}
+
+// upApps builds, deploys and releases the apps and returns the exit code.
+func (c *UpCommand) upApps() int {
+	err := c.DoApp(c.Ctx, c.up)
+	if err != nil {
+		if err != ErrSentinel {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		}
+
+		return 1
+	}
+
+	return 0
+}
+
+// initStages sets the stages to run from the flags and validates them.
+func (c *UpCommand) initStages() error {
+	c.stages = map[string]bool{}
+	if len(c.flagOnly) == 0 {
+		for _, s := range upStages {
+			c.stages[s] = true
+		}
+	}
+	for _, s := range c.flagOnly {
+		s = strings.ToLower(strings.TrimSpace(s))
+		valid := false
+		for _, v := range upStages {
+			valid = valid || s == v
+		}
+		if !valid {
+			return fmt.Errorf("Invalid stage %q for -only. Must be one of: %s.",
+				s, strings.Join(upStages, ", "))
+		}
+
+		c.stages[s] = true
+	}
+
+	if c.flagSkipBuild || c.flagFromArtifact != "" {
+		if len(c.flagOnly) > 0 && c.stages["build"] {
+			return fmt.Errorf("-only can't include build with -skip-build or -from-artifact.")
+		}
+
+		delete(c.stages, "build")
+	}
+	if c.flagFromArtifact != "" && !c.stages["deploy"] {
+		return fmt.Errorf("-from-artifact requires the deploy stage.")
+	}
+	if c.flagFromArtifact != "" && c.refApp == nil {
+		return fmt.Errorf("-from-artifact requires a single app. Specify it with -app.")
+	}
+	if c.flagDryRun && !c.stages["deploy"] {
+		return fmt.Errorf("-dry-run requires the deploy stage.")
+	}
+	if len(c.stages) == 0 {
+		return fmt.Errorf("No stages to run.")
+	}
+
+	return nil
+}
+
+// checkDependencies checks that the apps that the targeted app depends on
+// have been deployed in the workspace. This is only checked if a single app
+// is targeted with -app, since otherwise the dependencies are run first.
+func (c *UpCommand) checkDependencies() error {
+	if c.refApp == nil || c.cfg == nil {
+		return nil
+	}
+
+	appCfg, ok := c.cfg.AppConfig(c.refApp.Application)
+	if !ok {
+		return nil
+	}
+
+	for _, dep := range appCfg.DependsOn {
+		resp, err := c.project.Client().ListDeployments(c.Ctx, &pb.ListDeploymentsRequest{
+			Application: &pb.Ref_Application{
+				Project:     c.refApp.Project,
+				Application: dep,
+			},
+			Workspace:     c.project.WorkspaceRef(),
+			PhysicalState: pb.Operation_CREATED,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(resp.Deployments) == 0 {
+			return fmt.Errorf("App %q depends on app %q, which has no deployment in "+
+				"workspace %q. Run \"waypoint up -app %s\" first, or run \"waypoint up\" "+
+				"without -app to run all apps in dependency order.",
+				c.refApp.Application, dep, c.project.WorkspaceRef().Workspace, dep)
+		}
+	}
+
+	return nil
+}
+
+// up builds, deploys and releases an app. Only the stages from the flags
+// are run, and the stages that are skipped use the latest result.
+func (c *UpCommand) up(ctx context.Context, app *clientpkg.App) error {
 	client := c.project.Client()
 
-	err := c.DoApp(c.Ctx, func(ctx context.Context, app *clientpkg.App) error {
-		// Build it
+	// Build it
+	if c.stages["build"] {
 		app.UI.Output("Building...", terminal.WithHeaderStyle())
 
 		_, err := app.Build(ctx, &pb.Job_BuildOp{})
@@ -37,16 +201,36 @@ func (c *UpCommand) Run(args []string) int {
 			return ErrSentinel
 		}
 
-		// Get the most recent pushed artifact
-		push, err := client.GetLatestPushedArtifact(ctx, &pb.GetLatestPushedArtifactRequest{
-			Application: app.Ref(),
-			Workspace:   c.project.WorkspaceRef(),
-		})
+		if !c.stages["deploy"] && !c.stages["release"] {
+			return nil
+		}
+	}
+
+	var deployment *pb.Deployment
+	var deployUrl string
+	if c.stages["deploy"] {
+		push, err := c.artifact(ctx, app)
 		if err != nil {
 			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
 
+		// A dry run only shows what would be deployed
+		if c.flagDryRun {
+			app.UI.Output("Planning deploy...", terminal.WithHeaderStyle())
+			result, err := app.Deploy(ctx, &pb.Job_DeployOp{
+				Artifact: push,
+				DryRun:   true,
+			})
+			if err != nil {
+				app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+				return ErrSentinel
+			}
+
+			outputPlan(app.UI, result.Plan)
+			return nil
+		}
+
 		// Push it
 		app.UI.Output("Deploying...", terminal.WithHeaderStyle())
 
@@ -57,72 +241,166 @@ func (c *UpCommand) Run(args []string) int {
 			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
-		deployUrl := result.Deployment.Preload.DeployUrl
-
-		// Try to get the hostname
-		var hostname *pb.Hostname
-		hostnamesResp, err := client.ListHostnames(ctx, &pb.ListHostnamesRequest{
-			Target: &pb.Hostname_Target{
-				Target: &pb.Hostname_Target_Application{
-					Application: &pb.Hostname_TargetApp{
-						Application: result.Deployment.Application,
-						Workspace:   result.Deployment.Workspace,
-					},
-				},
-			},
-		})
-		if err == nil && len(hostnamesResp.Hostnames) > 0 {
-			hostname = hostnamesResp.Hostnames[0]
-		}
+		deployment = result.Deployment
+		deployUrl = deployment.Preload.DeployUrl
+
+		if !c.stages["release"] {
+			app.UI.Output("")
+			if deployUrl != "" {
+				app.UI.Output("Deployment URL: https://%s", deployUrl, terminal.WithSuccessStyle())
+			}
 
-		// We're releasing, do that too.
-		app.UI.Output("Releasing...", terminal.WithHeaderStyle())
-		releaseResult, err := app.Release(ctx, &pb.Job_ReleaseOp{
-			Deployment: result.Deployment,
-			Prune:      true,
+			return nil
+		}
+	} else {
+		// Release the latest deployment
+		resp, err := client.ListDeployments(ctx, &pb.ListDeploymentsRequest{
+			Application:   app.Ref(),
+			Workspace:     c.project.WorkspaceRef(),
+			PhysicalState: pb.Operation_CREATED,
+			Order: &pb.OperationOrder{
+				Limit: 1,
+				Order: pb.OperationOrder_COMPLETE_TIME,
+				Desc:  true,
+			},
 		})
 		if err != nil {
 			app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
 			return ErrSentinel
 		}
+		if len(resp.Deployments) == 0 {
+			app.UI.Output(strings.TrimSpace(releaseNoDeploys), terminal.WithErrorStyle())
+			return ErrSentinel
+		}
+		deployment = resp.Deployments[0]
+	}
+
+	// Try to get the hostname
+	var hostname *pb.Hostname
+	hostnamesResp, err := client.ListHostnames(ctx, &pb.ListHostnamesRequest{
+		Target: &pb.Hostname_Target{
+			Target: &pb.Hostname_Target_Application{
+				Application: &pb.Hostname_TargetApp{
+					Application: deployment.Application,
+					Workspace:   deployment.Workspace,
+				},
+			},
+		},
+	})
+	if err == nil && len(hostnamesResp.Hostnames) > 0 {
+		hostname = hostnamesResp.Hostnames[0]
+	}
 
-		releaseUrl := releaseResult.Release.Url
+	// We're releasing, do that too.
+	app.UI.Output("Releasing...", terminal.WithHeaderStyle())
+	releaseResult, err := app.Release(ctx, &pb.Job_ReleaseOp{
+		Deployment: deployment,
+		Prune:      true,
+	})
+	if err != nil {
+		app.UI.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return ErrSentinel
+	}
 
-		// Output
-		app.UI.Output("")
-		switch {
-		case releaseUrl != "":
-			app.UI.Output(strings.TrimSpace(deployURLService)+"\n", terminal.WithSuccessStyle())
-			app.UI.Output("   Release URL: %s", releaseUrl, terminal.WithSuccessStyle())
-			if deployUrl != "" {
-				app.UI.Output("Deployment URL: https://%s", deployUrl, terminal.WithSuccessStyle())
-			}
+	releaseUrl := releaseResult.Release.Url
 
-		case hostname != nil && deployUrl != "":
-			app.UI.Output(strings.TrimSpace(deployURLService)+"\n", terminal.WithSuccessStyle())
-			app.UI.Output("           URL: https://%s", hostname.Fqdn, terminal.WithSuccessStyle())
+	// Output
+	app.UI.Output("")
+	switch {
+	case releaseUrl != "":
+		app.UI.Output(strings.TrimSpace(deployURLService)+"\n", terminal.WithSuccessStyle())
+		app.UI.Output("   Release URL: %s", releaseUrl, terminal.WithSuccessStyle())
+		if deployUrl != "" {
 			app.UI.Output("Deployment URL: https://%s", deployUrl, terminal.WithSuccessStyle())
-
-		default:
-			app.UI.Output(strings.TrimSpace(deployNoURL)+"\n", terminal.WithSuccessStyle())
 		}
 
-		return nil
-	})
+	case hostname != nil && deployUrl != "":
+		app.UI.Output(strings.TrimSpace(deployURLService)+"\n", terminal.WithSuccessStyle())
+		app.UI.Output("           URL: https://%s", hostname.Fqdn, terminal.WithSuccessStyle())
+		app.UI.Output("Deployment URL: https://%s", deployUrl, terminal.WithSuccessStyle())
 
-	if err != nil {
-		if err != ErrSentinel {
-			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
-		}
+	default:
+		app.UI.Output(strings.TrimSpace(deployNoURL)+"\n", terminal.WithSuccessStyle())
+	}
 
-		return 1
+	return nil
+}
+
+// artifact returns the pushed artifact to deploy. This is the one given
+// with -from-artifact by ID or sequence number, or the latest one.
+func (c *UpCommand) artifact(ctx context.Context, app *clientpkg.App) (*pb.PushedArtifact, error) {
+	client := c.project.Client()
+	if c.flagFromArtifact == "" {
+		return client.GetLatestPushedArtifact(ctx, &pb.GetLatestPushedArtifactRequest{
+			Application: app.Ref(),
+			Workspace:   c.project.WorkspaceRef(),
+			Labels:      c.artifactLabels(app.Ref().Application),
+		})
 	}
 
-	return 0
+	ref := &pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: c.flagFromArtifact},
+	}
+	if v, err := strconv.ParseUint(c.flagFromArtifact, 10, 64); err == nil {
+		ref.Target = &pb.Ref_Operation_Sequence{
+			Sequence: &pb.Ref_OperationSeq{
+				Application: app.Ref(),
+				Number:      v,
+			},
+		}
+	}
+
+	return client.GetPushedArtifact(ctx, &pb.GetPushedArtifactRequest{Ref: ref})
 }
 
 func (c *UpCommand) Flags() *flag.Sets {
 	return c.flagSet(flagSetOperation, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.BoolVar(&flag.BoolVar{
+			Name:   "watch",
+			Target: &c.flagWatch,
+			Usage: "Watch the project for changes to the files and waypoint.hcl " +
+				"and run up again when they change, until interrupted.",
+		})
+
+		f.DurationVar(&flag.DurationVar{
+			Name:   "watch-delay",
+			Target: &c.flagWatchDelay,
+			Usage: "How long to wait for changes to stop with -watch before running " +
+				"up again. Changes during this time are batched together.",
+			Default: time.Second,
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "dry-run",
+			Target: &c.flagDryRun,
+			Usage: "Build the app and show the resources the deployment would " +
+				"create, without deploying or releasing.",
+		})
+
+		f.BoolVar(&flag.BoolVar{
+			Name:   "skip-build",
+			Target: &c.flagSkipBuild,
+			Usage:  "Don't build the app and deploy the latest pushed artifact.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "from-artifact",
+			Target: &c.flagFromArtifact,
+			Usage: "Deploy this pushed artifact, by ID or sequence number, instead of " +
+				"building the app.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "only",
+			Target: &c.flagOnly,
+			Usage: "Only run these stages, separated by commas. The stages are build, " +
+				"deploy and release. Skipped stages use the latest result, so a failed " +
+				"release can be run again with -only=release.",
+			Completion: complete.PredictSet(upStages...),
+		})
+
+		initParallelFlag(f, &c.flagParallel)
 	})
 }
 
@@ -136,5 +414,24 @@ Usage: waypoint up [options]
 
   Perform the build, deploy, and release steps for the app.
 
+  With -watch, this keeps running and performs the steps again whenever
+  files in the project change. Hidden files and directories, such as .git,
+  are ignored. This only works with local runners.
+
+  With -dry-run, the app is still built and pushed but only the resources
+  the deployment would create are shown. Nothing is deployed or released.
+
+  To resume after a stage failed, run only the stages from the one that
+  failed with -only, such as -only=deploy,release. Stages that are skipped
+  use the latest result: deploys use the latest pushed artifact and
+  releases use the latest deployment. An existing artifact can be deployed
+  without building with -from-artifact.
+
+  If the project has multiple apps and -app isn't given, all apps are
+  built, deployed and released. Apps start after the apps they depend on
+  with "depends_on", and with -parallel several apps run at once. If an app
+  fails, the apps that depend on it are skipped. With -app, the apps it
+  depends on must already be deployed in the workspace.
+
 ` + c.Flags().Help())
 }
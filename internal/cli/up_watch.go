@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/hashicorp/go-hclog"
+)
+
+// watchSource calls fn with the paths that changed under root, relative to
+// root, until ctx is done. Changes are batched: fn is called once no change
+// was made for delay. Changes made while fn runs are batched for the next
+// call to fn. Hidden files and directories, like .git and the .waypoint data
+// directory, are ignored.
+func watchSource(
+	ctx context.Context,
+	log hclog.Logger,
+	root string,
+	delay time.Duration,
+	fn func(changed []string),
+) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := watchDir(w, root); err != nil {
+		return err
+	}
+
+	changed := map[string]struct{}{}
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err := <-w.Errors:
+			log.Warn("error watching files", "err", err)
+
+		case ev := <-w.Events:
+			rel, err := filepath.Rel(root, ev.Name)
+			if err != nil || watchIgnored(rel) || ev.Op == fsnotify.Chmod {
+				continue
+			}
+
+			// Watches aren't recursive so new directories are added as
+			// they are created.
+			if ev.Op&fsnotify.Create != 0 {
+				if fi, err := os.Stat(ev.Name); err == nil && fi.IsDir() {
+					if err := watchDir(w, ev.Name); err != nil {
+						log.Warn("error watching directory", "path", ev.Name, "err", err)
+					}
+				}
+			}
+
+			log.Trace("file changed", "path", rel, "op", ev.Op.String())
+			changed[rel] = struct{}{}
+			timer = time.After(delay)
+
+		case <-timer:
+			timer = nil
+
+			paths := make([]string, 0, len(changed))
+			for p := range changed {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			changed = map[string]struct{}{}
+
+			fn(paths)
+		}
+	}
+}
+
+// watchDir adds dir and the directories under it to the watcher.
+func watchDir(w *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		return w.Add(path)
+	})
+}
+
+// watchIgnored returns true if changes to the relative path are ignored.
+func watchIgnored(rel string) bool {
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if strings.HasPrefix(part, ".") && part != "." {
+			return true
+		}
+	}
+
+	return false
+}
+
+// watchSummary returns a short summary of the changed paths for output.
+func watchSummary(changed []string) string {
+	const max = 3
+	if len(changed) <= max {
+		return strings.Join(changed, ", ")
+	}
+
+	return fmt.Sprintf("%s and %d more", strings.Join(changed[:max], ", "), len(changed)-max)
+}
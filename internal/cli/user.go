@@ -0,0 +1,329 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type UserListCommand struct {
+	*baseCommand
+
+	flagJson bool
+}
+
+func (c *UserListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	resp, err := c.project.Client().ListUsers(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range resp.Users {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	table := terminal.NewTable("ID", "Username", "Email", "Last Login", "Permissions", "Organization")
+	for _, user := range resp.Users {
+		var lastLogin string
+		if t, err := ptypes.Timestamp(user.LastLoginTime); err == nil {
+			lastLogin = t.Local().Format("2006-01-02 15:04")
+		}
+
+		var perms []string
+		for _, p := range user.Permissions {
+			perms = append(perms, formatPermission(p))
+		}
+
+		table.Rich([]string{
+			user.Id,
+			user.Username,
+			user.Email,
+			lastLogin,
+			strings.Join(perms, ", "),
+			user.Organization,
+		}, nil)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *UserListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *UserListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *UserListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *UserListCommand) Synopsis() string {
+	return "List all users and their permissions."
+}
+
+func (c *UserListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint user list [options]
+
+  List all users and their permissions.
+
+  Users are created the first time they log in with "waypoint login".
+  This requires admin permission for all projects.
+
+` + c.Flags().Help())
+}
+
+// UserPermissionCommand implements "user grant" and "user revoke".
+type UserPermissionCommand struct {
+	*baseCommand
+
+	// revoke is true to remove the permission instead of adding it.
+	revoke bool
+
+	flagOrganization string
+}
+
+func (c *UserPermissionCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) < 2 || len(c.args) > 3 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	var scope string
+	if len(c.args) > 2 {
+		scope = c.args[2]
+	}
+	perm, err := parsePermission(c.args[1], scope)
+	if err != nil {
+		c.ui.Output(err.Error(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	client := c.project.Client()
+	resp, err := client.ListUsers(c.Ctx, &empty.Empty{})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	// The user can be given by ID, username or email.
+	var user *pb.User
+	for _, u := range resp.Users {
+		if u.Id == c.args[0] || u.Username == c.args[0] || u.Email == c.args[0] {
+			if user != nil {
+				c.ui.Output("Multiple users match %q, use the ID of the user instead.",
+					c.args[0], terminal.WithErrorStyle())
+				return 1
+			}
+
+			user = u
+		}
+	}
+	if user == nil {
+		c.ui.Output("User not found: %s", c.args[0], terminal.WithErrorStyle())
+		return 1
+	}
+
+	var perms []*pb.Permission
+	var found bool
+	for _, p := range user.Permissions {
+		if strings.EqualFold(p.Project, perm.Project) &&
+			strings.EqualFold(p.Workspace, perm.Workspace) &&
+			p.Verb == perm.Verb {
+			found = true
+			if c.revoke {
+				continue
+			}
+		}
+
+		perms = append(perms, p)
+	}
+
+	switch {
+	case c.revoke && !found:
+		c.ui.Output("User %s doesn't have the permission %s.",
+			c.args[0], formatPermission(perm), terminal.WithErrorStyle())
+		return 1
+
+	case !c.revoke && found:
+		c.ui.Output("User %s already has the permission %s.",
+			c.args[0], formatPermission(perm), terminal.WithSuccessStyle())
+		return 0
+
+	case !c.revoke:
+		perms = append(perms, perm)
+	}
+
+	// The user stays in their organization unless another one is given.
+	org := user.Organization
+	if c.flagOrganization != "" {
+		org = c.flagOrganization
+	}
+
+	_, err = client.SetUserPermissions(c.Ctx, &pb.SetUserPermissionsRequest{
+		UserId:       user.Id,
+		Permissions:  perms,
+		Organization: org,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.revoke {
+		c.ui.Output("Revoked %s from %s.", formatPermission(perm), c.args[0],
+			terminal.WithSuccessStyle())
+	} else {
+		c.ui.Output("Granted %s to %s.", formatPermission(perm), c.args[0],
+			terminal.WithSuccessStyle())
+	}
+
+	return 0
+}
+
+func (c *UserPermissionCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		if c.revoke {
+			return
+		}
+
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:   "organization",
+			Target: &c.flagOrganization,
+			Usage: "Add the user to this organization. The permissions of the " +
+				"user then only apply to the projects of the organization.",
+		})
+	})
+}
+
+func (c *UserPermissionCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *UserPermissionCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *UserPermissionCommand) Synopsis() string {
+	if c.revoke {
+		return "Revoke a permission from a user."
+	}
+
+	return "Grant a permission to a user."
+}
+
+func (c *UserPermissionCommand) Help() string {
+	if c.revoke {
+		return formatHelp(`
+Usage: waypoint user revoke USER VERB [PROJECT[/WORKSPACE]]
+
+  Revoke a permission from a user.
+
+  The permission must match a permission of the user exactly, including
+  the project and workspace. Use "waypoint user list" to see the
+  permissions of all users.
+
+`)
+	}
+
+	return formatHelp(`
+Usage: waypoint user grant [options] USER VERB [PROJECT[/WORKSPACE]]
+
+  Grant a permission to a user.
+
+  USER is the ID, username or email of the user. VERB is one of "read",
+  "deploy" or "admin". Each verb includes the verbs before it, so "deploy"
+  can also read.
+
+  The permission applies to all projects and workspaces unless a project
+  or a project and workspace are given. Use "*" for all projects, such as
+  "*/prod" for the prod workspace of every project.
+
+  If the user is in an organization, the permissions only apply to the
+  projects of the organization. Admins of an organization can add new
+  users to their organization by granting them a permission.
+
+` + c.Flags().Help())
+}
+
+// parsePermission parses a permission from a verb such as "deploy" and a
+// scope in the form "PROJECT/WORKSPACE". Either part of the scope can be
+// "*" or omitted for all projects or workspaces.
+func parsePermission(verb, scope string) (*pb.Permission, error) {
+	v, ok := pb.Permission_Verb_value[strings.ToUpper(verb)]
+	if !ok {
+		return nil, fmt.Errorf(
+			"Unknown permission %q. Must be one of \"read\", \"deploy\" or \"admin\".", verb)
+	}
+
+	var result pb.Permission
+	result.Verb = pb.Permission_Verb(v)
+
+	parts := strings.SplitN(scope, "/", 2)
+	if parts[0] != "*" {
+		result.Project = parts[0]
+	}
+	if len(parts) > 1 && parts[1] != "*" {
+		result.Workspace = parts[1]
+	}
+
+	return &result, nil
+}
+
+// formatPermission returns a human friendly description of the permission.
+func formatPermission(p *pb.Permission) string {
+	project := p.Project
+	if project == "" {
+		project = "*"
+	}
+	workspace := p.Workspace
+	if workspace == "" {
+		workspace = "*"
+	}
+
+	return fmt.Sprintf("%s on %s/%s", strings.ToLower(p.Verb.String()), project, workspace)
+}
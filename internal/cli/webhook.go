@@ -0,0 +1,352 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/posener/complete"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clierrors"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type WebhookCreateCommand struct {
+	*baseCommand
+
+	flagId        string
+	flagName      string
+	flagProject   string
+	flagProvider  string
+	flagOperation string
+	flagRefs      []string
+	flagSecret    string
+}
+
+func (c *WebhookCreateCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithConfig(true),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 0 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	project := c.refProject
+	if c.flagProject != "" {
+		project = &pb.Ref_Project{Project: c.flagProject}
+	}
+	if project == nil {
+		c.ui.Output("A project must be set with -project or a waypoint.hcl file.",
+			terminal.WithErrorStyle())
+		return 1
+	}
+
+	op, ok := pb.Trigger_Operation_value[strings.ToUpper(c.flagOperation)]
+	if !ok || op == int32(pb.Trigger_UNKNOWN) {
+		c.ui.Output("Invalid operation %q. Must be one of: build, deploy, up.",
+			c.flagOperation, terminal.WithErrorStyle())
+		return 1
+	}
+
+	provider, ok := pb.Webhook_Provider_value[strings.ToUpper(c.flagProvider)]
+	if !ok {
+		c.ui.Output("Invalid provider %q. Must be one of: github, gitlab, generic.",
+			c.flagProvider, terminal.WithErrorStyle())
+		return 1
+	}
+
+	hook := &pb.Webhook{
+		Id:        c.flagId,
+		Name:      c.flagName,
+		Project:   project,
+		Workspace: c.refWorkspace,
+		Provider:  pb.Webhook_Provider(provider),
+		Secret:    c.flagSecret,
+		Refs:      c.flagRefs,
+		Operation: pb.Trigger_Operation(op),
+	}
+	if c.flagApp != "" {
+		hook.Applications = []string{c.flagApp}
+	}
+
+	resp, err := c.project.Client().UpsertWebhook(c.Ctx, &pb.UpsertWebhookRequest{
+		Webhook: hook,
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Webhook %s saved.", resp.Webhook.Id, terminal.WithSuccessStyle())
+	c.ui.Output("")
+	c.ui.Output("Configure the webhook of the repository with the path and secret below.\n"+
+		"The path is on the HTTP address of the server, such as https://localhost:9702.\n"+
+		"The secret isn't shown again but can be changed with -id and -secret.", terminal.WithInfoStyle())
+	c.ui.Output("")
+	c.ui.NamedValues([]terminal.NamedValue{
+		{Name: "Path", Value: server.WebhookPath + resp.Webhook.Id},
+		{Name: "Secret", Value: resp.Webhook.Secret},
+	})
+	return 0
+}
+
+func (c *WebhookCreateCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:    "provider",
+			Target:  &c.flagProvider,
+			Default: "generic",
+			Usage:   "Format of the webhook requests. One of: github, gitlab, generic.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:    "op",
+			Target:  &c.flagOperation,
+			Default: "up",
+			Usage:   "Operation to run. One of: build, deploy, up.",
+		})
+
+		f.StringSliceVar(&flag.StringSliceVar{
+			Name:   "ref",
+			Target: &c.flagRefs,
+			Usage: "Pattern of the Git refs that run the operation, such as \"main\" or " +
+				"\"refs/tags/v*\". Can be specified multiple times. Pushes to any ref " +
+				"run the operation if this isn't set.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage:      "Project to run the operation for. This defaults to the current project.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "name",
+			Target: &c.flagName,
+			Usage:  "Name of the webhook to show in the webhook list.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "secret",
+			Target: &c.flagSecret,
+			Usage:  "Secret to verify the requests with. A secret is generated if this isn't set.",
+		})
+
+		f.StringVar(&flag.StringVar{
+			Name:   "id",
+			Target: &c.flagId,
+			Usage:  "ID of an existing webhook to update instead of creating a new one.",
+		})
+	})
+}
+
+func (c *WebhookCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *WebhookCreateCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *WebhookCreateCommand) Synopsis() string {
+	return "Run an operation when Git pushes are received."
+}
+
+func (c *WebhookCreateCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint webhook create [options]
+
+  Create a webhook that runs an operation for the project when a push to
+  a Git ref is received from GitHub, GitLab or any other system.
+
+  The operation runs for every app of the project unless -app is set.
+  The jobs are queued on a remote runner and check out the pushed commit,
+  so the project must have a Git data source.
+
+  Generic webhooks receive JSON objects with the pushed "ref" and the
+  "commit" to check out. The X-Waypoint-Timestamp header must be the Unix
+  time of the request and the X-Waypoint-Signature header "sha256="
+  followed by the hex encoded HMAC-SHA256 signature of the timestamp, a
+  "." and the body.
+
+` + c.Flags().Help())
+}
+
+type WebhookListCommand struct {
+	*baseCommand
+
+	flagProject string
+	flagJson    bool
+}
+
+func (c *WebhookListCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithConfig(true),
+	); err != nil {
+		return 1
+	}
+
+	// List the webhooks of the project we're in unless one is given.
+	req := &pb.ListWebhooksRequest{}
+	if c.flagProject != "" {
+		req.Project = &pb.Ref_Project{Project: c.flagProject}
+	} else if c.refProject != nil {
+		req.Project = c.refProject
+	}
+
+	resp, err := c.project.Client().ListWebhooks(c.Ctx, req)
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	if c.flagJson {
+		var msgs []proto.Message
+		for _, v := range resp.Webhooks {
+			msgs = append(msgs, v)
+		}
+		if err := outputJsonList(msgs); err != nil {
+			c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+			return 1
+		}
+
+		return 0
+	}
+
+	table := terminal.NewTable(
+		"ID", "Name", "Project", "Provider", "Refs", "Operation", "Last Push", "Last Jobs", "Error")
+	for _, hook := range resp.Webhooks {
+		var last string
+		if ts, err := ptypes.Timestamp(hook.LastTime); err == nil {
+			last = ts.Local().Format("2006-01-02 15:04") + " " + hook.LastRef
+		}
+
+		var colors []string
+		if hook.LastError != "" {
+			colors = []string{"", "", "", "", "", "", "", "", terminal.Red}
+		}
+
+		table.Rich([]string{
+			hook.Id,
+			hook.Name,
+			hook.Project.GetProject(),
+			strings.ToLower(hook.Provider.String()),
+			strings.Join(hook.Refs, ", "),
+			strings.ToLower(hook.Operation.String()),
+			last,
+			strings.Join(hook.LastJobIds, ", "),
+			hook.LastError,
+		}, colors)
+	}
+
+	c.ui.Table(table)
+	return 0
+}
+
+func (c *WebhookListCommand) Flags() *flag.Sets {
+	return c.flagSet(0, func(set *flag.Sets) {
+		f := set.NewSet("Command Options")
+		f.StringVar(&flag.StringVar{
+			Name:       "project",
+			Target:     &c.flagProject,
+			Completion: c.predictProjects(),
+			Usage:      "Only list the webhooks of this project.",
+		})
+
+		initJsonFlag(f, &c.flagJson)
+	})
+}
+
+func (c *WebhookListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *WebhookListCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *WebhookListCommand) Synopsis() string {
+	return "List the webhooks and the result of the last push."
+}
+
+func (c *WebhookListCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint webhook list [options]
+
+  List the webhooks and the result of the last push. The webhooks of the
+  project in the current directory are listed unless -project is set.
+
+` + c.Flags().Help())
+}
+
+type WebhookDeleteCommand struct {
+	*baseCommand
+}
+
+func (c *WebhookDeleteCommand) Run(args []string) int {
+	// Initialize. If we fail, we just exit since Init handles the UI.
+	if err := c.Init(
+		WithArgs(args),
+		WithFlags(c.Flags()),
+		WithNoConfig(),
+	); err != nil {
+		return 1
+	}
+
+	if len(c.args) != 1 {
+		c.ui.Output(c.Help(), terminal.WithErrorStyle())
+		return 1
+	}
+
+	_, err := c.project.Client().DeleteWebhook(c.Ctx, &pb.DeleteWebhookRequest{
+		Id: c.args[0],
+	})
+	if err != nil {
+		c.ui.Output(clierrors.Humanize(err), terminal.WithErrorStyle())
+		return 1
+	}
+
+	c.ui.Output("Webhook %s deleted.", c.args[0], terminal.WithSuccessStyle())
+	return 0
+}
+
+func (c *WebhookDeleteCommand) Flags() *flag.Sets {
+	return c.flagSet(0, nil)
+}
+
+func (c *WebhookDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *WebhookDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Flags().Completions()
+}
+
+func (c *WebhookDeleteCommand) Synopsis() string {
+	return "Delete a webhook."
+}
+
+func (c *WebhookDeleteCommand) Help() string {
+	return formatHelp(`
+Usage: waypoint webhook delete [options] ID
+
+  Delete a webhook. Requests to the webhook are rejected afterwards and
+  jobs that it already queued aren't canceled.
+
+`)
+}
@@ -11,6 +11,12 @@ import (
 type App struct {
 	UI terminal.UI
 
+	// RunnerLabels, if set, returns the labels of the runners that a job
+	// of the app targets, such as from the runner stanzas of the app. If
+	// this returns nil, the job targets the runner of the project. This
+	// has no effect in local mode, where the local runner runs every job.
+	RunnerLabels func(*pb.Job) map[string]string
+
 	project     *Project
 	application *pb.Ref_Application
 }
@@ -40,7 +46,18 @@ func (c *App) job() *pb.Job {
 	return job
 }
 
-// doJob is the same as Project.doJob except we set the proper app-specific UI.
+// doJob is the same as Project.doJob except we set the proper app-specific UI
+// and the runner targeting of the app.
 func (c *App) doJob(ctx context.Context, job *pb.Job) (*pb.Job_Result, error) {
+	if _, ok := job.TargetRunner.GetTarget().(*pb.Ref_Runner_Any); ok && c.RunnerLabels != nil {
+		if labels := c.RunnerLabels(job); labels != nil {
+			job.TargetRunner = &pb.Ref_Runner{
+				Target: &pb.Ref_Runner_Labels{
+					Labels: &pb.Ref_RunnerLabels{Labels: labels},
+				},
+			}
+		}
+	}
+
 	return c.project.doJob(ctx, job, c.UI)
 }
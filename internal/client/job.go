@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -12,9 +13,17 @@ import (
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
 	"github.com/hashicorp/waypoint/internal/pkg/finalcontext"
+	"github.com/hashicorp/waypoint/internal/server"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
+const (
+	// queueJobAttempts is how many times queueing a job is tried while the
+	// server is unavailable, such as while it restarts.
+	queueJobAttempts   = 5
+	queueJobRetryDelay = time.Second
+)
+
 // job returns the basic job skeleton prepoulated with the correct
 // defaults based on how the client is configured. For example, for local
 // operations, this will already have the targeting for the local runner.
@@ -33,6 +42,7 @@ func (c *Project) job() *pb.Job {
 			},
 		},
 		DataSourceOverrides: c.dataSourceOverrides,
+		Variables:           c.variables,
 
 		Operation: &pb.Job_Noop_{
 			Noop: &pb.Job_Noop{},
@@ -125,26 +135,70 @@ func (c *Project) queueAndStreamJob(
 		expiration = "30s"
 	}
 
+	// The idempotency key lets us retry queueing if the server is briefly
+	// unavailable without queueing the job twice.
+	key, err := server.Id()
+	if err != nil {
+		return nil, err
+	}
+
 	// Queue the job
 	log.Debug("queueing job", "operation", fmt.Sprintf("%T", job.Operation))
-	queueResp, err := c.client.QueueJob(ctx, &pb.QueueJobRequest{
-		Job:       job,
-		ExpiresIn: expiration,
-	})
+	var queueResp *pb.QueueJobResponse
+	for attempt := 1; ; attempt++ {
+		queueResp, err = c.client.QueueJob(ctx, &pb.QueueJobRequest{
+			Job:            job,
+			ExpiresIn:      expiration,
+			IdempotencyKey: key,
+		})
+		if status.Code(err) != codes.Unavailable || attempt >= queueJobAttempts {
+			break
+		}
+
+		log.Debug("server unavailable, retrying to queue job", "attempt", attempt)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(queueJobRetryDelay):
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
-	log = log.With("job_id", queueResp.JobId)
 
 	// Call our callback if it was given
 	if jobIdCallback != nil {
 		jobIdCallback(queueResp.JobId)
 	}
 
+	return c.streamJob(ctx, queueResp.JobId, ui, c.local, true)
+}
+
+// StreamJob streams the output of a job that is already queued to the UI
+// until the job completes. This is used to attach to a job again, so the
+// job keeps running if ctx is canceled.
+func (c *Project) StreamJob(ctx context.Context, id string, ui terminal.UI) (*pb.Job_Result, error) {
+	return c.streamJob(ctx, id, ui, false, false)
+}
+
+// streamJob streams the output of the job to the UI until it completes.
+// If local is true, the terminal output is ignored since the local runner
+// uses the UI directly. If cancel is true, the job is canceled if ctx is
+// canceled, such as by an interrupt or the operation timeout, so that the
+// runner stops it rather than only the stream ending.
+func (c *Project) streamJob(
+	ctx context.Context,
+	jobId string,
+	ui terminal.UI,
+	local bool,
+	cancel bool,
+) (*pb.Job_Result, error) {
+	log := c.logger.With("job_id", jobId)
+
 	// Get the stream
 	log.Debug("opening job stream")
 	stream, err := c.client.GetJobStream(ctx, &pb.GetJobStreamRequest{
-		JobId: queueResp.JobId,
+		JobId: jobId,
 	})
 	if err != nil {
 		return nil, err
@@ -174,13 +228,19 @@ func (c *Project) queueAndStreamJob(
 		stateEventTimer *time.Timer
 		tstatus         terminal.Status
 
+		// queuePosition is the latest position of the job in the queue.
+		// queuedShown is set to 1 by the state event timer once we've
+		// told the user the job is queued. These are accessed atomically.
+		queuePosition uint32
+		queuedShown   int32
+
 		stdout, stderr io.Writer
 
 		sg    terminal.StepGroup
 		steps = map[int32]*stepData{}
 	)
 
-	if c.local {
+	if cancel {
 		defer func() {
 			// If we completed then do nothing, or if the context is still
 			// active since this means that we're not cancelled.
@@ -193,7 +253,7 @@ func (c *Project) queueAndStreamJob(
 
 			log.Warn("canceling job")
 			_, err := c.client.CancelJob(ctx, &pb.CancelJobRequest{
-				JobId: queueResp.JobId,
+				JobId: jobId,
 			})
 			if err != nil {
 				log.Warn("error canceling job", "err", err)
@@ -236,7 +296,7 @@ func (c *Project) queueAndStreamJob(
 
 		case *pb.GetJobStreamResponse_Terminal_:
 			// Ignore this for local jobs since we're using our UI directly.
-			if c.local {
+			if local {
 				continue
 			}
 
@@ -347,6 +407,18 @@ func (c *Project) queueAndStreamJob(
 				}
 			}
 		case *pb.GetJobStreamResponse_State_:
+			// The position of a queued job changed. We only report it once
+			// we've told the user that the job is queued.
+			atomic.StoreUint32(&queuePosition, event.State.Job.GetQueuePosition())
+			if event.State.Previous == pb.Job_QUEUED && event.State.Current == pb.Job_QUEUED {
+				if atomic.LoadInt32(&queuedShown) == 1 && event.State.Job.GetQueuePosition() > 0 {
+					ui.Output("Queue position: %d", event.State.Job.QueuePosition,
+						terminal.WithInfoStyle())
+				}
+
+				continue
+			}
+
 			// Stop any state event timers if we have any since the state
 			// has changed and we don't want to output that information anymore.
 			if stateEventTimer != nil {
@@ -361,8 +433,12 @@ func (c *Project) queueAndStreamJob(
 				stateEventTimer = time.AfterFunc(stateEventPause, func() {
 					ui.Output("Operation is queued. Waiting for runner assignment...",
 						terminal.WithHeaderStyle())
+					if position := atomic.LoadUint32(&queuePosition); position > 0 {
+						ui.Output("Queue position: %d", position, terminal.WithInfoStyle())
+					}
 					ui.Output("If you interrupt this command, the job will still run in the background.",
 						terminal.WithInfoStyle())
+					atomic.StoreInt32(&queuedShown, 1)
 				})
 
 			case pb.Job_WAITING:
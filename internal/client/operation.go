@@ -123,6 +123,34 @@ func (c *App) Deploy(ctx context.Context, op *pb.Job_DeployOp) (*pb.Job_DeployRe
 	return result.Deploy, nil
 }
 
+// DeployWorkspace is the same as Deploy but targets the given workspace
+// rather than the workspace of the project. The labels are added to the
+// labels of the project for the job.
+func (c *App) DeployWorkspace(
+	ctx context.Context,
+	op *pb.Job_DeployOp,
+	ws *pb.Ref_Workspace,
+	labels map[string]string,
+) (*pb.Job_DeployResult, error) {
+	if op == nil {
+		op = &pb.Job_DeployOp{}
+	}
+
+	// Build our job
+	job := c.workspaceJob(ws, labels)
+	job.Operation = &pb.Job_Deploy{
+		Deploy: op,
+	}
+
+	// Execute it
+	result, err := c.doJob(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Deploy, nil
+}
+
 func (c *App) Destroy(ctx context.Context, op *pb.Job_DestroyOp) error {
 	if op == nil {
 		op = &pb.Job_DestroyOp{}
@@ -139,6 +167,73 @@ func (c *App) Destroy(ctx context.Context, op *pb.Job_DestroyOp) error {
 	return err
 }
 
+// DestroyWorkspace is the same as Destroy but targets the given workspace
+// rather than the workspace of the project.
+func (c *App) DestroyWorkspace(ctx context.Context, op *pb.Job_DestroyOp, ws *pb.Ref_Workspace) error {
+	if op == nil {
+		op = &pb.Job_DestroyOp{}
+	}
+
+	// Build our job
+	job := c.job()
+	job.Workspace = ws
+	job.Operation = &pb.Job_Destroy{
+		Destroy: op,
+	}
+
+	// Execute it
+	_, err := c.doJob(ctx, job)
+	return err
+}
+
+func (c *App) Scale(ctx context.Context, op *pb.Job_ScaleOp) error {
+	if op == nil {
+		op = &pb.Job_ScaleOp{}
+	}
+
+	// Build our job
+	job := c.job()
+	job.Operation = &pb.Job_Scale{
+		Scale: op,
+	}
+
+	// Execute it
+	_, err := c.doJob(ctx, job)
+	return err
+}
+
+func (c *App) Inspect(ctx context.Context, op *pb.Job_InspectOp) (*pb.Job_InspectResult, error) {
+	if op == nil {
+		op = &pb.Job_InspectOp{}
+	}
+
+	// Build our job
+	job := c.job()
+	job.Operation = &pb.Job_Inspect{
+		Inspect: op,
+	}
+
+	// Execute it
+	result, err := c.doJob(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Inspect, nil
+}
+
+func (c *App) PipelineStep(ctx context.Context, op *pb.Job_PipelineStepOp) error {
+	// Build our job
+	job := c.job()
+	job.Operation = &pb.Job_PipelineStep{
+		PipelineStep: op,
+	}
+
+	// Execute it
+	_, err := c.doJob(ctx, job)
+	return err
+}
+
 func (c *App) Release(ctx context.Context, op *pb.Job_ReleaseOp) (*pb.Job_ReleaseResult, error) {
 	if op == nil {
 		op = &pb.Job_ReleaseOp{}
@@ -159,11 +254,59 @@ func (c *App) Release(ctx context.Context, op *pb.Job_ReleaseOp) (*pb.Job_Releas
 	return result.Release, nil
 }
 
-func (a *App) Logs(ctx context.Context) (component.LogViewer, error) {
+// ReleaseWorkspace is the same as Release but targets the given workspace
+// rather than the workspace of the project. The labels are added to the
+// labels of the project for the job.
+func (c *App) ReleaseWorkspace(
+	ctx context.Context,
+	op *pb.Job_ReleaseOp,
+	ws *pb.Ref_Workspace,
+	labels map[string]string,
+) (*pb.Job_ReleaseResult, error) {
+	if op == nil {
+		op = &pb.Job_ReleaseOp{}
+	}
+
+	// Build our job
+	job := c.workspaceJob(ws, labels)
+	job.Operation = &pb.Job_Release{
+		Release: op,
+	}
+
+	// Execute it
+	result, err := c.doJob(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Release, nil
+}
+
+// workspaceJob returns a job for the given workspace with the labels
+// added to the labels of the project.
+func (c *App) workspaceJob(ws *pb.Ref_Workspace, labels map[string]string) *pb.Job {
+	job := c.job()
+	job.Workspace = ws
+
+	merged := map[string]string{}
+	for k, v := range job.Labels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	job.Labels = merged
+
+	return job
+}
+
+// Logs streams the logs of the app. If level is set, only the lines with
+// at least that level, such as "error", are streamed.
+func (a *App) Logs(ctx context.Context, level string) (component.LogViewer, error) {
 	log := a.project.logger.Named("logs")
 
 	// First we attempt to query the server for logs for this deployment.
-	log.Info("requesting log stream")
+	log.Info("requesting log stream", "level", level)
 	client, err := a.project.client.GetLogStream(ctx, &pb.GetLogStreamRequest{
 		Scope: &pb.GetLogStreamRequest_Application_{
 			Application: &pb.GetLogStreamRequest_Application{
@@ -171,6 +314,7 @@ func (a *App) Logs(ctx context.Context) (component.LogViewer, error) {
 				Workspace:   a.project.WorkspaceRef(),
 			},
 		},
+		Level: level,
 	})
 	if err != nil {
 		return nil, err
@@ -24,6 +24,7 @@ type Project struct {
 	runner              *pb.Ref_Runner
 	labels              map[string]string
 	dataSourceOverrides map[string]string
+	variables           map[string]string
 	cleanupFunc         func()
 
 	local bool
@@ -180,6 +181,15 @@ func WithSourceOverrides(m map[string]string) Option {
 	}
 }
 
+// WithVariables sets the values of the input variables of the configuration
+// for queued jobs. The values are HCL expressions.
+func WithVariables(m map[string]string) Option {
+	return func(c *Project, cfg *config) error {
+		c.variables = m
+		return nil
+	}
+}
+
 // WithLocal puts the client in local exec mode. In this mode, the client
 // will spin up a per-operation runner locally and reference the local on-disk
 // data for all operations.
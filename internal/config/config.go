@@ -1,16 +1,28 @@
 package config
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
 )
 
 // Config is the configuration structure.
 type Config struct {
-	Runner  *Runner           `hcl:"runner,block" default:"{}"`
-	Project string            `hcl:"project,attr"`
-	Apps    []*App            `hcl:"app,block"`
-	Labels  map[string]string `hcl:"labels,optional"`
-	Plugin  []*Plugin         `hcl:"plugin,block"`
+	Runner          *Runner           `hcl:"runner,block" default:"{}"`
+	Project         string            `hcl:"project,attr"`
+	Apps            []*App            `hcl:"app,block"`
+	Labels          map[string]string `hcl:"labels,optional"`
+	Plugin          []*Plugin         `hcl:"plugin,block"`
+	RequiredPlugins *RequiredPlugins  `hcl:"required_plugins,block"`
+	Retention       *Retention        `hcl:"retention,block"`
+	Jobs            *Jobs             `hcl:"jobs,block"`
+	Config          []*ConfigVars     `hcl:"config,block"`
+	Variables       []*Variable       `hcl:"variable,block"`
+	Data            []*Data           `hcl:"data,block"`
+	Imports         []*Import         `hcl:"import,block"`
 }
 
 // Retrieve the app config for the named application
@@ -24,6 +36,51 @@ func (c *Config) AppConfig(name string) (*App, bool) {
 	return nil, false
 }
 
+// AppsOrdered returns the apps ordered so that each app comes after the
+// apps it depends on. Apps are otherwise in the order they are declared.
+// This returns an error if the dependencies have a cycle.
+func (c *Config) AppsOrdered() ([]*App, error) {
+	done := map[string]bool{}
+	result := make([]*App, 0, len(c.Apps))
+	for len(result) < len(c.Apps) {
+		progress := false
+		for _, app := range c.Apps {
+			if done[app.Name] {
+				continue
+			}
+
+			ready := true
+			for _, dep := range app.DependsOn {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				continue
+			}
+
+			done[app.Name] = true
+			result = append(result, app)
+			progress = true
+		}
+
+		if !progress {
+			var names []string
+			for _, app := range c.Apps {
+				if !done[app.Name] {
+					names = append(names, app.Name)
+				}
+			}
+
+			return nil, fmt.Errorf(
+				"depends_on: apps have a dependency cycle: %s", strings.Join(names, ", "))
+		}
+	}
+
+	return result, nil
+}
+
 // App represents a single application.
 type App struct {
 	Name   string            `hcl:",label"`
@@ -31,9 +88,104 @@ type App struct {
 	Labels map[string]string `hcl:"labels,optional"`
 	URL    *AppURL           `hcl:"url,block" default:"{}"`
 
+	// PathGlobs are the files that the app is built from, such as
+	// "services/api/**". Operations triggered by a push or a poll of the
+	// data source only run for the app if a changed file matches one of
+	// them. If this isn't set, they always run.
+	PathGlobs []string `hcl:"path_globs,optional"`
+
+	// DependsOn are the names of apps in the project that must complete
+	// an operation before this app when operating on several apps.
+	DependsOn []string `hcl:"depends_on,optional"`
+
+	// Import are the names of the imports that the app is merged over.
+	// Later imports take precedence over earlier ones. See Import.
+	Import []string `hcl:"import,optional"`
+
+	// Runner selects the runners that the remote jobs of the app run on.
+	// The runner stanza of a stage replaces this for its jobs.
+	Runner *RunnerTarget `hcl:"runner,block"`
+
 	Build   *Build   `hcl:"build,block"`
+	Scan    *Scan    `hcl:"scan,block"`
 	Deploy  *Deploy  `hcl:"deploy,block"`
 	Release *Release `hcl:"release,block"`
+
+	// Config are the config variables of the app. See ConfigVars.
+	Config []*ConfigVars `hcl:"config,block"`
+
+	// Pipelines are the named sequences of steps of the app that are run
+	// with "waypoint pipeline run".
+	Pipelines []*Pipeline `hcl:"pipeline,block"`
+
+	// WorkspaceOverrides and LabelOverrides replace stanzas of the app for
+	// operations in a workspace or with a label. See Scoped.
+	WorkspaceOverrides []*WorkspaceOverride `hcl:"workspace,block"`
+	LabelOverrides     []*LabelOverride     `hcl:"label,block"`
+}
+
+// RunnerTarget selects the runners that remote jobs run on by their
+// labels, such as runners that can build container images or have GPUs,
+// rather than whichever runner is assigned the job first.
+type RunnerTarget struct {
+	// Labels are the labels that the runner must have. The runner may
+	// have other labels too.
+	Labels map[string]string `hcl:"labels,optional"`
+
+	// Profile is the value that the "profile" label of the runner must
+	// have, such as "gpu". This is the same as setting the label.
+	Profile string `hcl:"profile,optional"`
+}
+
+// RunnerProfileLabel is the label of runners that RunnerTarget.Profile
+// matches, such as registering a runner with "-label profile=gpu".
+const RunnerProfileLabel = "profile"
+
+// RunnerLabels returns the labels that the runner of a remote job of the
+// app must have, or nil if any runner may run it. stage is "build",
+// "deploy" or "release" for the jobs of a stage, which use the runner
+// stanza of the stage if it has one, or empty for other jobs.
+func (app *App) RunnerLabels(stage string) map[string]string {
+	target := app.Runner
+	var stageTarget *RunnerTarget
+	switch stage {
+	case "build":
+		if app.Build != nil {
+			stageTarget = app.Build.Runner
+		}
+
+	case "deploy":
+		if app.Deploy != nil {
+			stageTarget = app.Deploy.Runner
+		}
+
+	case "release":
+		if app.Release != nil {
+			stageTarget = app.Release.Runner
+		}
+	}
+	if stageTarget != nil {
+		target = stageTarget
+	}
+
+	return target.labels()
+}
+
+// labels returns the labels with the profile label added.
+func (t *RunnerTarget) labels() map[string]string {
+	if t == nil || (len(t.Labels) == 0 && t.Profile == "") {
+		return nil
+	}
+
+	result := map[string]string{}
+	for k, v := range t.Labels {
+		result[k] = v
+	}
+	if t.Profile != "" {
+		result[RunnerProfileLabel] = t.Profile
+	}
+
+	return result
 }
 
 // AppURL configures the App-specific URL settings.
@@ -41,6 +193,61 @@ type AppURL struct {
 	AutoHostname *bool `hcl:"auto_hostname,optional"`
 }
 
+// WorkspaceOverride replaces the stanzas of an app that it sets for
+// operations in the workspace.
+type WorkspaceOverride struct {
+	Workspace string `hcl:",label"`
+
+	Build   *Build   `hcl:"build,block"`
+	Deploy  *Deploy  `hcl:"deploy,block"`
+	Release *Release `hcl:"release,block"`
+}
+
+// LabelOverride replaces the stanzas of an app that it sets for operations
+// that have the label with the value.
+type LabelOverride struct {
+	Key   string `hcl:",label"`
+	Value string `hcl:",label"`
+
+	Build   *Build   `hcl:"build,block"`
+	Deploy  *Deploy  `hcl:"deploy,block"`
+	Release *Release `hcl:"release,block"`
+}
+
+// Scoped returns the app with the stanzas replaced by the overrides that
+// match the workspace and labels of an operation. Workspace overrides are
+// applied before label overrides, each in the order they are declared, so
+// later overrides take precedence. A stanza replaces the whole stanza of
+// the app rather than being merged with it.
+func (app *App) Scoped(workspace string, labels map[string]string) *App {
+	result := *app
+	override := func(b *Build, d *Deploy, r *Release) {
+		if b != nil {
+			result.Build = b
+		}
+		if d != nil {
+			result.Deploy = d
+		}
+		if r != nil {
+			result.Release = r
+		}
+	}
+
+	for _, o := range app.WorkspaceOverrides {
+		if o.Workspace == workspace {
+			override(o.Build, o.Deploy, o.Release)
+		}
+	}
+
+	for _, o := range app.LabelOverrides {
+		if v, ok := labels[o.Key]; ok && v == o.Value {
+			override(o.Build, o.Deploy, o.Release)
+		}
+	}
+
+	return &result
+}
+
 // Server configures the remote server.
 type Server struct {
 	Address string `hcl:"address,attr"`
@@ -72,6 +279,154 @@ type Runner struct {
 
 	// DataSource is the default data source when a remote job is queued.
 	DataSource *DataSource `hcl:"data_source,block"`
+
+	// Poll configures the server to poll the data source for changes and
+	// run an operation when it changes.
+	Poll *Poll `hcl:"poll,block"`
+}
+
+// Poll configures polling of the data source of a project.
+type Poll struct {
+	// Enabled is whether the server polls the data source.
+	Enabled bool `hcl:"enabled,optional"`
+
+	// Interval is how often the data source is polled, such as "5m".
+	Interval string `hcl:"interval,optional"`
+}
+
+// Retention configures how long the server keeps the records of the
+// operations and jobs of this project.
+type Retention struct {
+	// MaxCount is the number of records of each type to keep for each
+	// application and workspace.
+	MaxCount uint32 `hcl:"max_count,optional"`
+
+	// MaxAge is the duration after which records are deleted, such as "720h".
+	MaxAge string `hcl:"max_age,optional"`
+}
+
+// Jobs configures how the jobs of this project are queued.
+type Jobs struct {
+	// Priority is the default priority of jobs. Jobs with a higher priority
+	// are assigned to runners first.
+	Priority int32 `hcl:"priority,optional"`
+
+	// MaxConcurrent is the maximum number of jobs of the project that run
+	// at the same time.
+	MaxConcurrent uint32 `hcl:"max_concurrent,optional"`
+}
+
+// ConfigVars declares config variables of the project or an app. They are
+// set on the server with "waypoint config sync".
+type ConfigVars struct {
+	// Workspace and Labels limit the variables to deployments in the
+	// workspace and with the labels, like the scope flags of
+	// "waypoint config set".
+	Workspace string            `hcl:"workspace,optional"`
+	Labels    map[string]string `hcl:"labels,optional"`
+
+	// Body has the variables. It is only decoded by Values so that the
+	// functions in it, such as file() for secrets that are only on some
+	// machines, don't fail other commands.
+	Body hcl.Body `hcl:",remain"`
+}
+
+// ConfigValues are the variables of a config block.
+type ConfigValues struct {
+	// Env are the variables with static values.
+	Env map[string]string `hcl:"env,optional"`
+
+	// Dynamic are the variables that are read from a config source when
+	// the app starts.
+	Dynamic []*ConfigDynamic `hcl:"dynamic,block"`
+
+	// File are the variables that are written to files rather than set as
+	// environment variables.
+	File []*ConfigFile `hcl:"file,block"`
+}
+
+// ConfigDynamic is a variable that is read from a config source.
+type ConfigDynamic struct {
+	Name   string            `hcl:",label"`
+	From   string            `hcl:"from,attr"`
+	Config map[string]string `hcl:"config,optional"`
+}
+
+// ConfigFile is a variable that the entrypoint writes to a file, for apps
+// that read their configuration from files rather than the environment.
+// The value is either set with Value or read from a config source like a
+// dynamic variable.
+type ConfigFile struct {
+	Name string `hcl:",label"`
+
+	// Path is the absolute path of the file in the deployment.
+	Path string `hcl:"path,attr"`
+
+	// Mode is the permissions of the file in octal, such as "0600". This
+	// defaults to "0644".
+	Mode string `hcl:"mode,optional"`
+
+	// Signal is sent to the app when the file changes after it started,
+	// such as "SIGHUP". If this isn't set, the app isn't notified.
+	Signal string `hcl:"signal,optional"`
+
+	Value  string            `hcl:"value,optional"`
+	From   string            `hcl:"from,optional"`
+	Config map[string]string `hcl:"config,optional"`
+}
+
+// FileMode returns the parsed Mode, or 0644 if it isn't set.
+func (f *ConfigFile) FileMode() (uint32, error) {
+	if f.Mode == "" {
+		return 0644, nil
+	}
+
+	mode, err := strconv.ParseUint(f.Mode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf(
+			"config: file %q: mode must be octal permissions such as \"0600\", got %q",
+			f.Name, f.Mode)
+	}
+
+	return uint32(mode), nil
+}
+
+// Values decodes the variables of the block.
+func (c *ConfigVars) Values(ctx *hcl.EvalContext) (*ConfigValues, error) {
+	var result ConfigValues
+	if diag := gohcl.DecodeBody(c.Body, ctx, &result); diag.HasErrors() {
+		return nil, diag
+	}
+
+	for _, d := range result.Dynamic {
+		if _, ok := result.Env[d.Name]; ok {
+			return nil, fmt.Errorf(
+				"config: %q is set in both env and a dynamic block", d.Name)
+		}
+	}
+
+	for _, f := range result.File {
+		if _, ok := result.Env[f.Name]; ok {
+			return nil, fmt.Errorf(
+				"config: %q is set in both env and a file block", f.Name)
+		}
+		for _, d := range result.Dynamic {
+			if d.Name == f.Name {
+				return nil, fmt.Errorf(
+					"config: %q is set in both a dynamic and a file block", f.Name)
+			}
+		}
+
+		if (f.Value == "") == (f.From == "") {
+			return nil, fmt.Errorf(
+				"config: file %q must set exactly one of value or from", f.Name)
+		}
+		if _, err := f.FileMode(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &result, nil
 }
 
 // DataSource configures the data source for the runner.
@@ -85,8 +440,25 @@ type Hook struct {
 	When      string   `hcl:"when,attr"`
 	Command   []string `hcl:"command,attr"`
 	OnFailure string   `hcl:"on_failure,optional"`
+
+	// Capture is the name to record the output of the command as. Later
+	// hooks and stages refer to it as hook.<name>. See HookLabelPrefix.
+	Capture string `hcl:"capture,optional"`
+
+	// Destroy runs the hook when the deployments or releases of the stage
+	// are destroyed rather than for the operation itself.
+	Destroy bool `hcl:"destroy,optional"`
 }
 
+// HookLabelPrefix is the prefix of the labels that hook output is recorded
+// as on the operation, such as "waypoint/hook/version" for the capture
+// "version". This lets the output be used for operations in other jobs.
+const HookLabelPrefix = "waypoint/hook/"
+
+// HookMaxOutput is the maximum length of captured hook output, which is
+// the maximum length of a label value.
+const HookMaxOutput = 255
+
 func (h *Hook) ContinueOnFailure() bool {
 	return h.OnFailure == "continue"
 }
@@ -97,6 +469,11 @@ type Build struct {
 	Hooks    []*Hook           `hcl:"hook,block"`
 	Use      *Use              `hcl:"use,block"`
 	Registry *Registry         `hcl:"registry,block"`
+	Runner   *RunnerTarget     `hcl:"runner,block"`
+
+	// Matrix builds a variant of the app for each combination of the
+	// values, such as architectures. See Variants.
+	Matrix map[string][]string `hcl:"matrix,optional"`
 }
 
 // Registry are the registry settings.
@@ -106,11 +483,78 @@ type Registry struct {
 	Use    *Use              `hcl:"use,block"`
 }
 
+// Scan are the vulnerability scan settings. The scan runs after the
+// build is pushed and before it is deployed.
+type Scan struct {
+	// Scanner is the scanner to run, "trivy" or "grype".
+	Scanner string `hcl:"scanner,optional"`
+
+	// FailOn is the severity at or above which findings fail the build,
+	// such as "high". If empty, findings are recorded but never fail.
+	FailOn string `hcl:"fail_on,optional"`
+
+	// IgnoreUnfixed ignores findings that don't have a fix available.
+	IgnoreUnfixed bool `hcl:"ignore_unfixed,optional"`
+}
+
+// Pipeline is a named sequence of steps for an app. The steps run in
+// order and the pipeline stops at the first step that fails.
+type Pipeline struct {
+	Name  string          `hcl:",label"`
+	Steps []*PipelineStep `hcl:"step,block"`
+}
+
+// PipelineStep is a step of a pipeline. Exactly one of Operation, Exec or
+// Approval must be set.
+type PipelineStep struct {
+	Name string `hcl:",label"`
+
+	// Operation is the operation of the app to run: "build", "deploy" or
+	// "release". Deploys use the artifact of an earlier build step and
+	// releases the deployment of an earlier deploy step, or the latest
+	// ones if the pipeline doesn't have those steps.
+	Operation string `hcl:"operation,optional"`
+
+	// Exec is a command that a runner runs in the directory of the app.
+	Exec []string `hcl:"exec,optional"`
+
+	// Approval is the message to show when asking to continue. The
+	// pipeline stops if it isn't approved.
+	Approval string `hcl:"approval,optional"`
+}
+
+// Pipeline returns the pipeline of the app with the given name.
+func (app *App) Pipeline(name string) (*Pipeline, bool) {
+	for _, p := range app.Pipelines {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return nil, false
+}
+
+// Step returns the step of the pipeline with the given name.
+func (p *Pipeline) Step(name string) (*PipelineStep, bool) {
+	for _, s := range p.Steps {
+		if s.Name == name {
+			return s, true
+		}
+	}
+
+	return nil, false
+}
+
 // Deploy are the deploy settings.
 type Deploy struct {
 	Labels map[string]string `hcl:"labels,optional"`
 	Hooks  []*Hook           `hcl:"hook,block"`
 	Use    *Use              `hcl:"use,block"`
+	Runner *RunnerTarget     `hcl:"runner,block"`
+
+	// Matrix selects the variant of the build matrix to deploy, such as
+	// { arch = "arm64" }.
+	Matrix map[string]string `hcl:"matrix,optional"`
 }
 
 // Release are the release settings.
@@ -118,6 +562,7 @@ type Release struct {
 	Labels map[string]string `hcl:"labels,optional"`
 	Hooks  []*Hook           `hcl:"hook,block"`
 	Use    *Use              `hcl:"use,block"`
+	Runner *RunnerTarget     `hcl:"runner,block"`
 }
 
 // Use is something in the Waypoint configuration that is executed
@@ -46,3 +46,490 @@ func TestParseFile(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadServerReload(t *testing.T) {
+	require := require.New(t)
+
+	cfg, err := LoadServerReload(filepath.Join("testdata", "server", "reload.hcl"))
+	require.NoError(err)
+	require.NotNil(cfg.URL)
+	require.True(cfg.URL.Enabled)
+	require.Equal("api.example.com:443", cfg.URL.APIAddress)
+	require.Nil(cfg.Audit)
+	require.NotNil(cfg.RateLimit)
+	require.Equal(2.5, cfg.RateLimit.TokenRate)
+	require.Equal([]string{"QueueJob"}, cfg.RateLimit.Endpoints)
+}
+
+func TestConfigAppsOrdered(t *testing.T) {
+	t.Run("dependencies first", func(t *testing.T) {
+		require := require.New(t)
+
+		cfg := &Config{
+			Apps: []*App{
+				{Name: "web", DependsOn: []string{"api"}},
+				{Name: "api", DependsOn: []string{"db"}},
+				{Name: "worker"},
+				{Name: "db"},
+			},
+		}
+
+		apps, err := cfg.AppsOrdered()
+		require.NoError(err)
+
+		var names []string
+		for _, app := range apps {
+			names = append(names, app.Name)
+		}
+		require.Equal([]string{"worker", "db", "api", "web"}, names)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		require := require.New(t)
+
+		cfg := &Config{
+			Apps: []*App{
+				{Name: "web", DependsOn: []string{"api"}},
+				{Name: "api", DependsOn: []string{"web"}},
+				{Name: "worker"},
+			},
+		}
+
+		_, err := cfg.AppsOrdered()
+		require.Error(err)
+		require.Contains(err.Error(), "web, api")
+	})
+}
+
+func TestConfigVarsValues(t *testing.T) {
+	require := require.New(t)
+
+	var cfg Config
+	err := hclsimple.Decode("waypoint.hcl", []byte(`
+project = "foo"
+
+config {
+  env = {
+    PORT = "8080"
+  }
+
+  dynamic "DB_PASSWORD" {
+    from   = "vault"
+    config = {
+      path = "secret/data/db"
+      key  = "password"
+    }
+  }
+}
+
+config {
+  workspace = "production"
+  env = {
+    PORT = upper("eighty")
+  }
+}
+`), EvalContext("."), &cfg)
+	require.NoError(err)
+	require.Len(cfg.Config, 2)
+
+	values, err := cfg.Config[0].Values(EvalContext("."))
+	require.NoError(err)
+	require.Equal(map[string]string{"PORT": "8080"}, values.Env)
+	require.Len(values.Dynamic, 1)
+	require.Equal("DB_PASSWORD", values.Dynamic[0].Name)
+	require.Equal("vault", values.Dynamic[0].From)
+	require.Equal("password", values.Dynamic[0].Config["key"])
+
+	require.Equal("production", cfg.Config[1].Workspace)
+	values, err = cfg.Config[1].Values(EvalContext("."))
+	require.NoError(err)
+	require.Equal(map[string]string{"PORT": "EIGHTY"}, values.Env)
+}
+
+func TestConfigVarsValues_file(t *testing.T) {
+	decode := func(t *testing.T, src string) (*ConfigValues, error) {
+		var cfg Config
+		err := hclsimple.Decode("waypoint.hcl", []byte(`
+project = "foo"
+
+config {
+`+src+`
+}
+`), EvalContext("."), &cfg)
+		require.NoError(t, err)
+
+		return cfg.Config[0].Values(EvalContext("."))
+	}
+
+	t.Run("value and dynamic", func(t *testing.T) {
+		require := require.New(t)
+
+		values, err := decode(t, `
+  file "APP_CONFIG" {
+    path   = "/etc/app/config.yml"
+    value  = "port: 8080"
+    signal = "SIGHUP"
+  }
+
+  file "DB_CERT" {
+    path = "/etc/app/db.pem"
+    mode = "0600"
+    from = "vault"
+    config = {
+      path = "secret/data/db"
+      key  = "cert"
+    }
+  }
+`)
+		require.NoError(err)
+		require.Len(values.File, 2)
+
+		f := values.File[0]
+		require.Equal("APP_CONFIG", f.Name)
+		require.Equal("port: 8080", f.Value)
+		require.Equal("SIGHUP", f.Signal)
+		mode, err := f.FileMode()
+		require.NoError(err)
+		require.Equal(uint32(0644), mode)
+
+		f = values.File[1]
+		require.Equal("vault", f.From)
+		require.Equal("cert", f.Config["key"])
+		mode, err = f.FileMode()
+		require.NoError(err)
+		require.Equal(uint32(0600), mode)
+	})
+
+	t.Run("value or from", func(t *testing.T) {
+		_, err := decode(t, `
+  file "APP_CONFIG" {
+    path = "/etc/app/config.yml"
+  }
+`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exactly one of value or from")
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := decode(t, `
+  file "APP_CONFIG" {
+    path  = "/etc/app/config.yml"
+    mode  = "rw"
+    value = "port: 8080"
+  }
+`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "mode must be octal")
+	})
+
+	t.Run("also in env", func(t *testing.T) {
+		_, err := decode(t, `
+  env = {
+    APP_CONFIG = "port: 8080"
+  }
+
+  file "APP_CONFIG" {
+    path  = "/etc/app/config.yml"
+    value = "port: 8080"
+  }
+`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "both env and a file block")
+	})
+}
+
+func TestAppScoped(t *testing.T) {
+	cfg := TestConfig(t, `
+project = "foo"
+
+app "web" {
+  build {
+    use "docker" {}
+  }
+
+  deploy {
+    use "docker" {}
+  }
+
+  workspace "production" {
+    deploy {
+      use "kubernetes" {}
+    }
+
+    release {
+      use "kubernetes" {}
+    }
+  }
+
+  label "region" "eu" {
+    deploy {
+      use "nomad" {}
+    }
+  }
+}
+`)
+	app, ok := cfg.AppConfig("web")
+	require.True(t, ok)
+	require.NoError(t, cfg.Validate())
+
+	t.Run("no overrides", func(t *testing.T) {
+		require := require.New(t)
+
+		scoped := app.Scoped("default", nil)
+		require.Equal("docker", scoped.Deploy.Use.Type)
+		require.Nil(scoped.Release)
+	})
+
+	t.Run("workspace", func(t *testing.T) {
+		require := require.New(t)
+
+		scoped := app.Scoped("production", map[string]string{"region": "us"})
+		require.Equal("docker", scoped.Build.Use.Type)
+		require.Equal("kubernetes", scoped.Deploy.Use.Type)
+		require.Equal("kubernetes", scoped.Release.Use.Type)
+
+		// The app itself isn't modified
+		require.Equal("docker", app.Deploy.Use.Type)
+	})
+
+	t.Run("labels after workspace", func(t *testing.T) {
+		require := require.New(t)
+
+		scoped := app.Scoped("production", map[string]string{"region": "eu"})
+		require.Equal("nomad", scoped.Deploy.Use.Type)
+		require.Equal("kubernetes", scoped.Release.Use.Type)
+	})
+
+	t.Run("plugins", func(t *testing.T) {
+		var names []string
+		for _, p := range cfg.Plugins() {
+			names = append(names, p.Name)
+		}
+		require.ElementsMatch(t, []string{"docker", "kubernetes", "nomad"}, names)
+	})
+}
+
+func TestAppRunnerLabels(t *testing.T) {
+	cfg := TestConfig(t, `
+project = "foo"
+
+app "web" {
+  runner {
+    labels = { arch = "arm64" }
+  }
+
+  build {
+    use "docker" {}
+
+    runner {
+      labels  = { docker = "true" }
+      profile = "gpu"
+    }
+  }
+
+  deploy {
+    use "docker" {}
+  }
+}
+
+app "api" {
+  build {
+    use "docker" {}
+  }
+
+  deploy {
+    use "docker" {}
+  }
+}
+`)
+	require.NoError(t, cfg.Validate())
+
+	web, ok := cfg.AppConfig("web")
+	require.True(t, ok)
+	api, ok := cfg.AppConfig("api")
+	require.True(t, ok)
+
+	cases := []struct {
+		Name     string
+		App      *App
+		Stage    string
+		Expected map[string]string
+	}{
+		{
+			"stage runner replaces the app runner",
+			web,
+			"build",
+			map[string]string{"docker": "true", "profile": "gpu"},
+		},
+
+		{
+			"stage without a runner",
+			web,
+			"deploy",
+			map[string]string{"arch": "arm64"},
+		},
+
+		{
+			"other operations",
+			web,
+			"",
+			map[string]string{"arch": "arm64"},
+		},
+
+		{
+			"no runners",
+			api,
+			"build",
+			nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require.Equal(t, tt.Expected, tt.App.RunnerLabels(tt.Stage))
+		})
+	}
+}
+
+func TestBuildVariants(t *testing.T) {
+	cfg := TestConfig(t, `
+project = "foo"
+
+app "web" {
+  build {
+    matrix = {
+      arch    = ["amd64", "arm64"]
+      variant = ["slim", "full"]
+    }
+
+    use "docker" {
+      dockerfile = "Dockerfile.${matrix.arch}"
+    }
+  }
+
+  deploy {
+    matrix = { arch = "arm64", variant = "slim" }
+
+    use "docker" {}
+  }
+}
+`)
+	require.NoError(t, cfg.Validate())
+
+	app, ok := cfg.AppConfig("web")
+	require.True(t, ok)
+	require.Equal(t, []map[string]string{
+		{"arch": "amd64", "variant": "slim"},
+		{"arch": "amd64", "variant": "full"},
+		{"arch": "arm64", "variant": "slim"},
+		{"arch": "arm64", "variant": "full"},
+	}, app.Build.Variants())
+	require.Equal(t, map[string]string{
+		"waypoint/matrix/arch":    "arm64",
+		"waypoint/matrix/variant": "slim",
+	}, app.ArtifactLabels())
+
+	t.Run("deploy selects a variant that isn't built", func(t *testing.T) {
+		app.Deploy.Matrix = map[string]string{"arch": "s390x"}
+		defer func() { app.Deploy.Matrix = nil }()
+
+		err := cfg.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "isn't a variant of the build matrix")
+	})
+
+	t.Run("no matrix", func(t *testing.T) {
+		require.Nil(t, (&Build{}).Variants())
+		require.Nil(t, (&App{}).ArtifactLabels())
+	})
+}
+
+func TestAppPipelines(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		require := require.New(t)
+
+		cfg := TestConfig(t, `
+project = "foo"
+
+app "web" {
+  build {
+    use "docker" {}
+  }
+
+  deploy {
+    use "docker" {}
+  }
+
+  pipeline "ship" {
+    step "test" {
+      exec = ["make", "test"]
+    }
+
+    step "build" {
+      operation = "build"
+    }
+
+    step "approve" {
+      approval = "Deploy to production?"
+    }
+
+    step "deploy" {
+      operation = "deploy"
+    }
+  }
+}
+`)
+		require.NoError(cfg.Validate())
+
+		app, ok := cfg.AppConfig("web")
+		require.True(ok)
+		p, ok := app.Pipeline("ship")
+		require.True(ok)
+		require.Len(p.Steps, 4)
+
+		step, ok := p.Step("test")
+		require.True(ok)
+		require.Equal([]string{"make", "test"}, step.Exec)
+
+		_, ok = app.Pipeline("nope")
+		require.False(ok)
+	})
+
+	t.Run("invalid steps", func(t *testing.T) {
+		require := require.New(t)
+
+		cfg := TestConfig(t, `
+project = "foo"
+
+app "web" {
+  build {
+    use "docker" {}
+  }
+
+  deploy {
+    use "docker" {}
+  }
+
+  pipeline "ship" {
+    step "both" {
+      operation = "build"
+      exec      = ["make"]
+    }
+
+    step "unknown" {
+      operation = "scan"
+    }
+
+    step "unknown" {
+      approval = "Continue?"
+    }
+  }
+}
+`)
+		err := cfg.Validate()
+		require.Error(err)
+		require.Contains(err.Error(), "step[both]: exactly one of")
+		require.Contains(err.Error(), "step[unknown]: operation must be")
+		require.Contains(err.Error(), "step[unknown]: is declared more than once")
+	})
+}
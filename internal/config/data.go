@@ -0,0 +1,428 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// dataTimeout is how long a data source may take to fetch its values.
+const dataTimeout = 30 * time.Second
+
+// Data is a data source, which fetches values when the runner loads the
+// configuration, such as the outputs of Terraform or keys in Consul. The
+// rest of the configuration refers to them as data.<type>.<name>.<attribute>.
+type Data struct {
+	Type string   `hcl:",label"`
+	Name string   `hcl:",label"`
+	Body hcl.Body `hcl:",remain"`
+}
+
+// dataOnly is used to decode the data blocks before the rest of the
+// configuration.
+type dataOnly struct {
+	Data   []*Data  `hcl:"data,block"`
+	Remain hcl.Body `hcl:",remain"`
+}
+
+// dataSources are the data source types. Each decodes the body of the
+// data block and returns the object that the block refers to. dir is the
+// directory of the configuration.
+var dataSources = map[string]func(ctx *hcl.EvalContext, body hcl.Body, dir string) (cty.Value, error){
+	"consul":                 dataConsul,
+	"env":                    dataEnv,
+	"exec":                   dataExec,
+	"terraform_remote_state": dataTerraformRemoteState,
+}
+
+// dataValues fetches the values of the data sources in the order they are
+// declared and adds them to ctx as "data". A data source can refer to the
+// data sources declared before it. If fetch is false, the data sources are
+// only checked and their values are unknown.
+func dataValues(ctx *hcl.EvalContext, dir string, data []*Data, fetch bool) error {
+	values := map[string]map[string]cty.Value{}
+	for _, d := range data {
+		f, ok := dataSources[d.Type]
+		if !ok {
+			var types []string
+			for t := range dataSources {
+				types = append(types, t)
+			}
+			sort.Strings(types)
+
+			return fmt.Errorf("data %q %q: unknown type, must be one of: %s",
+				d.Type, d.Name, strings.Join(types, ", "))
+		}
+		if _, ok := values[d.Type][d.Name]; ok {
+			return fmt.Errorf("data %q %q: is declared more than once", d.Type, d.Name)
+		}
+
+		v := cty.DynamicVal
+		if fetch {
+			var err error
+			v, err = f(ctx, d.Body, dir)
+			if err != nil {
+				return fmt.Errorf("data %q %q: %s", d.Type, d.Name, err)
+			}
+		}
+
+		if values[d.Type] == nil {
+			values[d.Type] = map[string]cty.Value{}
+		}
+		values[d.Type][d.Name] = v
+
+		types := map[string]cty.Value{}
+		for t, names := range values {
+			types[t] = cty.ObjectVal(names)
+		}
+		ctx.Variables["data"] = cty.ObjectVal(types)
+	}
+
+	return nil
+}
+
+// dataTerraformRemoteStateConfig is the body of a terraform_remote_state
+// data source. Config has the settings of the backend.
+type dataTerraformRemoteStateConfig struct {
+	Backend string            `hcl:"backend,attr"`
+	Config  map[string]string `hcl:"config,optional"`
+}
+
+// dataTerraformRemoteState reads the outputs of a Terraform state. The
+// state is read from a file with the "local" backend, an HTTP endpoint
+// with the "http" backend, or an S3 object with the "s3" backend. The
+// result has the outputs as "outputs" like the data source of Terraform.
+func dataTerraformRemoteState(ctx *hcl.EvalContext, body hcl.Body, dir string) (cty.Value, error) {
+	var cfg dataTerraformRemoteStateConfig
+	if diags := gohcl.DecodeBody(body, ctx, &cfg); diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	required := func(keys ...string) error {
+		for _, k := range keys {
+			if cfg.Config[k] == "" {
+				return fmt.Errorf("config.%s is required for the %s backend", k, cfg.Backend)
+			}
+		}
+
+		return nil
+	}
+
+	var state []byte
+	var err error
+	switch cfg.Backend {
+	case "local":
+		path := cfg.Config["path"]
+		if path == "" {
+			path = "terraform.tfstate"
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		state, err = ioutil.ReadFile(path)
+
+	case "http":
+		if err := required("address"); err != nil {
+			return cty.NilVal, err
+		}
+
+		var req *http.Request
+		req, err = http.NewRequest("GET", cfg.Config["address"], nil)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		if u := cfg.Config["username"]; u != "" {
+			req.SetBasicAuth(u, cfg.Config["password"])
+		}
+
+		state, err = dataHTTPGet(req)
+
+	case "s3":
+		if err := required("bucket", "key"); err != nil {
+			return cty.NilVal, err
+		}
+
+		state, err = dataS3Get(cfg.Config["region"], cfg.Config["bucket"], cfg.Config["key"])
+
+	default:
+		return cty.NilVal, fmt.Errorf(
+			"unsupported backend %q, must be one of: http, local, s3", cfg.Backend)
+	}
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("error reading the state: %s", err)
+	}
+
+	outputs, err := terraformOutputs(state)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"outputs": outputs,
+	}), nil
+}
+
+// terraformOutputs returns the outputs of a Terraform state as an object.
+func terraformOutputs(state []byte) (cty.Value, error) {
+	var decoded struct {
+		Outputs map[string]struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"outputs"`
+	}
+	if err := json.Unmarshal(state, &decoded); err != nil {
+		return cty.NilVal, fmt.Errorf("error decoding the state: %s", err)
+	}
+
+	result := map[string]cty.Value{}
+	for name, output := range decoded.Outputs {
+		v, err := dataJSON(output.Value)
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("output %q: %s", name, err)
+		}
+
+		result[name] = v
+	}
+
+	return cty.ObjectVal(result), nil
+}
+
+// dataConsulConfig is the body of a consul data source.
+type dataConsulConfig struct {
+	Key        string  `hcl:"key,attr"`
+	Address    string  `hcl:"address,optional"`
+	Token      string  `hcl:"token,optional"`
+	Datacenter string  `hcl:"datacenter,optional"`
+	Default    *string `hcl:"default,optional"`
+}
+
+// dataConsul reads a key from the KV store of Consul. The address and
+// token default to the CONSUL_HTTP_ADDR and CONSUL_HTTP_TOKEN environment
+// variables like the Consul CLI. The result has the value as "value".
+func dataConsul(ctx *hcl.EvalContext, body hcl.Body, dir string) (cty.Value, error) {
+	var cfg dataConsulConfig
+	if diags := gohcl.DecodeBody(body, ctx, &cfg); diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	addr := cfg.Address
+	if addr == "" {
+		addr = os.Getenv("CONSUL_HTTP_ADDR")
+	}
+	if addr == "" {
+		addr = "127.0.0.1:8500"
+	}
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+
+	token := cfg.Token
+	if token == "" {
+		token = os.Getenv("CONSUL_HTTP_TOKEN")
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("invalid address %q: %s", addr, err)
+	}
+	u.Path = "/v1/kv/" + strings.TrimPrefix(cfg.Key, "/")
+	query := url.Values{"raw": []string{""}}
+	if cfg.Datacenter != "" {
+		query.Set("dc", cfg.Datacenter)
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if token != "" {
+		req.Header.Set("X-Consul-Token", token)
+	}
+
+	value, err := dataHTTPGet(req)
+	if err == errDataNotFound && cfg.Default != nil {
+		value, err = []byte(*cfg.Default), nil
+	}
+	if err == errDataNotFound {
+		return cty.NilVal, fmt.Errorf("key %q doesn't exist and no default is set", cfg.Key)
+	}
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("error reading key %q: %s", cfg.Key, err)
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"value": cty.StringVal(string(value)),
+	}), nil
+}
+
+// dataEnvConfig is the body of an env data source.
+type dataEnvConfig struct {
+	Name    string  `hcl:"name,attr"`
+	Default *string `hcl:"default,optional"`
+}
+
+// dataEnv reads an environment variable. Unlike the env function, it is
+// an error if the variable isn't set and there is no default, so that a
+// missing variable fails when the configuration is loaded. The result has
+// the value as "value".
+func dataEnv(ctx *hcl.EvalContext, body hcl.Body, dir string) (cty.Value, error) {
+	var cfg dataEnvConfig
+	if diags := gohcl.DecodeBody(body, ctx, &cfg); diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	value, ok := os.LookupEnv(cfg.Name)
+	if !ok {
+		if cfg.Default == nil {
+			return cty.NilVal, fmt.Errorf(
+				"environment variable %s isn't set and no default is set", cfg.Name)
+		}
+
+		value = *cfg.Default
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"value": cty.StringVal(value),
+	}), nil
+}
+
+// dataExecConfig is the body of an exec data source.
+type dataExecConfig struct {
+	Command []string `hcl:"command,attr"`
+
+	// Format is "text" to use the output as a string, or "json" to decode
+	// it. This defaults to "text".
+	Format string `hcl:"format,optional"`
+}
+
+// dataExec runs a command in the directory of the configuration. The
+// result has its output, without surrounding whitespace, as "output".
+func dataExec(ctx *hcl.EvalContext, body hcl.Body, dir string) (cty.Value, error) {
+	var cfg dataExecConfig
+	if diags := gohcl.DecodeBody(body, ctx, &cfg); diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	if len(cfg.Command) == 0 {
+		return cty.NilVal, fmt.Errorf("command must not be empty")
+	}
+
+	execCtx, cancel := context.WithTimeout(context.Background(), dataTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(execCtx, cfg.Command[0], cfg.Command[1:]...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return cty.NilVal, fmt.Errorf("error running %s: %s\n\n%s",
+			cfg.Command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	var output cty.Value
+	switch cfg.Format {
+	case "", "text":
+		output = cty.StringVal(strings.TrimSpace(stdout.String()))
+
+	case "json":
+		var err error
+		output, err = dataJSON(stdout.Bytes())
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("error decoding the output: %s", err)
+		}
+
+	default:
+		return cty.NilVal, fmt.Errorf("format must be \"text\" or \"json\"")
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"output": output,
+	}), nil
+}
+
+// errDataNotFound is returned by dataHTTPGet if the response is a 404.
+var errDataNotFound = errors.New("not found")
+
+// dataHTTPGet sends the request and returns the body of the response.
+func dataHTTPGet(req *http.Request) ([]byte, error) {
+	client := &http.Client{Timeout: dataTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errDataNotFound
+
+	case resp.StatusCode >= 300:
+		return nil, fmt.Errorf("unexpected status %s: %s",
+			resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
+// dataS3Get returns the content of an S3 object. The credentials, and the
+// region if it is empty, are read from the environment.
+func dataS3Get(region, bucket, key string) ([]byte, error) {
+	cfg := aws.NewConfig()
+	if region != "" {
+		cfg = cfg.WithRegion(region)
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dataTimeout)
+	defer cancel()
+
+	out, err := s3.New(sess).GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	return ioutil.ReadAll(out.Body)
+}
+
+// dataJSON decodes JSON into a value of its implied type.
+func dataJSON(data []byte) (cty.Value, error) {
+	ty, err := ctyjson.ImpliedType(data)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return ctyjson.Unmarshal(data, ty)
+}
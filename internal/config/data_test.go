@@ -0,0 +1,82 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_data(t *testing.T) {
+	load := func(name string, opts ...LoadOption) (*Config, error) {
+		path := filepath.Join("testdata", "data", name)
+		return Load(path, EvalContext(filepath.Dir(path)), nil, opts...)
+	}
+
+	// Consul serves one key
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/apps/web/alb_arn" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte("arn:aws:elasticloadbalancing:alb"))
+	}))
+	defer srv.Close()
+
+	defer os.Setenv("CONSUL_HTTP_ADDR", os.Getenv("CONSUL_HTTP_ADDR"))
+	os.Setenv("CONSUL_HTTP_ADDR", srv.URL)
+	defer os.Unsetenv("WP_TEST_DATA_REGION")
+	os.Setenv("WP_TEST_DATA_REGION", "eu-west-1")
+
+	t.Run("values", func(t *testing.T) {
+		require := require.New(t)
+
+		cfg, err := load("waypoint.hcl", WithData())
+		require.NoError(err)
+
+		app, ok := cfg.AppConfig("web")
+		require.True(ok)
+		require.Equal(map[string]string{
+			"cluster": "prod-cluster",
+			"subnet":  "subnet-b",
+			"alb":     "arn:aws:elasticloadbalancing:alb",
+			"missing": "none",
+			"region":  "eu-west-1",
+			"version": "1.2.3",
+			"json":    "3",
+		}, app.Labels)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := load("unknown.hcl")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown type")
+	})
+
+	t.Run("unset env", func(t *testing.T) {
+		_, err := load("env_unset.hcl", WithData())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "WP_TEST_DATA_UNSET isn't set")
+	})
+
+	t.Run("not fetched without WithData", func(t *testing.T) {
+		require := require.New(t)
+
+		// Plugin configuration is decoded later, so this loads
+		_, err := load("lazy.hcl")
+		require.NoError(err)
+
+		_, err = load("lazy.hcl", WithData())
+		require.Error(err)
+		require.Contains(err.Error(), "error running false")
+
+		// Other attributes can't refer to unknown values
+		_, err = load("waypoint.hcl")
+		require.Error(err)
+		require.Contains(err.Error(), "only fetched by the runner")
+	})
+}
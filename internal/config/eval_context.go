@@ -30,6 +30,9 @@ func EvalContext(pwd string) *hcl.EvalContext {
 	addFuncs(funcs.VCSGitFuncs(pwd))
 	addFuncs(funcs.Filesystem(pwd))
 	addFuncs(funcs.Encoding())
+	addFuncs(funcs.Datetime())
+	addFuncs(funcs.Env())
+	addFuncs(funcs.Sensitive(nil))
 
 	return &result
 }
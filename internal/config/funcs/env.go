@@ -0,0 +1,49 @@
+package funcs
+
+import (
+	"os"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+func Env() map[string]function.Function {
+	return map[string]function.Function{
+		"env": MakeEnvFunc(os.LookupEnv),
+	}
+}
+
+// MakeEnvFunc constructs a function that returns the value of an environment
+// variable, looked up with lookup. If the variable isn't set, the optional
+// second argument is returned, or an empty string if it isn't given.
+func MakeEnvFunc(lookup func(string) (string, bool)) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name: "name",
+				Type: cty.String,
+			},
+		},
+		VarParam: &function.Parameter{
+			Name: "default",
+			Type: cty.String,
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			if len(args) > 2 {
+				return cty.UnknownVal(cty.String), function.NewArgErrorf(2,
+					"only a name and a default value may be given")
+			}
+
+			if v, ok := lookup(args[0].AsString()); ok {
+				return cty.StringVal(v), nil
+			}
+
+			if len(args) == 2 {
+				return args[1], nil
+			}
+
+			return cty.StringVal(""), nil
+		},
+	})
+}
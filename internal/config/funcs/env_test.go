@@ -0,0 +1,78 @@
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestEnv(t *testing.T) {
+	lookup := func(k string) (string, bool) {
+		switch k {
+		case "FOO":
+			return "bar", true
+		case "EMPTY":
+			return "", true
+		}
+
+		return "", false
+	}
+
+	tests := []struct {
+		Args []cty.Value
+		Want cty.Value
+		Err  bool
+	}{
+		{
+			[]cty.Value{cty.StringVal("FOO")},
+			cty.StringVal("bar"),
+			false,
+		},
+		{
+			[]cty.Value{cty.StringVal("FOO"), cty.StringVal("default")},
+			cty.StringVal("bar"),
+			false,
+		},
+		{ // Set but empty, so the default isn't used
+			[]cty.Value{cty.StringVal("EMPTY"), cty.StringVal("default")},
+			cty.StringVal(""),
+			false,
+		},
+		{
+			[]cty.Value{cty.StringVal("UNSET")},
+			cty.StringVal(""),
+			false,
+		},
+		{
+			[]cty.Value{cty.StringVal("UNSET"), cty.StringVal("default")},
+			cty.StringVal("default"),
+			false,
+		},
+		{ // Too many arguments
+			[]cty.Value{cty.StringVal("FOO"), cty.StringVal("a"), cty.StringVal("b")},
+			cty.UnknownVal(cty.String),
+			true,
+		},
+	}
+
+	f := MakeEnvFunc(lookup)
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("env(%#v)", test.Args), func(t *testing.T) {
+			got, err := f.Call(test.Args)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package funcs
+
+import (
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// Sensitive returns the sensitive function. record is called with the
+// values it marks and may be nil.
+func Sensitive(record func(string)) map[string]function.Function {
+	return map[string]function.Function{
+		"sensitive": MakeSensitiveFunc(record),
+	}
+}
+
+// MakeSensitiveFunc constructs a function that returns its argument as is
+// and calls record with each string and number in it, so that they can be
+// redacted from output.
+func MakeSensitiveFunc(record func(string)) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name:             "value",
+				Type:             cty.DynamicPseudoType,
+				AllowNull:        true,
+				AllowUnknown:     true,
+				AllowDynamicType: true,
+			},
+		},
+		Type: func(args []cty.Value) (cty.Type, error) {
+			return args[0].Type(), nil
+		},
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			if record == nil {
+				return args[0], nil
+			}
+
+			err := cty.Walk(args[0], func(_ cty.Path, v cty.Value) (bool, error) {
+				if !v.IsKnown() || v.IsNull() {
+					return false, nil
+				}
+
+				if v.Type() == cty.String || v.Type() == cty.Number {
+					s, err := convert.Convert(v, cty.String)
+					if err != nil {
+						return false, err
+					}
+
+					record(s.AsString())
+				}
+
+				return true, nil
+			})
+			if err != nil {
+				return cty.NilVal, err
+			}
+
+			return args[0], nil
+		},
+	})
+}
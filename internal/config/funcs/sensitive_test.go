@@ -0,0 +1,33 @@
+package funcs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSensitive(t *testing.T) {
+	require := require.New(t)
+
+	var recorded []string
+	fn := MakeSensitiveFunc(func(v string) {
+		recorded = append(recorded, v)
+	})
+
+	value := cty.ObjectVal(map[string]cty.Value{
+		"password": cty.StringVal("hunter2"),
+		"pin":      cty.NumberIntVal(1234),
+		"enabled":  cty.True,
+		"unset":    cty.NullVal(cty.String),
+	})
+	got, err := fn.Call([]cty.Value{value})
+	require.NoError(err)
+	require.True(got.RawEquals(value))
+	require.ElementsMatch([]string{"hunter2", "1234"}, recorded)
+
+	// Without a record function the value is returned as is
+	got, err = MakeSensitiveFunc(nil).Call([]cty.Value{cty.StringVal("hunter2")})
+	require.NoError(err)
+	require.True(got.RawEquals(cty.StringVal("hunter2")))
+}
@@ -0,0 +1,359 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+// Import is a set of shared app stanzas, such as the standard build and
+// deploy of an organization. Apps import it with the import attribute
+// and set only the parameters that are specific to them.
+type Import struct {
+	Name string `hcl:",label"`
+
+	// Source is the directory with the stanzas in its .hcl files. This is
+	// a local path if it starts with "./", "../" or "/", which is relative
+	// to the directory of the configuration. Otherwise it is fetched, such
+	// as from a Git URL, the same as "waypoint init -from".
+	Source string `hcl:"source,attr"`
+}
+
+// importsOnly is used to decode the import blocks before the apps.
+type importsOnly struct {
+	Imports []*Import `hcl:"import,block"`
+	Remain  hcl.Body  `hcl:",remain"`
+}
+
+// importBodies returns the body of each import by name. dir is the
+// directory of the configuration, which local sources are relative to.
+func importBodies(dir string, imports []*Import) (map[string]hcl.Body, error) {
+	result := map[string]hcl.Body{}
+	for _, i := range imports {
+		if _, ok := result[i.Name]; ok {
+			return nil, fmt.Errorf("import %q: is declared more than once", i.Name)
+		}
+
+		body, err := i.body(dir)
+		if err != nil {
+			return nil, fmt.Errorf("import %q: %s", i.Name, err)
+		}
+
+		result[i.Name] = body
+	}
+
+	return result, nil
+}
+
+// body returns the stanzas of the import, fetching them if the source
+// isn't local.
+func (i *Import) body(dir string) (hcl.Body, error) {
+	src := i.Source
+	if strings.HasPrefix(src, "./") || strings.HasPrefix(src, "../") || filepath.IsAbs(src) {
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(dir, src)
+		}
+
+		return importDirBody(src, src)
+	}
+
+	td, err := ioutil.TempDir("", "waypoint-import")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(td)
+
+	dst := filepath.Join(td, "import")
+	client := &getter.Client{
+		Src: src,
+		Dst: dst,
+		Pwd: dir,
+		Dir: true,
+	}
+	if err := client.Get(); err != nil {
+		return nil, fmt.Errorf("error fetching %s: %s", src, err)
+	}
+
+	// The files are named by the source in errors since the fetched
+	// copy is removed.
+	return importDirBody(dst, src)
+}
+
+// importDirBody parses the .hcl and .hcl.json files in dir as one body.
+// name is the prefix of the file names in errors.
+func importDirBody(dir, name string) (hcl.Body, error) {
+	var paths []string
+	for _, pattern := range []string{"*.hcl", "*.hcl.json"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, matches...)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .hcl or .hcl.json files are in %s", name)
+	}
+	sort.Strings(paths)
+
+	parser := hclparse.NewParser()
+	var files []*hcl.File
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		filename := name + "/" + filepath.Base(path)
+		var file *hcl.File
+		var diags hcl.Diagnostics
+		if strings.HasSuffix(path, ".json") {
+			file, diags = parser.ParseJSON(data, filename)
+		} else {
+			file, diags = parser.ParseHCL(data, filename)
+		}
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		files = append(files, file)
+	}
+
+	return hcl.MergeFiles(files), nil
+}
+
+// importedBody is the body of the configuration with the stanzas of each
+// app merged over the imports it lists.
+type importedBody struct {
+	hcl.Body
+
+	ctx     *hcl.EvalContext
+	imports map[string]hcl.Body
+}
+
+func (b *importedBody) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostics) {
+	content, diags := b.Body.Content(schema)
+	if content != nil {
+		diags = append(diags, b.mergeApps(content)...)
+	}
+
+	return content, diags
+}
+
+func (b *importedBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	content, remain, diags := b.Body.PartialContent(schema)
+	if content != nil {
+		diags = append(diags, b.mergeApps(content)...)
+	}
+
+	return content, remain, diags
+}
+
+// mergeApps replaces the body of each app block that has imports with
+// the body merged over them. Later imports take precedence over earlier
+// ones and the app takes precedence over all of them.
+func (b *importedBody) mergeApps(content *hcl.BodyContent) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for i, block := range content.Blocks {
+		if block.Type != "app" {
+			continue
+		}
+
+		attrs, _, moreDiags := block.Body.PartialContent(&hcl.BodySchema{
+			Attributes: []hcl.AttributeSchema{{Name: "import"}},
+		})
+		diags = append(diags, moreDiags...)
+		attr, ok := attrs.Attributes["import"]
+		if !ok {
+			continue
+		}
+
+		var names []string
+		if moreDiags := gohcl.DecodeExpression(attr.Expr, b.ctx, &names); moreDiags.HasErrors() {
+			diags = append(diags, moreDiags...)
+			continue
+		}
+
+		var body hcl.Body
+		for _, name := range names {
+			imported, ok := b.imports[name]
+			if !ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unknown import",
+					Detail:   fmt.Sprintf("No import block is named %q.", name),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+
+			if body == nil {
+				body = imported
+			} else {
+				body = &mergedBody{base: body, override: imported}
+			}
+		}
+		if body == nil {
+			continue
+		}
+
+		merged := *block
+		merged.Body = &mergedBody{base: body, override: block.Body}
+		content.Blocks[i] = &merged
+	}
+
+	return diags
+}
+
+// mergedBody is a body with override merged over base. An attribute in
+// override replaces the attribute in base. If both have exactly one block
+// of a type with the same labels, the blocks are merged the same way.
+// Otherwise, the blocks of a type in override replace the blocks of that
+// type in base.
+type mergedBody struct {
+	base     hcl.Body
+	override hcl.Body
+}
+
+// MergedBodies returns the bodies that body was merged from if it is the
+// body of an app, or one of its blocks, merged over imports. This returns
+// nil for other bodies.
+func MergedBodies(body hcl.Body) []hcl.Body {
+	b, ok := body.(*mergedBody)
+	if !ok {
+		return nil
+	}
+
+	return []hcl.Body{b.base, b.override}
+}
+
+func (b *mergedBody) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostics) {
+	// Required attributes may be set by either body, so they are checked
+	// once the bodies are merged.
+	optional := mergedSchema(schema)
+	base, diags := b.base.Content(optional)
+	override, moreDiags := b.override.Content(optional)
+	diags = append(diags, moreDiags...)
+
+	content := mergeContent(base, override)
+	diags = append(diags, b.checkRequired(schema, content)...)
+	return content, diags
+}
+
+func (b *mergedBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	optional := mergedSchema(schema)
+	base, baseRemain, diags := b.base.PartialContent(optional)
+	override, overrideRemain, moreDiags := b.override.PartialContent(optional)
+	diags = append(diags, moreDiags...)
+
+	content := mergeContent(base, override)
+	diags = append(diags, b.checkRequired(schema, content)...)
+	return content, &mergedBody{base: baseRemain, override: overrideRemain}, diags
+}
+
+func (b *mergedBody) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
+	base, diags := b.base.JustAttributes()
+	override, moreDiags := b.override.JustAttributes()
+	diags = append(diags, moreDiags...)
+
+	result := hcl.Attributes{}
+	for name, attr := range base {
+		result[name] = attr
+	}
+	for name, attr := range override {
+		result[name] = attr
+	}
+
+	return result, diags
+}
+
+func (b *mergedBody) MissingItemRange() hcl.Range {
+	return b.override.MissingItemRange()
+}
+
+// checkRequired returns an error for each required attribute of the schema
+// that neither body sets.
+func (b *mergedBody) checkRequired(schema *hcl.BodySchema, content *hcl.BodyContent) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, attr := range schema.Attributes {
+		if _, ok := content.Attributes[attr.Name]; attr.Required && !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing required argument",
+				Detail: fmt.Sprintf(
+					"The argument %q is required, but neither the app nor its imports set it.",
+					attr.Name),
+				Subject: b.MissingItemRange().Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
+// mergedSchema returns the schema with every attribute optional.
+func mergedSchema(schema *hcl.BodySchema) *hcl.BodySchema {
+	result := &hcl.BodySchema{Blocks: schema.Blocks}
+	for _, attr := range schema.Attributes {
+		attr.Required = false
+		result.Attributes = append(result.Attributes, attr)
+	}
+
+	return result
+}
+
+// mergeContent returns the content of override merged over base.
+func mergeContent(base, override *hcl.BodyContent) *hcl.BodyContent {
+	result := &hcl.BodyContent{
+		Attributes:       hcl.Attributes{},
+		MissingItemRange: override.MissingItemRange,
+	}
+	for name, attr := range base.Attributes {
+		result.Attributes[name] = attr
+	}
+	for name, attr := range override.Attributes {
+		result.Attributes[name] = attr
+	}
+
+	baseBlocks := base.Blocks.ByType()
+	overrideBlocks := override.Blocks.ByType()
+	for _, block := range base.Blocks {
+		if _, ok := overrideBlocks[block.Type]; !ok {
+			result.Blocks = append(result.Blocks, block)
+		}
+	}
+	for _, block := range override.Blocks {
+		bases := baseBlocks[block.Type]
+		if len(bases) == 1 && len(overrideBlocks[block.Type]) == 1 &&
+			labelsEqual(bases[0].Labels, block.Labels) {
+			merged := *block
+			merged.Body = &mergedBody{base: bases[0].Body, override: block.Body}
+			block = &merged
+		}
+
+		result.Blocks = append(result.Blocks, block)
+	}
+
+	return result
+}
+
+func labelsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestLoad_imports(t *testing.T) {
+	load := func(name string) (*Config, error) {
+		path := filepath.Join("testdata", "imports", name)
+		return Load(path, EvalContext(filepath.Dir(path)), nil)
+	}
+	attrs := func(t *testing.T, body hcl.Body) map[string]cty.Value {
+		attrs, diags := body.JustAttributes()
+		require.False(t, diags.HasErrors(), diags.Error())
+
+		result := map[string]cty.Value{}
+		for name, attr := range attrs {
+			v, diags := attr.Expr.Value(nil)
+			require.False(t, diags.HasErrors(), diags.Error())
+			result[name] = v
+		}
+
+		return result
+	}
+
+	cfg, err := load("waypoint.hcl")
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	t.Run("merged blocks", func(t *testing.T) {
+		require := require.New(t)
+
+		app, ok := cfg.AppConfig("web")
+		require.True(ok)
+		require.Equal(map[string]string{"team": "platform"}, app.Labels)
+		require.Equal("docker", app.Build.Use.Type)
+		require.Equal("docker", app.Build.Registry.Use.Type)
+		require.Equal("kubernetes", app.Deploy.Use.Type)
+		require.Equal(map[string]cty.Value{
+			"probe_path": cty.StringVal("/health"),
+			"replicas":   cty.NumberIntVal(3),
+		}, attrs(t, app.Deploy.Use.Body))
+	})
+
+	t.Run("replaced blocks", func(t *testing.T) {
+		require := require.New(t)
+
+		app, ok := cfg.AppConfig("worker")
+		require.True(ok)
+		require.Equal("docker", app.Build.Use.Type)
+		require.Equal("nomad", app.Deploy.Use.Type)
+		require.Empty(attrs(t, app.Deploy.Use.Body))
+	})
+
+	t.Run("without imports", func(t *testing.T) {
+		require := require.New(t)
+
+		app, ok := cfg.AppConfig("local")
+		require.True(ok)
+		require.Nil(app.Labels)
+		require.Nil(app.Build.Registry)
+		require.Equal("pack", app.Build.Use.Type)
+	})
+
+	t.Run("unknown import", func(t *testing.T) {
+		_, err := load("unknown.hcl")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `No import block is named "other"`)
+	})
+}
@@ -3,18 +3,30 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // Filename is the default filename for the Waypoint configuration.
 const Filename = "waypoint.hcl"
 
+// FilenameJSON is the filename for the Waypoint configuration in the JSON
+// syntax of HCL, such as configuration generated by other tools. It is
+// used if a directory has no Filename.
+const FilenameJSON = Filename + ".json"
+
 // FindPath looks for our configuration file starting at "start" and
 // traversing parent directories until it is found. If it is found, the
 // path is returned. If it is not found, an empty string is returned.
 // Error will be non-nil only if an error occurred.
 //
 // If start is empty, start will be the current working directory. If
-// filename is empty, it will default to the Filename constant.
+// filename is empty, it will default to the Filename constant, or the
+// FilenameJSON constant for directories that only have that.
 func FindPath(start, filename string) (string, error) {
 	var err error
 	if start == "" {
@@ -24,16 +36,19 @@ func FindPath(start, filename string) (string, error) {
 		}
 	}
 
+	filenames := []string{filename}
 	if filename == "" {
-		filename = Filename
+		filenames = []string{Filename, FilenameJSON}
 	}
 
 	for {
-		path := filepath.Join(start, filename)
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
-		} else if !os.IsNotExist(err) {
-			return "", err
+		for _, filename := range filenames {
+			path := filepath.Join(start, filename)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			} else if !os.IsNotExist(err) {
+				return "", err
+			}
 		}
 
 		next := filepath.Dir(start)
@@ -44,3 +59,128 @@ func FindPath(start, filename string) (string, error) {
 		start = next
 	}
 }
+
+// FilenameIn returns the path of the configuration file in dir. This is
+// Filename, or FilenameJSON if dir only has that.
+func FilenameIn(dir string) string {
+	path := filepath.Join(dir, Filename)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if _, err := os.Stat(filepath.Join(dir, FilenameJSON)); err == nil {
+			return filepath.Join(dir, FilenameJSON)
+		}
+	}
+
+	return path
+}
+
+// LoadOption is an option for Load.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	data bool
+}
+
+// WithData makes Load fetch the values of the data sources. This runs
+// commands and calls other services, so only the runner of an operation
+// does it. Without it, the data sources are checked but their values are
+// unknown.
+func WithData() LoadOption {
+	return func(opts *loadOptions) {
+		opts.data = true
+	}
+}
+
+// Load loads the configuration at path. Paths ending in ".json" are parsed
+// with the JSON syntax of HCL and other paths with the native syntax; the
+// configuration is the same in both. The input variables are set from
+// their defaults and the assigned values, which may be nil, and are added
+// to ctx as "var", and the data sources are added as "data". The rest of
+// the configuration, including the plugin configuration that is decoded
+// later with ctx, can then refer to them. Imports are fetched and each app
+// is merged over the imports it lists.
+func Load(path string, ctx *hcl.EvalContext, assigned map[string]*VariableValue, opts ...LoadOption) (*Config, error) {
+	var lopts loadOptions
+	for _, opt := range opts {
+		opt(&lopts)
+	}
+
+	parser := hclparse.NewParser()
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(path, ".json") {
+		file, diags = parser.ParseJSONFile(path)
+	} else {
+		file, diags = parser.ParseHCLFile(path)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// The variables are decoded first since the rest of the configuration
+	// can refer to them. Their defaults can't refer to other variables.
+	var pre variablesOnly
+	if diags := gohcl.DecodeBody(file.Body, ctx, &pre); diags.HasErrors() {
+		return nil, diags
+	}
+
+	values, err := variableValues(pre.Variables, assigned)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.Variables == nil {
+		ctx.Variables = map[string]cty.Value{}
+	}
+	ctx.Variables["var"] = cty.ObjectVal(values)
+	if err := validateVariables(ctx, pre.Variables); err != nil {
+		return nil, err
+	}
+
+	// The data sources are fetched next so that the rest of the
+	// configuration can refer to them. They can refer to the variables.
+	var data dataOnly
+	if diags := gohcl.DecodeBody(file.Body, ctx, &data); diags.HasErrors() {
+		return nil, diags
+	}
+	if err := dataValues(ctx, filepath.Dir(path), data.Data, lopts.data); err != nil {
+		return nil, err
+	}
+
+	// The imports are fetched before the apps are decoded since the apps
+	// are merged over them.
+	var imports importsOnly
+	if diags := gohcl.DecodeBody(file.Body, ctx, &imports); diags.HasErrors() {
+		return nil, diags
+	}
+
+	bodies, err := importBodies(filepath.Dir(path), imports.Imports)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &importedBody{Body: file.Body, ctx: ctx, imports: bodies}
+
+	var result Config
+	if diags := gohcl.DecodeBody(body, ctx, &result); diags.HasErrors() {
+		if len(data.Data) > 0 && !lopts.data {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Data sources are only fetched by the runner",
+				Detail: "The values of data sources are unknown outside of the runner " +
+					"of an operation, so only the configuration of plugins can refer to them.",
+			})
+		}
+
+		return nil, diags
+	}
+
+	// The constraints are parsed now so that invalid ones fail before any
+	// plugin is launched.
+	if result.RequiredPlugins != nil {
+		if err := result.RequiredPlugins.decode(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return &result, nil
+}
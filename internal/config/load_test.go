@@ -1,11 +1,15 @@
 package config
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 func TestFindPath(t *testing.T) {
@@ -45,3 +49,128 @@ func TestFindPath_notFound(t *testing.T) {
 	require.NoError(t, err)
 	require.Empty(t, result)
 }
+
+func TestFindPath_json(t *testing.T) {
+	require := require.New(t)
+
+	start := filepath.Join("testdata", "findpath-json")
+	expected := filepath.Join(start, FilenameJSON)
+
+	result, err := FindPath(start, "")
+	require.NoError(err)
+	require.Equal(expected, result)
+	require.Equal(expected, FilenameIn(start))
+
+	// The native syntax takes precedence if both files exist
+	require.Equal(
+		filepath.Join("testdata", "json", Filename),
+		FilenameIn(filepath.Join("testdata", "json")))
+}
+
+func TestLoad_json(t *testing.T) {
+	defer os.Unsetenv("WP_TEST_JSON_REGION")
+	os.Setenv("WP_TEST_JSON_REGION", "eu-west-1")
+
+	load := func(t *testing.T, name string) map[string]interface{} {
+		path := filepath.Join("testdata", "json", name)
+		ctx := EvalContext(filepath.Dir(path))
+		cfg, err := Load(path, ctx, nil)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate())
+
+		return configSummary(t, cfg, ctx)
+	}
+
+	require.Equal(t, load(t, Filename), load(t, FilenameJSON))
+}
+
+// configSummary returns the values of the configuration without the
+// source ranges of its bodies, so that configurations written in the
+// native and the JSON syntax can be compared.
+func configSummary(t *testing.T, cfg *Config, ctx *hcl.EvalContext) map[string]interface{} {
+	ctx = ctx.NewChild()
+	ctx.Variables = map[string]cty.Value{
+		"artifact": cty.ObjectVal(map[string]cty.Value{
+			"image": cty.StringVal("example/nginx"),
+		}),
+		"matrix": MatrixValue(map[string]string{"arch": "amd64"}),
+	}
+
+	value := func(v cty.Value) string {
+		data, err := ctyjson.Marshal(v, v.Type())
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	use := func(u *Use) map[string]string {
+		if u == nil {
+			return nil
+		}
+
+		attrs, diags := u.Body.JustAttributes()
+		require.False(t, diags.HasErrors(), diags.Error())
+
+		result := map[string]string{"type": u.Type}
+		for name, attr := range attrs {
+			v, diags := attr.Expr.Value(ctx)
+			require.False(t, diags.HasErrors(), diags.Error())
+			result[name] = value(v)
+		}
+
+		return result
+	}
+
+	result := map[string]interface{}{
+		"project":  cfg.Project,
+		"labels":   cfg.Labels,
+		"var":      value(ctx.Variables["var"]),
+		"data":     value(ctx.Variables["data"]),
+		"priority": cfg.Jobs.Priority,
+	}
+
+	var plugins []RequiredPlugin
+	for _, p := range cfg.RequiredPlugins.Plugins {
+		plugins = append(plugins, RequiredPlugin{
+			Name:    p.Name,
+			Source:  p.Source,
+			Version: p.Version,
+		})
+	}
+	result["required_plugins"] = plugins
+
+	var config []*ConfigValues
+	for _, c := range cfg.Config {
+		values, err := c.Values(ctx)
+		require.NoError(t, err)
+		config = append(config, values)
+	}
+	result["config"] = config
+
+	for _, app := range cfg.Apps {
+		production := app.Scoped("production", nil)
+		eu := app.Scoped("default", map[string]string{"region": "eu"})
+
+		summary := map[string]interface{}{
+			"path":       app.Path,
+			"labels":     app.Labels,
+			"depends_on": app.DependsOn,
+			"runner":     app.RunnerLabels("build"),
+			"build":      use(app.Build.Use),
+			"variants":   app.Build.Variants(),
+			"hooks":      app.Build.Hooks,
+			"deploy":     use(app.Deploy.Use),
+			"production": use(production.Deploy.Use),
+		}
+		if app.Build.Registry != nil {
+			summary["registry"] = use(app.Build.Registry.Use)
+		}
+		if app.Release != nil {
+			summary["release"] = use(app.Release.Use)
+			summary["eu"] = use(eu.Release.Use)
+		}
+
+		result["app."+app.Name] = summary
+	}
+
+	return result
+}
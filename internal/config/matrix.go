@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// MatrixLabelPrefix is the prefix of the labels that Waypoint sets on the
+// builds and artifacts of a variant of the build matrix, such as
+// "waypoint/matrix/arch" for the "arch" key.
+const MatrixLabelPrefix = "waypoint/matrix/"
+
+// Variants returns each combination of the values of the build matrix,
+// such as { arch = "amd64" } and { arch = "arm64" }. The keys are combined
+// in sorted order and the values in the order they are listed, so the
+// first variant has the first value of every key. This returns nil if the
+// build has no matrix.
+func (b *Build) Variants() []map[string]string {
+	if b == nil || len(b.Matrix) == 0 {
+		return nil
+	}
+
+	var keys []string
+	for k := range b.Matrix {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := []map[string]string{{}}
+	for _, k := range keys {
+		var next []map[string]string
+		for _, variant := range result {
+			for _, v := range b.Matrix[k] {
+				m := map[string]string{k: v}
+				for vk, vv := range variant {
+					m[vk] = vv
+				}
+
+				next = append(next, m)
+			}
+		}
+
+		result = next
+	}
+
+	return result
+}
+
+// MatrixLabels returns the labels of the builds and artifacts of the
+// variant.
+func MatrixLabels(variant map[string]string) map[string]string {
+	if len(variant) == 0 {
+		return nil
+	}
+
+	result := map[string]string{}
+	for k, v := range variant {
+		result[MatrixLabelPrefix+k] = v
+	}
+
+	return result
+}
+
+// MatrixValue returns the value of the "matrix" variable of the variant,
+// which has an attribute for each key of the matrix.
+func MatrixValue(variant map[string]string) cty.Value {
+	attrs := map[string]cty.Value{}
+	for k, v := range variant {
+		attrs[k] = cty.StringVal(v)
+	}
+
+	return cty.ObjectVal(attrs)
+}
+
+// ArtifactLabels returns the labels that the artifact to deploy must
+// have. These select the variant of the build matrix that the deploy
+// stanza selects, or nil if it doesn't select one.
+func (app *App) ArtifactLabels() map[string]string {
+	if app.Deploy == nil {
+		return nil
+	}
+
+	return MatrixLabels(app.Deploy.Matrix)
+}
+
+// validateMatrix checks that the build matrix has values and that the
+// variant that the deploy stanza selects is in it.
+func validateMatrix(b *Build, d *Deploy) error {
+	var result error
+	if b != nil {
+		for k, vs := range b.Matrix {
+			if !hclsyntax.ValidIdentifier(k) {
+				result = multierror.Append(result, fmt.Errorf(
+					"build: matrix: %q must be a valid identifier", k))
+			}
+			if len(vs) == 0 {
+				result = multierror.Append(result, fmt.Errorf(
+					"build: matrix: %q must have at least one value", k))
+			}
+		}
+	}
+
+	if d != nil && len(d.Matrix) > 0 {
+		for k, v := range d.Matrix {
+			var values []string
+			if b != nil {
+				values = b.Matrix[k]
+			}
+
+			found := false
+			for _, bv := range values {
+				found = found || bv == v
+			}
+			if !found {
+				result = multierror.Append(result, fmt.Errorf(
+					"deploy: matrix: %s = %q isn't a variant of the build matrix", k, v))
+			}
+		}
+	}
+
+	return result
+}
@@ -14,22 +14,38 @@ func (c *Config) Plugins() []*Plugin {
 		known[p.Name] = p
 	}
 
-	// Collect all the plugins used by all the apps.
-	for _, app := range c.Apps {
+	// Collect all the plugins used by all the apps, including the stanzas
+	// of their workspace and label overrides.
+	track := func(b *Build, d *Deploy, r *Release) {
 		// Get all the implied stage plugins: build, deploy, etc.
-		if v := app.Build; v != nil {
+		if v := b; v != nil {
 			result = trackPlugin(result, known, v.Use, component.BuilderType)
 			if v := v.Registry; v != nil {
 				result = trackPlugin(result, known, v.Use, component.RegistryType)
 			}
 		}
-		if v := app.Deploy; v != nil {
+		if v := d; v != nil {
 			result = trackPlugin(result, known, v.Use, component.PlatformType)
 		}
-		if v := app.Release; v != nil {
+		if v := r; v != nil {
 			result = trackPlugin(result, known, v.Use, component.ReleaseManagerType)
 		}
 	}
+	for _, app := range c.Apps {
+		track(app.Build, app.Deploy, app.Release)
+		for _, o := range app.WorkspaceOverrides {
+			track(o.Build, o.Deploy, o.Release)
+		}
+		for _, o := range app.LabelOverrides {
+			track(o.Build, o.Deploy, o.Release)
+		}
+	}
+
+	// Attach the requirements so that the plugin is checked when it is
+	// discovered.
+	for _, p := range result {
+		p.Required = c.RequiredPlugins.Required(p.Name)
+	}
 
 	return result
 }
@@ -71,6 +87,10 @@ type Plugin struct {
 
 	// Checksum is the SHA256 checksum to validate this plugin.
 	Checksum string `hcl:"checksum,optional"`
+
+	// Required is the entry for this plugin in required_plugins, if any.
+	// This is set by Config.Plugins.
+	Required *RequiredPlugin
 }
 
 // Types returns the list of types that this plugin implements.
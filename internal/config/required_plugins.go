@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// RequiredPlugins is the required_plugins block. It has an attribute for
+// each plugin with the source and versions that the configuration needs,
+// such as `pack = { version = ">= 0.2.0" }`.
+type RequiredPlugins struct {
+	Attrs hcl.Attributes `hcl:",remain"`
+
+	// Plugins are the decoded attributes, sorted by name. This is set by
+	// Load.
+	Plugins []*RequiredPlugin
+}
+
+// RequiredPlugin is the source and version constraint of a plugin.
+type RequiredPlugin struct {
+	Name string
+
+	// Source is where the plugin is published, such as
+	// "github.com/example/waypoint-plugin-example". This is shown in errors
+	// so that operators know where to get a plugin that is missing or has
+	// the wrong version.
+	Source string
+
+	// Version is the version constraint, such as ">= 1.2, < 2.0". If this
+	// is empty, any version is accepted.
+	Version string
+
+	// Constraints is Version parsed. This is nil if Version is empty.
+	Constraints version.Constraints
+}
+
+// Required returns the requirements of the named plugin, or nil if the
+// configuration has none for it.
+func (r *RequiredPlugins) Required(name string) *RequiredPlugin {
+	if r == nil {
+		return nil
+	}
+
+	for _, p := range r.Plugins {
+		if p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// decode sets Plugins from the attributes of the block.
+func (r *RequiredPlugins) decode(ctx *hcl.EvalContext) error {
+	r.Plugins = nil
+	for name, attr := range r.Attrs {
+		val, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return diags
+		}
+
+		p, err := requiredPlugin(name, val)
+		if err != nil {
+			return fmt.Errorf("required_plugins %q: %s", name, err)
+		}
+
+		r.Plugins = append(r.Plugins, p)
+	}
+
+	sort.Slice(r.Plugins, func(i, j int) bool {
+		return r.Plugins[i].Name < r.Plugins[j].Name
+	})
+
+	return nil
+}
+
+// requiredPlugin decodes the object value of a required_plugins attribute.
+func requiredPlugin(name string, val cty.Value) (*RequiredPlugin, error) {
+	ty := val.Type()
+	if val.IsNull() || !val.IsKnown() || !(ty.IsObjectType() || ty.IsMapType()) {
+		return nil, fmt.Errorf("must be an object with source and version")
+	}
+
+	result := &RequiredPlugin{Name: name}
+	for it := val.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		if v.IsNull() {
+			continue
+		}
+		if !v.IsKnown() || v.Type() != cty.String {
+			return nil, fmt.Errorf("%s must be a string", k.AsString())
+		}
+
+		switch k.AsString() {
+		case "source":
+			result.Source = v.AsString()
+
+		case "version":
+			result.Version = v.AsString()
+
+		default:
+			return nil, fmt.Errorf("unsupported argument %q, expected source or version",
+				k.AsString())
+		}
+	}
+
+	if result.Version != "" {
+		constraints, err := version.NewConstraint(result.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %s", result.Version, err)
+		}
+
+		result.Constraints = constraints
+	}
+
+	return result, nil
+}
+
+// Check returns an error if v doesn't meet the version constraint. v is
+// nil if the version of the plugin is unknown, which doesn't meet any
+// constraint.
+func (p *RequiredPlugin) Check(v *version.Version) error {
+	if p == nil || p.Constraints == nil {
+		return nil
+	}
+
+	if v != nil && p.Constraints.Check(v) {
+		return nil
+	}
+
+	found := "an unknown version"
+	if v != nil {
+		found = "version " + v.String()
+	}
+
+	msg := fmt.Sprintf(
+		"plugin %q is %s, which doesn't satisfy the constraint %q from required_plugins",
+		p.Name, found, p.Version)
+	if p.Source != "" {
+		msg += fmt.Sprintf(". Install a version that does from %s", p.Source)
+	}
+
+	return errors.New(msg)
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_requiredPlugins(t *testing.T) {
+	load := func(name string) (*Config, error) {
+		path := filepath.Join("testdata", "required_plugins", name)
+		return Load(path, EvalContext(filepath.Dir(path)), nil)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		require := require.New(t)
+
+		cfg, err := load("waypoint.hcl")
+		require.NoError(err)
+		require.Len(cfg.RequiredPlugins.Plugins, 2)
+
+		example := cfg.RequiredPlugins.Required("example")
+		require.NotNil(example)
+		require.Equal("github.com/example/waypoint-plugin-example", example.Source)
+		require.Equal("~> 1.2", example.Version)
+		require.Nil(cfg.RequiredPlugins.Required("docker"))
+
+		// The requirements are attached to the plugins that are used
+		plugins := map[string]*Plugin{}
+		for _, p := range cfg.Plugins() {
+			plugins[p.Name] = p
+		}
+		require.Equal(example, plugins["example"].Required)
+		require.Nil(plugins["docker"].Required)
+	})
+
+	t.Run("invalid constraint", func(t *testing.T) {
+		_, err := load("invalid.hcl")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid version constraint")
+	})
+
+	t.Run("unsupported argument", func(t *testing.T) {
+		_, err := load("unsupported.hcl")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `unsupported argument "checksum"`)
+	})
+}
+
+func TestRequiredPluginCheck(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "required_plugins", "waypoint.hcl"),
+		EvalContext("."), nil)
+	require.NoError(t, err)
+	example := cfg.RequiredPlugins.Required("example")
+
+	cases := []struct {
+		Name    string
+		Plugin  *RequiredPlugin
+		Version string
+		Err     string
+	}{
+		{
+			"no requirement",
+			nil,
+			"",
+			"",
+		},
+
+		{
+			"satisfied",
+			example,
+			"1.4.0",
+			"",
+		},
+
+		{
+			"not satisfied",
+			example,
+			"2.0.0",
+			`plugin "example" is version 2.0.0, which doesn't satisfy the constraint "~> 1.2"`,
+		},
+
+		{
+			"unknown version",
+			example,
+			"",
+			"an unknown version",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			var v *version.Version
+			if tt.Version != "" {
+				v = version.Must(version.NewVersion(tt.Version))
+			}
+
+			err := tt.Plugin.Check(v)
+			if tt.Err == "" {
+				require.NoError(err)
+				return
+			}
+
+			require.Error(err)
+			require.Contains(err.Error(), tt.Err)
+			require.Contains(err.Error(), example.Source)
+		})
+	}
+}
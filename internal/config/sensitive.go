@@ -0,0 +1,70 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/hashicorp/waypoint/internal/config/funcs"
+)
+
+// SensitiveRedacted replaces sensitive values in output.
+const SensitiveRedacted = "(sensitive)"
+
+// sensitiveMinLength is the length of the shortest value that is redacted.
+// Shorter values would redact unrelated output.
+const sensitiveMinLength = 4
+
+// Sensitive is the set of values that the configuration marked with the
+// sensitive function, so that they can be redacted from output. Plugins
+// still receive the values as is. This is safe for concurrent use.
+type Sensitive struct {
+	mu     sync.Mutex
+	values []string
+}
+
+// Track sets the sensitive function of ctx to add the values it marks to s.
+// This must be called before the configuration is decoded with ctx.
+func (s *Sensitive) Track(ctx *hcl.EvalContext) {
+	for k, v := range funcs.Sensitive(s.add) {
+		ctx.Functions[k] = v
+	}
+}
+
+func (s *Sensitive) add(v string) {
+	if len(v) < sensitiveMinLength {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.values {
+		if existing == v {
+			return
+		}
+	}
+
+	// Longer values are replaced first in case they contain shorter ones.
+	s.values = append(s.values, v)
+	sort.Slice(s.values, func(i, j int) bool {
+		return len(s.values[i]) > len(s.values[j])
+	})
+}
+
+// Redact returns v with the sensitive values replaced. This can be called
+// on a nil Sensitive, which redacts nothing.
+func (s *Sensitive) Redact(v string) string {
+	if s == nil {
+		return v
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, value := range s.values {
+		v = strings.Replace(v, value, SensitiveRedacted, -1)
+	}
+
+	return v
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSensitive(t *testing.T) {
+	require := require.New(t)
+
+	var s Sensitive
+	ctx := EvalContext("testdata")
+	s.Track(ctx)
+
+	expr, diags := hclsyntax.ParseExpression(
+		[]byte(`{ password = sensitive("hunter2"), token = sensitive("abcd1234"), pin = sensitive("12") }`),
+		"test.hcl", hcl.Pos{Line: 1, Column: 1})
+	require.False(diags.HasErrors(), diags.Error())
+	_, diags = expr.Value(ctx)
+	require.False(diags.HasErrors(), diags.Error())
+
+	require.Equal(
+		"password=(sensitive) token=(sensitive) pin=12",
+		s.Redact("password=hunter2 token=abcd1234 pin=12"))
+
+	// A nil set redacts nothing
+	var empty *Sensitive
+	require.Equal("hunter2", empty.Redact("hunter2"))
+}
@@ -1,10 +1,29 @@
 package config
 
+import (
+	"github.com/hashicorp/hcl/v2/hclsimple"
+)
+
 // ServerConfig is the configuration for the built-in server.
 type ServerConfig struct {
 	// DBPath is the path to the database file, including the filename.
 	DBPath string `hcl:"db_path,attr"`
 
+	// PostgresURL is the URL of a PostgreSQL database to store state in.
+	// If this is set, DBPath is ignored.
+	PostgresURL string `hcl:"postgres_url,optional"`
+
+	// LeaderAddr is the address of this server that the other servers
+	// sharing the PostgreSQL database send runners to while this server is
+	// the leader. This must reach this server itself, unlike the address
+	// in CEBConfig which may be a load balancer for all the servers.
+	LeaderAddr string `hcl:"leader_addr,optional"`
+
+	// KeyringPath is the path to the keyring file with the keys that
+	// encrypt the secrets in the database, such as config variables and
+	// tokens. If this is empty, the secrets are stored as plaintext.
+	KeyringPath string `hcl:"keyring_path,optional"`
+
 	// GRPC is the grpc service listening configuration. This is required.
 	GRPC Listener `hcl:"grpc,block"`
 
@@ -16,6 +35,186 @@ type ServerConfig struct {
 
 	// CEBConfig configures the entrypoint binary for deployments
 	CEBConfig *CEBConfig `hcl:"entrypoint_config,block"`
+
+	// LoginTokenTTL is how long the tokens created with "waypoint token new"
+	// are valid if they have no lifetime, such as "720h".
+	LoginTokenTTL string `hcl:"login_token_ttl,optional"`
+
+	// OIDC configures logging in users with an OpenID Connect provider.
+	OIDC *OIDC `hcl:"oidc,block"`
+
+	// Audit configures exporting the audit log.
+	Audit *Audit `hcl:"audit,block"`
+
+	// RateLimit configures limiting how often expensive endpoints can be
+	// called.
+	RateLimit *RateLimit `hcl:"rate_limit,block"`
+
+	// ExecRecording configures recording exec sessions.
+	ExecRecording *ExecRecording `hcl:"exec_recording,block"`
+
+	// Logs configures the history of log lines that the server keeps.
+	Logs *Logs `hcl:"logs,block"`
+
+	// LogExport configures forwarding the log lines of deployments to
+	// external sinks.
+	LogExport *LogExport `hcl:"log_export,block"`
+}
+
+// ServerReload are the settings of the server that can be set in a file
+// with "waypoint server run -config". The file is loaded again when the
+// server receives SIGHUP and the settings are applied without restarting.
+type ServerReload struct {
+	URL           *URL           `hcl:"url,block"`
+	Audit         *Audit         `hcl:"audit,block"`
+	RateLimit     *RateLimit     `hcl:"rate_limit,block"`
+	ExecRecording *ExecRecording `hcl:"exec_recording,block"`
+	LogExport     *LogExport     `hcl:"log_export,block"`
+}
+
+// LoadServerReload loads the reloadable server settings from the HCL or
+// JSON file at path.
+func LoadServerReload(path string) (*ServerReload, error) {
+	var result ServerReload
+	if err := hclsimple.DecodeFile(path, nil, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// RateLimit is the configuration for limiting how often the endpoints that
+// are expensive for the server, such as queueing jobs and streaming logs,
+// can be called. Limited calls fail with the ResourceExhausted code.
+type RateLimit struct {
+	// TokenRate is how many calls per second each token can make to each
+	// limited endpoint and TokenBurst how many calls it can make at once.
+	// Calls aren't limited per token if TokenRate is zero.
+	TokenRate  float64 `hcl:"token_rate,optional"`
+	TokenBurst int     `hcl:"token_burst,optional"`
+
+	// IPRate and IPBurst limit the calls of each IP address the same way.
+	IPRate  float64 `hcl:"ip_rate,optional"`
+	IPBurst int     `hcl:"ip_burst,optional"`
+
+	// Endpoints are the names of the endpoints to limit, such as "QueueJob".
+	// This defaults to the endpoints that queue jobs and stream logs.
+	Endpoints []string `hcl:"endpoints,optional"`
+}
+
+// ExecRecording is the configuration for recording the input and output of
+// "waypoint exec" sessions on the server. Recordings can be retrieved with
+// "waypoint exec-recording".
+type ExecRecording struct {
+	// Enabled records every exec session.
+	Enabled bool `hcl:"enabled,optional"`
+
+	// SkipInput only records the output of sessions and not what is typed
+	// into them, which may include secrets.
+	SkipInput bool `hcl:"skip_input,optional"`
+
+	// Retention is how long recordings are kept, such as "2160h". This
+	// defaults to 30 days.
+	Retention string `hcl:"retention,optional"`
+
+	// MaxBytes is the maximum size of the transcript of a session. Later
+	// input and output isn't recorded. This defaults to 10 MiB.
+	MaxBytes int `hcl:"max_bytes,optional"`
+}
+
+// Logs is the configuration for the log lines that the server keeps after
+// they're streamed from the entrypoints, so that "waypoint logs" can show
+// the history of a deployment and not only new lines.
+type Logs struct {
+	// MaxLines is the maximum number of lines kept for each deployment.
+	// This defaults to 2000. If this is negative, no lines are kept.
+	MaxLines int `hcl:"max_lines,optional"`
+
+	// MaxAge is how long lines are kept, such as "72h". This defaults to
+	// 24 hours.
+	MaxAge string `hcl:"max_age,optional"`
+
+	// Persist writes the lines to the database, so that they are kept
+	// when the server restarts. Otherwise they're only kept in memory.
+	Persist bool `hcl:"persist,optional"`
+}
+
+// LogExport is the configuration for forwarding the log lines that are
+// streamed from the entrypoints to external sinks. Lines are sent in
+// batches in the background, so exporting doesn't slow down deployments.
+type LogExport struct {
+	// Syslog sends each line to the local syslog daemon with SyslogTag as
+	// the tag, which defaults to "waypoint".
+	Syslog    bool   `hcl:"syslog,optional"`
+	SyslogTag string `hcl:"syslog_tag,optional"`
+
+	// Loki pushes lines to a Grafana Loki server.
+	Loki *LogExportLoki `hcl:"loki,block"`
+
+	// CloudWatch writes lines to AWS CloudWatch Logs.
+	CloudWatch *LogExportCloudWatch `hcl:"cloudwatch,block"`
+}
+
+// LogExportLoki is the configuration for pushing log lines to Loki. Each
+// line has the labels "project", "app", "workspace", "deployment_id" and
+// "instance_id" in addition to Labels.
+type LogExportLoki struct {
+	// URL is the push API endpoint, such as
+	// "http://loki:3100/loki/api/v1/push".
+	URL string `hcl:"url"`
+
+	// TenantID is sent as the X-Scope-OrgID header if set.
+	TenantID string `hcl:"tenant_id,optional"`
+
+	// Labels are added to every line.
+	Labels map[string]string `hcl:"labels,optional"`
+}
+
+// LogExportCloudWatch is the configuration for writing log lines to
+// CloudWatch Logs. The lines of each instance are written to a stream
+// named "<project>/<app>/<deployment ID>/<instance ID>", which is created
+// if it doesn't exist. Credentials are read from the environment.
+type LogExportCloudWatch struct {
+	// Region is the AWS region, which defaults to the environment.
+	Region string `hcl:"region,optional"`
+
+	// Group is the name of the log group, which must exist.
+	Group string `hcl:"group"`
+}
+
+// Audit is the configuration for exporting the audit log. Events are
+// always stored by the server, these are additional destinations that
+// each event is written to as a line of JSON.
+type Audit struct {
+	// File is the path of a file to append events to.
+	File string `hcl:"file,optional"`
+
+	// Syslog sends events to the local syslog daemon with SyslogTag as the
+	// tag, which defaults to "waypoint".
+	Syslog    bool   `hcl:"syslog,optional"`
+	SyslogTag string `hcl:"syslog_tag,optional"`
+}
+
+// OIDC is the configuration for logging in with an OpenID Connect provider.
+type OIDC struct {
+	// Issuer is the URL of the provider, which must serve the discovery
+	// document at "/.well-known/openid-configuration".
+	Issuer string `hcl:"issuer,attr"`
+
+	// ClientID and ClientSecret are the credentials registered with the
+	// provider for the server.
+	ClientID     string `hcl:"client_id,attr"`
+	ClientSecret string `hcl:"client_secret,attr"`
+
+	// Scopes are requested in addition to "openid".
+	Scopes []string `hcl:"scopes,optional"`
+
+	// AllowedRedirectURIs are the URIs the provider may redirect back to.
+	// This defaults to the address the CLI listens on during "waypoint login".
+	AllowedRedirectURIs []string `hcl:"allowed_redirect_uris,optional"`
+
+	// TokenTTL is how long login tokens for users are valid, such as "24h".
+	TokenTTL string `hcl:"token_ttl,optional"`
 }
 
 // CEBConfig is specific configuration for the entrypoint binaries
@@ -31,6 +230,12 @@ type Listener struct {
 	TLSDisable  bool   `hcl:"tls_disable,optional"`
 	TLSCertFile string `hcl:"tls_cert_file,optional"`
 	TLSKeyFile  string `hcl:"tls_key_file,optional"`
+
+	// TLSRunnerCAFile and TLSEntrypointCAFile are the CA certificates that
+	// the client certificates of runners and entrypoints must be signed by.
+	// Client certificates aren't required if these aren't set.
+	TLSRunnerCAFile     string `hcl:"tls_runner_ca_file,optional"`
+	TLSEntrypointCAFile string `hcl:"tls_entrypoint_ca_file,optional"`
 }
 
 // URL is the configuration for the URL service.
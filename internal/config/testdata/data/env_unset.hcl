@@ -0,0 +1,5 @@
+project = "foo"
+
+data "env" "unset" {
+  name = "WP_TEST_DATA_UNSET"
+}
@@ -0,0 +1,17 @@
+project = "foo"
+
+data "exec" "fail" {
+  command = ["false"]
+}
+
+app "web" {
+  build {
+    use "docker" {
+      tag = data.exec.fail.output
+    }
+  }
+
+  deploy {
+    use "docker" {}
+  }
+}
@@ -0,0 +1,5 @@
+project = "foo"
+
+data "vault" "secret" {
+  path = "secret/foo"
+}
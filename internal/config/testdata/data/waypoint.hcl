@@ -0,0 +1,50 @@
+project = "foo"
+
+data "terraform_remote_state" "network" {
+  backend = "local"
+  config = {
+    path = "terraform.tfstate"
+  }
+}
+
+data "consul" "alb" {
+  key = "apps/web/alb_arn"
+}
+
+data "consul" "missing" {
+  key     = "apps/web/missing"
+  default = "none"
+}
+
+data "env" "region" {
+  name = "WP_TEST_DATA_REGION"
+}
+
+data "exec" "version" {
+  command = ["echo", "  1.2.3  "]
+}
+
+data "exec" "json" {
+  command = ["echo", "{\"replicas\": 3}"]
+  format  = "json"
+}
+
+app "web" {
+  labels = {
+    cluster = data.terraform_remote_state.network.outputs.cluster_name
+    subnet  = data.terraform_remote_state.network.outputs.subnet_ids[1]
+    alb     = data.consul.alb.value
+    missing = data.consul.missing.value
+    region  = data.env.region.value
+    version = data.exec.version.output
+    json    = data.exec.json.output.replicas
+  }
+
+  build {
+    use "docker" {}
+  }
+
+  deploy {
+    use "docker" {}
+  }
+}
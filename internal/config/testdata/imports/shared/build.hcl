@@ -0,0 +1,14 @@
+labels = {
+  team = "platform"
+}
+
+build {
+  use "docker" {}
+
+  registry {
+    use "docker" {
+      image = "registry.example.com/app"
+      tag   = "latest"
+    }
+  }
+}
@@ -0,0 +1,6 @@
+deploy {
+  use "kubernetes" {
+    probe_path = "/health"
+    replicas   = 1
+  }
+}
@@ -0,0 +1,9 @@
+project = "imports"
+
+import "standard" {
+  source = "./shared"
+}
+
+app "web" {
+  import = ["other"]
+}
@@ -0,0 +1,33 @@
+project = "imports"
+
+import "standard" {
+  source = "./shared"
+}
+
+app "web" {
+  import = ["standard"]
+
+  deploy {
+    use "kubernetes" {
+      replicas = 3
+    }
+  }
+}
+
+app "worker" {
+  import = ["standard"]
+
+  deploy {
+    use "nomad" {}
+  }
+}
+
+app "local" {
+  build {
+    use "pack" {}
+  }
+
+  deploy {
+    use "docker" {}
+  }
+}
@@ -0,0 +1,115 @@
+project = "parity"
+
+labels = { team = "platform" }
+
+variable "image" {
+  type    = string
+  default = "nginx"
+}
+
+variable "replicas" {
+  type    = number
+  default = 2
+
+  validation {
+    condition     = var.replicas > 0
+    error_message = "replicas must be at least 1."
+  }
+}
+
+data "env" "region" {
+  name    = "WP_TEST_JSON_REGION"
+  default = "us-east-1"
+}
+
+required_plugins {
+  example = {
+    source  = "github.com/example/waypoint-plugin-example"
+    version = ">= 1.0"
+  }
+}
+
+jobs {
+  priority = 5
+}
+
+config {
+  env = {
+    DB_HOST     = "db.${data.env.region.value}.internal"
+    DB_PASSWORD = sensitive("hunter2")
+  }
+}
+
+app "api" {
+  build {
+    use "docker" {}
+  }
+
+  deploy {
+    use "docker" {}
+  }
+}
+
+app "web" {
+  path       = "./web"
+  depends_on = ["api"]
+
+  labels = {
+    image = var.image
+    upper = upper(var.image)
+  }
+
+  runner {
+    labels = { arch = "arm64" }
+  }
+
+  build {
+    matrix = { arch = ["amd64", "arm64"] }
+
+    use "docker" {
+      dockerfile = "Dockerfile.${matrix.arch}"
+    }
+
+    registry {
+      use "docker" {
+        image = "example/${var.image}"
+        tag   = "latest"
+      }
+    }
+
+    hook {
+      when    = "before"
+      command = ["echo", "building"]
+      capture = "version"
+    }
+  }
+
+  deploy {
+    use "kubernetes" {
+      image    = artifact.image
+      replicas = var.replicas
+    }
+  }
+
+  release {
+    use "kubernetes" {
+      port = 80
+    }
+  }
+
+  workspace "production" {
+    deploy {
+      use "kubernetes" {
+        replicas = 5
+      }
+    }
+  }
+
+  label "region" "eu" {
+    release {
+      use "kubernetes" {
+        port = 443
+      }
+    }
+  }
+}
@@ -0,0 +1,7 @@
+project = "foo"
+
+required_plugins {
+  example = {
+    checksum = "abc"
+  }
+}
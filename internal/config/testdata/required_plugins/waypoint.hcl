@@ -0,0 +1,22 @@
+project = "foo"
+
+required_plugins {
+  pack = {
+    version = ">= 0.1.0"
+  }
+
+  example = {
+    source  = "github.com/example/waypoint-plugin-example"
+    version = "~> 1.2"
+  }
+}
+
+app "web" {
+  build {
+    use "example" {}
+  }
+
+  deploy {
+    use "docker" {}
+  }
+}
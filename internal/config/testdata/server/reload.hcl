@@ -0,0 +1,9 @@
+url {
+  enabled     = true
+  api_address = "api.example.com:443"
+}
+
+rate_limit {
+  token_rate = 2.5
+  endpoints  = ["QueueJob"]
+}
@@ -0,0 +1,2 @@
+image    = "caddy"
+replicas = 3
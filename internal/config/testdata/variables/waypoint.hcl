@@ -0,0 +1,42 @@
+project = "variables"
+
+variable "image" {
+  type    = string
+  default = "nginx"
+}
+
+variable "replicas" {
+  type    = number
+  default = 1
+
+  validation {
+    condition     = var.replicas > 0
+    error_message = "replicas must be at least 1."
+  }
+}
+
+variable "regions" {
+  type    = list(string)
+  default = ["us-east-1"]
+}
+
+variable "region" {
+  description = "The region to deploy to."
+}
+
+app "web" {
+  labels = {
+    image    = var.image
+    replicas = var.replicas
+    region   = var.region
+    regions  = join(",", var.regions)
+  }
+
+  build {
+    use "docker" {}
+  }
+
+  deploy {
+    use "docker" {}
+  }
+}
@@ -2,11 +2,13 @@ package config
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 )
 
 // internalValidator is an interface implemented internally for validation.
@@ -27,6 +29,21 @@ func (c *Config) Validate() error {
 		if err := app.Validate(); err != nil {
 			result = multierror.Append(result, err)
 		}
+
+		for _, dep := range app.DependsOn {
+			if _, ok := c.AppConfig(dep); !ok || dep == app.Name {
+				result = multierror.Append(result, fmt.Errorf(
+					"app[%s]: depends_on: %q must be another app in the project", app.Name, dep))
+			}
+		}
+	}
+
+	// Only look for cycles if the names are valid since unknown names
+	// would look like a cycle.
+	if result == nil {
+		if _, err := c.AppsOrdered(); err != nil {
+			result = multierror.Append(result, err)
+		}
 	}
 
 	return result
@@ -55,6 +72,13 @@ func (app *App) Validate() error {
 		}
 	}
 
+	for _, pattern := range app.PathGlobs {
+		if err := validatePathGlob(pattern); err != nil {
+			result = multierror.Append(result, fmt.Errorf(
+				"path_globs: %q %s", pattern, err))
+		}
+	}
+
 	// Build and deploy are currently required.
 	if app.Build == nil {
 		result = multierror.Append(result, fmt.Errorf(
@@ -64,6 +88,18 @@ func (app *App) Validate() error {
 		result = multierror.Append(result, fmt.Errorf(
 			"deploy: a deployment platform must be configured"))
 	}
+	if err := validateMatrix(app.Build, app.Deploy); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	pipelines := map[string]bool{}
+	for _, p := range app.Pipelines {
+		if pipelines[p.Name] {
+			result = multierror.Append(result, fmt.Errorf(
+				"pipeline[%s]: is declared more than once", p.Name))
+		}
+		pipelines[p.Name] = true
+	}
 
 	for k, v := range app.validatorChildren() {
 		if v != nil {
@@ -87,11 +123,45 @@ func (app *App) validatorChildren() map[string]internalValidator {
 		result["build.registry"] = app.Build.Registry
 	}
 
+	if app.Scan != nil {
+		result["scan"] = app.Scan
+	}
+
+	for _, p := range app.Pipelines {
+		result[fmt.Sprintf("pipeline[%s]", p.Name)] = p
+	}
+
+	add := func(prefix string, b *Build, d *Deploy, r *Release) {
+		if b != nil {
+			result[prefix+".build"] = b
+			if b.Registry != nil {
+				result[prefix+".build.registry"] = b.Registry
+			}
+		}
+		if d != nil {
+			result[prefix+".deploy"] = d
+		}
+		if r != nil {
+			result[prefix+".release"] = r
+		}
+	}
+	for _, o := range app.WorkspaceOverrides {
+		add(fmt.Sprintf("workspace[%s]", o.Workspace), o.Build, o.Deploy, o.Release)
+	}
+	for _, o := range app.LabelOverrides {
+		add(fmt.Sprintf("label[%s=%s]", o.Key, o.Value), o.Build, o.Deploy, o.Release)
+	}
+
 	return result
 }
 
 func (c *Build) validate(key string) error {
-	return c.Operation().validate(key)
+	if c == nil {
+		return c.Operation().validate(key)
+	}
+
+	return multierror.Append(c.Operation().validate(key),
+		noDestroyHooks(key, c.Hooks)...).ErrorOrNil()
 }
 
 func (c *Deploy) validate(key string) error {
@@ -99,13 +169,88 @@ func (c *Deploy) validate(key string) error {
 }
 
 func (c *Registry) validate(key string) error {
-	return c.Operation().validate(key)
+	if c == nil {
+		return c.Operation().validate(key)
+	}
+
+	return multierror.Append(c.Operation().validate(key),
+		noDestroyHooks(key, c.Hooks)...).ErrorOrNil()
+}
+
+// noDestroyHooks returns an error for each destroy hook, for stages that
+// don't create anything that is destroyed.
+func noDestroyHooks(key string, hooks []*Hook) []error {
+	var result []error
+	for i, h := range hooks {
+		if h.Destroy {
+			result = append(result, fmt.Errorf(
+				"%s: hook[%d]: destroy hooks are only supported in deploy and release", key, i))
+		}
+	}
+
+	return result
 }
 
 func (c *Release) validate(key string) error {
 	return c.Operation().validate(key)
 }
 
+func (c *Scan) validate(key string) error {
+	var result error
+
+	switch c.Scanner {
+	case "", "trivy", "grype":
+	default:
+		result = multierror.Append(result, fmt.Errorf("scanner must be 'trivy' or 'grype'"))
+	}
+
+	switch c.FailOn {
+	case "", "negligible", "low", "medium", "high", "critical":
+	default:
+		result = multierror.Append(result, fmt.Errorf(
+			"fail_on must be one of 'negligible', 'low', 'medium', 'high' or 'critical'"))
+	}
+
+	return multierror.Prefix(result, fmt.Sprintf("%s:", key))
+}
+
+func (c *Pipeline) validate(key string) error {
+	var result error
+
+	if len(c.Steps) == 0 {
+		result = multierror.Append(result, fmt.Errorf("at least one step is required"))
+	}
+
+	steps := map[string]bool{}
+	for _, s := range c.Steps {
+		if steps[s.Name] {
+			result = multierror.Append(result, fmt.Errorf(
+				"step[%s]: is declared more than once", s.Name))
+		}
+		steps[s.Name] = true
+
+		set := 0
+		for _, v := range []bool{s.Operation != "", len(s.Exec) > 0, s.Approval != ""} {
+			if v {
+				set++
+			}
+		}
+		if set != 1 {
+			result = multierror.Append(result, fmt.Errorf(
+				"step[%s]: exactly one of operation, exec or approval must be set", s.Name))
+		}
+
+		switch s.Operation {
+		case "", "build", "deploy", "release":
+		default:
+			result = multierror.Append(result, fmt.Errorf(
+				"step[%s]: operation must be 'build', 'deploy' or 'release'", s.Name))
+		}
+	}
+
+	return multierror.Prefix(result, fmt.Sprintf("%s:", key))
+}
+
 func (c *Operation) validate(key string) error {
 	if c == nil {
 		return nil
@@ -135,9 +280,9 @@ func (h *Hook) validate(key string) error {
 	var result error
 
 	switch h.When {
-	case "before", "after":
+	case "before", "after", "failure":
 	default:
-		result = multierror.Append(result, fmt.Errorf("label must be 'before' or 'after'"))
+		result = multierror.Append(result, fmt.Errorf("when must be 'before', 'after' or 'failure'"))
 	}
 
 	if len(h.Command) == 0 {
@@ -150,6 +295,10 @@ func (h *Hook) validate(key string) error {
 		result = multierror.Append(result, fmt.Errorf("on_failure must be 'continue' or 'fail'"))
 	}
 
+	if h.Capture != "" && !hclsyntax.ValidIdentifier(h.Capture) {
+		result = multierror.Append(result, fmt.Errorf("capture must be a valid identifier"))
+	}
+
 	return multierror.Prefix(result, fmt.Sprintf("%s:", key))
 }
 
@@ -180,3 +329,25 @@ func ValidateLabels(labels map[string]string) []error {
 }
 
 var hostnameRegexRFC952 = regexp.MustCompile(`^[a-zA-Z]([a-zA-Z0-9\-]+[\.]?)*[a-zA-Z0-9]$`)
+
+// validatePathGlob checks that the pattern is a relative path glob. Each
+// element must be "**" or a valid path.Match pattern.
+func validatePathGlob(pattern string) error {
+	if pattern == "" || strings.HasPrefix(pattern, "/") {
+		return fmt.Errorf("must be a relative path")
+	}
+
+	for _, part := range strings.Split(pattern, "/") {
+		if part == ".." {
+			return fmt.Errorf("must not contain .. entries")
+		}
+		if part == "**" {
+			continue
+		}
+		if _, err := path.Match(part, ""); err != nil {
+			return fmt.Errorf("is not a valid glob: %s", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// VariableEnvPrefix is the prefix of environment variables that set input
+// variables, such as WP_VAR_image for the variable "image".
+const VariableEnvPrefix = "WP_VAR_"
+
+// Variable is an input variable of the configuration. The rest of the
+// configuration refers to its value as var.<name>.
+type Variable struct {
+	Name        string `hcl:",label"`
+	Description string `hcl:"description,optional"`
+
+	// Type is the type constraint, such as string or list(number). If this
+	// isn't set, any value is accepted.
+	Type hcl.Expression `hcl:"type,optional"`
+
+	// Default is the value if none is assigned. If this isn't set, a value
+	// must be assigned.
+	Default cty.Value `hcl:"default,optional"`
+
+	Validations []*VariableValidation `hcl:"validation,block"`
+}
+
+// VariableValidation is a condition that the value of a variable must meet.
+type VariableValidation struct {
+	Condition    hcl.Expression `hcl:"condition,attr"`
+	ErrorMessage string         `hcl:"error_message,attr"`
+}
+
+// VariableValue is a value assigned to an input variable.
+type VariableValue struct {
+	// Raw is a value from the command line or environment. Values of
+	// string, number and bool variables are used as is and values of other
+	// types are parsed as HCL, such as `["a", "b"]`.
+	Raw string
+
+	// Expr is the value as an HCL expression, such as from a variable
+	// file. This is used instead of Raw if it is set.
+	Expr hcl.Expression
+
+	// Source describes where the value was assigned for errors.
+	Source string
+
+	// env is true if the value is from the environment.
+	env bool
+}
+
+// ParseVariableFlag parses a "name=value" assignment, such as from -var.
+func ParseVariableFlag(s string) (string, *VariableValue, error) {
+	idx := strings.Index(s, "=")
+	if idx <= 0 {
+		return "", nil, fmt.Errorf(
+			"variable assignment %q must be in the form name=value", s)
+	}
+
+	return s[:idx], &VariableValue{Raw: s[idx+1:], Source: "-var"}, nil
+}
+
+// LoadVariableFile loads the values assigned in a variable file. The file
+// has an attribute for each variable, such as `image = "nginx"`.
+func LoadVariableFile(path string) (map[string]*VariableValue, error) {
+	parser := hclparse.NewParser()
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(path, ".json") {
+		file, diags = parser.ParseJSONFile(path)
+	} else {
+		file, diags = parser.ParseHCLFile(path)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	result := map[string]*VariableValue{}
+	for name, attr := range attrs {
+		result[name] = &VariableValue{Expr: attr.Expr, Source: path}
+	}
+
+	return result, nil
+}
+
+// EnvVariables returns the values assigned with WP_VAR_ environment
+// variables. environ is in the format of os.Environ.
+func EnvVariables(environ []string) map[string]*VariableValue {
+	result := map[string]*VariableValue{}
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, VariableEnvPrefix) {
+			continue
+		}
+
+		kv = kv[len(VariableEnvPrefix):]
+		idx := strings.Index(kv, "=")
+		if idx <= 0 {
+			continue
+		}
+
+		result[kv[:idx]] = &VariableValue{
+			Raw:    kv[idx+1:],
+			Source: "environment variable " + VariableEnvPrefix + kv[:idx],
+			env:    true,
+		}
+	}
+
+	return result
+}
+
+// VariableExpr returns the value as HCL expression source. This is used
+// to send assigned values to runners, which parse them with an Expr.
+func VariableExpr(v cty.Value) string {
+	return string(hclwrite.TokensForValue(v).Bytes())
+}
+
+// variablesOnly is used to decode the variable blocks before the rest of
+// the configuration.
+type variablesOnly struct {
+	Variables []*Variable `hcl:"variable,block"`
+	Remain    hcl.Body    `hcl:",remain"`
+}
+
+// variableValues returns the value of each variable from its default and
+// the assigned values. Every assigned value must be for a declared variable
+// unless it is from the environment, since the environment may be shared by
+// other projects.
+func variableValues(
+	vars []*Variable,
+	assigned map[string]*VariableValue,
+) (map[string]cty.Value, error) {
+	result := map[string]cty.Value{}
+	declared := map[string]bool{}
+	for _, v := range vars {
+		if !hclsyntax.ValidIdentifier(v.Name) {
+			return nil, fmt.Errorf("variable %q: the name must be a valid identifier", v.Name)
+		}
+		if declared[v.Name] {
+			return nil, fmt.Errorf("variable %q: is declared more than once", v.Name)
+		}
+		declared[v.Name] = true
+
+		ty := cty.DynamicPseudoType
+		if !variableExprNull(v.Type) {
+			var diags hcl.Diagnostics
+			ty, diags = typeexpr.TypeConstraint(v.Type)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+		}
+
+		val := v.Default
+		source := "default"
+		if a, ok := assigned[v.Name]; ok {
+			var err error
+			val, err = a.value(ty)
+			if err != nil {
+				return nil, fmt.Errorf("variable %q: invalid value from %s: %s",
+					v.Name, a.Source, err)
+			}
+
+			source = a.Source
+		}
+		if val.IsNull() {
+			return nil, fmt.Errorf("variable %q: a value is required. Set it with -var, "+
+				"-var-file, or the %s%s environment variable", v.Name, VariableEnvPrefix, v.Name)
+		}
+
+		val, err := convert.Convert(val, ty)
+		if err != nil {
+			return nil, fmt.Errorf("variable %q: invalid value from %s: %s",
+				v.Name, source, err)
+		}
+
+		result[v.Name] = val
+	}
+
+	var unknown []string
+	for name, a := range assigned {
+		if !declared[name] && !a.env {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("values were assigned to undeclared variables: %s",
+			strings.Join(unknown, ", "))
+	}
+
+	return result, nil
+}
+
+// validateVariables checks the validation conditions of the variables.
+// ctx must have the values of the variables.
+func validateVariables(ctx *hcl.EvalContext, vars []*Variable) error {
+	for _, v := range vars {
+		for _, validation := range v.Validations {
+			result, diags := validation.Condition.Value(ctx)
+			if diags.HasErrors() {
+				return diags
+			}
+
+			result, err := convert.Convert(result, cty.Bool)
+			if err != nil || result.IsNull() || !result.IsKnown() {
+				return fmt.Errorf("variable %q: the validation condition must be a bool", v.Name)
+			}
+			if result.False() {
+				return fmt.Errorf("variable %q: %s", v.Name, validation.ErrorMessage)
+			}
+		}
+	}
+
+	return nil
+}
+
+// value returns the assigned value for a variable of the type.
+func (a *VariableValue) value(ty cty.Type) (cty.Value, error) {
+	expr := a.Expr
+	if expr == nil {
+		if ty == cty.DynamicPseudoType || ty.IsPrimitiveType() {
+			return cty.StringVal(a.Raw), nil
+		}
+
+		var diags hcl.Diagnostics
+		expr, diags = hclsyntax.ParseExpression([]byte(a.Raw), a.Source, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return cty.NilVal, diags
+		}
+	}
+
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	return val, nil
+}
+
+// variableExprNull returns true if the expression is the null expression
+// that gohcl sets for optional attributes that aren't set.
+func variableExprNull(expr hcl.Expression) bool {
+	if expr == nil {
+		return true
+	}
+
+	// Type constraints are keywords, which fail to evaluate without a
+	// context, so only the null expression evaluates successfully.
+	val, diags := expr.Value(nil)
+	return !diags.HasErrors() && val.IsNull()
+}
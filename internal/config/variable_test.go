@@ -0,0 +1,131 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+func TestLoad_variables(t *testing.T) {
+	path := filepath.Join("testdata", "variables", "waypoint.hcl")
+	load := func(assigned map[string]*VariableValue) (*Config, error) {
+		return Load(path, EvalContext(filepath.Dir(path)), assigned)
+	}
+	labels := func(cfg *Config) map[string]string {
+		app, ok := cfg.AppConfig("web")
+		require.True(t, ok)
+		return app.Labels
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		require := require.New(t)
+
+		cfg, err := load(EnvVariables([]string{"WP_VAR_region=eu-west-1"}))
+		require.NoError(err)
+		require.Equal(map[string]string{
+			"image":    "nginx",
+			"replicas": "1",
+			"region":   "eu-west-1",
+			"regions":  "us-east-1",
+		}, labels(cfg))
+		require.Len(cfg.Variables, 4)
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		require := require.New(t)
+
+		assigned := EnvVariables([]string{
+			"WP_VAR_region=eu-west-1",
+			"WP_VAR_image=httpd",
+			"WP_VAR_other=ignored",
+		})
+		values, err := LoadVariableFile(filepath.Join("testdata", "variables", "values.hcl"))
+		require.NoError(err)
+		for k, v := range values {
+			assigned[k] = v
+		}
+		for _, raw := range []string{"replicas=5", `regions=["a", "b"]`} {
+			name, v, err := ParseVariableFlag(raw)
+			require.NoError(err)
+			assigned[name] = v
+		}
+
+		cfg, err := load(assigned)
+		require.NoError(err)
+		require.Equal(map[string]string{
+			"image":    "caddy",
+			"replicas": "5",
+			"region":   "eu-west-1",
+			"regions":  "a,b",
+		}, labels(cfg))
+	})
+
+	t.Run("required", func(t *testing.T) {
+		_, err := load(nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "WP_VAR_region")
+	})
+
+	t.Run("undeclared", func(t *testing.T) {
+		_, v, err := ParseVariableFlag("nope=1")
+		require.NoError(t, err)
+
+		_, err = load(map[string]*VariableValue{
+			"region": {Raw: "eu-west-1"},
+			"nope":   v,
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "nope")
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := load(map[string]*VariableValue{
+			"region":   {Raw: "eu-west-1"},
+			"replicas": {Raw: "many", Source: "-var"},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `variable "replicas"`)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := load(map[string]*VariableValue{
+			"region":   {Raw: "eu-west-1"},
+			"replicas": {Raw: "0"},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "replicas must be at least 1.")
+	})
+}
+
+func TestParseVariableFlag(t *testing.T) {
+	require := require.New(t)
+
+	name, v, err := ParseVariableFlag("url=http://a?b=c")
+	require.NoError(err)
+	require.Equal("url", name)
+	require.Equal("http://a?b=c", v.Raw)
+
+	_, _, err = ParseVariableFlag("=value")
+	require.Error(err)
+	_, _, err = ParseVariableFlag("value")
+	require.Error(err)
+}
+
+func TestVariableExpr(t *testing.T) {
+	require := require.New(t)
+
+	val := cty.ObjectVal(map[string]cty.Value{
+		"names": cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+		"count": cty.NumberIntVal(2),
+	})
+
+	v := &VariableValue{Raw: VariableExpr(val)}
+	result, err := v.value(val.Type())
+	require.NoError(err)
+	result, err = convert.Convert(result, val.Type())
+	require.NoError(err)
+	require.True(result.RawEquals(val))
+}
@@ -0,0 +1,53 @@
+package configsource
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// awsSSMSource reads values from the AWS SSM Parameter Store. SecureString
+// parameters are decrypted.
+//
+// The source is configured with the "region", which defaults to the
+// environment like the credentials. Variables are configured with the
+// "path" of the parameter.
+type awsSSMSource struct {
+	client *ssm.SSM
+}
+
+func newAWSSSMSource(config map[string]string) (Sourcer, error) {
+	cfg := aws.NewConfig()
+	if region := config["region"]; region != "" {
+		cfg = cfg.WithRegion(region)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *cfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &awsSSMSource{client: ssm.New(sess)}, nil
+}
+
+func (s *awsSSMSource) Read(ctx context.Context, config map[string]string) (string, error) {
+	values, err := required(config, "path")
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.GetParameterWithContext(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(values[0]),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.StringValue(resp.Parameter.Value), nil
+}
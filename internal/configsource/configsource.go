@@ -0,0 +1,65 @@
+// Package configsource has the sources that the entrypoint reads the
+// values of dynamic config variables from, so that secrets are read at
+// runtime instead of being stored on the Waypoint server.
+package configsource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sourcer is implemented by all config sources and is responsible for
+// reading the values of dynamic config variables.
+type Sourcer interface {
+	// Read returns the value of a variable. The config tells the source
+	// where the value is, such as the path and key of a secret.
+	Read(ctx context.Context, config map[string]string) (string, error)
+}
+
+// FromString maps the type of a source to its implementation. The config
+// is the configuration of the source that is set on the server, such as
+// its address and credentials. It may be empty, in which case the source
+// uses its defaults.
+var FromString = map[string]func(config map[string]string) (Sourcer, error){
+	"aws-ssm":           newAWSSSMSource,
+	"gcp-secretmanager": newGCPSecretManagerSource,
+	"vault":             newVaultSource,
+}
+
+// New returns the source of the type with the config.
+func New(typ string, config map[string]string) (Sourcer, error) {
+	f, ok := FromString[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown config source %q, must be one of: %s",
+			typ, strings.Join(Types(), ", "))
+	}
+
+	return f(config)
+}
+
+// Types returns the types of all the sources, sorted.
+func Types() []string {
+	result := make([]string, 0, len(FromString))
+	for k := range FromString {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// required returns the values of the keys in the config of a variable,
+// or an error if any of them aren't set.
+func required(config map[string]string, keys ...string) ([]string, error) {
+	result := make([]string, len(keys))
+	for i, k := range keys {
+		result[i] = config[k]
+		if result[i] == "" {
+			return nil, fmt.Errorf("config %q must be set", k)
+		}
+	}
+
+	return result, nil
+}
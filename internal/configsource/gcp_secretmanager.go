@@ -0,0 +1,98 @@
+package configsource
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// gcpSecretManagerEndpoint is the address of the Secret Manager API.
+const gcpSecretManagerEndpoint = "https://secretmanager.googleapis.com/v1/"
+
+// gcpSecretManagerSource reads values from GCP Secret Manager.
+//
+// The source is configured with an optional "credentials_file", and uses
+// the application default credentials otherwise. Variables are configured
+// with the "project" and "secret", and optionally the "version", which
+// defaults to the latest version.
+type gcpSecretManagerSource struct {
+	client *http.Client
+}
+
+func newGCPSecretManagerSource(config map[string]string) (Sourcer, error) {
+	const scope = "https://www.googleapis.com/auth/cloud-platform"
+	ctx := context.Background()
+
+	var creds *google.Credentials
+	var err error
+	if path := config["credentials_file"]; path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		creds, err = google.CredentialsFromJSON(ctx, data, scope)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		creds, err = google.FindDefaultCredentials(ctx, scope)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &gcpSecretManagerSource{
+		client: oauth2.NewClient(ctx, creds.TokenSource),
+	}, nil
+}
+
+func (s *gcpSecretManagerSource) Read(ctx context.Context, config map[string]string) (string, error) {
+	values, err := required(config, "project", "secret")
+	if err != nil {
+		return "", err
+	}
+	version := config["version"]
+	if version == "" {
+		version = "latest"
+	}
+
+	name := fmt.Sprintf("projects/%s/secrets/%s/versions/%s",
+		url.PathEscape(values[0]), url.PathEscape(values[1]), url.PathEscape(version))
+	req, err := http.NewRequest("GET", gcpSecretManagerEndpoint+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error accessing secret %q: %s", name, resp.Status)
+	}
+
+	var secret struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("error decoding secret %q: %s", name, err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(secret.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("error decoding secret %q: %s", name, err)
+	}
+
+	return string(data), nil
+}
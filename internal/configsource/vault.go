@@ -0,0 +1,111 @@
+package configsource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// vaultSource reads values from the KV secrets engine of Vault. Both
+// versions of the engine are supported.
+//
+// The source is configured with "addr", "token" and "namespace", which
+// default to the VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE environment
+// variables. Variables are configured with the "path" of the secret, such
+// as "secret/data/db" for version 2 of the engine, and the "key" in it.
+type vaultSource struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+func newVaultSource(config map[string]string) (Sourcer, error) {
+	s := &vaultSource{
+		addr:      vaultConfig(config, "addr", "VAULT_ADDR"),
+		token:     vaultConfig(config, "token", "VAULT_TOKEN"),
+		namespace: vaultConfig(config, "namespace", "VAULT_NAMESPACE"),
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+	if s.addr == "" {
+		s.addr = "https://127.0.0.1:8200"
+	}
+	if s.token == "" {
+		return nil, fmt.Errorf("vault token must be configured or set with VAULT_TOKEN")
+	}
+
+	return s, nil
+}
+
+func (s *vaultSource) Read(ctx context.Context, config map[string]string) (string, error) {
+	values, err := required(config, "path", "key")
+	if err != nil {
+		return "", err
+	}
+	path, key := values[0], values[1]
+
+	req, err := http.NewRequest("GET",
+		strings.TrimSuffix(s.addr, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", s.token)
+	if s.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.namespace)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error reading vault secret %q: %s", path, resp.Status)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("error decoding vault secret %q: %s", path, err)
+	}
+
+	// Version 2 of the engine nests the data with its metadata.
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("vault secret %q has no key %q", path, key)
+	}
+	if v, ok := value.(string); ok {
+		return v, nil
+	}
+
+	// Other values are returned as JSON.
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// vaultConfig returns the config key, or the environment variable if the
+// key isn't set.
+func vaultConfig(config map[string]string, key, env string) string {
+	if v := config[key]; v != "" {
+		return v
+	}
+
+	return os.Getenv(env)
+}
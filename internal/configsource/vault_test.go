@@ -0,0 +1,68 @@
+package configsource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultSource(t *testing.T) {
+	require := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "secret-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/db":
+			w.Write([]byte(`{"data": {"data": {"password": "hunter2", "port": 5432}, "metadata": {"version": 1}}}`))
+
+		case "/v1/kv/db":
+			w.Write([]byte(`{"data": {"password": "hunter3"}}`))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s, err := New("vault", map[string]string{"addr": srv.URL, "token": "secret-token"})
+	require.NoError(err)
+
+	ctx := context.Background()
+	read := func(path, key string) (string, error) {
+		return s.Read(ctx, map[string]string{"path": path, "key": key})
+	}
+
+	// Version 2 of the engine
+	v, err := read("secret/data/db", "password")
+	require.NoError(err)
+	require.Equal("hunter2", v)
+
+	// Values that aren't strings are JSON
+	v, err = read("secret/data/db", "port")
+	require.NoError(err)
+	require.Equal("5432", v)
+
+	// Version 1 of the engine
+	v, err = read("kv/db", "password")
+	require.NoError(err)
+	require.Equal("hunter3", v)
+
+	// Missing keys, secrets and config
+	_, err = read("kv/db", "nope")
+	require.Error(err)
+	_, err = read("kv/nope", "password")
+	require.Error(err)
+	_, err = s.Read(ctx, map[string]string{"path": "kv/db"})
+	require.Error(err)
+
+	// Unknown sources
+	_, err = New("nope", nil)
+	require.Error(err)
+}
@@ -3,13 +3,18 @@ package core
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/go-argmapper"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/dynblock"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -50,6 +55,30 @@ type App struct {
 	mappers    []*argmapper.Func
 	components map[interface{}]*appComponent
 	closers    []func() error
+
+	// inprocess is the platform created in-process if it is one of
+	// plugin.InProcessPlatforms. This is used for the operations that can't
+	// be called over the plugin protocol, such as scaling.
+	inprocess component.Platform
+
+	// evalContext is the context the configuration of the components is
+	// evaluated with.
+	evalContext *hcl.EvalContext
+
+	// outputConfigs are the configurations of the components that refer to
+	// the outputs of earlier operations, such as artifact.image. These
+	// components are configured by configureOutputs when the operation runs.
+	outputConfigs map[interface{}]hcl.Body
+
+	// hookOutputs is the output of the hooks that captured it during this
+	// job, by the capture name.
+	hookOutputs map[string]string
+
+	// matrix is the variant of the build matrix that this app builds, or
+	// nil if the build has no matrix. rawConfig is the configuration before
+	// it was scoped, which the apps of the other variants are created from.
+	matrix    map[string]string
+	rawConfig *config.App
 }
 
 type appComponent struct {
@@ -66,32 +95,66 @@ type appComponent struct {
 
 	// Hooks are the hooks associated with this component keyed by their When value
 	Hooks map[string][]*config.Hook
+
+	// DestroyHooks are the hooks that run when the deployments or releases
+	// of this component are destroyed, keyed by their When value.
+	DestroyHooks map[string][]*config.Hook
+
+	// Config is the configuration of the component as it was written in
+	// the configuration file. This may be empty.
+	Config string
 }
 
 // newApp creates an App for the given project and configuration. This will
 // initialize and configure all the components of this application. An error
 // will be returned if this app fails to initialize: configuration is invalid,
 // a component could not be found, etc.
+//
+// matrix is the variant of the build matrix to build. If it is nil, the
+// first variant is built.
 func newApp(
 	ctx context.Context,
 	p *Project,
 	cfg *config.App,
 	evalContext *hcl.EvalContext,
+	matrix map[string]string,
 ) (*App, error) {
+	rawConfig := cfg
+
+	// Replace the stanzas that are overridden for the workspace or the
+	// labels of this operation.
+	cfg = cfg.Scoped(p.workspace, p.mergeLabels(cfg.Labels))
+
+	// The configuration of the variant refers to its values as matrix.<key>
+	if variants := cfg.Build.Variants(); matrix == nil && len(variants) > 0 {
+		matrix = variants[0]
+	}
+	if len(matrix) > 0 {
+		evalContext = evalContext.NewChild()
+		evalContext.Variables = map[string]cty.Value{
+			"matrix": config.MatrixValue(matrix),
+		}
+	}
+
 	// Initialize
 	app := &App{
-		project:    p,
-		client:     p.client,
-		source:     &component.Source{App: cfg.Name, Path: "."},
-		jobInfo:    p.jobInfo,
-		logger:     p.logger.Named("app").Named(cfg.Name),
-		components: make(map[interface{}]*appComponent),
+		project:       p,
+		client:        p.client,
+		source:        &component.Source{App: cfg.Name, Path: "."},
+		jobInfo:       p.jobInfo,
+		logger:        p.logger.Named("app").Named(cfg.Name),
+		components:    make(map[interface{}]*appComponent),
+		evalContext:   evalContext,
+		outputConfigs: make(map[interface{}]hcl.Body),
+		hookOutputs:   make(map[string]string),
 		ref: &pb.Ref_Application{
 			Application: cfg.Name,
 			Project:     p.name,
 		},
 		workspace: p.WorkspaceRef(),
 		config:    cfg,
+		matrix:    matrix,
+		rawConfig: rawConfig,
 
 		// very important below that we allocate a new slice since we modify
 		mappers: append([]*argmapper.Func{}, p.mappers...),
@@ -133,7 +196,13 @@ func newApp(
 			continue
 		}
 
-		err = app.initComponent(ctx, evalContext, c.Type, c.Target, p.factories[c.Type], c.Config, c.Config.Labels)
+		// The builds and artifacts of a variant have its labels
+		labels := c.Config.Labels
+		if c.Type == component.BuilderType || c.Type == component.RegistryType {
+			labels = labelsMerge(labels, config.MatrixLabels(matrix))
+		}
+
+		err = app.initComponent(ctx, evalContext, c.Type, c.Target, p.factories[c.Type], c.Config, labels)
 		if err != nil {
 			return nil, err
 		}
@@ -147,6 +216,14 @@ func newApp(
 		}
 	}
 
+	// Some operations can't be called over the plugin protocol, so if the
+	// platform is a builtin that supports them we create it in-process too.
+	if app.Platform != nil {
+		if err := app.initInProcess(evalContext, cfg.Deploy.Operation()); err != nil {
+			return nil, err
+		}
+	}
+
 	// If we don't have a releaser but our platform implements release then
 	// we use that.
 	if app.Releaser == nil && app.Platform != nil {
@@ -186,6 +263,36 @@ func (a *App) Close() error {
 	return nil
 }
 
+// Matrix returns the variant of the build matrix that this app builds, or
+// nil if the build has no matrix.
+func (a *App) Matrix() map[string]string {
+	return a.matrix
+}
+
+// Variants returns an app for each variant of the build matrix, starting
+// with this app, which builds the first variant. If the build has no
+// matrix, this only returns this app. The other apps are closed when this
+// app is closed.
+func (a *App) Variants(ctx context.Context) ([]*App, error) {
+	variants := a.config.Build.Variants()
+	if len(variants) == 0 {
+		return []*App{a}, nil
+	}
+
+	result := []*App{a}
+	for _, v := range variants[1:] {
+		app, err := newApp(ctx, a.project, a.rawConfig, a.project.evalContext, v)
+		if err != nil {
+			return nil, err
+		}
+
+		a.closers = append(a.closers, app.Close)
+		result = append(result, app)
+	}
+
+	return result, nil
+}
+
 // Ref returns the reference to this application for us in API calls.
 func (a *App) Ref() *pb.Ref_Application {
 	return a.ref
@@ -229,6 +336,8 @@ func (a *App) mergeLabels(ls ...map[string]string) map[string]string {
 //   * *component.Source
 //   * *datadir.Project
 //   * history.Client
+//   * *component.LabelSet with the labels of the operation, which merge
+//     the labels of the project, the app, and the component
 //
 func (a *App) callDynamicFunc(
 	ctx context.Context,
@@ -273,7 +382,9 @@ func (a *App) callDynamicFunc(
 			a.UI,
 		),
 
-		argmapper.Named("labels", &component.LabelSet{Labels: componentData.Labels}),
+		argmapper.Named("labels", &component.LabelSet{
+			Labels: a.mergeLabels(componentData.Labels),
+		}),
 	)
 
 	// Build the chain and call it
@@ -370,9 +481,15 @@ func (a *App) initComponent(
 
 	// Configure the component. This will handle all the cases where no
 	// config is given but required, vice versa, and everything in between.
-	diag := component.Configure(raw, cfg.Use.Body, evalContext)
-	if diag.HasErrors() {
-		return diag
+	// If the config refers to the outputs of earlier operations, it is
+	// configured once they are known.
+	if usesOutputs(cfg.Use.Body) {
+		a.outputConfigs[raw] = cfg.Use.Body
+	} else {
+		diag := configure(raw, cfg.Use.Body, evalContext)
+		if diag.HasErrors() {
+			return diag
+		}
 	}
 
 	// Assign our value now that we won't error anymore
@@ -380,7 +497,13 @@ func (a *App) initComponent(
 
 	// Setup our hooks
 	hooks := map[string][]*config.Hook{}
+	destroyHooks := map[string][]*config.Hook{}
 	for _, h := range cfg.Hooks {
+		if h.Destroy {
+			destroyHooks[h.When] = append(destroyHooks[h.When], h)
+			continue
+		}
+
 		hooks[h.When] = append(hooks[h.When], h)
 	}
 
@@ -391,11 +514,92 @@ func (a *App) initComponent(
 			Name: cfg.Use.Type,
 		},
 
-		Dir:    cdir,
-		Hooks:  hooks,
-		Labels: labels,
+		Dir:          cdir,
+		Hooks:        hooks,
+		DestroyHooks: destroyHooks,
+		Labels:       labels,
+		Config:       bodySource(cfg.Use.Body),
+	}
+
+	return nil
+}
+
+// configure configures the component with the body. Dynamic blocks in the
+// body are expanded so that repeated blocks, such as ports or mounts, can be
+// generated from a collection with for_each.
+func configure(c interface{}, body hcl.Body, evalContext *hcl.EvalContext) hcl.Diagnostics {
+	return component.Configure(c, dynblock.Expand(body, evalContext), evalContext)
+}
+
+// bodySource returns the source of the body as it was written in its
+// file, with the arguments of sensitive() calls redacted. This returns an
+// empty string if the body isn't HCL native syntax or the file can't be
+// read.
+func bodySource(body hcl.Body) string {
+	b, ok := body.(*hclsyntax.Body)
+	if !ok {
+		return ""
+	}
+
+	data, err := ioutil.ReadFile(b.SrcRange.Filename)
+	if err != nil {
+		return ""
+	}
+
+	start, end := b.SrcRange.Start.Byte, b.SrcRange.End.Byte
+	if start < 0 || end > len(data) || start > end {
+		return ""
+	}
+
+	var calls []hcl.Range
+	hclsyntax.VisitAll(b, func(n hclsyntax.Node) hcl.Diagnostics {
+		if call, ok := n.(*hclsyntax.FunctionCallExpr); ok && call.Name == "sensitive" {
+			calls = append(calls, call.Range())
+		}
+
+		return nil
+	})
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].Start.Byte < calls[j].Start.Byte
+	})
+
+	// Calls within a call that was already redacted are skipped.
+	var result strings.Builder
+	offset := start
+	for _, r := range calls {
+		if r.Start.Byte < offset || r.End.Byte > end {
+			continue
+		}
+
+		result.Write(data[offset:r.Start.Byte])
+		result.WriteString(`sensitive("` + config.SensitiveRedacted + `")`)
+		offset = r.End.Byte
+	}
+	result.Write(data[offset:end])
+
+	return result.String()
+}
+
+// initInProcess creates the in-process platform, if the platform is one of
+// plugin.InProcessPlatforms. It is configured the same as the platform.
+func (a *App) initInProcess(evalContext *hcl.EvalContext, cfg *config.Operation) error {
+	newPlatform, ok := plugin.InProcessPlatforms[cfg.Use.Type]
+	if !ok {
+		return nil
+	}
+
+	p := newPlatform()
+	if usesOutputs(cfg.Use.Body) {
+		a.outputConfigs[p] = cfg.Use.Body
+	} else {
+		diag := configure(p, cfg.Use.Body, evalContext)
+		if diag.HasErrors() {
+			return diag
+		}
 	}
 
+	a.inprocess = p
+	a.components[p] = a.components[a.Platform]
 	return nil
 }
 
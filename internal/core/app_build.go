@@ -35,11 +35,25 @@ func (a *App) Build(ctx context.Context, optFuncs ...BuildOption) (
 
 	// If we're not pushing, then we're done!
 	if !opts.Push {
-		return build, nil, nil
+		if a.config.Scan != nil {
+			build, err = a.scanBuild(ctx, build, build.Artifact.Artifact)
+		}
+
+		return build, nil, err
 	}
 
 	// We're also pushing to a registry, so invoke that.
 	artifact, err := a.PushBuild(ctx, PushWithBuild(build))
+	if err != nil {
+		return build, artifact, err
+	}
+
+	// Scan the pushed artifact since that is the image that will be
+	// deployed.
+	if a.config.Scan != nil {
+		build, err = a.scanBuild(ctx, build, artifact.Artifact.Artifact)
+	}
+
 	return build, artifact, err
 }
 
@@ -119,8 +133,10 @@ func (op *buildOperation) Upsert(
 	client pb.WaypointClient,
 	msg proto.Message,
 ) (proto.Message, error) {
+	build := msg.(*pb.Build)
 	resp, err := client.UpsertBuild(ctx, &pb.UpsertBuildRequest{
-		Build: msg.(*pb.Build),
+		Build:          build,
+		IdempotencyKey: jobIdempotencyKey(build.Id, build.JobId),
 	})
 	if err != nil {
 		return nil, err
@@ -130,6 +146,10 @@ func (op *buildOperation) Upsert(
 }
 
 func (op *buildOperation) Do(ctx context.Context, log hclog.Logger, app *App, _ proto.Message) (interface{}, error) {
+	if err := app.configureOutputs(ctx, app.Builder, &operationOutputs{}); err != nil {
+		return nil, err
+	}
+
 	return app.callDynamicFunc(ctx,
 		log,
 		(*component.Artifact)(nil),
@@ -60,3 +60,107 @@ app "test" {
 	}
 }
 `
+
+func TestAppBuild_labels(t *testing.T) {
+	require := require.New(t)
+
+	// Make our factory for platforms
+	mock := &componentmocks.Builder{}
+	factory := TestFactory(t, component.BuilderType)
+	TestFactoryRegister(t, factory, "test", mock)
+
+	// Make our app
+	app := TestApp(t, TestProject(t,
+		WithConfig(config.TestConfig(t, testBuildConfigLabels)),
+		WithFactory(component.BuilderType, factory),
+	), "test")
+
+	// The builder should get the labels of the project, app, and stage
+	var labels map[string]string
+	artifact := &componentmocks.Artifact{}
+	artifact.On("Labels").Return(map[string]string{})
+	mock.On("BuildFunc").Return(func(ls *component.LabelSet) component.Artifact {
+		labels = ls.Labels
+		return artifact
+	})
+
+	_, _, err := app.Build(context.Background())
+	require.NoError(err)
+	require.Equal("platform", labels["team"])
+	require.Equal("web", labels["service"])
+	require.Equal("build", labels["stage"])
+	require.Contains(labels, "waypoint/workspace")
+}
+
+const testBuildConfigLabels = `
+project = "test"
+
+labels = { team = "platform" }
+
+app "test" {
+	labels = { service = "web" }
+
+	build {
+		labels = { stage = "build" }
+
+		use "test" {}
+	}
+
+	deploy {
+		use "test" {}
+	}
+}
+`
+
+func TestAppBuild_matrix(t *testing.T) {
+	require := require.New(t)
+
+	// Make our factory for platforms
+	mock := &componentmocks.Builder{}
+	factory := TestFactory(t, component.BuilderType)
+	TestFactoryRegister(t, factory, "test", mock)
+
+	// Make our app
+	app := TestApp(t, TestProject(t,
+		WithConfig(config.TestConfig(t, testBuildConfigMatrix)),
+		WithFactory(component.BuilderType, factory),
+	), "test")
+
+	var labels []map[string]string
+	artifact := &componentmocks.Artifact{}
+	artifact.On("Labels").Return(map[string]string{})
+	mock.On("BuildFunc").Return(func(ls *component.LabelSet) component.Artifact {
+		labels = append(labels, ls.Labels)
+		return artifact
+	})
+
+	variants, err := app.Variants(context.Background())
+	require.NoError(err)
+	require.Len(variants, 2)
+	require.Equal(app, variants[0])
+
+	for i, arch := range []string{"amd64", "arm64"} {
+		require.Equal(map[string]string{"arch": arch}, variants[i].Matrix())
+
+		build, _, err := variants[i].Build(context.Background(), BuildWithPush(false))
+		require.NoError(err)
+		require.Equal(arch, build.Labels["waypoint/matrix/arch"])
+		require.Equal(arch, labels[i]["waypoint/matrix/arch"])
+	}
+}
+
+const testBuildConfigMatrix = `
+project = "test"
+
+app "test" {
+	build {
+		matrix = { arch = ["amd64", "arm64"] }
+
+		use "test" {}
+	}
+
+	deploy {
+		use "test" {}
+	}
+}
+`
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestConfigure_dynamicBlocks(t *testing.T) {
+	require := require.New(t)
+
+	f, diags := hclsyntax.ParseConfig([]byte(`
+image = "nginx"
+
+dynamic "port" {
+  for_each = var.ports
+  content {
+    name = port.key
+    port = port.value
+  }
+}
+`), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	require.False(diags.HasErrors(), diags.Error())
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(map[string]cty.Value{
+				"ports": cty.MapVal(map[string]cty.Value{
+					"http":  cty.NumberIntVal(80),
+					"https": cty.NumberIntVal(443),
+				}),
+			}),
+		},
+	}
+
+	var c testConfigurable
+	diags = configure(&c, f.Body, ctx)
+	require.False(diags.HasErrors(), diags.Error())
+	require.Equal("nginx", c.config.Image)
+	require.Equal([]*testPortConfig{
+		{Name: "http", Port: 80},
+		{Name: "https", Port: 443},
+	}, c.config.Ports)
+}
+
+func TestBodySource_sensitive(t *testing.T) {
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "waypoint")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+
+	src := `image = "nginx"
+static_environment = {
+  DB_PASSWORD = sensitive("hunter2")
+  API_KEY     = sensitive(upper(sensitive("abcd1234")))
+}`
+	path := filepath.Join(td, "waypoint.hcl")
+	require.NoError(ioutil.WriteFile(path, []byte(src), 0644))
+
+	f, diags := hclsyntax.ParseConfig([]byte(src), path, hcl.Pos{Line: 1, Column: 1})
+	require.False(diags.HasErrors(), diags.Error())
+	require.Equal(`image = "nginx"
+static_environment = {
+  DB_PASSWORD = sensitive("(sensitive)")
+  API_KEY     = sensitive("(sensitive)")
+}`, bodySource(f.Body))
+}
+
+// testConfigurable is a component with repeated blocks in its configuration.
+type testConfigurable struct {
+	config struct {
+		Image string            `hcl:"image"`
+		Ports []*testPortConfig `hcl:"port,block"`
+	}
+}
+
+type testPortConfig struct {
+	Name string `hcl:"name"`
+	Port int    `hcl:"port"`
+}
+
+func (c *testConfigurable) Config() (interface{}, error) {
+	return &c.config, nil
+}
@@ -77,6 +77,9 @@ func (op *deployOperation) Init(app *App) (proto.Message, error) {
 		State:       pb.Operation_CREATED,
 		HasEntrypointConfig: op.DeploymentConfig != nil &&
 			op.DeploymentConfig.ServerAddr != "",
+		Snapshot: &pb.Deployment_Snapshot{
+			PluginConfig: app.components[app.Platform].Config,
+		},
 	}, nil
 }
 
@@ -101,9 +104,11 @@ func (op *deployOperation) Upsert(
 	client pb.WaypointClient,
 	msg proto.Message,
 ) (proto.Message, error) {
+	deployment := msg.(*pb.Deployment)
 	resp, err := client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
-		Deployment:   msg.(*pb.Deployment),
-		AutoHostname: op.autoHostname,
+		Deployment:     deployment,
+		AutoHostname:   op.autoHostname,
+		IdempotencyKey: jobIdempotencyKey(deployment.Id, deployment.JobId),
 	})
 	if err != nil {
 		return nil, err
@@ -140,6 +145,12 @@ func (op *deployOperation) Do(ctx context.Context, log hclog.Logger, app *App, _
 	dconfig.Id = op.id
 	dconfig.EntrypointInviteToken = op.cebToken
 
+	if err := app.configureOutputs(ctx, app.Platform, &operationOutputs{
+		Artifact: op.Push,
+	}); err != nil {
+		return nil, err
+	}
+
 	return app.callDynamicFunc(ctx,
 		log,
 		(*component.Deployment)(nil),
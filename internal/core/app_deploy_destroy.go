@@ -121,7 +121,11 @@ func (op *deployDestroyOperation) Init(app *App) (proto.Message, error) {
 }
 
 func (op *deployDestroyOperation) Hooks(app *App) map[string][]*config.Hook {
-	return nil
+	platform, ok := app.components[app.Platform]
+	if !ok {
+		return nil
+	}
+	return platform.DestroyHooks
 }
 
 func (op *deployDestroyOperation) Labels(app *App) map[string]string {
@@ -157,6 +161,12 @@ func (op *deployDestroyOperation) Do(ctx context.Context, log hclog.Logger, app
 		return nil, nil // Fail silently for now, this will be fixed in v0.2
 	}
 
+	if err := app.configureOutputs(ctx, app.Platform, &operationOutputs{
+		Deploy: op.Deployment,
+	}); err != nil {
+		return nil, err
+	}
+
 	return app.callDynamicFunc(ctx,
 		log,
 		nil,
@@ -41,3 +41,13 @@ func (a *App) Destroy(ctx context.Context) error {
 
 	return nil
 }
+
+// DestroyReleases will destroy all the releases for this app in the current
+// configured workspace, leaving the deployments.
+func (a *App) DestroyReleases(ctx context.Context) error {
+	if err := a.destroyAllReleases(ctx); err != nil {
+		return err
+	}
+
+	return a.destroyReleaseWorkspace(ctx)
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// operationOutputs are the outputs of earlier operations that the
+// configuration of a component can refer to, such as artifact.image or
+// deploy.id. The configuration can also refer to the output captured by
+// hooks of these operations, or of earlier hooks in the job, as
+// hook.<name>.
+type operationOutputs struct {
+	// Build is the artifact before it is pushed. This is only set for
+	// pushes, where it is the artifact.
+	Build *pb.Build
+
+	// Artifact is the pushed artifact. If this isn't set and Deploy is,
+	// the artifact of the deployment is used.
+	Artifact *pb.PushedArtifact
+
+	// Deploy is the deployment.
+	Deploy *pb.Deployment
+}
+
+// usesOutputs returns true if the body refers to the outputs of earlier
+// operations. The outputs aren't known until the operation runs, so the
+// component is configured then rather than when the app is created.
+func usesOutputs(body hcl.Body) bool {
+	// Bodies merged over imports are checked by the bodies they are
+	// merged from.
+	if bodies := config.MergedBodies(body); bodies != nil {
+		for _, b := range bodies {
+			if usesOutputs(b) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	b, ok := body.(*hclsyntax.Body)
+	if !ok {
+		// Without a schema, the JSON syntax has an attribute for each
+		// property, and the variables of an attribute include those of the
+		// objects nested in it, which are its blocks.
+		attrs, diags := body.JustAttributes()
+		if diags.HasErrors() {
+			return false
+		}
+
+		for _, attr := range attrs {
+			if exprUsesOutputs(attr.Expr) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, attr := range b.Attributes {
+		if exprUsesOutputs(attr.Expr) {
+			return true
+		}
+	}
+
+	for _, block := range b.Blocks {
+		if usesOutputs(block.Body) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exprUsesOutputs returns true if the expression refers to the outputs of
+// earlier operations.
+func exprUsesOutputs(expr hcl.Expression) bool {
+	for _, t := range expr.Variables() {
+		switch t.RootName() {
+		case "artifact", "deploy", "hook":
+			return true
+		}
+	}
+
+	return false
+}
+
+// configureOutputs configures the component if its configuration refers
+// to the outputs of earlier operations. This does nothing for other
+// components since they are configured when the app is created.
+func (a *App) configureOutputs(ctx context.Context, c interface{}, outputs *operationOutputs) error {
+	body, ok := a.outputConfigs[c]
+	if !ok {
+		return nil
+	}
+
+	vars := map[string]cty.Value{}
+	hooks := map[string]cty.Value{}
+	addHooks := func(labels map[string]string) {
+		for k, v := range labels {
+			if strings.HasPrefix(k, config.HookLabelPrefix) {
+				hooks[strings.TrimPrefix(k, config.HookLabelPrefix)] = cty.StringVal(v)
+			}
+		}
+	}
+
+	if outputs.Build != nil {
+		addHooks(outputs.Build.Labels)
+		v, err := outputValue(outputs.Build.Id, outputs.Build.Sequence,
+			outputs.Build.Labels, outputs.Build.Artifact.GetArtifact())
+		if err != nil {
+			return err
+		}
+
+		vars["artifact"] = v
+	}
+
+	artifact := outputs.Artifact
+	if artifact == nil && outputs.Deploy != nil && outputs.Deploy.ArtifactId != "" {
+		var err error
+		artifact, err = a.client.GetPushedArtifact(ctx, &pb.GetPushedArtifactRequest{
+			Ref: &pb.Ref_Operation{
+				Target: &pb.Ref_Operation_Id{Id: outputs.Deploy.ArtifactId},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if artifact != nil {
+		addHooks(artifact.Build.GetLabels())
+		addHooks(artifact.Labels)
+		v, err := outputValue(artifact.Id, artifact.Sequence,
+			artifact.Labels, artifact.Artifact.GetArtifact())
+		if err != nil {
+			return err
+		}
+
+		vars["artifact"] = v
+	}
+
+	if outputs.Deploy != nil {
+		addHooks(outputs.Deploy.Labels)
+		v, err := outputValue(outputs.Deploy.Id, outputs.Deploy.Sequence,
+			outputs.Deploy.Labels, outputs.Deploy.Deployment)
+		if err != nil {
+			return err
+		}
+
+		vars["deploy"] = v
+	}
+
+	// Output captured in this job takes precedence since it is the latest
+	for k, v := range a.hookOutputs {
+		hooks[k] = cty.StringVal(v)
+	}
+	vars["hook"] = cty.ObjectVal(hooks)
+
+	evalContext := a.evalContext.NewChild()
+	evalContext.Variables = vars
+	if diag := configure(c, body, evalContext); diag.HasErrors() {
+		return diag
+	}
+
+	return nil
+}
+
+// outputValue returns the value of an operation output. This has the id,
+// sequence and labels of the operation along with the fields of the value
+// the plugin returned, such as the image of a Docker artifact.
+func outputValue(id string, sequence uint64, labels map[string]string, value *any.Any) (cty.Value, error) {
+	attrs := map[string]cty.Value{}
+
+	// The fields of the value are only known if its type is registered,
+	// which is the case for the builtin plugins. Other plugins only have
+	// the common fields.
+	var msg ptypes.DynamicAny
+	if value != nil && ptypes.UnmarshalAny(value, &msg) == nil {
+		data, err := protojson.MarshalOptions{
+			UseProtoNames:   true,
+			EmitUnpopulated: true,
+		}.Marshal(proto.MessageV2(msg.Message))
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		ty, err := ctyjson.ImpliedType(data)
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		v, err := ctyjson.Unmarshal(data, ty)
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		if v.Type().IsObjectType() {
+			for k, attr := range v.AsValueMap() {
+				attrs[k] = attr
+			}
+		}
+	}
+
+	labelVals := cty.MapValEmpty(cty.String)
+	if len(labels) > 0 {
+		m := map[string]cty.Value{}
+		for k, v := range labels {
+			m[k] = cty.StringVal(v)
+		}
+		labelVals = cty.MapVal(m)
+	}
+
+	attrs["id"] = cty.StringVal(id)
+	attrs["sequence"] = cty.NumberUIntVal(sequence)
+	attrs["labels"] = labelVals
+	return cty.ObjectVal(attrs), nil
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestUsesOutputs(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Body     string
+		Expected bool
+	}{
+		{
+			"no references",
+			`image = "nginx"`,
+			false,
+		},
+
+		{
+			"variable reference",
+			`image = var.image`,
+			false,
+		},
+
+		{
+			"artifact reference",
+			`image = artifact.image`,
+			true,
+		},
+
+		{
+			"deploy reference in a template",
+			`url = "https://${deploy.id}.example.com"`,
+			true,
+		},
+
+		{
+			"hook reference",
+			`tag = hook.version`,
+			true,
+		},
+
+		{
+			"nested block",
+			"auth {\n  tag = artifact.tag\n}",
+			true,
+		},
+
+		{
+			"dynamic block",
+			"dynamic \"port\" {\n  for_each = var.ports\n  content {\n    port = port.value\n  }\n}",
+			false,
+		},
+
+		{
+			"dynamic block over an output",
+			"dynamic \"port\" {\n  for_each = deploy.ports\n  content {\n    port = port.value\n  }\n}",
+			true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			f, diags := hclsyntax.ParseConfig([]byte(tt.Body), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+			require.False(diags.HasErrors(), diags.Error())
+			require.Equal(tt.Expected, usesOutputs(f.Body))
+		})
+	}
+}
+
+func TestUsesOutputs_json(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Body     string
+		Expected bool
+	}{
+		{
+			"no references",
+			`{"image": "nginx"}`,
+			false,
+		},
+
+		{
+			"variable reference",
+			`{"image": "${var.image}"}`,
+			false,
+		},
+
+		{
+			"artifact reference",
+			`{"image": "${artifact.image}"}`,
+			true,
+		},
+
+		{
+			"nested block",
+			`{"auth": {"tag": "${artifact.tag}"}}`,
+			true,
+		},
+
+		{
+			"list of blocks",
+			`{"port": [{"port": 80}, {"port": "${deploy.port}"}]}`,
+			true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			f, diags := json.Parse([]byte(tt.Body), "test.hcl.json")
+			require.False(diags.HasErrors(), diags.Error())
+			require.Equal(tt.Expected, usesOutputs(f.Body))
+		})
+	}
+}
+
+func TestOutputValue(t *testing.T) {
+	require := require.New(t)
+
+	value, err := ptypes.MarshalAny(&pb.Ref_Application{
+		Application: "web",
+		Project:     "test",
+	})
+	require.NoError(err)
+
+	v, err := outputValue("A1", 3, map[string]string{"env": "prod"}, value)
+	require.NoError(err)
+	require.True(v.GetAttr("id").RawEquals(cty.StringVal("A1")))
+	require.True(v.GetAttr("sequence").RawEquals(cty.NumberUIntVal(3)))
+	require.True(v.GetAttr("labels").RawEquals(cty.MapVal(map[string]cty.Value{
+		"env": cty.StringVal("prod"),
+	})))
+	require.True(v.GetAttr("application").RawEquals(cty.StringVal("web")))
+
+	// Without a value only the common fields are set
+	v, err = outputValue("A2", 1, nil, nil)
+	require.NoError(err)
+	require.True(v.GetAttr("id").RawEquals(cty.StringVal("A2")))
+	require.False(v.Type().HasAttribute("application"))
+}
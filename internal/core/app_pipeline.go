@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"os/exec"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PipelineStep runs an exec step of a pipeline of this app. The command
+// runs in the directory of the app and its output is written to the UI.
+func (a *App) PipelineStep(ctx context.Context, pipeline, step string) error {
+	p, ok := a.config.Pipeline(pipeline)
+	if !ok {
+		return status.Errorf(codes.NotFound,
+			"app %q has no pipeline %q", a.config.Name, pipeline)
+	}
+
+	s, ok := p.Step(step)
+	if !ok {
+		return status.Errorf(codes.NotFound,
+			"pipeline %q has no step %q", pipeline, step)
+	}
+	if len(s.Exec) == 0 {
+		return status.Errorf(codes.FailedPrecondition,
+			"step %q of pipeline %q isn't an exec step", step, pipeline)
+	}
+
+	log := a.logger.Named("pipeline").With("pipeline", pipeline, "step", step)
+	log.Debug("executing step", "command", s.Exec)
+
+	stdout, stderr, err := a.UI.OutputWriters()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, s.Exec[0], s.Exec[1:]...)
+	cmd.Dir = a.source.Path
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		if exiterr, ok := err.(*exec.ExitError); ok {
+			log.Warn("step failed", "code", exiterr.ExitCode())
+			return status.Errorf(codes.Aborted,
+				"step %q exited with code %d", step, exiterr.ExitCode())
+		}
+
+		return err
+	}
+
+	return nil
+}
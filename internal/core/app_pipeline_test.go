@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	componentmocks "github.com/hashicorp/waypoint-plugin-sdk/component/mocks"
+	"github.com/hashicorp/waypoint/internal/config"
+)
+
+func TestAppPipelineStep(t *testing.T) {
+	factory := TestFactory(t, component.PlatformType)
+	TestFactoryRegister(t, factory, "test", &componentmocks.Platform{})
+
+	// Make our app
+	app := TestApp(t, TestProject(t,
+		WithConfig(config.TestConfig(t, testPipelineConfig)),
+		WithFactory(component.PlatformType, factory),
+	), "test")
+
+	t.Run("success", func(t *testing.T) {
+		require := require.New(t)
+		require.NoError(app.PipelineStep(context.Background(), "ship", "ok"))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		require := require.New(t)
+
+		err := app.PipelineStep(context.Background(), "ship", "fail")
+		require.Error(err)
+		require.Equal(codes.Aborted, status.Code(err))
+	})
+
+	t.Run("not an exec step", func(t *testing.T) {
+		require := require.New(t)
+
+		err := app.PipelineStep(context.Background(), "ship", "deploy")
+		require.Error(err)
+		require.Equal(codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("unknown pipeline", func(t *testing.T) {
+		require := require.New(t)
+
+		err := app.PipelineStep(context.Background(), "nope", "ok")
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+	})
+}
+
+const testPipelineConfig = `
+project = "test"
+
+app "test" {
+	build {
+		use "test" {}
+	}
+
+	deploy {
+		use "test" {}
+	}
+
+	pipeline "ship" {
+		step "ok" {
+			exec = ["true"]
+		}
+
+		step "fail" {
+			exec = ["false"]
+		}
+
+		step "deploy" {
+			operation = "deploy"
+		}
+	}
+}
+`
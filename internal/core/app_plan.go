@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-argmapper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	"github.com/hashicorp/waypoint/internal/plugin"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// CanPlanDeploy returns true if this app supports dry runs of deploys.
+func (a *App) CanPlanDeploy() bool {
+	_, ok := a.inprocess.(plugin.Planner)
+	return ok
+}
+
+// PlanDeploy returns the resources that deploying the given artifact would
+// create, without deploying it.
+func (a *App) PlanDeploy(ctx context.Context, push *pb.PushedArtifact) (string, error) {
+	planner, ok := a.inprocess.(plugin.Planner)
+	if !ok {
+		return "", status.Errorf(codes.FailedPrecondition,
+			"The configured deployment plugin doesn't support dry runs. Dry runs are "+
+				"supported by the kubernetes, aws-ecs and docker plugins.")
+	}
+
+	// Get the deployment config so the plan matches a real deploy
+	resp, err := a.client.RunnerGetDeploymentConfig(ctx, &pb.RunnerGetDeploymentConfigRequest{})
+	if err != nil {
+		return "", err
+	}
+	dconfig := &component.DeploymentConfig{
+		ServerAddr:          resp.ServerAddr,
+		ServerTls:           resp.ServerTls,
+		ServerTlsSkipVerify: resp.ServerTlsSkipVerify,
+	}
+
+	// The planner is in-process so it takes the artifact as its concrete
+	// type rather than the Any that plugins decode themselves.
+	var artifact ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(push.Artifact.Artifact, &artifact); err != nil {
+		return "", status.Errorf(codes.FailedPrecondition,
+			"unable to decode artifact %s: %s", push.Id, err)
+	}
+
+	if err := a.configureOutputs(ctx, planner, &operationOutputs{Artifact: push}); err != nil {
+		return "", err
+	}
+
+	raw, err := a.callDynamicFunc(ctx,
+		a.logger.Named("plan"),
+		nil,
+		planner,
+		planner.PlanFunc(),
+		argmapper.Typed(artifact.Message, dconfig),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	plan, ok := raw.(string)
+	if !ok {
+		return "", status.Errorf(codes.FailedPrecondition,
+			"plan expected to return a string, got %T", raw)
+	}
+
+	return plan, nil
+}
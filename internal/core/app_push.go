@@ -100,8 +100,10 @@ func (op *pushBuildOperation) Hooks(app *App) map[string][]*config.Hook {
 }
 
 func (op *pushBuildOperation) Labels(app *App) map[string]string {
+	// Without a registry, the artifact is the build, so it has the labels
+	// of the variant of the build matrix.
 	if app.Registry == nil {
-		return nil
+		return config.MatrixLabels(app.matrix)
 	}
 
 	return app.components[app.Registry].Labels
@@ -128,6 +130,12 @@ func (op *pushBuildOperation) Do(ctx context.Context, log hclog.Logger, app *App
 		return op.Build.Artifact.Artifact, nil
 	}
 
+	if err := app.configureOutputs(ctx, app.Registry, &operationOutputs{
+		Build: op.Build,
+	}); err != nil {
+		return nil, err
+	}
+
 	return app.callDynamicFunc(ctx,
 		log,
 		(*component.Artifact)(nil),
@@ -99,6 +99,12 @@ func (op *releaseOperation) Do(ctx context.Context, log hclog.Logger, app *App,
 		return nil, nil
 	}
 
+	if err := app.configureOutputs(ctx, app.Releaser, &operationOutputs{
+		Deploy: op.Target,
+	}); err != nil {
+		return nil, err
+	}
+
 	result, err := app.callDynamicFunc(ctx,
 		log,
 		(*component.Release)(nil),
@@ -113,7 +113,11 @@ func (op *releaseDestroyOperation) Init(app *App) (proto.Message, error) {
 }
 
 func (op *releaseDestroyOperation) Hooks(app *App) map[string][]*config.Hook {
-	return nil
+	if app.Releaser == nil {
+		return nil
+	}
+
+	return app.components[app.Releaser].DestroyHooks
 }
 
 func (op *releaseDestroyOperation) Labels(app *App) map[string]string {
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-argmapper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/pkg/resource"
+	"github.com/hashicorp/waypoint/internal/plugin"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// CanListResources returns true if this app's platform declares the
+// resources of its deployments.
+func (a *App) CanListResources() bool {
+	_, ok := a.inprocess.(plugin.DeclaredResources)
+	return ok
+}
+
+// Resources returns the resources on the platform that make up the
+// deployment, with their current state.
+func (a *App) Resources(ctx context.Context, d *pb.Deployment) ([]*resource.Resource, error) {
+	declarer, ok := a.inprocess.(plugin.DeclaredResources)
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"The configured deployment plugin doesn't declare its resources. Resources "+
+				"are listed by the kubernetes, aws-ecs and docker plugins.")
+	}
+	if d.State == pb.Operation_DESTROYED {
+		return nil, nil
+	}
+
+	// The declarer is in-process so it takes the deployment as its concrete
+	// type rather than the Any that plugins decode themselves.
+	var deployment ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(d.Deployment, &deployment); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"unable to decode deployment %s: %s", d.Id, err)
+	}
+
+	if err := a.configureOutputs(ctx, declarer, &operationOutputs{Deploy: d}); err != nil {
+		return nil, err
+	}
+
+	raw, err := a.callDynamicFunc(ctx,
+		a.logger.Named("resources"),
+		nil,
+		declarer,
+		declarer.ResourcesFunc(),
+		argmapper.Typed(deployment.Message),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := raw.([]*resource.Resource)
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"resources expected to return []*resource.Resource, got %T", raw)
+	}
+
+	return result, nil
+}
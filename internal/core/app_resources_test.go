@@ -0,0 +1,60 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	componentmocks "github.com/hashicorp/waypoint-plugin-sdk/component/mocks"
+	"github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestAppCanListResources(t *testing.T) {
+	t.Run("can", func(t *testing.T) {
+		require := require.New(t)
+
+		// The platform is named after a builtin that declares resources
+		factory := TestFactory(t, component.PlatformType)
+		TestFactoryRegister(t, factory, "kubernetes", &componentmocks.Platform{})
+
+		// Make our app
+		app := TestApp(t, TestProject(t,
+			WithConfig(config.TestConfig(t, testScaleConfig)),
+			WithFactory(component.PlatformType, factory),
+		), "test")
+
+		require.True(app.CanListResources())
+
+		// Destroyed deployments have no resources
+		resources, err := app.Resources(context.Background(), &pb.Deployment{
+			Id:    "A",
+			State: pb.Operation_DESTROYED,
+		})
+		require.NoError(err)
+		require.Empty(resources)
+	})
+
+	t.Run("cannot", func(t *testing.T) {
+		require := require.New(t)
+
+		factory := TestFactory(t, component.PlatformType)
+		TestFactoryRegister(t, factory, "test", &componentmocks.Platform{})
+
+		// Make our app
+		app := TestApp(t, TestProject(t,
+			WithConfig(config.TestConfig(t, testPlatformConfig)),
+			WithFactory(component.PlatformType, factory),
+		), "test")
+
+		require.False(app.CanListResources())
+
+		_, err := app.Resources(context.Background(), &pb.Deployment{Id: "A"})
+		require.Error(err)
+		require.Equal(codes.FailedPrecondition, status.Code(err))
+	})
+}
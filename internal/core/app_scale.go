@@ -0,0 +1,57 @@
+package core
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-argmapper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/plugin"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// CanScale returns true if this app supports scaling deployments.
+func (a *App) CanScale() bool {
+	_, ok := a.inprocess.(plugin.Scaler)
+	return ok
+}
+
+// Scale changes the number of replicas of a deployment in place.
+func (a *App) Scale(ctx context.Context, d *pb.Deployment, replicas int32) error {
+	scaler, ok := a.inprocess.(plugin.Scaler)
+	if !ok {
+		return status.Errorf(codes.FailedPrecondition,
+			"The configured deployment plugin doesn't support scaling. Scaling is "+
+				"supported by the kubernetes, nomad, aws-ecs and docker plugins.")
+	}
+	if replicas < 0 {
+		return status.Errorf(codes.InvalidArgument, "replicas must not be negative")
+	}
+	if d.State == pb.Operation_DESTROYED {
+		return status.Errorf(codes.FailedPrecondition,
+			"deployment %s is destroyed and can't be scaled", d.Id)
+	}
+
+	// The scaler is in-process so it takes the deployment as its concrete
+	// type rather than the Any that plugins decode themselves.
+	var deployment ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(d.Deployment, &deployment); err != nil {
+		return status.Errorf(codes.FailedPrecondition,
+			"unable to decode deployment %s: %s", d.Id, err)
+	}
+
+	if err := a.configureOutputs(ctx, scaler, &operationOutputs{Deploy: d}); err != nil {
+		return err
+	}
+
+	_, err := a.callDynamicFunc(ctx,
+		a.logger.Named("scale"),
+		nil,
+		scaler,
+		scaler.ScaleFunc(),
+		argmapper.Typed(deployment.Message, replicas),
+	)
+	return err
+}
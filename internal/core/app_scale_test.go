@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	componentmocks "github.com/hashicorp/waypoint-plugin-sdk/component/mocks"
+	"github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestAppCanScale(t *testing.T) {
+	t.Run("can", func(t *testing.T) {
+		require := require.New(t)
+
+		// The platform is named after a builtin that supports scaling
+		factory := TestFactory(t, component.PlatformType)
+		TestFactoryRegister(t, factory, "kubernetes", &componentmocks.Platform{})
+
+		// Make our app
+		app := TestApp(t, TestProject(t,
+			WithConfig(config.TestConfig(t, testScaleConfig)),
+			WithFactory(component.PlatformType, factory),
+		), "test")
+
+		require.True(app.CanScale())
+	})
+
+	t.Run("cannot", func(t *testing.T) {
+		require := require.New(t)
+
+		factory := TestFactory(t, component.PlatformType)
+		TestFactoryRegister(t, factory, "test", &componentmocks.Platform{})
+
+		// Make our app
+		app := TestApp(t, TestProject(t,
+			WithConfig(config.TestConfig(t, testPlatformConfig)),
+			WithFactory(component.PlatformType, factory),
+		), "test")
+
+		require.False(app.CanScale())
+
+		err := app.Scale(context.Background(), &pb.Deployment{Id: "A"}, 2)
+		require.Error(err)
+		require.Equal(codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+const testScaleConfig = `
+project = "test"
+
+app "test" {
+	build {
+		use "test" {}
+	}
+
+	deploy {
+		use "kubernetes" {}
+	}
+}
+`
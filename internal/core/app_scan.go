@@ -0,0 +1,165 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/scan"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// scanBuild scans the image referenced by the artifact for vulnerabilities
+// and stores the result on the build. An error is returned if the scan
+// could not run or if a finding is at or above the configured threshold.
+func (a *App) scanBuild(ctx context.Context, build *pb.Build, artifact *any.Any) (*pb.Build, error) {
+	cfg := a.config.Scan
+	log := a.logger.Named("scan")
+
+	scanner := cfg.Scanner
+	if scanner == "" {
+		scanner = scan.ScannerTrivy
+	}
+
+	image, err := artifactImage(artifact)
+	if err != nil {
+		return build, status.Errorf(codes.FailedPrecondition,
+			"unable to scan artifact: %s", err)
+	}
+
+	sg := a.UI.StepGroup()
+	defer sg.Wait()
+
+	s := sg.Add("Scanning image %s with %s...", image, scanner)
+	defer func() {
+		if s != nil {
+			s.Abort()
+		}
+	}()
+
+	findings, err := scan.Run(ctx, log, &scan.Options{
+		Scanner:       scanner,
+		Image:         image,
+		IgnoreUnfixed: cfg.IgnoreUnfixed,
+		Output:        s.TermOutput(),
+	})
+	if err != nil {
+		return build, status.Errorf(codes.Internal, "error scanning image: %s", err)
+	}
+
+	failOn := scan.ParseSeverity(cfg.FailOn)
+	build.Scan = &pb.ScanResult{
+		Scanner:      scanner,
+		Image:        image,
+		Findings:     findings,
+		FailOn:       failOn,
+		Passed:       scan.Passed(findings, failOn),
+		CompleteTime: ptypes.TimestampNow(),
+	}
+
+	// Store the findings on the build whether or not the scan passed so
+	// they can be inspected later.
+	resp, err := a.client.UpsertBuild(ctx, &pb.UpsertBuildRequest{Build: build})
+	if err != nil {
+		return build, err
+	}
+	build = resp.Build
+
+	s.Update("Scanned image %s with %s: %s", image, scanner, scanSummary(findings))
+	s.Done()
+	s = nil
+
+	// Show the findings that fail the build, or that are high severity
+	// if there is no threshold. Findings are sorted by severity.
+	threshold := failOn
+	if threshold == pb.ScanResult_UNKNOWN {
+		threshold = pb.ScanResult_HIGH
+	}
+	for _, f := range findings {
+		if f.Severity < threshold {
+			break
+		}
+
+		a.UI.Output("%s %s in %s %s (%s)", f.Severity, f.Id, f.Package, f.InstalledVersion, fixedVersion(f),
+			terminal.WithWarningStyle())
+	}
+
+	if !build.Scan.Passed {
+		return build, status.Errorf(codes.FailedPrecondition,
+			"vulnerability scan found findings with severity %s or higher",
+			strings.ToLower(failOn.String()))
+	}
+
+	return build, nil
+}
+
+// artifactImage returns the image reference of the artifact. The artifact
+// can be any message with an "image" field and optional "tag" field, such
+// as docker.Image, so this works with any plugin that produces an image.
+func artifactImage(v *any.Any) (string, error) {
+	if v == nil {
+		return "", fmt.Errorf("no artifact")
+	}
+
+	var dyn ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(v, &dyn); err != nil {
+		return "", err
+	}
+
+	msg := proto.MessageReflect(dyn.Message)
+	fields := msg.Descriptor().Fields()
+
+	image := stringField(msg, fields.ByName("image"))
+	if image == "" {
+		return "", fmt.Errorf("artifact type %s does not reference an image",
+			msg.Descriptor().FullName())
+	}
+
+	if tag := stringField(msg, fields.ByName("tag")); tag != "" {
+		image += ":" + tag
+	}
+
+	return image, nil
+}
+
+func stringField(msg protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+		return ""
+	}
+
+	return msg.Get(fd).String()
+}
+
+// scanSummary returns a short summary of the number of findings.
+func scanSummary(findings []*pb.ScanResult_Finding) string {
+	if len(findings) == 0 {
+		return "no vulnerabilities found"
+	}
+
+	counts := scan.Summary(findings)
+
+	var parts []string
+	for sev := pb.ScanResult_CRITICAL; sev >= pb.ScanResult_UNKNOWN; sev-- {
+		if n := counts[sev]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, strings.ToLower(sev.String())))
+		}
+	}
+
+	return fmt.Sprintf("%d vulnerabilities found (%s)", len(findings), strings.Join(parts, ", "))
+}
+
+func fixedVersion(f *pb.ScanResult_Finding) string {
+	if f.FixedVersion == "" {
+		return "no fix available"
+	}
+
+	return "fixed in " + f.FixedVersion
+}
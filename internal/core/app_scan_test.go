@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/builtin/docker"
+)
+
+func TestArtifactImage(t *testing.T) {
+	t.Run("image and tag", func(t *testing.T) {
+		require := require.New(t)
+
+		v, err := ptypes.MarshalAny(&docker.Image{Image: "example.com/app", Tag: "v1"})
+		require.NoError(err)
+
+		image, err := artifactImage(v)
+		require.NoError(err)
+		require.Equal("example.com/app:v1", image)
+	})
+
+	t.Run("image without tag", func(t *testing.T) {
+		require := require.New(t)
+
+		v, err := ptypes.MarshalAny(&docker.Image{Image: "example.com/app"})
+		require.NoError(err)
+
+		image, err := artifactImage(v)
+		require.NoError(err)
+		require.Equal("example.com/app", image)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		require := require.New(t)
+
+		v, err := ptypes.MarshalAny(&empty.Empty{})
+		require.NoError(err)
+
+		_, err = artifactImage(v)
+		require.Error(err)
+		require.Contains(err.Error(), "does not reference an image")
+	})
+}
@@ -1,8 +1,14 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/go-hclog"
 
@@ -11,25 +17,36 @@ import (
 
 // execHook executes the given hook. This will return any errors. This ignores
 // on_failure configurations so this must be processed external.
-func (a *App) execHook(ctx context.Context, log hclog.Logger, h *config.Hook) error {
+//
+// If the hook captures its output, the output is recorded in hookOutputs
+// and returned. The output of earlier hooks is in the environment of the
+// command as WAYPOINT_HOOK_<name>.
+func (a *App) execHook(ctx context.Context, log hclog.Logger, h *config.Hook) (string, error) {
 	log.Debug("executing hook", "command", h.Command)
 
 	// Get our writers
 	stdout, stderr, err := a.UI.OutputWriters()
 	if err != nil {
 		log.Warn("error getting UI stdout/stderr", "err", err)
-		return err
+		return "", err
 	}
 
 	// Build our command
 	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...)
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(), a.hookEnv()...)
+
+	// If we capture the output we still show it
+	var output bytes.Buffer
+	if h.Capture != "" {
+		cmd.Stdout = io.MultiWriter(stdout, &output)
+	}
 
 	// Start
 	if err := cmd.Start(); err != nil {
 		log.Warn("error starting command", "err", err)
-		return err
+		return "", err
 	}
 
 	// Wait
@@ -42,8 +59,32 @@ func (a *App) execHook(ctx context.Context, log hclog.Logger, h *config.Hook) er
 		}
 
 		L.Warn("error running command", "err", err)
-		return err
+		return "", err
+	}
+
+	if h.Capture == "" {
+		return "", nil
+	}
+
+	result := strings.TrimSpace(output.String())
+	if len(result) > config.HookMaxOutput {
+		return "", fmt.Errorf(
+			"the captured output %q is %d characters, which is more than the maximum of %d",
+			h.Capture, len(result), config.HookMaxOutput)
+	}
+
+	a.hookOutputs[h.Capture] = result
+	return result, nil
+}
+
+// hookEnv returns the environment variables with the captured output of
+// the hooks that ran so far.
+func (a *App) hookEnv() []string {
+	result := make([]string, 0, len(a.hookOutputs))
+	for k, v := range a.hookOutputs {
+		result = append(result, "WAYPOINT_HOOK_"+strings.ToUpper(k)+"="+v)
 	}
+	sort.Strings(result)
 
-	return nil
+	return result
 }
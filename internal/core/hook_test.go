@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+	componentmocks "github.com/hashicorp/waypoint-plugin-sdk/component/mocks"
+	"github.com/hashicorp/waypoint/internal/config"
+)
+
+func TestAppHooks_capture(t *testing.T) {
+	require := require.New(t)
+
+	// Make our factory for builders
+	mock := &componentmocks.Builder{}
+	factory := TestFactory(t, component.BuilderType)
+	TestFactoryRegister(t, factory, "test", mock)
+
+	// Make our app
+	app := TestApp(t, TestProject(t,
+		WithConfig(config.TestConfig(t, testHookCaptureConfig)),
+		WithFactory(component.BuilderType, factory),
+	), "test")
+
+	artifact := &componentmocks.Artifact{}
+	artifact.On("Labels").Return(map[string]string{})
+	mock.On("BuildFunc").Return(func() component.Artifact {
+		return artifact
+	})
+
+	build, _, err := app.Build(context.Background())
+	require.NoError(err)
+	require.Equal("1.2.3", build.Labels["waypoint/hook/version"])
+
+	// The later hook sees the output of the earlier one
+	require.Equal("v1.2.3", build.Labels["waypoint/hook/tag"])
+}
+
+func TestAppHooks_failure(t *testing.T) {
+	require := require.New(t)
+
+	td, err := ioutil.TempDir("", "waypoint")
+	require.NoError(err)
+	defer os.RemoveAll(td)
+	path := filepath.Join(td, "failed")
+
+	// Make our factory for builders
+	mock := &componentmocks.Builder{}
+	factory := TestFactory(t, component.BuilderType)
+	TestFactoryRegister(t, factory, "test", mock)
+
+	// Make our app
+	app := TestApp(t, TestProject(t,
+		WithConfig(config.TestConfig(t, fmt.Sprintf(testHookFailureConfig, path))),
+		WithFactory(component.BuilderType, factory),
+	), "test")
+
+	mock.On("BuildFunc").Return(func() (component.Artifact, error) {
+		return nil, errors.New("build failed")
+	})
+
+	_, _, err = app.Build(context.Background())
+	require.Error(err)
+	require.Contains(err.Error(), "build failed")
+
+	// The failure hook ran
+	_, err = os.Stat(path)
+	require.NoError(err)
+}
+
+const testHookCaptureConfig = `
+project = "test"
+
+app "test" {
+	build {
+		use "test" {}
+
+		hook {
+			when    = "before"
+			command = ["echo", "1.2.3"]
+			capture = "version"
+		}
+
+		hook {
+			when    = "after"
+			command = ["sh", "-c", "echo v$WAYPOINT_HOOK_VERSION"]
+			capture = "tag"
+		}
+	}
+
+	deploy {
+		use "test" {}
+	}
+}
+`
+
+const testHookFailureConfig = `
+project = "test"
+
+app "test" {
+	build {
+		use "test" {}
+
+		hook {
+			when    = "failure"
+			command = ["touch", %q]
+		}
+	}
+
+	deploy {
+		use "test" {}
+	}
+}
+`
@@ -105,15 +105,32 @@ func (a *App) doOperation(
 
 	var doErr error
 
+	// The output captured by the hooks of this operation. This is recorded
+	// as labels so that operations in later jobs can use it.
+	captured := map[string]string{}
+	runHook := func(when string, i int, h *config.Hook) error {
+		output, err := a.execHook(ctx, log.Named(fmt.Sprintf("hook-%s-%d", when, i)), h)
+		if err != nil {
+			return err
+		}
+		if h.Capture != "" {
+			captured[h.Capture] = output
+		}
+
+		return nil
+	}
+
 	// If we have before hooks, run those
 	for i, h := range hooks["before"] {
-		if err := a.execHook(ctx, log.Named(fmt.Sprintf("hook-before-%d", i)), h); err != nil {
+		if err := runHook("before", i, h); err != nil {
 			doErr = fmt.Errorf("Error running before hook index %d: %w", i, err)
 			log.Warn("error running before hook", "err", err)
 
 			if h.ContinueOnFailure() {
 				log.Info("hook configured to continueon failure, ignoring error")
 				doErr = nil
+			} else {
+				break
 			}
 		}
 	}
@@ -144,18 +161,32 @@ func (a *App) doOperation(
 	// Run after hooks
 	if doErr == nil {
 		for i, h := range hooks["after"] {
-			if err := a.execHook(ctx, log.Named(fmt.Sprintf("hook-after-%d", i)), h); err != nil {
+			if err := runHook("after", i, h); err != nil {
 				doErr = fmt.Errorf("Error running after hook index %d: %w", i, err)
 				log.Warn("error running after hook", "err", err)
 
 				if h.ContinueOnFailure() {
 					log.Info("hook configured to continueon failure, ignoring error")
 					doErr = nil
+				} else {
+					break
 				}
 			}
 		}
 	}
 
+	// Run failure hooks. These can't change the result of the operation,
+	// so their errors are only logged.
+	if doErr != nil {
+		for i, h := range hooks["failure"] {
+			if err := runHook("failure", i, h); err != nil {
+				log.Warn("error running failure hook", "err", err)
+			}
+		}
+	}
+
+	msgAddLabels(msg, captured)
+
 	// If we have an error, then we set the error status
 	if doErr != nil {
 		log.Warn("error during local operation", "err", doErr)
@@ -210,6 +241,27 @@ func msgUpdateLabels(
 	val.Set(reflect.ValueOf(app.mergeLabels(base, resultLabels)))
 }
 
+// msgAddLabels adds the captured hook output to the labels of the message,
+// if it has labels.
+func msgAddLabels(msg proto.Message, captured map[string]string) {
+	if len(captured) == 0 {
+		return
+	}
+
+	val := msgField(msg, "Labels")
+	if !val.IsValid() {
+		return
+	}
+
+	labels, _ := val.Interface().(map[string]string)
+	labels = labelsMerge(labels, nil)
+	for k, v := range captured {
+		labels[config.HookLabelPrefix+k] = v
+	}
+
+	val.Set(reflect.ValueOf(labels))
+}
+
 // msgId gets the id of the message by looking for the "Id" field. This
 // will return empty string if the ID field can't be found for any reason.
 func msgId(msg proto.Message) string {
@@ -232,3 +284,16 @@ func msgField(msg proto.Message, f string) reflect.Value {
 	// Get the Id field
 	return val.FieldByName(f)
 }
+
+// jobIdempotencyKey returns the idempotency key used to create the record
+// of an operation for a job. If the job runs again, such as when its runner
+// restarted while it was running, the record the job already created is
+// used instead of creating another one. This is empty if the record exists
+// already or the operation doesn't run in a job.
+func jobIdempotencyKey(id, jobId string) string {
+	if id != "" || jobId == "" {
+		return ""
+	}
+
+	return "job/" + jobId
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"sync"
 
+	"github.com/go-git/go-git/v5"
 	"github.com/hashicorp/go-argmapper"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
@@ -46,9 +47,17 @@ type Project struct {
 	// workspace is the workspace that this project will work in.
 	workspace string
 
+	// gitCommit is the hash of the HEAD commit of the git repository
+	// that the project is in, if any.
+	gitCommit string
+
 	// jobInfo is the base job info for executed functions.
 	jobInfo *component.JobInfo
 
+	// evalContext is the context the configuration of the apps is
+	// evaluated with.
+	evalContext *hcl.EvalContext
+
 	// This lock only needs to be held currently to protect localClosers.
 	lock sync.Mutex
 
@@ -122,13 +131,15 @@ func NewProject(ctx context.Context, os ...Option) (*Project, error) {
 
 	// Set our labels
 	p.labels = opts.Config.Labels
+	p.gitCommit = gitCommit(p.root)
 
 	// Set our final job info
 	p.jobInfo.Workspace = p.workspace
 
 	// Initialize all the applications and load all their components.
+	p.evalContext = opts.ConfigContext
 	for _, appConfig := range opts.Config.Apps {
-		app, err := newApp(ctx, p, appConfig, opts.ConfigContext)
+		app, err := newApp(ctx, p, appConfig, opts.ConfigContext, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -196,6 +207,9 @@ func (p *Project) mergeLabels(ls ...map[string]string) map[string]string {
 
 	// Set our builtin labels
 	result["waypoint/workspace"] = p.workspace
+	if p.gitCommit != "" {
+		result["waypoint/git-commit"] = p.gitCommit
+	}
 
 	// Merge order
 	mergeOrder := []map[string]string{result, p.labels}
@@ -206,6 +220,24 @@ func (p *Project) mergeLabels(ls ...map[string]string) map[string]string {
 	return labelsMerge(mergeOrder...)
 }
 
+// gitCommit returns the hash of the HEAD commit of the git repository that
+// dir is in, or an empty string if it isn't in a git repository.
+func gitCommit(dir string) string {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{
+		DetectDotGit: true,
+	})
+	if err != nil {
+		return ""
+	}
+
+	ref, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+
+	return ref.Hash().String()
+}
+
 // options is the configuration to construct a new Project. Some
 // configuration is set directly on the Project. This is only used for
 // intermediate values that need to be processed further before initializing
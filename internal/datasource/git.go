@@ -37,6 +37,7 @@ func (s *GitSource) ProjectSource(body hcl.Body, ctx *hcl.EvalContext) (*pb.Job_
 			Git: &pb.Job_Git{
 				Url:  cfg.Url,
 				Path: cfg.Path,
+				Ref:  cfg.Ref,
 			},
 		},
 	}, nil
@@ -137,6 +138,7 @@ func (s *GitSource) Get(
 type gitConfig struct {
 	Url  string `hcl:"url,attr"`
 	Path string `hcl:"path,optional"`
+	Ref  string `hcl:"ref,optional"`
 }
 
 var _ Sourcer = (*GitSource)(nil)
@@ -46,6 +46,14 @@ type InjectFile struct {
 }
 
 func AlterEntrypoint(ctx context.Context, image string, f func(cur []string) (*NewEntrypoint, error)) (string, error) {
+	return alterEntrypoint(ctx, image, func(cur []string, platform string) (*NewEntrypoint, error) {
+		return f(cur)
+	})
+}
+
+// alterEntrypoint is AlterEntrypoint but f is also given the platform of
+// the image, such as "linux/amd64".
+func alterEntrypoint(ctx context.Context, image string, f func(cur []string, platform string) (*NewEntrypoint, error)) (string, error) {
 	dc, err := dockerClient(ctx)
 	if err != nil {
 		return "", err
@@ -62,9 +70,10 @@ func AlterEntrypoint(ctx context.Context, image string, f func(cur []string) (*N
 
 	icfg := info.Config
 
-	L.Debug("extracted existing entrypoint", "image", image, "entrypoint", icfg.Entrypoint)
+	platform := info.Os + "/" + info.Architecture
+	L.Debug("extracted existing entrypoint", "image", image, "entrypoint", icfg.Entrypoint, "platform", platform)
 
-	newEp, err := f(icfg.Entrypoint)
+	newEp, err := f(icfg.Entrypoint, platform)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,133 @@
+package epinject
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/waypoint/internal/assets"
+)
+
+// Path is where the entrypoint binary is added in images.
+const Path = "/waypoint-entrypoint"
+
+// Mode is how a builder adds the entrypoint to the images it builds.
+type Mode string
+
+const (
+	// ModeInject adds the entrypoint binary to the image and makes it the
+	// entrypoint of the image, which runs the original entrypoint as its
+	// child. This is the default.
+	ModeInject Mode = "inject"
+
+	// ModeBinary only adds the entrypoint binary to the image. The image
+	// must start it itself, such as from an entrypoint script or the
+	// command of the deployment, as "/waypoint-entrypoint <command>".
+	ModeBinary Mode = "binary"
+
+	// ModeDisable doesn't add the entrypoint to the image.
+	ModeDisable Mode = "disable"
+)
+
+// platformAssets are the names of the entrypoint binaries for the platforms
+// of images that the entrypoint can run on. The entrypoint is statically
+// linked so it runs on every Linux distribution.
+var platformAssets = map[string]string{
+	"linux/amd64": "ceb/ceb",
+	"linux/arm64": "ceb/ceb-linux-arm64",
+}
+
+// ParseMode returns the mode for the configuration of a builder. disable is
+// the disable_entrypoint setting of the builder, which is the same as
+// ModeDisable, and mode defaults to ModeInject.
+func ParseMode(mode string, disable bool) (Mode, error) {
+	switch m := Mode(mode); m {
+	case "":
+		if disable {
+			return ModeDisable, nil
+		}
+
+		return ModeInject, nil
+
+	case ModeInject, ModeBinary, ModeDisable:
+		if disable && m != ModeDisable {
+			return "", fmt.Errorf("disable_entrypoint can't be set with entrypoint_mode %q", mode)
+		}
+
+		return m, nil
+
+	default:
+		return "", fmt.Errorf("unknown entrypoint_mode %q, must be one of: %s, %s, %s",
+			mode, ModeInject, ModeBinary, ModeDisable)
+	}
+}
+
+// Asset returns the entrypoint binary for images of the platform, such as
+// "linux/arm64". The entrypoint doesn't run on other operating systems,
+// such as Windows, so images for them must use ModeDisable.
+func Asset(platform string) ([]byte, os.FileInfo, error) {
+	name, ok := platformAssets[platform]
+	if !ok {
+		return nil, nil, fmt.Errorf(
+			"the entrypoint isn't available for %s images, it is available for: %s. "+
+				"Set entrypoint_mode to %q to build without it",
+			platform, strings.Join(Platforms(), ", "), ModeDisable)
+	}
+
+	data, err := assets.Asset(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to restore custom entry point binary: %s", err)
+	}
+
+	info, err := assets.AssetInfo(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to restore custom entry point binary: %s", err)
+	}
+
+	return data, info, nil
+}
+
+// Platforms returns the platforms that the entrypoint is available for,
+// sorted.
+func Platforms() []string {
+	result := make([]string, 0, len(platformAssets))
+	for k := range platformAssets {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// Inject adds the entrypoint to the Docker image with the mode. The binary
+// is chosen for the platform of the image. This returns the ID of the new
+// image, or an empty string if the mode is ModeDisable.
+func Inject(ctx context.Context, image string, mode Mode) (string, error) {
+	if mode == ModeDisable {
+		return "", nil
+	}
+
+	return alterEntrypoint(ctx, image, func(cur []string, platform string) (*NewEntrypoint, error) {
+		data, info, err := Asset(platform)
+		if err != nil {
+			return nil, err
+		}
+
+		ep := &NewEntrypoint{
+			InjectFiles: map[string]InjectFile{
+				Path: {
+					Reader: bytes.NewReader(data),
+					Info:   info,
+				},
+			},
+		}
+		if mode == ModeInject {
+			ep.Entrypoint = append([]string{Path}, cur...)
+		}
+
+		return ep, nil
+	})
+}
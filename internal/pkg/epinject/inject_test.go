@@ -0,0 +1,46 @@
+package epinject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMode(t *testing.T) {
+	cases := []struct {
+		Mode     string
+		Disable  bool
+		Expected Mode
+		Err      bool
+	}{
+		{"", false, ModeInject, false},
+		{"", true, ModeDisable, false},
+		{"binary", false, ModeBinary, false},
+		{"disable", true, ModeDisable, false},
+		{"inject", true, "", true},
+		{"sidecar", false, "", true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Mode, func(t *testing.T) {
+			require := require.New(t)
+
+			mode, err := ParseMode(tt.Mode, tt.Disable)
+			if tt.Err {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tt.Expected, mode)
+		})
+	}
+}
+
+func TestAsset_unsupportedPlatform(t *testing.T) {
+	require := require.New(t)
+
+	_, _, err := Asset("windows/amd64")
+	require.Error(err)
+	require.Contains(err.Error(), "windows/amd64")
+	require.Contains(err.Error(), "linux/amd64, linux/arm64")
+}
@@ -0,0 +1,270 @@
+// Package oidc implements the authorization code flow of OpenID Connect for
+// a confidential client: building the URL to send the user to, exchanging
+// the returned code for an ID token and verifying that token.
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// clockSkew is how far the clocks of the provider and us can differ when
+// validating the times in an ID token.
+const clockSkew = time.Minute
+
+// Config is the configuration for a Provider.
+type Config struct {
+	// Issuer is the URL of the provider. The discovery document is read
+	// from "/.well-known/openid-configuration" under it.
+	Issuer string
+
+	// ClientID and ClientSecret are the credentials of the client that
+	// was registered with the provider.
+	ClientID     string
+	ClientSecret string
+
+	// Scopes are requested in addition to "openid".
+	Scopes []string
+
+	// HTTPClient is used for all requests to the provider. This defaults
+	// to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Claims are the claims of a verified ID token.
+type Claims struct {
+	Issuer  string
+	Subject string
+
+	// These are only set if the provider returns them, which usually
+	// depends on the requested scopes.
+	Email             string
+	Name              string
+	PreferredUsername string
+}
+
+// Provider is an OpenID Connect provider.
+type Provider struct {
+	config   Config
+	oauth    oauth2.Config
+	jwksURL  string
+	issuer   string
+	client   *http.Client
+	keysLock sync.Mutex
+	keys     *jose.JSONWebKeySet
+}
+
+// discovery is the subset of the discovery document that we use.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// New reads the discovery document of the provider and returns a Provider.
+func New(ctx context.Context, cfg *Config) (*Provider, error) {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var doc discovery
+	url := strings.TrimSuffix(cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(ctx, client, url, &doc); err != nil {
+		return nil, fmt.Errorf("error reading OIDC discovery document: %s", err)
+	}
+
+	// The issuer must match so that tokens from another issuer served at
+	// the same location aren't accepted.
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(cfg.Issuer, "/") {
+		return nil, fmt.Errorf("OIDC issuer %q does not match the configured issuer %q",
+			doc.Issuer, cfg.Issuer)
+	}
+
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC discovery document is missing required endpoints")
+	}
+
+	return &Provider{
+		config: *cfg,
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  doc.AuthorizationEndpoint,
+				TokenURL: doc.TokenEndpoint,
+			},
+			Scopes: append([]string{"openid"}, cfg.Scopes...),
+		},
+		jwksURL: doc.JWKSURI,
+		issuer:  doc.Issuer,
+		client:  client,
+	}, nil
+}
+
+// AuthURL returns the URL to send the user to in order to log in. The
+// provider redirects back to redirectURI with the state and a code to
+// pass to Exchange. The nonce is embedded in the ID token.
+func (p *Provider) AuthURL(redirectURI, state, nonce string) string {
+	cfg := p.oauth
+	cfg.RedirectURL = redirectURI
+	return cfg.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce))
+}
+
+// Exchange exchanges the code for an ID token and returns its verified
+// claims. The redirectURI and nonce must be the same as given to AuthURL.
+func (p *Provider) Exchange(ctx context.Context, redirectURI, code, nonce string) (*Claims, error) {
+	cfg := p.oauth
+	cfg.RedirectURL = redirectURI
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	token, err := cfg.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging OIDC code: %s", err)
+	}
+
+	raw, ok := token.Extra("id_token").(string)
+	if !ok || raw == "" {
+		return nil, fmt.Errorf("OIDC provider did not return an ID token")
+	}
+
+	return p.Verify(ctx, raw, nonce)
+}
+
+// Verify verifies the signature and claims of the ID token and returns the
+// claims. If nonce isn't empty, the token must have the same nonce.
+func (p *Provider) Verify(ctx context.Context, raw, nonce string) (*Claims, error) {
+	tok, err := jwt.ParseSigned(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %s", err)
+	}
+	if len(tok.Headers) != 1 {
+		return nil, fmt.Errorf("invalid ID token: expected one signature")
+	}
+
+	header := tok.Headers[0]
+	if header.Algorithm == "" || header.Algorithm == "none" || strings.HasPrefix(header.Algorithm, "HS") {
+		// Only asymmetric keys from the key set are accepted.
+		return nil, fmt.Errorf("invalid ID token: unsupported algorithm %q", header.Algorithm)
+	}
+
+	key, err := p.key(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var std jwt.Claims
+	var extra struct {
+		Nonce             string `json:"nonce"`
+		Email             string `json:"email"`
+		Name              string `json:"name"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := tok.Claims(key, &std, &extra); err != nil {
+		return nil, fmt.Errorf("invalid ID token: %s", err)
+	}
+
+	err = std.ValidateWithLeeway(jwt.Expected{
+		Issuer:   p.issuer,
+		Audience: jwt.Audience{p.config.ClientID},
+		Time:     time.Now(),
+	}, clockSkew)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %s", err)
+	}
+
+	if std.Expiry == nil {
+		return nil, fmt.Errorf("invalid ID token: no expiry")
+	}
+
+	if std.Subject == "" {
+		return nil, fmt.Errorf("invalid ID token: no subject")
+	}
+
+	if nonce != "" && subtle.ConstantTimeCompare([]byte(nonce), []byte(extra.Nonce)) != 1 {
+		return nil, fmt.Errorf("invalid ID token: nonce does not match")
+	}
+
+	return &Claims{
+		Issuer:            std.Issuer,
+		Subject:           std.Subject,
+		Email:             extra.Email,
+		Name:              extra.Name,
+		PreferredUsername: extra.PreferredUsername,
+	}, nil
+}
+
+// key returns the public key with the given ID. The key set is fetched
+// again if the key isn't known, since providers rotate their keys.
+func (p *Provider) key(ctx context.Context, id string) (*jose.JSONWebKey, error) {
+	p.keysLock.Lock()
+	defer p.keysLock.Unlock()
+
+	if p.keys != nil {
+		if key := findKey(p.keys, id); key != nil {
+			return key, nil
+		}
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := getJSON(ctx, p.client, p.jwksURL, &keys); err != nil {
+		return nil, fmt.Errorf("error reading OIDC key set: %s", err)
+	}
+	p.keys = &keys
+
+	if key := findKey(p.keys, id); key != nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("invalid ID token: unknown key %q", id)
+}
+
+// findKey returns the public signing key with the ID. If id is empty, the
+// key is only returned if the set has a single key.
+func findKey(keys *jose.JSONWebKeySet, id string) *jose.JSONWebKey {
+	var candidates []jose.JSONWebKey
+	if id != "" {
+		candidates = keys.Key(id)
+	} else if len(keys.Keys) == 1 {
+		candidates = keys.Keys
+	}
+
+	for _, key := range candidates {
+		if key.Use == "" || key.Use == "sig" {
+			key := key.Public()
+			return &key
+		}
+	}
+
+	return nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status from %s: %s", url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,158 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func TestProvider(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(err)
+
+	srv := testServer(key)
+	defer srv.Close()
+
+	p, err := New(ctx, &Config{
+		Issuer:       srv.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"email"},
+	})
+	require.NoError(err)
+
+	// The auth URL has everything the provider needs
+	u, err := url.Parse(p.AuthURL("http://localhost:8250/oidc/callback", "state", "nonce"))
+	require.NoError(err)
+	require.Equal("/authorize", u.Path)
+	require.Equal("client", u.Query().Get("client_id"))
+	require.Equal("state", u.Query().Get("state"))
+	require.Equal("nonce", u.Query().Get("nonce"))
+	require.Equal("openid email", u.Query().Get("scope"))
+
+	sign := func(claims interface{}) string {
+		signer, err := jose.NewSigner(jose.SigningKey{
+			Algorithm: jose.RS256,
+			Key:       jose.JSONWebKey{Key: key, KeyID: "k1"},
+		}, nil)
+		require.NoError(err)
+
+		raw, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+		require.NoError(err)
+		return raw
+	}
+
+	valid := map[string]interface{}{
+		"iss":   srv.URL,
+		"sub":   "alice",
+		"aud":   "client",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": "nonce",
+		"email": "alice@example.com",
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		claims, err := p.Verify(ctx, sign(valid), "nonce")
+		require.NoError(err)
+		require.Equal("alice", claims.Subject)
+		require.Equal("alice@example.com", claims.Email)
+	})
+
+	tests := map[string]func(map[string]interface{}){
+		"wrong nonce":    func(c map[string]interface{}) { c["nonce"] = "other" },
+		"wrong audience": func(c map[string]interface{}) { c["aud"] = "other" },
+		"wrong issuer":   func(c map[string]interface{}) { c["iss"] = "https://example.com" },
+		"expired":        func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no subject":     func(c map[string]interface{}) { delete(c, "sub") },
+		"no expiry":      func(c map[string]interface{}) { delete(c, "exp") },
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims := map[string]interface{}{}
+			for k, v := range valid {
+				claims[k] = v
+			}
+			f(claims)
+
+			_, err := p.Verify(ctx, sign(claims), "nonce")
+			require.Error(err)
+		})
+	}
+
+	t.Run("wrong key", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(err)
+
+		signer, err := jose.NewSigner(jose.SigningKey{
+			Algorithm: jose.RS256,
+			Key:       jose.JSONWebKey{Key: other, KeyID: "k1"},
+		}, nil)
+		require.NoError(err)
+		raw, err := jwt.Signed(signer).Claims(valid).CompactSerialize()
+		require.NoError(err)
+
+		_, err = p.Verify(ctx, raw, "nonce")
+		require.Error(err)
+	})
+
+	t.Run("exchange", func(t *testing.T) {
+		idToken = sign(valid)
+		claims, err := p.Exchange(ctx, "http://localhost:8250/oidc/callback", "code", "nonce")
+		require.NoError(err)
+		require.Equal("alice", claims.Subject)
+	})
+}
+
+// idToken is returned by the token endpoint of testServer.
+var idToken string
+
+func testServer(key *rsa.PrivateKey) *httptest.Server {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 srv.URL,
+			"authorization_endpoint": srv.URL + "/authorize",
+			"token_endpoint":         srv.URL + "/token",
+			"jwks_uri":               srv.URL + "/keys",
+		})
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{
+				{Key: &key.PublicKey, KeyID: "k1", Algorithm: "RS256", Use: "sig"},
+			},
+		})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "code" {
+			http.Error(w, "bad code", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     idToken,
+		})
+	})
+
+	return srv
+}
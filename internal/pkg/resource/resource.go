@@ -0,0 +1,17 @@
+// Package resource has the types that platforms use to declare the
+// resources that a deployment created on the platform.
+package resource
+
+// Resource is a resource on the platform that makes up a deployment.
+type Resource struct {
+	// Type is the kind of resource, such as "container" or "service".
+	Type string
+
+	// Name is the name of the resource on the platform. This is the ID or
+	// ARN for platforms that identify resources that way.
+	Name string
+
+	// State is the current state of the resource as the platform reports
+	// it, such as "running". This is empty if the state isn't known.
+	State string
+}
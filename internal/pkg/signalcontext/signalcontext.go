@@ -4,11 +4,13 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/hashicorp/go-hclog"
 )
 
-// WithInterrupt returns a Context that is done when an interrupt signal is received.
+// WithInterrupt returns a Context that is done when an interrupt or termination
+// signal is received.
 // It also returns a closer function that should be deferred for proper cleanup.
 func WithInterrupt(ctx context.Context, log hclog.Logger) (context.Context, func()) {
 	log.Trace("starting interrupt listener for context cancellation")
@@ -18,7 +20,7 @@ func WithInterrupt(ctx context.Context, log hclog.Logger) (context.Context, func
 
 	// Create the signal channel and cancel the context when we get a signal
 	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, os.Interrupt)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		log.Trace("interrupt listener goroutine started")
 
@@ -0,0 +1,244 @@
+// Package structlog parses structured log lines, such as JSON or logfmt,
+// into their level, message and fields so that they can be filtered by
+// level.
+package structlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Levels are the levels of lines, from the least to the most severe. The
+// levels of parsed lines are normalized to one of these.
+var Levels = []string{"trace", "debug", "info", "warn", "error", "fatal"}
+
+// levelAliases are other names of levels used by logging libraries.
+var levelAliases = map[string]string{
+	"trc":       "trace",
+	"dbg":       "debug",
+	"inf":       "info",
+	"notice":    "info",
+	"wrn":       "warn",
+	"warning":   "warn",
+	"err":       "error",
+	"eror":      "error",
+	"crit":      "fatal",
+	"critical":  "fatal",
+	"alert":     "fatal",
+	"emerg":     "fatal",
+	"emergency": "fatal",
+	"panic":     "fatal",
+	"dpanic":    "fatal",
+}
+
+// The keys of the level and message in structured lines, in the order they
+// are looked for. These cover the common logging libraries, such as
+// "@level" for hclog and "severity" for Stackdriver.
+var (
+	levelKeys   = []string{"level", "lvl", "@level", "severity", "log.level"}
+	messageKeys = []string{"msg", "message", "@message"}
+)
+
+// Entry is a parsed structured line.
+type Entry struct {
+	// Level is the normalized level, or empty if the line has no level or
+	// it isn't one we know. An unknown level is kept in Fields.
+	Level string
+
+	// Message is the message of the line, or empty if it has none.
+	Message string
+
+	// Fields are the other keys of the line. Values that aren't strings,
+	// such as numbers or objects in JSON, are encoded as JSON.
+	Fields map[string]string
+}
+
+// Parse parses a line of JSON or logfmt. This returns false if the line
+// isn't structured, in which case it should be kept as text. A logfmt line
+// must have a level or message key so that text that happens to contain
+// "=" isn't parsed.
+func Parse(line string) (*Entry, bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "{") && strings.HasSuffix(line, "}") {
+		return parseJSON(line)
+	}
+
+	return parseLogfmt(line)
+}
+
+func parseJSON(line string) (*Entry, bool) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return nil, false
+	}
+
+	fields := map[string]string{}
+	for k, v := range raw {
+		switch v := v.(type) {
+		case string:
+			fields[k] = v
+
+		case float64:
+			fields[k] = strconv.FormatFloat(v, 'f', -1, 64)
+
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+
+			fields[k] = string(encoded)
+		}
+	}
+
+	return newEntry(fields), true
+}
+
+func parseLogfmt(line string) (*Entry, bool) {
+	fields := map[string]string{}
+	for line != "" {
+		// Read the key, which ends at "=". logfmt allows keys without a
+		// value but we don't, since most text would then be logfmt.
+		end := strings.IndexAny(line, "= ")
+		if end <= 0 || line[end] != '=' {
+			return nil, false
+		}
+		key := line[:end]
+		line = line[end+1:]
+
+		// Read the value, which is quoted if it has spaces.
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			n := quotedLen(line)
+			if n == -1 {
+				return nil, false
+			}
+
+			v, err := strconv.Unquote(line[:n])
+			if err != nil {
+				return nil, false
+			}
+
+			value = v
+			line = line[n:]
+			if line != "" && line[0] != ' ' {
+				return nil, false
+			}
+		} else {
+			end := strings.IndexByte(line, ' ')
+			if end == -1 {
+				end = len(line)
+			}
+
+			value = line[:end]
+			line = line[end:]
+		}
+
+		fields[key] = value
+		line = strings.TrimLeft(line, " ")
+	}
+
+	if !hasAny(fields, levelKeys) && !hasAny(fields, messageKeys) {
+		return nil, false
+	}
+
+	return newEntry(fields), true
+}
+
+// quotedLen returns the length of the quoted string at the start of v,
+// including the quotes, or -1 if it isn't terminated.
+func quotedLen(v string) int {
+	for i := 1; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			i++
+
+		case '"':
+			return i + 1
+		}
+	}
+
+	return -1
+}
+
+// newEntry returns the entry with the level and message taken out of the
+// fields.
+func newEntry(fields map[string]string) *Entry {
+	result := &Entry{Fields: fields}
+	for _, k := range levelKeys {
+		v, ok := fields[k]
+		if !ok {
+			continue
+		}
+
+		if level, err := ParseLevel(v); err == nil {
+			result.Level = level
+			delete(fields, k)
+		}
+		break
+	}
+
+	for _, k := range messageKeys {
+		if v, ok := fields[k]; ok {
+			result.Message = v
+			delete(fields, k)
+			break
+		}
+	}
+
+	return result
+}
+
+// ParseLevel returns the normalized level, such as "warn" for "WARNING".
+// Numeric levels are supported for the JSON of pino and bunyan, where 30
+// is info and 50 is error.
+func ParseLevel(v string) (string, error) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if n, err := strconv.Atoi(v); err == nil {
+		idx := n/10 - 1
+		if n%10 != 0 || idx < 0 || idx >= len(Levels) {
+			return "", fmt.Errorf("unknown log level %q", v)
+		}
+
+		return Levels[idx], nil
+	}
+
+	if alias, ok := levelAliases[v]; ok {
+		return alias, nil
+	}
+	if levelIndex(v) == -1 {
+		return "", fmt.Errorf("unknown log level %q, must be one of: %s",
+			v, strings.Join(Levels, ", "))
+	}
+
+	return v, nil
+}
+
+// AtLeast returns true if level is at least as severe as min. Both must be
+// normalized levels. Lines without a level are never at least min.
+func AtLeast(level, min string) bool {
+	idx := levelIndex(level)
+	return idx != -1 && idx >= levelIndex(min)
+}
+
+func levelIndex(level string) int {
+	for i, v := range Levels {
+		if v == level {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func hasAny(fields map[string]string, keys []string) bool {
+	for _, k := range keys {
+		if _, ok := fields[k]; ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,147 @@
+package structlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Line     string
+		Expected *Entry
+	}{
+		{
+			"text",
+			"Listening on port 8080\n",
+			nil,
+		},
+
+		{
+			"text with equals",
+			"retrying with timeout=5s",
+			nil,
+		},
+
+		{
+			"json",
+			`{"level":"ERROR","msg":"connection failed","attempt":3,"tls":true}` + "\n",
+			&Entry{
+				Level:   "error",
+				Message: "connection failed",
+				Fields:  map[string]string{"attempt": "3", "tls": "true"},
+			},
+		},
+
+		{
+			"json hclog",
+			`{"@level":"warn","@message":"slow request","@module":"web"}`,
+			&Entry{
+				Level:   "warn",
+				Message: "slow request",
+				Fields:  map[string]string{"@module": "web"},
+			},
+		},
+
+		{
+			"json numeric level",
+			`{"level":50,"msg":"boom"}`,
+			&Entry{
+				Level:   "error",
+				Message: "boom",
+				Fields:  map[string]string{},
+			},
+		},
+
+		{
+			"json unknown level",
+			`{"level":"verbose","msg":"hi"}`,
+			&Entry{
+				Message: "hi",
+				Fields:  map[string]string{"level": "verbose"},
+			},
+		},
+
+		{
+			"invalid json",
+			`{"level": "error"`,
+			nil,
+		},
+
+		{
+			"logfmt",
+			`time=2020-10-20T12:00:00Z level=warning msg="disk almost full" used="95%"` + "\n",
+			&Entry{
+				Level:   "warn",
+				Message: "disk almost full",
+				Fields: map[string]string{
+					"time": "2020-10-20T12:00:00Z",
+					"used": "95%",
+				},
+			},
+		},
+
+		{
+			"logfmt escaped quote",
+			`lvl=info msg="said \"hi\""`,
+			&Entry{
+				Level:   "info",
+				Message: `said "hi"`,
+				Fields:  map[string]string{},
+			},
+		},
+
+		{
+			"logfmt unterminated quote",
+			`level=info msg="oops`,
+			nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			entry, ok := Parse(tt.Line)
+			if tt.Expected == nil {
+				require.False(ok)
+				return
+			}
+
+			require.True(ok)
+			require.Equal(tt.Expected, entry)
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	require := require.New(t)
+
+	for input, expected := range map[string]string{
+		"info":    "info",
+		"WARNING": "warn",
+		" Err ":   "error",
+		"crit":    "fatal",
+		"10":      "trace",
+		"60":      "fatal",
+	} {
+		level, err := ParseLevel(input)
+		require.NoError(err, input)
+		require.Equal(expected, level, input)
+	}
+
+	for _, input := range []string{"", "verbose", "35", "70"} {
+		_, err := ParseLevel(input)
+		require.Error(err, input)
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	require := require.New(t)
+
+	require.True(AtLeast("error", "warn"))
+	require.True(AtLeast("warn", "warn"))
+	require.False(AtLeast("info", "warn"))
+	require.False(AtLeast("", "trace"))
+}
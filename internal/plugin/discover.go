@@ -12,8 +12,10 @@ import (
 	"strings"
 
 	"github.com/adrg/xdg"
+	"github.com/hashicorp/go-version"
 
 	"github.com/hashicorp/waypoint/internal/config"
+	wpversion "github.com/hashicorp/waypoint/internal/version"
 )
 
 // Discover finds the given plugin and returns the command for it. The command
@@ -21,7 +23,12 @@ import (
 // plugin type. If the plugin is not found `(nil, nil)` is returned.
 //
 // The plugin binary must have the form "waypoint-plugin-<name>" (with a
-// ".exe" extension on Windows).
+// ".exe" extension on Windows). The binary may also be versioned as
+// "waypoint-plugin-<name>_v<version>" so that several versions can be
+// installed side by side. If the plugin has a version constraint from
+// required_plugins, the highest version that satisfies it is used and
+// it is an error if none does. Otherwise an unversioned binary is
+// preferred over the highest version.
 //
 // This will search the paths given. You can use DefaultPaths() to get
 // the default set of paths.
@@ -29,26 +36,29 @@ import (
 func Discover(cfg *config.Plugin, paths []string) (*exec.Cmd, error) {
 	// Expected filename
 	expected := "waypoint-plugin-" + cfg.Name
+	ext := ""
 	if runtime.GOOS == "windows " {
-		expected += ".exe"
+		ext = ".exe"
 	}
 
 	// Search our paths
 	for _, path := range paths {
-		path = filepath.Join(path, expected)
-
-		_, err := os.Stat(path)
+		candidates, err := discoverPath(path, expected, ext)
 		if err != nil {
-			if os.IsNotExist(err) {
-				continue
-			}
+			return nil, err
+		}
+		if len(candidates) == 0 {
+			continue
+		}
 
+		found, err := discoverSelect(cfg, candidates)
+		if err != nil {
 			return nil, err
 		}
 
 		// If the checksum is set we validate it.
 		if cfg.Checksum != "" {
-			actual, err := checksum(path)
+			actual, err := checksum(found.path)
 			if err != nil {
 				return nil, err
 			}
@@ -60,13 +70,108 @@ func Discover(cfg *config.Plugin, paths []string) (*exec.Cmd, error) {
 			}
 		}
 
-		cmd := exec.Command(path)
+		cmd := exec.Command(found.path)
 		return cmd, nil
 	}
 
 	return nil, nil
 }
 
+// BuiltinVersion returns the version of the builtin plugins, which is the
+// version of Waypoint.
+func BuiltinVersion() *version.Version {
+	v := wpversion.Version
+	if wpversion.VersionPrerelease != "" {
+		v += "-" + wpversion.VersionPrerelease
+	}
+
+	result, err := version.NewVersion(v)
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
+
+// discovered is a plugin binary. version is nil if the binary isn't
+// versioned.
+type discovered struct {
+	path    string
+	version *version.Version
+}
+
+// discoverPath returns the binaries of the plugin in the directory.
+func discoverPath(dir, expected, ext string) ([]*discovered, error) {
+	var result []*discovered
+	path := filepath.Join(dir, expected+ext)
+	if _, err := os.Stat(path); err == nil {
+		result = append(result, &discovered{path: path})
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, expected+"_v*"+ext))
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		raw := strings.TrimSuffix(filepath.Base(match), ext)
+		raw = strings.TrimPrefix(raw, expected+"_v")
+		v, err := version.NewVersion(raw)
+		if err != nil {
+			// Not a version, such as a backup of the binary
+			continue
+		}
+
+		result = append(result, &discovered{path: match, version: v})
+	}
+
+	return result, nil
+}
+
+// discoverSelect returns the binary to use from the binaries in a path.
+func discoverSelect(cfg *config.Plugin, candidates []*discovered) (*discovered, error) {
+	var unversioned, highest, satisfying *discovered
+	for _, c := range candidates {
+		if c.version == nil {
+			unversioned = c
+			continue
+		}
+
+		if highest == nil || c.version.GreaterThan(highest.version) {
+			highest = c
+		}
+
+		if cfg.Required.Check(c.version) == nil &&
+			(satisfying == nil || c.version.GreaterThan(satisfying.version)) {
+			satisfying = c
+		}
+	}
+
+	if cfg.Required == nil || cfg.Required.Constraints == nil {
+		if unversioned != nil {
+			return unversioned, nil
+		}
+
+		return highest, nil
+	}
+
+	if satisfying != nil {
+		return satisfying, nil
+	}
+
+	// Report the highest version found, or an unknown version if every
+	// binary is unversioned.
+	if highest == nil {
+		return nil, fmt.Errorf(
+			"%s. The version of a plugin is known only if its binary is named "+
+				"waypoint-plugin-%s_v<version>",
+			cfg.Required.Check(nil), cfg.Name)
+	}
+
+	return nil, cfg.Required.Check(highest.version)
+}
+
 // DefaultPaths returns the default search paths for plugins. These are:
 //
 //   * pwd given
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/hashicorp/go-version"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/waypoint/internal/config"
@@ -95,6 +96,44 @@ func TestDiscover(t *testing.T) {
 			"checksum",
 			nil,
 		},
+
+		{
+			"Unversioned preferred",
+			[]string{filepath.Join("testdata", "pathC")},
+			&config.Plugin{Name: "c"},
+			"",
+			&exec.Cmd{
+				Path: filepath.Join("testdata", "pathC", "waypoint-plugin-c"),
+				Args: []string{filepath.Join("testdata", "pathC", "waypoint-plugin-c")},
+			},
+		},
+
+		{
+			"Highest satisfying version",
+			[]string{filepath.Join("testdata", "pathC")},
+			&config.Plugin{Name: "c", Required: required("~> 1.0")},
+			"",
+			&exec.Cmd{
+				Path: filepath.Join("testdata", "pathC", "waypoint-plugin-c_v1.2.0"),
+				Args: []string{filepath.Join("testdata", "pathC", "waypoint-plugin-c_v1.2.0")},
+			},
+		},
+
+		{
+			"No satisfying version",
+			[]string{filepath.Join("testdata", "pathC")},
+			&config.Plugin{Name: "c", Required: required(">= 3.0")},
+			"is version 2.0.0",
+			nil,
+		},
+
+		{
+			"Unversioned with a constraint",
+			[]string{filepath.Join("testdata", "pathB")},
+			&config.Plugin{Name: "b", Required: required(">= 1.0")},
+			"an unknown version",
+			nil,
+		},
 	}
 
 	for _, tt := range cases {
@@ -126,3 +165,12 @@ func TestDiscover(t *testing.T) {
 		})
 	}
 }
+
+func required(v string) *config.RequiredPlugin {
+	constraints, err := version.NewConstraint(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return &config.RequiredPlugin{Name: "c", Version: v, Constraints: constraints}
+}
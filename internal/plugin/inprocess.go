@@ -0,0 +1,34 @@
+package plugin
+
+import (
+	"github.com/hashicorp/waypoint-plugin-sdk/component"
+
+	"github.com/hashicorp/waypoint/builtin/aws/ecs"
+	"github.com/hashicorp/waypoint/builtin/docker"
+	"github.com/hashicorp/waypoint/builtin/k8s"
+	"github.com/hashicorp/waypoint/builtin/nomad"
+)
+
+// InProcessPlatforms are the builtin platforms that implement interfaces
+// that the plugin SDK doesn't have, such as Scaler and Planner, by plugin
+// name. Those interfaces can't be called over the plugin protocol, so the
+// platform is also created in-process to call them.
+var InProcessPlatforms = map[string]func() component.Platform{
+	"kubernetes": func() component.Platform { return &k8s.Platform{} },
+	"nomad":      func() component.Platform { return &nomad.Platform{} },
+	"aws-ecs":    func() component.Platform { return &ecs.Platform{} },
+	"docker":     func() component.Platform { return &docker.Platform{} },
+}
+
+var (
+	_ Scaler            = (*k8s.Platform)(nil)
+	_ Scaler            = (*nomad.Platform)(nil)
+	_ Scaler            = (*ecs.Platform)(nil)
+	_ Scaler            = (*docker.Platform)(nil)
+	_ Planner           = (*k8s.Platform)(nil)
+	_ Planner           = (*ecs.Platform)(nil)
+	_ Planner           = (*docker.Platform)(nil)
+	_ DeclaredResources = (*k8s.Platform)(nil)
+	_ DeclaredResources = (*ecs.Platform)(nil)
+	_ DeclaredResources = (*docker.Platform)(nil)
+)
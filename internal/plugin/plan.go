@@ -0,0 +1,11 @@
+package plugin
+
+// Planner is implemented by platforms that can report the resources a
+// deploy would create or modify without applying them. This isn't part of
+// the plugin SDK, so only the InProcessPlatforms support it.
+type Planner interface {
+	// PlanFunc should return the method handle for the plan operation.
+	// The function is given the same arguments as DeployFunc and returns
+	// the resources as a string, such as a JSON manifest.
+	PlanFunc() interface{}
+}
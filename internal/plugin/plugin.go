@@ -11,14 +11,19 @@ import (
 	"github.com/hashicorp/waypoint/builtin/aws/ecr"
 	"github.com/hashicorp/waypoint/builtin/aws/ecs"
 	"github.com/hashicorp/waypoint/builtin/azure/aci"
+	"github.com/hashicorp/waypoint/builtin/azure/acr"
+	"github.com/hashicorp/waypoint/builtin/azure/appservice"
 	"github.com/hashicorp/waypoint/builtin/docker"
 	dockerpull "github.com/hashicorp/waypoint/builtin/docker/pull"
 	"github.com/hashicorp/waypoint/builtin/exec"
 	"github.com/hashicorp/waypoint/builtin/files"
+	"github.com/hashicorp/waypoint/builtin/golang"
 	"github.com/hashicorp/waypoint/builtin/google/cloudrun"
 	"github.com/hashicorp/waypoint/builtin/k8s"
+	k8sbuild "github.com/hashicorp/waypoint/builtin/k8s/build"
 	"github.com/hashicorp/waypoint/builtin/netlify"
 	"github.com/hashicorp/waypoint/builtin/nomad"
+	"github.com/hashicorp/waypoint/builtin/oci"
 	"github.com/hashicorp/waypoint/builtin/pack"
 )
 
@@ -31,13 +36,18 @@ var (
 		"docker":                   docker.Options,
 		"docker-pull":              dockerpull.Options,
 		"exec":                     exec.Options,
+		"go":                       golang.Options,
 		"google-cloud-run":         cloudrun.Options,
 		"azure-container-instance": aci.Options,
+		"azure-app-service":        appservice.Options,
+		"azure-acr":                acr.Options,
 		"kubernetes":               k8s.Options,
+		"kubernetes-build":         k8sbuild.Options,
 		"netlify":                  netlify.Options,
 		"aws-ecs":                  ecs.Options,
 		"aws-ecr":                  ecr.Options,
 		"nomad":                    nomad.Options,
+		"oci":                      oci.Options,
 		"aws-ami":                  ami.Options,
 		"aws-ec2":                  ec2.Options,
 		"aws-alb":                  alb.Options,
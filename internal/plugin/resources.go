@@ -0,0 +1,11 @@
+package plugin
+
+// DeclaredResources is implemented by platforms that can list the resources
+// on the platform that make up a deployment, such as containers or services.
+// This isn't part of the plugin SDK, so only the InProcessPlatforms support it.
+type DeclaredResources interface {
+	// ResourcesFunc should return the method handle for listing resources.
+	// The function is given the deployment along with the usual arguments
+	// such as the logger, and returns a []*resource.Resource.
+	ResourcesFunc() interface{}
+}
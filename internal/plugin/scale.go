@@ -0,0 +1,11 @@
+package plugin
+
+// Scaler is implemented by platforms that can change the number of replicas
+// of a deployment without deploying again. This isn't part of the plugin
+// SDK, so only the InProcessPlatforms support it.
+type Scaler interface {
+	// ScaleFunc should return the method handle for the scale operation.
+	// The function is given the deployment and the replica count as an
+	// int32, along with the usual arguments such as the UI and logger.
+	ScaleFunc() interface{}
+}
@@ -10,6 +10,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	configpkg "github.com/hashicorp/waypoint/internal/config"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
@@ -124,6 +125,15 @@ func (r *Runner) accept(ctx context.Context, id string) error {
 	// We need a mutex to protect against simultaneous sends to the client.
 	var sendMutex sync.Mutex
 
+	// The output and errors of the job are sent with the sensitive values
+	// of the configuration redacted. The values are recorded as the
+	// configuration is evaluated.
+	sensitive := &configpkg.Sensitive{}
+	client = &redactStream{
+		Waypoint_RunnerJobStreamClient: client,
+		sensitive:                      sensitive,
+	}
+
 	// For our UI, we always send output to the server. If we have a local UI
 	// set, we mirror to that as well.
 	var ui terminal.UI = &runnerUI{
@@ -210,7 +220,7 @@ func (r *Runner) accept(ctx context.Context, id string) error {
 		// Execute the job. We have to close the UI right afterwards to
 		// ensure that no more output is writting to the client.
 		log.Info("starting job execution")
-		result, err = r.executeJob(ctx, log, ui, assignment.Assignment.Job, wd)
+		result, err = r.executeJob(ctx, log, ui, sensitive, assignment.Assignment.Job, wd)
 		if ui, ok := ui.(*runnerUI); ok {
 			ui.Close()
 		}
@@ -3,11 +3,13 @@ package runner
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
-	"github.com/hashicorp/hcl/v2/hclsimple"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -27,23 +29,36 @@ func (r *Runner) executeJob(
 	ctx context.Context,
 	log hclog.Logger,
 	ui terminal.UI,
+	sensitive *configpkg.Sensitive,
 	job *pb.Job,
 	wd string,
 ) (*pb.Job_Result, error) {
 	// Eventually we'll need to extract the data source. For now we're
 	// just building for local exec so it is the working directory.
-	path := configpkg.Filename
-	if wd != "" {
-		path = filepath.Join(wd, path)
-	}
+	path := configpkg.FilenameIn(wd)
 
 	// Determine the evaluation context we'll be using
 	configCtx := configpkg.EvalContext(filepath.Dir(path))
+	sensitive.Track(configCtx)
+
+	// Input variables are set from the environment of the runner and then
+	// from the values assigned for the job, such as with -var.
+	vars := configpkg.EnvVariables(os.Environ())
+	for name, v := range job.Variables {
+		expr, diags := hclsyntax.ParseExpression([]byte(v), "job variable "+name, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		vars[name] = &configpkg.VariableValue{Expr: expr, Source: "the job"}
+	}
 
-	// Decode the configuration
-	var cfg configpkg.Config
+	// Decode the configuration. The data sources are only fetched here
+	// rather than by the CLI, since they run commands and call other
+	// services.
 	log.Trace("reading configuration", "path", path)
-	if err := hclsimple.DecodeFile(path, configCtx, &cfg); err != nil {
+	cfg, err := configpkg.Load(path, configCtx, vars, configpkg.WithData())
+	if err != nil {
 		return nil, err
 	}
 
@@ -72,7 +87,7 @@ func (r *Runner) executeJob(
 		core.WithUI(ui),
 		core.WithComponents(factories),
 		core.WithClient(r.client),
-		core.WithConfig(&cfg),
+		core.WithConfig(cfg),
 		core.WithConfigContext(configCtx),
 		core.WithDataDir(projDir),
 		core.WithRootDir(filepath.Dir(path)),
@@ -112,6 +127,15 @@ func (r *Runner) executeJob(
 	case *pb.Job_Destroy:
 		return r.executeDestroyOp(ctx, job, project)
 
+	case *pb.Job_Scale:
+		return r.executeScaleOp(ctx, job, project)
+
+	case *pb.Job_Inspect:
+		return r.executeInspectOp(ctx, job, project)
+
+	case *pb.Job_PipelineStep:
+		return r.executePipelineStepOp(ctx, job, project)
+
 	case *pb.Job_Release:
 		return r.executeReleaseOp(ctx, log, job, project)
 
@@ -165,10 +189,19 @@ func (r *Runner) pluginFactories(
 		// we don't have it already registered.
 		if cmd == nil {
 			if _, ok := plugin.Builtins[pluginCfg.Name]; !ok {
-				perr = multierror.Append(perr, fmt.Errorf(
-					"plugin %q not found",
-					pluginCfg.Name))
+				err := fmt.Errorf("plugin %q not found", pluginCfg.Name)
+				if req := pluginCfg.Required; req != nil && req.Source != "" {
+					err = fmt.Errorf("%s. Install it from %s", err, req.Source)
+				}
+
+				perr = multierror.Append(perr, err)
 				plog.Warn("plugin not found")
+			} else if err := pluginCfg.Required.Check(plugin.BuiltinVersion()); err != nil {
+				// Builtin plugins have the version of Waypoint, so the
+				// runner itself must be a different version.
+				perr = multierror.Append(perr, fmt.Errorf(
+					"%s. The plugin is builtin, so it has the version of the runner", err))
+				plog.Warn("builtin plugin doesn't satisfy the version constraint")
 			} else {
 				plog.Debug("plugin found as builtin")
 				for _, t := range pluginCfg.Types() {
@@ -24,15 +24,35 @@ func (r *Runner) executeBuildOp(
 		panic("operation not expected type")
 	}
 
-	build, push, err := app.Build(ctx, core.BuildWithPush(!op.Build.DisablePush))
+	// With a build matrix, each variant is built and pushed in turn.
+	variants, err := app.Variants(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.Job_Result{
-		Build: &pb.Job_BuildResult{
+	var results []*pb.Job_BuildResult
+	for _, variant := range variants {
+		build, push, err := variant.Build(ctx, core.BuildWithPush(!op.Build.DisablePush))
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &pb.Job_BuildResult{
 			Build: build,
 			Push:  push,
-		},
+		})
+	}
+
+	result := results[0]
+	if app.Matrix() != nil {
+		result = &pb.Job_BuildResult{
+			Build:  results[0].Build,
+			Push:   results[0].Push,
+			Matrix: results,
+		}
+	}
+
+	return &pb.Job_Result{
+		Build: result,
 	}, nil
 }
@@ -24,6 +24,20 @@ func (r *Runner) executeDeployOp(
 		panic("operation not expected type")
 	}
 
+	// A dry run only reports what would be deployed.
+	if op.Deploy.DryRun {
+		plan, err := app.PlanDeploy(ctx, op.Deploy.Artifact)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pb.Job_Result{
+			Deploy: &pb.Job_DeployResult{
+				Plan: plan,
+			},
+		}, nil
+	}
+
 	deployment, err := app.Deploy(ctx, op.Deploy.Artifact)
 	if err != nil {
 		return nil, err
@@ -27,8 +27,22 @@ func (r *Runner) executeDestroyOp(
 
 	switch t := op.Destroy.Target.(type) {
 	case *pb.Job_DestroyOp_Deployment:
+		if err := destroyCheckApp(job, t.Deployment.Application); err != nil {
+			return nil, err
+		}
+
 		err = app.DestroyDeploy(ctx, t.Deployment)
 
+	case *pb.Job_DestroyOp_Release:
+		if err := destroyCheckApp(job, t.Release.Application); err != nil {
+			return nil, err
+		}
+
+		err = app.DestroyRelease(ctx, t.Release)
+
+	case *pb.Job_DestroyOp_Releases:
+		err = app.DestroyReleases(ctx)
+
 	case *pb.Job_DestroyOp_Workspace:
 		err = app.Destroy(ctx)
 
@@ -41,3 +55,16 @@ func (r *Runner) executeDestroyOp(
 
 	return &pb.Job_Result{}, nil
 }
+
+// destroyCheckApp returns an error if the deployment or release to destroy
+// belongs to another app than the job, since it would be destroyed with
+// the plugins of the job's app.
+func destroyCheckApp(job *pb.Job, ref *pb.Ref_Application) error {
+	if ref.GetProject() != job.Application.Project ||
+		ref.GetApplication() != job.Application.Application {
+		return fmt.Errorf("the target belongs to the app %q, not %q",
+			ref.GetApplication(), job.Application.Application)
+	}
+
+	return nil
+}
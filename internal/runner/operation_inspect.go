@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"context"
+
+	"github.com/hashicorp/waypoint/internal/core"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func (r *Runner) executeInspectOp(
+	ctx context.Context,
+	job *pb.Job,
+	project *core.Project,
+) (*pb.Job_Result, error) {
+	app, err := project.App(job.Application.Application)
+	if err != nil {
+		return nil, err
+	}
+
+	op, ok := job.Operation.(*pb.Job_Inspect)
+	if !ok {
+		// this shouldn't happen since the call to this function is gated
+		// on the above type match.
+		panic("operation not expected type")
+	}
+
+	resources, err := app.Resources(ctx, op.Inspect.Deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pb.Job_InspectResult{}
+	for _, r := range resources {
+		result.Resources = append(result.Resources, &pb.Deployment_Resource{
+			Type:  r.Type,
+			Name:  r.Name,
+			State: r.State,
+		})
+	}
+
+	return &pb.Job_Result{Inspect: result}, nil
+}
@@ -0,0 +1,62 @@
+package runner
+
+import (
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// redactStream is a job stream that redacts the values that the
+// configuration marked as sensitive from the terminal output and errors
+// of the job before they are sent to the server. The values are known
+// once the configuration is loaded, so output before then isn't changed.
+type redactStream struct {
+	pb.Waypoint_RunnerJobStreamClient
+
+	sensitive *configpkg.Sensitive
+}
+
+func (s *redactStream) Send(req *pb.RunnerJobStreamRequest) error {
+	switch ev := req.Event.(type) {
+	case *pb.RunnerJobStreamRequest_Terminal:
+		for _, e := range ev.Terminal.Events {
+			s.redactEvent(e)
+		}
+
+	case *pb.RunnerJobStreamRequest_Error_:
+		if st := ev.Error.Error; st != nil {
+			st.Message = s.sensitive.Redact(st.Message)
+		}
+	}
+
+	return s.Waypoint_RunnerJobStreamClient.Send(req)
+}
+
+func (s *redactStream) redactEvent(e *pb.GetJobStreamResponse_Terminal_Event) {
+	redact := s.sensitive.Redact
+	switch ev := e.Event.(type) {
+	case *pb.GetJobStreamResponse_Terminal_Event_Line_:
+		ev.Line.Msg = redact(ev.Line.Msg)
+
+	case *pb.GetJobStreamResponse_Terminal_Event_Status_:
+		ev.Status.Msg = redact(ev.Status.Msg)
+
+	case *pb.GetJobStreamResponse_Terminal_Event_NamedValues_:
+		for _, v := range ev.NamedValues.Values {
+			v.Value = redact(v.Value)
+		}
+
+	case *pb.GetJobStreamResponse_Terminal_Event_Raw_:
+		ev.Raw.Data = []byte(redact(string(ev.Raw.Data)))
+
+	case *pb.GetJobStreamResponse_Terminal_Event_Table_:
+		for _, row := range ev.Table.Rows {
+			for _, entry := range row.Entries {
+				entry.Value = redact(entry.Value)
+			}
+		}
+
+	case *pb.GetJobStreamResponse_Terminal_Event_Step_:
+		ev.Step.Msg = redact(ev.Step.Msg)
+		ev.Step.Output = []byte(redact(string(ev.Step.Output)))
+	}
+}
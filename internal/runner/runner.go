@@ -236,6 +236,26 @@ func WithLocal(ui terminal.UI) Option {
 	}
 }
 
+// WithId sets the ID of the runner rather than generating one. The same ID
+// should be used when the runner restarts so that the server keeps its
+// adoption state.
+func WithId(id string) Option {
+	return func(r *Runner, cfg *config) error {
+		r.id = id
+		r.runner.Id = id
+		return nil
+	}
+}
+
+// WithLabels sets the labels that the runner registers with. These are
+// shown to operators to tell runners apart.
+func WithLabels(labels map[string]string) Option {
+	return func(r *Runner, cfg *config) error {
+		r.runner.Labels = labels
+		return nil
+	}
+}
+
 // ByIdOnly sets it so that only jobs that target this runner by specific
 // ID may be assigned.
 func ByIdOnly() Option {
@@ -39,14 +39,16 @@ func TestRunnerStart(t *testing.T) {
 	require.NoError(err)
 	require.Equal(runner.Id(), resp.Id)
 
+	require.True(resp.Online)
+
 	// Close
 	require.NoError(runner.Close())
 	time.Sleep(100 * time.Millisecond)
 
-	// The runner should not be registered
-	_, err = client.GetRunner(ctx, &pb.GetRunnerRequest{RunnerId: runner.Id()})
-	require.Error(err)
-	require.Equal(codes.NotFound, status.Code(err))
+	// The runner should be known but offline
+	resp, err = client.GetRunner(ctx, &pb.GetRunnerRequest{RunnerId: runner.Id()})
+	require.NoError(err)
+	require.False(resp.Online)
 }
 
 func TestRunnerStart_config(t *testing.T) {
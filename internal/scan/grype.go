@@ -0,0 +1,56 @@
+package scan
+
+import (
+	"encoding/json"
+	"strings"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type grypeReport struct {
+	Matches []struct {
+		Vulnerability struct {
+			ID          string `json:"id"`
+			Severity    string `json:"severity"`
+			Description string `json:"description"`
+
+			// FixedInVersion is set by older versions of Grype, newer
+			// versions list the versions in Fix.
+			FixedInVersion string `json:"fixedInVersion"`
+			Fix            struct {
+				Versions []string `json:"versions"`
+			} `json:"fix"`
+		} `json:"vulnerability"`
+
+		Artifact struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"artifact"`
+	} `json:"matches"`
+}
+
+func parseGrype(data []byte) ([]*pb.ScanResult_Finding, error) {
+	var report grypeReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+
+	var findings []*pb.ScanResult_Finding
+	for _, m := range report.Matches {
+		fixed := m.Vulnerability.FixedInVersion
+		if fixed == "" {
+			fixed = strings.Join(m.Vulnerability.Fix.Versions, ", ")
+		}
+
+		findings = append(findings, &pb.ScanResult_Finding{
+			Id:               m.Vulnerability.ID,
+			Package:          m.Artifact.Name,
+			InstalledVersion: m.Artifact.Version,
+			FixedVersion:     fixed,
+			Severity:         ParseSeverity(m.Vulnerability.Severity),
+			Title:            m.Vulnerability.Description,
+		})
+	}
+
+	return findings, nil
+}
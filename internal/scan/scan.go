@@ -0,0 +1,128 @@
+// Package scan runs vulnerability scanners such as Trivy and Grype against
+// images and converts their reports into findings stored on the build.
+package scan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	ScannerTrivy = "trivy"
+	ScannerGrype = "grype"
+)
+
+// Scanners is the list of supported scanners.
+var Scanners = []string{ScannerTrivy, ScannerGrype}
+
+// Options configures a scan.
+type Options struct {
+	// Scanner is the name of the scanner to run, one of Scanners.
+	Scanner string
+
+	// Image is the image reference to scan.
+	Image string
+
+	// IgnoreUnfixed drops findings that don't have a fixed version.
+	IgnoreUnfixed bool
+
+	// Output receives the stderr of the scanner so progress is visible.
+	Output io.Writer
+}
+
+// Run runs the scanner against the image and returns the findings, sorted
+// by severity with the most severe first.
+func Run(ctx context.Context, log hclog.Logger, opts *Options) ([]*pb.ScanResult_Finding, error) {
+	var args []string
+	var parse func([]byte) ([]*pb.ScanResult_Finding, error)
+	switch opts.Scanner {
+	case ScannerTrivy:
+		args = []string{"image", "--format", "json", "--no-progress", "--quiet", opts.Image}
+		parse = parseTrivy
+
+	case ScannerGrype:
+		args = []string{opts.Image, "-o", "json", "-q"}
+		parse = parseGrype
+
+	default:
+		return nil, fmt.Errorf("unknown scanner %q, must be one of: %s",
+			opts.Scanner, strings.Join(Scanners, ", "))
+	}
+
+	path, err := exec.LookPath(opts.Scanner)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be installed to scan images: %s", opts.Scanner, err)
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = opts.Output
+
+	log.Debug("running scanner", "path", path, "args", args)
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error running %s: %s", opts.Scanner, err)
+	}
+
+	findings, err := parse(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s report: %s", opts.Scanner, err)
+	}
+
+	if opts.IgnoreUnfixed {
+		filtered := findings[:0]
+		for _, f := range findings {
+			if f.FixedVersion != "" {
+				filtered = append(filtered, f)
+			}
+		}
+		findings = filtered
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity > findings[j].Severity
+	})
+
+	return findings, nil
+}
+
+// ParseSeverity parses the severity name used by scanners, such as "High".
+// Unknown names are returned as UNKNOWN.
+func ParseSeverity(v string) pb.ScanResult_Severity {
+	return pb.ScanResult_Severity(pb.ScanResult_Severity_value[strings.ToUpper(v)])
+}
+
+// Passed returns true if none of the findings are at or above the
+// threshold. An UNKNOWN threshold never fails.
+func Passed(findings []*pb.ScanResult_Finding, threshold pb.ScanResult_Severity) bool {
+	if threshold == pb.ScanResult_UNKNOWN {
+		return true
+	}
+
+	for _, f := range findings {
+		if f.Severity >= threshold {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Summary returns the number of findings for each severity.
+func Summary(findings []*pb.ScanResult_Finding) map[pb.ScanResult_Severity]int {
+	result := map[pb.ScanResult_Severity]int{}
+	for _, f := range findings {
+		result[f.Severity]++
+	}
+
+	return result
+}
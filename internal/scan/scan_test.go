@@ -0,0 +1,114 @@
+package scan
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		File     string
+		Parse    func([]byte) ([]*pb.ScanResult_Finding, error)
+		Expected []*pb.ScanResult_Finding
+	}{
+		{
+			"trivy.json",
+			parseTrivy,
+			[]*pb.ScanResult_Finding{
+				{
+					Id:               "CVE-2020-1967",
+					Package:          "libssl1.1",
+					InstalledVersion: "1.1.1f-r0",
+					FixedVersion:     "1.1.1g-r0",
+					Severity:         pb.ScanResult_HIGH,
+					Title:            "openssl: Segmentation fault in SSL_check_chain",
+				},
+				{
+					Id:               "CVE-2020-28928",
+					Package:          "musl",
+					InstalledVersion: "1.1.24-r8",
+					Severity:         pb.ScanResult_LOW,
+				},
+			},
+		},
+
+		{
+			"trivy-v2.json",
+			parseTrivy,
+			[]*pb.ScanResult_Finding{
+				{
+					Id:               "CVE-2021-3711",
+					Package:          "libcrypto1.1",
+					InstalledVersion: "1.1.1f-r0",
+					FixedVersion:     "1.1.1l-r0",
+					Severity:         pb.ScanResult_CRITICAL,
+					Title:            "openssl: SM2 Decryption Buffer Overflow",
+				},
+			},
+		},
+
+		{
+			"grype.json",
+			parseGrype,
+			[]*pb.ScanResult_Finding{
+				{
+					Id:               "CVE-2020-28928",
+					Package:          "musl",
+					InstalledVersion: "1.1.24-r8",
+					Severity:         pb.ScanResult_LOW,
+					Title:            "In musl libc through 1.2.1, wcsnrtombs mishandles particular combinations of destination buffer size and source character limit",
+				},
+				{
+					Id:               "CVE-2020-1967",
+					Package:          "libssl1.1",
+					InstalledVersion: "1.1.1f-r0",
+					FixedVersion:     "1.1.1g-r0",
+					Severity:         pb.ScanResult_HIGH,
+				},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.File, func(t *testing.T) {
+			require := require.New(t)
+
+			data, err := ioutil.ReadFile(filepath.Join("testdata", tt.File))
+			require.NoError(err)
+
+			findings, err := tt.Parse(data)
+			require.NoError(err)
+			require.Len(findings, len(tt.Expected))
+			for i, f := range findings {
+				require.Equal(tt.Expected[i].String(), f.String())
+			}
+		})
+	}
+}
+
+func TestPassed(t *testing.T) {
+	require := require.New(t)
+
+	findings := []*pb.ScanResult_Finding{
+		{Id: "a", Severity: pb.ScanResult_MEDIUM},
+		{Id: "b", Severity: pb.ScanResult_LOW},
+	}
+
+	require.True(Passed(findings, pb.ScanResult_UNKNOWN))
+	require.True(Passed(findings, pb.ScanResult_HIGH))
+	require.False(Passed(findings, pb.ScanResult_MEDIUM))
+	require.False(Passed(findings, pb.ScanResult_LOW))
+}
+
+func TestParseSeverity(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(pb.ScanResult_HIGH, ParseSeverity("High"))
+	require.Equal(pb.ScanResult_NEGLIGIBLE, ParseSeverity("Negligible"))
+	require.Equal(pb.ScanResult_UNKNOWN, ParseSeverity("other"))
+}
@@ -0,0 +1,59 @@
+package scan
+
+import (
+	"bytes"
+	"encoding/json"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+type trivyReport struct {
+	// Results is only set by Trivy 0.20 and later, earlier versions
+	// output the list of results directly.
+	Results []trivyResult
+}
+
+type trivyResult struct {
+	Target          string
+	Vulnerabilities []struct {
+		VulnerabilityID  string
+		PkgName          string
+		InstalledVersion string
+		FixedVersion     string
+		Title            string
+		Severity         string
+	}
+}
+
+func parseTrivy(data []byte) ([]*pb.ScanResult_Finding, error) {
+	var results []trivyResult
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &results); err != nil {
+			return nil, err
+		}
+	} else if len(data) > 0 {
+		var report trivyReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, err
+		}
+		results = report.Results
+	}
+
+	var findings []*pb.ScanResult_Finding
+	for _, r := range results {
+		for _, v := range r.Vulnerabilities {
+			findings = append(findings, &pb.ScanResult_Finding{
+				Id:               v.VulnerabilityID,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         ParseSeverity(v.Severity),
+				Title:            v.Title,
+			})
+		}
+	}
+
+	return findings, nil
+}
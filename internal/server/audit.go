@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+)
+
+// Auditor may be implemented by the service to record calls to the server.
+// This is called after each unary call with the error it returned. If the
+// server requires authentication, this is called with the context from
+// AuthContextProvider so the caller is known. Streaming endpoints must
+// record calls themselves.
+type Auditor interface {
+	Audit(ctx context.Context, endpoint string, req interface{}, err error)
+}
+
+// auditUnaryInterceptor returns a gRPC unary interceptor that records each
+// call with the given Auditor.
+func auditUnaryInterceptor(a Auditor) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		a.Audit(ctx, filepath.Base(info.FullMethod), req, err)
+		return resp, err
+	}
+}
@@ -18,6 +18,22 @@ type AuthChecker interface {
 	Authenticate(ctx context.Context, token, endpoint string, effects []string) error
 }
 
+// AuthContextProvider may be implemented by an AuthChecker to store
+// information about the caller of an authenticated request in its context,
+// such as their identity. This is called after Authenticate succeeds.
+type AuthContextProvider interface {
+	AuthContext(ctx context.Context, token string) (context.Context, error)
+}
+
+// Authorizer may be implemented by an AuthChecker to check that the caller
+// can access the resources in the request. This is called after
+// Authenticate with the context from AuthContextProvider. For streaming
+// endpoints req is nil since the requests are only known once they're
+// received, so those must check any resources they access themselves.
+type Authorizer interface {
+	Authorize(ctx context.Context, endpoint string, req interface{}) error
+}
+
 var readonly = []string{"readonly"}
 
 // Information about the effects of endpoints that are authenticated. If a endpoint
@@ -62,8 +78,23 @@ func authUnaryInterceptor(checker AuthChecker) grpc.UnaryServerInterceptor {
 
 		err := checker.Authenticate(ctx, token, name, effects)
 		if err != nil {
+			authFailed(checker, ctx)
 			return nil, err
 		}
+
+		if p, ok := checker.(AuthContextProvider); ok {
+			ctx, err = p.AuthContext(ctx, token)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if a, ok := checker.(Authorizer); ok {
+			if err := a.Authorize(ctx, name, req); err != nil {
+				return nil, err
+			}
+		}
+
 		return handler(ctx, req)
 	}
 }
@@ -104,10 +135,45 @@ func authStreamInterceptor(checker AuthChecker) grpc.StreamServerInterceptor {
 
 		err := checker.Authenticate(ss.Context(), token, name, effects)
 		if err != nil {
+			authFailed(checker, ss.Context())
 			return err
 		}
 
+		if p, ok := checker.(AuthContextProvider); ok {
+			ctx, err := p.AuthContext(ss.Context(), token)
+			if err != nil {
+				return err
+			}
+
+			ss = &authServerStream{ServerStream: ss, ctx: ctx}
+		}
+
+		if a, ok := checker.(Authorizer); ok {
+			if err := a.Authorize(ss.Context(), name, nil); err != nil {
+				return err
+			}
+		}
+
 		// Invoke the handler.
 		return handler(srv, ss)
 	}
 }
+
+// authFailed tells the checker that the authentication of a call failed if
+// it limits calls, see RateLimiter.
+func authFailed(checker AuthChecker, ctx context.Context) {
+	if l, ok := checker.(RateLimiter); ok {
+		l.AuthFailed(ctx)
+	}
+}
+
+// authServerStream is a grpc.ServerStream with the context replaced by the
+// context from AuthContextProvider.
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
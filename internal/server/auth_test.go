@@ -6,7 +6,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type trivialAuth struct {
@@ -55,3 +57,39 @@ func TestAuthUnaryInterceptor(t *testing.T) {
 	require.Equal("bar", chk.method)
 	require.Equal(DefaultEffects, chk.effects)
 }
+
+// failingAuth fails every authentication and counts the failures.
+type failingAuth struct {
+	failed int
+}
+
+func (f *failingAuth) Authenticate(ctx context.Context, token string, endpoint string, effects []string) error {
+	return status.Errorf(codes.Unauthenticated, "invalid token")
+}
+
+func (f *failingAuth) RateLimitAddr(ctx context.Context, endpoint string) error { return nil }
+func (f *failingAuth) RateLimit(ctx context.Context, endpoint string) error     { return nil }
+func (f *failingAuth) AuthFailed(ctx context.Context)                           { f.failed++ }
+
+func TestAuthUnaryInterceptor_failed(t *testing.T) {
+	require := require.New(t)
+
+	var chk failingAuth
+	f := authUnaryInterceptor(&chk)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{
+		"authorization": []string{"this-is-a-token"},
+	})
+
+	for i := 1; i <= 2; i++ {
+		_, err := f(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/foo/bar"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				t.Fatal("handler should not be called")
+				return nil, nil
+			},
+		)
+		require.Error(err)
+		require.Equal(codes.Unauthenticated, status.Code(err))
+		require.Equal(i, chk.failed)
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultDrainTimeout is how long the server waits for the service to
+// drain when it shuts down if WithDrainTimeout isn't set.
+const DefaultDrainTimeout = 30 * time.Second
+
+// Drainer is implemented by services that finish their work before the
+// server shuts down. Drain is called when the context of the server is
+// cancelled and before the gRPC server stops, so that connected runners
+// and entrypoints can still complete their calls. Drain must return when
+// ctx is done.
+type Drainer interface {
+	Drain(ctx context.Context)
+}
+
+// drain drains the service if it implements Drainer.
+func drain(opts *options) {
+	d, ok := opts.Service.(Drainer)
+	if !ok {
+		return
+	}
+
+	timeout := opts.DrainTimeout
+	if timeout < 0 {
+		return
+	}
+	if timeout == 0 {
+		timeout = DefaultDrainTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log := opts.Logger.Named("grpc")
+	log.Info("draining server before shutdown", "timeout", timeout)
+	d.Drain(ctx)
+}
@@ -29,6 +29,13 @@ type Client struct {
 	Stdin         io.Reader
 	Stdout        io.Writer
 	Stderr        io.Writer
+
+	// InstanceId is the instance to exec into. If this is empty, the
+	// server chooses an instance of the deployment.
+	InstanceId string
+
+	// DisablePTY disables allocating a PTY even if stdout is a terminal.
+	DisablePTY bool
 }
 
 func (c *Client) Run() (int, error) {
@@ -38,7 +45,7 @@ func (c *Client) Run() (int, error) {
 	var ptyF *os.File
 	var status terminal.Status
 
-	if f, ok := c.Stdout.(*os.File); ok && sshterm.IsTerminal(int(f.Fd())) {
+	if f, ok := c.Stdout.(*os.File); ok && !c.DisablePTY && sshterm.IsTerminal(int(f.Fd())) {
 		status = c.UI.Status()
 		defer status.Close()
 		status.Update(fmt.Sprintf("Connecting to deployment v%d...", c.DeploymentSeq))
@@ -86,6 +93,7 @@ func (c *Client) Run() (int, error) {
 				DeploymentId: c.DeploymentId,
 				Args:         c.Args,
 				Pty:          ptyReq,
+				InstanceId:   c.InstanceId,
 			},
 		},
 	}); err != nil {
@@ -136,30 +144,49 @@ func (c *Client) Run() (int, error) {
 	input := &EscapeWatcher{Cancel: cancel, Input: c.Stdin}
 
 	// Build our connection. We only build the stdin sending side because
-	// we can receive other message types from our recv.
-	go io.Copy(&grpc_net_conn.Conn{
-		Stream:  client,
-		Request: &pb.ExecStreamRequest{},
-		Encode: grpc_net_conn.SimpleEncoder(func(msg proto.Message) *[]byte {
-			req := msg.(*pb.ExecStreamRequest)
-			if req.Event == nil {
-				req.Event = &pb.ExecStreamRequest_Input_{
-					Input: &pb.ExecStreamRequest_Input{},
+	// we can receive other message types from our recv. Without a pty, the
+	// end of the input is sent once stdin is closed so that the command
+	// sees it, such as when input is piped to it.
+	sendInput := func() {
+		_, err := io.Copy(&grpc_net_conn.Conn{
+			Stream:  client,
+			Request: &pb.ExecStreamRequest{},
+			Encode: grpc_net_conn.SimpleEncoder(func(msg proto.Message) *[]byte {
+				req := msg.(*pb.ExecStreamRequest)
+				if req.Event == nil {
+					req.Event = &pb.ExecStreamRequest_Input_{
+						Input: &pb.ExecStreamRequest_Input{},
+					}
 				}
-			}
 
-			return &req.Event.(*pb.ExecStreamRequest_Input_).Input.Data
-		}),
-	}, input)
+				return &req.Event.(*pb.ExecStreamRequest_Input_).Input.Data
+			}),
+		}, input)
+		if err != nil || ptyF != nil {
+			return
+		}
+
+		client.Send(&pb.ExecStreamRequest{
+			Event: &pb.ExecStreamRequest_InputEof{
+				InputEof: &pb.ExecStreamRequest_InputEOF{},
+			},
+		})
+	}
+	go sendInput()
 
-	// Add our recv blocker that sends data
+	// Add our recv blocker that sends data. If the stream fails, such as
+	// if the entrypoint couldn't run the command, we return the error.
 	recvCh := make(chan *pb.ExecStreamResponse)
+	recvErrCh := make(chan error, 1)
 	go func() {
 		defer cancel()
 		for {
 			resp, err := client.Recv()
 			if err != nil {
-				c.Logger.Error("receive error", "err", err)
+				if err != io.EOF {
+					c.Logger.Error("receive error", "err", err)
+					recvErrCh <- err
+				}
 				return
 			}
 
@@ -167,10 +194,14 @@ func (c *Client) Run() (int, error) {
 		}
 	}()
 
-	// Listen for window change events
+	// Listen for window change events. The window may have changed while
+	// we waited for the session, so we also send the size right away.
 	winchCh := make(chan os.Signal, 1)
-	registerSigwinch(winchCh)
-	defer signal.Stop(winchCh)
+	if ptyF != nil {
+		registerSigwinch(winchCh)
+		defer signal.Stop(winchCh)
+		winchCh <- nil
+	}
 
 	// Loop for data
 	for {
@@ -218,7 +249,12 @@ func (c *Client) Run() (int, error) {
 			}
 
 		case <-ctx.Done():
-			return 1, nil
+			select {
+			case err := <-recvErrCh:
+				return 1, err
+			default:
+				return 1, nil
+			}
 		}
 	}
 }
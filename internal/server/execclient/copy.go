@@ -0,0 +1,152 @@
+package execclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// copyChunkSize is the size of the input messages of a file copied to the
+// instance.
+const copyChunkSize = 32 * 1024
+
+// CopyClient copies a file to or from an instance over an exec stream.
+type CopyClient struct {
+	Logger       hclog.Logger
+	Context      context.Context
+	Client       pb.WaypointClient
+	DeploymentId string
+
+	// InstanceId is the instance to copy to or from. If this is empty, the
+	// server chooses an instance of the deployment.
+	InstanceId string
+
+	// Path is the absolute path of the file on the instance.
+	Path string
+
+	// Mode is the permissions of a file copied to the instance.
+	Mode os.FileMode
+
+	// Reader, if set, is copied to the file on the instance. Otherwise the
+	// file on the instance is copied to Writer.
+	Reader io.Reader
+	Writer io.Writer
+}
+
+func (c *CopyClient) Run() error {
+	direction := pb.ExecStreamRequest_FileCopy_FROM_INSTANCE
+	if c.Reader != nil {
+		direction = pb.ExecStreamRequest_FileCopy_TO_INSTANCE
+	}
+
+	client, err := c.Client.StartExecStream(c.Context)
+	if err != nil {
+		return err
+	}
+	defer client.CloseSend()
+
+	if err := client.Send(&pb.ExecStreamRequest{
+		Event: &pb.ExecStreamRequest_Start_{
+			Start: &pb.ExecStreamRequest_Start{
+				DeploymentId: c.DeploymentId,
+				InstanceId:   c.InstanceId,
+				Copy: &pb.ExecStreamRequest_FileCopy{
+					Direction: direction,
+					Path:      c.Path,
+					Mode:      uint32(c.Mode.Perm()),
+				},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+
+	// Receive our open message. If this fails then we weren't assigned.
+	resp, err := client.Recv()
+	if err != nil {
+		return err
+	}
+	if _, ok := resp.Event.(*pb.ExecStreamResponse_Open_); !ok {
+		return fmt.Errorf("internal protocol error: unexpected opening message")
+	}
+
+	if c.Reader != nil {
+		if err := c.send(client); err != nil {
+			return err
+		}
+	}
+
+	// Receive the file, if we're copying from the instance, until the
+	// session exits.
+	for {
+		resp, err := client.Recv()
+		if err != nil {
+			return err
+		}
+
+		switch event := resp.Event.(type) {
+		case *pb.ExecStreamResponse_Output_:
+			if c.Writer != nil {
+				if _, err := c.Writer.Write(event.Output.Data); err != nil {
+					return err
+				}
+			}
+
+		case *pb.ExecStreamResponse_Exit_:
+			if event.Exit.Code != 0 {
+				return fmt.Errorf("copy exited with code %d", event.Exit.Code)
+			}
+
+			return nil
+
+		default:
+			c.Logger.Warn("unknown event type",
+				"type", fmt.Sprintf("%T", resp.Event))
+		}
+	}
+}
+
+// send sends the contents of Reader followed by the end of the input. If
+// the stream is closed while sending, such as if the file can't be written
+// on the instance, this returns nil so that the error is received.
+func (c *CopyClient) send(client pb.Waypoint_StartExecStreamClient) error {
+	buf := make([]byte, copyChunkSize)
+	for {
+		n, err := c.Reader.Read(buf)
+		if n > 0 {
+			if err := client.Send(&pb.ExecStreamRequest{
+				Event: &pb.ExecStreamRequest_Input_{
+					Input: &pb.ExecStreamRequest_Input{
+						Data: append([]byte(nil), buf[:n]...),
+					},
+				},
+			}); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	err := client.Send(&pb.ExecStreamRequest{
+		Event: &pb.ExecStreamRequest_InputEof{
+			InputEof: &pb.ExecStreamRequest_InputEOF{},
+		},
+	})
+	if err == io.EOF {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,418 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	"github.com/oklog/run"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/hashicorp/waypoint/internal/protocolversion"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// GatewayPath is the path prefix of the JSON gateway to the gRPC API on
+// the HTTP server. Each unary and server streaming method is available as
+// POST GatewayPath + method name, such as /v1/ListProjects, with the
+// request as the JSON body. Methods that only read can also be called with
+// GET. The OpenAPI specification of the gateway is served at GatewayPath +
+// "openapi.json".
+const GatewayPath = "/v1/"
+
+// gatewayMaxBody is the maximum size of the body of gateway requests.
+const gatewayMaxBody = 10 << 20
+
+// gatewayClientAddrKey is the metadata key the gateway sets to the address
+// of the HTTP client. See ClientAddr.
+const gatewayClientAddrKey = "waypoint-gateway-client-addr"
+
+// gateway translates HTTP requests with JSON bodies to gRPC calls to the
+// server. Calls are made over an in-process pipe connection so that they go
+// through the same interceptors as any other client.
+type gateway struct {
+	log     hclog.Logger
+	conn    grpc.ClientConnInterface
+	service protoreflect.ServiceDescriptor
+	spec    []byte
+}
+
+// gatewayInit starts serving the gRPC server on an in-memory listener for
+// the gateway and returns the gateway.
+func gatewayInit(group *run.Group, opts *options, log hclog.Logger) (*gateway, error) {
+	resp, err := opts.Service.GetVersionInfo(opts.Context, &empty.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	ln := newGatewayListener()
+	conn, err := grpc.DialContext(opts.Context, "gateway",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithUnaryInterceptor(protocolversion.UnaryClientInterceptor(resp.Info)),
+		grpc.WithStreamInterceptor(protocolversion.StreamClientInterceptor(resp.Info)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Add(func() error {
+		return opts.grpcServer.Serve(ln)
+	}, func(error) {
+		conn.Close()
+		ln.Close()
+	})
+
+	return newGateway(log, conn, resp.Info)
+}
+
+// newGateway returns a gateway that calls the Waypoint service over conn.
+func newGateway(log hclog.Logger, conn grpc.ClientConnInterface, info *pb.VersionInfo) (*gateway, error) {
+	sd := pb.File_internal_server_proto_server_proto.Services().ByName("Waypoint")
+	if sd == nil {
+		return nil, fmt.Errorf("Waypoint service descriptor not found")
+	}
+
+	spec, err := gatewaySpec(sd, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gateway{
+		log:     log,
+		conn:    conn,
+		service: sd,
+		spec:    spec,
+	}, nil
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, GatewayPath)
+	if name == "openapi.json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(g.spec)
+		return
+	}
+
+	md := g.service.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		gatewayError(w, status.Errorf(codes.NotFound, "unknown method: %s", name))
+		return
+	}
+	if md.IsStreamingClient() {
+		gatewayError(w, status.Errorf(codes.Unimplemented,
+			"client streaming method %s isn't available over HTTP", name))
+		return
+	}
+	// Only methods that read are available with GET, so that links and
+	// cached responses can't change anything.
+	if r.Method != http.MethodPost && (r.Method != http.MethodGet || !gatewayReadMethod(md)) {
+		allow := "POST"
+		if gatewayReadMethod(md) {
+			allow = "GET, POST"
+		}
+
+		w.Header().Set("Allow", allow)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, err := gatewayMessage(md.Input())
+	if err != nil {
+		gatewayError(w, err)
+		return
+	}
+
+	// GET requests and empty bodies are the empty request.
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, gatewayMaxBody))
+	if err != nil {
+		gatewayError(w, status.Errorf(codes.InvalidArgument, "error reading body: %s", err))
+		return
+	}
+	if len(body) > 0 {
+		if err := protojson.Unmarshal(body, req); err != nil {
+			gatewayError(w, status.Errorf(codes.InvalidArgument, "invalid request: %s", err))
+			return
+		}
+	}
+
+	// Pass the address of the client along so that the server can rate
+	// limit by it rather than by the gateway's own address.
+	ctx := metadata.AppendToOutgoingContext(r.Context(), gatewayClientAddrKey, r.RemoteAddr)
+
+	// Pass the token along. We accept it with or without the Bearer scheme.
+	if token := r.Header.Get("Authorization"); token != "" {
+		token = strings.TrimSpace(strings.TrimPrefix(token, "Bearer "))
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+	}
+
+	method := fmt.Sprintf("/%s/%s", g.service.FullName(), md.Name())
+	if md.IsStreamingServer() {
+		g.serveStream(ctx, w, md, method, req)
+		return
+	}
+
+	resp, err := gatewayMessage(md.Output())
+	if err != nil {
+		gatewayError(w, err)
+		return
+	}
+	if err := g.conn.Invoke(ctx, method, req, resp); err != nil {
+		gatewayError(w, err)
+		return
+	}
+
+	data, err := protojson.Marshal(resp)
+	if err != nil {
+		gatewayError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// serveStream calls a server streaming method and writes each response as
+// a line of JSON. Errors after the stream started are written as a line
+// with an "error" field since the status is already sent.
+func (g *gateway) serveStream(
+	ctx context.Context,
+	w http.ResponseWriter,
+	md protoreflect.MethodDescriptor,
+	method string,
+	req proto.Message,
+) {
+	stream, err := g.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, method)
+	if err == nil {
+		err = stream.SendMsg(req)
+	}
+	if err == nil {
+		err = stream.CloseSend()
+	}
+	if err != nil {
+		gatewayError(w, err)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	started := false
+	for {
+		resp, err := gatewayMessage(md.Output())
+		if err == nil {
+			err = stream.RecvMsg(resp)
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			if !started {
+				gatewayError(w, err)
+				return
+			}
+
+			data, _ := json.Marshal(map[string]interface{}{"error": gatewayErrorBody(err)})
+			w.Write(append(data, '\n'))
+			return
+		}
+
+		data, err := protojson.Marshal(resp)
+		if err != nil {
+			g.log.Warn("error encoding stream response", "method", method, "err", err)
+			return
+		}
+
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			started = true
+		}
+		w.Write(append(data, '\n'))
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// gatewayReadMethod returns true if the method only reads, such as
+// GetProject or ListDeployments.
+func gatewayReadMethod(md protoreflect.MethodDescriptor) bool {
+	name := string(md.Name())
+	for _, prefix := range []string{"Get", "List", "Search", "Diff"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return name == "StreamEvents"
+}
+
+// gatewayListener is an in-memory listener that the gateway dials to call
+// the gRPC server. Each connection is a net.Pipe.
+type gatewayListener struct {
+	connCh    chan net.Conn
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+func newGatewayListener() *gatewayListener {
+	return &gatewayListener{
+		connCh:  make(chan net.Conn),
+		closeCh: make(chan struct{}),
+	}
+}
+
+// DialContext returns a new connection to the listener.
+func (l *gatewayListener) DialContext(ctx context.Context) (net.Conn, error) {
+	server, client := net.Pipe()
+
+	var err error
+	select {
+	case l.connCh <- gatewayConn{server}:
+		return client, nil
+
+	case <-l.closeCh:
+		err = errGatewayClosed
+
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	server.Close()
+	client.Close()
+	return nil, err
+}
+
+func (l *gatewayListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.connCh:
+		return conn, nil
+
+	case <-l.closeCh:
+		return nil, errGatewayClosed
+	}
+}
+
+func (l *gatewayListener) Close() error {
+	l.closeOnce.Do(func() { close(l.closeCh) })
+	return nil
+}
+
+func (l *gatewayListener) Addr() net.Addr { return gatewayAddr{} }
+
+// gatewayConn is the server side of a gateway connection. Its addresses
+// are a gatewayAddr so that ClientAddr can tell gateway calls apart.
+type gatewayConn struct {
+	net.Conn
+}
+
+func (gatewayConn) LocalAddr() net.Addr  { return gatewayAddr{} }
+func (gatewayConn) RemoteAddr() net.Addr { return gatewayAddr{} }
+
+// gatewayAddr is the address of the connections of the gateway.
+type gatewayAddr struct{}
+
+func (gatewayAddr) Network() string { return "pipe" }
+func (gatewayAddr) String() string  { return "gateway" }
+
+// ClientAddr returns the address of the client of a gRPC call. For calls
+// made through the gateway this is the address of the HTTP client, since
+// the gRPC peer is the gateway itself. The X-Forwarded-For header isn't
+// trusted for this, since any client can set it. This returns an empty
+// string if the address isn't known.
+func ClientAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	if _, ok := p.Addr.(gatewayAddr); !ok {
+		return p.Addr.String()
+	}
+
+	// Only the gateway can set this, since it is only read from calls
+	// over the gateway listener.
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(gatewayClientAddrKey); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+var errGatewayClosed = errors.New("gateway listener closed")
+
+// gatewayMessage returns a new message of the type of the descriptor.
+func gatewayMessage(desc protoreflect.MessageDescriptor) (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unknown message type %s: %s", desc.FullName(), err)
+	}
+
+	return mt.New().Interface(), nil
+}
+
+// gatewayError writes the gRPC error as a JSON response with the matching
+// HTTP status code.
+func gatewayError(w http.ResponseWriter, err error) {
+	data, _ := json.Marshal(gatewayErrorBody(err))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatusFromCode(status.Code(err)))
+	w.Write(data)
+}
+
+// gatewayErrorBody returns the JSON body of an error response.
+func gatewayErrorBody(err error) map[string]interface{} {
+	st := status.Convert(err)
+	return map[string]interface{}{
+		"code":    st.Code().String(),
+		"message": st.Message(),
+	}
+}
+
+// httpStatusFromCode returns the HTTP status code for a gRPC status code.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
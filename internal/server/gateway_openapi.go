@@ -0,0 +1,190 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// gatewayWellKnown are the schemas of the well-known types that have a
+// special JSON encoding.
+var gatewayWellKnown = map[protoreflect.FullName]map[string]interface{}{
+	"google.protobuf.Timestamp": {"type": "string", "format": "date-time"},
+	"google.protobuf.Duration":  {"type": "string"},
+	"google.protobuf.Empty":     {"type": "object"},
+	"google.protobuf.Any":       {"type": "object"},
+	"google.protobuf.Struct":    {"type": "object"},
+	"google.protobuf.Value":     {},
+}
+
+// gatewaySpec returns the OpenAPI 2.0 specification of the gateway to the
+// service as JSON.
+func gatewaySpec(sd protoreflect.ServiceDescriptor, info *pb.VersionInfo) ([]byte, error) {
+	defs := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code":    map[string]interface{}{"type": "string"},
+				"message": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+
+	errorResponse := map[string]interface{}{
+		"description": "An error response.",
+		"schema":      map[string]interface{}{"$ref": "#/definitions/Error"},
+	}
+
+	paths := map[string]interface{}{}
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		if md.IsStreamingClient() {
+			continue
+		}
+
+		response := map[string]interface{}{
+			"description": "A successful response.",
+			"schema":      gatewayMessageSchema(md.Output(), defs),
+		}
+		op := map[string]interface{}{
+			"operationId": string(md.Name()),
+			"tags":        []string{string(sd.Name())},
+			"parameters": []interface{}{
+				map[string]interface{}{
+					"name":     "body",
+					"in":       "body",
+					"required": true,
+					"schema":   gatewayMessageSchema(md.Input(), defs),
+				},
+			},
+			"responses": map[string]interface{}{
+				"200":     response,
+				"default": errorResponse,
+			},
+		}
+		if md.IsStreamingServer() {
+			response["description"] = "A stream of responses, one JSON object per line."
+			op["produces"] = []string{"application/x-ndjson"}
+		}
+
+		paths["/"+string(md.Name())] = map[string]interface{}{"post": op}
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"swagger": "2.0",
+		"info": map[string]interface{}{
+			"title":   "Waypoint",
+			"version": info.GetVersion(),
+		},
+		"basePath": strings.TrimSuffix(GatewayPath, "/"),
+		"consumes": []string{"application/json"},
+		"produces": []string{"application/json"},
+		"securityDefinitions": map[string]interface{}{
+			"token": map[string]interface{}{
+				"type": "apiKey",
+				"name": "Authorization",
+				"in":   "header",
+			},
+		},
+		"security":    []interface{}{map[string][]string{"token": {}}},
+		"paths":       paths,
+		"definitions": defs,
+	}, "", "  ")
+}
+
+// gatewayMessageSchema returns the schema for the message, adding the
+// definitions of it and the messages it references to defs.
+func gatewayMessageSchema(desc protoreflect.MessageDescriptor, defs map[string]interface{}) map[string]interface{} {
+	if schema, ok := gatewayWellKnown[desc.FullName()]; ok {
+		return schema
+	}
+
+	name := string(desc.FullName())
+	ref := map[string]interface{}{"$ref": "#/definitions/" + name}
+	if _, ok := defs[name]; ok {
+		return ref
+	}
+
+	// Add the definition before its fields since messages can be recursive.
+	props := map[string]interface{}{}
+	defs[name] = map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+	}
+
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		props[fd.JSONName()] = gatewayFieldSchema(fd, defs)
+	}
+
+	return ref
+}
+
+// gatewayFieldSchema returns the schema of the field in its JSON encoding.
+func gatewayFieldSchema(fd protoreflect.FieldDescriptor, defs map[string]interface{}) map[string]interface{} {
+	if fd.IsMap() {
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": gatewayValueSchema(fd.MapValue(), defs),
+		}
+	}
+
+	if fd.IsList() {
+		return map[string]interface{}{
+			"type":  "array",
+			"items": gatewayValueSchema(fd, defs),
+		}
+	}
+
+	return gatewayValueSchema(fd, defs)
+}
+
+// gatewayValueSchema returns the schema of a single value of the field.
+func gatewayValueSchema(fd protoreflect.FieldDescriptor, defs map[string]interface{}) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+
+	// 64-bit integers are encoded as strings in JSON.
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "int64"}
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "uint64"}
+
+	case protoreflect.FloatKind:
+		return map[string]interface{}{"type": "number", "format": "float"}
+
+	case protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number", "format": "double"}
+
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+
+	case protoreflect.EnumKind:
+		var names []string
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+
+		return map[string]interface{}{"type": "string", "enum": names}
+
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return gatewayMessageSchema(fd.Message(), defs)
+
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
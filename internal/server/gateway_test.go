@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	pbmocks "github.com/hashicorp/waypoint/internal/server/gen/mocks"
+)
+
+func TestGateway(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := &pbmocks.WaypointServer{}
+	m.On("GetVersionInfo", mock.Anything, mock.Anything).Return(testVersionInfoResponse(), nil)
+	m.On("GetWorkspace", mock.Anything, mock.MatchedBy(func(req *pb.GetWorkspaceRequest) bool {
+		return req.Workspace.GetWorkspace() == "dev"
+	})).Return(&pb.GetWorkspaceResponse{
+		Workspace: &pb.Workspace{Name: "dev"},
+	}, nil)
+	m.On("GetProject", mock.Anything, mock.Anything).Return(
+		nil, status.Errorf(codes.NotFound, "project not found"))
+
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(err)
+	defer grpcLn.Close()
+	httpLn, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(err)
+	defer httpLn.Close()
+
+	go Run(
+		WithContext(ctx),
+		WithGRPC(grpcLn),
+		WithHTTP(httpLn),
+		WithImpl(m),
+	)
+	addr := "http://" + httpLn.Addr().String()
+
+	// Calls are translated to gRPC
+	resp, err := http.Post(addr+"/v1/GetWorkspace", "application/json",
+		strings.NewReader(`{"workspace": {"workspace": "dev"}}`))
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(err)
+	var ws pb.GetWorkspaceResponse
+	require.NoError(protojson.Unmarshal(body, &ws))
+	require.Equal("dev", ws.Workspace.Name)
+
+	// Errors have the matching status
+	resp, err = http.Post(addr+"/v1/GetProject", "application/json", nil)
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusNotFound, resp.StatusCode)
+
+	var apiErr struct{ Code, Message string }
+	require.NoError(json.NewDecoder(resp.Body).Decode(&apiErr))
+	require.Equal("NotFound", apiErr.Code)
+	require.Equal("project not found", apiErr.Message)
+
+	// Methods that read can be called with GET, others only with POST
+	resp, err = http.Get(addr + "/v1/GetProject")
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(addr + "/v1/UpsertProject")
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+	require.Equal("POST", resp.Header.Get("Allow"))
+
+	// Unknown methods
+	resp, err = http.Post(addr+"/v1/Nope", "application/json", nil)
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusNotFound, resp.StatusCode)
+
+	// The specification lists the methods
+	resp, err = http.Get(addr + "/v1/openapi.json")
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	var spec struct {
+		Paths       map[string]interface{}
+		Definitions map[string]interface{}
+	}
+	require.NoError(json.NewDecoder(resp.Body).Decode(&spec))
+	require.Contains(spec.Paths, "/GetWorkspace")
+	require.NotContains(spec.Paths, "/RunnerJobStream")
+	require.Contains(spec.Definitions, "hashicorp.waypoint.Workspace")
+}
+
+func TestClientAddr(t *testing.T) {
+	require := require.New(t)
+
+	tcpAddr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}
+	md := metadata.Pairs(gatewayClientAddrKey, "10.0.0.2:5678")
+
+	// No peer
+	require.Equal("", ClientAddr(context.Background()))
+
+	// Regular calls use the address of the peer, even with the metadata
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tcpAddr})
+	require.Equal("10.0.0.1:1234", ClientAddr(ctx))
+	require.Equal("10.0.0.1:1234", ClientAddr(metadata.NewIncomingContext(ctx, md)))
+
+	// Gateway calls use the address of the HTTP client
+	ctx = peer.NewContext(context.Background(), &peer.Peer{Addr: gatewayAddr{}})
+	require.Equal("", ClientAddr(ctx))
+	require.Equal("10.0.0.2:5678", ClientAddr(metadata.NewIncomingContext(ctx, md)))
+}
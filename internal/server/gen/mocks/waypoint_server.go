@@ -16,6 +16,29 @@ type WaypointServer struct {
 	mock.Mock
 }
 
+// AdoptRunner provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) AdoptRunner(_a0 context.Context, _a1 *gen.AdoptRunnerRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.AdoptRunnerRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.AdoptRunnerRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BootstrapToken provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) BootstrapToken(_a0 context.Context, _a1 *emptypb.Empty) (*gen.NewTokenResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -62,6 +85,29 @@ func (_m *WaypointServer) CancelJob(_a0 context.Context, _a1 *gen.CancelJobReque
 	return r0, r1
 }
 
+// CompleteOIDCAuth provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) CompleteOIDCAuth(_a0 context.Context, _a1 *gen.CompleteOIDCAuthRequest) (*gen.CompleteOIDCAuthResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.CompleteOIDCAuthResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.CompleteOIDCAuthRequest) *gen.CompleteOIDCAuthResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.CompleteOIDCAuthResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.CompleteOIDCAuthRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ConvertInviteToken provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) ConvertInviteToken(_a0 context.Context, _a1 *gen.ConvertInviteTokenRequest) (*gen.NewTokenResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -108,6 +154,20 @@ func (_m *WaypointServer) CreateHostname(_a0 context.Context, _a1 *gen.CreateHos
 	return r0, r1
 }
 
+// CreateSnapshot provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) CreateSnapshot(_a0 *emptypb.Empty, _a1 gen.Waypoint_CreateSnapshotServer) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*emptypb.Empty, gen.Waypoint_CreateSnapshotServer) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteHostname provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) DeleteHostname(_a0 context.Context, _a1 *gen.DeleteHostnameRequest) (*emptypb.Empty, error) {
 	ret := _m.Called(_a0, _a1)
@@ -131,6 +191,121 @@ func (_m *WaypointServer) DeleteHostname(_a0 context.Context, _a1 *gen.DeleteHos
 	return r0, r1
 }
 
+// DeleteNotificationSink provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) DeleteNotificationSink(_a0 context.Context, _a1 *gen.DeleteNotificationSinkRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DeleteNotificationSinkRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DeleteNotificationSinkRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteOrganization provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) DeleteOrganization(_a0 context.Context, _a1 *gen.DeleteOrganizationRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DeleteOrganizationRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DeleteOrganizationRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTrigger provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) DeleteTrigger(_a0 context.Context, _a1 *gen.DeleteTriggerRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DeleteTriggerRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DeleteTriggerRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWebhook provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) DeleteWebhook(_a0 context.Context, _a1 *gen.DeleteWebhookRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DeleteWebhookRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DeleteWebhookRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiffDeployments provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) DiffDeployments(_a0 context.Context, _a1 *gen.DiffDeploymentsRequest) (*gen.DiffDeploymentsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.DiffDeploymentsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.DiffDeploymentsRequest) *gen.DiffDeploymentsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.DiffDeploymentsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.DiffDeploymentsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EntrypointConfig provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) EntrypointConfig(_a0 *gen.EntrypointConfigRequest, _a1 gen.Waypoint_EntrypointConfigServer) error {
 	ret := _m.Called(_a0, _a1)
@@ -159,6 +334,29 @@ func (_m *WaypointServer) EntrypointExecStream(_a0 gen.Waypoint_EntrypointExecSt
 	return r0
 }
 
+// EntrypointHealth provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) EntrypointHealth(_a0 context.Context, _a1 *gen.EntrypointHealthRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.EntrypointHealthRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.EntrypointHealthRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EntrypointLogStream provides a mock function with given fields: _a0
 func (_m *WaypointServer) EntrypointLogStream(_a0 gen.Waypoint_EntrypointLogStreamServer) error {
 	ret := _m.Called(_a0)
@@ -173,6 +371,52 @@ func (_m *WaypointServer) EntrypointLogStream(_a0 gen.Waypoint_EntrypointLogStre
 	return r0
 }
 
+// EntrypointMetrics provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) EntrypointMetrics(_a0 context.Context, _a1 *gen.EntrypointMetricsRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.EntrypointMetricsRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.EntrypointMetricsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForgetRunner provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ForgetRunner(_a0 context.Context, _a1 *gen.ForgetRunnerRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ForgetRunnerRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ForgetRunnerRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GenerateInviteToken provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) GenerateInviteToken(_a0 context.Context, _a1 *gen.InviteTokenRequest) (*gen.NewTokenResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -197,11 +441,11 @@ func (_m *WaypointServer) GenerateInviteToken(_a0 context.Context, _a1 *gen.Invi
 }
 
 // GenerateLoginToken provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) GenerateLoginToken(_a0 context.Context, _a1 *emptypb.Empty) (*gen.NewTokenResponse, error) {
+func (_m *WaypointServer) GenerateLoginToken(_a0 context.Context, _a1 *gen.LoginTokenRequest) (*gen.NewTokenResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 *gen.NewTokenResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.NewTokenResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.LoginTokenRequest) *gen.NewTokenResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
@@ -210,7 +454,7 @@ func (_m *WaypointServer) GenerateLoginToken(_a0 context.Context, _a1 *emptypb.E
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.LoginTokenRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -265,6 +509,29 @@ func (_m *WaypointServer) GetConfig(_a0 context.Context, _a1 *gen.ConfigGetReque
 	return r0, r1
 }
 
+// GetConfigSource provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetConfigSource(_a0 context.Context, _a1 *gen.GetConfigSourceRequest) (*gen.GetConfigSourceResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.GetConfigSourceResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetConfigSourceRequest) *gen.GetConfigSourceResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetConfigSourceResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetConfigSourceRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDeployment provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) GetDeployment(_a0 context.Context, _a1 *gen.GetDeploymentRequest) (*gen.Deployment, error) {
 	ret := _m.Called(_a0, _a1)
@@ -288,6 +555,29 @@ func (_m *WaypointServer) GetDeployment(_a0 context.Context, _a1 *gen.GetDeploym
 	return r0, r1
 }
 
+// GetExecRecording provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetExecRecording(_a0 context.Context, _a1 *gen.GetExecRecordingRequest) (*gen.ExecRecording, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ExecRecording
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetExecRecordingRequest) *gen.ExecRecording); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ExecRecording)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetExecRecordingRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetJob provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) GetJob(_a0 context.Context, _a1 *gen.GetJobRequest) (*gen.Job, error) {
 	ret := _m.Called(_a0, _a1)
@@ -408,21 +698,21 @@ func (_m *WaypointServer) GetLogStream(_a0 *gen.GetLogStreamRequest, _a1 gen.Way
 	return r0
 }
 
-// GetProject provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) GetProject(_a0 context.Context, _a1 *gen.GetProjectRequest) (*gen.GetProjectResponse, error) {
+// GetLogs provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetLogs(_a0 context.Context, _a1 *gen.GetLogsRequest) (*gen.GetLogsResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.GetProjectResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetProjectRequest) *gen.GetProjectResponse); ok {
+	var r0 *gen.GetLogsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetLogsRequest) *gen.GetLogsResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.GetProjectResponse)
+			r0 = ret.Get(0).(*gen.GetLogsResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetProjectRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetLogsRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -431,21 +721,21 @@ func (_m *WaypointServer) GetProject(_a0 context.Context, _a1 *gen.GetProjectReq
 	return r0, r1
 }
 
-// GetPushedArtifact provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) GetPushedArtifact(_a0 context.Context, _a1 *gen.GetPushedArtifactRequest) (*gen.PushedArtifact, error) {
+// GetOIDCAuthURL provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetOIDCAuthURL(_a0 context.Context, _a1 *gen.GetOIDCAuthURLRequest) (*gen.GetOIDCAuthURLResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.PushedArtifact
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetPushedArtifactRequest) *gen.PushedArtifact); ok {
+	var r0 *gen.GetOIDCAuthURLResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetOIDCAuthURLRequest) *gen.GetOIDCAuthURLResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.PushedArtifact)
+			r0 = ret.Get(0).(*gen.GetOIDCAuthURLResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetPushedArtifactRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetOIDCAuthURLRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -454,21 +744,343 @@ func (_m *WaypointServer) GetPushedArtifact(_a0 context.Context, _a1 *gen.GetPus
 	return r0, r1
 }
 
-// GetRelease provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) GetRelease(_a0 context.Context, _a1 *gen.GetReleaseRequest) (*gen.Release, error) {
+// GetProject provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetProject(_a0 context.Context, _a1 *gen.GetProjectRequest) (*gen.GetProjectResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.Release
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetReleaseRequest) *gen.Release); ok {
+	var r0 *gen.GetProjectResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetProjectRequest) *gen.GetProjectResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetProjectResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetProjectRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPushedArtifact provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetPushedArtifact(_a0 context.Context, _a1 *gen.GetPushedArtifactRequest) (*gen.PushedArtifact, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.PushedArtifact
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetPushedArtifactRequest) *gen.PushedArtifact); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.PushedArtifact)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetPushedArtifactRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRelease provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetRelease(_a0 context.Context, _a1 *gen.GetReleaseRequest) (*gen.Release, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.Release
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetReleaseRequest) *gen.Release); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.Release)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetReleaseRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRunner provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetRunner(_a0 context.Context, _a1 *gen.GetRunnerRequest) (*gen.Runner, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.Runner
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetRunnerRequest) *gen.Runner); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.Runner)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetRunnerRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServerConfig provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetServerConfig(_a0 context.Context, _a1 *emptypb.Empty) (*gen.GetServerConfigResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.GetServerConfigResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.GetServerConfigResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetServerConfigResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVersionInfo provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetVersionInfo(_a0 context.Context, _a1 *emptypb.Empty) (*gen.GetVersionInfoResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.GetVersionInfoResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.GetVersionInfoResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetVersionInfoResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWorkspace provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) GetWorkspace(_a0 context.Context, _a1 *gen.GetWorkspaceRequest) (*gen.GetWorkspaceResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.GetWorkspaceResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetWorkspaceRequest) *gen.GetWorkspaceResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.GetWorkspaceResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetWorkspaceRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuditEvents provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListAuditEvents(_a0 context.Context, _a1 *gen.ListAuditEventsRequest) (*gen.ListAuditEventsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListAuditEventsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListAuditEventsRequest) *gen.ListAuditEventsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListAuditEventsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListAuditEventsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListBuilds provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListBuilds(_a0 context.Context, _a1 *gen.ListBuildsRequest) (*gen.ListBuildsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListBuildsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListBuildsRequest) *gen.ListBuildsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListBuildsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListBuildsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeployments provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListDeployments(_a0 context.Context, _a1 *gen.ListDeploymentsRequest) (*gen.ListDeploymentsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListDeploymentsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListDeploymentsRequest) *gen.ListDeploymentsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListDeploymentsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListDeploymentsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListExecRecordings provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListExecRecordings(_a0 context.Context, _a1 *gen.ListExecRecordingsRequest) (*gen.ListExecRecordingsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListExecRecordingsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListExecRecordingsRequest) *gen.ListExecRecordingsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListExecRecordingsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListExecRecordingsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListHostnames provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListHostnames(_a0 context.Context, _a1 *gen.ListHostnamesRequest) (*gen.ListHostnamesResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListHostnamesResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListHostnamesRequest) *gen.ListHostnamesResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListHostnamesResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListHostnamesRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListInstances provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListInstances(_a0 context.Context, _a1 *gen.ListInstancesRequest) (*gen.ListInstancesResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListInstancesResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListInstancesRequest) *gen.ListInstancesResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListInstancesResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListInstancesRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListNotificationSinks provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListNotificationSinks(_a0 context.Context, _a1 *gen.ListNotificationSinksRequest) (*gen.ListNotificationSinksResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListNotificationSinksResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListNotificationSinksRequest) *gen.ListNotificationSinksResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.ListNotificationSinksResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListNotificationSinksRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrganizations provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListOrganizations(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListOrganizationsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.ListOrganizationsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.ListOrganizationsResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.Release)
+			r0 = ret.Get(0).(*gen.ListOrganizationsResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetReleaseRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -477,21 +1089,21 @@ func (_m *WaypointServer) GetRelease(_a0 context.Context, _a1 *gen.GetReleaseReq
 	return r0, r1
 }
 
-// GetRunner provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) GetRunner(_a0 context.Context, _a1 *gen.GetRunnerRequest) (*gen.Runner, error) {
+// ListProjects provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListProjects(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListProjectsResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.Runner
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetRunnerRequest) *gen.Runner); ok {
+	var r0 *gen.ListProjectsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.ListProjectsResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.Runner)
+			r0 = ret.Get(0).(*gen.ListProjectsResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetRunnerRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -500,21 +1112,21 @@ func (_m *WaypointServer) GetRunner(_a0 context.Context, _a1 *gen.GetRunnerReque
 	return r0, r1
 }
 
-// GetServerConfig provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) GetServerConfig(_a0 context.Context, _a1 *emptypb.Empty) (*gen.GetServerConfigResponse, error) {
+// ListPushedArtifacts provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListPushedArtifacts(_a0 context.Context, _a1 *gen.ListPushedArtifactsRequest) (*gen.ListPushedArtifactsResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.GetServerConfigResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.GetServerConfigResponse); ok {
+	var r0 *gen.ListPushedArtifactsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListPushedArtifactsRequest) *gen.ListPushedArtifactsResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.GetServerConfigResponse)
+			r0 = ret.Get(0).(*gen.ListPushedArtifactsResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListPushedArtifactsRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -523,21 +1135,21 @@ func (_m *WaypointServer) GetServerConfig(_a0 context.Context, _a1 *emptypb.Empt
 	return r0, r1
 }
 
-// GetVersionInfo provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) GetVersionInfo(_a0 context.Context, _a1 *emptypb.Empty) (*gen.GetVersionInfoResponse, error) {
+// ListReleases provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListReleases(_a0 context.Context, _a1 *gen.ListReleasesRequest) (*gen.ListReleasesResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.GetVersionInfoResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.GetVersionInfoResponse); ok {
+	var r0 *gen.ListReleasesResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListReleasesRequest) *gen.ListReleasesResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.GetVersionInfoResponse)
+			r0 = ret.Get(0).(*gen.ListReleasesResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListReleasesRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -546,21 +1158,21 @@ func (_m *WaypointServer) GetVersionInfo(_a0 context.Context, _a1 *emptypb.Empty
 	return r0, r1
 }
 
-// GetWorkspace provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) GetWorkspace(_a0 context.Context, _a1 *gen.GetWorkspaceRequest) (*gen.GetWorkspaceResponse, error) {
+// ListRunners provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListRunners(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListRunnersResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.GetWorkspaceResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.GetWorkspaceRequest) *gen.GetWorkspaceResponse); ok {
+	var r0 *gen.ListRunnersResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.ListRunnersResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.GetWorkspaceResponse)
+			r0 = ret.Get(0).(*gen.ListRunnersResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.GetWorkspaceRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -569,21 +1181,21 @@ func (_m *WaypointServer) GetWorkspace(_a0 context.Context, _a1 *gen.GetWorkspac
 	return r0, r1
 }
 
-// ListBuilds provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) ListBuilds(_a0 context.Context, _a1 *gen.ListBuildsRequest) (*gen.ListBuildsResponse, error) {
+// ListTokens provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListTokens(_a0 context.Context, _a1 *gen.ListTokensRequest) (*gen.ListTokensResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.ListBuildsResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListBuildsRequest) *gen.ListBuildsResponse); ok {
+	var r0 *gen.ListTokensResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListTokensRequest) *gen.ListTokensResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListBuildsResponse)
+			r0 = ret.Get(0).(*gen.ListTokensResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListBuildsRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListTokensRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -592,21 +1204,21 @@ func (_m *WaypointServer) ListBuilds(_a0 context.Context, _a1 *gen.ListBuildsReq
 	return r0, r1
 }
 
-// ListDeployments provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) ListDeployments(_a0 context.Context, _a1 *gen.ListDeploymentsRequest) (*gen.ListDeploymentsResponse, error) {
+// ListTriggers provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListTriggers(_a0 context.Context, _a1 *gen.ListTriggersRequest) (*gen.ListTriggersResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.ListDeploymentsResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListDeploymentsRequest) *gen.ListDeploymentsResponse); ok {
+	var r0 *gen.ListTriggersResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListTriggersRequest) *gen.ListTriggersResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListDeploymentsResponse)
+			r0 = ret.Get(0).(*gen.ListTriggersResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListDeploymentsRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListTriggersRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -615,21 +1227,21 @@ func (_m *WaypointServer) ListDeployments(_a0 context.Context, _a1 *gen.ListDepl
 	return r0, r1
 }
 
-// ListHostnames provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) ListHostnames(_a0 context.Context, _a1 *gen.ListHostnamesRequest) (*gen.ListHostnamesResponse, error) {
+// ListUsers provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListUsers(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListUsersResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.ListHostnamesResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListHostnamesRequest) *gen.ListHostnamesResponse); ok {
+	var r0 *gen.ListUsersResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.ListUsersResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListHostnamesResponse)
+			r0 = ret.Get(0).(*gen.ListUsersResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListHostnamesRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -638,21 +1250,21 @@ func (_m *WaypointServer) ListHostnames(_a0 context.Context, _a1 *gen.ListHostna
 	return r0, r1
 }
 
-// ListInstances provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) ListInstances(_a0 context.Context, _a1 *gen.ListInstancesRequest) (*gen.ListInstancesResponse, error) {
+// ListWebhooks provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListWebhooks(_a0 context.Context, _a1 *gen.ListWebhooksRequest) (*gen.ListWebhooksResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.ListInstancesResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListInstancesRequest) *gen.ListInstancesResponse); ok {
+	var r0 *gen.ListWebhooksResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListWebhooksRequest) *gen.ListWebhooksResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListInstancesResponse)
+			r0 = ret.Get(0).(*gen.ListWebhooksResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListInstancesRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListWebhooksRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -661,16 +1273,16 @@ func (_m *WaypointServer) ListInstances(_a0 context.Context, _a1 *gen.ListInstan
 	return r0, r1
 }
 
-// ListProjects provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) ListProjects(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListProjectsResponse, error) {
+// ListWorkspaces provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) ListWorkspaces(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListWorkspacesResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.ListProjectsResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.ListProjectsResponse); ok {
+	var r0 *gen.ListWorkspacesResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.ListWorkspacesResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListProjectsResponse)
+			r0 = ret.Get(0).(*gen.ListWorkspacesResponse)
 		}
 	}
 
@@ -684,21 +1296,21 @@ func (_m *WaypointServer) ListProjects(_a0 context.Context, _a1 *emptypb.Empty)
 	return r0, r1
 }
 
-// ListPushedArtifacts provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) ListPushedArtifacts(_a0 context.Context, _a1 *gen.ListPushedArtifactsRequest) (*gen.ListPushedArtifactsResponse, error) {
+// PruneOperations provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) PruneOperations(_a0 context.Context, _a1 *gen.PruneOperationsRequest) (*gen.PruneOperationsResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.ListPushedArtifactsResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListPushedArtifactsRequest) *gen.ListPushedArtifactsResponse); ok {
+	var r0 *gen.PruneOperationsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.PruneOperationsRequest) *gen.PruneOperationsResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListPushedArtifactsResponse)
+			r0 = ret.Get(0).(*gen.PruneOperationsResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListPushedArtifactsRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.PruneOperationsRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -707,21 +1319,21 @@ func (_m *WaypointServer) ListPushedArtifacts(_a0 context.Context, _a1 *gen.List
 	return r0, r1
 }
 
-// ListReleases provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) ListReleases(_a0 context.Context, _a1 *gen.ListReleasesRequest) (*gen.ListReleasesResponse, error) {
+// QueueJob provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) QueueJob(_a0 context.Context, _a1 *gen.QueueJobRequest) (*gen.QueueJobResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.ListReleasesResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.ListReleasesRequest) *gen.ListReleasesResponse); ok {
+	var r0 *gen.QueueJobResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.QueueJobRequest) *gen.QueueJobResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListReleasesResponse)
+			r0 = ret.Get(0).(*gen.QueueJobResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.ListReleasesRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.QueueJobRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -730,21 +1342,35 @@ func (_m *WaypointServer) ListReleases(_a0 context.Context, _a1 *gen.ListRelease
 	return r0, r1
 }
 
-// ListWorkspaces provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) ListWorkspaces(_a0 context.Context, _a1 *emptypb.Empty) (*gen.ListWorkspacesResponse, error) {
+// RestoreSnapshot provides a mock function with given fields: _a0
+func (_m *WaypointServer) RestoreSnapshot(_a0 gen.Waypoint_RestoreSnapshotServer) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(gen.Waypoint_RestoreSnapshotServer) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeToken provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) RevokeToken(_a0 context.Context, _a1 *gen.RevokeTokenRequest) (*emptypb.Empty, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.ListWorkspacesResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *emptypb.Empty) *gen.ListWorkspacesResponse); ok {
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.RevokeTokenRequest) *emptypb.Empty); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.ListWorkspacesResponse)
+			r0 = ret.Get(0).(*emptypb.Empty)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *emptypb.Empty) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.RevokeTokenRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -753,21 +1379,21 @@ func (_m *WaypointServer) ListWorkspaces(_a0 context.Context, _a1 *emptypb.Empty
 	return r0, r1
 }
 
-// QueueJob provides a mock function with given fields: _a0, _a1
-func (_m *WaypointServer) QueueJob(_a0 context.Context, _a1 *gen.QueueJobRequest) (*gen.QueueJobResponse, error) {
+// RunMaintenance provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) RunMaintenance(_a0 context.Context, _a1 *gen.RunMaintenanceRequest) (*gen.RunMaintenanceResponse, error) {
 	ret := _m.Called(_a0, _a1)
 
-	var r0 *gen.QueueJobResponse
-	if rf, ok := ret.Get(0).(func(context.Context, *gen.QueueJobRequest) *gen.QueueJobResponse); ok {
+	var r0 *gen.RunMaintenanceResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.RunMaintenanceRequest) *gen.RunMaintenanceResponse); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*gen.QueueJobResponse)
+			r0 = ret.Get(0).(*gen.RunMaintenanceResponse)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, *gen.QueueJobRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.RunMaintenanceRequest) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -827,6 +1453,29 @@ func (_m *WaypointServer) RunnerJobStream(_a0 gen.Waypoint_RunnerJobStreamServer
 	return r0
 }
 
+// SearchOperations provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) SearchOperations(_a0 context.Context, _a1 *gen.SearchOperationsRequest) (*gen.SearchOperationsResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.SearchOperationsResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.SearchOperationsRequest) *gen.SearchOperationsResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.SearchOperationsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.SearchOperationsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetConfig provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) SetConfig(_a0 context.Context, _a1 *gen.ConfigSetRequest) (*gen.ConfigSetResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -850,6 +1499,29 @@ func (_m *WaypointServer) SetConfig(_a0 context.Context, _a1 *gen.ConfigSetReque
 	return r0, r1
 }
 
+// SetConfigSource provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) SetConfigSource(_a0 context.Context, _a1 *gen.SetConfigSourceRequest) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.SetConfigSourceRequest) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.SetConfigSourceRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetServerConfig provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) SetServerConfig(_a0 context.Context, _a1 *gen.SetServerConfigRequest) (*emptypb.Empty, error) {
 	ret := _m.Called(_a0, _a1)
@@ -873,6 +1545,29 @@ func (_m *WaypointServer) SetServerConfig(_a0 context.Context, _a1 *gen.SetServe
 	return r0, r1
 }
 
+// SetUserPermissions provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) SetUserPermissions(_a0 context.Context, _a1 *gen.SetUserPermissionsRequest) (*gen.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.User
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.SetUserPermissionsRequest) *gen.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.SetUserPermissionsRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StartExecStream provides a mock function with given fields: _a0
 func (_m *WaypointServer) StartExecStream(_a0 gen.Waypoint_StartExecStreamServer) error {
 	ret := _m.Called(_a0)
@@ -887,6 +1582,20 @@ func (_m *WaypointServer) StartExecStream(_a0 gen.Waypoint_StartExecStreamServer
 	return r0
 }
 
+// StreamEvents provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) StreamEvents(_a0 *gen.StreamEventsRequest, _a1 gen.Waypoint_StreamEventsServer) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gen.StreamEventsRequest, gen.Waypoint_StreamEventsServer) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpsertApplication provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) UpsertApplication(_a0 context.Context, _a1 *gen.UpsertApplicationRequest) (*gen.UpsertApplicationResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -956,6 +1665,52 @@ func (_m *WaypointServer) UpsertDeployment(_a0 context.Context, _a1 *gen.UpsertD
 	return r0, r1
 }
 
+// UpsertNotificationSink provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) UpsertNotificationSink(_a0 context.Context, _a1 *gen.UpsertNotificationSinkRequest) (*gen.UpsertNotificationSinkResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.UpsertNotificationSinkResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.UpsertNotificationSinkRequest) *gen.UpsertNotificationSinkResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.UpsertNotificationSinkResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.UpsertNotificationSinkRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertOrganization provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) UpsertOrganization(_a0 context.Context, _a1 *gen.UpsertOrganizationRequest) (*gen.UpsertOrganizationResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.UpsertOrganizationResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.UpsertOrganizationRequest) *gen.UpsertOrganizationResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.UpsertOrganizationResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.UpsertOrganizationRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpsertProject provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) UpsertProject(_a0 context.Context, _a1 *gen.UpsertProjectRequest) (*gen.UpsertProjectResponse, error) {
 	ret := _m.Called(_a0, _a1)
@@ -1025,6 +1780,52 @@ func (_m *WaypointServer) UpsertRelease(_a0 context.Context, _a1 *gen.UpsertRele
 	return r0, r1
 }
 
+// UpsertTrigger provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) UpsertTrigger(_a0 context.Context, _a1 *gen.UpsertTriggerRequest) (*gen.UpsertTriggerResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.UpsertTriggerResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.UpsertTriggerRequest) *gen.UpsertTriggerResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.UpsertTriggerResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.UpsertTriggerRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertWebhook provides a mock function with given fields: _a0, _a1
+func (_m *WaypointServer) UpsertWebhook(_a0 context.Context, _a1 *gen.UpsertWebhookRequest) (*gen.UpsertWebhookResponse, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *gen.UpsertWebhookResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *gen.UpsertWebhookRequest) *gen.UpsertWebhookResponse); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gen.UpsertWebhookResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *gen.UpsertWebhookRequest) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateJob provides a mock function with given fields: _a0, _a1
 func (_m *WaypointServer) ValidateJob(_a0 context.Context, _a1 *gen.ValidateJobRequest) (*gen.ValidateJobResponse, error) {
 	ret := _m.Called(_a0, _a1)
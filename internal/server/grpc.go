@@ -39,6 +39,15 @@ func grpcInit(group *run.Group, opts *options) error {
 		),
 	)
 
+	// Limit calls by client address before authentication so that calls
+	// that fail authentication are limited too.
+	if l, ok := opts.Service.(RateLimiter); ok {
+		so = append(so,
+			grpc.ChainUnaryInterceptor(rateLimitAddrUnaryInterceptor(l)),
+			grpc.ChainStreamInterceptor(rateLimitAddrStreamInterceptor(l)),
+		)
+	}
+
 	if opts.AuthChecker != nil {
 		so = append(so,
 			grpc.ChainUnaryInterceptor(authUnaryInterceptor(opts.AuthChecker)),
@@ -46,6 +55,28 @@ func grpcInit(group *run.Group, opts *options) error {
 		)
 	}
 
+	// Require client certificates for runners and entrypoints if configured.
+	if opts.ClientCAs != nil {
+		so = append(so,
+			grpc.Creds(tlsConnCredentials{}),
+			grpc.ChainUnaryInterceptor(clientCertUnaryInterceptor(opts.ClientCAs)),
+			grpc.ChainStreamInterceptor(clientCertStreamInterceptor(opts.ClientCAs)),
+		)
+	}
+
+	// Limit calls after authentication so that limits can be per token.
+	if l, ok := opts.Service.(RateLimiter); ok {
+		so = append(so,
+			grpc.ChainUnaryInterceptor(rateLimitUnaryInterceptor(l)),
+			grpc.ChainStreamInterceptor(rateLimitStreamInterceptor(l)),
+		)
+	}
+
+	// Record calls after authentication so that the caller is known.
+	if a, ok := opts.Service.(Auditor); ok {
+		so = append(so, grpc.ChainUnaryInterceptor(auditUnaryInterceptor(a)))
+	}
+
 	s := grpc.NewServer(so...)
 	opts.grpcServer = s
 
@@ -65,6 +96,10 @@ func grpcInit(group *run.Group, opts *options) error {
 		log.Info("starting gRPC server", "addr", ln.Addr().String())
 		return s.Serve(ln)
 	}, func(err error) {
+		// Let the service finish its work while runners and entrypoints
+		// are still connected.
+		drain(opts)
+
 		// Graceful in a goroutine so we can timeout
 		gracefulCh := make(chan struct{})
 		go func() {
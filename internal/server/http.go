@@ -8,11 +8,23 @@ import (
 	"time"
 
 	assetfs "github.com/elazarl/go-bindata-assetfs"
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"github.com/oklog/run"
 )
 
+// WebhookPath is the path prefix of webhooks on the HTTP server.
+const WebhookPath = "/webhook/"
+
+// WebhookHandler may be implemented by the service to receive webhooks
+// over HTTP. Requests with a path starting with WebhookPath are passed to
+// it with a request-scoped logger in their context. These requests aren't
+// authenticated with tokens so the handler must verify them itself.
+type WebhookHandler interface {
+	ServeWebhook(w http.ResponseWriter, r *http.Request)
+}
+
 // httpInit initializes the HTTP server and adds it to the run group.
 func httpInit(group *run.Group, opts *options) error {
 	log := opts.Logger.Named("http")
@@ -36,11 +48,26 @@ func httpInit(group *run.Group, opts *options) error {
 		Fallback:  "index.html",
 	})
 
-	// If the path has a grpc prefix we assume it's a GRPC gateway request,
-	// otherwise fall back to serving the UI from the filesystem
+	webhooks, _ := opts.Service.(WebhookHandler)
+	health := healthHandler(opts, log.Named("health"))
+
+	gw, err := gatewayInit(group, opts, log.Named("gateway"))
+	if err != nil {
+		return err
+	}
+
+	// If the path has a grpc prefix we assume it's a gRPC-web request and
+	// GatewayPath is the JSON gateway, otherwise fall back to serving the UI
+	// from the filesystem
 	rootHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if strings.HasPrefix(r.URL.Path, "/grpc") {
 			grpcWrapped.ServeHTTP(w, r)
+		} else if strings.HasPrefix(r.URL.Path, GatewayPath) {
+			gw.ServeHTTP(w, r)
+		} else if r.URL.Path == HealthPath || r.URL.Path == ReadyPath {
+			health.ServeHTTP(w, r)
+		} else if webhooks != nil && strings.HasPrefix(r.URL.Path, WebhookPath) {
+			webhooks.ServeWebhook(w, r.WithContext(hclog.WithContext(r.Context(), log)))
 		} else if opts.BrowserUIEnabled {
 			uifs.ServeHTTP(w, r)
 		}
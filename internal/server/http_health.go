@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// HealthPath is the path of the liveness endpoint on the HTTP server.
+	// It fails if the server can't serve requests at all, such as when the
+	// database is inaccessible, and restarting it may help.
+	HealthPath = "/healthz"
+
+	// ReadyPath is the path of the readiness endpoint on the HTTP server.
+	// It fails if any check fails or the server is shutting down, so that
+	// load balancers stop sending it traffic.
+	ReadyPath = "/readyz"
+)
+
+// healthTimeout is how long the checks of a health request can take.
+var healthTimeout = 5 * time.Second
+
+// HealthChecker may be implemented by the service to report the health of
+// the dependencies it needs, such as its database, on the HealthPath and
+// ReadyPath endpoints.
+type HealthChecker interface {
+	HealthChecks(ctx context.Context) []HealthCheck
+}
+
+// HealthCheck is the result of checking a dependency of the server.
+type HealthCheck struct {
+	// Name identifies the check in the response, such as "db".
+	Name string
+
+	// Err is set if the check failed.
+	Err error
+
+	// Live is true if the liveness endpoint fails when this check fails.
+	// Other checks only fail the readiness endpoint.
+	Live bool
+}
+
+// healthResponse is the JSON body of the health endpoints.
+type healthResponse struct {
+	Status string                         `json:"status"`
+	Checks map[string]healthCheckResponse `json:"checks"`
+}
+
+type healthCheckResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// healthHandler serves HealthPath and ReadyPath. Both respond with the
+// result of every check, and with status 503 if the endpoint fails.
+func healthHandler(opts *options, log hclog.Logger) http.Handler {
+	checker, _ := opts.Service.(HealthChecker)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), healthTimeout)
+		defer cancel()
+
+		var checks []HealthCheck
+		if checker != nil {
+			checks = checker.HealthChecks(ctx)
+		}
+		checks = append(checks, healthCheckListener(ctx, "grpc_listener", opts.GRPCListener))
+
+		// The server is no longer ready once it starts shutting down, but
+		// it is still live until it exits.
+		var shutdownErr error
+		if opts.Context != nil && opts.Context.Err() != nil {
+			shutdownErr = fmt.Errorf("server is shutting down")
+		}
+		checks = append(checks, HealthCheck{Name: "server", Err: shutdownErr})
+
+		ready := r.URL.Path == ReadyPath
+		resp := healthResponse{
+			Status: "ok",
+			Checks: map[string]healthCheckResponse{},
+		}
+		for _, check := range checks {
+			result := healthCheckResponse{Status: "ok"}
+			if check.Err != nil {
+				result.Status = "failed"
+				result.Error = check.Err.Error()
+				if ready || check.Live {
+					resp.Status = "unavailable"
+				}
+			}
+
+			resp.Checks[check.Name] = result
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if resp.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(&resp); err != nil {
+			log.Warn("error writing health response", "err", err)
+		}
+	})
+}
+
+// healthCheckListener checks that the listener accepts connections.
+func healthCheckListener(ctx context.Context, name string, ln net.Listener) HealthCheck {
+	check := HealthCheck{Name: name, Live: true}
+	if ln == nil {
+		check.Err = fmt.Errorf("listener is not set")
+		return check
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, ln.Addr().Network(), ln.Addr().String())
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	conn.Close()
+
+	return check
+}
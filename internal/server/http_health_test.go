@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	pbmocks "github.com/hashicorp/waypoint/internal/server/gen/mocks"
+)
+
+// testHealthService is a service with fixed health checks.
+type testHealthService struct {
+	*pbmocks.WaypointServer
+	checks []HealthCheck
+}
+
+func (s *testHealthService) HealthChecks(context.Context) []HealthCheck {
+	return s.checks
+}
+
+func TestHealthHandler(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(err)
+	defer grpcLn.Close()
+
+	impl := &testHealthService{WaypointServer: &pbmocks.WaypointServer{}}
+	opts := &options{
+		Context:      ctx,
+		Service:      impl,
+		GRPCListener: grpcLn,
+	}
+	h := healthHandler(opts, hclog.L())
+
+	// get returns the status code and body of a request to the path
+	get := func(path string) (int, *healthResponse) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+		var resp healthResponse
+		require.NoError(json.NewDecoder(w.Body).Decode(&resp))
+		return w.Code, &resp
+	}
+
+	// Everything is healthy
+	impl.checks = []HealthCheck{{Name: "db", Live: true}}
+	code, resp := get(HealthPath)
+	require.Equal(http.StatusOK, code)
+	require.Equal("ok", resp.Status)
+	require.Equal("ok", resp.Checks["db"].Status)
+	require.Equal("ok", resp.Checks["grpc_listener"].Status)
+	code, _ = get(ReadyPath)
+	require.Equal(http.StatusOK, code)
+
+	// Checks that aren't live only fail readiness
+	impl.checks = []HealthCheck{
+		{Name: "db", Live: true},
+		{Name: "url", Err: errors.New("unreachable")},
+	}
+	code, _ = get(HealthPath)
+	require.Equal(http.StatusOK, code)
+	code, resp = get(ReadyPath)
+	require.Equal(http.StatusServiceUnavailable, code)
+	require.Equal("unreachable", resp.Checks["url"].Error)
+
+	// Live checks fail both
+	impl.checks = []HealthCheck{{Name: "db", Err: errors.New("closed"), Live: true}}
+	code, _ = get(HealthPath)
+	require.Equal(http.StatusServiceUnavailable, code)
+
+	// Shutting down fails readiness
+	impl.checks = nil
+	cancel()
+	code, _ = get(HealthPath)
+	require.Equal(http.StatusOK, code)
+	code, resp = get(ReadyPath)
+	require.Equal(http.StatusServiceUnavailable, code)
+	require.Equal("failed", resp.Checks["server"].Status)
+}
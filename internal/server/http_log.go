@@ -34,7 +34,14 @@ func httpLogHandler(handler http.Handler, log hclog.Logger) http.Handler {
 			scheme = forwardedProto
 		}
 
-		log.Info(
+		// Probes of the health endpoints are frequent, so they are only
+		// logged in trace mode unless they fail.
+		logFunc := log.Info
+		if (req.URL.Path == HealthPath || req.URL.Path == ReadyPath) && params.StatusCode == http.StatusOK {
+			logFunc = log.Trace
+		}
+
+		logFunc(
 			fmt.Sprintf("HTTP request: %s %s", req.Method, req.URL.Path),
 			"date", params.TimeStamp.Format(time.RFC3339Nano),
 			"http.host", req.Host,
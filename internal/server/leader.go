@@ -0,0 +1,59 @@
+package server
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// notLeaderReason is the reason of the ErrorInfo detail of the errors
+	// from NotLeaderError.
+	notLeaderReason = "NOT_LEADER"
+
+	// notLeaderAddrKey is the key of the address of the leader in the
+	// metadata of the ErrorInfo detail.
+	notLeaderAddrKey = "leader_addr"
+)
+
+// NotLeaderError returns the error for calls that only the leader of the
+// servers sharing a database can serve, such as the job streams of
+// runners. If addr isn't empty, the error has it as the address of the
+// leader so that clients can reconnect to it.
+func NotLeaderError(addr string) error {
+	st := status.New(codes.Unavailable,
+		"this server is not the leader, please retry against the leader")
+	if addr == "" {
+		return st.Err()
+	}
+
+	withAddr, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   notLeaderReason,
+		Domain:   "waypoint",
+		Metadata: map[string]string{notLeaderAddrKey: addr},
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	return withAddr.Err()
+}
+
+// LeaderAddr returns the address of the leader from an error returned by
+// NotLeaderError. This returns an empty string if err isn't from
+// NotLeaderError or has no address.
+func LeaderAddr(err error) string {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Unavailable {
+		return ""
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if ok && info.Reason == notLeaderReason {
+			return info.Metadata[notLeaderAddrKey]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLeaderAddr(t *testing.T) {
+	require := require.New(t)
+
+	err := NotLeaderError("leader.example.com:9701")
+	require.Equal(codes.Unavailable, status.Code(err))
+	require.Equal("leader.example.com:9701", LeaderAddr(err))
+
+	// Without an address clients just retry
+	err = NotLeaderError("")
+	require.Equal(codes.Unavailable, status.Code(err))
+	require.Equal("", LeaderAddr(err))
+
+	// Other errors
+	require.Equal("", LeaderAddr(nil))
+	require.Equal("", LeaderAddr(errors.New("nope")))
+	require.Equal("", LeaderAddr(status.Errorf(codes.Unavailable, "shutting down")))
+}
@@ -1,6 +1,9 @@
 package ptypes
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/imdario/mergo"
 	"github.com/mitchellh/go-testing-interface"
@@ -31,5 +34,47 @@ func TestServerConfig(t testing.T, src *pb.ServerConfig) *pb.ServerConfig {
 func ValidateServerConfig(c *pb.ServerConfig) error {
 	return validation.ValidateStruct(c,
 		validation.Field(&c.AdvertiseAddrs, validation.Required, validation.Length(1, 1)),
+		validation.Field(&c.RunnerProfiles,
+			validation.By(uniqueRunnerProfiles),
+			validation.Each(validation.By(validateRunnerProfile)),
+		),
 	)
 }
+
+func validateRunnerProfile(v interface{}) error {
+	p, ok := v.(*pb.ServerConfig_RunnerProfile)
+	if !ok || p == nil {
+		return errors.New("must be set")
+	}
+
+	return validation.ValidateStruct(p,
+		validation.Field(&p.Name, validation.Required),
+		validation.Field(&p.TargetRunner, validation.Required, validation.By(isRunnerProfileTarget)),
+	)
+}
+
+// isRunnerProfileTarget checks that a runner profile targets runners by ID
+// or labels. Targeting any runner is what jobs do without a profile.
+func isRunnerProfileTarget(v interface{}) error {
+	ref, _ := v.(*pb.Ref_Runner)
+	switch ref.GetTarget().(type) {
+	case *pb.Ref_Runner_Id, *pb.Ref_Runner_Labels:
+		return nil
+
+	default:
+		return errors.New("must target runners by ID or labels")
+	}
+}
+
+func uniqueRunnerProfiles(v interface{}) error {
+	profiles, _ := v.([]*pb.ServerConfig_RunnerProfile)
+	seen := map[string]struct{}{}
+	for _, p := range profiles {
+		if _, ok := seen[p.GetName()]; ok {
+			return fmt.Errorf("profile name %q is used more than once", p.GetName())
+		}
+		seen[p.GetName()] = struct{}{}
+	}
+
+	return nil
+}
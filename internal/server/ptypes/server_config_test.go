@@ -33,6 +33,59 @@ func TestValidateServerConfig(t *testing.T) {
 			},
 			"advertise_addrs: the length must be exactly 1",
 		},
+
+		{
+			"runner profile",
+			func(c *pb.ServerConfig) {
+				c.RunnerProfiles = []*pb.ServerConfig_RunnerProfile{
+					testRunnerProfile("docker"),
+				}
+			},
+			"",
+		},
+
+		{
+			"runner profile without name",
+			func(c *pb.ServerConfig) {
+				c.RunnerProfiles = []*pb.ServerConfig_RunnerProfile{
+					testRunnerProfile(""),
+				}
+			},
+			"name: cannot be blank",
+		},
+
+		{
+			"runner profile targeting any runner",
+			func(c *pb.ServerConfig) {
+				p := testRunnerProfile("docker")
+				p.TargetRunner = &pb.Ref_Runner{
+					Target: &pb.Ref_Runner_Any{Any: &pb.Ref_RunnerAny{}},
+				}
+				c.RunnerProfiles = []*pb.ServerConfig_RunnerProfile{p}
+			},
+			"target_runner: must target runners by ID or labels",
+		},
+
+		{
+			"runner profile without target",
+			func(c *pb.ServerConfig) {
+				p := testRunnerProfile("docker")
+				p.TargetRunner = nil
+				c.RunnerProfiles = []*pb.ServerConfig_RunnerProfile{p}
+			},
+			"target_runner: cannot be blank",
+		},
+
+		{
+			"duplicate runner profiles",
+			func(c *pb.ServerConfig) {
+				c.RunnerProfiles = []*pb.ServerConfig_RunnerProfile{
+					testRunnerProfile("docker"),
+					testRunnerProfile("docker"),
+				}
+			},
+			`profile name "docker" is used more than once`,
+		},
 	}
 
 	for _, tt := range cases {
@@ -55,3 +108,16 @@ func TestValidateServerConfig(t *testing.T) {
 		})
 	}
 }
+
+func testRunnerProfile(name string) *pb.ServerConfig_RunnerProfile {
+	return &pb.ServerConfig_RunnerProfile{
+		Name: name,
+		TargetRunner: &pb.Ref_Runner{
+			Target: &pb.Ref_Runner_Labels{
+				Labels: &pb.Ref_RunnerLabels{
+					Labels: map[string]string{"docker": "true"},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+)
+
+// RateLimiter may be implemented by the service to limit how often the
+// endpoints of the server can be called. Calls that are limited fail with
+// the returned error, which should have the ResourceExhausted code.
+type RateLimiter interface {
+	// RateLimitAddr is called before each call, before authentication, to
+	// limit the calls of each client address. Calls that then fail
+	// authentication are limited too, so tokens can't be guessed.
+	RateLimitAddr(ctx context.Context, endpoint string) error
+
+	// RateLimit is called before each call after authentication, so the
+	// caller is known if the server requires authentication.
+	RateLimit(ctx context.Context, endpoint string) error
+
+	// AuthFailed is called when the authentication of a call fails, so
+	// that addresses that keep failing can be limited by RateLimitAddr.
+	AuthFailed(ctx context.Context)
+}
+
+// rateLimitAddrUnaryInterceptor returns a gRPC unary interceptor that
+// limits calls by client address with the given RateLimiter. This must be
+// before authentication.
+func rateLimitAddrUnaryInterceptor(l RateLimiter) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.RateLimitAddr(ctx, filepath.Base(info.FullMethod)); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitAddrStreamInterceptor returns a gRPC stream interceptor that
+// limits starting streams by client address with the given RateLimiter.
+// This must be before authentication.
+func rateLimitAddrStreamInterceptor(l RateLimiter) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if err := l.RateLimitAddr(ss.Context(), filepath.Base(info.FullMethod)); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// rateLimitUnaryInterceptor returns a gRPC unary interceptor that limits
+// calls with the given RateLimiter.
+func rateLimitUnaryInterceptor(l RateLimiter) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.RateLimit(ctx, filepath.Base(info.FullMethod)); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitStreamInterceptor returns a gRPC stream interceptor that limits
+// starting streams with the given RateLimiter.
+func rateLimitStreamInterceptor(l RateLimiter) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if err := l.RateLimit(ss.Context(), filepath.Base(info.FullMethod)); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/oklog/run"
@@ -90,6 +91,14 @@ type options struct {
 	// BrowserUIEnabled determines if the browser UI should be mounted
 	BrowserUIEnabled bool
 
+	// ClientCAs, if set, returns the certificate authorities that the
+	// client certificates of runners and entrypoints must be signed by.
+	ClientCAs func() *ClientCAs
+
+	// DrainTimeout is how long the service may take to drain when the
+	// server shuts down, if it implements Drainer.
+	DrainTimeout time.Duration
+
 	grpcServer *grpc.Server
 }
 
@@ -132,3 +141,17 @@ func WithAuthentication(ac AuthChecker) Option {
 func WithBrowserUI(enabled bool) Option {
 	return func(opts *options) { opts.BrowserUIEnabled = enabled }
 }
+
+// WithClientCAs configures the server to require client certificates for
+// the runner and entrypoint endpoints. f is called for each call so that
+// the authorities can be rotated.
+func WithClientCAs(f func() *ClientCAs) Option {
+	return func(opts *options) { opts.ClientCAs = f }
+}
+
+// WithDrainTimeout sets how long the service may take to drain when the
+// server shuts down. This defaults to DefaultDrainTimeout. A negative
+// duration skips draining.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(opts *options) { opts.DrainTimeout = d }
+}
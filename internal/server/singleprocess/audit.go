@@ -0,0 +1,288 @@
+package singleprocess
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	// auditRequestMax is the maximum length of the request summary of an
+	// audit event. Longer summaries are truncated.
+	auditRequestMax = 4096
+
+	// auditRedacted replaces secret values in the request summary.
+	auditRedacted = "(redacted)"
+)
+
+// auditSecretFields are the names of string fields that are removed from
+// the request summary of audit events.
+var auditSecretFields = map[protoreflect.Name]struct{}{
+	"token":         {},
+	"value":         {},
+	"code":          {},
+	"nonce":         {},
+	"client_secret": {},
+	"api_token":     {},
+	"password":      {},
+	"secret":        {},
+}
+
+// auditSecretMaps are the map fields whose values are removed from the
+// request summary of audit events.
+var auditSecretMaps = map[protoreflect.FullName]struct{}{
+	"hashicorp.waypoint.ConfigSource.config": {},
+}
+
+// auditLog writes audit events to the configured sinks in addition to
+// the state store.
+type auditLog struct {
+	lock  sync.Mutex
+	sinks []io.Writer
+}
+
+// initAudit opens the sinks that audit events are exported to. This can
+// be called again to reload the configuration, in which case the previous
+// sinks are closed once the new ones are open.
+func (s *service) initAudit(cfg *configpkg.Audit) error {
+	var sinks []io.Writer
+	if cfg.File != "" {
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("error opening audit log file: %s", err)
+		}
+
+		sinks = append(sinks, f)
+	}
+
+	if cfg.Syslog {
+		tag := cfg.SyslogTag
+		if tag == "" {
+			tag = "waypoint"
+		}
+
+		w, err := auditSyslog(tag)
+		if err != nil {
+			closeAuditSinks(sinks)
+			return fmt.Errorf("error connecting to syslog for the audit log: %s", err)
+		}
+
+		sinks = append(sinks, w)
+	}
+
+	s.audit.lock.Lock()
+	old := s.audit.sinks
+	s.audit.sinks = sinks
+	s.audit.lock.Unlock()
+
+	closeAuditSinks(old)
+	return nil
+}
+
+// closeAuditSinks closes the sinks that can be closed.
+func closeAuditSinks(sinks []io.Writer) {
+	for _, sink := range sinks {
+		if c, ok := sink.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
+// Audit implements server.Auditor and records calls that change the server
+// as well as calls that were denied. Failures to record are logged but
+// don't fail the call.
+func (s *service) Audit(ctx context.Context, endpoint string, req interface{}, callErr error) {
+	if strings.HasPrefix(endpoint, "Runner") || strings.HasPrefix(endpoint, "Entrypoint") {
+		// These are the internal protocol of runners and entrypoints. The
+		// changes they make are recorded through the other endpoints.
+		return
+	}
+	if endpointReadOnly(endpoint) && status.Code(callErr) != codes.PermissionDenied {
+		return
+	}
+
+	log := hclog.FromContext(ctx)
+
+	id, err := server.Id()
+	if err != nil {
+		log.Warn("error creating audit event ID", "err", err)
+		return
+	}
+
+	event := &pb.AuditEvent{
+		Id:       id,
+		Time:     ptypes.TimestampNow(),
+		Endpoint: endpoint,
+		Request:  auditRequest(req),
+	}
+	if info := authInfoFromContext(ctx); info != nil {
+		event.User = info.user
+	}
+	if callErr != nil {
+		event.Error = callErr.Error()
+	}
+
+	// Find the target of the call. Requests by ID are resolved to the
+	// resource they reference.
+	msgs := []interface{}{req}
+	if resolved, err := s.authResolve(req); err == nil && resolved != nil {
+		msgs = append(msgs, resolved)
+	}
+	for _, msg := range msgs {
+		if msg, ok := msg.(proto.Message); ok && msg != nil {
+			auditTarget(proto.MessageReflect(msg), event)
+		}
+	}
+
+	if err := s.state.AuditEventPut(event); err != nil {
+		log.Warn("error storing audit event", "err", err, "endpoint", endpoint)
+	}
+
+	s.audit.lock.Lock()
+	defer s.audit.lock.Unlock()
+	if len(s.audit.sinks) == 0 {
+		return
+	}
+
+	data, err := protojson.Marshal(event)
+	if err != nil {
+		log.Warn("error encoding audit event", "err", err)
+		return
+	}
+	data = append(data, '\n')
+
+	for _, sink := range s.audit.sinks {
+		if _, err := sink.Write(data); err != nil {
+			log.Warn("error writing audit event", "err", err)
+		}
+	}
+}
+
+// auditTarget sets the project, application and workspace of the event
+// from the first references to them in the message.
+func auditTarget(m protoreflect.Message, event *pb.AuditEvent) {
+	walkMessages(m, func(m protoreflect.ProtoMessage) {
+		switch v := m.(type) {
+		case *pb.Ref_Application:
+			if event.Application == "" {
+				event.Project = v.Project
+				event.Application = v.Application
+			}
+		case *pb.Ref_Project:
+			if event.Project == "" {
+				event.Project = v.Project
+			}
+		case *pb.Project:
+			if event.Project == "" {
+				event.Project = v.Name
+			}
+		case *pb.Ref_Workspace:
+			if event.Workspace == "" {
+				event.Workspace = v.Workspace
+			}
+		}
+	})
+}
+
+// auditRequest returns a JSON summary of the request. Secret values, bytes
+// and Any values are removed. Any values can't be encoded since they are
+// defined by plugins that the server doesn't know about.
+func auditRequest(req interface{}) string {
+	msg, ok := req.(proto.Message)
+	if !ok || msg == nil {
+		return ""
+	}
+
+	m := proto.MessageReflect(proto.Clone(msg))
+	auditRedact(m)
+
+	data, err := protojson.Marshal(m.Interface())
+	if err != nil {
+		return ""
+	}
+
+	result := string(data)
+	if len(result) > auditRequestMax {
+		result = result[:auditRequestMax] + "..."
+	}
+
+	return result
+}
+
+// auditRedact removes secret values from the message in place.
+func auditRedact(m protoreflect.Message) {
+	if !m.IsValid() {
+		return
+	}
+
+	// Messages can't be changed while ranging over them so we collect the
+	// fields to change first.
+	var clearFields, redactFields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if _, ok := auditSecretMaps[fd.FullName()]; ok {
+				clearFields = append(clearFields, fd)
+			} else if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					auditRedact(v.Message())
+					return true
+				})
+			}
+
+		case fd.Kind() == protoreflect.BytesKind:
+			clearFields = append(clearFields, fd)
+
+		case fd.Kind() == protoreflect.StringKind:
+			if _, ok := auditSecretFields[fd.Name()]; ok {
+				redactFields = append(redactFields, fd)
+			}
+
+		case fd.Kind() != protoreflect.MessageKind:
+			// Other scalars aren't secret.
+
+		case fd.Message().FullName() == "google.protobuf.Any":
+			clearFields = append(clearFields, fd)
+
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				auditRedact(list.Get(i).Message())
+			}
+
+		default:
+			auditRedact(v.Message())
+		}
+
+		return true
+	})
+
+	for _, fd := range clearFields {
+		m.Clear(fd)
+	}
+	for _, fd := range redactFields {
+		if fd.IsList() {
+			m.Clear(fd)
+			continue
+		}
+
+		m.Set(fd, protoreflect.ValueOfString(auditRedacted))
+	}
+}
+
+var _ server.Auditor = (*service)(nil)
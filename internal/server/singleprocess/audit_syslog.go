@@ -0,0 +1,20 @@
+// +build !windows
+
+package singleprocess
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// auditSyslog returns a writer that sends each write to the local syslog
+// daemon as a message with the given tag.
+func auditSyslog(tag string) (io.Writer, error) {
+	return syslog.New(syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+}
+
+// logExportSyslog returns a writer that sends each write to the local
+// syslog daemon as a message with the given tag.
+func logExportSyslog(tag string) (io.Writer, error) {
+	return syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+}
@@ -0,0 +1,18 @@
+// +build windows
+
+package singleprocess
+
+import (
+	"fmt"
+	"io"
+)
+
+// auditSyslog is not supported on Windows since there is no syslog.
+func auditSyslog(tag string) (io.Writer, error) {
+	return nil, fmt.Errorf("syslog is not supported on Windows")
+}
+
+// logExportSyslog is not supported on Windows since there is no syslog.
+func logExportSyslog(tag string) (io.Writer, error) {
+	return nil, fmt.Errorf("syslog is not supported on Windows")
+}
@@ -0,0 +1,114 @@
+package singleprocess
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceAudit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+
+	var buf bytes.Buffer
+	s.audit.sinks = []io.Writer{&buf}
+
+	// Read-only calls aren't recorded unless they're denied
+	s.Audit(ctx, "ListBuilds", &pb.ListBuildsRequest{}, nil)
+	s.Audit(ctx, "RunnerConfig", nil, nil)
+	resp, err := s.ListAuditEvents(ctx, &pb.ListAuditEventsRequest{})
+	require.NoError(err)
+	require.Empty(resp.Events)
+
+	// Config values are redacted
+	s.Audit(ctx, "SetConfig", &pb.ConfigSetRequest{
+		Variables: []*pb.ConfigVar{
+			{
+				Scope: &pb.ConfigVar_Application{
+					Application: &pb.Ref_Application{Project: "web", Application: "api"},
+				},
+				Name:  "DATABASE_URL",
+				Value: "postgres://secret",
+			},
+		},
+	}, nil)
+
+	// Requests by ID record the target
+	job := serverptypes.TestJobNew(t, nil)
+	job.Id = "A"
+	require.NoError(s.state.JobCreate(job))
+	s.Audit(ctx, "CancelJob", &pb.CancelJobRequest{JobId: "A"}, errors.New("failed"))
+
+	s.Audit(ctx, "GetJob", &pb.GetJobRequest{JobId: "A"},
+		status.Errorf(codes.PermissionDenied, "denied"))
+
+	resp, err = s.ListAuditEvents(ctx, &pb.ListAuditEventsRequest{})
+	require.NoError(err)
+	require.Len(resp.Events, 3)
+	require.Empty(resp.NextPageToken)
+
+	denied := resp.Events[0]
+	require.Equal("GetJob", denied.Endpoint)
+	require.Contains(denied.Error, "denied")
+
+	cancel := resp.Events[1]
+	require.Equal("CancelJob", cancel.Endpoint)
+	require.Equal(job.Application.Project, cancel.Project)
+	require.Equal(job.Application.Application, cancel.Application)
+	require.Equal(job.Workspace.Workspace, cancel.Workspace)
+	require.Equal("failed", cancel.Error)
+
+	set := resp.Events[2]
+	require.Equal("SetConfig", set.Endpoint)
+	require.Equal("web", set.Project)
+	require.Equal("api", set.Application)
+	require.Contains(set.Request, "DATABASE_URL")
+	require.NotContains(set.Request, "secret")
+
+	// Events are exported to the sinks
+	require.Equal(3, bytes.Count(buf.Bytes(), []byte("\n")))
+	require.NotContains(buf.String(), "postgres://secret")
+
+	// Pagination
+	resp, err = s.ListAuditEvents(ctx, &pb.ListAuditEventsRequest{PageSize: 2})
+	require.NoError(err)
+	require.Len(resp.Events, 2)
+	require.NotEmpty(resp.NextPageToken)
+
+	resp, err = s.ListAuditEvents(ctx, &pb.ListAuditEventsRequest{
+		PageSize:  2,
+		PageToken: resp.NextPageToken,
+	})
+	require.NoError(err)
+	require.Len(resp.Events, 1)
+	require.Equal("SetConfig", resp.Events[0].Endpoint)
+	require.Empty(resp.NextPageToken)
+}
+
+func TestAuditRedact_configSource(t *testing.T) {
+	require := require.New(t)
+
+	req := &pb.SetConfigSourceRequest{
+		ConfigSource: &pb.ConfigSource{
+			Type:   "vault",
+			Config: map[string]string{"token": "s.secret"},
+		},
+	}
+	auditRedact(proto.MessageReflect(req))
+	require.Equal("vault", req.ConfigSource.Type)
+	require.Empty(req.ConfigSource.Config)
+}
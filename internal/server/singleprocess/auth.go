@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/mr-tron/base58"
@@ -44,6 +45,11 @@ const (
 
 var (
 	ErrInvalidToken = errors.New("invalid authentication token")
+
+	// defaultLoginTokenTTL is how long the tokens created with
+	// GenerateLoginToken are valid if the request has no duration and
+	// the TTL isn't configured.
+	defaultLoginTokenTTL = 30 * 24 * time.Hour
 )
 
 // DecodeToken parses the string and validates it as a valid token. If the token
@@ -98,16 +104,47 @@ func (s *service) DecodeToken(token string) (*pb.TokenTransport, *pb.Token, erro
 		}
 	}
 
+	revoked, err := s.state.TokenRevoked(tokenID(&body))
+	if err != nil {
+		return nil, nil, err
+	}
+	if revoked {
+		return nil, nil, errors.Wrapf(ErrInvalidToken, "token has been revoked")
+	}
+
 	return &tt, &body, nil
 }
 
+// tokenID returns the ID of the token that is used for its record.
+func tokenID(body *pb.Token) string {
+	return base58.Encode(body.TokenId)
+}
+
+// tokenInfo returns the record of the token.
+func tokenInfo(body *pb.Token) *pb.TokenInfo {
+	return &pb.TokenInfo{
+		Id:           tokenID(body),
+		Name:         body.Name,
+		User:         body.User,
+		CreateTime:   ptypes.TimestampNow(),
+		ValidUntil:   body.ValidUntil,
+		Invite:       body.Invite,
+		Entrypoint:   body.Entrypoint != nil,
+		Runner:       body.Runner,
+		Permissions:  body.Permissions,
+		Organization: body.Organization,
+	}
+}
+
 // Authenticate checks if the given endpoint should be allowed. This is called during a
 // gRPC request. Effects is some information about the endpoint, at present these are
 // either ["readonly"] or ["mutable"] to indicate if the endpoint will be only reading
 // data or also mutating it.
 func (s *service) Authenticate(ctx context.Context, token, endpoint string, effects []string) error {
 	// We always allow ConvertInviteToken so that folks can actually get authentication data
-	if endpoint == "ConvertInviteToken" || endpoint == "BootstrapToken" || endpoint == "GetVersionInfo" {
+	switch endpoint {
+	case "ConvertInviteToken", "BootstrapToken", "GetVersionInfo",
+		"GetOIDCAuthURL", "CompleteOIDCAuth":
 		return nil
 	}
 
@@ -129,14 +166,56 @@ func (s *service) Authenticate(ctx context.Context, token, endpoint string, effe
 		return status.Errorf(codes.Unauthenticated, "Unauthorized endpoint")
 	}
 
-	// TODO When we have a user model, this is where you'll check for the user.
+	// Runner tokens can only access the APIs that runners use.
+	if body.Runner && !runnerEndpoint(endpoint) {
+		return status.Errorf(codes.Unauthenticated, "Unauthorized endpoint")
+	}
+
+	// Tokens for users that logged in with OIDC are only valid as long as
+	// the user exists.
 	if body.User != DefaultUser {
-		return ErrInvalidToken
+		if _, err := s.state.UserGet(body.User); err != nil {
+			return ErrInvalidToken
+		}
 	}
 
 	return nil
 }
 
+// runnerEndpoints are the endpoints that runner tokens can access besides
+// the Runner endpoints. Runners record the operations of their jobs, read
+// the previous operations these depend on and create the invites for the
+// entrypoints of their deployments.
+var runnerEndpoints = map[string]struct{}{
+	"GetJob":                  {},
+	"GenerateInviteToken":     {},
+	"UpsertBuild":             {},
+	"UpsertPushedArtifact":    {},
+	"UpsertDeployment":        {},
+	"UpsertRelease":           {},
+	"GetBuild":                {},
+	"GetLatestBuild":          {},
+	"ListBuilds":              {},
+	"GetPushedArtifact":       {},
+	"GetLatestPushedArtifact": {},
+	"ListPushedArtifacts":     {},
+	"GetDeployment":           {},
+	"ListDeployments":         {},
+	"GetRelease":              {},
+	"GetLatestRelease":        {},
+	"ListReleases":            {},
+}
+
+// runnerEndpoint returns true if runner tokens can access the endpoint.
+func runnerEndpoint(endpoint string) bool {
+	if strings.HasPrefix(endpoint, "Runner") {
+		return true
+	}
+
+	_, ok := runnerEndpoints[endpoint]
+	return ok
+}
+
 // Generate a new token by signing the data in body.
 // keyId controls which key is used to sign the key (key values are generated lazily).
 // metadata is attached to the token transport as configuration style information
@@ -169,6 +248,13 @@ func (s *service) GenerateToken(keyId string, metadata map[string]string, body *
 		return "", err
 	}
 
+	// Keep a record of the token so that it can be listed and revoked.
+	if len(body.TokenId) > 0 {
+		if err := s.state.TokenPut(tokenInfo(body)); err != nil {
+			return "", err
+		}
+	}
+
 	var buf bytes.Buffer
 	buf.WriteString(tokenMagic)
 	buf.Write(ttData)
@@ -184,23 +270,110 @@ func (s *service) NewLoginToken(
 	metadata map[string]string,
 	entrypoint *pb.Token_Entrypoint,
 ) (string, error) {
-	var body pb.Token
+	return s.newUserLoginToken(keyId, &pb.Token{
+		User:       DefaultUser,
+		Entrypoint: entrypoint,
+	}, 0, metadata)
+}
+
+// newUserLoginToken creates a new login token. The user, restrictions and
+// name of the token are taken from template. If ttl is non-zero the token
+// is only valid for that long.
+func (s *service) newUserLoginToken(
+	keyId string,
+	template *pb.Token,
+	ttl time.Duration,
+	metadata map[string]string,
+) (string, error) {
+	body := proto.Clone(template).(*pb.Token)
 	body.Login = true
-	body.User = DefaultUser
+	body.Invite = false
 	body.TokenId = make([]byte, 16)
-	body.Entrypoint = entrypoint
+	body.ValidUntil = nil
+
+	if ttl > 0 {
+		until := time.Now().UTC().Add(ttl)
+		body.ValidUntil = &timestamp.Timestamp{
+			Seconds: until.Unix(),
+			Nanos:   int32(until.Nanosecond()),
+		}
+	}
 
 	_, err := io.ReadFull(rand.Reader, body.TokenId)
 	if err != nil {
 		return "", err
 	}
 
-	return s.GenerateToken(keyId, metadata, &body)
+	return s.GenerateToken(keyId, metadata, body)
 }
 
 // Create a new login token. This is just a gRPC wrapper around NewLoginToken.
-func (s *service) GenerateLoginToken(ctx context.Context, _ *empty.Empty) (*pb.NewTokenResponse, error) {
-	token, err := s.NewLoginToken(DefaultKeyId, nil, nil)
+// The token is for the same user as the caller and can't have more access
+// than the caller.
+func (s *service) GenerateLoginToken(ctx context.Context, req *pb.LoginTokenRequest) (*pb.NewTokenResponse, error) {
+	if req == nil {
+		req = &pb.LoginTokenRequest{}
+	}
+
+	var ttl time.Duration
+	if req.Duration != "" {
+		var err error
+		ttl, err = time.ParseDuration(req.Duration)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid duration: %s", err)
+		}
+		if ttl < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "duration can't be negative")
+		}
+	}
+
+	user, perms, err := authTokenPermissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authTokenKind(ctx, req.Runner, nil); err != nil {
+		return nil, err
+	}
+	org, err := s.authTokenOrganization(ctx, req.Organization, req.Runner, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users that log in with OIDC must log in again once the OIDC token
+	// TTL is over, so their tokens can't be valid for longer than that or
+	// than the token of the request.
+	if user != DefaultUser {
+		maxTTL := s.oidcTokenTTL()
+		switch {
+		case req.Duration == "":
+			ttl = maxTTL
+
+		case ttl <= 0:
+			return nil, status.Errorf(codes.InvalidArgument,
+				"the tokens of users that log in with OIDC must expire")
+
+		case ttl > maxTTL:
+			ttl = maxTTL
+		}
+
+		if info := authInfoFromContext(ctx); info != nil && !info.validUntil.IsZero() {
+			if left := time.Until(info.validUntil); ttl > left {
+				ttl = left
+			}
+		}
+	} else if ttl == 0 {
+		// A zero duration is the same as none, so tokens only stop
+		// expiring if the TTL is long rather than by accident.
+		ttl = s.loginTokenTTL
+	}
+
+	token, err := s.newUserLoginToken(DefaultKeyId, &pb.Token{
+		User:         user,
+		Permissions:  perms,
+		Name:         req.Name,
+		Runner:       req.Runner,
+		Organization: org,
+	}, ttl, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -217,10 +390,24 @@ func (s *service) NewInviteToken(
 	metadata map[string]string,
 	entrypoint *pb.Token_Entrypoint,
 ) (string, error) {
-	var body pb.Token
+	return s.newUserInviteToken(duration, keyId, &pb.Token{
+		User:       DefaultUser,
+		Entrypoint: entrypoint,
+	}, metadata)
+}
+
+// newUserInviteToken creates a new invite token. The login token it is
+// exchanged for gets the user, restrictions and name of template.
+func (s *service) newUserInviteToken(
+	duration time.Duration,
+	keyId string,
+	template *pb.Token,
+	metadata map[string]string,
+) (string, error) {
+	body := proto.Clone(template).(*pb.Token)
+	body.Login = false
 	body.Invite = true
 	body.TokenId = make([]byte, 16)
-	body.Entrypoint = entrypoint
 
 	now := time.Now().UTC().Add(duration)
 	body.ValidUntil = &timestamp.Timestamp{
@@ -233,7 +420,7 @@ func (s *service) NewInviteToken(
 		return "", err
 	}
 
-	return s.GenerateToken(keyId, metadata, &body)
+	return s.GenerateToken(keyId, metadata, body)
 }
 
 // Create a new invite token. This is just a gRPC wrapper around NewInviteToken.
@@ -243,7 +430,32 @@ func (s *service) GenerateInviteToken(ctx context.Context, req *pb.InviteTokenRe
 		return nil, err
 	}
 
-	token, err := s.NewInviteToken(dur, DefaultKeyId, nil, req.Entrypoint)
+	// Runner tokens can only invite the entrypoints of their deployments.
+	if info := authInfoFromContext(ctx); info != nil && info.runner && req.Entrypoint == nil {
+		return nil, status.Errorf(codes.PermissionDenied,
+			"runner tokens can only create entrypoint invites")
+	}
+
+	user, perms, err := authTokenPermissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authTokenKind(ctx, req.Runner, req.Entrypoint); err != nil {
+		return nil, err
+	}
+	org, err := s.authTokenOrganization(ctx, req.Organization, req.Runner, req.Entrypoint)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.newUserInviteToken(dur, DefaultKeyId, &pb.Token{
+		User:         user,
+		Permissions:  perms,
+		Entrypoint:   req.Entrypoint,
+		Name:         req.Name,
+		Runner:       req.Runner,
+		Organization: org,
+	}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -262,7 +474,26 @@ func (s *service) ExchangeInvite(keyId, invite string) (string, error) {
 		return "", errors.Wrapf(ErrInvalidToken, "not an invite token")
 	}
 
-	return s.NewLoginToken(keyId, tt.Metadata, body.Entrypoint)
+	// Invites created before users existed don't have a user.
+	user := body.User
+	if user == "" {
+		user = DefaultUser
+	}
+
+	// The tokens of OIDC users expire like the tokens of their logins.
+	var ttl time.Duration
+	if user != DefaultUser {
+		ttl = s.oidcTokenTTL()
+	}
+
+	return s.newUserLoginToken(keyId, &pb.Token{
+		User:         user,
+		Permissions:  body.Permissions,
+		Entrypoint:   body.Entrypoint,
+		Name:         body.Name,
+		Runner:       body.Runner,
+		Organization: body.Organization,
+	}, ttl, tt.Metadata)
 }
 
 // Given an invite token, validate it and return a login token. This is a gRPC wrapper around ExchangeInvite.
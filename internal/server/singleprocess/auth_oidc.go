@@ -0,0 +1,188 @@
+package singleprocess
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/mr-tron/base58"
+	"golang.org/x/crypto/blake2b"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/pkg/oidc"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var (
+	// DefaultOIDCRedirectURIs are the allowed redirect URIs if none are
+	// configured. These match the default address of "waypoint login".
+	DefaultOIDCRedirectURIs = []string{
+		"http://localhost:8250/oidc/callback",
+		"http://127.0.0.1:8250/oidc/callback",
+	}
+
+	// defaultOIDCTokenTTL is how long login tokens for OIDC users are valid
+	// if the TTL isn't configured.
+	defaultOIDCTokenTTL = 24 * time.Hour
+)
+
+// oidcConfig is the OIDC configuration of the server.
+type oidcConfig struct {
+	provider     *oidc.Provider
+	redirectURIs map[string]struct{}
+	tokenTTL     time.Duration
+}
+
+// initOIDC reads the discovery document of the provider and enables OIDC
+// login.
+func (s *service) initOIDC(cfg *configpkg.OIDC) error {
+	ttl := defaultOIDCTokenTTL
+	if cfg.TokenTTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(cfg.TokenTTL)
+		if err != nil {
+			return fmt.Errorf("invalid OIDC token TTL: %s", err)
+		}
+	}
+
+	uris := cfg.AllowedRedirectURIs
+	if len(uris) == 0 {
+		uris = DefaultOIDCRedirectURIs
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	provider, err := oidc.New(ctx, &oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		Scopes:       cfg.Scopes,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.oidc = &oidcConfig{
+		provider:     provider,
+		redirectURIs: make(map[string]struct{}),
+		tokenTTL:     ttl,
+	}
+	for _, uri := range uris {
+		s.oidc.redirectURIs[uri] = struct{}{}
+	}
+
+	return nil
+}
+
+// oidcTokenTTL returns how long login tokens for OIDC users are valid. The
+// tokens of existing users are limited to the default TTL even if OIDC
+// login is no longer enabled.
+func (s *service) oidcTokenTTL() time.Duration {
+	if s.oidc == nil {
+		return defaultOIDCTokenTTL
+	}
+
+	return s.oidc.tokenTTL
+}
+
+// oidcCheck validates the parameters common to the OIDC endpoints.
+func (s *service) oidcCheck(redirectURI, nonce string) error {
+	if s.oidc == nil {
+		return status.Errorf(codes.FailedPrecondition,
+			"OIDC login is not configured for this server")
+	}
+
+	// Only allow known redirects, otherwise the code could be sent to
+	// anyone that gets the user to follow a link.
+	if _, ok := s.oidc.redirectURIs[redirectURI]; !ok {
+		return status.Errorf(codes.InvalidArgument,
+			"redirect URI %q is not allowed", redirectURI)
+	}
+
+	if len(nonce) < 16 {
+		return status.Errorf(codes.InvalidArgument,
+			"nonce must be at least 16 characters")
+	}
+
+	return nil
+}
+
+func (s *service) GetOIDCAuthURL(
+	ctx context.Context,
+	req *pb.GetOIDCAuthURLRequest,
+) (*pb.GetOIDCAuthURLResponse, error) {
+	if err := s.oidcCheck(req.RedirectUri, req.Nonce); err != nil {
+		return nil, err
+	}
+
+	if req.State == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "state must be set")
+	}
+
+	return &pb.GetOIDCAuthURLResponse{
+		Url: s.oidc.provider.AuthURL(req.RedirectUri, req.State, req.Nonce),
+	}, nil
+}
+
+func (s *service) CompleteOIDCAuth(
+	ctx context.Context,
+	req *pb.CompleteOIDCAuthRequest,
+) (*pb.CompleteOIDCAuthResponse, error) {
+	if err := s.oidcCheck(req.RedirectUri, req.Nonce); err != nil {
+		return nil, err
+	}
+
+	claims, err := s.oidc.provider.Exchange(ctx, req.RedirectUri, req.Code, req.Nonce)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "OIDC login failed: %s", err)
+	}
+
+	// Get the existing user or create a new one on their first login.
+	id := oidcUserId(claims.Issuer, claims.Subject)
+	user, err := s.state.UserGet(id)
+	if status.Code(err) == codes.NotFound {
+		user = &pb.User{
+			Id: id,
+			Oidc: &pb.User_OIDC{
+				Issuer:  claims.Issuer,
+				Subject: claims.Subject,
+			},
+			CreateTime: ptypes.TimestampNow(),
+		}
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the claims that may change.
+	user.Username = claims.PreferredUsername
+	if user.Username == "" {
+		user.Username = claims.Name
+	}
+	user.Email = claims.Email
+	user.LastLoginTime = ptypes.TimestampNow()
+	if err := s.state.UserPut(user); err != nil {
+		return nil, err
+	}
+
+	token, err := s.newUserLoginToken(DefaultKeyId, &pb.Token{User: user.Id, Organization: user.Organization}, s.oidc.tokenTTL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CompleteOIDCAuthResponse{
+		Token: token,
+		User:  user,
+	}, nil
+}
+
+// oidcUserId returns the user ID for an OIDC identity. Subjects are only
+// unique per issuer so the issuer is part of the ID.
+func oidcUserId(issuer, subject string) string {
+	sum := blake2b.Sum256([]byte(issuer + "\x00" + subject))
+	return "oidc-" + base58.Encode(sum[:16])
+}
@@ -0,0 +1,129 @@
+package singleprocess
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// organizationEndpoints are the endpoints that callers in an organization
+// can call without referencing a project. These filter their results to
+// the organization of the caller. All other endpoints without a project,
+// such as managing the server and runners, are only available to callers
+// that aren't in an organization.
+var organizationEndpoints = map[string]struct{}{
+	"ListProjects":       {},
+	"ListWorkspaces":     {},
+	"GetWorkspace":       {},
+	"ListUsers":          {},
+	"SetUserPermissions": {},
+	"ListTokens":         {},
+	"ListHostnames":      {},
+	"DeleteHostname":     {},
+	"ListOrganizations":  {},
+}
+
+// authorizeOrganization checks that the projects of a request are in the
+// organization of the caller.
+func (s *service) authorizeOrganization(info *authInfo, endpoint string, projects []string) error {
+	if info.org == "" {
+		return nil
+	}
+
+	if len(projects) == 0 {
+		if _, ok := organizationEndpoints[endpoint]; ok {
+			return nil
+		}
+
+		return status.Errorf(codes.PermissionDenied,
+			"%s can't be called by callers in an organization", endpoint)
+	}
+
+	for _, project := range projects {
+		p, err := s.state.ProjectGet(&pb.Ref_Project{Project: project})
+		if status.Code(err) == codes.NotFound {
+			// New projects are created in the organization of the caller.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if !strings.EqualFold(p.Organization, info.org) {
+			return status.Errorf(codes.PermissionDenied,
+				"project %q is not in organization %q", project, info.org)
+		}
+	}
+
+	return nil
+}
+
+// organizationHasProject returns true if the project exists and is in the
+// organization. Every project is in the empty organization of callers
+// that aren't in an organization.
+func (s *service) organizationHasProject(org, project string) bool {
+	if org == "" {
+		return true
+	}
+
+	p, err := s.state.ProjectGet(&pb.Ref_Project{Project: project})
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(p.Organization, org)
+}
+
+// authOrganization returns the organization of the caller. This is empty
+// if the caller isn't in an organization or the server doesn't require
+// authentication.
+func authOrganization(ctx context.Context) string {
+	if info := authInfoFromContext(ctx); info != nil {
+		return info.org
+	}
+
+	return ""
+}
+
+// authTokenOrganization returns the organization for a token created by
+// the caller. Tokens of callers in an organization are always in the same
+// organization, and can't be for runners or entrypoints since those have
+// access to the jobs and deployments of all organizations.
+func (s *service) authTokenOrganization(
+	ctx context.Context,
+	requested string,
+	runner bool,
+	entrypoint *pb.Token_Entrypoint,
+) (string, error) {
+	if org := authOrganization(ctx); org != "" {
+		if requested != "" && !strings.EqualFold(requested, org) {
+			return "", status.Errorf(codes.PermissionDenied,
+				"can't create a token for another organization")
+		}
+		if runner || entrypoint != nil {
+			return "", status.Errorf(codes.PermissionDenied,
+				"runner and entrypoint tokens can't be created by callers in an organization")
+		}
+
+		return org, nil
+	}
+
+	if requested == "" {
+		return "", nil
+	}
+
+	o, err := s.state.OrganizationGet(requested)
+	if status.Code(err) == codes.NotFound {
+		return "", status.Errorf(codes.InvalidArgument,
+			"organization not found: %s", requested)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return o.Name, nil
+}
@@ -0,0 +1,539 @@
+package singleprocess
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// authInfoKey is the context key for the authInfo of a request.
+type authInfoKey struct{}
+
+// authInfo is the caller of an authenticated request.
+type authInfo struct {
+	// user is the ID of the user that the token was issued for.
+	user string
+
+	// tokenId is the ID of the token of the request.
+	tokenId string
+
+	// userPerms are the permissions of the user. These aren't used if
+	// all is true, which is the case for DefaultUser.
+	userPerms []*pb.Permission
+	all       bool
+
+	// tokenPerms further restrict what the token can access if they
+	// aren't empty.
+	tokenPerms []*pb.Permission
+
+	// entrypoint is true for entrypoint tokens. These are already limited
+	// to the entrypoint endpoints during authentication. deploymentId is
+	// the deployment that the token is for, and the entrypoint endpoints
+	// only serve the instances of that deployment.
+	entrypoint   bool
+	deploymentId string
+
+	// runner is true for runner tokens. These are already limited to the
+	// runner endpoints during authentication.
+	runner bool
+
+	// org is the organization of the caller. If this is set, the caller
+	// can only access the projects, users and tokens of the organization.
+	org string
+
+	// validUntil is when the token of the request expires, or zero if it
+	// doesn't expire.
+	validUntil time.Time
+}
+
+// allowed returns true if the caller can perform the verb on the project
+// and workspace. Empty values are only allowed by permissions for all
+// projects or workspaces.
+func (a *authInfo) allowed(project, workspace string, verb pb.Permission_Verb) bool {
+	if !a.all && !permissionsAllow(a.userPerms, project, workspace, verb) {
+		return false
+	}
+
+	if len(a.tokenPerms) > 0 && !permissionsAllow(a.tokenPerms, project, workspace, verb) {
+		return false
+	}
+
+	return true
+}
+
+// permissionsAllow returns true if any permission allows the verb on the
+// project and workspace. Verbs include the verbs below them, so ADMIN
+// allows DEPLOY and READ.
+func permissionsAllow(perms []*pb.Permission, project, workspace string, verb pb.Permission_Verb) bool {
+	for _, p := range perms {
+		if p.Verb < verb {
+			continue
+		}
+		if p.Project != "" && !strings.EqualFold(p.Project, project) {
+			continue
+		}
+		if p.Workspace != "" && !strings.EqualFold(p.Workspace, workspace) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// authInfoFromContext returns the caller of the request. This is nil if
+// the server doesn't require authentication.
+func authInfoFromContext(ctx context.Context) *authInfo {
+	info, _ := ctx.Value(authInfoKey{}).(*authInfo)
+	return info
+}
+
+// AuthContext implements server.AuthContextProvider and stores the caller
+// in the context so that Authorize and the endpoints can check access.
+func (s *service) AuthContext(ctx context.Context, token string) (context.Context, error) {
+	// Endpoints that don't require authentication may be called without
+	// a valid token. Authenticate has already validated the token otherwise.
+	if token == "" {
+		return ctx, nil
+	}
+	_, body, err := s.DecodeToken(token)
+	if err != nil || !body.Login {
+		return ctx, nil
+	}
+
+	info := &authInfo{
+		user:       body.User,
+		tokenId:    tokenID(body),
+		tokenPerms: body.Permissions,
+		entrypoint: body.Entrypoint != nil,
+		runner:     body.Runner,
+		org:        body.Organization,
+	}
+	if body.Entrypoint != nil {
+		info.deploymentId = body.Entrypoint.DeploymentId
+	}
+	if body.ValidUntil != nil {
+		info.validUntil = time.Unix(body.ValidUntil.Seconds, int64(body.ValidUntil.Nanos))
+	}
+	if body.User == DefaultUser {
+		info.all = true
+	} else {
+		user, err := s.state.UserGet(body.User)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+
+		// Tokens are always in the organization of their user. If the user
+		// moved to another organization, the old tokens are no longer valid.
+		if user.Organization != "" {
+			if info.org != "" && !strings.EqualFold(info.org, user.Organization) {
+				return nil, ErrInvalidToken
+			}
+
+			info.org = user.Organization
+		}
+
+		info.userPerms = user.Permissions
+	}
+
+	return context.WithValue(ctx, authInfoKey{}, info), nil
+}
+
+// Authorize implements server.Authorizer and checks that the caller can
+// perform the endpoint on the projects and workspaces of the request.
+func (s *service) Authorize(ctx context.Context, endpoint string, req interface{}) error {
+	info := authInfoFromContext(ctx)
+	if info == nil || info.entrypoint {
+		return nil
+	}
+
+	switch endpoint {
+	case "ConvertInviteToken", "BootstrapToken", "GetVersionInfo",
+		"GetOIDCAuthURL", "CompleteOIDCAuth":
+		// These don't require authentication at all.
+		return nil
+
+	case "GenerateLoginToken", "GenerateInviteToken":
+		// Any caller can create tokens since these are limited to the
+		// permissions of the caller. Runner and entrypoint tokens require
+		// more, which is checked by authTokenKind.
+		return nil
+
+	case "RevokeToken":
+		// Callers can always revoke their own token. This is checked by
+		// the endpoint since it depends on the token.
+		return nil
+
+	case "GetJobStream", "GetLogStream", "StartExecStream":
+		// These are checked by the endpoint once the request is known.
+		if req == nil {
+			return nil
+		}
+
+	case "StreamEvents":
+		// The events are filtered by the endpoint to the projects and
+		// workspaces that the caller can read.
+		return nil
+	}
+
+	if err := s.authorize(info, endpoint, req); err != nil {
+		s.Audit(ctx, endpoint, req, err)
+		return err
+	}
+
+	return nil
+}
+
+// authorize checks that the caller can perform the endpoint on the
+// projects and workspaces of the request.
+func (s *service) authorize(info *authInfo, endpoint string, req interface{}) error {
+	projects, workspaces, err := s.authScope(req)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeOrganization(info, endpoint, projects); err != nil {
+		return err
+	}
+
+	verb := endpointVerb(endpoint)
+
+	// Requests for no particular project, such as listing all projects,
+	// require a permission for all projects.
+	if len(projects) == 0 {
+		projects = []string{""}
+	}
+	if len(workspaces) == 0 {
+		workspaces = []string{""}
+	}
+
+	for _, project := range projects {
+		for _, workspace := range workspaces {
+			if !info.allowed(project, workspace, verb) {
+				return status.Errorf(codes.PermissionDenied,
+					"%s permission is required", verb)
+			}
+		}
+	}
+
+	return nil
+}
+
+// endpointVerb returns the verb required to call the endpoint.
+func endpointVerb(endpoint string) pb.Permission_Verb {
+	switch endpoint {
+	case "SetServerConfig", "ListUsers", "SetUserPermissions",
+		"ListAuditEvents", "ListTokens", "CreateSnapshot", "RestoreSnapshot",
+		"UpsertOrganization", "DeleteOrganization",
+		"SetConfigSource", "GetConfigSource", "ListExecRecordings",
+		"GetExecRecording", "RunMaintenance", "AdoptRunner", "ForgetRunner":
+		return pb.Permission_ADMIN
+	}
+
+	switch {
+	case strings.HasPrefix(endpoint, "Runner"),
+		strings.HasPrefix(endpoint, "Entrypoint"):
+		return pb.Permission_ADMIN
+
+	case endpointReadOnly(endpoint):
+		return pb.Permission_READ
+
+	default:
+		return pb.Permission_DEPLOY
+	}
+}
+
+// endpointReadOnly returns true if the endpoint doesn't change the server.
+func endpointReadOnly(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "Get") ||
+		strings.HasPrefix(endpoint, "List") ||
+		strings.HasPrefix(endpoint, "Stream") ||
+		strings.HasPrefix(endpoint, "_List") ||
+		strings.HasPrefix(endpoint, "Validate") ||
+		strings.HasPrefix(endpoint, "Diff") ||
+		strings.HasPrefix(endpoint, "Search")
+}
+
+// authScope returns the projects and workspaces that a request accesses.
+// Requests that reference a resource by ID are resolved to the resource.
+func (s *service) authScope(req interface{}) ([]string, []string, error) {
+	projects := map[string]struct{}{}
+	workspaces := map[string]struct{}{}
+
+	msgs := []interface{}{req}
+	resolved, err := s.authResolve(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resolved != nil {
+		msgs = append(msgs, resolved)
+	}
+
+	for _, msg := range msgs {
+		if msg, ok := msg.(proto.Message); ok && msg != nil {
+			authWalk(proto.MessageReflect(msg), projects, workspaces)
+		}
+	}
+
+	var projectList, workspaceList []string
+	for k := range projects {
+		projectList = append(projectList, k)
+	}
+	for k := range workspaces {
+		workspaceList = append(workspaceList, k)
+	}
+
+	return projectList, workspaceList, nil
+}
+
+// authWalk finds all the project and workspace references in the message.
+func authWalk(m protoreflect.Message, projects, workspaces map[string]struct{}) {
+	add := func(set map[string]struct{}, v string) {
+		if v != "" {
+			set[strings.ToLower(v)] = struct{}{}
+		}
+	}
+
+	walkMessages(m, func(m protoreflect.ProtoMessage) {
+		switch v := m.(type) {
+		case *pb.Ref_Application:
+			add(projects, v.Project)
+		case *pb.Ref_Project:
+			add(projects, v.Project)
+		case *pb.Ref_Workspace:
+			add(workspaces, v.Workspace)
+		case *pb.Project:
+			add(projects, v.Name)
+		}
+	})
+}
+
+// walkMessages calls f for the message and every message within it.
+func walkMessages(m protoreflect.Message, f func(protoreflect.ProtoMessage)) {
+	if !m.IsValid() {
+		return
+	}
+
+	f(m.Interface())
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					walkMessages(v.Message(), f)
+					return true
+				})
+			}
+
+		case fd.Kind() != protoreflect.MessageKind:
+			// Scalars don't contain messages.
+
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				walkMessages(list.Get(i).Message(), f)
+			}
+
+		default:
+			walkMessages(v.Message(), f)
+		}
+
+		return true
+	})
+}
+
+// authResolve returns the resource that a request accesses by ID so its
+// project and workspace can be checked. This returns nil if the request
+// doesn't reference a resource by ID or the resource doesn't exist, in
+// which case only callers with access to all projects are allowed.
+func (s *service) authResolve(req interface{}) (proto.Message, error) {
+	var msg proto.Message
+	var err error
+	switch req := req.(type) {
+	case *pb.GetJobRequest:
+		msg, err = s.authResolveJob(req.JobId)
+	case *pb.CancelJobRequest:
+		msg, err = s.authResolveJob(req.JobId)
+	case *pb.GetJobStreamRequest:
+		msg, err = s.authResolveJob(req.JobId)
+
+	case *pb.GetBuildRequest:
+		msg, err = s.state.BuildGet(req.Ref)
+	case *pb.GetPushedArtifactRequest:
+		msg, err = s.state.ArtifactGet(req.Ref)
+	case *pb.GetDeploymentRequest:
+		msg, err = s.state.DeploymentGet(req.Ref)
+	case *pb.DiffDeploymentsRequest:
+		msg, err = s.authResolveDiff(req)
+	case *pb.GetReleaseRequest:
+		msg, err = s.state.ReleaseGet(req.Ref)
+
+	case *pb.UpsertTriggerRequest:
+		if id := req.GetTrigger().GetId(); id != "" {
+			msg, err = s.state.TriggerGet(id)
+		}
+	case *pb.DeleteTriggerRequest:
+		msg, err = s.state.TriggerGet(req.Id)
+	case *pb.UpsertWebhookRequest:
+		if id := req.GetWebhook().GetId(); id != "" {
+			msg, err = s.state.WebhookGet(id)
+		}
+	case *pb.DeleteWebhookRequest:
+		msg, err = s.state.WebhookGet(req.Id)
+	case *pb.UpsertNotificationSinkRequest:
+		if id := req.GetSink().GetId(); id != "" {
+			msg, err = s.state.NotificationSinkGet(id)
+		}
+	case *pb.DeleteNotificationSinkRequest:
+		msg, err = s.state.NotificationSinkGet(req.Id)
+
+	case *pb.ListInstancesRequest:
+		msg, err = s.authResolveDeployment(req.GetDeploymentId())
+	case *pb.GetLogStreamRequest:
+		msg, err = s.authResolveDeployment(req.GetDeploymentId())
+	case *pb.GetLogsRequest:
+		msg, err = s.authResolveDeployment(req.GetDeploymentId())
+	case *pb.GetExecRecordingRequest:
+		msg, err = s.state.ExecRecordingGet(req.Id)
+	case *pb.ExecStreamRequest:
+		msg, err = s.authResolveDeployment(req.GetStart().GetDeploymentId())
+
+	default:
+		return nil, nil
+	}
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+
+	return msg, err
+}
+
+func (s *service) authResolveJob(id string) (proto.Message, error) {
+	job, err := s.state.JobById(id, nil)
+	if err != nil || job == nil {
+		return nil, err
+	}
+
+	return job.Job, nil
+}
+
+// authResolveDiff resolves both deployments of a diff, since they may be
+// in different workspaces.
+func (s *service) authResolveDiff(req *pb.DiffDeploymentsRequest) (proto.Message, error) {
+	var result pb.DiffDeploymentsResponse
+	for _, d := range []struct {
+		Ref    *pb.Ref_Operation
+		Target **pb.Deployment
+	}{
+		{req.From, &result.From},
+		{req.To, &result.To},
+	} {
+		if d.Ref == nil {
+			continue
+		}
+
+		deployment, err := s.state.DeploymentGet(d.Ref)
+		if err != nil {
+			return nil, err
+		}
+
+		*d.Target = deployment
+	}
+
+	return &result, nil
+}
+
+func (s *service) authResolveDeployment(id string) (proto.Message, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	return s.state.DeploymentGet(&pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: id},
+	})
+}
+
+// authTokenPermissions returns the permissions for a token created by the
+// caller. Tokens can't have more access than the token of the caller, so
+// requested permissions must be allowed for the caller and if none are
+// requested the restrictions of the caller's token are kept.
+func authTokenPermissions(ctx context.Context, requested []*pb.Permission) (string, []*pb.Permission, error) {
+	info := authInfoFromContext(ctx)
+	if info == nil {
+		return DefaultUser, requested, nil
+	}
+
+	if len(requested) == 0 {
+		return info.user, info.tokenPerms, nil
+	}
+
+	for _, p := range requested {
+		if !info.allowed(p.Project, p.Workspace, p.Verb) {
+			return "", nil, status.Errorf(codes.PermissionDenied,
+				"can't create a token with more access than the current token")
+		}
+	}
+
+	return info.user, requested, nil
+}
+
+// authTokenKind checks that the caller can create runner and entrypoint
+// tokens. Runner tokens require ADMIN since runners run the jobs of every
+// project. Entrypoint tokens require DEPLOY on the project and workspace
+// of their deployment since they can read its config variables.
+func (s *service) authTokenKind(ctx context.Context, runner bool, entrypoint *pb.Token_Entrypoint) error {
+	info := authInfoFromContext(ctx)
+	if info == nil {
+		return nil
+	}
+
+	if runner && !info.allowed("", "", pb.Permission_ADMIN) {
+		return status.Errorf(codes.PermissionDenied,
+			"ADMIN permission is required to create runner tokens")
+	}
+
+	if entrypoint != nil {
+		d, err := s.state.DeploymentGet(&pb.Ref_Operation{
+			Target: &pb.Ref_Operation_Id{Id: entrypoint.DeploymentId},
+		})
+		if err != nil {
+			return err
+		}
+
+		if !info.allowed(d.Application.Project, d.Workspace.Workspace, pb.Permission_DEPLOY) {
+			return status.Errorf(codes.PermissionDenied,
+				"DEPLOY permission is required to create entrypoint tokens for the deployment")
+		}
+	}
+
+	return nil
+}
+
+// authEntrypoint checks that an entrypoint token of the request is for the
+// deployment. Other callers are checked by Authorize.
+func authEntrypoint(ctx context.Context, deploymentId string) error {
+	info := authInfoFromContext(ctx)
+	if info == nil || !info.entrypoint {
+		return nil
+	}
+
+	if info.deploymentId == "" || info.deploymentId != deploymentId {
+		return status.Errorf(codes.PermissionDenied,
+			"the entrypoint token is for another deployment")
+	}
+
+	return nil
+}
+
+var (
+	_ server.AuthContextProvider = (*service)(nil)
+	_ server.Authorizer          = (*service)(nil)
+)
@@ -0,0 +1,259 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
+)
+
+func TestServiceAuthorize(t *testing.T) {
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+
+	// A user that can deploy "web" in any workspace and read "api" in prod.
+	require.NoError(s.state.UserPut(&pb.User{
+		Id: "alice",
+		Permissions: []*pb.Permission{
+			{Project: "web", Verb: pb.Permission_DEPLOY},
+			{Project: "api", Workspace: "prod", Verb: pb.Permission_READ},
+		},
+	}))
+
+	authCtx := func(token string) context.Context {
+		ctx, err := s.AuthContext(context.Background(), token)
+		require.NoError(err)
+		return ctx
+	}
+
+	token, err := s.newUserLoginToken(DefaultKeyId, &pb.Token{User: "alice"}, 0, nil)
+	require.NoError(err)
+	ctx := authCtx(token)
+
+	queue := func(project, workspace string) *pb.QueueJobRequest {
+		return &pb.QueueJobRequest{
+			Job: serverptypes.TestJobNew(t, &pb.Job{
+				Application: &pb.Ref_Application{Project: project, Application: "app"},
+				Workspace:   &pb.Ref_Workspace{Workspace: workspace},
+			}),
+		}
+	}
+
+	t.Run("allowed within the permissions", func(t *testing.T) {
+		require := require.New(t)
+
+		require.NoError(s.Authorize(ctx, "QueueJob", queue("web", "default")))
+		require.NoError(s.Authorize(ctx, "QueueJob", queue("WEB", "default")))
+		require.NoError(s.Authorize(ctx, "GetProject", &pb.GetProjectRequest{
+			Project: &pb.Ref_Project{Project: "web"},
+		}))
+		require.NoError(s.Authorize(ctx, "ListBuilds", &pb.ListBuildsRequest{
+			Application: &pb.Ref_Application{Project: "api", Application: "app"},
+			Workspace:   &pb.Ref_Workspace{Workspace: "prod"},
+		}))
+	})
+
+	t.Run("denied outside the permissions", func(t *testing.T) {
+		require := require.New(t)
+
+		tests := map[string]struct {
+			endpoint string
+			req      interface{}
+		}{
+			"other project":   {"QueueJob", queue("db", "default")},
+			"verb too low":    {"QueueJob", queue("api", "prod")},
+			"other workspace": {"ListBuilds", &pb.ListBuildsRequest{Application: &pb.Ref_Application{Project: "api", Application: "app"}}},
+			"no project":      {"ListProjects", nil},
+			"admin":           {"SetServerConfig", &pb.SetServerConfigRequest{}},
+		}
+		for name, tt := range tests {
+			err := s.Authorize(ctx, tt.endpoint, tt.req)
+			require.Error(err, name)
+			require.Equal(codes.PermissionDenied, status.Code(err), name)
+		}
+	})
+
+	t.Run("requests by ID check the referenced resource", func(t *testing.T) {
+		require := require.New(t)
+
+		for _, project := range []string{"web", "db"} {
+			job := queue(project, "default").Job
+			job.Id = project
+			require.NoError(s.state.JobCreate(job))
+		}
+
+		require.NoError(s.Authorize(ctx, "GetJob", &pb.GetJobRequest{JobId: "web"}))
+		require.NoError(s.Authorize(ctx, "GetJobStream", &pb.GetJobStreamRequest{JobId: "web"}))
+		require.Error(s.Authorize(ctx, "GetJob", &pb.GetJobRequest{JobId: "db"}))
+		require.Error(s.Authorize(ctx, "CancelJob", &pb.CancelJobRequest{JobId: "db"}))
+		require.Error(s.Authorize(ctx, "GetJob", &pb.GetJobRequest{JobId: "missing"}))
+	})
+
+	t.Run("default user can access everything", func(t *testing.T) {
+		require := require.New(t)
+
+		token, err := s.NewLoginToken(DefaultKeyId, nil, nil)
+		require.NoError(err)
+		ctx := authCtx(token)
+
+		require.NoError(s.Authorize(ctx, "QueueJob", queue("db", "default")))
+		require.NoError(s.Authorize(ctx, "SetServerConfig", &pb.SetServerConfigRequest{}))
+		require.NoError(s.Authorize(ctx, "RunnerConfig", nil))
+	})
+
+	t.Run("tokens can be restricted further", func(t *testing.T) {
+		require := require.New(t)
+
+		// Tokens can't get more access than the caller
+		_, err := s.GenerateInviteToken(ctx, &pb.InviteTokenRequest{
+			Duration:    "1h",
+			Permissions: []*pb.Permission{{Project: "db", Verb: pb.Permission_READ}},
+		})
+		require.Error(err)
+		require.Equal(codes.PermissionDenied, status.Code(err))
+
+		resp, err := s.GenerateInviteToken(ctx, &pb.InviteTokenRequest{
+			Duration:    "1h",
+			Permissions: []*pb.Permission{{Project: "web", Workspace: "dev", Verb: pb.Permission_READ}},
+		})
+		require.NoError(err)
+
+		token, err := s.ExchangeInvite(DefaultKeyId, resp.Token)
+		require.NoError(err)
+		ctx := authCtx(token)
+
+		info := authInfoFromContext(ctx)
+		require.Equal("alice", info.user)
+		require.NoError(s.Authorize(ctx, "ListBuilds", &pb.ListBuildsRequest{
+			Application: &pb.Ref_Application{Project: "web", Application: "app"},
+			Workspace:   &pb.Ref_Workspace{Workspace: "dev"},
+		}))
+		require.Error(s.Authorize(ctx, "QueueJob", queue("web", "dev")))
+		require.Error(s.Authorize(ctx, "ListBuilds", &pb.ListBuildsRequest{
+			Application: &pb.Ref_Application{Project: "web", Application: "app"},
+			Workspace:   &pb.Ref_Workspace{Workspace: "prod"},
+		}))
+
+		// Login tokens keep the restrictions of the caller
+		login, err := s.GenerateLoginToken(ctx, nil)
+		require.NoError(err)
+		_, body, err := s.DecodeToken(login.Token)
+		require.NoError(err)
+		require.Equal("alice", body.User)
+		require.Len(body.Permissions, 1)
+	})
+
+	t.Run("runner and entrypoint tokens", func(t *testing.T) {
+		require := require.New(t)
+
+		// A token that can only read "api" in prod
+		readToken, err := s.newUserLoginToken(DefaultKeyId, &pb.Token{
+			User:        "alice",
+			Permissions: []*pb.Permission{{Project: "api", Workspace: "prod", Verb: pb.Permission_READ}},
+		}, 0, nil)
+		require.NoError(err)
+		readCtx := authCtx(readToken)
+
+		for _, d := range []*pb.Deployment{
+			{
+				Id:          "api-prod",
+				Application: &pb.Ref_Application{Project: "api", Application: "app"},
+				Workspace:   &pb.Ref_Workspace{Workspace: "prod"},
+			},
+			{
+				Id:          "web-dev",
+				Application: &pb.Ref_Application{Project: "web", Application: "app"},
+				Workspace:   &pb.Ref_Workspace{Workspace: "dev"},
+			},
+		} {
+			require.NoError(s.state.DeploymentPut(false, serverptypes.TestValidDeployment(t, d)))
+		}
+
+		cases := []struct {
+			Name       string
+			Ctx        context.Context
+			Runner     bool
+			Entrypoint *pb.Token_Entrypoint
+			Code       codes.Code
+		}{
+			{"read can't mint runner", readCtx, true, nil, codes.PermissionDenied},
+			{"read can't mint entrypoint", readCtx, false,
+				&pb.Token_Entrypoint{DeploymentId: "api-prod"}, codes.PermissionDenied},
+			{"deploy can't mint runner", ctx, true, nil, codes.PermissionDenied},
+			{"deploy can't mint entrypoint of another project", ctx, false,
+				&pb.Token_Entrypoint{DeploymentId: "api-prod"}, codes.PermissionDenied},
+			{"deploy mints entrypoint of its project", ctx, false,
+				&pb.Token_Entrypoint{DeploymentId: "web-dev"}, codes.OK},
+			{"entrypoint of a missing deployment", ctx, false,
+				&pb.Token_Entrypoint{DeploymentId: "missing"}, codes.NotFound},
+		}
+
+		for _, tt := range cases {
+			_, err := s.GenerateInviteToken(tt.Ctx, &pb.InviteTokenRequest{
+				Duration:   "1h",
+				Runner:     tt.Runner,
+				Entrypoint: tt.Entrypoint,
+			})
+			require.Equal(tt.Code, status.Code(err), tt.Name)
+
+			if tt.Entrypoint == nil {
+				_, err := s.GenerateLoginToken(tt.Ctx, &pb.LoginTokenRequest{Runner: tt.Runner})
+				require.Equal(tt.Code, status.Code(err), tt.Name)
+			}
+		}
+	})
+
+	t.Run("entrypoint tokens are bound to their deployment", func(t *testing.T) {
+		require := require.New(t)
+
+		for _, inst := range []*state.Instance{
+			{Id: "I1", DeploymentId: "A", Project: "p", Application: "a", Workspace: "default"},
+			{Id: "I2", DeploymentId: "B", Project: "p", Application: "a", Workspace: "default"},
+		} {
+			require.NoError(s.state.InstanceCreate(inst))
+		}
+
+		token, err := s.NewLoginToken(DefaultKeyId, nil, &pb.Token_Entrypoint{DeploymentId: "A"})
+		require.NoError(err)
+		ctx := authCtx(token)
+
+		health := &pb.Instance_Health{}
+		_, err = s.EntrypointHealth(ctx, &pb.EntrypointHealthRequest{InstanceId: "I1", Health: health})
+		require.NoError(err)
+		_, err = s.EntrypointHealth(ctx, &pb.EntrypointHealthRequest{InstanceId: "I2", Health: health})
+		require.Equal(codes.PermissionDenied, status.Code(err))
+
+		require.NoError(authEntrypoint(ctx, "A"))
+		require.Equal(codes.PermissionDenied, status.Code(authEntrypoint(ctx, "B")))
+	})
+
+	t.Run("set user permissions", func(t *testing.T) {
+		require := require.New(t)
+
+		user, err := s.SetUserPermissions(context.Background(), &pb.SetUserPermissionsRequest{
+			UserId:      "alice",
+			Permissions: []*pb.Permission{{Project: "db", Verb: pb.Permission_ADMIN}},
+		})
+		require.NoError(err)
+		require.Len(user.Permissions, 1)
+
+		// Existing tokens use the new permissions
+		ctx := authCtx(token)
+		require.NoError(s.Authorize(ctx, "QueueJob", queue("db", "default")))
+		require.Error(s.Authorize(ctx, "QueueJob", queue("web", "default")))
+
+		_, err = s.SetUserPermissions(context.Background(), &pb.SetUserPermissionsRequest{
+			UserId: "bob",
+		})
+		require.Equal(codes.NotFound, status.Code(err))
+	})
+}
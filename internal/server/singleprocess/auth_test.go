@@ -16,6 +16,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
 )
 
 func TestServiceAuth(t *testing.T) {
@@ -155,6 +156,168 @@ func TestServiceAuth(t *testing.T) {
 		_, err = s.ExchangeInvite(DefaultKeyId, invite)
 		require.Error(t, err)
 	})
+
+	t.Run("user tokens are only valid for existing users", func(t *testing.T) {
+		s := impl.(*service)
+
+		id := oidcUserId("https://example.com", "alice")
+		token, err := s.newUserLoginToken(DefaultKeyId, &pb.Token{User: id}, time.Hour, nil)
+		require.NoError(t, err)
+
+		_, body, err := s.DecodeToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, id, body.User)
+		assert.NotNil(t, body.ValidUntil)
+
+		err = s.Authenticate(context.Background(), token, "test", nil)
+		require.Error(t, err)
+
+		require.NoError(t, s.state.UserPut(&pb.User{Id: id}))
+		err = s.Authenticate(context.Background(), token, "test", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("tokens without a duration get the default TTL", func(t *testing.T) {
+		s := impl.(*service)
+
+		for _, d := range []string{"", "0s"} {
+			resp, err := s.GenerateLoginToken(context.Background(), &pb.LoginTokenRequest{Duration: d})
+			require.NoError(t, err)
+
+			_, body, err := s.DecodeToken(resp.Token)
+			require.NoError(t, err)
+			require.NotNil(t, body.ValidUntil, d)
+			until := time.Unix(body.ValidUntil.Seconds, 0)
+			assert.WithinDuration(t, time.Now().Add(defaultLoginTokenTTL), until, time.Minute, d)
+		}
+
+		_, err := s.GenerateLoginToken(context.Background(), &pb.LoginTokenRequest{Duration: "-1h"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("tokens of OIDC users always expire", func(t *testing.T) {
+		s := impl.(*service)
+
+		id := oidcUserId("https://example.com", "bob")
+		require.NoError(t, s.state.UserPut(&pb.User{Id: id}))
+		login, err := s.newUserLoginToken(DefaultKeyId, &pb.Token{User: id}, time.Hour, nil)
+		require.NoError(t, err)
+		ctx, err := s.AuthContext(context.Background(), login)
+		require.NoError(t, err)
+
+		validUntil := func(token string) time.Time {
+			_, body, err := s.DecodeToken(token)
+			require.NoError(t, err)
+			require.NotNil(t, body.ValidUntil)
+			return time.Unix(body.ValidUntil.Seconds, 0)
+		}
+
+		// Tokens without a duration or with a longer one are valid for
+		// as long as the token of the request at most.
+		for _, d := range []string{"", "1000h"} {
+			resp, err := s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{Duration: d})
+			require.NoError(t, err)
+			assert.False(t, validUntil(resp.Token).After(validUntil(login)), d)
+		}
+
+		_, err = s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{Duration: "0s"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		// Invites become tokens with the OIDC token TTL
+		invite, err := s.newUserInviteToken(time.Hour, DefaultKeyId, &pb.Token{User: id}, nil)
+		require.NoError(t, err)
+		token, err := s.ExchangeInvite(DefaultKeyId, invite)
+		require.NoError(t, err)
+		assert.False(t, validUntil(token).After(time.Now().Add(defaultOIDCTokenTTL)))
+	})
+
+	t.Run("tokens are recorded and can be revoked", func(t *testing.T) {
+		s := impl.(*service)
+		ctx := context.Background()
+
+		resp, err := s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{
+			Duration: "1h",
+			Name:     "ci",
+		})
+		require.NoError(t, err)
+		require.NoError(t, s.Authenticate(ctx, resp.Token, "test", nil))
+
+		list, err := s.ListTokens(ctx, &pb.ListTokensRequest{})
+		require.NoError(t, err)
+
+		var info *pb.TokenInfo
+		for _, tok := range list.Tokens {
+			if tok.Name == "ci" {
+				info = tok
+			}
+		}
+		require.NotNil(t, info)
+		assert.NotNil(t, info.ValidUntil)
+		assert.Nil(t, info.RevokeTime)
+
+		_, err = s.RevokeToken(ctx, &pb.RevokeTokenRequest{
+			Target: &pb.RevokeTokenRequest_Id{Id: info.Id},
+		})
+		require.NoError(t, err)
+		require.Error(t, s.Authenticate(ctx, resp.Token, "test", nil))
+
+		// Invites can be revoked before they're exchanged
+		invite, err := s.NewInviteToken(time.Hour, DefaultKeyId, nil, nil)
+		require.NoError(t, err)
+		_, err = s.RevokeToken(ctx, &pb.RevokeTokenRequest{
+			Target: &pb.RevokeTokenRequest_Token{Token: invite},
+		})
+		require.NoError(t, err)
+		_, err = s.ExchangeInvite(DefaultKeyId, invite)
+		require.Error(t, err)
+	})
+
+	t.Run("runner tokens can only access runner endpoints", func(t *testing.T) {
+		s := impl.(*service)
+		ctx := context.Background()
+
+		resp, err := s.GenerateLoginToken(ctx, &pb.LoginTokenRequest{Runner: true})
+		require.NoError(t, err)
+
+		require.NoError(t, s.Authenticate(ctx, resp.Token, "RunnerConfig", nil))
+		require.NoError(t, s.Authenticate(ctx, resp.Token, "UpsertDeployment", nil))
+		require.NoError(t, s.Authenticate(ctx, resp.Token, "GetJob", nil))
+		require.Error(t, s.Authenticate(ctx, resp.Token, "QueueJob", nil))
+		require.Error(t, s.Authenticate(ctx, resp.Token, "GenerateLoginToken", nil))
+		for _, endpoint := range []string{
+			"UpsertOrganization", "UpsertWebhook", "UpsertTrigger",
+			"UpsertNotificationSink", "UpsertProject", "GetConfigSource",
+			"ListUsers",
+		} {
+			require.Error(t, s.Authenticate(ctx, resp.Token, endpoint, nil), endpoint)
+		}
+
+		// Runners can only invite entrypoints
+		require.NoError(t, s.state.DeploymentPut(false, serverptypes.TestValidDeployment(t, &pb.Deployment{
+			Id: "A",
+		})))
+		ctx, err = s.AuthContext(ctx, resp.Token)
+		require.NoError(t, err)
+		_, err = s.GenerateInviteToken(ctx, &pb.InviteTokenRequest{Duration: "1h"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = s.GenerateInviteToken(ctx, &pb.InviteTokenRequest{
+			Duration:   "1h",
+			Entrypoint: &pb.Token_Entrypoint{DeploymentId: "A"},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("OIDC endpoints require OIDC to be configured", func(t *testing.T) {
+		s := impl.(*service)
+
+		_, err := s.GetOIDCAuthURL(context.Background(), &pb.GetOIDCAuthURLRequest{
+			RedirectUri: DefaultOIDCRedirectURIs[0],
+			State:       "state",
+			Nonce:       "0123456789abcdef",
+		})
+		require.Error(t, err)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
 }
 
 func TestServiceBootstrapToken(t *testing.T) {
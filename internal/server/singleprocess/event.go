@@ -0,0 +1,124 @@
+package singleprocess
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/ptypes"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
+)
+
+// eventBus delivers the events of the server to subscribers such as the
+// notification sinks. Publishing never blocks, so subscribers that don't
+// keep up miss events. The zero value is ready to use.
+type eventBus struct {
+	lock sync.Mutex
+	subs map[chan *pb.Event]struct{}
+}
+
+// Publish sends the event to all subscribers.
+func (b *eventBus) Publish(ev *pb.Event) {
+	if ev.Time == nil {
+		ev.Time = ptypes.TimestampNow()
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel that receives the events published from now
+// on. Up to size events are buffered. The returned function must be called
+// to unsubscribe, after which the channel is closed.
+func (b *eventBus) Subscribe(size int) (<-chan *pb.Event, func()) {
+	ch := make(chan *pb.Event, size)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.subs == nil {
+		b.subs = map[chan *pb.Event]struct{}{}
+	}
+	b.subs[ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.lock.Lock()
+			defer b.lock.Unlock()
+			delete(b.subs, ch)
+			close(ch)
+		})
+	}
+}
+
+// publishJob publishes an event for the job. This is called by the state
+// whenever the state of a job changes.
+func (s *service) publishJob(job *pb.Job) {
+	s.events.Publish(&pb.Event{
+		Type:        pb.Event_JOB,
+		Application: job.Application,
+		Workspace:   job.Workspace,
+		Subject:     &pb.Event_Job{Job: job},
+	})
+}
+
+// publishDeployment publishes an event for the deployment if it has just
+// completed or failed. prev is the deployment before it was updated, or
+// nil if it was just created.
+func (s *service) publishDeployment(prev, d *pb.Deployment) {
+	if !statusCompleted(prev.GetStatus(), d.Status) {
+		return
+	}
+
+	s.events.Publish(&pb.Event{
+		Type:        pb.Event_DEPLOYMENT,
+		Application: d.Application,
+		Workspace:   d.Workspace,
+		Subject:     &pb.Event_Deployment{Deployment: d},
+	})
+}
+
+// publishRelease publishes an event for the release if it has just
+// completed or failed. prev is the release before it was updated, or nil
+// if it was just created.
+func (s *service) publishRelease(prev, r *pb.Release) {
+	if !statusCompleted(prev.GetStatus(), r.Status) {
+		return
+	}
+
+	s.events.Publish(&pb.Event{
+		Type:        pb.Event_RELEASE,
+		Application: r.Application,
+		Workspace:   r.Workspace,
+		Subject:     &pb.Event_Release{Release: r},
+	})
+}
+
+// publishInstance publishes an event of the type for the instance when its
+// entrypoint connects or disconnects.
+func (s *service) publishInstance(typ pb.Event_Type, rec *state.Instance) {
+	inst := rec.Proto()
+	s.events.Publish(&pb.Event{
+		Type:        typ,
+		Application: inst.Application,
+		Workspace:   inst.Workspace,
+		Subject:     &pb.Event_Instance{Instance: inst},
+	})
+}
+
+// statusCompleted returns true if the operation status changed from prev
+// to a completed state.
+func statusCompleted(prev, current *pb.Status) bool {
+	switch current.GetState() {
+	case pb.Status_SUCCESS, pb.Status_ERROR:
+		return prev.GetState() != current.GetState()
+	default:
+		return false
+	}
+}
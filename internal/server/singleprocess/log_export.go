@@ -0,0 +1,268 @@
+package singleprocess
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-hclog"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var (
+	// logExportQueue is how many batches from the entrypoints are queued
+	// for export. Batches are dropped if the sinks fall behind further.
+	logExportQueue = 1024
+
+	// logExportBatchMax is how many lines are sent to the sinks at once and
+	// logExportInterval how long lines wait for a batch to fill up.
+	logExportBatchMax = 500
+	logExportInterval = 1 * time.Second
+
+	// logExportTimeout is how long a sink can take to export a batch.
+	logExportTimeout = 30 * time.Second
+)
+
+// logSink is a destination that log lines are exported to.
+type logSink interface {
+	// Export sends the lines, which are ordered as they were received.
+	Export(ctx context.Context, lines []*pb.GetLogsResponse_Line) error
+
+	// Close closes the sink after the last export.
+	Close() error
+}
+
+// logExporter forwards the log lines of the entrypoints to the sinks in
+// the background. A nil exporter exports nothing so that callers don't
+// need to check if exporting is enabled.
+type logExporter struct {
+	log   hclog.Logger
+	sinks []logSink
+	ch    chan []*pb.GetLogsResponse_Line
+	done  chan struct{}
+
+	lock    sync.Mutex
+	closed  bool
+	dropped int
+}
+
+// initLogExport opens the sinks that log lines are exported to. This can
+// be called again to reload the configuration, in which case the previous
+// sinks are closed once their queued lines are exported.
+func (s *service) initLogExport(cfg *configpkg.LogExport) error {
+	var sinks []logSink
+	if cfg.Syslog {
+		tag := cfg.SyslogTag
+		if tag == "" {
+			tag = "waypoint"
+		}
+
+		w, err := logExportSyslog(tag)
+		if err != nil {
+			return fmt.Errorf("error connecting to syslog for log export: %s", err)
+		}
+
+		sinks = append(sinks, &logSyslogSink{w: w})
+	}
+
+	if cfg.Loki != nil {
+		sink, err := newLogLokiSink(cfg.Loki)
+		if err != nil {
+			closeLogSinks(sinks)
+			return err
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.CloudWatch != nil {
+		sink, err := newLogCloudWatchSink(cfg.CloudWatch)
+		if err != nil {
+			closeLogSinks(sinks)
+			return err
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	var e *logExporter
+	if len(sinks) > 0 {
+		e = newLogExporter(s.log.Named("log_export"), sinks)
+	}
+
+	s.reloadLock.Lock()
+	old := s.logExport
+	s.logExport = e
+	s.reloadLock.Unlock()
+
+	old.Close()
+	return nil
+}
+
+// exportLogs queues the lines for export if it is enabled.
+func (s *service) exportLogs(lines []*pb.GetLogsResponse_Line) {
+	s.reloadLock.RLock()
+	e := s.logExport
+	s.reloadLock.RUnlock()
+
+	e.Export(lines)
+}
+
+// newLogExporter starts exporting to the sinks.
+func newLogExporter(log hclog.Logger, sinks []logSink) *logExporter {
+	e := &logExporter{
+		log:   log,
+		sinks: sinks,
+		ch:    make(chan []*pb.GetLogsResponse_Line, logExportQueue),
+		done:  make(chan struct{}),
+	}
+	go e.run()
+
+	return e
+}
+
+// Export queues the lines for export. This doesn't block, the lines are
+// dropped if the queue is full.
+func (e *logExporter) Export(lines []*pb.GetLogsResponse_Line) {
+	if e == nil || len(lines) == 0 {
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.closed {
+		return
+	}
+
+	select {
+	case e.ch <- lines:
+	default:
+		if e.dropped == 0 {
+			e.log.Warn("log export queue is full, lines are dropped until the sinks catch up")
+		}
+		e.dropped += len(lines)
+	}
+}
+
+// Close exports the queued lines and closes the sinks.
+func (e *logExporter) Close() {
+	if e == nil {
+		return
+	}
+
+	e.lock.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.ch)
+	}
+	e.lock.Unlock()
+
+	<-e.done
+}
+
+func (e *logExporter) run() {
+	defer close(e.done)
+	defer closeLogSinks(e.sinks)
+
+	tick := time.NewTicker(logExportInterval)
+	defer tick.Stop()
+
+	var batch []*pb.GetLogsResponse_Line
+	for {
+		select {
+		case lines, ok := <-e.ch:
+			if !ok {
+				e.flush(batch)
+				return
+			}
+
+			batch = append(batch, lines...)
+			if len(batch) < logExportBatchMax {
+				continue
+			}
+
+		case <-tick.C:
+		}
+
+		e.flush(batch)
+		batch = nil
+	}
+}
+
+// flush exports the batch to every sink. Failures are logged and the
+// lines aren't retried so a sink that is down doesn't hold up the others.
+func (e *logExporter) flush(batch []*pb.GetLogsResponse_Line) {
+	e.lock.Lock()
+	dropped := e.dropped
+	e.dropped = 0
+	e.lock.Unlock()
+	if dropped > 0 {
+		e.log.Warn("log lines were dropped because the queue was full", "lines", dropped)
+	}
+
+	if len(batch) == 0 {
+		return
+	}
+
+	for _, sink := range e.sinks {
+		ctx, cancel := context.WithTimeout(context.Background(), logExportTimeout)
+		if err := sink.Export(ctx, batch); err != nil {
+			e.log.Warn("error exporting log lines", "sink", fmt.Sprintf("%T", sink), "err", err)
+		}
+		cancel()
+	}
+}
+
+// closeLogSinks closes the sinks.
+func closeLogSinks(sinks []logSink) {
+	for _, sink := range sinks {
+		sink.Close()
+	}
+}
+
+// logLineTime returns the time of a line, or now if it has none.
+func logLineTime(line *pb.GetLogsResponse_Line) time.Time {
+	t, err := ptypes.Timestamp(line.Entry.GetTimestamp())
+	if err != nil {
+		return time.Now()
+	}
+
+	return t
+}
+
+// logSyslogSink sends each line to syslog as a message that starts with
+// the app, deployment and instance of the line.
+type logSyslogSink struct {
+	w io.Writer
+}
+
+func (s *logSyslogSink) Export(ctx context.Context, lines []*pb.GetLogsResponse_Line) error {
+	for _, line := range lines {
+		msg := fmt.Sprintf("%s/%s/%s deployment=%s instance=%s: %s",
+			line.Application.GetProject(),
+			line.Application.GetApplication(),
+			line.Workspace.GetWorkspace(),
+			line.DeploymentId,
+			line.InstanceId,
+			line.Entry.GetLine(),
+		)
+		if _, err := s.w.Write([]byte(msg)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *logSyslogSink) Close() error {
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
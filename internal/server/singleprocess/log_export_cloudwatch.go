@@ -0,0 +1,144 @@
+package singleprocess
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// logCloudWatchSink writes lines to CloudWatch Logs with a stream for each
+// deployment instance.
+type logCloudWatchSink struct {
+	client *cloudwatchlogs.CloudWatchLogs
+	group  string
+
+	// tokens are the sequence tokens of the streams that were written to,
+	// which the next write to the stream must have. Exports don't run
+	// concurrently so this isn't locked.
+	tokens map[string]*string
+}
+
+func newLogCloudWatchSink(cfg *configpkg.LogExportCloudWatch) (*logCloudWatchSink, error) {
+	if cfg.Group == "" {
+		return nil, fmt.Errorf("log export to CloudWatch requires a log group")
+	}
+
+	awsCfg := aws.NewConfig()
+	if cfg.Region != "" {
+		awsCfg = awsCfg.WithRegion(cfg.Region)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsCfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error configuring AWS for log export: %s", err)
+	}
+
+	return &logCloudWatchSink{
+		client: cloudwatchlogs.New(sess),
+		group:  cfg.Group,
+		tokens: map[string]*string{},
+	}, nil
+}
+
+func (s *logCloudWatchSink) Export(ctx context.Context, lines []*pb.GetLogsResponse_Line) error {
+	var names []string
+	events := map[string][]*cloudwatchlogs.InputLogEvent{}
+	for _, line := range lines {
+		name := strings.Join([]string{
+			line.Application.GetProject(),
+			line.Application.GetApplication(),
+			line.DeploymentId,
+			line.InstanceId,
+		}, "/")
+		if _, ok := events[name]; !ok {
+			names = append(names, name)
+		}
+
+		msg := line.Entry.GetLine()
+		if msg == "" {
+			// CloudWatch doesn't accept empty messages.
+			msg = " "
+		}
+
+		events[name] = append(events[name], &cloudwatchlogs.InputLogEvent{
+			Timestamp: aws.Int64(logLineTime(line).UnixNano() / 1e6),
+			Message:   aws.String(msg),
+		})
+	}
+
+	for _, name := range names {
+		// The events of a request must be in order.
+		streamEvents := events[name]
+		sort.SliceStable(streamEvents, func(i, j int) bool {
+			return *streamEvents[i].Timestamp < *streamEvents[j].Timestamp
+		})
+
+		if err := s.put(ctx, name, streamEvents); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// put writes the events to the stream. The stream is created if it
+// doesn't exist and the write is retried once if the sequence token is
+// out of date, such as after the server restarts.
+func (s *logCloudWatchSink) put(
+	ctx context.Context,
+	name string,
+	events []*cloudwatchlogs.InputLogEvent,
+) error {
+	for attempt := 0; ; attempt++ {
+		resp, err := s.client.PutLogEventsWithContext(ctx, &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(s.group),
+			LogStreamName: aws.String(name),
+			LogEvents:     events,
+			SequenceToken: s.tokens[name],
+		})
+		if err == nil {
+			s.tokens[name] = resp.NextSequenceToken
+			return nil
+		}
+		if attempt > 0 {
+			return err
+		}
+
+		switch err := err.(type) {
+		case *cloudwatchlogs.InvalidSequenceTokenException:
+			s.tokens[name] = err.ExpectedSequenceToken
+			continue
+
+		case *cloudwatchlogs.DataAlreadyAcceptedException:
+			s.tokens[name] = err.ExpectedSequenceToken
+			return nil
+		}
+
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != cloudwatchlogs.ErrCodeResourceNotFoundException {
+			return err
+		}
+
+		_, err = s.client.CreateLogStreamWithContext(ctx, &cloudwatchlogs.CreateLogStreamInput{
+			LogGroupName:  aws.String(s.group),
+			LogStreamName: aws.String(name),
+		})
+		if err != nil {
+			return err
+		}
+		delete(s.tokens, name)
+	}
+}
+
+func (s *logCloudWatchSink) Close() error { return nil }
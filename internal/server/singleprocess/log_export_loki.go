@@ -0,0 +1,117 @@
+package singleprocess
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// logLokiClient is the HTTP client that lines are pushed to Loki with.
+var logLokiClient = &http.Client{Timeout: 10 * time.Second}
+
+// logLokiSink pushes lines to the Loki push API. Each deployment instance
+// is a stream.
+type logLokiSink struct {
+	cfg *configpkg.LogExportLoki
+}
+
+func newLogLokiSink(cfg *configpkg.LogExportLoki) (*logLokiSink, error) {
+	if u, err := url.Parse(cfg.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid Loki URL for log export: %q", cfg.URL)
+	}
+
+	return &logLokiSink{cfg: cfg}, nil
+}
+
+// logLokiPush is the body of a request to the push API.
+type logLokiPush struct {
+	Streams []*logLokiStream `json:"streams"`
+}
+
+type logLokiStream struct {
+	Stream map[string]string `json:"stream"`
+
+	// Values are pairs of the time in nanoseconds and the line.
+	Values [][2]string `json:"values"`
+}
+
+func (s *logLokiSink) Export(ctx context.Context, lines []*pb.GetLogsResponse_Line) error {
+	var push logLokiPush
+	streams := map[string]*logLokiStream{}
+	for _, line := range lines {
+		stream, ok := streams[line.InstanceId]
+		if !ok {
+			labels := map[string]string{}
+			for k, v := range s.cfg.Labels {
+				labels[k] = v
+			}
+			labels["project"] = line.Application.GetProject()
+			labels["app"] = line.Application.GetApplication()
+			labels["workspace"] = line.Workspace.GetWorkspace()
+			labels["deployment_id"] = line.DeploymentId
+			labels["instance_id"] = line.InstanceId
+
+			stream = &logLokiStream{Stream: labels}
+			streams[line.InstanceId] = stream
+			push.Streams = append(push.Streams, stream)
+		}
+
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(logLineTime(line).UnixNano(), 10),
+			line.Entry.GetLine(),
+		})
+	}
+
+	// Loki rejects lines that are older than the previous line of the
+	// stream, so they're sent in order.
+	for _, stream := range push.Streams {
+		sort.SliceStable(stream.Values, func(i, j int) bool {
+			a, _ := strconv.ParseInt(stream.Values[i][0], 10, 64)
+			b, _ := strconv.ParseInt(stream.Values[j][0], 10, 64)
+			return a < b
+		})
+	}
+
+	body, err := json.Marshal(&push)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if s.cfg.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.cfg.TenantID)
+	}
+
+	resp, err := logLokiClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Loki responded with %s: %s",
+			resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+func (s *logLokiSink) Close() error { return nil }
@@ -0,0 +1,86 @@
+package singleprocess
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
+)
+
+func TestLogExportLoki(t *testing.T) {
+	require := require.New(t)
+
+	pushes := make(chan *logLokiPush, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal("tenant", r.Header.Get("X-Scope-OrgID"))
+
+		var push logLokiPush
+		require.NoError(json.NewDecoder(r.Body).Decode(&push))
+		pushes <- &push
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sink, err := newLogLokiSink(&configpkg.LogExportLoki{
+		URL:      srv.URL,
+		TenantID: "tenant",
+		Labels:   map[string]string{"env": "prod"},
+	})
+	require.NoError(err)
+	e := newLogExporter(hclog.L(), []logSink{sink})
+
+	inst := &state.Instance{
+		Id:           "I",
+		DeploymentId: "D",
+		Project:      "p",
+		Application:  "a",
+		Workspace:    "default",
+	}
+	now := time.Now()
+	var entries []*pb.LogBatch_Entry
+	for i, line := range []string{"second", "first"} {
+		ts, err := ptypes.TimestampProto(now.Add(-time.Duration(i) * time.Second))
+		require.NoError(err)
+		entries = append(entries, &pb.LogBatch_Entry{Timestamp: ts, Line: line})
+	}
+	e.Export(inst.LogLines(entries))
+
+	// Closing exports the queued lines
+	e.Close()
+
+	var push *logLokiPush
+	select {
+	case push = <-pushes:
+	default:
+		t.Fatal("lines weren't pushed")
+	}
+	require.Len(push.Streams, 1)
+
+	stream := push.Streams[0]
+	require.Equal("prod", stream.Stream["env"])
+	require.Equal("D", stream.Stream["deployment_id"])
+	require.Len(stream.Values, 2)
+	require.Equal("first", stream.Values[0][1])
+
+	// A closed exporter drops lines
+	e.Export(inst.LogLines(entries))
+
+	// A nil exporter does nothing
+	var nilExporter *logExporter
+	nilExporter.Export(inst.LogLines(entries))
+	nilExporter.Close()
+}
+
+func TestLogExportLoki_invalidURL(t *testing.T) {
+	_, err := newLogLokiSink(&configpkg.LogExportLoki{URL: "loki:3100"})
+	require.Error(t, err)
+}
@@ -0,0 +1,238 @@
+package singleprocess
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/server"
+)
+
+var (
+	// rateLimitDefaultEndpoints are the endpoints that are limited if the
+	// configuration doesn't list any. These queue jobs or hold streams
+	// open and are the most expensive for the server.
+	rateLimitDefaultEndpoints = []string{"QueueJob", "GetJobStream", "GetLogStream"}
+
+	// rateLimitMaxKeys is how many limiters are kept. Once there are this
+	// many, the least recently used one is removed for each new one.
+	rateLimitMaxKeys = 10000
+
+	// rateLimitAuthFailures is how many calls from an address can fail
+	// authentication per rateLimitAuthWindow before all calls from it are
+	// rejected until the window ends. This is always enabled so tokens
+	// can't be guessed.
+	rateLimitAuthFailures = 10
+	rateLimitAuthWindow   = time.Minute
+)
+
+// rateLimits keeps the limiters of each token and IP address.
+type rateLimits struct {
+	cfg       *configpkg.RateLimit
+	endpoints map[string]struct{}
+
+	lock     sync.Mutex
+	limiters rateLimitKeys
+}
+
+// authFailures keeps the failed authentications of each IP address.
+type authFailures struct {
+	lock    sync.Mutex
+	windows rateLimitKeys
+}
+
+// authFailureWindow counts the failed authentications of an address since
+// start.
+type authFailureWindow struct {
+	start time.Time
+	count int
+}
+
+// rateLimitKeys keeps a value for each key, up to rateLimitMaxKeys. The
+// least recently used values are removed first. The zero value is ready to
+// use. This isn't safe for concurrent use.
+type rateLimitKeys struct {
+	lru   list.List
+	items map[string]*list.Element
+}
+
+// rateLimitItem is the value of the elements in rateLimitKeys.lru.
+type rateLimitItem struct {
+	key   string
+	value interface{}
+}
+
+// get returns the value for the key. If there is none, it is set to the
+// result of create.
+func (k *rateLimitKeys) get(key string, create func() interface{}) interface{} {
+	if e, ok := k.items[key]; ok {
+		k.lru.MoveToFront(e)
+		return e.Value.(*rateLimitItem).value
+	}
+
+	if k.items == nil {
+		k.items = map[string]*list.Element{}
+	}
+	for len(k.items) >= rateLimitMaxKeys && k.lru.Len() > 0 {
+		e := k.lru.Back()
+		k.lru.Remove(e)
+		delete(k.items, e.Value.(*rateLimitItem).key)
+	}
+
+	item := &rateLimitItem{key: key, value: create()}
+	k.items[key] = k.lru.PushFront(item)
+	return item.value
+}
+
+// initRateLimit sets up the limiters for cfg. This can be called again to
+// reload the configuration, which resets the limiters.
+func (s *service) initRateLimit(cfg *configpkg.RateLimit) {
+	endpoints := cfg.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = rateLimitDefaultEndpoints
+	}
+
+	l := &rateLimits{
+		cfg:       cfg,
+		endpoints: map[string]struct{}{},
+	}
+	for _, endpoint := range endpoints {
+		l.endpoints[endpoint] = struct{}{}
+	}
+
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+	s.rateLimit = l
+}
+
+// RateLimitAddr implements server.RateLimiter. This rejects all calls from
+// an address that failed authentication too often and limits the calls of
+// each IP address to the configured endpoints.
+func (s *service) RateLimitAddr(ctx context.Context, endpoint string) error {
+	ip := clientIP(ctx)
+	if ip == "" {
+		return nil
+	}
+
+	if s.authFailures.blocked(ip, time.Now()) {
+		return status.Errorf(codes.ResourceExhausted,
+			"too many calls from the address failed authentication, try again later")
+	}
+
+	s.reloadLock.RLock()
+	l := s.rateLimit
+	s.reloadLock.RUnlock()
+	if l == nil || l.cfg.IPRate <= 0 {
+		return nil
+	}
+	if _, ok := l.endpoints[endpoint]; !ok {
+		return nil
+	}
+
+	if !l.allow("ip/"+ip+"/"+endpoint, l.cfg.IPRate, l.cfg.IPBurst) {
+		return status.Errorf(codes.ResourceExhausted,
+			"rate limit of the address for %s exceeded, try again later", endpoint)
+	}
+
+	return nil
+}
+
+// RateLimit implements server.RateLimiter and limits the calls of each
+// token to the configured endpoints.
+func (s *service) RateLimit(ctx context.Context, endpoint string) error {
+	s.reloadLock.RLock()
+	l := s.rateLimit
+	s.reloadLock.RUnlock()
+	if l == nil || l.cfg.TokenRate <= 0 {
+		return nil
+	}
+	if _, ok := l.endpoints[endpoint]; !ok {
+		return nil
+	}
+
+	info := authInfoFromContext(ctx)
+	if info == nil || info.tokenId == "" {
+		return nil
+	}
+
+	if !l.allow("token/"+info.tokenId+"/"+endpoint, l.cfg.TokenRate, l.cfg.TokenBurst) {
+		return status.Errorf(codes.ResourceExhausted,
+			"rate limit of the token for %s exceeded, try again later", endpoint)
+	}
+
+	return nil
+}
+
+// AuthFailed implements server.RateLimiter and counts the failed
+// authentication against the address of the call.
+func (s *service) AuthFailed(ctx context.Context) {
+	if ip := clientIP(ctx); ip != "" {
+		s.authFailures.add(ip, time.Now())
+	}
+}
+
+// allow returns true if a call can be made with the limiter for the key.
+// If burst is zero, it defaults to the rate rounded up.
+func (l *rateLimits) allow(key string, r float64, burst int) bool {
+	if burst <= 0 {
+		burst = int(math.Ceil(r))
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	lim := l.limiters.get(key, func() interface{} {
+		return rate.NewLimiter(rate.Limit(r), burst)
+	}).(*rate.Limiter)
+	return lim.Allow()
+}
+
+// blocked returns true if the address failed authentication too often in
+// the current window.
+func (f *authFailures) blocked(ip string, now time.Time) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	e, ok := f.windows.items[ip]
+	if !ok {
+		return false
+	}
+
+	w := e.Value.(*rateLimitItem).value.(*authFailureWindow)
+	return now.Sub(w.start) < rateLimitAuthWindow && w.count >= rateLimitAuthFailures
+}
+
+// add counts a failed authentication of the address, starting a new
+// window if the last one ended.
+func (f *authFailures) add(ip string, now time.Time) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	w := f.windows.get(ip, func() interface{} {
+		return &authFailureWindow{start: now}
+	}).(*authFailureWindow)
+	if now.Sub(w.start) >= rateLimitAuthWindow {
+		w.start = now
+		w.count = 0
+	}
+	w.count++
+}
+
+// clientIP returns the IP address of the client of the call without the
+// port, or "" if it isn't known.
+func clientIP(ctx context.Context) string {
+	addr := server.ClientAddr(ctx)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
@@ -0,0 +1,165 @@
+package singleprocess
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+)
+
+func TestServiceRateLimit(t *testing.T) {
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)), WithConfig(&configpkg.ServerConfig{
+		RateLimit: &configpkg.RateLimit{
+			TokenRate:  0.001,
+			TokenBurst: 2,
+			IPRate:     0.001,
+			IPBurst:    3,
+		},
+	}))
+	require.NoError(err)
+	s := impl.(*service)
+
+	// tokenCtx returns the context of a call with the token from the address.
+	tokenCtx := func(token, ip string) context.Context {
+		ctx := context.WithValue(context.Background(), authInfoKey{}, &authInfo{tokenId: token})
+		return peer.NewContext(ctx, &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234},
+		})
+	}
+
+	// Each token can make a burst of calls
+	ctx := tokenCtx("a", "10.0.0.1")
+	require.NoError(s.RateLimit(ctx, "QueueJob"))
+	require.NoError(s.RateLimit(ctx, "QueueJob"))
+	err = s.RateLimit(ctx, "QueueJob")
+	require.Error(err)
+	require.Equal(codes.ResourceExhausted, status.Code(err))
+
+	// Endpoints are limited separately and only the configured ones
+	require.NoError(s.RateLimit(ctx, "GetLogStream"))
+	for i := 0; i < 5; i++ {
+		require.NoError(s.RateLimit(ctx, "ListProjects"))
+	}
+
+	// Addresses are limited separately from the tokens
+	for i := 0; i < 3; i++ {
+		require.NoError(s.RateLimitAddr(tokenCtx("b", "10.0.0.1"), "QueueJob"))
+	}
+	require.Error(s.RateLimitAddr(tokenCtx("c", "10.0.0.1"), "QueueJob"))
+	require.NoError(s.RateLimitAddr(tokenCtx("c", "10.0.0.2"), "QueueJob"))
+}
+
+func TestServiceRateLimit_maxKeys(t *testing.T) {
+	defer func(v int) { rateLimitMaxKeys = v }(rateLimitMaxKeys)
+	rateLimitMaxKeys = 2
+
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)), WithConfig(&configpkg.ServerConfig{
+		RateLimit: &configpkg.RateLimit{TokenRate: 0.001, TokenBurst: 1},
+	}))
+	require.NoError(err)
+	s := impl.(*service)
+
+	ctx := func(token string) context.Context {
+		return context.WithValue(context.Background(), authInfoKey{}, &authInfo{tokenId: token})
+	}
+
+	// Use the limit of a and b, using a last
+	require.NoError(s.RateLimit(ctx("b"), "QueueJob"))
+	require.NoError(s.RateLimit(ctx("a"), "QueueJob"))
+	require.Error(s.RateLimit(ctx("a"), "QueueJob"))
+
+	// c removes the limiter of b, which was used least recently
+	require.NoError(s.RateLimit(ctx("c"), "QueueJob"))
+	require.Len(s.rateLimit.limiters.items, 2)
+	require.Error(s.RateLimit(ctx("a"), "QueueJob"))
+	require.NoError(s.RateLimit(ctx("b"), "QueueJob"))
+}
+
+func TestServiceRateLimit_authFailures(t *testing.T) {
+	require := require.New(t)
+
+	// Limits aren't configured, auth failures are always limited
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+
+	ctx := func(ip string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234},
+		})
+	}
+
+	for i := 0; i < rateLimitAuthFailures; i++ {
+		require.NoError(s.RateLimitAddr(ctx("10.0.0.1"), "ListProjects"))
+		s.AuthFailed(ctx("10.0.0.1"))
+	}
+
+	// All endpoints are blocked for the address
+	err = s.RateLimitAddr(ctx("10.0.0.1"), "ListProjects")
+	require.Error(err)
+	require.Equal(codes.ResourceExhausted, status.Code(err))
+	require.Error(s.RateLimitAddr(ctx("10.0.0.1"), "GetVersionInfo"))
+
+	// Other addresses aren't
+	require.NoError(s.RateLimitAddr(ctx("10.0.0.2"), "ListProjects"))
+
+	// The block ends with the window
+	e := s.authFailures.windows.items["10.0.0.1"]
+	e.Value.(*rateLimitItem).value.(*authFailureWindow).start = time.Now().Add(-rateLimitAuthWindow)
+	require.NoError(s.RateLimitAddr(ctx("10.0.0.1"), "ListProjects"))
+}
+
+func TestServiceRateLimit_disabled(t *testing.T) {
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(s.RateLimitAddr(context.Background(), "QueueJob"))
+		require.NoError(s.RateLimit(context.Background(), "QueueJob"))
+	}
+}
+
+func TestServiceRateLimit_reload(t *testing.T) {
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+
+	// Enable limits while running
+	require.NoError(s.Reload(&configpkg.ServerReload{
+		RateLimit: &configpkg.RateLimit{IPRate: 0.001, IPBurst: 1},
+	}))
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+	})
+	require.NoError(s.RateLimitAddr(ctx, "QueueJob"))
+	require.Error(s.RateLimitAddr(ctx, "QueueJob"))
+
+	// Reloading without the block leaves the limits unchanged
+	require.NoError(s.Reload(&configpkg.ServerReload{}))
+	require.Error(s.RateLimitAddr(ctx, "QueueJob"))
+
+	// Reloading resets the limiters with the new settings
+	require.NoError(s.Reload(&configpkg.ServerReload{
+		RateLimit: &configpkg.RateLimit{IPRate: 0.001, IPBurst: 2},
+	}))
+	require.NoError(s.RateLimitAddr(ctx, "QueueJob"))
+	require.NoError(s.RateLimitAddr(ctx, "QueueJob"))
+	require.Error(s.RateLimitAddr(ctx, "QueueJob"))
+}
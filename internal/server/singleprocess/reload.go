@@ -0,0 +1,50 @@
+package singleprocess
+
+import (
+	"fmt"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+)
+
+// Reloader is implemented by the service to apply new settings while the
+// server runs, without dropping the streams of runners and entrypoints.
+type Reloader interface {
+	Reload(*configpkg.ServerReload) error
+}
+
+// Reload implements Reloader. Only the settings with a block in cfg are
+// changed and the others are left as they are. If a setting fails to
+// apply, its previous value is still used.
+func (s *service) Reload(cfg *configpkg.ServerReload) error {
+	if cfg.URL != nil {
+		if err := s.initURL(cfg.URL); err != nil {
+			return fmt.Errorf("error reloading the URL service: %s", err)
+		}
+	}
+
+	if cfg.Audit != nil {
+		if err := s.initAudit(cfg.Audit); err != nil {
+			return err
+		}
+	}
+
+	if cfg.RateLimit != nil {
+		s.initRateLimit(cfg.RateLimit)
+	}
+
+	if cfg.ExecRecording != nil {
+		if err := s.initExecRecording(cfg.ExecRecording); err != nil {
+			return err
+		}
+	}
+
+	if cfg.LogExport != nil {
+		if err := s.initLogExport(cfg.LogExport); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var _ Reloader = (*service)(nil)
@@ -1,15 +1,17 @@
 package singleprocess
 
 import (
-	"crypto/tls"
+	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
 
 	"github.com/hashicorp/go-hclog"
-	grpctoken "github.com/hashicorp/horizon/pkg/grpc/token"
 	wphznpb "github.com/hashicorp/waypoint-hzn/pkg/pb"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 
 	configpkg "github.com/hashicorp/waypoint/internal/config"
 	"github.com/hashicorp/waypoint/internal/server"
@@ -28,10 +30,74 @@ type service struct {
 	// id is our unique server ID.
 	id string
 
+	// reloadLock protects the settings that can be reloaded while the
+	// server runs: the URL service, rate limits, exec recording and log
+	// export.
+	reloadLock sync.RWMutex
+
 	// urlConfig is not nil if the URL service is enabled. This is guaranteed
-	// to have the configs set.
-	urlConfig *configpkg.URL
-	urlClient wphznpb.WaypointHznClient
+	// to have the configs set. These must be accessed with url().
+	urlConfig      *configpkg.URL
+	urlClient      wphznpb.WaypointHznClient
+	urlConn        *grpc.ClientConn
+	acceptURLTerms bool
+
+	// oidc is not nil if OIDC login is enabled.
+	oidc *oidcConfig
+
+	// loginTokenTTL is how long the tokens of GenerateLoginToken are valid
+	// if the request has no duration.
+	loginTokenTTL time.Duration
+
+	// audit exports audit events to the configured sinks.
+	audit auditLog
+
+	// rateLimit is not nil if calls to expensive endpoints are limited.
+	// This is protected by reloadLock.
+	rateLimit *rateLimits
+
+	// authFailures counts the failed authentications of each address so
+	// that addresses guessing tokens are blocked.
+	authFailures authFailures
+
+	// execRecording is not nil if exec sessions are recorded. This is
+	// protected by reloadLock.
+	execRecording *execRecordingConfig
+
+	// execRecordingRetention is how long recordings are kept. Recordings
+	// are pruned even if sessions aren't recorded anymore. This is
+	// protected by reloadLock.
+	execRecordingRetention time.Duration
+
+	// logMaxAge is how long log lines are kept in the history.
+	logMaxAge time.Duration
+
+	// events delivers the events of the server, such as job state
+	// changes, to notification sinks.
+	events eventBus
+
+	// logExport is not nil if log lines are exported. This is protected
+	// by reloadLock.
+	logExport *logExporter
+
+	// log is the logger for work that isn't part of a request.
+	log hclog.Logger
+
+	// leaderAddr is the address of this server that the followers of a
+	// shared database send runners to while this server is the leader.
+	leaderAddr string
+
+	// drainCh is closed when the server starts to shut down, after which
+	// no more jobs are queued or assigned. drainEntrypointsCh is closed
+	// once the running jobs are done, to disconnect the entrypoints.
+	drainCh              chan struct{}
+	drainOnce            sync.Once
+	drainEntrypointsCh   chan struct{}
+	drainEntrypointsOnce sync.Once
+
+	// ctx is cancelled when the server exits. This is used for work that
+	// continues after the request that started it, such as webhook runs.
+	ctx context.Context
 }
 
 // New returns a Waypoint server implementation that uses BotlDB plus
@@ -50,12 +116,32 @@ func New(opts ...Option) (pb.WaypointServer, error) {
 		log = hclog.L()
 	}
 
+	s.log = log
+	s.drainCh = make(chan struct{})
+	s.drainEntrypointsCh = make(chan struct{})
+	s.ctx = cfg.ctx
+	if s.ctx == nil {
+		s.ctx = context.Background()
+	}
+
+	// Load the keyring that encrypts the secrets in our state
+	var stateOpts []state.Option
+	if scfg := cfg.serverConfig; scfg != nil && scfg.KeyringPath != "" {
+		k, err := state.LoadKeyring(scfg.KeyringPath)
+		if err != nil {
+			return nil, err
+		}
+
+		stateOpts = append(stateOpts, state.WithKeyring(k))
+	}
+
 	// Initialize our state
-	st, err := state.New(log, cfg.db)
+	st, err := state.New(log, cfg.db, stateOpts...)
 	if err != nil {
 		return nil, err
 	}
 	s.state = st
+	st.SetJobStateHook(s.publishJob)
 
 	// If we don't have a server ID, set that.
 	id, err := st.ServerIdGet()
@@ -74,34 +160,74 @@ func New(opts ...Option) (pb.WaypointServer, error) {
 	}
 	s.id = id
 
+	// Restore the instances that were connected when the server last shut
+	// down, so that they're known until their entrypoints reconnect.
+	if err := s.restoreInstances(s.ctx, log.Named("instance")); err != nil {
+		return nil, err
+	}
+
 	// Setup our URL service config if it is enabled.
-	if scfg := cfg.serverConfig; scfg != nil && scfg.URL != nil && scfg.URL.Enabled {
-		// Set our config
-		s.urlConfig = scfg.URL
+	s.acceptURLTerms = cfg.acceptUrlTerms
+	if scfg := cfg.serverConfig; scfg != nil && scfg.URL != nil {
+		if err := s.initURL(scfg.URL); err != nil {
+			return nil, err
+		}
+	}
 
-		// If we have no API token, get our guest account token.
-		if scfg.URL.APIToken == "" {
-			if err := s.initURLGuestAccount(cfg.acceptUrlTerms); err != nil {
-				return nil, err
-			}
+	s.loginTokenTTL = defaultLoginTokenTTL
+	if scfg := cfg.serverConfig; scfg != nil && scfg.LoginTokenTTL != "" {
+		d, err := time.ParseDuration(scfg.LoginTokenTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid login token TTL: %s", err)
 		}
+		if d <= 0 {
+			return nil, fmt.Errorf("login token TTL must be positive")
+		}
+
+		s.loginTokenTTL = d
+	}
 
-		// Now that we have a token, connect to the API service.
-		opts := []grpc.DialOption{
-			grpc.WithPerRPCCredentials(grpctoken.Token(scfg.URL.APIToken)),
+	// Setup OIDC login if it is configured.
+	if scfg := cfg.serverConfig; scfg != nil && scfg.OIDC != nil {
+		if err := s.initOIDC(scfg.OIDC); err != nil {
+			return nil, err
 		}
-		if scfg.URL.APIInsecure {
-			opts = append(opts, grpc.WithInsecure())
-		} else {
-			opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	}
+
+	// Setup exporting the audit log if it is configured.
+	if scfg := cfg.serverConfig; scfg != nil && scfg.Audit != nil {
+		if err := s.initAudit(scfg.Audit); err != nil {
+			return nil, err
 		}
+	}
 
-		conn, err := grpc.Dial(scfg.URL.APIAddress, opts...)
-		if err != nil {
+	// Limit calls to expensive endpoints if it is configured.
+	if scfg := cfg.serverConfig; scfg != nil && scfg.RateLimit != nil {
+		s.initRateLimit(scfg.RateLimit)
+	}
+
+	// Record exec sessions if it is configured.
+	s.execRecordingRetention = execRecordingDefaultRetention
+	if scfg := cfg.serverConfig; scfg != nil && scfg.ExecRecording != nil {
+		if err := s.initExecRecording(scfg.ExecRecording); err != nil {
 			return nil, err
 		}
+	}
 
-		s.urlClient = wphznpb.NewWaypointHznClient(conn)
+	// Keep a history of log lines.
+	var logsCfg *configpkg.Logs
+	if scfg := cfg.serverConfig; scfg != nil {
+		logsCfg = scfg.Logs
+	}
+	if err := s.initLogs(logsCfg); err != nil {
+		return nil, err
+	}
+
+	// Export log lines if it is configured.
+	if scfg := cfg.serverConfig; scfg != nil && scfg.LogExport != nil {
+		if err := s.initLogExport(scfg.LogExport); err != nil {
+			return nil, err
+		}
 	}
 
 	// Set specific server config for the deployment entrypoint binaries
@@ -113,22 +239,59 @@ func New(opts ...Option) (pb.WaypointServer, error) {
 			TlsSkipVerify: scfg.CEBConfig.TLSSkipVerify,
 		}
 
-		conf := &pb.ServerConfig{
-			AdvertiseAddrs: []*pb.ServerConfig_AdvertiseAddr{addr},
+		// The other settings, such as the runner profiles, are kept.
+		conf, err := s.state.ServerConfigGet()
+		if err != nil {
+			return nil, err
 		}
+		conf = proto.Clone(conf).(*pb.ServerConfig)
+		conf.AdvertiseAddrs = []*pb.ServerConfig_AdvertiseAddr{addr}
 
 		if err := s.state.ServerConfigSet(conf); err != nil {
 			return nil, err
 		}
 	}
 
+	// Prune the records of old operations, start scheduled triggers, send
+	// notifications and maintain the database in the background for as
+	// long as the server runs.
+	if cfg.ctx != nil {
+		go s.runPrune(cfg.ctx, log.Named("prune"))
+		go s.runTriggers(cfg.ctx, log.Named("trigger"))
+		go s.runPoll(cfg.ctx, log.Named("poll"))
+		go s.runNotifications(cfg.ctx, log.Named("notify"))
+		go s.runMaintenance(cfg.ctx, log.Named("maintenance"))
+		go s.runLogFlush(cfg.ctx, log.Named("logs"))
+	}
+
+	// If the state is shared with other servers, only the leader runs the
+	// singleton duties such as assigning jobs.
+	if db, ok := cfg.db.(state.SharedDB); ok {
+		ctx := cfg.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		// Runners that connect to a follower are sent to the address of
+		// the leader. This isn't the advertised address for entrypoints
+		// since that is usually shared by all the servers.
+		if scfg := cfg.serverConfig; scfg != nil {
+			s.leaderAddr = scfg.LeaderAddr
+		}
+
+		st.SetLeader(false)
+		go s.runLeaderElection(ctx, log.Named("leader"), db)
+		go s.runIndexSync(ctx, log.Named("index-sync"), db)
+	}
+
 	return &s, nil
 }
 
 type config struct {
-	db           *bolt.DB
+	db           state.DB
 	serverConfig *configpkg.ServerConfig
 	log          hclog.Logger
+	ctx          context.Context
 
 	acceptUrlTerms bool
 }
@@ -137,6 +300,15 @@ type Option func(*service, *config) error
 
 // WithDB sets the Bolt DB for use with the server.
 func WithDB(db *bolt.DB) Option {
+	return func(s *service, cfg *config) error {
+		cfg.db = state.BoltDB(db)
+		return nil
+	}
+}
+
+// WithStateDB sets the storage for the server state. This can be used
+// instead of WithDB to store state in a database other than Bolt.
+func WithStateDB(db state.DB) Option {
 	return func(s *service, cfg *config) error {
 		cfg.db = db
 		return nil
@@ -151,6 +323,15 @@ func WithConfig(scfg *configpkg.ServerConfig) Option {
 	}
 }
 
+// WithContext sets the context for background work such as leader election.
+// When it is done, leadership is given up.
+func WithContext(ctx context.Context) Option {
+	return func(s *service, cfg *config) error {
+		cfg.ctx = ctx
+		return nil
+	}
+}
+
 // WithLogger sets the logger for use with the server.
 func WithLogger(log hclog.Logger) Option {
 	return func(s *service, cfg *config) error {
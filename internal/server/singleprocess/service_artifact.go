@@ -74,7 +74,7 @@ func (s *service) GetLatestPushedArtifact(
 	ctx context.Context,
 	req *pb.GetLatestPushedArtifactRequest,
 ) (*pb.PushedArtifact, error) {
-	return s.state.ArtifactLatest(req.Application, req.Workspace)
+	return s.state.ArtifactLatest(req.Application, req.Workspace, req.Labels)
 }
 
 // GetPushedArtifact returns a PushedArtifact based on ID
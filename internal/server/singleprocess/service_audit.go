@@ -0,0 +1,43 @@
+package singleprocess
+
+import (
+	"context"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// ListAuditEvents returns the audit log, newest first.
+func (s *service) ListAuditEvents(
+	ctx context.Context,
+	req *pb.ListAuditEventsRequest,
+) (*pb.ListAuditEventsResponse, error) {
+	limit := int(req.PageSize)
+	if limit <= 0 {
+		limit = defaultAuditPageSize
+	}
+	if limit > maxAuditPageSize {
+		limit = maxAuditPageSize
+	}
+
+	var project string
+	if req.Project != nil {
+		project = req.Project.Project
+	}
+
+	events, next, err := s.state.AuditEventList(project, req.PageToken, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListAuditEventsResponse{
+		Events:        events,
+		NextPageToken: next,
+	}, nil
+}
+
+const (
+	// defaultAuditPageSize is the page size for ListAuditEvents if the
+	// request doesn't set one. Pages are never larger than maxAuditPageSize.
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+)
@@ -15,6 +15,32 @@ func (s *service) UpsertBuild(
 	ctx context.Context,
 	req *pb.UpsertBuildRequest,
 ) (*pb.UpsertBuildResponse, error) {
+	// A retried insert returns the build that the first request created.
+	if req.IdempotencyKey != "" && req.Build.GetId() == "" {
+		var resp *pb.UpsertBuildResponse
+		id, created, err := s.idempotent("build", req.Build.Application, req.IdempotencyKey, func() (string, error) {
+			var err error
+			resp, err = s.UpsertBuild(ctx, &pb.UpsertBuildRequest{Build: req.Build})
+			if err != nil {
+				return "", err
+			}
+
+			return resp.Build.Id, nil
+		})
+		if err != nil || created {
+			return resp, err
+		}
+
+		build, err := s.state.BuildGet(&pb.Ref_Operation{
+			Target: &pb.Ref_Operation_Id{Id: id},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &pb.UpsertBuildResponse{Build: build}, nil
+	}
+
 	result := req.Build
 
 	// If we have no ID, then we're inserting and need to generate an ID.
@@ -41,15 +67,19 @@ func (s *service) ListBuilds(
 	ctx context.Context,
 	req *pb.ListBuildsRequest,
 ) (*pb.ListBuildsResponse, error) {
+	page := &state.ListPage{Size: req.PageSize, Token: req.PageToken}
 	result, err := s.state.BuildList(req.Application,
 		state.ListWithWorkspace(req.Workspace),
 		state.ListWithOrder(req.Order),
+		state.ListWithStatusFilter(req.Status...),
+		state.ListWithStartTime(req.StartTime),
+		state.ListWithPage(page),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.ListBuildsResponse{Builds: result}, nil
+	return &pb.ListBuildsResponse{Builds: result, NextPageToken: page.Next}, nil
 }
 
 func (s *service) GetLatestBuild(
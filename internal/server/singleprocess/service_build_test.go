@@ -48,6 +48,33 @@ func TestServiceBuild(t *testing.T) {
 		require.Equal(pb.Status_RUNNING, result.Status.State)
 	})
 
+	t.Run("create with idempotency key", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := client.UpsertBuild(ctx, &Req{
+			Build:          serverptypes.TestValidBuild(t, nil),
+			IdempotencyKey: "A",
+		})
+		require.NoError(err)
+		id := resp.Build.Id
+
+		// A retry returns the same build
+		resp, err = client.UpsertBuild(ctx, &Req{
+			Build:          serverptypes.TestValidBuild(t, nil),
+			IdempotencyKey: "A",
+		})
+		require.NoError(err)
+		require.Equal(id, resp.Build.Id)
+
+		// Another key creates another build
+		resp, err = client.UpsertBuild(ctx, &Req{
+			Build:          serverptypes.TestValidBuild(t, nil),
+			IdempotencyKey: "B",
+		})
+		require.NoError(err)
+		require.NotEqual(id, resp.Build.Id)
+	})
+
 	t.Run("update non-existent", func(t *testing.T) {
 		require := require.New(t)
 
@@ -61,4 +88,43 @@ func TestServiceBuild(t *testing.T) {
 		require.True(ok)
 		require.Equal(codes.NotFound, st.Code())
 	})
+
+	t.Run("list in pages", func(t *testing.T) {
+		require := require.New(t)
+
+		for i := 0; i < 3; i++ {
+			_, err := client.UpsertBuild(ctx, &Req{
+				Build: serverptypes.TestValidBuild(t, nil),
+			})
+			require.NoError(err)
+		}
+
+		app := serverptypes.TestValidBuild(t, nil).Application
+		all, err := client.ListBuilds(ctx, &pb.ListBuildsRequest{Application: app})
+		require.NoError(err)
+		require.Empty(all.NextPageToken)
+
+		// Reading every page returns all the builds in the same order
+		var ids []string
+		req := &pb.ListBuildsRequest{Application: app, PageSize: 2}
+		for {
+			resp, err := client.ListBuilds(ctx, req)
+			require.NoError(err)
+			require.True(len(resp.Builds) <= 2)
+
+			for _, b := range resp.Builds {
+				ids = append(ids, b.Id)
+			}
+			if resp.NextPageToken == "" {
+				break
+			}
+
+			req.PageToken = resp.NextPageToken
+		}
+
+		require.Len(ids, len(all.Builds))
+		for i, b := range all.Builds {
+			require.Equal(b.Id, ids[i])
+		}
+	})
 }
@@ -2,7 +2,14 @@ package singleprocess
 
 import (
 	"context"
+	"path"
+	"strings"
 
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/configsource"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
@@ -10,6 +17,53 @@ func (s *service) SetConfig(
 	ctx context.Context,
 	req *pb.ConfigSetRequest,
 ) (*pb.ConfigSetResponse, error) {
+	for _, v := range req.Variables {
+		// Runners aren't deployed, so their variables can't be scoped to
+		// workspaces or labels.
+		if _, ok := v.Scope.(*pb.ConfigVar_Runner); ok &&
+			(v.Workspace != nil || len(v.Labels) > 0) {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"runner variable %q can't have a workspace or labels", v.Name)
+		}
+		if _, ok := v.GetRunner().GetTarget().(*pb.Ref_Runner_Labels); ok {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"runner variable %q must target any runner or a runner by ID", v.Name)
+		}
+
+		if v.Dynamic != nil {
+			// Dynamic variables are read by the entrypoint, which runners
+			// don't have.
+			if _, ok := v.Scope.(*pb.ConfigVar_Runner); ok {
+				return nil, status.Errorf(codes.InvalidArgument,
+					"runner variable %q can't be dynamic", v.Name)
+			}
+
+			if v.Value != "" {
+				return nil, status.Errorf(codes.InvalidArgument,
+					"dynamic variable %q can't have a value", v.Name)
+			}
+
+			if _, ok := configsource.FromString[v.Dynamic.From]; !ok {
+				return nil, status.Errorf(codes.InvalidArgument,
+					"variable %q has unknown config source %q, must be one of: %s",
+					v.Name, v.Dynamic.From, strings.Join(configsource.Types(), ", "))
+			}
+		}
+
+		if v.File != nil {
+			// Files are written by the entrypoint too.
+			if _, ok := v.Scope.(*pb.ConfigVar_Runner); ok {
+				return nil, status.Errorf(codes.InvalidArgument,
+					"runner variable %q can't be a file", v.Name)
+			}
+
+			if !path.IsAbs(v.File.Path) {
+				return nil, status.Errorf(codes.InvalidArgument,
+					"file variable %q must have an absolute path, got %q", v.Name, v.File.Path)
+			}
+		}
+	}
+
 	if err := s.state.ConfigSet(req.Variables...); err != nil {
 		return nil, err
 	}
@@ -28,3 +82,54 @@ func (s *service) GetConfig(
 
 	return &pb.ConfigGetResponse{Variables: vars}, nil
 }
+
+// deploymentConfigRequest returns the request for the config variables
+// that apply to the workspace and labels of the deployment.
+func deploymentConfigRequest(d *pb.Deployment) *pb.ConfigGetRequest {
+	workspace := d.Workspace
+	if workspace == nil {
+		workspace = &pb.Ref_Workspace{Workspace: "default"}
+	}
+
+	return &pb.ConfigGetRequest{
+		Scope: &pb.ConfigGetRequest_Application{
+			Application: d.Application,
+		},
+		Workspace: workspace,
+		Labels:    d.Labels,
+	}
+}
+
+func (s *service) SetConfigSource(
+	ctx context.Context,
+	req *pb.SetConfigSourceRequest,
+) (*empty.Empty, error) {
+	source := req.ConfigSource
+	if source == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "config_source must be set")
+	}
+
+	if _, ok := configsource.FromString[source.Type]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"unknown config source %q, must be one of: %s",
+			source.Type, strings.Join(configsource.Types(), ", "))
+	}
+
+	if err := s.state.ConfigSourceSet(source); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *service) GetConfigSource(
+	ctx context.Context,
+	req *pb.GetConfigSourceRequest,
+) (*pb.GetConfigSourceResponse, error) {
+	sources, err := s.state.ConfigSourceGet(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetConfigSourceResponse{ConfigSources: sources}, nil
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/go-hclog"
 	configpkg "github.com/hashicorp/waypoint/internal/config"
@@ -65,6 +67,74 @@ func TestServiceConfig(t *testing.T) {
 		require.Equal(Var.Name, grep.Variables[0].Name)
 		require.Equal(Var.Value, grep.Variables[0].Value)
 	})
+
+	t.Run("runner variables can't be scoped", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := client.SetConfig(ctx, &SReq{Variables: []*pb.ConfigVar{{
+			Scope: &pb.ConfigVar_Runner{
+				Runner: &pb.Ref_Runner{
+					Target: &pb.Ref_Runner_Any{Any: &pb.Ref_RunnerAny{}},
+				},
+			},
+
+			Name:      "DATABASE_URL",
+			Value:     "postgresql:///",
+			Workspace: &pb.Ref_Workspace{Workspace: "prod"},
+		}}})
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("dynamic variables need a known source", func(t *testing.T) {
+		require := require.New(t)
+
+		dynamic := func(from string) *SReq {
+			return &SReq{Variables: []*pb.ConfigVar{{
+				Scope:   Var.Scope,
+				Name:    "DATABASE_PASSWORD",
+				Dynamic: &pb.ConfigVar_DynamicVal{From: from},
+			}}}
+		}
+
+		_, err := client.SetConfig(ctx, dynamic("nope"))
+		require.Equal(codes.InvalidArgument, status.Code(err))
+
+		_, err = client.SetConfig(ctx, dynamic("vault"))
+		require.NoError(err)
+
+		_, err = client.SetConfigSource(ctx, &pb.SetConfigSourceRequest{
+			ConfigSource: &pb.ConfigSource{Type: "nope"},
+		})
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("file variables need an absolute path", func(t *testing.T) {
+		require := require.New(t)
+
+		file := func(path string) *SReq {
+			return &SReq{Variables: []*pb.ConfigVar{{
+				Scope: Var.Scope,
+				Name:  "APP_CONFIG",
+				Value: "port: 8080",
+				File:  &pb.ConfigVar_FileVal{Path: path, Mode: 0600},
+			}}}
+		}
+
+		_, err := client.SetConfig(ctx, file("config.yml"))
+		require.Equal(codes.InvalidArgument, status.Code(err))
+
+		_, err = client.SetConfig(ctx, file("/etc/app/config.yml"))
+		require.NoError(err)
+
+		grep, err := client.GetConfig(ctx, &GReq{
+			Scope:  &pb.ConfigGetRequest_Application{Application: Var.GetApplication()},
+			Prefix: "APP_CONFIG",
+		})
+		require.NoError(err)
+		require.Len(grep.Variables, 1)
+		require.Equal("/etc/app/config.yml", grep.Variables[0].File.Path)
+	})
 }
 
 func TestServerConfigWithStartupConfig(t *testing.T) {
@@ -19,6 +19,35 @@ func (s *service) UpsertDeployment(
 	ctx context.Context,
 	req *pb.UpsertDeploymentRequest,
 ) (*pb.UpsertDeploymentResponse, error) {
+	// A retried insert returns the deployment that the first request created.
+	if req.IdempotencyKey != "" && req.Deployment.GetId() == "" {
+		var resp *pb.UpsertDeploymentResponse
+		id, created, err := s.idempotent("deployment", req.Deployment.Application, req.IdempotencyKey, func() (string, error) {
+			var err error
+			resp, err = s.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+				Deployment:   req.Deployment,
+				AutoHostname: req.AutoHostname,
+			})
+			if err != nil {
+				return "", err
+			}
+
+			return resp.Deployment.Id, nil
+		})
+		if err != nil || created {
+			return resp, err
+		}
+
+		deployment, err := s.state.DeploymentGet(&pb.Ref_Operation{
+			Target: &pb.Ref_Operation_Id{Id: id},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &pb.UpsertDeploymentResponse{Deployment: deployment}, nil
+	}
+
 	result := req.Deployment
 
 	// If we have no ID, then we're inserting and need to generate an ID.
@@ -32,18 +61,43 @@ func (s *service) UpsertDeployment(
 
 		// Specify the id
 		result.Id = id
+
+		// Record the config that applies to the deployment so that it
+		// can be compared with other deployments.
+		if result.Snapshot == nil {
+			result.Snapshot = &pb.Deployment_Snapshot{}
+		}
+		fingerprints, err := s.configFingerprints(result)
+		if err != nil {
+			return nil, err
+		}
+		result.Snapshot.ConfigVars = fingerprints
+	}
+
+	// Get the current deployment so that we know if it just completed.
+	var prev *pb.Deployment
+	if !insert {
+		var err error
+		prev, err = s.state.DeploymentGet(&pb.Ref_Operation{
+			Target: &pb.Ref_Operation_Id{Id: result.Id},
+		})
+		if err != nil && status.Code(err) != codes.NotFound {
+			return nil, err
+		}
 	}
 
 	if err := s.state.DeploymentPut(!insert, result); err != nil {
 		return nil, err
 	}
+	s.publishDeployment(prev, result)
 
 	// This requires: (1) URL service is enabled (2) auto hostname isn't
 	// explicitly set to false in the request and (3) either the server
 	// default is true or we explicitly ask for it.
-	if s.urlClient != nil &&
+	urlConfig, urlClient := s.url()
+	if urlClient != nil &&
 		req.AutoHostname != pb.UpsertDeploymentRequest_FALSE &&
-		(s.urlConfig.AutomaticAppHostname || req.AutoHostname == pb.UpsertDeploymentRequest_TRUE) {
+		(urlConfig.AutomaticAppHostname || req.AutoHostname == pb.UpsertDeploymentRequest_TRUE) {
 		// Our hostname target. We need this to automatically create a hostname.
 		target := &pb.Hostname_Target{
 			Target: &pb.Hostname_Target_Application{
@@ -78,11 +132,14 @@ func (s *service) ListDeployments(
 	ctx context.Context,
 	req *pb.ListDeploymentsRequest,
 ) (*pb.ListDeploymentsResponse, error) {
+	page := &state.ListPage{Size: req.PageSize, Token: req.PageToken}
 	result, err := s.state.DeploymentList(req.Application,
 		state.ListWithStatusFilter(req.Status...),
 		state.ListWithOrder(req.Order),
 		state.ListWithWorkspace(req.Workspace),
 		state.ListWithPhysicalState(req.PhysicalState),
+		state.ListWithStartTime(req.StartTime),
+		state.ListWithPage(page),
 	)
 	if err != nil {
 		return nil, err
@@ -98,7 +155,10 @@ func (s *service) ListDeployments(
 		}
 	}
 
-	return &pb.ListDeploymentsResponse{Deployments: result}, nil
+	return &pb.ListDeploymentsResponse{
+		Deployments:   result,
+		NextPageToken: page.Next,
+	}, nil
 }
 
 // GetDeployment returns a Deployment based on ID
@@ -0,0 +1,164 @@
+package singleprocess
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
+)
+
+// configFingerprintKeyId is the ID of the HMAC key that config variable
+// fingerprints are made with, so that the values can't be guessed from
+// their fingerprints.
+const configFingerprintKeyId = "config-fingerprint"
+
+func (s *service) DiffDeployments(
+	ctx context.Context,
+	req *pb.DiffDeploymentsRequest,
+) (*pb.DiffDeploymentsResponse, error) {
+	if req.From == nil || req.To == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "from and to must be set")
+	}
+
+	from, err := s.diffDeployment(req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.diffDeployment(req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(from.Application.Project, to.Application.Project) ||
+		!strings.EqualFold(from.Application.Application, to.Application.Application) {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"deployments must be of the same application")
+	}
+
+	var changes []*pb.DiffDeploymentsResponse_Change
+	change := func(kind pb.DiffDeploymentsResponse_Change_Kind, name, a, b string) {
+		if a != b {
+			changes = append(changes, &pb.DiffDeploymentsResponse_Change{
+				Kind: kind,
+				Name: name,
+				From: a,
+				To:   b,
+			})
+		}
+	}
+
+	change(pb.DiffDeploymentsResponse_Change_ARTIFACT, "",
+		artifactDigest(from.Preload.GetArtifact()), artifactDigest(to.Preload.GetArtifact()))
+	change(pb.DiffDeploymentsResponse_Change_PLATFORM, "",
+		from.Component.GetName(), to.Component.GetName())
+	change(pb.DiffDeploymentsResponse_Change_PLUGIN_CONFIG, "",
+		from.Snapshot.GetPluginConfig(), to.Snapshot.GetPluginConfig())
+
+	for _, name := range diffKeys(from.Snapshot.GetConfigVars(), to.Snapshot.GetConfigVars()) {
+		change(pb.DiffDeploymentsResponse_Change_CONFIG_VAR, name,
+			from.Snapshot.GetConfigVars()[name], to.Snapshot.GetConfigVars()[name])
+	}
+
+	for _, name := range diffKeys(from.Labels, to.Labels) {
+		change(pb.DiffDeploymentsResponse_Change_LABEL, name, from.Labels[name], to.Labels[name])
+	}
+
+	return &pb.DiffDeploymentsResponse{
+		From:    from,
+		To:      to,
+		Changes: changes,
+	}, nil
+}
+
+// diffDeployment gets a deployment with its artifact. The artifact may have
+// been pruned, in which case it isn't loaded.
+func (s *service) diffDeployment(ref *pb.Ref_Operation) (*pb.Deployment, error) {
+	d, err := s.state.DeploymentGet(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	pa, err := s.state.ArtifactGet(&pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: d.ArtifactId},
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	if d.Preload == nil {
+		d.Preload = &pb.Deployment_Preload{}
+	}
+	d.Preload.Artifact = pa
+
+	return d, nil
+}
+
+// configFingerprints returns the fingerprints of the config variables that
+// apply to the deployment by name. Dynamic variables are fingerprinted by
+// where they are read from since their values aren't known to the server.
+// File variables also include the path, mode and signal of the file.
+func (s *service) configFingerprints(d *pb.Deployment) (map[string]string, error) {
+	vars, err := s.state.ConfigGet(deploymentConfigRequest(d))
+	if err != nil || len(vars) == 0 {
+		return nil, err
+	}
+
+	key, err := s.state.HMACKeyCreateIfNotExist(configFingerprintKeyId, hmacKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]string{}
+	for _, v := range vars {
+		h, err := blake2b.New256(key.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if v.Dynamic != nil {
+			h.Write([]byte("dynamic:" + v.Dynamic.From))
+			for _, k := range diffKeys(v.Dynamic.Config, nil) {
+				h.Write([]byte("\x00" + k + "=" + v.Dynamic.Config[k]))
+			}
+		} else {
+			h.Write([]byte("value:" + v.Value))
+		}
+		if f := v.File; f != nil {
+			h.Write([]byte(fmt.Sprintf("\x00file:%s:%o:%s", f.Path, f.Mode, f.Signal)))
+		}
+
+		result[v.Name] = hex.EncodeToString(h.Sum(nil))[:16]
+	}
+
+	return result, nil
+}
+
+// artifactDigest returns the digest of the pushed artifact, or an empty
+// string if there is none.
+func artifactDigest(a *pb.PushedArtifact) string {
+	return state.ArtifactDigest(a.GetArtifact())
+}
+
+// diffKeys returns the keys of both maps, sorted.
+func diffKeys(a, b map[string]string) []string {
+	var result []string
+	for k := range a {
+		result = append(result, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+
+	return result
+}
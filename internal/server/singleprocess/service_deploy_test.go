@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes/any"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -251,3 +252,84 @@ func TestServiceDeployment_ListDeployments(t *testing.T) {
 		require.Equal(deployments.Deployments[0].Preload.Build.Id, build.Id)
 	})
 }
+
+func TestServiceDeployment_DiffDeployments(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// deploy creates a deployment of a new artifact with the config
+	// variable set to value.
+	deploy := func(value, artifact, pluginConfig string, labels map[string]string) *pb.Deployment {
+		_, err := client.SetConfig(ctx, &pb.ConfigSetRequest{Variables: []*pb.ConfigVar{{
+			Scope: &pb.ConfigVar_Application{Application: &pb.Ref_Application{
+				Project:     "p_test",
+				Application: "a_test",
+			}},
+			Name:  "DATABASE_URL",
+			Value: value,
+		}}})
+		require.NoError(err)
+
+		aresp, err := client.UpsertPushedArtifact(ctx, &pb.UpsertPushedArtifactRequest{
+			Artifact: serverptypes.TestValidArtifact(t, &pb.PushedArtifact{
+				Artifact: &pb.Artifact{
+					Artifact: &any.Any{TypeUrl: "test", Value: []byte(artifact)},
+				},
+			}),
+		})
+		require.NoError(err)
+
+		resp, err := client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+			Deployment: serverptypes.TestValidDeployment(t, &pb.Deployment{
+				Component:  &pb.Component{Name: "docker"},
+				ArtifactId: aresp.Artifact.Id,
+				Labels:     labels,
+				Snapshot:   &pb.Deployment_Snapshot{PluginConfig: pluginConfig},
+			}),
+		})
+		require.NoError(err)
+		return resp.Deployment
+	}
+
+	a := deploy("one", "image-1", `image = "web"`, map[string]string{"env": "prod"})
+	b := deploy("two", "image-2", `image = "web"`, map[string]string{"env": "prod", "canary": "true"})
+
+	// The server records fingerprints instead of values
+	require.NotEmpty(a.Snapshot.ConfigVars["DATABASE_URL"])
+	require.NotEqual("one", a.Snapshot.ConfigVars["DATABASE_URL"])
+
+	ref := func(d *pb.Deployment) *pb.Ref_Operation {
+		return &pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: d.Id}}
+	}
+	resp, err := client.DiffDeployments(ctx, &pb.DiffDeploymentsRequest{From: ref(a), To: ref(b)})
+	require.NoError(err)
+	require.Equal(a.Id, resp.From.Id)
+	require.Equal(b.Id, resp.To.Id)
+
+	kinds := map[pb.DiffDeploymentsResponse_Change_Kind]*pb.DiffDeploymentsResponse_Change{}
+	for _, c := range resp.Changes {
+		kinds[c.Kind] = c
+	}
+	require.Len(resp.Changes, 3)
+	require.Contains(kinds[pb.DiffDeploymentsResponse_Change_ARTIFACT].From, "sha256:")
+	require.Equal("DATABASE_URL", kinds[pb.DiffDeploymentsResponse_Change_CONFIG_VAR].Name)
+	require.Equal("canary", kinds[pb.DiffDeploymentsResponse_Change_LABEL].Name)
+	require.Empty(kinds[pb.DiffDeploymentsResponse_Change_LABEL].From)
+
+	// A deployment doesn't differ from itself
+	resp, err = client.DiffDeployments(ctx, &pb.DiffDeploymentsRequest{From: ref(a), To: ref(a)})
+	require.NoError(err)
+	require.Empty(resp.Changes)
+
+	// Unknown deployments
+	_, err = client.DiffDeployments(ctx, &pb.DiffDeploymentsRequest{
+		From: ref(a),
+		To:   &pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "nope"}},
+	})
+	require.Equal(codes.NotFound, status.Code(err))
+}
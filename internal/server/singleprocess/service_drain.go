@@ -0,0 +1,96 @@
+package singleprocess
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/waypoint/internal/server"
+)
+
+// instanceRestoreGrace is how long the instances that are restored after
+// a restart are kept for their entrypoints to reconnect.
+var instanceRestoreGrace = 2 * time.Minute
+
+// Drain implements server.Drainer. Once the server drains, QueueJob fails
+// and no more jobs are assigned to runners. Drain waits for the jobs that
+// runners are executing to complete, or until ctx is done. It then saves
+// the connected instances so they're known when the server starts again,
+// and tells the entrypoints that the server is going away.
+func (s *service) Drain(ctx context.Context) {
+	log := s.log.Named("drain")
+	s.drainOnce.Do(func() { close(s.drainCh) })
+
+	for {
+		ws := memdb.NewWatchSet()
+		n, err := s.state.JobAssignedCount(ws)
+		if err != nil {
+			log.Warn("error reading running jobs", "err", err)
+			break
+		}
+		if n == 0 {
+			break
+		}
+
+		log.Info("waiting for running jobs to complete", "jobs", n)
+		if err := ws.WatchCtx(ctx); err != nil {
+			log.Warn("timeout waiting for jobs to complete, they'll be expired "+
+				"if their runners don't reconnect", "jobs", n)
+			break
+		}
+	}
+
+	if err := s.state.InstanceSave(); err != nil {
+		log.Warn("error saving instances", "err", err)
+	}
+
+	s.drainEntrypointsOnce.Do(func() { close(s.drainEntrypointsCh) })
+}
+
+// draining returns true if the server is shutting down.
+func (s *service) draining() bool {
+	select {
+	case <-s.drainCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// restoreInstances restores the instances that were connected when the
+// server last shut down. The ones whose entrypoints haven't reconnected
+// after instanceRestoreGrace are removed.
+func (s *service) restoreInstances(ctx context.Context, log hclog.Logger) error {
+	restored, err := s.state.InstanceRestore()
+	if err != nil || len(restored) == 0 {
+		return err
+	}
+	log.Debug("restored instances", "count", len(restored))
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(instanceRestoreGrace):
+		}
+
+		for _, rec := range restored {
+			// A reconnected entrypoint replaces the restored record.
+			if current, err := s.state.InstanceById(rec.Id); err != nil || current != rec {
+				continue
+			}
+
+			log.Debug("removing instance that didn't reconnect", "instance_id", rec.Id)
+			rec.LogBuffer.Close()
+			if err := s.state.InstanceDelete(rec.Id); err != nil {
+				log.Warn("error removing instance", "instance_id", rec.Id, "err", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+var _ server.Drainer = (*service)(nil)
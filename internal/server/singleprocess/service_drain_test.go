@@ -0,0 +1,88 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceDrain(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+	s := testServiceImpl(impl)
+	TestApp(t, client, serverptypes.TestJobNew(t, nil).Application)
+
+	// Start a job on a runner
+	resp, err := client.QueueJob(ctx, &pb.QueueJobRequest{
+		Job: serverptypes.TestJobNew(t, nil),
+	})
+	require.NoError(err)
+	job, err := s.state.JobAssignForRunner(ctx, &pb.Runner{Id: "R_A"})
+	require.NoError(err)
+	require.Equal(resp.JobId, job.Id)
+	_, err = s.state.JobAck(job.Id, true)
+	require.NoError(err)
+
+	// Drain waits for the job
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		s.Drain(ctx)
+	}()
+	require.Eventually(s.draining, time.Second, 10*time.Millisecond)
+
+	// No new jobs are accepted
+	_, err = client.QueueJob(ctx, &pb.QueueJobRequest{
+		Job: serverptypes.TestJobNew(t, nil),
+	})
+	require.Equal(codes.Unavailable, status.Code(err))
+
+	select {
+	case <-doneCh:
+		t.Fatal("drain should wait for the running job")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(s.state.JobComplete(job.Id, nil, nil))
+	select {
+	case <-doneCh:
+	case <-time.After(time.Second):
+		t.Fatal("drain should be done")
+	}
+}
+
+func TestServiceDrain_timeout(t *testing.T) {
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+	s := testServiceImpl(impl)
+	TestApp(t, client, serverptypes.TestJobNew(t, nil).Application)
+
+	_, err = client.QueueJob(context.Background(), &pb.QueueJobRequest{
+		Job: serverptypes.TestJobNew(t, nil),
+	})
+	require.NoError(err)
+	_, err = s.state.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+	require.NoError(err)
+
+	// Drain gives up on the job once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.Drain(ctx)
+	require.Error(ctx.Err())
+}
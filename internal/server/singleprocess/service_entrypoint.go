@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync/atomic"
 
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"google.golang.org/grpc/codes"
@@ -25,6 +26,11 @@ func (s *service) EntrypointConfig(
 ) error {
 	log := hclog.FromContext(srv.Context())
 
+	// Entrypoint tokens can only register instances of their deployment.
+	if err := authEntrypoint(srv.Context(), req.DeploymentId); err != nil {
+		return err
+	}
+
 	// Fetch the deployment info so we can calculate the config variables to send.
 	// This also verifies this deployment exists.
 	deployment, err := s.GetDeployment(srv.Context(), &pb.GetDeploymentRequest{
@@ -50,6 +56,7 @@ func (s *service) EntrypointConfig(
 	if err := s.state.InstanceCreate(record); err != nil {
 		return err
 	}
+	s.publishInstance(pb.Event_INSTANCE_CONNECTED, record)
 
 	// Defer deleting this.
 	// TODO(mitchellh): this is too aggressive and we want to have some grace
@@ -63,6 +70,7 @@ func (s *service) EntrypointConfig(
 		if err := s.state.InstanceDelete(record.Id); err != nil {
 			log.Error("failed to delete instance data. This should not happen.", "err", err)
 		}
+		s.publishInstance(pb.Event_INSTANCE_DISCONNECTED, record)
 
 		// Delete any active but unconnected exec requests. This can happen
 		// if the entrypoint crashed after an exec was assigned to the entrypoint.
@@ -94,21 +102,34 @@ func (s *service) EntrypointConfig(
 				Index: exec.Id,
 				Args:  exec.Args,
 				Pty:   exec.Pty,
+				Copy:  exec.Copy,
 			})
 		}
 
-		vars, err := s.state.ConfigGetWatch(&pb.ConfigGetRequest{
-			Scope: &pb.ConfigGetRequest_Application{
-				Application: deployment.Application,
-			},
-		}, ws)
+		vars, err := s.state.ConfigGetWatch(deploymentConfigRequest(deployment), ws)
 		if err != nil {
 			return err
 		}
 		config.EnvVars = vars
 
+		// Send the configuration of the sources that the dynamic variables
+		// are read from. Other sources aren't sent since they may have
+		// credentials that the deployment doesn't need.
+		sources, err := s.state.ConfigSourceGetWatch(&pb.GetConfigSourceRequest{}, ws)
+		if err != nil {
+			return err
+		}
+		for _, source := range sources {
+			for _, v := range vars {
+				if v.Dynamic != nil && strings.EqualFold(v.Dynamic.From, source.Type) {
+					config.ConfigSources = append(config.ConfigSources, source)
+					break
+				}
+			}
+		}
+
 		// If we have the URL service setup, note that
-		if v := s.urlConfig; v != nil {
+		if v, _ := s.url(); v != nil {
 			var flatLabels []string
 			for k, v := range deployment.Labels {
 				flatLabels = append(flatLabels, fmt.Sprintf("%s=%s", k, v))
@@ -120,7 +141,7 @@ func (s *service) EntrypointConfig(
 				hznLabelProject+"="+deployment.Application.Project,
 				hznLabelWorkspace+"="+deployment.Workspace.Workspace,
 
-				":deployment=v"+strconv.FormatUint(deployment.Sequence, 10),
+				hznLabelDeployment+"=v"+strconv.FormatUint(deployment.Sequence, 10),
 				":deployment-order="+strings.ToLower(deployment.Id),
 			)
 
@@ -142,10 +163,25 @@ func (s *service) EntrypointConfig(
 		config = nil
 		execs = nil
 
-		// Wait for any changes
+		// Wait for any changes, or for the server to shut down.
+		ws.Add(s.drainEntrypointsCh)
 		if err := ws.WatchCtx(srv.Context()); err != nil {
 			return err
 		}
+
+		select {
+		case <-s.drainEntrypointsCh:
+			log.Trace("server is shutting down, disconnecting entrypoint")
+			if err := srv.Send(&pb.EntrypointConfigResponse{
+				ServerShutdown: true,
+			}); err != nil {
+				return err
+			}
+
+			return status.Errorf(codes.Unavailable, "server is shutting down")
+
+		default:
+		}
 	}
 }
 
@@ -155,6 +191,7 @@ func (s *service) EntrypointLogStream(
 ) error {
 	log := hclog.FromContext(server.Context())
 
+	var instance *state.Instance
 	var buf *logbuffer.Buffer
 	for {
 		// Read the next log entry
@@ -168,10 +205,13 @@ func (s *service) EntrypointLogStream(
 			log = log.With("instance_id", batch.InstanceId)
 
 			// Read our instance record
-			instance, err := s.state.InstanceById(batch.InstanceId)
+			instance, err = s.state.InstanceById(batch.InstanceId)
 			if err != nil {
 				return err
 			}
+			if err := authEntrypoint(server.Context(), instance.DeploymentId); err != nil {
+				return err
+			}
 
 			// Get our log buffer
 			buf = instance.LogBuffer
@@ -195,9 +235,58 @@ func (s *service) EntrypointLogStream(
 
 		// Write our log data to the circular buffer
 		buf.Write(entries...)
+
+		// Keep the lines in the history so they can be retrieved later
+		// with GetLogs, after they're gone from the buffer.
+		if err := s.state.LogHistoryAppend(instance, batch.Lines); err != nil {
+			log.Warn("error keeping log history", "err", err)
+		}
+
+		// Forward the lines to the external sinks.
+		s.exportLogs(instance.LogLines(batch.Lines))
 	}
 }
 
+// EntrypointHealth records the health that the entrypoint reported for its
+// instance.
+func (s *service) EntrypointHealth(
+	ctx context.Context,
+	req *pb.EntrypointHealthRequest,
+) (*empty.Empty, error) {
+	if req.Health == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "health must be set")
+	}
+	if err := s.authEntrypointInstance(ctx, req.InstanceId); err != nil {
+		return nil, err
+	}
+
+	if err := s.state.InstanceHealthSet(req.InstanceId, req.Health); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// EntrypointMetrics records the metrics that the entrypoint reported for
+// its instance.
+func (s *service) EntrypointMetrics(
+	ctx context.Context,
+	req *pb.EntrypointMetricsRequest,
+) (*empty.Empty, error) {
+	if req.Metrics == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "metrics must be set")
+	}
+	if err := s.authEntrypointInstance(ctx, req.InstanceId); err != nil {
+		return nil, err
+	}
+
+	if err := s.state.InstanceMetricsSet(req.InstanceId, req.Metrics); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
 // TODO: test
 func (s *service) EntrypointExecStream(
 	server pb.Waypoint_EntrypointExecStreamServer,
@@ -220,6 +309,9 @@ func (s *service) EntrypointExecStream(
 	if err != nil {
 		return err
 	}
+	if err := s.authEntrypointInstance(server.Context(), exec.InstanceId); err != nil {
+		return err
+	}
 	log = log.With("instance_id", exec.InstanceId, "index", open.Open.Index)
 
 	// Mark we're connected
@@ -312,6 +404,22 @@ func (s *service) EntrypointExecStream(
 	}
 }
 
+// authEntrypointInstance checks that an entrypoint token of the request is
+// for the deployment of the instance.
+func (s *service) authEntrypointInstance(ctx context.Context, instanceId string) error {
+	info := authInfoFromContext(ctx)
+	if info == nil || !info.entrypoint {
+		return nil
+	}
+
+	instance, err := s.state.InstanceById(instanceId)
+	if err != nil {
+		return err
+	}
+
+	return authEntrypoint(ctx, instance.DeploymentId)
+}
+
 func (s *service) handleClientExecRequest(
 	log hclog.Logger,
 	srv pb.Waypoint_EntrypointExecStreamServer,
@@ -333,6 +441,13 @@ func (s *service) handleClientExecRequest(
 				Winch: event.Winch,
 			},
 		}
+
+	case *pb.ExecStreamRequest_InputEof:
+		send = &pb.EntrypointExecResponse{
+			Event: &pb.EntrypointExecResponse_InputEof{
+				InputEof: true,
+			},
+		}
 	}
 
 	// Send our response
@@ -96,6 +96,173 @@ func TestServiceEntrypointConfig(t *testing.T) {
 		require.NotNil(cfgResp.Config.UrlService)
 		require.NotEmpty(cfgResp.Config.UrlService.Labels)
 	})
+
+	t.Run("dynamic config", func(t *testing.T) {
+		require := require.New(t)
+
+		// Create our server
+		impl, err := New(WithDB(testDB(t)))
+		require.NoError(err)
+		client := server.TestServer(t, impl)
+
+		// Create a deployment
+		resp, err := client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+			Deployment: serverptypes.TestValidDeployment(t, &pb.Deployment{
+				Component: &pb.Component{
+					Name: "testapp",
+				},
+			}),
+		})
+		require.NoError(err)
+		dep := resp.Deployment
+
+		// Configure two sources and a variable from one of them
+		for _, typ := range []string{"vault", "aws-ssm"} {
+			_, err = client.SetConfigSource(ctx, &pb.SetConfigSourceRequest{
+				ConfigSource: &pb.ConfigSource{
+					Type:   typ,
+					Config: map[string]string{"token": "secret"},
+				},
+			})
+			require.NoError(err)
+		}
+		_, err = client.SetConfig(ctx, &pb.ConfigSetRequest{Variables: []*pb.ConfigVar{{
+			Scope: &pb.ConfigVar_Application{Application: dep.Application},
+			Name:  "DATABASE_PASSWORD",
+			Dynamic: &pb.ConfigVar_DynamicVal{
+				From:   "vault",
+				Config: map[string]string{"path": "secret/data/db", "key": "password"},
+			},
+		}}})
+		require.NoError(err)
+
+		// Create the config
+		instanceId, err := server.Id()
+		require.NoError(err)
+		stream, err := client.EntrypointConfig(ctx, &pb.EntrypointConfigRequest{
+			InstanceId:   instanceId,
+			DeploymentId: dep.Id,
+		})
+		require.NoError(err)
+
+		// Wait for the first config so that we know we're registered
+		cfgResp, err := stream.Recv()
+		require.NoError(err)
+
+		// Only the source of the variable is sent
+		require.Len(cfgResp.Config.EnvVars, 1)
+		require.NotNil(cfgResp.Config.EnvVars[0].Dynamic)
+		require.Len(cfgResp.Config.ConfigSources, 1)
+		require.Equal("vault", cfgResp.Config.ConfigSources[0].Type)
+	})
+}
+
+func TestServiceEntrypointHealth(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Create a deployment
+	resp, err := client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+		Deployment: serverptypes.TestValidDeployment(t, nil),
+	})
+	require.NoError(err)
+	dep := resp.Deployment
+
+	// Register an instance
+	instanceId, err := server.Id()
+	require.NoError(err)
+	stream, err := client.EntrypointConfig(ctx, &pb.EntrypointConfigRequest{
+		InstanceId:   instanceId,
+		DeploymentId: dep.Id,
+	})
+	require.NoError(err)
+	_, err = stream.Recv()
+	require.NoError(err)
+
+	// Report the health
+	_, err = client.EntrypointHealth(ctx, &pb.EntrypointHealthRequest{
+		InstanceId: instanceId,
+		Health: &pb.Instance_Health{
+			State:   pb.Instance_Health_UNHEALTHY,
+			Message: "connection refused",
+		},
+	})
+	require.NoError(err)
+
+	// The instance has the health
+	list, err := client.ListInstances(ctx, &pb.ListInstancesRequest{
+		Scope: &pb.ListInstancesRequest_DeploymentId{DeploymentId: dep.Id},
+	})
+	require.NoError(err)
+	require.Len(list.Instances, 1)
+	require.Equal(pb.Instance_Health_UNHEALTHY, list.Instances[0].Health.GetState())
+	require.Equal("connection refused", list.Instances[0].Health.GetMessage())
+
+	// Unknown instances are an error
+	_, err = client.EntrypointHealth(ctx, &pb.EntrypointHealthRequest{
+		InstanceId: "nope",
+		Health:     &pb.Instance_Health{State: pb.Instance_Health_READY},
+	})
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestServiceEntrypointMetrics(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Create a deployment
+	resp, err := client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+		Deployment: serverptypes.TestValidDeployment(t, nil),
+	})
+	require.NoError(err)
+	dep := resp.Deployment
+
+	// Register an instance
+	instanceId, err := server.Id()
+	require.NoError(err)
+	stream, err := client.EntrypointConfig(ctx, &pb.EntrypointConfigRequest{
+		InstanceId:   instanceId,
+		DeploymentId: dep.Id,
+	})
+	require.NoError(err)
+	_, err = stream.Recv()
+	require.NoError(err)
+
+	// Report the metrics
+	_, err = client.EntrypointMetrics(ctx, &pb.EntrypointMetricsRequest{
+		InstanceId: instanceId,
+		Metrics: &pb.Instance_Metrics{
+			CpuSeconds: 1.5,
+			RssBytes:   4096,
+			Restarts:   1,
+		},
+	})
+	require.NoError(err)
+
+	// The instance has the metrics
+	list, err := client.ListInstances(ctx, &pb.ListInstancesRequest{
+		Scope: &pb.ListInstancesRequest_DeploymentId{DeploymentId: dep.Id},
+	})
+	require.NoError(err)
+	require.Len(list.Instances, 1)
+	require.Equal(uint64(4096), list.Instances[0].Metrics.GetRssBytes())
+	require.Equal(uint32(1), list.Instances[0].Metrics.GetRestarts())
+
+	// Metrics are required
+	_, err = client.EntrypointMetrics(ctx, &pb.EntrypointMetricsRequest{
+		InstanceId: instanceId,
+	})
+	require.Equal(codes.InvalidArgument, status.Code(err))
 }
 
 func TestServiceEntrypointExecStream_badOpen(t *testing.T) {
@@ -0,0 +1,107 @@
+package singleprocess
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// streamEventsBuffer is how many events are buffered for each StreamEvents
+// call. Streams that fall further behind miss events.
+const streamEventsBuffer = 100
+
+func (s *service) StreamEvents(
+	req *pb.StreamEventsRequest,
+	srv pb.Waypoint_StreamEventsServer,
+) error {
+	ctx := srv.Context()
+	for _, typ := range req.Types {
+		if _, ok := pb.Event_Type_name[int32(typ)]; !ok || typ == pb.Event_UNKNOWN {
+			return status.Errorf(codes.InvalidArgument, "unknown event type: %d", typ)
+		}
+	}
+
+	// Entrypoints only stream their own config and commands, and the
+	// events of other deployments aren't theirs to read.
+	info := authInfoFromContext(ctx)
+	if info != nil && info.entrypoint {
+		return status.Errorf(codes.PermissionDenied,
+			"entrypoint tokens can't stream events")
+	}
+
+	eventCh, cancel := s.events.Subscribe(streamEventsBuffer)
+	defer cancel()
+
+	// Send the headers so that clients can wait for them to know that
+	// they receive all events from now on.
+	if err := srv.SendHeader(nil); err != nil {
+		return err
+	}
+
+	// Authorization was skipped for this endpoint, so we only send the
+	// events that the caller is allowed to read.
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case ev, ok := <-eventCh:
+			if !ok {
+				return nil
+			}
+
+			if !eventMatches(req, ev) {
+				continue
+			}
+			if info != nil && (!info.allowed(
+				ev.Application.GetProject(),
+				ev.Workspace.GetWorkspace(),
+				pb.Permission_READ,
+			) || !s.organizationHasProject(info.org, ev.Application.GetProject())) {
+				continue
+			}
+
+			if err := srv.Send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// eventMatches returns true if the event matches the filters of the request.
+func eventMatches(req *pb.StreamEventsRequest, ev *pb.Event) bool {
+	if len(req.Types) > 0 {
+		found := false
+		for _, typ := range req.Types {
+			if typ == ev.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if p := req.Project.GetProject(); p != "" &&
+		!strings.EqualFold(p, ev.Application.GetProject()) {
+		return false
+	}
+
+	if app := req.Application; app != nil {
+		if !strings.EqualFold(app.Project, ev.Application.GetProject()) ||
+			!strings.EqualFold(app.Application, ev.Application.GetApplication()) {
+			return false
+		}
+	}
+
+	if ws := req.Workspace.GetWorkspace(); ws != "" &&
+		!strings.EqualFold(ws, ev.Workspace.GetWorkspace()) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,147 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceStreamEvents(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	resp, err := client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+		Deployment: serverptypes.TestValidDeployment(t, &pb.Deployment{
+			Status: server.NewStatus(pb.Status_RUNNING),
+		}),
+	})
+	require.NoError(err)
+	dep := resp.Deployment
+
+	// Only stream instance events of the project
+	stream, err := client.StreamEvents(ctx, &pb.StreamEventsRequest{
+		Types: []pb.Event_Type{
+			pb.Event_INSTANCE_CONNECTED,
+			pb.Event_INSTANCE_DISCONNECTED,
+		},
+		Project: &pb.Ref_Project{Project: dep.Application.Project},
+	})
+	require.NoError(err)
+	_, err = stream.Header()
+	require.NoError(err)
+
+	// Completing the deployment isn't sent
+	server.StatusSetSuccess(dep.Status)
+	_, err = client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{Deployment: dep})
+	require.NoError(err)
+
+	// Connect and disconnect an entrypoint
+	entryCtx, entryCancel := context.WithCancel(ctx)
+	configClient, err := client.EntrypointConfig(entryCtx, &pb.EntrypointConfigRequest{
+		DeploymentId: dep.Id,
+		InstanceId:   "1",
+	})
+	require.NoError(err)
+	_, err = configClient.Recv()
+	require.NoError(err)
+
+	ev, err := stream.Recv()
+	require.NoError(err)
+	require.Equal(pb.Event_INSTANCE_CONNECTED, ev.Type)
+	require.Equal("1", ev.GetInstance().Id)
+	require.Equal(dep.Id, ev.GetInstance().DeploymentId)
+
+	entryCancel()
+	ev, err = stream.Recv()
+	require.NoError(err)
+	require.Equal(pb.Event_INSTANCE_DISCONNECTED, ev.Type)
+}
+
+func TestServiceStreamEvents_entrypoint(t *testing.T) {
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+
+	ctx := context.WithValue(context.Background(), authInfoKey{}, &authInfo{entrypoint: true})
+	err = testServiceImpl(impl).StreamEvents(
+		&pb.StreamEventsRequest{},
+		&testStreamEventsServer{ctx: ctx},
+	)
+	require.Error(err)
+	require.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+// testStreamEventsServer is a pb.Waypoint_StreamEventsServer for calling
+// StreamEvents directly with a context.
+type testStreamEventsServer struct {
+	pb.Waypoint_StreamEventsServer
+
+	ctx context.Context
+}
+
+func (s *testStreamEventsServer) Context() context.Context { return s.ctx }
+
+func TestEventMatches(t *testing.T) {
+	ev := &pb.Event{
+		Type: pb.Event_JOB,
+		Application: &pb.Ref_Application{
+			Project:     "p",
+			Application: "a",
+		},
+		Workspace: &pb.Ref_Workspace{Workspace: "default"},
+	}
+
+	cases := []struct {
+		Name     string
+		Req      *pb.StreamEventsRequest
+		Expected bool
+	}{
+		{"no filters", &pb.StreamEventsRequest{}, true},
+		{
+			"type",
+			&pb.StreamEventsRequest{Types: []pb.Event_Type{pb.Event_DEPLOYMENT, pb.Event_JOB}},
+			true,
+		},
+		{
+			"other type",
+			&pb.StreamEventsRequest{Types: []pb.Event_Type{pb.Event_DEPLOYMENT}},
+			false,
+		},
+		{"project", &pb.StreamEventsRequest{Project: &pb.Ref_Project{Project: "P"}}, true},
+		{"other project", &pb.StreamEventsRequest{Project: &pb.Ref_Project{Project: "q"}}, false},
+		{
+			"application",
+			&pb.StreamEventsRequest{Application: &pb.Ref_Application{Project: "p", Application: "a"}},
+			true,
+		},
+		{
+			"other application",
+			&pb.StreamEventsRequest{Application: &pb.Ref_Application{Project: "p", Application: "b"}},
+			false,
+		},
+		{
+			"other workspace",
+			&pb.StreamEventsRequest{Workspace: &pb.Ref_Workspace{Workspace: "prod"}},
+			false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require.Equal(t, tt.Expected, eventMatches(tt.Req, ev))
+		})
+	}
+}
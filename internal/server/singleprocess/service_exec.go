@@ -2,6 +2,7 @@ package singleprocess
 
 import (
 	"io"
+	"path"
 
 	"github.com/hashicorp/go-hclog"
 	"google.golang.org/grpc/codes"
@@ -27,8 +28,22 @@ func (s *service) StartExecStream(
 		return status.Errorf(codes.FailedPrecondition,
 			"first message must be start type")
 	}
+	if err := s.Authorize(srv.Context(), "StartExecStream", req); err != nil {
+		return err
+	}
+	s.Audit(srv.Context(), "StartExecStream", req, nil)
 	log = log.With("deployment_id", start.Start.DeploymentId)
 	log.Debug("exec requested", "args", start.Start.Args)
+	if c := start.Start.Copy; c != nil && !path.IsAbs(c.Path) {
+		return status.Errorf(codes.InvalidArgument,
+			"path of the file to copy must be absolute, got %q", c.Path)
+	}
+
+	// Record the session if it is configured.
+	recorder, err := s.newExecRecorder(srv.Context(), start.Start)
+	if err != nil {
+		return err
+	}
 
 	// Create our exec. We have to populate everything here first because
 	// once we register, this will trigger any watchers to be notified of
@@ -38,18 +53,24 @@ func (s *service) StartExecStream(
 	execRec := &state.InstanceExec{
 		Args:              start.Start.Args,
 		Pty:               start.Start.Pty,
+		Copy:              start.Start.Copy,
 		ClientEventCh:     clientEventCh,
 		EntrypointEventCh: eventCh,
 	}
 
 	// Register the exec session
-	err = s.state.InstanceExecCreateByDeployment(start.Start.DeploymentId, execRec)
+	if id := start.Start.InstanceId; id != "" {
+		err = s.state.InstanceExecCreateByInstance(start.Start.DeploymentId, id, execRec)
+	} else {
+		err = s.state.InstanceExecCreateByDeployment(start.Start.DeploymentId, execRec)
+	}
 	if err != nil {
 		return err
 	}
 
 	// Make sure we always deregister it
 	defer s.state.InstanceExecDelete(execRec.Id)
+	defer s.finishExecRecording(log, recorder, execRec.InstanceId)
 
 	// Always send the open message. In the future we'll send some metadata here.
 	if err := srv.Send(&pb.ExecStreamResponse{
@@ -79,6 +100,10 @@ func (s *service) StartExecStream(
 				return
 			}
 
+			if input, ok := resp.Event.(*pb.ExecStreamRequest_Input_); ok {
+				recorder.Record(pb.ExecRecording_STDIN, input.Input.Data)
+			}
+
 			clientEventCh <- resp
 		}
 	}()
@@ -103,7 +128,7 @@ func (s *service) StartExecStream(
 				return nil
 			}
 
-			exit, err := s.handleEntrypointExecRequest(log, srv, entryReq)
+			exit, err := s.handleEntrypointExecRequest(log, srv, recorder, entryReq)
 			if exit || err != nil {
 				return err
 			}
@@ -114,6 +139,7 @@ func (s *service) StartExecStream(
 func (s *service) handleEntrypointExecRequest(
 	log hclog.Logger,
 	srv pb.Waypoint_StartExecStreamServer,
+	recorder *execRecorder,
 	entryReq *pb.EntrypointExecRequest,
 ) (bool, error) {
 	log.Trace("event received from entrypoint", "event", entryReq.Event)
@@ -121,6 +147,12 @@ func (s *service) handleEntrypointExecRequest(
 	exit := false
 	switch event := entryReq.Event.(type) {
 	case *pb.EntrypointExecRequest_Output_:
+		channel := pb.ExecRecording_STDOUT
+		if event.Output.Channel == pb.EntrypointExecRequest_Output_STDERR {
+			channel = pb.ExecRecording_STDERR
+		}
+		recorder.Record(channel, event.Output.Data)
+
 		send = &pb.ExecStreamResponse{
 			Event: &pb.ExecStreamResponse_Output_{
 				Output: &pb.ExecStreamResponse_Output{
@@ -132,6 +164,7 @@ func (s *service) handleEntrypointExecRequest(
 
 	case *pb.EntrypointExecRequest_Exit_:
 		exit = true
+		recorder.Exit(event.Exit.Code)
 		send = &pb.ExecStreamResponse{
 			Event: &pb.ExecStreamResponse_Exit_{
 				Exit: &pb.ExecStreamResponse_Exit{
@@ -139,6 +172,11 @@ func (s *service) handleEntrypointExecRequest(
 				},
 			},
 		}
+
+	case *pb.EntrypointExecRequest_Error_:
+		// The entrypoint couldn't run the session, such as if the command
+		// doesn't exist, so the client gets its error.
+		return true, status.ErrorProto(event.Error.Error)
 	}
 
 	// Send our response
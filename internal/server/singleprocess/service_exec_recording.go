@@ -0,0 +1,208 @@
+package singleprocess
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	// execRecordingDefaultRetention and execRecordingDefaultMaxBytes are
+	// used if the configuration doesn't set them.
+	execRecordingDefaultRetention = 30 * 24 * time.Hour
+	execRecordingDefaultMaxBytes  = 10 * 1024 * 1024
+)
+
+// execRecordingConfig is the parsed configuration for recording exec
+// sessions.
+type execRecordingConfig struct {
+	skipInput bool
+	maxBytes  int
+}
+
+// initExecRecording parses the configuration for recording exec sessions.
+// This can be called again to reload the configuration, which applies to
+// the sessions that start afterwards. The retention applies whether or not
+// recording is enabled, since there may be recordings from before.
+func (s *service) initExecRecording(cfg *configpkg.ExecRecording) error {
+	retention := execRecordingDefaultRetention
+	if cfg.Retention != "" {
+		d, err := time.ParseDuration(cfg.Retention)
+		if err != nil {
+			return fmt.Errorf("invalid exec recording retention: %s", err)
+		}
+
+		retention = d
+	}
+
+	var c *execRecordingConfig
+	if cfg.Enabled {
+		c = &execRecordingConfig{
+			skipInput: cfg.SkipInput,
+			maxBytes:  cfg.MaxBytes,
+		}
+		if c.maxBytes <= 0 {
+			c.maxBytes = execRecordingDefaultMaxBytes
+		}
+	}
+
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+	s.execRecording = c
+	s.execRecordingRetention = retention
+	return nil
+}
+
+// execRecorder records the transcript of an exec session. A nil recorder
+// records nothing so that sessions don't need to check if recording is
+// enabled.
+type execRecorder struct {
+	cfg *execRecordingConfig
+
+	lock sync.Mutex
+	rec  *pb.ExecRecording
+	size int
+}
+
+// newExecRecorder returns the recorder for a session if recording is
+// enabled, or nil otherwise.
+func (s *service) newExecRecorder(ctx context.Context, start *pb.ExecStreamRequest_Start) (*execRecorder, error) {
+	s.reloadLock.RLock()
+	cfg := s.execRecording
+	s.reloadLock.RUnlock()
+	if cfg == nil {
+		return nil, nil
+	}
+
+	d, err := s.state.DeploymentGet(&pb.Ref_Operation{
+		Target: &pb.Ref_Operation_Id{Id: start.DeploymentId},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := server.Id()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "uuid generation failed: %s", err)
+	}
+
+	var user string
+	if info := authInfoFromContext(ctx); info != nil {
+		user = info.user
+	}
+
+	return &execRecorder{
+		cfg: cfg,
+		rec: &pb.ExecRecording{
+			Id:           id,
+			DeploymentId: d.Id,
+			Application:  d.Application,
+			Workspace:    d.Workspace,
+			User:         user,
+			Args:         start.Args,
+			Pty:          start.Pty != nil && start.Pty.Enable,
+			StartTime:    ptypes.TimestampNow(),
+		},
+	}, nil
+}
+
+// Record adds the data to the transcript unless it reached the size limit.
+func (r *execRecorder) Record(channel pb.ExecRecording_Channel, data []byte) {
+	if r == nil || len(data) == 0 {
+		return
+	}
+	if channel == pb.ExecRecording_STDIN && r.cfg.skipInput {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.size+len(data) > r.cfg.maxBytes {
+		r.rec.Truncated = true
+		return
+	}
+
+	r.size += len(data)
+	r.rec.Events = append(r.rec.Events, &pb.ExecRecording_Event{
+		Time:    ptypes.TimestampNow(),
+		Channel: channel,
+		Data:    data,
+	})
+}
+
+// Exit records the exit code of the command.
+func (r *execRecorder) Exit(code int32) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.rec.ExitCode = code
+}
+
+// finishExecRecording writes the recording of a session that ended with
+// the instance it ran in. Failures are logged since the session already
+// ended.
+func (s *service) finishExecRecording(log hclog.Logger, r *execRecorder, instanceId string) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.rec.InstanceId = instanceId
+	r.rec.EndTime = ptypes.TimestampNow()
+	if err := s.state.ExecRecordingPut(r.rec); err != nil {
+		log.Error("error writing exec recording", "err", err)
+	}
+}
+
+// pruneExecRecordings deletes the recordings that are older than the
+// retention. This runs even if recording is disabled, so recordings made
+// while it was enabled don't outlive the retention.
+func (s *service) pruneExecRecordings(log hclog.Logger) {
+	s.reloadLock.RLock()
+	retention := s.execRecordingRetention
+	s.reloadLock.RUnlock()
+	if retention <= 0 {
+		return
+	}
+
+	n, err := s.state.ExecRecordingPrune(time.Now().Add(-retention))
+	if err != nil {
+		log.Warn("error pruning exec recordings", "err", err)
+		return
+	}
+
+	log.Debug("pruned exec recordings", "recordings", n)
+}
+
+func (s *service) ListExecRecordings(
+	ctx context.Context,
+	req *pb.ListExecRecordingsRequest,
+) (*pb.ListExecRecordingsResponse, error) {
+	result, err := s.state.ExecRecordingList(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListExecRecordingsResponse{Recordings: result}, nil
+}
+
+func (s *service) GetExecRecording(
+	ctx context.Context,
+	req *pb.GetExecRecordingRequest,
+) (*pb.ExecRecording, error) {
+	return s.state.ExecRecordingGet(req.Id)
+}
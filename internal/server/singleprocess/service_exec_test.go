@@ -6,11 +6,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	configpkg "github.com/hashicorp/waypoint/internal/config"
 	"github.com/hashicorp/waypoint/internal/server"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
@@ -166,6 +169,119 @@ func TestServiceStartExecStream_eventExit(t *testing.T) {
 	require.False(active)
 }
 
+func TestServiceStartExecStream_recording(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server with recording enabled
+	impl, err := New(WithDB(testDB(t)), WithConfig(&configpkg.ServerConfig{
+		ExecRecording: &configpkg.ExecRecording{Enabled: true},
+	}))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Create an instance
+	instanceId, deploymentId, closer := TestEntrypoint(t, client)
+	defer closer()
+
+	// Start stream
+	stream, err := client.StartExecStream(ctx)
+	require.NoError(err)
+	require.NoError(stream.Send(&pb.ExecStreamRequest{
+		Event: &pb.ExecStreamRequest_Start_{
+			Start: &pb.ExecStreamRequest_Start{
+				DeploymentId: deploymentId,
+				Args:         []string{"sh"},
+			},
+		},
+	}))
+	defer stream.CloseSend()
+
+	// Should open
+	{
+		resp, err := stream.Recv()
+		require.NoError(err)
+		_, ok := resp.Event.(*pb.ExecStreamResponse_Open_)
+		require.True(ok, "should be an open")
+	}
+	exec := testGetInstanceExec(t, impl, instanceId)
+
+	// Send input and read it on the entrypoint side
+	require.NoError(stream.Send(&pb.ExecStreamRequest{
+		Event: &pb.ExecStreamRequest_Input_{
+			Input: &pb.ExecStreamRequest_Input{Data: []byte("ls\n")},
+		},
+	}))
+	<-exec.ClientEventCh
+
+	// Send output and exit
+	exec.EntrypointEventCh <- &pb.EntrypointExecRequest{
+		Event: &pb.EntrypointExecRequest_Output_{
+			Output: &pb.EntrypointExecRequest_Output{
+				Channel: pb.EntrypointExecRequest_Output_STDOUT,
+				Data:    []byte("hello\n"),
+			},
+		},
+	}
+	exec.EntrypointEventCh <- &pb.EntrypointExecRequest{
+		Event: &pb.EntrypointExecRequest_Exit_{
+			Exit: &pb.EntrypointExecRequest_Exit{Code: 2},
+		},
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			require.Equal(io.EOF, err)
+			break
+		}
+	}
+
+	// The session should be recorded
+	list, err := client.ListExecRecordings(ctx, &pb.ListExecRecordingsRequest{
+		DeploymentId: deploymentId,
+	})
+	require.NoError(err)
+	require.Len(list.Recordings, 1)
+	require.Empty(list.Recordings[0].Events)
+
+	rec, err := client.GetExecRecording(ctx, &pb.GetExecRecordingRequest{
+		Id: list.Recordings[0].Id,
+	})
+	require.NoError(err)
+	require.Equal(instanceId, rec.InstanceId)
+	require.Equal([]string{"sh"}, rec.Args)
+	require.Equal(int32(2), rec.ExitCode)
+	require.Len(rec.Events, 2)
+	require.Equal(pb.ExecRecording_STDIN, rec.Events[0].Channel)
+	require.Equal([]byte("ls\n"), rec.Events[0].Data)
+	require.Equal(pb.ExecRecording_STDOUT, rec.Events[1].Channel)
+	require.Equal([]byte("hello\n"), rec.Events[1].Data)
+}
+
+func TestServicePruneExecRecordings_disabled(t *testing.T) {
+	require := require.New(t)
+
+	// Create our server with recording disabled and a retention set
+	impl, err := New(WithDB(testDB(t)), WithConfig(&configpkg.ServerConfig{
+		ExecRecording: &configpkg.ExecRecording{Retention: "1h"},
+	}))
+	require.NoError(err)
+	s := testServiceImpl(impl)
+
+	// Write a recording from before recording was disabled
+	start, err := ptypes.TimestampProto(time.Now().Add(-2 * time.Hour))
+	require.NoError(err)
+	require.NoError(s.state.ExecRecordingPut(&pb.ExecRecording{
+		Id:        "A",
+		StartTime: start,
+	}))
+
+	// It should be pruned after the retention
+	s.pruneExecRecordings(hclog.L())
+	_, err = s.state.ExecRecordingGet("A")
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
 // When the InstanceExec EntrypointEventCh closes, we should exit.
 func TestServiceStartExecStream_entrypointEventChClose(t *testing.T) {
 	ctx := context.Background()
@@ -228,6 +344,119 @@ func TestServiceStartExecStream_entrypointEventChClose(t *testing.T) {
 	require.False(active)
 }
 
+func TestServiceStartExecStream_eventError(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Create an instance
+	instanceId, deploymentId, closer := TestEntrypoint(t, client)
+	defer closer()
+
+	// Start stream
+	stream, err := client.StartExecStream(ctx)
+	require.NoError(err)
+	require.NoError(stream.Send(&pb.ExecStreamRequest{
+		Event: &pb.ExecStreamRequest_Start_{
+			Start: &pb.ExecStreamRequest_Start{
+				DeploymentId: deploymentId,
+				Args:         []string{"foo", "bar"},
+			},
+		},
+	}))
+	defer stream.CloseSend()
+
+	// Should open
+	{
+		resp, err := stream.Recv()
+		require.NoError(err)
+		_, ok := resp.Event.(*pb.ExecStreamResponse_Open_)
+		require.True(ok, "should be an open")
+	}
+
+	// Send an error event
+	exec := testGetInstanceExec(t, impl, instanceId)
+	exec.EntrypointEventCh <- &pb.EntrypointExecRequest{
+		Event: &pb.EntrypointExecRequest_Error_{
+			Error: &pb.EntrypointExecRequest_Error{
+				Error: status.New(codes.PermissionDenied, "exec is disabled").Proto(),
+			},
+		},
+	}
+
+	// The client gets the error
+	_, err = stream.Recv()
+	require.Error(err)
+	require.Equal(codes.PermissionDenied, status.Code(err))
+	require.Contains(err.Error(), "exec is disabled")
+}
+
+func TestServiceStartExecStream_copy(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Create an instance
+	instanceId, deploymentId, closer := TestEntrypoint(t, client)
+	defer closer()
+
+	start := func(path string) pb.Waypoint_StartExecStreamClient {
+		stream, err := client.StartExecStream(ctx)
+		require.NoError(err)
+		require.NoError(stream.Send(&pb.ExecStreamRequest{
+			Event: &pb.ExecStreamRequest_Start_{
+				Start: &pb.ExecStreamRequest_Start{
+					DeploymentId: deploymentId,
+					Copy: &pb.ExecStreamRequest_FileCopy{
+						Direction: pb.ExecStreamRequest_FileCopy_TO_INSTANCE,
+						Path:      path,
+					},
+				},
+			},
+		}))
+
+		return stream
+	}
+
+	// Relative paths are an error
+	{
+		stream := start("config.yml")
+		_, err := stream.Recv()
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	}
+
+	stream := start("/app/config.yml")
+	defer stream.CloseSend()
+	{
+		resp, err := stream.Recv()
+		require.NoError(err)
+		_, ok := resp.Event.(*pb.ExecStreamResponse_Open_)
+		require.True(ok, "should be an open")
+	}
+
+	// The copy is part of the session
+	exec := testGetInstanceExec(t, impl, instanceId)
+	require.Equal("/app/config.yml", exec.Copy.GetPath())
+
+	// The end of the input is sent along
+	require.NoError(stream.Send(&pb.ExecStreamRequest{
+		Event: &pb.ExecStreamRequest_InputEof{
+			InputEof: &pb.ExecStreamRequest_InputEOF{},
+		},
+	}))
+	req := <-exec.ClientEventCh
+	_, ok := req.Event.(*pb.ExecStreamRequest_InputEof)
+	require.True(ok)
+}
+
 func testGetInstanceExec(t *testing.T, impl pb.WaypointServer, instanceId string) *state.InstanceExec {
 	ws := memdb.NewWatchSet()
 	list, err := testServiceImpl(impl).state.InstanceExecListByInstanceId(instanceId, ws)
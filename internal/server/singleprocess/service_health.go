@@ -0,0 +1,39 @@
+package singleprocess
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/hashicorp/waypoint/internal/server"
+)
+
+// HealthChecks implements server.HealthChecker. The database must be
+// accessible for the server to be live. The URL service is only checked
+// if it is enabled, and only affects readiness since deployments work
+// without it.
+func (s *service) HealthChecks(ctx context.Context) []server.HealthCheck {
+	db := server.HealthCheck{Name: "db", Live: true}
+	if _, err := s.state.ServerIdGet(); err != nil {
+		db.Err = fmt.Errorf("error reading the database: %s", err)
+	}
+	checks := []server.HealthCheck{db}
+
+	s.reloadLock.RLock()
+	cfg, conn := s.urlConfig, s.urlConn
+	s.reloadLock.RUnlock()
+	if cfg != nil && conn != nil {
+		url := server.HealthCheck{Name: "url_service"}
+		switch state := conn.GetState(); state {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			url.Err = fmt.Errorf("connection to %s is %s", cfg.APIAddress, state)
+		}
+
+		checks = append(checks, url)
+	}
+
+	return checks
+}
+
+var _ server.HealthChecker = (*service)(nil)
@@ -3,6 +3,7 @@ package singleprocess
 import (
 	"context"
 	"reflect"
+	"strconv"
 	"strings"
 
 	empty "github.com/golang/protobuf/ptypes/empty"
@@ -17,13 +18,18 @@ const (
 	hznLabelApp       = "waypoint.hashicorp.com/app"
 	hznLabelProject   = "waypoint.hashicorp.com/project"
 	hznLabelWorkspace = "waypoint.hashicorp.com/workspace"
+
+	// hznLabelDeployment is set by entrypoints to the sequence number of
+	// their deployment prefixed with "v".
+	hznLabelDeployment = ":deployment"
 )
 
 func (s *service) CreateHostname(
 	ctx context.Context,
 	req *pb.CreateHostnameRequest,
 ) (*pb.CreateHostnameResponse, error) {
-	if s.urlClient == nil {
+	_, urlClient := s.url()
+	if urlClient == nil {
 		return nil, status.Errorf(codes.FailedPrecondition,
 			"server doesn't have the URL service enabled")
 	}
@@ -52,7 +58,7 @@ func (s *service) CreateHostname(
 	}
 
 	// Make the request
-	resp, err := s.urlClient.RegisterHostname(ctx, hostnameReq)
+	resp, err := urlClient.RegisterHostname(ctx, hostnameReq)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +82,8 @@ func (s *service) ListHostnames(
 	ctx context.Context,
 	req *pb.ListHostnamesRequest,
 ) (*pb.ListHostnamesResponse, error) {
-	if s.urlClient == nil {
+	_, urlClient := s.url()
+	if urlClient == nil {
 		return nil, status.Errorf(codes.FailedPrecondition,
 			"server doesn't have the URL service enabled")
 	}
@@ -93,11 +100,13 @@ func (s *service) ListHostnames(
 		targetMap = s.hostnameLabelSetToMap(labels)
 	}
 
-	resp, err := s.urlClient.ListHostnames(ctx, &wphznpb.ListHostnamesRequest{})
+	resp, err := urlClient.ListHostnames(ctx, &wphznpb.ListHostnamesRequest{})
 	if err != nil {
 		return nil, err
 	}
 
+	org := authOrganization(ctx)
+
 	result := make([]*pb.Hostname, 0, len(resp.Hostnames))
 	for _, item := range resp.Hostnames {
 		labelsMap := s.hostnameLabelSetToMap(item.Labels)
@@ -109,6 +118,11 @@ func (s *service) ListHostnames(
 			continue
 		}
 
+		// Hostnames are namespaced by the organization of their project.
+		if !s.organizationHasProject(org, labelsMap[hznLabelProject]) {
+			continue
+		}
+
 		result = append(result, &pb.Hostname{
 			Hostname:     item.Hostname,
 			Fqdn:         item.Fqdn,
@@ -123,12 +137,33 @@ func (s *service) DeleteHostname(
 	ctx context.Context,
 	req *pb.DeleteHostnameRequest,
 ) (*empty.Empty, error) {
-	if s.urlClient == nil {
+	_, urlClient := s.url()
+	if urlClient == nil {
 		return nil, status.Errorf(codes.FailedPrecondition,
 			"server doesn't have the URL service enabled")
 	}
 
-	_, err := s.urlClient.DeleteHostname(ctx, &wphznpb.DeleteHostnameRequest{
+	// Callers in an organization can only delete the hostnames of the
+	// organization, which are the ones that they can list.
+	if authOrganization(ctx) != "" {
+		resp, err := s.ListHostnames(ctx, &pb.ListHostnamesRequest{})
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, h := range resp.Hostnames {
+			if h.Hostname == req.Hostname {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, status.Errorf(codes.NotFound, "hostname not found: %s", req.Hostname)
+		}
+	}
+
+	_, err := urlClient.DeleteHostname(ctx, &wphznpb.DeleteHostnameRequest{
 		Hostname: req.Hostname,
 	})
 	if err != nil {
@@ -149,6 +184,14 @@ func (s *service) hostnameLabelSet(target *pb.Hostname_Target) (*wphznpb.LabelSe
 			&wphznpb.Label{Name: hznLabelWorkspace, Value: t.Application.Workspace.Workspace},
 		)
 
+	case *pb.Hostname_Target_Deployment:
+		labels.Labels = append(labels.Labels,
+			&wphznpb.Label{Name: hznLabelApp, Value: t.Deployment.Application.Application},
+			&wphznpb.Label{Name: hznLabelProject, Value: t.Deployment.Application.Project},
+			&wphznpb.Label{Name: hznLabelWorkspace, Value: t.Deployment.Workspace.Workspace},
+			&wphznpb.Label{Name: hznLabelDeployment, Value: "v" + strconv.FormatUint(t.Deployment.Sequence, 10)},
+		)
+
 	default:
 		return nil, status.Errorf(codes.FailedPrecondition, "invalid target type")
 	}
@@ -142,4 +142,47 @@ func TestServiceHostname(t *testing.T) {
 			require.Empty(resp.Hostnames)
 		}
 	})
+
+	t.Run("deployment target", func(t *testing.T) {
+		require := require.New(t)
+
+		// Create our server
+		impl, err := New(WithDB(testDB(t)), TestWithURLService(t, nil))
+		require.NoError(err)
+		client := server.TestServer(t, impl)
+
+		target := &pb.Hostname_Target{
+			Target: &pb.Hostname_Target_Deployment{
+				Deployment: &pb.Hostname_TargetDeployment{
+					Application: &pb.Ref_Application{
+						Application: "web",
+						Project:     "test",
+					},
+
+					Workspace: &pb.Ref_Workspace{
+						Workspace: "default",
+					},
+
+					Sequence: 3,
+				},
+			},
+		}
+
+		// Create a hostname
+		resp, err := client.CreateHostname(ctx, &pb.CreateHostnameRequest{
+			Target: target,
+		})
+		require.NoError(err)
+		require.Equal("v3", resp.Hostname.TargetLabels[hznLabelDeployment])
+		require.Equal("web", resp.Hostname.TargetLabels[hznLabelApp])
+
+		// Should be able to filter by the deployment
+		{
+			resp, err := client.ListHostnames(ctx, &pb.ListHostnamesRequest{
+				Target: target,
+			})
+			require.NoError(err)
+			require.Len(resp.Hostnames, 1)
+		}
+	})
 }
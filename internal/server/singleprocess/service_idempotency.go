@@ -0,0 +1,92 @@
+package singleprocess
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// idempotencyKeyTTL is how long the idempotency keys of requests are kept.
+// Retries of a request with a key must happen within this time.
+var idempotencyKeyTTL = 24 * time.Hour
+
+// idempotencyWait is how long a request waits for the record of another
+// request with the same idempotency key, checking every
+// idempotencyPollInterval.
+var (
+	idempotencyWait         = 10 * time.Second
+	idempotencyPollInterval = 100 * time.Millisecond
+)
+
+// idempotent calls create unless a record was already created with the
+// idempotency key, in which case the ID of that record is returned and
+// created is false. Keys are scoped to the kind of record and its app, so
+// that clients only need to choose keys that are unique for themselves.
+func (s *service) idempotent(
+	kind string,
+	app *pb.Ref_Application,
+	key string,
+	create func() (string, error),
+) (id string, created bool, err error) {
+	scoped := strings.ToLower(kind+"/"+app.GetProject()+"/"+app.GetApplication()) + "/" + key
+
+	// Reserve the key in the state before creating the record so that
+	// concurrent retries don't both create one, even if they are sent to
+	// different servers sharing the database. Retries that find the key
+	// reserved wait for the record.
+	deadline := time.Now().Add(idempotencyWait)
+	for {
+		var reserved bool
+		id, reserved, err = s.state.IdempotencyKeyReserve(scoped)
+		if err != nil {
+			return "", false, err
+		}
+		if id != "" {
+			return id, false, nil
+		}
+		if reserved {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return "", false, status.Errorf(codes.Aborted,
+				"a request with the idempotency key %q is still in progress, retry later", key)
+		}
+		time.Sleep(idempotencyPollInterval)
+	}
+
+	id, err = create()
+	if err != nil {
+		if err := s.state.IdempotencyKeyRelease(scoped); err != nil {
+			s.log.Warn("error releasing idempotency key", "kind", kind, "err", err)
+		}
+
+		return "", false, err
+	}
+
+	// The record exists now, so a failure to record the key only means
+	// that a retry could create another one.
+	if err := s.state.IdempotencyKeyPut(scoped, id); err != nil {
+		s.log.Warn("error recording idempotency key", "kind", kind, "id", id, "err", err)
+	}
+
+	return id, true, nil
+}
+
+// pruneIdempotencyKeys deletes the idempotency keys that are older than
+// idempotencyKeyTTL.
+func (s *service) pruneIdempotencyKeys(log hclog.Logger) {
+	n, err := s.state.IdempotencyKeyPrune(time.Now().Add(-idempotencyKeyTTL))
+	if err != nil {
+		log.Warn("error pruning idempotency keys", "err", err)
+		return
+	}
+	if n > 0 {
+		log.Debug("pruned idempotency keys", "count", n)
+	}
+}
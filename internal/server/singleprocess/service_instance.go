@@ -2,6 +2,7 @@ package singleprocess
 
 import (
 	"context"
+	"sort"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -35,10 +36,25 @@ func (s *service) ListInstances(
 		return nil, err
 	}
 
-	final := make([]*pb.Instance, len(result))
-	for i, r := range result {
-		final[i] = r.Proto()
+	// Instances come and go so pages are in the order of their ID. The
+	// next page starts after the last ID of the previous page even if that
+	// instance is gone by then.
+	sort.Slice(result, func(i, j int) bool { return result[i].Id < result[j].Id })
+
+	var final []*pb.Instance
+	var next string
+	for _, r := range result {
+		if req.PageToken != "" && r.Id <= req.PageToken {
+			continue
+		}
+
+		if req.PageSize > 0 && len(final) >= int(req.PageSize) {
+			next = final[len(final)-1].Id
+			break
+		}
+
+		final = append(final, r.Proto())
 	}
 
-	return &pb.ListInstancesResponse{Instances: final}, nil
+	return &pb.ListInstancesResponse{Instances: final, NextPageToken: next}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hashicorp/go-hclog"
@@ -39,7 +40,7 @@ func (s *service) XListJobs(
 	ctx context.Context,
 	req *pb.ListJobsRequest,
 ) (*pb.ListJobsResponse, error) {
-	jobs, err := s.state.JobList()
+	jobs, err := s.state.JobList(req)
 	if err != nil {
 		return nil, err
 	}
@@ -66,10 +67,37 @@ func (s *service) QueueJob(
 ) (*pb.QueueJobResponse, error) {
 	job := req.Job
 
+	// Jobs aren't assigned while the server shuts down, so don't accept
+	// new ones that would wait for the next server.
+	if s.draining() {
+		return nil, status.Errorf(codes.Unavailable,
+			"server is shutting down, queue the job again once it is back")
+	}
+
 	// Validation
 	if job == nil {
 		return nil, status.Errorf(codes.FailedPrecondition, "job must be set")
 	}
+
+	// A retried request returns the job that the first request queued.
+	if req.IdempotencyKey != "" {
+		id, _, err := s.idempotent("job", job.Application, req.IdempotencyKey, func() (string, error) {
+			resp, err := s.QueueJob(ctx, &pb.QueueJobRequest{
+				Job:       job,
+				ExpiresIn: req.ExpiresIn,
+			})
+			if err != nil {
+				return "", err
+			}
+
+			return resp.JobId, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &pb.QueueJobResponse{JobId: id}, nil
+	}
 	if err := serverptypes.ValidateJob(job); err != nil {
 		return nil, status.Errorf(codes.FailedPrecondition, err.Error())
 	}
@@ -107,6 +135,18 @@ func (s *service) QueueJob(
 		job.DataSource = project.DataSource
 	}
 
+	// Use the default priority of the project if the job has none.
+	if job.Priority == 0 {
+		job.Priority = project.Jobs.GetPriority()
+	}
+	job.QueuePosition = 0
+
+	// Jobs that can run on any runner target the runners of their profile.
+	job.TargetRunner, err = s.jobTargetRunner(job)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the next id
 	id, err := server.Id()
 	if err != nil {
@@ -137,6 +177,55 @@ func (s *service) QueueJob(
 	return &pb.QueueJobResponse{JobId: job.Id}, nil
 }
 
+// jobTargetRunner returns the runners that the job targets once it is
+// queued. Jobs that target any runner target the runners of the first
+// runner profile of the server config that matches them, if any do.
+func (s *service) jobTargetRunner(job *pb.Job) (*pb.Ref_Runner, error) {
+	if _, ok := job.TargetRunner.Target.(*pb.Ref_Runner_Any); !ok {
+		return job.TargetRunner, nil
+	}
+
+	cfg, err := s.state.ServerConfigGet()
+	if err != nil {
+		return nil, err
+	}
+
+	if p := runnerProfileFor(cfg, job); p != nil {
+		return p.TargetRunner, nil
+	}
+
+	return job.TargetRunner, nil
+}
+
+// runnerProfileFor returns the first runner profile of the server config
+// that matches the job, or nil if none do.
+func runnerProfileFor(cfg *pb.ServerConfig, job *pb.Job) *pb.ServerConfig_RunnerProfile {
+	for _, p := range cfg.GetRunnerProfiles() {
+		if p.Project != "" && p.Project != job.Application.GetProject() {
+			continue
+		}
+		if p.Application != "" && p.Application != job.Application.GetApplication() {
+			continue
+		}
+		if p.Workspace != "" && p.Workspace != job.Workspace.GetWorkspace() {
+			continue
+		}
+
+		match := true
+		for k, v := range p.JobLabels {
+			if job.Labels[k] != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return p
+		}
+	}
+
+	return nil
+}
+
 func (s *service) ValidateJob(
 	ctx context.Context,
 	req *pb.ValidateJobRequest,
@@ -151,8 +240,14 @@ func (s *service) ValidateJob(
 		return result, nil
 	}
 
-	// Check assignability
-	result.Assignable, err = s.state.JobIsAssignable(ctx, req.Job)
+	// Check assignability to the runners that the job would target once
+	// it is queued.
+	job := proto.Clone(req.Job).(*pb.Job)
+	job.TargetRunner, err = s.jobTargetRunner(job)
+	if err != nil {
+		return nil, err
+	}
+	result.Assignable, err = s.state.JobIsAssignable(ctx, job)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +262,10 @@ func (s *service) GetJobStream(
 	log := hclog.FromContext(server.Context())
 	ctx := server.Context()
 
+	if err := s.Authorize(ctx, "GetJobStream", req); err != nil {
+		return err
+	}
+
 	// Get the job
 	ws := memdb.NewWatchSet()
 	job, err := s.state.JobById(req.JobId, ws)
@@ -224,7 +323,9 @@ func (s *service) GetJobStream(
 
 	// Enter the event loop
 	var lastState pb.Job_State
+	var lastPosition uint32
 	var cancelSent bool
+	var storedSent bool
 	var eventsCh <-chan []*pb.GetJobStreamResponse_Terminal_Event
 	for {
 		select {
@@ -238,9 +339,11 @@ func (s *service) GetJobStream(
 			log.Debug("job state change", "state", job.State)
 
 			// If we have a state change, send that event down. We also send
-			// down a state change if we enter a "cancelled" scenario.
+			// down a state change if we enter a "cancelled" scenario or if
+			// the position of a queued job changes.
 			canceling := job.CancelTime != nil
-			if lastState != job.State || cancelSent != canceling {
+			if lastState != job.State || cancelSent != canceling ||
+				lastPosition != job.QueuePosition {
 				if err := server.Send(&pb.GetJobStreamResponse{
 					Event: &pb.GetJobStreamResponse_State_{
 						State: &pb.GetJobStreamResponse_State{
@@ -255,9 +358,36 @@ func (s *service) GetJobStream(
 				}
 
 				lastState = job.State
+				lastPosition = job.QueuePosition
 				cancelSent = canceling
 			}
 
+			// If the job was already complete when we started streaming, send
+			// the stored output. The output buffer may have dropped the start
+			// of the output, or may not exist if the server restarted.
+			if eventsCh == nil && !storedSent {
+				switch job.State {
+				case pb.Job_SUCCESS, pb.Job_ERROR:
+					storedSent = true
+					output, err := s.state.JobOutputGet(job.Id)
+					if status.Code(err) == codes.NotFound {
+						break
+					}
+					if err != nil {
+						return err
+					}
+
+					output.Buffered = true
+					if err := server.Send(&pb.GetJobStreamResponse{
+						Event: &pb.GetJobStreamResponse_Terminal_{Terminal: output},
+					}); err != nil {
+						return err
+					}
+
+					job.OutputBuffer = nil
+				}
+			}
+
 			// If we haven't initialized output streaming and the output buffer
 			// is now non-nil, initialize that. This will send any buffered
 			// data down.
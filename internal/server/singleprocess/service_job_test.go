@@ -46,6 +46,24 @@ func TestServiceQueueJob(t *testing.T) {
 		require.Equal(pb.Job_QUEUED, job.State)
 	})
 
+	t.Run("idempotency key", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := client.QueueJob(ctx, &Req{
+			Job:            serverptypes.TestJobNew(t, nil),
+			IdempotencyKey: "A",
+		})
+		require.NoError(err)
+
+		// A retry returns the same job
+		resp2, err := client.QueueJob(ctx, &Req{
+			Job:            serverptypes.TestJobNew(t, nil),
+			IdempotencyKey: "A",
+		})
+		require.NoError(err)
+		require.Equal(resp.JobId, resp2.JobId)
+	})
+
 	t.Run("expiration", func(t *testing.T) {
 		require := require.New(t)
 
@@ -67,6 +85,71 @@ func TestServiceQueueJob(t *testing.T) {
 	})
 }
 
+func TestServiceQueueJob_runnerProfile(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	client := server.TestServer(t, impl)
+
+	// Initialize our app
+	TestApp(t, client, serverptypes.TestJobNew(t, nil).Application)
+
+	dockerTarget := &pb.Ref_Runner{
+		Target: &pb.Ref_Runner_Labels{
+			Labels: &pb.Ref_RunnerLabels{
+				Labels: map[string]string{"docker": "true"},
+			},
+		},
+	}
+	_, err = client.SetServerConfig(ctx, &pb.SetServerConfigRequest{
+		Config: serverptypes.TestServerConfig(t, &pb.ServerConfig{
+			RunnerProfiles: []*pb.ServerConfig_RunnerProfile{
+				{
+					Name:         "other",
+					Project:      "other",
+					TargetRunner: &pb.Ref_Runner{Target: &pb.Ref_Runner_Id{Id: &pb.Ref_RunnerId{Id: "A"}}},
+				},
+				{
+					Name:         "docker",
+					Project:      "p_test",
+					JobLabels:    map[string]string{"zone": "dmz"},
+					TargetRunner: dockerTarget,
+				},
+			},
+		}),
+	})
+	require.NoError(err)
+
+	queue := func(job *pb.Job) *pb.Job {
+		resp, err := client.QueueJob(ctx, &pb.QueueJobRequest{Job: job})
+		require.NoError(err)
+
+		result, err := testServiceImpl(impl).state.JobById(resp.JobId, nil)
+		require.NoError(err)
+		return result.Job
+	}
+
+	// A job with the labels of the profile targets its runners
+	job := queue(serverptypes.TestJobNew(t, &pb.Job{
+		Labels: map[string]string{"zone": "dmz"},
+	}))
+	require.Equal(map[string]string{"docker": "true"}, job.TargetRunner.GetLabels().Labels)
+
+	// A job without them still targets any runner
+	job = queue(serverptypes.TestJobNew(t, nil))
+	require.IsType((*pb.Ref_Runner_Any)(nil), job.TargetRunner.Target)
+
+	// A job that targets a runner keeps its target
+	job = queue(serverptypes.TestJobNew(t, &pb.Job{
+		Labels:       map[string]string{"zone": "dmz"},
+		TargetRunner: &pb.Ref_Runner{Target: &pb.Ref_Runner_Id{Id: &pb.Ref_RunnerId{Id: "B"}}},
+	}))
+	require.Equal("B", job.TargetRunner.GetId().Id)
+}
+
 func TestServiceValidateJob(t *testing.T) {
 	ctx := context.Background()
 
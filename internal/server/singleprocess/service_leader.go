@@ -0,0 +1,130 @@
+package singleprocess
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
+)
+
+var (
+	// leaderRetryInterval is how long to wait before trying to become the
+	// leader again after an error.
+	leaderRetryInterval = 5 * time.Second
+
+	// indexSyncInterval is how often to check for writes from other servers.
+	indexSyncInterval = 1 * time.Second
+)
+
+// runLeaderElection tries to become the leader of the servers sharing db
+// until ctx is done. While this server is the leader it assigns jobs and
+// runs the job timers.
+func (s *service) runLeaderElection(ctx context.Context, log hclog.Logger, db state.SharedDB) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Each term has its own context so we can give up leadership
+		// without stopping the election.
+		termCtx, cancel := context.WithCancel(ctx)
+
+		log.Debug("waiting for leadership")
+		lostCh, err := db.Lead(termCtx)
+		if err != nil {
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Error("error acquiring leadership", "err", err)
+			sleepCtx(ctx, leaderRetryInterval)
+			continue
+		}
+
+		// Rebuild our indexes before taking over so we have the latest
+		// jobs and restart their timers, which don't run on followers.
+		if err := s.state.Reindex(); err != nil {
+			log.Error("error rebuilding indexes, giving up leadership", "err", err)
+			cancel()
+			<-lostCh
+			sleepCtx(ctx, leaderRetryInterval)
+			continue
+		}
+
+		// Let the followers know where to send runners. This isn't fatal
+		// since runners that connect to a follower still retry.
+		if err := s.state.LeaderAddrSet(s.leaderAddr); err != nil {
+			log.Warn("error setting the address of the leader", "err", err)
+		}
+
+		log.Info("acquired leadership")
+		s.state.SetLeader(true)
+
+		select {
+		case <-lostCh:
+			log.Warn("lost leadership")
+		case <-ctx.Done():
+			log.Info("giving up leadership")
+		}
+
+		s.state.SetLeader(false)
+		cancel()
+	}
+}
+
+// notLeaderError converts state.ErrNotLeader to an error with the address
+// of the leader, so that runners can reconnect to it. Other errors are
+// returned as they are.
+func (s *service) notLeaderError(err error) error {
+	if err != state.ErrNotLeader {
+		return err
+	}
+
+	addr, aerr := s.state.LeaderAddrGet()
+	if aerr != nil || addr == s.leaderAddr || s.state.IsLeader() {
+		addr = ""
+	}
+
+	return server.NotLeaderError(addr)
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+}
+
+// runIndexSync rebuilds the in-memory indexes whenever another server
+// writes to db, so that every server returns up to date results.
+func (s *service) runIndexSync(ctx context.Context, log hclog.Logger, db state.SharedDB) {
+	ticker := time.NewTicker(indexSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		changed, err := db.Changed()
+		if err != nil {
+			log.Warn("error checking for changes from other servers", "err", err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		log.Trace("rebuilding indexes after changes from other servers")
+		if err := s.state.Reindex(); err != nil {
+			log.Error("error rebuilding indexes", "err", err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package singleprocess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
+)
+
+func TestServiceNotLeaderError(t *testing.T) {
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+	s.leaderAddr = "b.example.com:9701"
+	s.state.SetLeader(false)
+
+	// Runners are sent to the leader
+	require.NoError(s.state.LeaderAddrSet("a.example.com:9701"))
+	err = s.notLeaderError(state.ErrNotLeader)
+	require.Equal(codes.Unavailable, status.Code(err))
+	require.Equal("a.example.com:9701", server.LeaderAddr(err))
+
+	// Not to ourselves, such as when the leader hasn't changed the address yet
+	require.NoError(s.state.LeaderAddrSet("b.example.com:9701"))
+	err = s.notLeaderError(state.ErrNotLeader)
+	require.Equal(codes.Unavailable, status.Code(err))
+	require.Equal("", server.LeaderAddr(err))
+
+	// Other errors are kept
+	other := status.Errorf(codes.NotFound, "nope")
+	require.Equal(other, s.notLeaderError(other))
+}
@@ -1,13 +1,18 @@
 package singleprocess
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/pkg/structlog"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	"github.com/hashicorp/waypoint/internal/server/singleprocess/state"
 )
@@ -15,6 +20,154 @@ import (
 // defaultLogLimitBacklog is the default backlog amount to send down.
 const defaultLogLimitBacklog = 100
 
+const (
+	// defaultLogHistoryMaxLines and defaultLogHistoryMaxAge are used if
+	// the configuration doesn't set them.
+	defaultLogHistoryMaxLines = 2000
+	defaultLogHistoryMaxAge   = 24 * time.Hour
+
+	// defaultGetLogsLimit is the default number of lines GetLogs returns.
+	defaultGetLogsLimit = 1000
+)
+
+// logFlushInterval is how often the persisted log lines are written to
+// the database. Lines are written in batches rather than as they arrive
+// since entrypoints send many small batches.
+var logFlushInterval = time.Second
+
+// initLogs configures the history of log lines. If cfg is nil, the
+// defaults are used.
+func (s *service) initLogs(cfg *configpkg.Logs) error {
+	if cfg == nil {
+		cfg = &configpkg.Logs{}
+	}
+
+	maxLines := cfg.MaxLines
+	if maxLines == 0 {
+		maxLines = defaultLogHistoryMaxLines
+	}
+
+	s.logMaxAge = defaultLogHistoryMaxAge
+	if cfg.MaxAge != "" {
+		d, err := time.ParseDuration(cfg.MaxAge)
+		if err != nil {
+			return fmt.Errorf("invalid logs max age: %s", err)
+		}
+
+		s.logMaxAge = d
+	}
+
+	s.state.SetLogHistory(state.LogHistoryConfig{
+		MaxLines: maxLines,
+		Persist:  cfg.Persist,
+	})
+	return nil
+}
+
+func (s *service) GetLogs(
+	ctx context.Context,
+	req *pb.GetLogsRequest,
+) (*pb.GetLogsResponse, error) {
+	switch scope := req.Scope.(type) {
+	case *pb.GetLogsRequest_DeploymentId:
+		if scope.DeploymentId == "" {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"deployment scope requires the deployment ID to be set")
+		}
+
+	case *pb.GetLogsRequest_Application:
+		if scope.Application == nil ||
+			scope.Application.Application == nil ||
+			scope.Application.Workspace == nil {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"application scope requires the application and workspace fields to be set",
+			)
+		}
+
+	default:
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"invalid scope supplied: %T",
+			req.Scope,
+		)
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultGetLogsLimit
+	}
+
+	level, err := logLevel(req.Level)
+	if err != nil {
+		return nil, err
+	}
+	req.Level = level
+
+	lines, truncated, err := s.state.LogHistoryList(req, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetLogsResponse{Lines: lines, Truncated: truncated}, nil
+}
+
+// logLevel returns the normalized level of a request that filters log
+// lines by their level, or an empty string if it doesn't.
+func logLevel(v string) (string, error) {
+	if v == "" {
+		return "", nil
+	}
+
+	level, err := structlog.ParseLevel(v)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+
+	return level, nil
+}
+
+// runLogFlush writes the persisted log lines to the database every
+// logFlushInterval until ctx is done.
+func (s *service) runLogFlush(ctx context.Context, log hclog.Logger) {
+	tick := time.NewTicker(logFlushInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.state.LogHistoryFlush(); err != nil {
+				log.Warn("error writing log history", "err", err)
+			}
+
+			return
+
+		case <-tick.C:
+		}
+
+		if err := s.state.LogHistoryFlush(); err != nil {
+			log.Warn("error writing log history", "err", err)
+		}
+	}
+}
+
+// pruneLogs removes the log lines that are older than the maximum age.
+// Each server keeps the lines of its own entrypoints, so this runs on
+// every server and not only the leader.
+func (s *service) pruneLogs(log hclog.Logger) {
+	if s.logMaxAge <= 0 {
+		return
+	}
+
+	n, err := s.state.LogHistoryPrune(time.Now().Add(-s.logMaxAge))
+	if err != nil {
+		log.Warn("error pruning log history", "err", err)
+		return
+	}
+
+	log.Debug("pruned log history", "lines", n)
+}
+
 // TODO: test
 func (s *service) GetLogStream(
 	req *pb.GetLogStreamRequest,
@@ -22,11 +175,21 @@ func (s *service) GetLogStream(
 ) error {
 	log := hclog.FromContext(srv.Context())
 
+	if err := s.Authorize(srv.Context(), "GetLogStream", req); err != nil {
+		return err
+	}
+
 	// Default the limit
 	if req.LimitBacklog == 0 {
 		req.LimitBacklog = defaultLogLimitBacklog
 	}
 
+	// Only send the lines with at least the level if it is set.
+	level, err := logLevel(req.Level)
+	if err != nil {
+		return err
+	}
+
 	var instanceFunc func(ws memdb.WatchSet) ([]*state.Instance, error)
 	switch scope := req.Scope.(type) {
 	case *pb.GetLogStreamRequest_DeploymentId:
@@ -114,9 +277,17 @@ func (s *service) GetLogStream(
 						return
 					}
 
-					lines := make([]*pb.LogBatch_Entry, len(entries))
-					for i, v := range entries {
-						lines[i] = v.(*pb.LogBatch_Entry)
+					lines := make([]*pb.LogBatch_Entry, 0, len(entries))
+					for _, v := range entries {
+						entry := v.(*pb.LogBatch_Entry)
+						if level != "" && !structlog.AtLeast(entry.Level, level) {
+							continue
+						}
+
+						lines = append(lines, entry)
+					}
+					if len(lines) == 0 {
+						continue
 					}
 
 					instanceLog.Trace("sending instance log data", "entries", len(entries))
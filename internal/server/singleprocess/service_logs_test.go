@@ -6,7 +6,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/waypoint/internal/server"
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
@@ -158,3 +161,161 @@ func TestServiceGetLogStream_byApp(t *testing.T) {
 	require.NotEmpty(batch.Lines)
 	require.Len(batch.Lines, 25)
 }
+
+func TestServiceGetLogs(t *testing.T) {
+	ctx := context.Background()
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(t, err)
+	client := server.TestServer(t, impl)
+
+	// Register our instances
+	resp, err := client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+		Deployment: serverptypes.TestValidDeployment(t, &pb.Deployment{
+			Component: &pb.Component{
+				Name: "testapp",
+			},
+		}),
+	})
+
+	require.NoError(t, err)
+
+	dep := resp.Deployment
+	configClient, err := client.EntrypointConfig(ctx, &pb.EntrypointConfigRequest{
+		DeploymentId: dep.Id,
+		InstanceId:   "1",
+	})
+	require.NoError(t, err)
+	_, err = configClient.Recv()
+	require.NoError(t, err)
+
+	require := require.New(t)
+
+	// Send some log messages
+	logSendClient, err := client.EntrypointLogStream(ctx)
+	require.NoError(err)
+	var entries []*pb.LogBatch_Entry
+	for i := 0; i < 5; i++ {
+		level := "info"
+		if i == 1 || i == 4 {
+			level = "error"
+		}
+
+		entries = append(entries, &pb.LogBatch_Entry{
+			Timestamp: ptypes.TimestampNow(),
+			Line:      strconv.Itoa(i),
+			Level:     level,
+		})
+	}
+	require.NoError(logSendClient.Send(&pb.EntrypointLogBatch{
+		InstanceId: "1",
+		Lines:      entries,
+	}))
+	time.Sleep(100 * time.Millisecond)
+
+	// Get the latest lines
+	logsResp, err := client.GetLogs(ctx, &pb.GetLogsRequest{
+		Scope: &pb.GetLogsRequest_DeploymentId{
+			DeploymentId: dep.Id,
+		},
+		Limit: 3,
+	})
+	require.NoError(err)
+	require.True(logsResp.Truncated)
+	require.Len(logsResp.Lines, 3)
+	require.Equal("2", logsResp.Lines[0].Entry.Line)
+	require.Equal("1", logsResp.Lines[0].InstanceId)
+
+	// Lines before the range aren't returned
+	after, err := ptypes.TimestampProto(time.Now().Add(time.Minute))
+	require.NoError(err)
+	logsResp, err = client.GetLogs(ctx, &pb.GetLogsRequest{
+		Scope: &pb.GetLogsRequest_DeploymentId{
+			DeploymentId: dep.Id,
+		},
+		TimeRange: &pb.TimeRange{After: after},
+	})
+	require.NoError(err)
+	require.Empty(logsResp.Lines)
+
+	// Only lines with at least the level are returned
+	logsResp, err = client.GetLogs(ctx, &pb.GetLogsRequest{
+		Scope: &pb.GetLogsRequest_DeploymentId{
+			DeploymentId: dep.Id,
+		},
+		Level: "WARNING",
+	})
+	require.NoError(err)
+	require.Len(logsResp.Lines, 2)
+	require.Equal("1", logsResp.Lines[0].Entry.Line)
+	require.Equal("4", logsResp.Lines[1].Entry.Line)
+
+	// Unknown levels are an error
+	_, err = client.GetLogs(ctx, &pb.GetLogsRequest{
+		Scope: &pb.GetLogsRequest_DeploymentId{
+			DeploymentId: dep.Id,
+		},
+		Level: "verbose",
+	})
+	require.Error(err)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestServiceGetLogStream_level(t *testing.T) {
+	ctx := context.Background()
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(t, err)
+	client := server.TestServer(t, impl)
+
+	// Register our instances
+	resp, err := client.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+		Deployment: serverptypes.TestValidDeployment(t, &pb.Deployment{
+			Component: &pb.Component{
+				Name: "testapp",
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	dep := resp.Deployment
+	configClient, err := client.EntrypointConfig(ctx, &pb.EntrypointConfigRequest{
+		DeploymentId: dep.Id,
+		InstanceId:   "1",
+	})
+	require.NoError(t, err)
+	_, err = configClient.Recv()
+	require.NoError(t, err)
+
+	require := require.New(t)
+
+	// Send lines with and without levels
+	logSendClient, err := client.EntrypointLogStream(ctx)
+	require.NoError(err)
+	require.NoError(logSendClient.Send(&pb.EntrypointLogBatch{
+		InstanceId: "1",
+		Lines: []*pb.LogBatch_Entry{
+			{Line: "text"},
+			{Line: "debug", Level: "debug"},
+			{Line: "warn", Level: "warn"},
+			{Line: "fatal", Level: "fatal"},
+		},
+	}))
+	time.Sleep(100 * time.Millisecond)
+
+	logRecvClient, err := client.GetLogStream(ctx, &pb.GetLogStreamRequest{
+		Scope: &pb.GetLogStreamRequest_DeploymentId{
+			DeploymentId: dep.Id,
+		},
+		Level: "warn",
+	})
+	require.NoError(err)
+
+	batch, err := logRecvClient.Recv()
+	require.NoError(err)
+	require.Len(batch.Lines, 2)
+	require.Equal("warn", batch.Lines[0].Line)
+	require.Equal("fatal", batch.Lines[1].Line)
+}
@@ -0,0 +1,114 @@
+package singleprocess
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var (
+	// maintenanceInterval is how often the database is checked in the
+	// background.
+	maintenanceInterval = 24 * time.Hour
+
+	// The database is compacted in the background if at least this much
+	// of it, and at least half of it, is free space. Transactions wait
+	// while the database is compacted, so it isn't done for little gain.
+	maintenanceCompactMinFree int64 = 64 * 1024 * 1024
+)
+
+func (s *service) RunMaintenance(
+	ctx context.Context,
+	req *pb.RunMaintenanceRequest,
+) (*pb.RunMaintenanceResponse, error) {
+	log := hclog.FromContext(ctx)
+	return s.maintain(log, !req.CheckOnly, 0)
+}
+
+// maintain checks the database and compacts it if compact is true and
+// the check finds no problems. The database is only compacted if it has
+// at least minFree bytes of free space.
+func (s *service) maintain(log hclog.Logger, compact bool, minFree int64) (*pb.RunMaintenanceResponse, error) {
+	var result pb.RunMaintenanceResponse
+	var err error
+	result.SizeBefore, result.FreeBefore, err = s.state.DBSize()
+	if err != nil {
+		return nil, err
+	}
+
+	result.Problems, err = s.state.DBCheck()
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Problems) > 0 {
+		// Don't rewrite a damaged database. Restoring a snapshot is safer.
+		log.Error("database integrity check failed", "problems", result.Problems)
+		result.SizeAfter = result.SizeBefore
+		return &result, nil
+	}
+
+	if compact && result.FreeBefore >= minFree {
+		start := time.Now()
+		if err := s.state.DBCompact(); err != nil {
+			return nil, status.Errorf(codes.Internal, "error compacting database: %s", err)
+		}
+
+		result.Compacted = true
+		log.Info("compacted database", "duration", time.Since(start))
+	}
+
+	result.SizeAfter, _, err = s.state.DBSize()
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// runMaintenance checks the database every maintenanceInterval until ctx
+// is done, and compacts it if it is mostly free space.
+func (s *service) runMaintenance(ctx context.Context, log hclog.Logger) {
+	tick := time.NewTicker(maintenanceInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+
+		size, _, err := s.state.DBSize()
+		if status.Code(err) == codes.Unimplemented {
+			// The database is maintained by its own tools.
+			return
+		}
+		if err != nil {
+			log.Warn("error reading database size", "err", err)
+			continue
+		}
+
+		minFree := maintenanceCompactMinFree
+		if half := size / 2; half > minFree {
+			minFree = half
+		}
+
+		result, err := s.maintain(log, true, minFree)
+		if err != nil {
+			log.Warn("error maintaining database", "err", err)
+			continue
+		}
+
+		log.Debug("database maintenance done",
+			"size_before", result.SizeBefore,
+			"free_before", result.FreeBefore,
+			"size_after", result.SizeAfter,
+			"compacted", result.Compacted,
+		)
+	}
+}
@@ -0,0 +1,45 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestServiceRunMaintenance(t *testing.T) {
+	ctx := context.Background()
+
+	// Create our server
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(t, err)
+	client := server.TestServer(t, impl)
+
+	t.Run("check only", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := client.RunMaintenance(ctx, &pb.RunMaintenanceRequest{CheckOnly: true})
+		require.NoError(err)
+		require.Empty(resp.Problems)
+		require.False(resp.Compacted)
+		require.Equal(resp.SizeBefore, resp.SizeAfter)
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := client.RunMaintenance(ctx, &pb.RunMaintenanceRequest{})
+		require.NoError(err)
+		require.Empty(resp.Problems)
+		require.True(resp.Compacted)
+		require.True(resp.SizeAfter > 0)
+
+		// The server still works
+		_, err = client.ListProjects(ctx, &empty.Empty{})
+		require.NoError(err)
+	})
+}
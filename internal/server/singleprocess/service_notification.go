@@ -0,0 +1,365 @@
+package singleprocess
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var (
+	// notifyClient is the HTTP client that notifications are sent with.
+	notifyClient = &http.Client{Timeout: 10 * time.Second}
+
+	// notifyAttempts is how many times delivering a notification is tried
+	// and notifyRetryWait how long to wait after the first failed attempt.
+	// The wait doubles after each attempt.
+	notifyAttempts  = 3
+	notifyRetryWait = 2 * time.Second
+)
+
+func (s *service) UpsertNotificationSink(
+	ctx context.Context,
+	req *pb.UpsertNotificationSinkRequest,
+) (*pb.UpsertNotificationSinkResponse, error) {
+	sink := req.Sink
+	if sink == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "sink must be set")
+	}
+	if sink.Project.GetProject() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "sink must have a project")
+	}
+	for _, typ := range sink.Events {
+		if _, ok := pb.Event_Type_name[int32(typ)]; !ok || typ == pb.Event_UNKNOWN {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown event type: %d", typ)
+		}
+	}
+
+	var rawURL string
+	switch v := sink.Sink.(type) {
+	case *pb.NotificationSink_Webhook_:
+		rawURL = v.Webhook.Url
+	case *pb.NotificationSink_Slack_:
+		rawURL = v.Slack.Url
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "sink must have a webhook or Slack URL")
+	}
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid sink URL: %q", rawURL)
+	}
+
+	if _, err := s.state.ProjectGet(sink.Project); err != nil {
+		return nil, err
+	}
+
+	if sink.Id == "" {
+		id, err := server.Id()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "uuid generation failed: %s", err)
+		}
+		sink.Id = id
+
+		sink.LastTime = nil
+		sink.LastError = ""
+	} else {
+		// Keep the secret unless it's changed and the results of the
+		// existing sink.
+		existing, err := s.state.NotificationSinkGet(sink.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if hook := sink.GetWebhook(); hook != nil && hook.Secret == "" {
+			hook.Secret = existing.GetWebhook().GetSecret()
+		}
+		sink.LastTime = existing.LastTime
+		sink.LastError = existing.LastError
+	}
+
+	if err := s.state.NotificationSinkPut(sink); err != nil {
+		return nil, err
+	}
+
+	return &pb.UpsertNotificationSinkResponse{Sink: sink}, nil
+}
+
+func (s *service) ListNotificationSinks(
+	ctx context.Context,
+	req *pb.ListNotificationSinksRequest,
+) (*pb.ListNotificationSinksResponse, error) {
+	result, err := s.state.NotificationSinkList(req.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	// Secrets are only returned when the sink is upserted.
+	for _, sink := range result {
+		if hook := sink.GetWebhook(); hook != nil {
+			hook.Secret = ""
+		}
+	}
+
+	return &pb.ListNotificationSinksResponse{Sinks: result}, nil
+}
+
+func (s *service) DeleteNotificationSink(
+	ctx context.Context,
+	req *pb.DeleteNotificationSinkRequest,
+) (*empty.Empty, error) {
+	if err := s.state.NotificationSinkDelete(req.Id); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// runNotifications sends notifications of the events of the server to the
+// sinks of their project until ctx is done.
+func (s *service) runNotifications(ctx context.Context, log hclog.Logger) {
+	eventCh, cancel := s.events.Subscribe(100)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case ev := <-eventCh:
+			if err := s.notify(ctx, log, ev); err != nil {
+				log.Warn("error sending notifications", "err", err)
+			}
+		}
+	}
+}
+
+// notify sends a notification of the event to the sinks that want it.
+func (s *service) notify(ctx context.Context, log hclog.Logger, ev *pb.Event) error {
+	// Only failed jobs are worth a notification.
+	if job := ev.GetJob(); job != nil && job.State != pb.Job_ERROR {
+		return nil
+	}
+	if ev.Application.GetProject() == "" {
+		return nil
+	}
+
+	sinks, err := s.state.NotificationSinkList(&pb.Ref_Project{
+		Project: ev.Application.Project,
+	})
+	if err != nil {
+		return err
+	}
+
+	n := &pb.Notification{Text: notificationText(ev), Event: ev}
+	for _, sink := range sinks {
+		if !notificationWanted(sink, ev) {
+			continue
+		}
+
+		go func(sink *pb.NotificationSink) {
+			err := s.notifySink(ctx, sink, n)
+			if err != nil {
+				log.Warn("error sending notification", "sink", sink.Id, "err", err)
+			}
+
+			s.notificationResult(log, sink.Id, err)
+		}(sink)
+	}
+
+	return nil
+}
+
+// notifySink delivers the notification to the sink, retrying if it fails.
+func (s *service) notifySink(ctx context.Context, sink *pb.NotificationSink, n *pb.Notification) error {
+	var (
+		rawURL string
+		body   []byte
+		err    error
+	)
+	headers := map[string]string{"Content-Type": "application/json"}
+	switch v := sink.Sink.(type) {
+	case *pb.NotificationSink_Webhook_:
+		rawURL = v.Webhook.Url
+		body, err = protojson.Marshal(n)
+		if err != nil {
+			return err
+		}
+
+		if secret := v.Webhook.Secret; secret != "" {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			headers["X-Waypoint-Signature"] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		}
+
+	case *pb.NotificationSink_Slack_:
+		rawURL = v.Slack.Url
+		body, err = json.Marshal(map[string]string{"text": n.Text})
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("sink has no URL")
+	}
+
+	wait := notifyRetryWait
+	for attempt := 1; ; attempt++ {
+		err = notifySend(ctx, rawURL, headers, body)
+		if err == nil || attempt >= notifyAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// notifySend sends a single notification request.
+func notifySend(ctx context.Context, rawURL string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequest("POST", rawURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := notifyClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// notificationResult stores the result of delivering a notification.
+func (s *service) notificationResult(log hclog.Logger, id string, err error) {
+	sink, gerr := s.state.NotificationSinkGet(id)
+	if gerr != nil {
+		// The sink was deleted in the meantime.
+		return
+	}
+
+	sink.LastTime = ptypes.TimestampNow()
+	sink.LastError = ""
+	if err != nil {
+		sink.LastError = err.Error()
+	}
+	if err := s.state.NotificationSinkPut(sink); err != nil {
+		log.Warn("error storing notification result", "sink", id, "err", err)
+	}
+}
+
+// notificationWanted returns true if the sink wants notifications of the
+// type of the event. Instance events are only sent to sinks that ask for
+// them since they are frequent.
+func notificationWanted(sink *pb.NotificationSink, ev *pb.Event) bool {
+	if len(sink.Events) == 0 {
+		switch ev.Type {
+		case pb.Event_JOB, pb.Event_DEPLOYMENT, pb.Event_RELEASE:
+			return true
+		default:
+			return false
+		}
+	}
+
+	for _, typ := range sink.Events {
+		if typ == ev.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notificationText returns a human friendly summary of the event, such as
+// "Deployment v3 of web/api (default) succeeded".
+func notificationText(ev *pb.Event) string {
+	target := fmt.Sprintf("%s/%s (%s)",
+		ev.Application.GetProject(),
+		ev.Application.GetApplication(),
+		ev.Workspace.GetWorkspace())
+
+	// result describes the outcome of an operation with its status.
+	result := func(st *pb.Status) string {
+		if st.GetState() == pb.Status_ERROR {
+			if msg := st.GetError().GetMessage(); msg != "" {
+				return "failed: " + msg
+			}
+
+			return "failed"
+		}
+
+		return "succeeded"
+	}
+
+	switch subject := ev.Subject.(type) {
+	case *pb.Event_Deployment:
+		d := subject.Deployment
+		return fmt.Sprintf("Deployment v%d of %s %s", d.Sequence, target, result(d.Status))
+
+	case *pb.Event_Release:
+		r := subject.Release
+		text := fmt.Sprintf("Release v%d of %s %s", r.Sequence, target, result(r.Status))
+		if r.Url != "" && r.Status.GetState() == pb.Status_SUCCESS {
+			text += ": " + r.Url
+		}
+
+		return text
+
+	case *pb.Event_Job:
+		job := subject.Job
+		var op string
+		m := job.ProtoReflect()
+		if fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("operation")); fd != nil {
+			op = string(fd.Name())
+		}
+
+		text := fmt.Sprintf("Job %s (%s) of %s is %s",
+			job.Id, op, target, strings.ToLower(job.State.String()))
+		if job.State == pb.Job_ERROR {
+			text = fmt.Sprintf("Job %s (%s) of %s failed", job.Id, op, target)
+			if msg := job.Error.GetMessage(); msg != "" {
+				text += ": " + msg
+			}
+		}
+
+		return text
+
+	case *pb.Event_Instance:
+		verb := "connected"
+		if ev.Type == pb.Event_INSTANCE_DISCONNECTED {
+			verb = "disconnected"
+		}
+
+		return fmt.Sprintf("Instance %s of %s %s", subject.Instance.Id, target, verb)
+
+	default:
+		return fmt.Sprintf("%s event for %s", strings.ToLower(ev.Type.String()), target)
+	}
+}
@@ -0,0 +1,113 @@
+package singleprocess
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceNotificationSink(t *testing.T) {
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	impl, err := New(WithDB(testDB(t)), WithContext(ctx))
+	require.NoError(err)
+	s := impl.(*service)
+
+	require.NoError(s.state.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+		Name: "p_test",
+	})))
+
+	// Receive the notifications along with their signature
+	type request struct {
+		Body      []byte
+		Signature string
+	}
+	reqCh := make(chan request, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		reqCh <- request{Body: body, Signature: r.Header.Get("X-Waypoint-Signature")}
+	}))
+	defer ts.Close()
+
+	// Invalid sinks
+	for _, sink := range []*pb.NotificationSink{
+		{Project: &pb.Ref_Project{Project: "p_test"}},
+		{
+			Project: &pb.Ref_Project{Project: "p_test"},
+			Sink: &pb.NotificationSink_Slack_{
+				Slack: &pb.NotificationSink_Slack{Url: "not a url"},
+			},
+		},
+	} {
+		_, err := s.UpsertNotificationSink(ctx, &pb.UpsertNotificationSinkRequest{Sink: sink})
+		require.Error(err)
+	}
+
+	_, err = s.UpsertNotificationSink(ctx, &pb.UpsertNotificationSinkRequest{
+		Sink: &pb.NotificationSink{
+			Project: &pb.Ref_Project{Project: "p_test"},
+			Events:  []pb.Event_Type{pb.Event_DEPLOYMENT},
+			Sink: &pb.NotificationSink_Webhook_{
+				Webhook: &pb.NotificationSink_Webhook{Url: ts.URL, Secret: "hunter2"},
+			},
+		},
+	})
+	require.NoError(err)
+
+	// Secrets aren't listed
+	list, err := s.ListNotificationSinks(ctx, &pb.ListNotificationSinksRequest{})
+	require.NoError(err)
+	require.Len(list.Sinks, 1)
+	require.Empty(list.Sinks[0].GetWebhook().Secret)
+
+	// A running deployment doesn't notify, but completing it does
+	resp, err := s.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{
+		Deployment: serverptypes.TestValidDeployment(t, &pb.Deployment{
+			Status: server.NewStatus(pb.Status_RUNNING),
+		}),
+	})
+	require.NoError(err)
+	deployment := resp.Deployment
+	server.StatusSetSuccess(deployment.Status)
+	_, err = s.UpsertDeployment(ctx, &pb.UpsertDeploymentRequest{Deployment: deployment})
+	require.NoError(err)
+
+	var req request
+	select {
+	case req = <-reqCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification not received")
+	}
+
+	mac := hmac.New(sha256.New, []byte("hunter2"))
+	mac.Write(req.Body)
+	require.Equal("sha256="+hex.EncodeToString(mac.Sum(nil)), req.Signature)
+
+	var n pb.Notification
+	require.NoError(protojson.Unmarshal(req.Body, &n))
+	require.Equal(pb.Event_DEPLOYMENT, n.Event.Type)
+	require.Equal(deployment.Id, n.Event.GetDeployment().Id)
+	require.Contains(n.Text, "p_test/a_test (default) succeeded")
+
+	// Only one notification is sent
+	select {
+	case <-reqCh:
+		t.Fatal("unexpected notification")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,92 @@
+package singleprocess
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func (s *service) UpsertOrganization(
+	ctx context.Context,
+	req *pb.UpsertOrganizationRequest,
+) (*pb.UpsertOrganizationResponse, error) {
+	o := req.Organization
+	if o == nil || o.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "organization must have a name")
+	}
+
+	existing, err := s.state.OrganizationGet(o.Name)
+	switch status.Code(err) {
+	case codes.OK:
+		o.CreateTime = existing.CreateTime
+
+	case codes.NotFound:
+		o.CreateTime = ptypes.TimestampNow()
+
+	default:
+		return nil, err
+	}
+
+	if err := s.state.OrganizationPut(o); err != nil {
+		return nil, err
+	}
+
+	return &pb.UpsertOrganizationResponse{Organization: o}, nil
+}
+
+func (s *service) ListOrganizations(
+	ctx context.Context,
+	req *empty.Empty,
+) (*pb.ListOrganizationsResponse, error) {
+	orgs, err := s.state.OrganizationList()
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers in an organization only see their own.
+	org := authOrganization(ctx)
+	var result []*pb.Organization
+	for _, o := range orgs {
+		if org != "" && !strings.EqualFold(o.Name, org) {
+			continue
+		}
+
+		result = append(result, o)
+	}
+
+	return &pb.ListOrganizationsResponse{Organizations: result}, nil
+}
+
+func (s *service) DeleteOrganization(
+	ctx context.Context,
+	req *pb.DeleteOrganizationRequest,
+) (*empty.Empty, error) {
+	if _, err := s.state.OrganizationGet(req.Name); err != nil {
+		return nil, err
+	}
+
+	// Projects would otherwise only be accessible to callers that aren't
+	// in an organization, so they must be deleted or moved first.
+	refs, err := s.state.ProjectList()
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range refs {
+		if s.organizationHasProject(req.Name, ref.Project) {
+			return nil, status.Errorf(codes.FailedPrecondition,
+				"organization %q still has projects, such as %q", req.Name, ref.Project)
+		}
+	}
+
+	if err := s.state.OrganizationDelete(req.Name); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
@@ -0,0 +1,165 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestServiceOrganization(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+
+	// Two organizations with an admin each and a project without one.
+	for _, name := range []string{"red", "blue"} {
+		_, err := s.UpsertOrganization(ctx, &pb.UpsertOrganizationRequest{
+			Organization: &pb.Organization{Name: name},
+		})
+		require.NoError(err)
+
+		require.NoError(s.state.UserPut(&pb.User{
+			Id:           name + "-admin",
+			Organization: name,
+			Permissions:  []*pb.Permission{{Verb: pb.Permission_ADMIN}},
+		}))
+	}
+	require.NoError(s.state.ProjectPut(&pb.Project{Name: "shared"}))
+
+	authCtx := func(user string) context.Context {
+		token, err := s.newUserLoginToken(DefaultKeyId, &pb.Token{User: user}, 0, nil)
+		require.NoError(err)
+		ctx, err := s.AuthContext(context.Background(), token)
+		require.NoError(err)
+		return ctx
+	}
+	redCtx := authCtx("red-admin")
+	blueCtx := authCtx("blue-admin")
+
+	// New projects are created in the organization of the caller
+	resp, err := s.UpsertProject(redCtx, &pb.UpsertProjectRequest{
+		Project: &pb.Project{Name: "web"},
+	})
+	require.NoError(err)
+	require.Equal("red", resp.Project.Organization)
+
+	// Upserting again keeps the organization
+	resp, err = s.UpsertProject(ctx, &pb.UpsertProjectRequest{
+		Project: &pb.Project{Name: "web", Organization: "blue"},
+	})
+	require.NoError(err)
+	require.Equal("red", resp.Project.Organization)
+
+	t.Run("access is limited to the organization", func(t *testing.T) {
+		require := require.New(t)
+
+		getWeb := &pb.GetProjectRequest{Project: &pb.Ref_Project{Project: "web"}}
+		require.NoError(s.Authorize(redCtx, "GetProject", getWeb))
+
+		err := s.Authorize(blueCtx, "GetProject", getWeb)
+		require.Equal(codes.PermissionDenied, status.Code(err))
+
+		// Projects without an organization are only for the whole server
+		err = s.Authorize(redCtx, "GetProject", &pb.GetProjectRequest{
+			Project: &pb.Ref_Project{Project: "shared"},
+		})
+		require.Equal(codes.PermissionDenied, status.Code(err))
+
+		// Server endpoints aren't available to organizations
+		err = s.Authorize(redCtx, "GetServerConfig", nil)
+		require.Equal(codes.PermissionDenied, status.Code(err))
+		err = s.Authorize(redCtx, "UpsertOrganization", &pb.UpsertOrganizationRequest{})
+		require.Equal(codes.PermissionDenied, status.Code(err))
+		require.NoError(s.Authorize(redCtx, "ListProjects", nil))
+	})
+
+	t.Run("lists are filtered to the organization", func(t *testing.T) {
+		require := require.New(t)
+
+		projects, err := s.ListProjects(redCtx, &empty.Empty{})
+		require.NoError(err)
+		require.Len(projects.Projects, 1)
+		require.Equal("web", projects.Projects[0].Project)
+
+		projects, err = s.ListProjects(blueCtx, &empty.Empty{})
+		require.NoError(err)
+		require.Empty(projects.Projects)
+
+		projects, err = s.ListProjects(ctx, &empty.Empty{})
+		require.NoError(err)
+		require.Len(projects.Projects, 2)
+
+		users, err := s.ListUsers(blueCtx, &empty.Empty{})
+		require.NoError(err)
+		require.Len(users.Users, 1)
+		require.Equal("blue-admin", users.Users[0].Id)
+
+		orgs, err := s.ListOrganizations(blueCtx, &empty.Empty{})
+		require.NoError(err)
+		require.Len(orgs.Organizations, 1)
+		require.Equal("blue", orgs.Organizations[0].Name)
+	})
+
+	t.Run("tokens stay in the organization", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := s.GenerateLoginToken(redCtx, &pb.LoginTokenRequest{})
+		require.NoError(err)
+		_, body, err := s.DecodeToken(resp.Token)
+		require.NoError(err)
+		require.Equal("red", body.Organization)
+
+		_, err = s.GenerateLoginToken(redCtx, &pb.LoginTokenRequest{Organization: "blue"})
+		require.Equal(codes.PermissionDenied, status.Code(err))
+		_, err = s.GenerateInviteToken(redCtx, &pb.InviteTokenRequest{Duration: "1h", Runner: true})
+		require.Equal(codes.PermissionDenied, status.Code(err))
+
+		// Tokens of the red organization can't be listed or revoked by blue
+		tokens, err := s.ListTokens(blueCtx, &pb.ListTokensRequest{})
+		require.NoError(err)
+		for _, token := range tokens.Tokens {
+			require.Equal("blue", token.Organization)
+		}
+		_, err = s.RevokeToken(blueCtx, &pb.RevokeTokenRequest{
+			Target: &pb.RevokeTokenRequest_Id{Id: tokenID(body)},
+		})
+		require.Equal(codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("users can be added to the organization", func(t *testing.T) {
+		require := require.New(t)
+
+		require.NoError(s.state.UserPut(&pb.User{Id: "new"}))
+		user, err := s.SetUserPermissions(redCtx, &pb.SetUserPermissionsRequest{
+			UserId:      "new",
+			Permissions: []*pb.Permission{{Verb: pb.Permission_READ}},
+		})
+		require.NoError(err)
+		require.Equal("red", user.Organization)
+
+		// Users of other organizations can't be taken over
+		_, err = s.SetUserPermissions(blueCtx, &pb.SetUserPermissionsRequest{UserId: "new"})
+		require.Equal(codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("organizations with projects can't be deleted", func(t *testing.T) {
+		require := require.New(t)
+
+		_, err := s.DeleteOrganization(ctx, &pb.DeleteOrganizationRequest{Name: "red"})
+		require.Equal(codes.FailedPrecondition, status.Code(err))
+
+		_, err = s.DeleteOrganization(ctx, &pb.DeleteOrganizationRequest{Name: "blue"})
+		require.NoError(err)
+		_, err = s.DeleteOrganization(ctx, &pb.DeleteOrganizationRequest{Name: "blue"})
+		require.Equal(codes.NotFound, status.Code(err))
+	})
+}
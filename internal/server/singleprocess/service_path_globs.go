@@ -0,0 +1,122 @@
+package singleprocess
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/hashicorp/go-hclog"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// pathGlobsApps returns the apps that operations triggered by a change of
+// the data source are queued for. files are the changed files relative to
+// the root of the repository, or nil if they aren't known. Apps without
+// path globs are always returned and if the files aren't known, every app
+// is returned.
+func pathGlobsApps(log hclog.Logger, p *pb.Project, apps []string, files []string) []string {
+	if files == nil {
+		return apps
+	}
+
+	// The globs are relative to the project directory in the repository.
+	var root string
+	if git, ok := p.DataSource.GetSource().(*pb.Job_DataSource_Git); ok {
+		root = strings.Trim(path.Clean(git.Git.Path), "/")
+		if root == "." {
+			root = ""
+		}
+	}
+
+	var result []string
+	for _, name := range apps {
+		globs := pathGlobs(p, name)
+		if len(globs) == 0 || pathGlobsMatch(globs, root, files) {
+			result = append(result, name)
+			continue
+		}
+
+		log.Debug("no changed files match the path globs, skipping app", "app", name)
+	}
+
+	return result
+}
+
+// pathGlobsUsed returns true if any of the apps have path globs. The
+// changed files only need to be found if this is true.
+func pathGlobsUsed(p *pb.Project, apps []string) bool {
+	for _, name := range apps {
+		if len(pathGlobs(p, name)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pathGlobs returns the path globs of the app of the project.
+func pathGlobs(p *pb.Project, name string) []string {
+	for _, app := range p.Applications {
+		if app.Name == name {
+			return app.PathGlobs
+		}
+	}
+
+	return nil
+}
+
+// pathGlobsMatch returns true if any of the files under root match any
+// of the globs.
+func pathGlobsMatch(globs []string, root string, files []string) bool {
+	for _, file := range files {
+		if root != "" {
+			if !strings.HasPrefix(file, root+"/") {
+				continue
+			}
+
+			file = strings.TrimPrefix(file, root+"/")
+		}
+
+		for _, glob := range globs {
+			if ok, _ := doublestar.Match(glob, file); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// pathGlobValidate returns an error if the pattern isn't a relative glob.
+// Each element must be "**" or a valid path.Match pattern.
+func pathGlobValidate(pattern string) error {
+	if pattern == "" || strings.HasPrefix(pattern, "/") {
+		return errors.New("must be a relative path")
+	}
+
+	for _, part := range strings.Split(pattern, "/") {
+		if part == "**" {
+			continue
+		}
+		if _, err := path.Match(part, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func pathGlobsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
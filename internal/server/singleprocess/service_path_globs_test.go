@@ -0,0 +1,119 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestPathGlobsApps(t *testing.T) {
+	apps := []string{"api", "frontend", "docs"}
+
+	cases := []struct {
+		Name     string
+		Root     string
+		Files    []string
+		Expected []string
+	}{
+		{
+			"unknown files",
+			"",
+			nil,
+			[]string{"api", "frontend", "docs"},
+		},
+
+		{
+			"no files",
+			"",
+			[]string{},
+			[]string{"docs"},
+		},
+
+		{
+			"one app",
+			"",
+			[]string{"services/api/main.go", "README.md"},
+			[]string{"api", "docs"},
+		},
+
+		{
+			"shared files",
+			"",
+			[]string{"lib/log/log.go", "services/frontend/index.js"},
+			[]string{"api", "frontend", "docs"},
+		},
+
+		{
+			"project directory",
+			"apps",
+			[]string{"apps/services/frontend/index.js", "services/api/main.go"},
+			[]string{"frontend", "docs"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			p := &pb.Project{
+				Name: "web",
+				Applications: []*pb.Application{
+					{Name: "api", PathGlobs: []string{"services/api/**", "lib/**"}},
+					{Name: "frontend", PathGlobs: []string{"services/frontend/**"}},
+					{Name: "docs"},
+				},
+				DataSource: &pb.Job_DataSource{
+					Source: &pb.Job_DataSource_Git{Git: &pb.Job_Git{Path: tt.Root}},
+				},
+			}
+
+			require.Equal(t, tt.Expected, pathGlobsApps(hclog.L(), p, apps, tt.Files))
+		})
+	}
+}
+
+func TestServiceUpsertApplication_pathGlobs(t *testing.T) {
+	ctx := context.Background()
+	require := require.New(t)
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(err)
+	s := impl.(*service)
+
+	require.NoError(s.state.ProjectPut(serverptypes.TestProject(t, &pb.Project{Name: "web"})))
+	ref := &pb.Ref_Project{Project: "web"}
+
+	resp, err := s.UpsertApplication(ctx, &pb.UpsertApplicationRequest{
+		Project:   ref,
+		Name:      "api",
+		PathGlobs: []string{"services/api/**"},
+	})
+	require.NoError(err)
+	require.Equal([]string{"services/api/**"}, resp.Application.PathGlobs)
+
+	// The globs of an existing app are updated
+	_, err = s.UpsertApplication(ctx, &pb.UpsertApplicationRequest{
+		Project:   ref,
+		Name:      "api",
+		PathGlobs: []string{"api/**"},
+	})
+	require.NoError(err)
+
+	app, err := s.state.AppGet(&pb.Ref_Application{Project: "web", Application: "api"})
+	require.NoError(err)
+	require.Equal([]string{"api/**"}, app.PathGlobs)
+
+	// Invalid globs are rejected
+	_, err = s.UpsertApplication(ctx, &pb.UpsertApplicationRequest{
+		Project:   ref,
+		Name:      "api",
+		PathGlobs: []string{"api/["},
+	})
+	require.Error(err)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
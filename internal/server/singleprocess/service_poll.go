@@ -0,0 +1,466 @@
+package singleprocess
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var (
+	// pollCheckInterval is how often the projects are checked for a data
+	// source that is due to be polled.
+	pollCheckInterval = 10 * time.Second
+
+	// pollDefaultInterval is how often a data source is polled if the
+	// project doesn't set an interval, and pollMinInterval the shortest
+	// interval that can be set.
+	pollDefaultInterval = 30 * time.Second
+	pollMinInterval     = 10 * time.Second
+
+	// pollRemoteCommit returns the commit of the ref in the Git
+	// repository. This is a variable so that tests don't need a repository.
+	pollRemoteCommit = gitRemoteCommit
+
+	// pollChangedFiles returns the files that changed between two commits
+	// of the Git repository. This is a variable for the same reason.
+	pollChangedFiles = gitChangedFiles
+
+	// pollCloneLocks are the locks of the clones of gitChangedFiles.
+	pollCloneLocks sync.Map
+
+	// pollCommitRe matches the commit IDs that are compared.
+	pollCommitRe = regexp.MustCompile(`^[0-9a-f]{7,64}$`)
+)
+
+// pollLabel is the label set on jobs queued because the data source of a
+// project changed. The value is the commit.
+const pollLabel = "waypoint/poll"
+
+// projectPoll validates the polling settings of a project that is
+// upserted and sets their defaults. The results of previous polls are
+// kept from the stored project.
+func (s *service) projectPoll(p *pb.Project) error {
+	poll := p.DataSourcePoll
+	if poll == nil {
+		return nil
+	}
+
+	if poll.Enabled {
+		if !p.RemoteEnabled {
+			return status.Errorf(codes.InvalidArgument,
+				"polling the data source requires remote operations to be enabled")
+		}
+		git, ok := p.DataSource.GetSource().(*pb.Job_DataSource_Git)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument,
+				"polling the data source requires a Git data source")
+		}
+		if err := pollValidateURL(git.Git.Url); err != nil {
+			return status.Errorf(codes.InvalidArgument, "can't poll the data source: %s", err)
+		}
+	}
+
+	if poll.Interval == "" {
+		poll.Interval = pollDefaultInterval.String()
+	}
+	d, err := time.ParseDuration(poll.Interval)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid poll interval: %s", err)
+	}
+	if d < pollMinInterval {
+		return status.Errorf(codes.InvalidArgument,
+			"poll interval must be at least %s", pollMinInterval)
+	}
+
+	if poll.Workspace == nil {
+		poll.Workspace = &pb.Ref_Workspace{Workspace: "default"}
+	}
+	if poll.Operation == pb.Trigger_UNKNOWN {
+		poll.Operation = pb.Trigger_UP
+	}
+
+	poll.LastTime = nil
+	poll.LastCommit = ""
+	poll.LastJobIds = nil
+	poll.LastError = ""
+	if old, err := s.state.ProjectGet(&pb.Ref_Project{Project: p.Name}); err == nil {
+		if last := old.DataSourcePoll; last != nil {
+			poll.LastTime = last.LastTime
+			poll.LastCommit = last.LastCommit
+			poll.LastJobIds = last.LastJobIds
+			poll.LastError = last.LastError
+		}
+	}
+
+	return nil
+}
+
+// runPoll polls the data sources of projects when they're due until ctx
+// is done. Only the leader polls if the state is shared.
+func (s *service) runPoll(ctx context.Context, log hclog.Logger) {
+	tick := time.NewTicker(pollCheckInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+
+		if !s.state.IsLeader() {
+			continue
+		}
+
+		if err := s.checkPolls(ctx, log); err != nil {
+			log.Warn("error checking projects to poll", "err", err)
+		}
+	}
+}
+
+// checkPolls starts polling the data sources that are due.
+func (s *service) checkPolls(ctx context.Context, log hclog.Logger) error {
+	refs, err := s.state.ProjectList()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, ref := range refs {
+		p, err := s.state.ProjectGet(ref)
+		if err != nil {
+			return err
+		}
+
+		poll := p.DataSourcePoll
+		if poll == nil || !poll.Enabled || !p.RemoteEnabled {
+			continue
+		}
+
+		interval, err := time.ParseDuration(poll.Interval)
+		if err != nil {
+			continue
+		}
+		if last, err := ptypes.Timestamp(poll.LastTime); err == nil && now.Sub(last) < interval {
+			continue
+		}
+
+		// Store the time before we start so that a slow poll doesn't
+		// start again.
+		poll.LastTime, _ = ptypes.TimestampProto(now)
+		if err := s.state.ProjectPut(p); err != nil {
+			return err
+		}
+
+		go s.pollProject(ctx, log, p)
+	}
+
+	return nil
+}
+
+// pollProject checks the Git data source of the project and queues jobs
+// for its applications if the commit changed since the last poll.
+func (s *service) pollProject(ctx context.Context, log hclog.Logger, p *pb.Project) {
+	log = log.With("project", p.Name)
+	poll := p.DataSourcePoll
+
+	git, ok := p.DataSource.GetSource().(*pb.Job_DataSource_Git)
+	if !ok {
+		return
+	}
+
+	commit, err := pollRemoteCommit(ctx, git.Git.Url, git.Git.Ref)
+	if err != nil {
+		log.Warn("error polling data source", "err", err)
+		s.pollResult(log, p, func(current *pb.Project_Poll) {
+			current.LastError = err.Error()
+		})
+		return
+	}
+	if commit == poll.LastCommit {
+		s.pollResult(log, p, func(current *pb.Project_Poll) {
+			current.LastError = ""
+		})
+		return
+	}
+
+	apps := poll.Applications
+	if len(apps) == 0 {
+		for _, app := range p.Applications {
+			apps = append(apps, app.Name)
+		}
+	}
+
+	// Only apps with changed files are operated on. The changed files are
+	// only found if an app has path globs since that requires a clone.
+	if poll.LastCommit != "" && pathGlobsUsed(p, apps) {
+		files, err := pollChangedFiles(ctx, git.Git.Url, poll.LastCommit, commit)
+		if err != nil {
+			log.Warn("error finding the changed files, queueing jobs for every app", "err", err)
+		} else {
+			apps = pathGlobsApps(log, p, apps, files)
+		}
+	}
+
+	log.Info("data source changed, queueing jobs", "commit", commit, "apps", len(apps))
+
+	// Queue the jobs of each application and store the ID of the first
+	// job of each once they're all queued. The rest of the jobs continue
+	// in the background like those of webhooks.
+	var (
+		wg     sync.WaitGroup
+		lock   sync.Mutex
+		jobIds []string
+		errs   []string
+	)
+	queuedCh := make(chan struct{}, len(apps))
+	for _, app := range apps {
+		wg.Add(1)
+		go func(app string) {
+			defer wg.Done()
+
+			queued := false
+			err := s.runOperation(ctx, poll.Operation,
+				&pb.Ref_Application{Project: p.Name, Application: app},
+				poll.Workspace,
+				map[string]string{pollLabel: commit},
+				map[string]string{"ref": commit},
+				func(jobId string) {
+					lock.Lock()
+					defer lock.Unlock()
+					jobIds = append(jobIds, jobId)
+
+					queued = true
+					queuedCh <- struct{}{}
+				},
+			)
+			if err != nil {
+				log.Warn("poll job failed", "app", app, "err", err)
+
+				lock.Lock()
+				errs = append(errs, fmt.Sprintf("%s: %s", app, err))
+				lock.Unlock()
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			if !queued {
+				queuedCh <- struct{}{}
+			}
+		}(app)
+	}
+	for range apps {
+		<-queuedCh
+	}
+
+	lock.Lock()
+	queuedIds := append([]string(nil), jobIds...)
+	queuedErrs := strings.Join(errs, "; ")
+	lock.Unlock()
+	s.pollResult(log, p, func(current *pb.Project_Poll) {
+		current.LastCommit = commit
+		current.LastJobIds = queuedIds
+		current.LastError = queuedErrs
+	})
+
+	// Store the errors of the jobs once they're all done.
+	wg.Wait()
+	if len(errs) > 0 {
+		s.pollResult(log, p, func(current *pb.Project_Poll) {
+			current.LastError = strings.Join(errs, "; ")
+		})
+	}
+}
+
+// pollResult updates the poll result of the project for the poll that
+// started at p.DataSourcePoll.LastTime. The project may have been changed
+// while the poll ran, so this updates the latest record.
+func (s *service) pollResult(log hclog.Logger, p *pb.Project, f func(*pb.Project_Poll)) {
+	current, err := s.state.ProjectGet(&pb.Ref_Project{Project: p.Name})
+	if err != nil || current.DataSourcePoll == nil {
+		return
+	}
+	if !proto.Equal(current.DataSourcePoll.LastTime, p.DataSourcePoll.LastTime) {
+		// The project was polled again in the meantime.
+		return
+	}
+
+	f(current.DataSourcePoll)
+	if err := s.state.ProjectPut(current); err != nil {
+		log.Warn("error storing poll result", "err", err)
+	}
+}
+
+// pollValidateURL checks that the server may poll the Git repository at
+// url. The URL is set by anyone who can write projects but git runs on the
+// server, so only remote repositories over https, ssh and git are polled.
+// Local paths, file:// URLs and transport helpers such as "ext::" would
+// read the server's files or run commands on it, and URLs that start with
+// "-" would be parsed by git as options.
+func pollValidateURL(url string) error {
+	if url == "" {
+		return fmt.Errorf("the Git URL must be set")
+	}
+	if strings.HasPrefix(url, "-") || strings.Contains(url, "::") {
+		return fmt.Errorf("invalid Git URL %q", url)
+	}
+
+	if idx := strings.Index(url, "://"); idx != -1 {
+		switch strings.ToLower(url[:idx]) {
+		case "https", "ssh", "git":
+			return nil
+
+		default:
+			return fmt.Errorf("can't poll Git URLs with the scheme %q, "+
+				"use https, ssh or git", url[:idx])
+		}
+	}
+
+	// The scp-like syntax of ssh, such as "git@github.com:org/repo.git".
+	// Anything else without a scheme is a local path.
+	if idx := strings.Index(url, ":"); idx > 0 && !strings.ContainsAny(url[:idx], `/\`) {
+		return nil
+	}
+
+	return fmt.Errorf("only remote Git repositories can be polled, %q is a local path", url)
+}
+
+// pollGitCommand returns the command to run git with the args. git may
+// only use the https, ssh and git protocols, also for submodules and
+// redirects, and never prompts for credentials.
+func pollGitCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(),
+		"GIT_ALLOW_PROTOCOL=https:ssh:git",
+		"GIT_TERMINAL_PROMPT=0",
+	)
+
+	return cmd
+}
+
+// gitRemoteCommit returns the commit that the ref points to in the
+// repository at url, using "git ls-remote" like the runners use git to
+// clone the data source. The ref can be a branch, a tag or a full ref. If
+// the ref is empty, the default branch is used.
+func gitRemoteCommit(ctx context.Context, url, ref string) (string, error) {
+	if err := pollValidateURL(url); err != nil {
+		return "", err
+	}
+
+	pattern := ref
+	if pattern == "" {
+		pattern = "HEAD"
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := pollGitCommand(ctx, "ls-remote", "--", url, pattern)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git ls-remote failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	// Prefer an exact match, then a branch and then a tag. Annotated tags
+	// are listed with a "^{}" suffix for the commit they point to.
+	candidates := []string{pattern, "refs/heads/" + pattern, "refs/tags/" + pattern + "^{}", "refs/tags/" + pattern}
+	found := map[string]string{}
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		parts := strings.Fields(line)
+		if len(parts) == 2 {
+			found[parts[1]] = parts[0]
+		}
+	}
+	for _, c := range candidates {
+		if commit, ok := found[c]; ok {
+			return commit, nil
+		}
+	}
+
+	return "", fmt.Errorf("ref %q not found in %s", pattern, url)
+}
+
+// gitChangedFiles returns the files that changed between the commits from
+// and to of the repository at url. This compares the commits in a bare
+// clone without the contents of the files. The clone is kept in the temp
+// directory and fetched by later polls, so only new commits are fetched.
+func gitChangedFiles(ctx context.Context, url, from, to string) ([]string, error) {
+	if err := pollValidateURL(url); err != nil {
+		return nil, err
+	}
+	for _, commit := range []string{from, to} {
+		if !pollCommitRe.MatchString(commit) {
+			return nil, fmt.Errorf("invalid commit %q", commit)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(url))
+	dir := filepath.Join(os.TempDir(), "waypoint-poll", hex.EncodeToString(sum[:8]))
+
+	// Projects with the same repository share the clone, so they take
+	// turns using it.
+	lockRaw, _ := pollCloneLocks.LoadOrStore(dir, &sync.Mutex{})
+	lock := lockRaw.(*sync.Mutex)
+	lock.Lock()
+	defer lock.Unlock()
+
+	git := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := pollGitCommand(ctx, args...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+
+		return stdout.String(), nil
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil {
+		if err := os.MkdirAll(filepath.Dir(dir), 0700); err != nil {
+			return nil, err
+		}
+		if _, err := git("clone", "--bare", "--quiet", "--filter=blob:none", "--", url, dir); err != nil {
+			os.RemoveAll(dir)
+			return nil, err
+		}
+	} else {
+		_, err := git("--git-dir", dir, "fetch", "--quiet", "--prune", "origin",
+			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*")
+		if err != nil {
+			// Start over with a new clone on the next poll.
+			os.RemoveAll(dir)
+			return nil, err
+		}
+	}
+
+	out, err := git("--git-dir", dir, "diff", "--name-only", from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+
+	return result, nil
+}
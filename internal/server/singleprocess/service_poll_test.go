@@ -0,0 +1,154 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServicePoll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Stub the commit of the repository
+	commit := "a"
+	oldRemoteCommit := pollRemoteCommit
+	defer func() { pollRemoteCommit = oldRemoteCommit }()
+	pollRemoteCommit = func(context.Context, string, string) (string, error) {
+		return commit, nil
+	}
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(t, err)
+	s := impl.(*service)
+
+	gitSource := &pb.Job_DataSource{
+		Source: &pb.Job_DataSource_Git{Git: &pb.Job_Git{Url: "https://example.com/web.git"}},
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		require := require.New(t)
+
+		for _, p := range []*pb.Project{
+			{Name: "web", DataSource: gitSource, DataSourcePoll: &pb.Project_Poll{Enabled: true}},
+			{Name: "web", RemoteEnabled: true, DataSourcePoll: &pb.Project_Poll{Enabled: true}},
+			{
+				Name:           "web",
+				RemoteEnabled:  true,
+				DataSource:     gitSource,
+				DataSourcePoll: &pb.Project_Poll{Enabled: true, Interval: "1s"},
+			},
+		} {
+			_, err := s.UpsertProject(ctx, &pb.UpsertProjectRequest{Project: p})
+			require.Error(err)
+			require.Equal(codes.InvalidArgument, status.Code(err))
+		}
+	})
+
+	t.Run("changes queue jobs", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := s.UpsertProject(ctx, &pb.UpsertProjectRequest{Project: &pb.Project{
+			Name:           "web",
+			RemoteEnabled:  true,
+			DataSource:     gitSource,
+			DataSourcePoll: &pb.Project_Poll{Enabled: true, Operation: pb.Trigger_BUILD},
+		}})
+		require.NoError(err)
+		require.Equal("30s", resp.Project.DataSourcePoll.Interval)
+		require.Equal("default", resp.Project.DataSourcePoll.Workspace.Workspace)
+
+		_, err = s.state.AppPut(serverptypes.TestApplication(t, &pb.Application{
+			Project: &pb.Ref_Project{Project: "web"},
+			Name:    "api",
+		}))
+		require.NoError(err)
+
+		// lastPoll returns the poll result of the project
+		lastPoll := func() *pb.Project_Poll {
+			p, err := s.state.ProjectGet(&pb.Ref_Project{Project: "web"})
+			require.NoError(err)
+			return p.DataSourcePoll
+		}
+
+		require.NoError(s.checkPolls(ctx, hclog.L()))
+		require.Eventually(func() bool {
+			return len(lastPoll().LastJobIds) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		poll := lastPoll()
+		require.Equal("a", poll.LastCommit)
+		job, err := s.state.JobById(poll.LastJobIds[0], nil)
+		require.NoError(err)
+		require.Equal("a", job.Labels[pollLabel])
+		require.Equal("a", job.DataSourceOverrides["ref"])
+		require.NotNil(job.Operation.(*pb.Job_Build))
+
+		// The project isn't polled again until the interval passes
+		commit = "b"
+		require.NoError(s.checkPolls(ctx, hclog.L()))
+		require.Equal("a", lastPoll().LastCommit)
+
+		// Upserting the project keeps the poll result
+		_, err = s.UpsertProject(ctx, &pb.UpsertProjectRequest{Project: &pb.Project{
+			Name:           "web",
+			RemoteEnabled:  true,
+			DataSource:     gitSource,
+			DataSourcePoll: &pb.Project_Poll{Enabled: true, Operation: pb.Trigger_BUILD},
+		}})
+		require.NoError(err)
+		require.Equal("a", lastPoll().LastCommit)
+	})
+}
+
+func TestPollValidateURL(t *testing.T) {
+	cases := []struct {
+		URL string
+		Err bool
+	}{
+		{"https://github.com/hashicorp/waypoint.git", false},
+		{"ssh://git@github.com/hashicorp/waypoint.git", false},
+		{"git://example.com/web.git", false},
+		{"git@github.com:hashicorp/waypoint.git", false},
+		{"", true},
+		{"--upload-pack=touch /tmp/pwned", true},
+		{"file:///var/lib/waypoint", true},
+		{"ext::sh -c touch% /tmp/pwned", true},
+		{"/var/lib/waypoint", true},
+		{"./web", true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.URL, func(t *testing.T) {
+			err := pollValidateURL(tt.URL)
+			if tt.Err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGitChangedFiles_invalid(t *testing.T) {
+	ctx := context.Background()
+	commit := "0123456789abcdef0123456789abcdef01234567"
+
+	for _, args := range [][3]string{
+		{"--upload-pack=touch /tmp/pwned", commit, commit},
+		{"https://example.com/web.git", "--output=/tmp/pwned", commit},
+		{"https://example.com/web.git", commit, "HEAD"},
+	} {
+		_, err := gitChangedFiles(ctx, args[0], args[1], args[2])
+		require.Error(t, err, args)
+	}
+}
@@ -2,8 +2,12 @@ package singleprocess
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
@@ -15,6 +19,21 @@ func (s *service) UpsertProject(
 	req *pb.UpsertProjectRequest,
 ) (*pb.UpsertProjectResponse, error) {
 	result := req.Project
+	if r := result.Retention; r != nil && r.MaxAge != "" {
+		if _, err := time.ParseDuration(r.MaxAge); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"invalid retention max_age: %s", err)
+		}
+	}
+
+	if err := s.projectPoll(result); err != nil {
+		return nil, err
+	}
+
+	if err := s.projectOrganization(ctx, result); err != nil {
+		return nil, err
+	}
+
 	if err := s.state.ProjectPut(result); err != nil {
 		return nil, err
 	}
@@ -45,30 +64,104 @@ func (s *service) ListProjects(
 		return nil, err
 	}
 
+	// Callers in an organization only see the projects of the organization.
+	if org := authOrganization(ctx); org != "" {
+		filtered := result[:0]
+		for _, ref := range result {
+			if s.organizationHasProject(org, ref.Project) {
+				filtered = append(filtered, ref)
+			}
+		}
+
+		result = filtered
+	}
+
 	return &pb.ListProjectsResponse{Projects: result}, nil
 }
 
+// projectOrganization sets the organization of the project to upsert.
+// New projects are created in the organization of the caller. Callers
+// that aren't in an organization can choose the organization of a new
+// project or of a project that isn't in an organization yet. Otherwise
+// the organization of a project can't be changed.
+func (s *service) projectOrganization(ctx context.Context, p *pb.Project) error {
+	org := authOrganization(ctx)
+
+	existing, err := s.state.ProjectGet(&pb.Ref_Project{Project: p.Name})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return err
+	}
+	if err == nil && (existing.Organization != "" || p.Organization == "") {
+		p.Organization = existing.Organization
+		return nil
+	}
+
+	if org != "" {
+		if p.Organization != "" && !strings.EqualFold(p.Organization, org) {
+			return status.Errorf(codes.PermissionDenied,
+				"can't create a project in another organization")
+		}
+
+		p.Organization = org
+		return nil
+	}
+
+	if p.Organization != "" {
+		o, err := s.state.OrganizationGet(p.Organization)
+		if status.Code(err) == codes.NotFound {
+			return status.Errorf(codes.InvalidArgument,
+				"organization not found: %s", p.Organization)
+		}
+		if err != nil {
+			return err
+		}
+
+		p.Organization = o.Name
+	}
+
+	return nil
+}
+
 // TODO: test
 func (s *service) UpsertApplication(
 	ctx context.Context,
 	req *pb.UpsertApplicationRequest,
 ) (*pb.UpsertApplicationResponse, error) {
+	for _, pattern := range req.PathGlobs {
+		if err := pathGlobValidate(pattern); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"invalid path glob %q: %s", pattern, err)
+		}
+	}
+
 	// Get the project
 	praw, err := s.state.ProjectGet(req.Project)
 	if err != nil {
 		return nil, err
 	}
 
-	// If the project has the application already then we're done.
+	// If the project has the application already then we're done unless
+	// its path globs changed.
 	p := serverptypes.Project{Project: praw}
 	if idx := p.App(req.Name); idx >= 0 {
-		return &pb.UpsertApplicationResponse{Application: p.Applications[idx]}, nil
+		app := p.Applications[idx]
+		if pathGlobsEqual(app.PathGlobs, req.PathGlobs) {
+			return &pb.UpsertApplicationResponse{Application: app}, nil
+		}
+
+		app.PathGlobs = req.PathGlobs
+		if _, err := s.state.AppPut(app); err != nil {
+			return nil, err
+		}
+
+		return &pb.UpsertApplicationResponse{Application: app}, nil
 	}
 
 	// Initialize a new app.
 	app, err := s.state.AppPut(&pb.Application{
-		Project: req.Project,
-		Name:    req.Name,
+		Project:   req.Project,
+		Name:      req.Name,
+		PathGlobs: req.PathGlobs,
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,90 @@
+package singleprocess
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// pruneInterval is how often the records of old operations are pruned in
+// the background.
+var pruneInterval = 1 * time.Hour
+
+func (s *service) PruneOperations(
+	ctx context.Context,
+	req *pb.PruneOperationsRequest,
+) (*pb.PruneOperationsResponse, error) {
+	var refs []*pb.Ref_Project
+	if req.Project != nil {
+		refs = []*pb.Ref_Project{req.Project}
+	} else {
+		var err error
+		refs, err = s.state.ProjectList()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result pb.PruneOperationsResponse
+	for _, ref := range refs {
+		p, err := s.state.ProjectGet(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := s.state.PruneOperations(p)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Builds += n.Builds
+		result.PushedArtifacts += n.PushedArtifacts
+		result.Deployments += n.Deployments
+		result.Releases += n.Releases
+		result.Jobs += n.Jobs
+	}
+
+	return &result, nil
+}
+
+// runPrune prunes the records of all projects, exec recordings that are
+// older than their retention and old log lines every pruneInterval until
+// ctx is done. Only the leader prunes records if the state is shared.
+func (s *service) runPrune(ctx context.Context, log hclog.Logger) {
+	tick := time.NewTicker(pruneInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+
+		s.pruneLogs(log)
+		if !s.state.IsLeader() {
+			continue
+		}
+
+		result, err := s.PruneOperations(ctx, &pb.PruneOperationsRequest{})
+		if err != nil {
+			log.Warn("error pruning operations", "err", err)
+			continue
+		}
+
+		log.Debug("pruned operations",
+			"builds", result.Builds,
+			"pushed_artifacts", result.PushedArtifacts,
+			"deployments", result.Deployments,
+			"releases", result.Releases,
+			"jobs", result.Jobs,
+		)
+
+		s.pruneExecRecordings(log)
+		s.pruneIdempotencyKeys(log)
+		s.pruneWebhookDeliveries(log)
+	}
+}
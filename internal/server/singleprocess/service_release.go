@@ -30,9 +30,22 @@ func (s *service) UpsertRelease(
 		result.Id = id
 	}
 
+	// Get the current release so that we know if it just completed.
+	var prev *pb.Release
+	if !insert {
+		var err error
+		prev, err = s.state.ReleaseGet(&pb.Ref_Operation{
+			Target: &pb.Ref_Operation_Id{Id: result.Id},
+		})
+		if err != nil && status.Code(err) != codes.NotFound {
+			return nil, err
+		}
+	}
+
 	if err := s.state.ReleasePut(!insert, result); err != nil {
 		return nil, err
 	}
+	s.publishRelease(prev, result)
 
 	return &pb.UpsertReleaseResponse{Release: result}, nil
 }
@@ -42,11 +55,14 @@ func (s *service) ListReleases(
 	ctx context.Context,
 	req *pb.ListReleasesRequest,
 ) (*pb.ListReleasesResponse, error) {
+	page := &state.ListPage{Size: req.PageSize, Token: req.PageToken}
 	result, err := s.state.ReleaseList(req.Application,
 		state.ListWithStatusFilter(req.Status...),
 		state.ListWithOrder(req.Order),
 		state.ListWithWorkspace(req.Workspace),
 		state.ListWithPhysicalState(req.PhysicalState),
+		state.ListWithStartTime(req.StartTime),
+		state.ListWithPage(page),
 	)
 	if err != nil {
 		return nil, err
@@ -58,7 +74,7 @@ func (s *service) ListReleases(
 		}
 	}
 
-	return &pb.ListReleasesResponse{Releases: result}, nil
+	return &pb.ListReleasesResponse{Releases: result, NextPageToken: page.Next}, nil
 }
 
 // TODO: test
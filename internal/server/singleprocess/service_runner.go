@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"google.golang.org/grpc/codes"
@@ -22,6 +23,45 @@ func (s *service) GetRunner(
 	return s.state.RunnerById(req.RunnerId)
 }
 
+func (s *service) ListRunners(
+	ctx context.Context,
+	req *empty.Empty,
+) (*pb.ListRunnersResponse, error) {
+	result, err := s.state.RunnerList()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListRunnersResponse{Runners: result}, nil
+}
+
+func (s *service) AdoptRunner(
+	ctx context.Context,
+	req *pb.AdoptRunnerRequest,
+) (*empty.Empty, error) {
+	state := pb.Runner_REJECTED
+	if req.Adopt {
+		state = pb.Runner_ADOPTED
+	}
+
+	if err := s.state.RunnerAdopt(req.RunnerId, state); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *service) ForgetRunner(
+	ctx context.Context,
+	req *pb.ForgetRunnerRequest,
+) (*empty.Empty, error) {
+	if err := s.state.RunnerForget(req.RunnerId); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
 func (s *service) RunnerGetDeploymentConfig(
 	ctx context.Context,
 	req *pb.RunnerGetDeploymentConfigRequest,
@@ -171,11 +211,29 @@ func (s *service) RunnerJobStream(
 	if err != nil {
 		return err
 	}
+	if !runner.Online {
+		return status.Errorf(codes.FailedPrecondition,
+			"runner must be connected with RunnerConfig before requesting jobs")
+	}
 
-	// Get a job assignment for this runner
-	job, err := s.state.JobAssignForRunner(ctx, runner)
+	// Get a job assignment for this runner. No more jobs are assigned once
+	// the server starts to shut down.
+	assignCtx, assignCancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-s.drainCh:
+			assignCancel()
+		case <-assignCtx.Done():
+		}
+	}()
+	job, err := s.state.JobAssignForRunner(assignCtx, runner)
+	assignCancel()
 	if err != nil {
-		return err
+		if s.draining() && ctx.Err() == nil {
+			return status.Errorf(codes.Unavailable, "server is shutting down")
+		}
+
+		return s.notLeaderError(err)
 	}
 
 	// Send the job assignment.
@@ -410,6 +468,9 @@ func (s *service) handleJobStreamRequest(
 
 		// Write the events
 		job.OutputBuffer.Write(entries...)
+		if job.Output != nil {
+			job.Output.Write(event.Terminal.Events...)
+		}
 
 		return nil
 
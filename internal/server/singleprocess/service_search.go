@@ -0,0 +1,59 @@
+package singleprocess
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// searchDefaultLimit is the number of results of each kind that
+// SearchOperations returns if the request has no limit.
+const searchDefaultLimit = 100
+
+func (s *service) SearchOperations(
+	ctx context.Context,
+	req *pb.SearchOperationsRequest,
+) (*pb.SearchOperationsResponse, error) {
+	if req.ArtifactDigest == "" && len(req.Labels) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"labels or an artifact digest are required to search")
+	}
+	if req.Limit == 0 {
+		req.Limit = searchDefaultLimit
+	}
+
+	kinds := req.Kinds
+	if len(kinds) == 0 {
+		kinds = []pb.SearchOperationsRequest_Kind{
+			pb.SearchOperationsRequest_BUILD,
+			pb.SearchOperationsRequest_PUSHED_ARTIFACT,
+			pb.SearchOperationsRequest_DEPLOYMENT,
+		}
+	}
+
+	var result pb.SearchOperationsResponse
+	for _, kind := range kinds {
+		var err error
+		switch kind {
+		case pb.SearchOperationsRequest_BUILD:
+			result.Builds, err = s.state.BuildSearch(req)
+
+		case pb.SearchOperationsRequest_PUSHED_ARTIFACT:
+			result.Artifacts, err = s.state.ArtifactSearch(req)
+
+		case pb.SearchOperationsRequest_DEPLOYMENT:
+			result.Deployments, err = s.state.DeploymentSearch(req)
+
+		default:
+			err = status.Errorf(codes.InvalidArgument, "unknown kind: %s", kind)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,101 @@
+package singleprocess
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// snapshotChunkSize is the maximum size of the chunks that snapshots are
+// streamed in.
+const snapshotChunkSize = 64 * 1024
+
+func (s *service) CreateSnapshot(
+	req *empty.Empty,
+	srv pb.Waypoint_CreateSnapshotServer,
+) error {
+	log := hclog.FromContext(srv.Context())
+
+	// Snapshots include secrets such as config values so we always
+	// record who created them.
+	s.Audit(srv.Context(), "CreateSnapshot", req, nil)
+
+	// Buffer the writes so that we send full chunks.
+	bw := bufio.NewWriterSize(&snapshotWriter{srv: srv}, snapshotChunkSize)
+	if err := s.state.CreateSnapshot(bw); err != nil {
+		log.Warn("error creating snapshot", "err", err)
+		return err
+	}
+
+	return bw.Flush()
+}
+
+func (s *service) RestoreSnapshot(
+	srv pb.Waypoint_RestoreSnapshotServer,
+) error {
+	log := hclog.FromContext(srv.Context())
+
+	err := s.state.RestoreSnapshot(&snapshotReader{srv: srv})
+	s.Audit(srv.Context(), "RestoreSnapshot", nil, err)
+	if err != nil {
+		log.Warn("error restoring snapshot", "err", err)
+		return err
+	}
+
+	log.Info("snapshot restored")
+	return srv.SendAndClose(&empty.Empty{})
+}
+
+// snapshotWriter is an io.Writer that sends the data as snapshot chunks.
+type snapshotWriter struct {
+	srv pb.Waypoint_CreateSnapshotServer
+}
+
+func (w *snapshotWriter) Write(p []byte) (int, error) {
+	var n int
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > snapshotChunkSize {
+			chunk = chunk[:snapshotChunkSize]
+		}
+
+		if err := w.srv.Send(&pb.CreateSnapshotResponse{Chunk: chunk}); err != nil {
+			return n, err
+		}
+
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+
+	return n, nil
+}
+
+// snapshotReader is an io.Reader that reads the data of received snapshot
+// chunks. It returns io.EOF when the client closes the stream.
+type snapshotReader struct {
+	srv pb.Waypoint_RestoreSnapshotServer
+	buf []byte
+}
+
+func (r *snapshotReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.srv.Recv()
+		if err != nil {
+			// Recv returns io.EOF when the client is done sending.
+			return 0, err
+		}
+
+		r.buf = req.Chunk
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+var _ io.Writer = (*snapshotWriter)(nil)
+var _ io.Reader = (*snapshotReader)(nil)
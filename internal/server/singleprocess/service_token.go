@@ -0,0 +1,90 @@
+package singleprocess
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func (s *service) ListTokens(
+	ctx context.Context,
+	req *pb.ListTokensRequest,
+) (*pb.ListTokensResponse, error) {
+	tokens, err := s.state.TokenList()
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers in an organization only see the tokens of the organization.
+	org := authOrganization(ctx)
+
+	var result []*pb.TokenInfo
+	for _, t := range tokens {
+		if t.Entrypoint && !req.IncludeEntrypoint {
+			continue
+		}
+		if org != "" && !strings.EqualFold(t.Organization, org) {
+			continue
+		}
+
+		result = append(result, t)
+	}
+
+	return &pb.ListTokensResponse{Tokens: result}, nil
+}
+
+func (s *service) RevokeToken(
+	ctx context.Context,
+	req *pb.RevokeTokenRequest,
+) (*empty.Empty, error) {
+	var id string
+	switch v := req.Target.(type) {
+	case *pb.RevokeTokenRequest_Id:
+		id = v.Id
+
+	case *pb.RevokeTokenRequest_Token:
+		_, body, err := s.DecodeToken(v.Token)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid token: %s", err)
+		}
+
+		id = tokenID(body)
+	}
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "the ID of the token is required")
+	}
+
+	// Having the token is enough to revoke it. Revoking any other token
+	// requires admin permission.
+	if info := authInfoFromContext(ctx); info != nil &&
+		req.GetToken() == "" &&
+		id != info.tokenId &&
+		!info.allowed("", "", pb.Permission_ADMIN) {
+		return nil, status.Errorf(codes.PermissionDenied,
+			"%s permission is required", pb.Permission_ADMIN)
+	}
+
+	// Callers in an organization can only revoke the tokens of the
+	// organization by ID.
+	if org := authOrganization(ctx); org != "" && req.GetToken() == "" {
+		t, err := s.state.TokenGet(id)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(t.Organization, org) {
+			return nil, status.Errorf(codes.PermissionDenied,
+				"token %q is not in organization %q", id, org)
+		}
+	}
+
+	if _, err := s.state.TokenRevoke(id); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
@@ -0,0 +1,332 @@
+package singleprocess
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/cronexpr"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-memdb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// triggerInterval is how often the schedules of triggers are checked.
+var triggerInterval = 30 * time.Second
+
+// triggerLabel is the label set on jobs queued by a trigger. The value is
+// the ID of the trigger.
+const triggerLabel = "waypoint/trigger"
+
+func (s *service) UpsertTrigger(
+	ctx context.Context,
+	req *pb.UpsertTriggerRequest,
+) (*pb.UpsertTriggerResponse, error) {
+	t := req.Trigger
+	if t == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "trigger must be set")
+	}
+	if t.Application.GetProject() == "" || t.Application.GetApplication() == "" {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"trigger must have a project and application")
+	}
+	if t.Operation == pb.Trigger_UNKNOWN {
+		return nil, status.Errorf(codes.InvalidArgument, "trigger must have an operation")
+	}
+	if t.Workspace == nil {
+		t.Workspace = &pb.Ref_Workspace{Workspace: "default"}
+	}
+
+	expr, err := cronexpr.Parse(t.Schedule)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"invalid schedule %q: %s", t.Schedule, err)
+	}
+
+	// The application must exist so that the jobs can be queued.
+	if _, err := s.state.AppGet(t.Application); err != nil {
+		return nil, err
+	}
+
+	if t.Id == "" {
+		t.Id, err = server.Id()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "uuid generation failed: %s", err)
+		}
+
+		t.LastTime = nil
+		t.LastJobId = ""
+		t.LastError = ""
+	} else {
+		// Keep the results of the existing trigger.
+		existing, err := s.state.TriggerGet(t.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		t.LastTime = existing.LastTime
+		t.LastJobId = existing.LastJobId
+		t.LastError = existing.LastError
+	}
+
+	next := expr.Next(time.Now().UTC())
+	if next.IsZero() {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"schedule %q never runs", t.Schedule)
+	}
+	t.NextTime, err = ptypes.TimestampProto(next)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode time: %s", err)
+	}
+
+	if err := s.state.TriggerPut(t); err != nil {
+		return nil, err
+	}
+
+	return &pb.UpsertTriggerResponse{Trigger: t}, nil
+}
+
+func (s *service) ListTriggers(
+	ctx context.Context,
+	req *pb.ListTriggersRequest,
+) (*pb.ListTriggersResponse, error) {
+	result, err := s.state.TriggerList(req.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListTriggersResponse{Triggers: result}, nil
+}
+
+func (s *service) DeleteTrigger(
+	ctx context.Context,
+	req *pb.DeleteTriggerRequest,
+) (*empty.Empty, error) {
+	if err := s.state.TriggerDelete(req.Id); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// runTriggers queues the jobs of triggers when they're due until ctx is
+// done. Only the leader queues jobs if the state is shared.
+func (s *service) runTriggers(ctx context.Context, log hclog.Logger) {
+	tick := time.NewTicker(triggerInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+
+		if !s.state.IsLeader() {
+			continue
+		}
+
+		if err := s.checkTriggers(ctx, log); err != nil {
+			log.Warn("error checking triggers", "err", err)
+		}
+	}
+}
+
+// checkTriggers starts the triggers that are due. If the server wasn't
+// running when a trigger was due, it starts once and is then scheduled
+// for its next time.
+func (s *service) checkTriggers(ctx context.Context, log hclog.Logger) error {
+	triggers, err := s.state.TriggerList(nil)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, t := range triggers {
+		next, err := ptypes.Timestamp(t.NextTime)
+		if err != nil || next.After(now) {
+			continue
+		}
+
+		// Schedule the next run before we start so that a slow trigger
+		// doesn't start again.
+		expr, err := cronexpr.Parse(t.Schedule)
+		if err != nil {
+			log.Warn("invalid trigger schedule", "trigger", t.Id, "err", err)
+			continue
+		}
+
+		// Triggers that will never run again keep no next time.
+		t.NextTime = nil
+		if next := expr.Next(now); !next.IsZero() {
+			t.NextTime, _ = ptypes.TimestampProto(next)
+		}
+		t.LastTime, _ = ptypes.TimestampProto(now)
+		t.LastJobId = ""
+		t.LastError = ""
+		if err := s.state.TriggerPut(t); err != nil {
+			return err
+		}
+
+		log.Info("starting trigger", "trigger", t.Id, "operation", t.Operation.String())
+		go s.runTrigger(ctx, log, t)
+	}
+
+	return nil
+}
+
+// runTrigger queues the jobs of the trigger and waits for them. The result
+// is stored on the trigger.
+func (s *service) runTrigger(ctx context.Context, log hclog.Logger, t *pb.Trigger) {
+	var firstJob string
+	err := s.runOperation(ctx, t.Operation, t.Application, t.Workspace,
+		map[string]string{triggerLabel: t.Id}, t.DataSourceOverrides,
+		func(jobId string) {
+			// Store the job right away so that it can be followed.
+			firstJob = jobId
+			s.triggerResult(log, t, firstJob, nil)
+		},
+	)
+	if err != nil {
+		log.Warn("trigger failed", "trigger", t.Id, "err", err)
+	}
+
+	s.triggerResult(log, t, firstJob, err)
+}
+
+// runOperation queues the jobs to perform op for the application and
+// waits for them to complete. Operations such as UP queue multiple jobs
+// one after the other. queued is called with the ID of the first job once
+// it's queued.
+func (s *service) runOperation(
+	ctx context.Context,
+	op pb.Trigger_Operation,
+	app *pb.Ref_Application,
+	ws *pb.Ref_Workspace,
+	labels map[string]string,
+	overrides map[string]string,
+	queued func(jobId string),
+) error {
+	first := true
+	queue := func(op interface{}) (*pb.Job_Result, error) {
+		job := &pb.Job{
+			Application: app,
+			Workspace:   ws,
+			TargetRunner: &pb.Ref_Runner{
+				Target: &pb.Ref_Runner_Any{Any: &pb.Ref_RunnerAny{}},
+			},
+			Labels:              labels,
+			DataSourceOverrides: overrides,
+		}
+
+		switch op := op.(type) {
+		case *pb.Job_BuildOp:
+			job.Operation = &pb.Job_Build{Build: op}
+		case *pb.Job_DeployOp:
+			job.Operation = &pb.Job_Deploy{Deploy: op}
+		case *pb.Job_ReleaseOp:
+			job.Operation = &pb.Job_Release{Release: op}
+		}
+
+		resp, err := s.QueueJob(ctx, &pb.QueueJobRequest{Job: job})
+		if err != nil {
+			return nil, err
+		}
+		if first {
+			first = false
+			queued(resp.JobId)
+		}
+
+		return s.waitJob(ctx, resp.JobId)
+	}
+
+	deploy := func() (*pb.Job_Result, error) {
+		artifact, err := s.state.ArtifactLatest(app, ws, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return queue(&pb.Job_DeployOp{Artifact: artifact})
+	}
+
+	var err error
+	switch op {
+	case pb.Trigger_BUILD:
+		_, err = queue(&pb.Job_BuildOp{})
+
+	case pb.Trigger_DEPLOY:
+		_, err = deploy()
+
+	case pb.Trigger_UP:
+		var result *pb.Job_Result
+		if _, err = queue(&pb.Job_BuildOp{}); err == nil {
+			result, err = deploy()
+		}
+		if err == nil {
+			_, err = queue(&pb.Job_ReleaseOp{
+				Deployment: result.GetDeploy().GetDeployment(),
+				Prune:      true,
+			})
+		}
+
+	default:
+		err = status.Errorf(codes.InvalidArgument, "unknown operation: %s", op)
+	}
+
+	return err
+}
+
+// triggerResult stores the result of the run of the trigger that started
+// at t.LastTime. The trigger may have been changed or deleted while the
+// jobs ran, so this updates the latest record.
+func (s *service) triggerResult(log hclog.Logger, t *pb.Trigger, jobId string, err error) {
+	current, gerr := s.state.TriggerGet(t.Id)
+	if gerr != nil {
+		return
+	}
+	if !proto.Equal(current.LastTime, t.LastTime) {
+		// The trigger started again in the meantime.
+		return
+	}
+
+	current.LastJobId = jobId
+	if err != nil {
+		current.LastError = err.Error()
+	}
+	if err := s.state.TriggerPut(current); err != nil {
+		log.Warn("error storing trigger result", "trigger", t.Id, "err", err)
+	}
+}
+
+// waitJob waits for a job to complete and returns its result. If the job
+// fails, the error of the job is returned.
+func (s *service) waitJob(ctx context.Context, id string) (*pb.Job_Result, error) {
+	for {
+		ws := memdb.NewWatchSet()
+		job, err := s.state.JobById(id, ws)
+		if err != nil {
+			return nil, err
+		}
+		if job == nil {
+			return nil, status.Errorf(codes.NotFound, "job not found: %s", id)
+		}
+
+		switch job.State {
+		case pb.Job_SUCCESS:
+			return job.Result, nil
+
+		case pb.Job_ERROR:
+			return nil, status.FromProto(job.Error).Err()
+		}
+
+		if err := ws.WatchCtx(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package singleprocess
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceTrigger(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	impl, err := New(WithDB(testDB(t)))
+	require.NoError(t, err)
+	s := impl.(*service)
+
+	require.NoError(t, s.state.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+		Name: "web",
+		DataSource: &pb.Job_DataSource{
+			Source: &pb.Job_DataSource_Local{Local: &pb.Job_Local{}},
+		},
+	})))
+	_, err = s.state.AppPut(serverptypes.TestApplication(t, &pb.Application{
+		Project: &pb.Ref_Project{Project: "web"},
+		Name:    "api",
+	}))
+	require.NoError(t, err)
+
+	app := &pb.Ref_Application{Project: "web", Application: "api"}
+
+	t.Run("validation", func(t *testing.T) {
+		require := require.New(t)
+
+		for _, trigger := range []*pb.Trigger{
+			{Application: app, Schedule: "nope", Operation: pb.Trigger_BUILD},
+			{Application: app, Schedule: "@daily"},
+			{Schedule: "@daily", Operation: pb.Trigger_BUILD},
+		} {
+			_, err := s.UpsertTrigger(ctx, &pb.UpsertTriggerRequest{Trigger: trigger})
+			require.Error(err)
+			require.Equal(codes.InvalidArgument, status.Code(err))
+		}
+
+		// The app must exist
+		_, err := s.UpsertTrigger(ctx, &pb.UpsertTriggerRequest{Trigger: &pb.Trigger{
+			Application: &pb.Ref_Application{Project: "web", Application: "nope"},
+			Schedule:    "@daily",
+			Operation:   pb.Trigger_BUILD,
+		}})
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+	})
+
+	t.Run("create, list and delete", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := s.UpsertTrigger(ctx, &pb.UpsertTriggerRequest{Trigger: &pb.Trigger{
+			Application: app,
+			Schedule:    "0 2 * * *",
+			Operation:   pb.Trigger_UP,
+		}})
+		require.NoError(err)
+		require.NotEmpty(resp.Trigger.Id)
+		require.Equal("default", resp.Trigger.Workspace.Workspace)
+
+		next, err := ptypes.Timestamp(resp.Trigger.NextTime)
+		require.NoError(err)
+		require.True(next.After(time.Now()))
+
+		list, err := s.ListTriggers(ctx, &pb.ListTriggersRequest{
+			Project: &pb.Ref_Project{Project: "WEB"},
+		})
+		require.NoError(err)
+		require.Len(list.Triggers, 1)
+
+		list, err = s.ListTriggers(ctx, &pb.ListTriggersRequest{
+			Project: &pb.Ref_Project{Project: "other"},
+		})
+		require.NoError(err)
+		require.Empty(list.Triggers)
+
+		_, err = s.DeleteTrigger(ctx, &pb.DeleteTriggerRequest{Id: resp.Trigger.Id})
+		require.NoError(err)
+		_, err = s.DeleteTrigger(ctx, &pb.DeleteTriggerRequest{Id: resp.Trigger.Id})
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+	})
+
+	t.Run("due triggers queue jobs", func(t *testing.T) {
+		require := require.New(t)
+
+		resp, err := s.UpsertTrigger(ctx, &pb.UpsertTriggerRequest{Trigger: &pb.Trigger{
+			Application: app,
+			Schedule:    "@hourly",
+			Operation:   pb.Trigger_BUILD,
+		}})
+		require.NoError(err)
+
+		// Make the trigger due
+		trigger := resp.Trigger
+		trigger.NextTime, err = ptypes.TimestampProto(time.Now().Add(-time.Minute))
+		require.NoError(err)
+		require.NoError(s.state.TriggerPut(trigger))
+
+		require.NoError(s.checkTriggers(ctx, hclog.L()))
+
+		// The trigger is scheduled again and the queued job is recorded
+		trigger, err = s.state.TriggerGet(trigger.Id)
+		require.NoError(err)
+		require.NotNil(trigger.LastTime)
+		next, err := ptypes.Timestamp(trigger.NextTime)
+		require.NoError(err)
+		require.True(next.After(time.Now()))
+
+		require.Eventually(func() bool {
+			trigger, err := s.state.TriggerGet(trigger.Id)
+			return err == nil && trigger.LastJobId != ""
+		}, 5*time.Second, 10*time.Millisecond)
+
+		trigger, err = s.state.TriggerGet(trigger.Id)
+		require.NoError(err)
+		job, err := s.state.JobById(trigger.LastJobId, nil)
+		require.NoError(err)
+		require.Equal(trigger.Id, job.Labels[triggerLabel])
+		require.NotNil(job.Operation.(*pb.Job_Build))
+	})
+}
@@ -5,23 +5,87 @@ import (
 	"crypto/tls"
 	"time"
 
+	grpctoken "github.com/hashicorp/horizon/pkg/grpc/token"
 	wphznpb "github.com/hashicorp/waypoint-hzn/pkg/pb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+
+	configpkg "github.com/hashicorp/waypoint/internal/config"
 )
 
-func (s *service) initURLGuestAccount(acceptURLTerms bool) error {
+// initURL connects to the URL service with the given configuration. If
+// the service isn't enabled, the connection to it is closed. This can be
+// called again to reload the configuration while the server runs.
+func (s *service) initURL(cfg *configpkg.URL) error {
+	var (
+		conn   *grpc.ClientConn
+		client wphznpb.WaypointHznClient
+	)
+	if cfg.Enabled {
+		// If we have no API token, get our guest account token.
+		if cfg.APIToken == "" {
+			if err := s.initURLGuestAccount(cfg); err != nil {
+				return err
+			}
+		}
+
+		// Now that we have a token, connect to the API service.
+		opts := []grpc.DialOption{
+			grpc.WithPerRPCCredentials(grpctoken.Token(cfg.APIToken)),
+		}
+		if cfg.APIInsecure {
+			opts = append(opts, grpc.WithInsecure())
+		} else {
+			opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+		}
+
+		var err error
+		conn, err = grpc.Dial(cfg.APIAddress, opts...)
+		if err != nil {
+			return err
+		}
+
+		client = wphznpb.NewWaypointHznClient(conn)
+	} else {
+		cfg = nil
+	}
+
+	s.reloadLock.Lock()
+	oldConn := s.urlConn
+	s.urlConfig = cfg
+	s.urlClient = client
+	s.urlConn = conn
+	s.reloadLock.Unlock()
+
+	// Calls that are still using the old connection will fail, but
+	// hostnames are only registered by short calls that can be retried.
+	if oldConn != nil {
+		oldConn.Close()
+	}
+
+	return nil
+}
+
+// url returns the configuration and client of the URL service. These are
+// nil if the URL service isn't enabled.
+func (s *service) url() (*configpkg.URL, wphznpb.WaypointHznClient) {
+	s.reloadLock.RLock()
+	defer s.reloadLock.RUnlock()
+	return s.urlConfig, s.urlClient
+}
+
+func (s *service) initURLGuestAccount(cfg *configpkg.URL) error {
 	// Connect without auth to our API client
 	var opts []grpc.DialOption
 	opts = append(opts, grpc.WithBlock(), grpc.WithTimeout(10*time.Second))
-	if s.urlConfig.APIInsecure {
+	if cfg.APIInsecure {
 		opts = append(opts, grpc.WithInsecure())
 	} else {
 		// If it isn't insecure, then we have to specify that we're using TLS
 		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
 	}
 
-	conn, err := grpc.Dial(s.urlConfig.APIAddress, opts...)
+	conn, err := grpc.Dial(cfg.APIAddress, opts...)
 	if err != nil {
 		return err
 	}
@@ -32,13 +96,13 @@ func (s *service) initURLGuestAccount(acceptURLTerms bool) error {
 		context.Background(),
 		&wphznpb.RegisterGuestAccountRequest{
 			ServerId:  s.id,
-			AcceptTos: acceptURLTerms,
+			AcceptTos: s.acceptURLTerms,
 		},
 	)
 	if err != nil {
 		return err
 	}
 
-	s.urlConfig.APIToken = accountResp.Token
+	cfg.APIToken = accountResp.Token
 	return nil
 }
@@ -0,0 +1,89 @@
+package singleprocess
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func (s *service) ListUsers(
+	ctx context.Context,
+	req *empty.Empty,
+) (*pb.ListUsersResponse, error) {
+	result, err := s.state.UserList()
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers in an organization only see the users of the organization.
+	if org := authOrganization(ctx); org != "" {
+		filtered := result[:0]
+		for _, u := range result {
+			if strings.EqualFold(u.Organization, org) {
+				filtered = append(filtered, u)
+			}
+		}
+
+		result = filtered
+	}
+
+	return &pb.ListUsersResponse{Users: result}, nil
+}
+
+func (s *service) SetUserPermissions(
+	ctx context.Context,
+	req *pb.SetUserPermissionsRequest,
+) (*pb.User, error) {
+	for _, p := range req.Permissions {
+		if _, ok := pb.Permission_Verb_name[int32(p.Verb)]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"unknown permission verb: %d", p.Verb)
+		}
+	}
+
+	user, err := s.state.UserGet(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	org := req.Organization
+	if callerOrg := authOrganization(ctx); callerOrg != "" {
+		// Callers in an organization can manage the users of their
+		// organization and add new users that have no permissions yet.
+		if org != "" && !strings.EqualFold(org, callerOrg) {
+			return nil, status.Errorf(codes.PermissionDenied,
+				"can't move users to another organization")
+		}
+		if !strings.EqualFold(user.Organization, callerOrg) &&
+			(user.Organization != "" || len(user.Permissions) > 0) {
+			return nil, status.Errorf(codes.PermissionDenied,
+				"user %q is not in organization %q", user.Id, callerOrg)
+		}
+
+		org = callerOrg
+	} else if org != "" {
+		o, err := s.state.OrganizationGet(org)
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"organization not found: %s", org)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		org = o.Name
+	}
+
+	user.Permissions = req.Permissions
+	user.Organization = org
+	if err := s.state.UserPut(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,546 @@
+package singleprocess
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	// webhookLabel is the label set on jobs queued by a webhook. The value
+	// is the ID of the webhook.
+	webhookLabel = "waypoint/webhook"
+
+	// webhookBodyMax is the maximum size of the body of webhook requests.
+	webhookBodyMax = 1024 * 1024
+
+	// webhookMaxSkew is how far the signed timestamp of generic webhook
+	// requests can be from the time of the server.
+	webhookMaxSkew = 5 * time.Minute
+
+	// webhookDeliveryTTL is how long the deliveries of webhooks are
+	// recorded to reject replays of them.
+	webhookDeliveryTTL = 90 * 24 * time.Hour
+)
+
+func (s *service) UpsertWebhook(
+	ctx context.Context,
+	req *pb.UpsertWebhookRequest,
+) (*pb.UpsertWebhookResponse, error) {
+	hook := req.Webhook
+	if hook == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "webhook must be set")
+	}
+	if hook.Project.GetProject() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "webhook must have a project")
+	}
+	if hook.Operation == pb.Trigger_UNKNOWN {
+		return nil, status.Errorf(codes.InvalidArgument, "webhook must have an operation")
+	}
+	if _, ok := pb.Webhook_Provider_name[int32(hook.Provider)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"unknown webhook provider: %d", hook.Provider)
+	}
+	for _, pattern := range hook.Refs {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"invalid ref pattern %q: %s", pattern, err)
+		}
+	}
+	if hook.Workspace == nil {
+		hook.Workspace = &pb.Ref_Workspace{Workspace: "default"}
+	}
+
+	// The project and applications must exist so that jobs can be queued.
+	if _, err := s.state.ProjectGet(hook.Project); err != nil {
+		return nil, err
+	}
+	for _, app := range hook.Applications {
+		_, err := s.state.AppGet(&pb.Ref_Application{
+			Project:     hook.Project.Project,
+			Application: app,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if hook.Id == "" {
+		id, err := server.Id()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "uuid generation failed: %s", err)
+		}
+		hook.Id = id
+
+		hook.LastTime = nil
+		hook.LastRef = ""
+		hook.LastJobIds = nil
+		hook.LastError = ""
+	} else {
+		// Keep the secret unless it's changed and the results of the
+		// existing webhook.
+		existing, err := s.state.WebhookGet(hook.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if hook.Secret == "" {
+			hook.Secret = existing.Secret
+		}
+		hook.LastTime = existing.LastTime
+		hook.LastRef = existing.LastRef
+		hook.LastJobIds = existing.LastJobIds
+		hook.LastError = existing.LastError
+	}
+
+	if hook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, status.Errorf(codes.Internal, "secret generation failed: %s", err)
+		}
+
+		hook.Secret = hex.EncodeToString(secret)
+	}
+
+	if err := s.state.WebhookPut(hook); err != nil {
+		return nil, err
+	}
+
+	return &pb.UpsertWebhookResponse{Webhook: hook}, nil
+}
+
+func (s *service) ListWebhooks(
+	ctx context.Context,
+	req *pb.ListWebhooksRequest,
+) (*pb.ListWebhooksResponse, error) {
+	result, err := s.state.WebhookList(req.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	// Secrets are only returned when the webhook is upserted.
+	for _, hook := range result {
+		hook.Secret = ""
+	}
+
+	return &pb.ListWebhooksResponse{Webhooks: result}, nil
+}
+
+func (s *service) DeleteWebhook(
+	ctx context.Context,
+	req *pb.DeleteWebhookRequest,
+) (*empty.Empty, error) {
+	if err := s.state.WebhookDelete(req.Id); err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// ServeWebhook implements server.WebhookHandler. The request is verified
+// with the secret of the webhook and a push to a matching ref queues jobs
+// for the applications of the project. The IDs of the first job of each
+// application are returned.
+func (s *service) ServeWebhook(w http.ResponseWriter, r *http.Request) {
+	log := hclog.FromContext(r.Context()).Named("webhook")
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	hook, err := s.state.WebhookGet(strings.TrimPrefix(r.URL.Path, server.WebhookPath))
+	if status.Code(err) == codes.NotFound {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Warn("error getting webhook", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, webhookBodyMax))
+	if err != nil {
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	delivery, ok := webhookVerify(hook, r, body, time.Now())
+	if !ok {
+		log.Warn("webhook request failed verification", "webhook", hook.Id)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	// Reject replays of deliveries we already received.
+	if delivery != "" {
+		added, err := s.state.WebhookDeliveryAdd(hook.Id, delivery)
+		if err != nil {
+			log.Warn("error recording webhook delivery", "webhook", hook.Id, "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if !added {
+			log.Warn("webhook delivery was already received", "webhook", hook.Id)
+			http.Error(w, "delivery was already received", http.StatusConflict)
+			return
+		}
+	}
+
+	ref, commit, files, err := webhookParse(hook, r, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ref == "" || !webhookRefMatch(hook.Refs, ref) {
+		// Other events, such as GitHub's ping event, and pushes to refs
+		// we don't build are accepted but ignored.
+		webhookRespond(w, http.StatusOK, nil, nil)
+		return
+	}
+
+	// Check out the pushed commit if we know it, otherwise the ref.
+	checkout := commit
+	if checkout == "" {
+		checkout = webhookShortRef(ref)
+	}
+
+	project, err := s.state.ProjectGet(hook.Project)
+	if err != nil {
+		log.Warn("error getting webhook project", "webhook", hook.Id, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	apps := hook.Applications
+	if len(apps) == 0 {
+		for _, app := range project.Applications {
+			apps = append(apps, app.Name)
+		}
+	}
+
+	// Only apps with changed files are operated on
+	apps = pathGlobsApps(log, project, apps, files)
+
+	log.Info("webhook received push", "webhook", hook.Id, "ref", ref, "commit", commit)
+
+	// Queue the jobs of each application. We wait until the first job of
+	// each is queued so that we can return their IDs, but the rest of the
+	// jobs continue in the background.
+	var (
+		wg     sync.WaitGroup
+		lock   sync.Mutex
+		jobIds []string
+		errs   []string
+	)
+	queuedCh := make(chan struct{}, len(apps))
+	for _, app := range apps {
+		wg.Add(1)
+		go func(app string) {
+			defer wg.Done()
+
+			queued := false
+			err := s.runOperation(s.ctx, hook.Operation,
+				&pb.Ref_Application{Project: hook.Project.Project, Application: app},
+				hook.Workspace,
+				map[string]string{webhookLabel: hook.Id},
+				map[string]string{"ref": checkout},
+				func(jobId string) {
+					lock.Lock()
+					defer lock.Unlock()
+					jobIds = append(jobIds, jobId)
+
+					queued = true
+					queuedCh <- struct{}{}
+				},
+			)
+			if err != nil {
+				log.Warn("webhook failed", "webhook", hook.Id, "app", app, "err", err)
+
+				lock.Lock()
+				errs = append(errs, fmt.Sprintf("%s: %s", app, err))
+				lock.Unlock()
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			if !queued {
+				queuedCh <- struct{}{}
+			}
+		}(app)
+	}
+	for range apps {
+		<-queuedCh
+	}
+
+	lock.Lock()
+	respJobIds := append([]string(nil), jobIds...)
+	respErrs := append([]string(nil), errs...)
+	lock.Unlock()
+
+	hook.LastTime = ptypes.TimestampNow()
+	hook.LastRef = ref
+	hook.LastJobIds = respJobIds
+	hook.LastError = strings.Join(respErrs, "; ")
+	if err := s.state.WebhookPut(hook); err != nil {
+		log.Warn("error storing webhook result", "webhook", hook.Id, "err", err)
+	}
+
+	// Store the errors of the jobs once they're all done.
+	go func() {
+		wg.Wait()
+		if len(errs) > len(respErrs) {
+			s.webhookResult(log, hook, strings.Join(errs, "; "))
+		}
+	}()
+
+	code := http.StatusAccepted
+	if len(respJobIds) == 0 && len(respErrs) > 0 {
+		code = http.StatusInternalServerError
+	}
+	webhookRespond(w, code, respJobIds, respErrs)
+}
+
+// webhookResult stores the error of the run of the webhook that started
+// at hook.LastTime, unless the webhook has run again since.
+func (s *service) webhookResult(log hclog.Logger, hook *pb.Webhook, errMsg string) {
+	current, err := s.state.WebhookGet(hook.Id)
+	if err != nil {
+		return
+	}
+	if !proto.Equal(current.LastTime, hook.LastTime) {
+		return
+	}
+
+	current.LastError = errMsg
+	if err := s.state.WebhookPut(current); err != nil {
+		log.Warn("error storing webhook result", "webhook", hook.Id, "err", err)
+	}
+}
+
+// webhookRespond writes the IDs of the queued jobs and any errors that
+// prevented jobs from being queued as JSON.
+func webhookRespond(w http.ResponseWriter, code int, jobIds, errs []string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string][]string{
+		"job_ids": jobIds,
+		"errors":  errs,
+	})
+}
+
+// webhookVerify returns true if the request was signed with the secret of
+// the webhook. It also returns the ID of the delivery, which is recorded
+// so that the request can't be replayed. The ID is empty for providers
+// that don't identify their deliveries.
+func webhookVerify(hook *pb.Webhook, r *http.Request, body []byte, now time.Time) (string, bool) {
+	switch hook.Provider {
+	case pb.Webhook_GITLAB:
+		// GitLab sends the secret token as is.
+		token := r.Header.Get("X-Gitlab-Token")
+		return "", subtle.ConstantTimeCompare([]byte(token), []byte(hook.Secret)) == 1
+
+	case pb.Webhook_GITHUB:
+		// GitHub doesn't sign a timestamp, so each delivery ID is only
+		// accepted once.
+		delivery := r.Header.Get("X-GitHub-Delivery")
+		if delivery == "" {
+			return "", false
+		}
+
+		return delivery, webhookVerifySignature(
+			hook.Secret, r.Header.Get("X-Hub-Signature-256"), body)
+
+	default:
+		// The timestamp is signed with the body so that old requests are
+		// rejected. Requests within the skew are only accepted once, by
+		// their signature.
+		ts := r.Header.Get("X-Waypoint-Timestamp")
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return "", false
+		}
+		if skew := now.Sub(time.Unix(sec, 0)); skew > webhookMaxSkew || skew < -webhookMaxSkew {
+			return "", false
+		}
+
+		sig := r.Header.Get("X-Waypoint-Signature")
+		signed := append([]byte(ts+"."), body...)
+		return sig, webhookVerifySignature(hook.Secret, sig, signed)
+	}
+}
+
+// pruneWebhookDeliveries deletes the deliveries of webhooks that are older
+// than webhookDeliveryTTL.
+func (s *service) pruneWebhookDeliveries(log hclog.Logger) {
+	n, err := s.state.WebhookDeliveryPrune(time.Now().Add(-webhookDeliveryTTL))
+	if err != nil {
+		log.Warn("error pruning webhook deliveries", "err", err)
+		return
+	}
+	if n > 0 {
+		log.Debug("pruned webhook deliveries", "count", n)
+	}
+}
+
+// webhookVerifySignature verifies a "sha256=<hex>" HMAC signature of data.
+func webhookVerifySignature(secret, header string, data []byte) bool {
+	if !strings.HasPrefix(header, "sha256=") {
+		return false
+	}
+
+	sig, err := hex.DecodeString(strings.TrimPrefix(header, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+// webhookParse returns the pushed ref and commit of a webhook request and
+// the files that the push changed. The ref is empty if the request isn't
+// the push of a ref, such as other events or the deletion of a branch. The
+// files are nil if they aren't known.
+func webhookParse(hook *pb.Webhook, r *http.Request, body []byte) (string, string, []string, error) {
+	switch hook.Provider {
+	case pb.Webhook_GITHUB:
+		if r.Header.Get("X-GitHub-Event") != "push" {
+			return "", "", nil, nil
+		}
+
+		var event struct {
+			Ref     string          `json:"ref"`
+			After   string          `json:"after"`
+			Deleted bool            `json:"deleted"`
+			Commits []webhookCommit `json:"commits"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			return "", "", nil, fmt.Errorf("invalid push event: %s", err)
+		}
+		if event.Deleted {
+			return "", "", nil, nil
+		}
+
+		return event.Ref, event.After, webhookFiles(event.Commits, len(event.Commits)), nil
+
+	case pb.Webhook_GITLAB:
+		var event struct {
+			ObjectKind        string          `json:"object_kind"`
+			Ref               string          `json:"ref"`
+			CheckoutSha       string          `json:"checkout_sha"`
+			Commits           []webhookCommit `json:"commits"`
+			TotalCommitsCount int             `json:"total_commits_count"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			return "", "", nil, fmt.Errorf("invalid push event: %s", err)
+		}
+		if event.ObjectKind != "push" && event.ObjectKind != "tag_push" {
+			return "", "", nil, nil
+		}
+
+		// The checkout SHA is null if the ref was deleted.
+		if event.CheckoutSha == "" {
+			return "", "", nil, nil
+		}
+
+		return event.Ref, event.CheckoutSha, webhookFiles(event.Commits, event.TotalCommitsCount), nil
+
+	default:
+		var event struct {
+			Ref    string   `json:"ref"`
+			Commit string   `json:"commit"`
+			Files  []string `json:"files"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			return "", "", nil, fmt.Errorf("invalid request: %s", err)
+		}
+
+		return event.Ref, event.Commit, event.Files, nil
+	}
+}
+
+// webhookCommit is a commit of a GitHub or GitLab push event.
+type webhookCommit struct {
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Modified []string `json:"modified"`
+}
+
+// webhookFiles returns the files that the commits changed. total is the
+// number of commits that were pushed. The providers only list some of the
+// commits of large pushes, so the files aren't known if commits has fewer
+// than total or none, such as for new tags.
+func webhookFiles(commits []webhookCommit, total int) []string {
+	if len(commits) == 0 || len(commits) < total {
+		return nil
+	}
+
+	result := []string{}
+	for _, c := range commits {
+		result = append(result, c.Added...)
+		result = append(result, c.Removed...)
+		result = append(result, c.Modified...)
+	}
+
+	return result
+}
+
+// webhookRefMatch returns true if the ref matches any of the patterns. The
+// patterns can match the full ref, such as "refs/heads/main", or the name
+// of the branch or tag, such as "main". Any ref matches if there are no
+// patterns.
+func webhookRefMatch(patterns []string, ref string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	short := webhookShortRef(ref)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, ref); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, short); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// webhookShortRef returns the name of the branch or tag of the ref.
+func webhookShortRef(ref string) string {
+	for _, prefix := range []string{"refs/heads/", "refs/tags/"} {
+		if strings.HasPrefix(ref, prefix) {
+			return strings.TrimPrefix(ref, prefix)
+		}
+	}
+
+	return ref
+}
+
+var _ server.WebhookHandler = (*service)(nil)
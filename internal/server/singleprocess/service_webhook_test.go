@@ -0,0 +1,205 @@
+package singleprocess
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/waypoint/internal/server"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestServiceWebhook(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	impl, err := New(WithDB(testDB(t)), WithContext(ctx))
+	require.NoError(t, err)
+	s := impl.(*service)
+
+	require.NoError(t, s.state.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+		Name: "web",
+		DataSource: &pb.Job_DataSource{
+			Source: &pb.Job_DataSource_Local{Local: &pb.Job_Local{}},
+		},
+	})))
+	for _, name := range []string{"api", "frontend"} {
+		_, err = s.state.AppPut(serverptypes.TestApplication(t, &pb.Application{
+			Project: &pb.Ref_Project{Project: "web"},
+			Name:    name,
+		}))
+		require.NoError(t, err)
+	}
+
+	resp, err := s.UpsertWebhook(ctx, &pb.UpsertWebhookRequest{Webhook: &pb.Webhook{
+		Project:   &pb.Ref_Project{Project: "web"},
+		Provider:  pb.Webhook_GITHUB,
+		Refs:      []string{"main"},
+		Operation: pb.Trigger_BUILD,
+	}})
+	require.NoError(t, err)
+	hook := resp.Webhook
+	require.NotEmpty(t, hook.Secret)
+
+	// send posts a push event signed with the secret of the webhook. Each
+	// request is a new delivery unless delivery is set.
+	var deliveries int
+	send := func(t *testing.T, secret, ref string, delivery ...string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]string{"ref": ref, "after": "3e7a1b2"})
+		require.NoError(t, err)
+
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+
+		deliveries++
+		id := fmt.Sprintf("delivery-%d", deliveries)
+		if len(delivery) > 0 {
+			id = delivery[0]
+		}
+
+		r := httptest.NewRequest("POST", server.WebhookPath+hook.Id, bytes.NewReader(body))
+		r.Header.Set("X-GitHub-Event", "push")
+		r.Header.Set("X-GitHub-Delivery", id)
+		r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+		w := httptest.NewRecorder()
+		s.ServeWebhook(w, r)
+		return w
+	}
+
+	t.Run("invalid signature", func(t *testing.T) {
+		w := send(t, "nope", "refs/heads/main")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("ignored ref", func(t *testing.T) {
+		w := send(t, hook.Secret, "refs/heads/feature")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		current, err := s.state.WebhookGet(hook.Id)
+		require.NoError(t, err)
+		require.Nil(t, current.LastTime)
+	})
+
+	t.Run("push queues jobs for every app", func(t *testing.T) {
+		require := require.New(t)
+
+		w := send(t, hook.Secret, "refs/heads/main")
+		require.Equal(http.StatusAccepted, w.Code)
+
+		var result struct {
+			JobIds []string `json:"job_ids"`
+		}
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &result))
+		require.Len(result.JobIds, 2)
+
+		for _, id := range result.JobIds {
+			job, err := s.state.JobById(id, nil)
+			require.NoError(err)
+			require.Equal(hook.Id, job.Labels[webhookLabel])
+			require.Equal("3e7a1b2", job.DataSourceOverrides["ref"])
+		}
+
+		current, err := s.state.WebhookGet(hook.Id)
+		require.NoError(err)
+		require.Equal("refs/heads/main", current.LastRef)
+		require.ElementsMatch(result.JobIds, current.LastJobIds)
+	})
+
+	t.Run("replayed deliveries are rejected", func(t *testing.T) {
+		w := send(t, hook.Secret, "refs/heads/feature", "replayed")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = send(t, hook.Secret, "refs/heads/feature", "replayed")
+		require.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("deliveries must have an ID", func(t *testing.T) {
+		w := send(t, hook.Secret, "refs/heads/feature", "")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("secrets aren't listed", func(t *testing.T) {
+		list, err := s.ListWebhooks(ctx, &pb.ListWebhooksRequest{})
+		require.NoError(t, err)
+		require.Len(t, list.Webhooks, 1)
+		require.Empty(t, list.Webhooks[0].Secret)
+	})
+}
+
+func TestWebhookVerify_generic(t *testing.T) {
+	hook := &pb.Webhook{Provider: pb.Webhook_GENERIC, Secret: "secret"}
+	body := []byte(`{"ref": "refs/heads/main"}`)
+	now := time.Now()
+
+	sign := func(secret, ts string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(ts + "."))
+		mac.Write(body)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	unix := func(t time.Time) string {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+
+	cases := []struct {
+		Name      string
+		Timestamp string
+		Signature string
+		Ok        bool
+	}{
+		{"valid", unix(now), sign("secret", unix(now)), true},
+		{"within the skew", unix(now.Add(-4 * time.Minute)), sign("secret", unix(now.Add(-4*time.Minute))), true},
+		{"old timestamp", unix(now.Add(-10 * time.Minute)), sign("secret", unix(now.Add(-10*time.Minute))), false},
+		{"future timestamp", unix(now.Add(10 * time.Minute)), sign("secret", unix(now.Add(10*time.Minute))), false},
+		{"no timestamp", "", sign("secret", ""), false},
+		{"timestamp not signed", unix(now), sign("secret", unix(now.Add(-time.Second))), false},
+		{"wrong secret", unix(now), sign("nope", unix(now)), false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", server.WebhookPath+"A", bytes.NewReader(body))
+			r.Header.Set("X-Waypoint-Timestamp", tt.Timestamp)
+			r.Header.Set("X-Waypoint-Signature", tt.Signature)
+
+			delivery, ok := webhookVerify(hook, r, body, now)
+			require.Equal(t, tt.Ok, ok)
+			if ok {
+				require.Equal(t, tt.Signature, delivery)
+			}
+		})
+	}
+}
+
+func TestWebhookRefMatch(t *testing.T) {
+	cases := []struct {
+		Patterns []string
+		Ref      string
+		Expected bool
+	}{
+		{nil, "refs/heads/main", true},
+		{[]string{"main"}, "refs/heads/main", true},
+		{[]string{"main"}, "refs/heads/feature", false},
+		{[]string{"refs/tags/v*"}, "refs/tags/v1.0.0", true},
+		{[]string{"refs/tags/v*"}, "refs/heads/v1", false},
+		{[]string{"release-*", "main"}, "refs/heads/release-1", true},
+	}
+
+	for _, tt := range cases {
+		require.Equal(t, tt.Expected, webhookRefMatch(tt.Patterns, tt.Ref),
+			"%v %s", tt.Patterns, tt.Ref)
+	}
+}
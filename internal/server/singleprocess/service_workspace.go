@@ -17,6 +17,7 @@ func (s *service) GetWorkspace(
 	if err != nil {
 		return nil, err
 	}
+	s.workspaceOrganizationFilter(authOrganization(ctx), result)
 
 	return &pb.GetWorkspaceResponse{Workspace: result}, nil
 }
@@ -31,5 +32,36 @@ func (s *service) ListWorkspaces(
 		return nil, err
 	}
 
+	// Callers in an organization only see the workspaces that the
+	// applications of the organization are in.
+	if org := authOrganization(ctx); org != "" {
+		filtered := result[:0]
+		for _, ws := range result {
+			s.workspaceOrganizationFilter(org, ws)
+			if len(ws.Applications) > 0 {
+				filtered = append(filtered, ws)
+			}
+		}
+
+		result = filtered
+	}
+
 	return &pb.ListWorkspacesResponse{Workspaces: result}, nil
 }
+
+// workspaceOrganizationFilter removes the applications of projects that
+// aren't in the organization from the workspace.
+func (s *service) workspaceOrganizationFilter(org string, ws *pb.Workspace) {
+	if org == "" {
+		return
+	}
+
+	apps := ws.Applications[:0]
+	for _, app := range ws.Applications {
+		if s.organizationHasProject(org, app.Application.GetProject()) {
+			apps = append(apps, app)
+		}
+	}
+
+	ws.Applications = apps
+}
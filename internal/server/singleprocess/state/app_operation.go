@@ -3,11 +3,11 @@ package state
 import (
 	"errors"
 	"reflect"
+	"sort"
 	"strings"
 	"sync/atomic"
 	"time"
 
-	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/hashicorp/go-memdb"
@@ -94,7 +94,7 @@ func (op *appOperation) Put(s *State, update bool, value proto.Message) error {
 	memTxn := s.inmem.Txn(true)
 	defer memTxn.Abort()
 
-	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+	err := s.db.Update(func(dbTxn Tx) error {
 		return op.dbPut(s, dbTxn, memTxn, update, value)
 	})
 	if err == nil {
@@ -110,7 +110,7 @@ func (op *appOperation) Get(s *State, ref *pb.Ref_Operation) (interface{}, error
 	defer memTxn.Abort()
 
 	result := op.newStruct()
-	err := s.db.View(func(tx *bolt.Tx) error {
+	err := s.db.View(func(tx Tx) error {
 		var id string
 		switch t := ref.Target.(type) {
 		case *pb.Ref_Operation_Id:
@@ -139,7 +139,7 @@ func (op *appOperation) Get(s *State, ref *pb.Ref_Operation) (interface{}, error
 
 func (op *appOperation) getIdForSeq(
 	s *State,
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	ref *pb.Ref_OperationSeq,
 ) (string, error) {
@@ -195,8 +195,14 @@ func (op *appOperation) List(s *State, opts *listOperationsOptions) ([]interface
 		return nil, err
 	}
 
+	// If we're continuing from a previous page, skip all the records up
+	// to and including the last record of that page.
+	page := opts.Page
+	skip := page != nil && page.Token != ""
+
 	var result []interface{}
-	s.db.View(func(tx *bolt.Tx) error {
+	var ids []string
+	err = s.db.View(func(tx Tx) error {
 		for {
 			current := iter.Next()
 			if current == nil {
@@ -208,11 +214,20 @@ func (op *appOperation) List(s *State, opts *listOperationsOptions) ([]interface
 				return nil
 			}
 
+			if skip {
+				skip = record.Id != page.Token
+				continue
+			}
+
 			// If our workspace doesn't match then continue to the next result.
 			if opts.Workspace != nil && record.Workspace != opts.Workspace.Workspace {
 				continue
 			}
 
+			if !timeRangeMatch(opts.StartTime, record.StartTime) {
+				continue
+			}
+
 			value := op.newStruct()
 			if err := op.dbGet(tx, []byte(record.Id), value); err != nil {
 				return err
@@ -238,13 +253,36 @@ func (op *appOperation) List(s *State, opts *listOperationsOptions) ([]interface
 			}
 
 			result = append(result, value)
+			ids = append(ids, record.Id)
 
 			// If we have a limit, check that now
 			if o := opts.Order; o != nil && o.Limit > 0 && len(result) >= int(o.Limit) {
 				return nil
 			}
+
+			// We read one more than the page size to know if there is
+			// another page.
+			if page != nil && page.Size > 0 && len(result) > int(page.Size) {
+				return nil
+			}
 		}
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if skip {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"invalid page token: %s", page.Token)
+	}
+
+	if page != nil {
+		page.Next = ""
+		if page.Size > 0 && len(result) > int(page.Size) {
+			result = result[:page.Size]
+			page.Next = ids[page.Size-1]
+		}
+	}
 
 	return result, nil
 }
@@ -254,6 +292,16 @@ func (op *appOperation) Latest(
 	s *State,
 	ref *pb.Ref_Application,
 	ws *pb.Ref_Workspace,
+) (interface{}, error) {
+	return op.LatestLabels(s, ref, ws, nil)
+}
+
+// LatestLabels is Latest for the records that have all of the labels.
+func (op *appOperation) LatestLabels(
+	s *State,
+	ref *pb.Ref_Application,
+	ws *pb.Ref_Workspace,
+	labels map[string]string,
 ) (interface{}, error) {
 	memTxn := s.inmem.Txn(false)
 	defer memTxn.Abort()
@@ -299,18 +347,123 @@ func (op *appOperation) Latest(
 		}
 
 		// State must be success.
-		switch st.(*pb.Status).State {
-		case pb.Status_SUCCESS:
-			return v, nil
+		if st.(*pb.Status).State != pb.Status_SUCCESS {
+			continue
 		}
+
+		// It must have all of the labels.
+		if len(labels) > 0 {
+			ls, _ := op.valueField(v, "Labels").(map[string]string)
+			match := true
+			for k, lv := range labels {
+				if got, ok := ls[k]; !ok || got != lv {
+					match = false
+					break
+				}
+			}
+			if !match {
+				continue
+			}
+		}
+
+		return v, nil
 	}
 
 	return nil, status.Error(codes.NotFound, "none available")
 }
 
+// Search finds the records that match the options using the labels or
+// artifact indexes. The latest records are returned first.
+func (op *appOperation) Search(s *State, opts *searchOperationsOptions) ([]interface{}, error) {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	// Get the records of the most selective index we can use. These still
+	// have to be matched against all the options.
+	var iters []memdb.ResultIterator
+	switch {
+	case len(opts.ArtifactIds) > 0:
+		for _, id := range opts.ArtifactIds {
+			iter, err := memTxn.Get(op.memTableName(), opArtifactIdIndexName, id)
+			if err != nil {
+				return nil, err
+			}
+
+			iters = append(iters, iter)
+		}
+
+	case opts.ArtifactDigest != "":
+		iter, err := memTxn.Get(op.memTableName(), opArtifactDigestIndexName, opts.ArtifactDigest)
+		if err != nil {
+			return nil, err
+		}
+
+		iters = append(iters, iter)
+
+	case len(opts.Labels) > 0:
+		var keys []string
+		for k := range opts.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		iter, err := memTxn.Get(op.memTableName(), opLabelsIndexName, keys[0], opts.Labels[keys[0]])
+		if err != nil {
+			return nil, err
+		}
+
+		iters = append(iters, iter)
+
+	default:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"labels or an artifact digest are required to search")
+	}
+
+	var records []*operationIndexRecord
+	for _, iter := range iters {
+		for {
+			current := iter.Next()
+			if current == nil {
+				break
+			}
+
+			record := current.(*operationIndexRecord)
+			if opts.match(record) {
+				records = append(records, record)
+			}
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartTime.After(records[j].StartTime)
+	})
+	if opts.Limit > 0 && len(records) > int(opts.Limit) {
+		records = records[:opts.Limit]
+	}
+
+	var result []interface{}
+	err := s.db.View(func(tx Tx) error {
+		for _, record := range records {
+			value := op.newStruct()
+			if err := op.dbGet(tx, []byte(record.Id), value); err != nil {
+				return err
+			}
+
+			result = append(result, value)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // dbGet reads the value from the database.
 func (op *appOperation) dbGet(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	id []byte,
 	result proto.Message,
 ) error {
@@ -328,10 +481,10 @@ func (op *appOperation) dbGet(
 }
 
 // dbPut wites the value to the database and also sets up any index records.
-// It expects to hold a write transaction to both bolt and memdb.
+// It expects to hold a write transaction to both the db and memdb.
 func (op *appOperation) dbPut(
 	s *State,
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	update bool,
 	value proto.Message,
@@ -435,7 +588,7 @@ func (op *appOperation) appSeq(ref *pb.Ref_Application) *uint64 {
 
 // indexInit initializes the index table in memdb from all the records
 // persisted on disk.
-func (op *appOperation) indexInit(s *State, dbTxn *bolt.Tx, memTxn *memdb.Txn) error {
+func (op *appOperation) indexInit(s *State, dbTxn Tx, memTxn *memdb.Txn) error {
 	bucket := dbTxn.Bucket(op.Bucket)
 	return bucket.ForEach(func(k, v []byte) error {
 		result := op.newStruct()
@@ -507,14 +660,32 @@ func (op *appOperation) indexPut(s *State, txn *memdb.Txn, value proto.Message)
 		return err
 	}
 
+	// Get the fields that operations can be searched by, which only
+	// some operations have.
+	var labels map[string]string
+	if v, ok := op.valueField(value, "Labels").(map[string]string); ok {
+		labels = v
+	}
+	var digest string
+	if v, ok := op.valueField(value, "Artifact").(*pb.Artifact); ok {
+		digest = ArtifactDigest(v)
+	}
+	var artifactId string
+	if v, ok := op.valueField(value, "ArtifactId").(string); ok {
+		artifactId = v
+	}
+
 	return txn.Insert(op.memTableName(), &operationIndexRecord{
-		Id:           op.valueField(value, "Id").(string),
-		Project:      ref.Project,
-		App:          ref.Application,
-		Workspace:    wsRef.Workspace,
-		Sequence:     sequence,
-		StartTime:    startTime,
-		CompleteTime: completeTime,
+		Id:             op.valueField(value, "Id").(string),
+		Project:        ref.Project,
+		App:            ref.Application,
+		Workspace:      wsRef.Workspace,
+		Sequence:       sequence,
+		StartTime:      startTime,
+		CompleteTime:   completeTime,
+		Labels:         labels,
+		ArtifactDigest: digest,
+		ArtifactId:     artifactId,
 	})
 }
 
@@ -617,6 +788,34 @@ func (op *appOperation) memSchema() *memdb.TableSchema {
 				},
 			},
 
+			opLabelsIndexName: &memdb.IndexSchema{
+				Name:         opLabelsIndexName,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringMapFieldIndex{
+					Field: "Labels",
+				},
+			},
+
+			opArtifactDigestIndexName: &memdb.IndexSchema{
+				Name:         opArtifactDigestIndexName,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field:     "ArtifactDigest",
+					Lowercase: true,
+				},
+			},
+
+			opArtifactIdIndexName: &memdb.IndexSchema{
+				Name:         opArtifactIdIndexName,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ArtifactId",
+				},
+			},
+
 			opSeqIndexName: &memdb.IndexSchema{
 				Name:         opSeqIndexName,
 				AllowMissing: false,
@@ -653,6 +852,12 @@ type operationIndexRecord struct {
 	Sequence     uint64
 	StartTime    time.Time
 	CompleteTime time.Time
+
+	// Labels, ArtifactDigest and ArtifactId are only set for the
+	// operations that have them, and are used to search operations.
+	Labels         map[string]string
+	ArtifactDigest string
+	ArtifactId     string
 }
 
 // MatchRef checks if a record matches the ref value. We have to provide
@@ -667,6 +872,10 @@ const (
 	opStartTimeIndexName    = "start-time"    // start time index
 	opCompleteTimeIndexName = "complete-time" // complete time index
 	opSeqIndexName          = "seq"           // sequence number index
+
+	opLabelsIndexName         = "labels"          // labels index
+	opArtifactDigestIndexName = "artifact-digest" // artifact digest index
+	opArtifactIdIndexName     = "artifact-id"     // artifact ID index of deployments
 )
 
 // listOperationsOptions are options that can be set for List calls on
@@ -677,6 +886,22 @@ type listOperationsOptions struct {
 	Status        []*pb.StatusFilter
 	Order         *pb.OperationOrder
 	PhysicalState pb.Operation_PhysicalState
+	StartTime     *pb.TimeRange
+	Page          *ListPage
+}
+
+// ListPage requests a single page of the results of a List call. Next is
+// set by the call to the Token of the next page, or empty if this was the
+// last page.
+type ListPage struct {
+	// Size is the maximum number of results. If this is zero, all results
+	// are returned.
+	Size uint32
+
+	// Token is the Next value of the previous page, if any.
+	Token string
+
+	Next string
 }
 
 func buildListOperationsOptions(ref *pb.Ref_Application, opts ...ListOperationOption) *listOperationsOptions {
@@ -720,6 +945,103 @@ func ListWithWorkspace(f *pb.Ref_Workspace) ListOperationOption {
 	}
 }
 
+// ListWithStartTime only lists operations that started within the range.
+func ListWithStartTime(f *pb.TimeRange) ListOperationOption {
+	return func(opts *listOperationsOptions) {
+		opts.StartTime = f
+	}
+}
+
+// ListWithPage only lists a single page of results. See ListPage.
+func ListWithPage(f *ListPage) ListOperationOption {
+	return func(opts *listOperationsOptions) {
+		opts.Page = f
+	}
+}
+
+// searchOperationsOptions are the options for Search calls on operations.
+// At least one of Labels, ArtifactDigest or ArtifactIds must be set so that
+// the records can be found with an index.
+type searchOperationsOptions struct {
+	Project     string
+	Application *pb.Ref_Application
+	Workspace   *pb.Ref_Workspace
+	Labels      map[string]string
+	Limit       uint32
+
+	// ArtifactDigest matches builds and pushed artifacts, and ArtifactIds
+	// matches the deployments of the pushed artifacts.
+	ArtifactDigest string
+	ArtifactIds    []string
+}
+
+func buildSearchOperationsOptions(req *pb.SearchOperationsRequest) *searchOperationsOptions {
+	opts := &searchOperationsOptions{
+		Application:    req.Application,
+		Workspace:      req.Workspace,
+		Labels:         req.Labels,
+		Limit:          req.Limit,
+		ArtifactDigest: req.ArtifactDigest,
+	}
+	if req.Project != nil {
+		opts.Project = req.Project.Project
+	}
+
+	return opts
+}
+
+// match returns true if the record matches all the options.
+func (opts *searchOperationsOptions) match(rec *operationIndexRecord) bool {
+	if opts.Application != nil {
+		if !strings.EqualFold(rec.Project, opts.Application.Project) ||
+			!strings.EqualFold(rec.App, opts.Application.Application) {
+			return false
+		}
+	} else if opts.Project != "" && !strings.EqualFold(rec.Project, opts.Project) {
+		return false
+	}
+
+	if opts.Workspace != nil && !strings.EqualFold(rec.Workspace, opts.Workspace.Workspace) {
+		return false
+	}
+
+	if opts.ArtifactDigest != "" && !strings.EqualFold(rec.ArtifactDigest, opts.ArtifactDigest) {
+		return false
+	}
+
+	for k, v := range opts.Labels {
+		if actual, ok := rec.Labels[k]; !ok || actual != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// timeRangeMatch returns true if t is within the range. All times are
+// within a nil range.
+func timeRangeMatch(r *pb.TimeRange, t time.Time) bool {
+	if r == nil {
+		return true
+	}
+
+	if r.After != nil {
+		after, err := ptypes.Timestamp(r.After)
+		if err == nil && t.Before(after) {
+			return false
+		}
+	}
+
+	if r.Before != nil {
+		before, err := ptypes.Timestamp(r.Before)
+		if err == nil && !t.Before(before) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // statusFilterMatch is a helper that compares a pb.Status to a set of
 // StatusFilters. This returns true if the filters match.
 func statusFilterMatch(
@@ -9,6 +9,8 @@ import (
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
@@ -183,6 +185,46 @@ func TestAppOperation(t *testing.T) {
 		require.Nil(b)
 	})
 
+	t.Run("latest with labels", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		ref := &pb.Ref_Application{
+			Application: "a_test",
+			Project:     "p_test",
+		}
+
+		// The arm64 build completes before the amd64 build
+		for i, arch := range []string{"arm64", "amd64"} {
+			pt, err := ptypes.TimestampProto(time.Now().Add(time.Duration(i) * time.Hour))
+			require.NoError(err)
+
+			require.NoError(op.Put(s, false, serverptypes.TestValidBuild(t, &pb.Build{
+				Id:          arch,
+				Application: ref,
+				Labels:      map[string]string{"waypoint/matrix/arch": arch},
+				Status: &pb.Status{
+					State:        pb.Status_SUCCESS,
+					StartTime:    pt,
+					CompleteTime: pt,
+				},
+			})))
+		}
+
+		raw, err := op.LatestLabels(s, ref, nil, map[string]string{"waypoint/matrix/arch": "arm64"})
+		require.NoError(err)
+		require.Equal("arm64", raw.(*pb.Build).Id)
+
+		raw, err = op.LatestLabels(s, ref, nil, nil)
+		require.NoError(err)
+		require.Equal("amd64", raw.(*pb.Build).Id)
+
+		_, err = op.LatestLabels(s, ref, nil, map[string]string{"waypoint/matrix/arch": "s390x"})
+		require.Error(err)
+	})
+
 	t.Run("list without application returns error", func(t *testing.T) {
 		require := require.New(t)
 
@@ -266,6 +308,69 @@ func TestAppOperation(t *testing.T) {
 		require.Len(results, 2)
 	})
 
+	t.Run("list with pages and a time range", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// A started first, C last
+		now := time.Now()
+		for i, id := range []string{"A", "B", "C"} {
+			pt, err := ptypes.TimestampProto(now.Add(time.Duration(i) * time.Hour))
+			require.NoError(err)
+
+			require.NoError(op.Put(s, false, serverptypes.TestValidBuild(t, &pb.Build{
+				Id: id,
+				Status: &pb.Status{
+					State:     pb.Status_SUCCESS,
+					StartTime: pt,
+				},
+			})))
+		}
+
+		ref := serverptypes.TestValidBuild(t, nil).Application
+		list := func(page *ListPage, r *pb.TimeRange) []string {
+			results, err := op.List(s, &listOperationsOptions{
+				Application: ref,
+				Page:        page,
+				StartTime:   r,
+			})
+			require.NoError(err)
+
+			var ids []string
+			for _, result := range results {
+				ids = append(ids, result.(*pb.Build).Id)
+			}
+
+			return ids
+		}
+
+		// Pages are newest first
+		page := &ListPage{Size: 2}
+		require.Equal([]string{"C", "B"}, list(page, nil))
+		require.Equal("B", page.Next)
+
+		page.Token = page.Next
+		require.Equal([]string{"A"}, list(page, nil))
+		require.Empty(page.Next)
+
+		// Time range
+		after, err := ptypes.TimestampProto(now.Add(30 * time.Minute))
+		require.NoError(err)
+		before, err := ptypes.TimestampProto(now.Add(2 * time.Hour))
+		require.NoError(err)
+		require.Equal([]string{"B"}, list(nil, &pb.TimeRange{After: after, Before: before}))
+
+		// Unknown page tokens are an error
+		_, err = op.List(s, &listOperationsOptions{
+			Application: ref,
+			Page:        &ListPage{Size: 2, Token: "nope"},
+		})
+		require.Error(err)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
 	t.Run("list by workspace specified", func(t *testing.T) {
 		require := require.New(t)
 
@@ -1,7 +1,6 @@
 package state
 
 import (
-	"github.com/boltdb/bolt"
 	"github.com/hashicorp/go-memdb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -15,7 +14,7 @@ func (s *State) AppPut(app *pb.Application) (*pb.Application, error) {
 	memTxn := s.inmem.Txn(true)
 	defer memTxn.Abort()
 
-	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+	err := s.db.Update(func(dbTxn Tx) error {
 		return s.appPut(dbTxn, memTxn, app)
 	})
 	if err == nil {
@@ -31,7 +30,7 @@ func (s *State) AppDelete(ref *pb.Ref_Application) error {
 	memTxn := s.inmem.Txn(true)
 	defer memTxn.Abort()
 
-	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+	err := s.db.Update(func(dbTxn Tx) error {
 		return s.appDelete(dbTxn, memTxn, ref)
 	})
 	if err == nil {
@@ -47,7 +46,7 @@ func (s *State) AppGet(ref *pb.Ref_Application) (*pb.Application, error) {
 	defer memTxn.Abort()
 
 	var result *pb.Application
-	err := s.db.View(func(dbTxn *bolt.Tx) error {
+	err := s.db.View(func(dbTxn Tx) error {
 		var err error
 		result, err = s.appGet(dbTxn, memTxn, ref)
 		return err
@@ -57,7 +56,7 @@ func (s *State) AppGet(ref *pb.Ref_Application) (*pb.Application, error) {
 }
 
 func (s *State) appPut(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	value *pb.Application,
 ) error {
@@ -83,7 +82,7 @@ func (s *State) appPut(
 }
 
 func (s *State) appDelete(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	ref *pb.Ref_Application,
 ) error {
@@ -112,7 +111,7 @@ func (s *State) appDelete(
 }
 
 func (s *State) appGet(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	ref *pb.Ref_Application,
 ) (*pb.Application, error) {
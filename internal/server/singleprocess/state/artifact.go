@@ -1,6 +1,9 @@
 package state
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+
 	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
@@ -45,15 +48,46 @@ func (s *State) ArtifactList(
 	return result, nil
 }
 
+// ArtifactSearch finds the artifacts that match the request. See
+// SearchOperationsRequest.
+func (s *State) ArtifactSearch(req *pb.SearchOperationsRequest) ([]*pb.PushedArtifact, error) {
+	raw, err := artifactOp.Search(s, buildSearchOperationsOptions(req))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*pb.PushedArtifact, len(raw))
+	for i, v := range raw {
+		result[i] = v.(*pb.PushedArtifact)
+	}
+
+	return result, nil
+}
+
 // ArtifactLatest gets the latest artifact that was completed successfully.
+// If labels is set, the artifact must have all of them.
 func (s *State) ArtifactLatest(
 	ref *pb.Ref_Application,
 	ws *pb.Ref_Workspace,
+	labels map[string]string,
 ) (*pb.PushedArtifact, error) {
-	result, err := artifactOp.Latest(s, ref, ws)
+	result, err := artifactOp.LatestLabels(s, ref, ws, labels)
 	if err != nil {
 		return nil, err
 	}
 
 	return result.(*pb.PushedArtifact), nil
 }
+
+// ArtifactDigest returns the SHA-256 digest of the artifact encoded by its
+// plugin, or an empty string if there is none. Operations can be searched
+// by this digest.
+func ArtifactDigest(a *pb.Artifact) string {
+	value := a.GetArtifact()
+	if value == nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(value.Value)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,83 @@
+package state
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var auditEventBucket = []byte("audit_event")
+
+func init() {
+	dbBuckets = append(dbBuckets, auditEventBucket)
+}
+
+// AuditEventPut appends an event to the audit log. The ID of the event must
+// sort after all existing events. Events can't be updated or deleted.
+func (s *State) AuditEventPut(e *pb.AuditEvent) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(auditEventBucket)
+		if b.Get([]byte(e.Id)) != nil {
+			return status.Errorf(codes.AlreadyExists,
+				"audit event already exists: %s", e.Id)
+		}
+
+		return dbPut(b, []byte(e.Id), e)
+	})
+}
+
+// AuditEventList returns up to limit events older than the event with the
+// ID before, newest first. If before is empty the newest events are
+// returned. If project isn't empty, only the events for that project are
+// returned.
+//
+// The returned string is the value of before for the next page, or empty
+// if there are no more events.
+func (s *State) AuditEventList(project, before string, limit int) ([]*pb.AuditEvent, string, error) {
+	// Events are stored oldest first so we keep the last limit events we
+	// see, noting if any older events were dropped.
+	var result []*pb.AuditEvent
+	var more bool
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbTxn.Bucket(auditEventBucket).ForEach(func(k, v []byte) error {
+			if before != "" && string(k) >= before {
+				return nil
+			}
+
+			var e pb.AuditEvent
+			if err := proto.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if project != "" && !strings.EqualFold(e.Project, project) {
+				return nil
+			}
+
+			result = append(result, &e)
+			if len(result) > limit {
+				result = result[1:]
+				more = true
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Newest first
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	var next string
+	if more && len(result) > 0 {
+		next = result[len(result)-1].Id
+	}
+
+	return result, next, nil
+}
@@ -0,0 +1,69 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestAuditEvent(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Empty
+	list, next, err := s.AuditEventList("", "", 10)
+	require.NoError(err)
+	require.Empty(list)
+	require.Empty(next)
+
+	for i := 0; i < 5; i++ {
+		project := "foo"
+		if i%2 == 1 {
+			project = "bar"
+		}
+
+		require.NoError(s.AuditEventPut(&pb.AuditEvent{
+			Id:       fmt.Sprintf("E%d", i),
+			Endpoint: "QueueJob",
+			Project:  project,
+		}))
+	}
+
+	// Events can't be replaced
+	err = s.AuditEventPut(&pb.AuditEvent{Id: "E0"})
+	require.Equal(codes.AlreadyExists, status.Code(err))
+
+	// Newest first, in pages
+	list, next, err = s.AuditEventList("", "", 2)
+	require.NoError(err)
+	require.Len(list, 2)
+	require.Equal("E4", list[0].Id)
+	require.Equal("E3", list[1].Id)
+	require.Equal("E3", next)
+
+	list, next, err = s.AuditEventList("", next, 2)
+	require.NoError(err)
+	require.Len(list, 2)
+	require.Equal("E2", list[0].Id)
+	require.Equal("E1", list[1].Id)
+
+	list, next, err = s.AuditEventList("", next, 2)
+	require.NoError(err)
+	require.Len(list, 1)
+	require.Equal("E0", list[0].Id)
+	require.Empty(next)
+
+	// Filtered by project
+	list, next, err = s.AuditEventList("FOO", "", 10)
+	require.NoError(err)
+	require.Len(list, 3)
+	require.Equal("E4", list[0].Id)
+	require.Empty(next)
+}
@@ -45,6 +45,22 @@ func (s *State) BuildList(
 	return result, nil
 }
 
+// BuildSearch finds the builds that match the request. See
+// SearchOperationsRequest.
+func (s *State) BuildSearch(req *pb.SearchOperationsRequest) ([]*pb.Build, error) {
+	raw, err := buildOp.Search(s, buildSearchOperationsOptions(req))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*pb.Build, len(raw))
+	for i, v := range raw {
+		result[i] = v.(*pb.Build)
+	}
+
+	return result, nil
+}
+
 // BuildLatest gets the latest build that was completed successfully.
 func (s *State) BuildLatest(
 	ref *pb.Ref_Application,
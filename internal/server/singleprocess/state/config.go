@@ -3,8 +3,9 @@ package state
 import (
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 
-	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-memdb"
 
@@ -16,6 +17,7 @@ var configBucket = []byte("config")
 
 func init() {
 	dbBuckets = append(dbBuckets, configBucket)
+	encryptedBuckets = append(encryptedBuckets, configBucket)
 	dbIndexers = append(dbIndexers, (*State).configIndexInit)
 	schemas = append(schemas, configIndexSchema)
 }
@@ -25,7 +27,7 @@ func (s *State) ConfigSet(vs ...*pb.ConfigVar) error {
 	memTxn := s.inmem.Txn(true)
 	defer memTxn.Abort()
 
-	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+	err := s.db.Update(func(dbTxn Tx) error {
 		for _, v := range vs {
 			if err := s.configSet(dbTxn, memTxn, v); err != nil {
 				return err
@@ -53,7 +55,7 @@ func (s *State) ConfigGetWatch(req *pb.ConfigGetRequest, ws memdb.WatchSet) ([]*
 	defer memTxn.Abort()
 
 	var result []*pb.ConfigVar
-	err := s.db.View(func(dbTxn *bolt.Tx) error {
+	err := s.db.View(func(dbTxn Tx) error {
 		var err error
 		result, err = s.configGetMerged(dbTxn, memTxn, ws, req)
 		return err
@@ -63,15 +65,16 @@ func (s *State) ConfigGetWatch(req *pb.ConfigGetRequest, ws memdb.WatchSet) ([]*
 }
 
 func (s *State) configSet(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	value *pb.ConfigVar,
 ) error {
 	id := s.configVarId(value)
 
-	// Get the global bucket and write the value to it.
+	// Get the global bucket and write the value to it. Dynamic variables
+	// have no value, so they aren't deleted.
 	b := dbTxn.Bucket(configBucket)
-	if value.Value == "" {
+	if value.Value == "" && value.Dynamic == nil {
 		if err := b.Delete(id); err != nil {
 			return err
 		}
@@ -86,16 +89,34 @@ func (s *State) configSet(
 }
 
 func (s *State) configGetMerged(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	ws memdb.WatchSet,
 	req *pb.ConfigGetRequest,
 ) ([]*pb.ConfigVar, error) {
+	// If we're not resolving the variables for a deployment, we return all
+	// of them, including the variants for workspaces and labels.
+	resolve := req.Workspace != nil || len(req.Labels) > 0
+
 	var mergeSet [][]*pb.ConfigVar
 	switch scope := req.Scope.(type) {
 	case *pb.ConfigGetRequest_Project:
 		// For project scope, we just return the project scoped values.
-		return s.configGetExact(dbTxn, memTxn, ws, scope.Project, req.Prefix)
+		vars, err := s.configGetExact(dbTxn, memTxn, ws, scope.Project, req.Prefix)
+		if err != nil || !resolve {
+			return vars, err
+		}
+
+		// When resolving, only the variables of the project itself apply
+		// since we don't know the application.
+		var projectVars []*pb.ConfigVar
+		for _, v := range vars {
+			if _, ok := v.Scope.(*pb.ConfigVar_Project); ok {
+				projectVars = append(projectVars, v)
+			}
+		}
+
+		mergeSet = append(mergeSet, projectVars)
 
 	case *pb.ConfigGetRequest_Application:
 		// Application scope, we have to get the project scope first
@@ -126,11 +147,30 @@ func (s *State) configGetMerged(
 		panic("unknown scope")
 	}
 
-	// Merge our merge set
+	// Merge our merge set. Later sets take precedence, and within a set the
+	// more specific variable of the same name wins.
 	merged := make(map[string]*pb.ConfigVar)
-	for _, set := range mergeSet {
+	ranks := make(map[string]int)
+	for level, set := range mergeSet {
 		for _, v := range set {
-			merged[v.Name] = v
+			key := v.Name
+			if !resolve {
+				key += string(configVarSelector(v))
+				merged[key] = v
+				continue
+			}
+
+			if !configVarMatches(v, req) {
+				continue
+			}
+
+			rank := level*1000 + configVarRank(v)
+			if current, ok := ranks[key]; ok && current > rank {
+				continue
+			}
+
+			merged[key] = v
+			ranks[key] = rank
 		}
 	}
 
@@ -149,7 +189,7 @@ func (s *State) configGetMerged(
 // app-scoped variables, you'll get app-scoped variables. If a project-scoped
 // variable matches, it will not be merged in.
 func (s *State) configGetExact(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	ws memdb.WatchSet,
 	ref interface{}, // should be one of the *pb.Ref_ values.
@@ -214,7 +254,7 @@ func (s *State) configGetExact(
 
 // configGetRunner gets the config vars for a runner.
 func (s *State) configGetRunner(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	ws memdb.WatchSet,
 	req *pb.Ref_RunnerId,
@@ -306,7 +346,7 @@ func (s *State) configIndexSet(txn *memdb.Txn, id []byte, value *pb.ConfigVar) e
 	}
 
 	// If we have no value, we delete from the memdb index
-	if value.Value == "" {
+	if value.Value == "" && value.Dynamic == nil {
 		return txn.Delete(configIndexTableName, record)
 	}
 
@@ -315,7 +355,7 @@ func (s *State) configIndexSet(txn *memdb.Txn, id []byte, value *pb.ConfigVar) e
 }
 
 // configIndexInit initializes the config index from persisted data.
-func (s *State) configIndexInit(dbTxn *bolt.Tx, memTxn *memdb.Txn) error {
+func (s *State) configIndexInit(dbTxn Tx, memTxn *memdb.Txn) error {
 	bucket := dbTxn.Bucket(configBucket)
 	return bucket.ForEach(func(k, v []byte) error {
 		var value pb.ConfigVar
@@ -333,17 +373,19 @@ func (s *State) configIndexInit(dbTxn *bolt.Tx, memTxn *memdb.Txn) error {
 func (s *State) configVarId(v *pb.ConfigVar) []byte {
 	switch scope := v.Scope.(type) {
 	case *pb.ConfigVar_Application:
-		return []byte(fmt.Sprintf("%s/%s/%s",
+		return []byte(fmt.Sprintf("%s/%s/%s%s",
 			scope.Application.Project,
 			scope.Application.Application,
 			v.Name,
+			configVarSelector(v),
 		))
 
 	case *pb.ConfigVar_Project:
-		return []byte(fmt.Sprintf("%s/%s/%s",
+		return []byte(fmt.Sprintf("%s/%s/%s%s",
 			scope.Project.Project,
 			"",
 			v.Name,
+			configVarSelector(v),
 		))
 
 	case *pb.ConfigVar_Runner:
@@ -366,6 +408,70 @@ func (s *State) configVarId(v *pb.ConfigVar) []byte {
 	}
 }
 
+// configVarSelector returns the suffix of the ID for the workspace and
+// labels of a variable. This is empty for variables without them so that
+// they keep the IDs they had before variables could be scoped. Each field
+// is prefixed with its length, so values that contain the separators
+// can't produce the ID of a different selector.
+func configVarSelector(v *pb.ConfigVar) string {
+	var result string
+	if v.Workspace != nil {
+		result += "/ws:" + configVarSelectorField(strings.ToLower(v.Workspace.Workspace))
+	}
+
+	if len(v.Labels) > 0 {
+		keys := make([]string, 0, len(v.Labels))
+		for k := range v.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		result += "/labels:"
+		for _, k := range keys {
+			result += configVarSelectorField(k) + configVarSelectorField(v.Labels[k])
+		}
+	}
+
+	return result
+}
+
+// configVarSelectorField returns a field of the selector prefixed with its
+// length.
+func configVarSelectorField(v string) string {
+	return strconv.Itoa(len(v)) + ":" + v
+}
+
+// configVarMatches returns true if the variable applies to the workspace
+// and labels of the request.
+func configVarMatches(v *pb.ConfigVar, req *pb.ConfigGetRequest) bool {
+	if v.Workspace != nil {
+		if req.Workspace == nil ||
+			!strings.EqualFold(v.Workspace.Workspace, req.Workspace.Workspace) {
+			return false
+		}
+	}
+
+	for k, value := range v.Labels {
+		if current, ok := req.Labels[k]; !ok || current != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// configVarRank returns how specific a matching variable is within its
+// scope. Variables for a workspace are more specific than any variable
+// without one, and then variables with more labels are more specific.
+func configVarRank(v *pb.ConfigVar) int {
+	rank := len(v.Labels)
+	if v.Workspace != nil {
+		rank += 100
+	}
+
+	return rank
+}
+
 func configIndexSchema() *memdb.TableSchema {
 	return &memdb.TableSchema{
 		Name: configIndexTableName,
@@ -0,0 +1,163 @@
+package state
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/go-memdb"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var configSourceBucket = []byte("config-source")
+
+func init() {
+	dbBuckets = append(dbBuckets, configSourceBucket)
+	encryptedBuckets = append(encryptedBuckets, configSourceBucket)
+	dbIndexers = append(dbIndexers, (*State).configSourceIndexInit)
+	schemas = append(schemas, configSourceIndexSchema)
+}
+
+// ConfigSourceSet writes the configuration of a config source. If Delete
+// is set, the configuration is removed instead.
+func (s *State) ConfigSourceSet(v *pb.ConfigSource) error {
+	memTxn := s.inmem.Txn(true)
+	defer memTxn.Abort()
+
+	err := s.db.Update(func(dbTxn Tx) error {
+		return s.configSourceSet(dbTxn, memTxn, v)
+	})
+	if err == nil {
+		memTxn.Commit()
+	}
+
+	return err
+}
+
+// ConfigSourceGet gets the configuration of the sources for the request.
+func (s *State) ConfigSourceGet(req *pb.GetConfigSourceRequest) ([]*pb.ConfigSource, error) {
+	return s.ConfigSourceGetWatch(req, nil)
+}
+
+// ConfigSourceGetWatch gets the configuration of the sources for the
+// request. If a non-nil WatchSet is given, this can be watched for
+// changes to the sources.
+func (s *State) ConfigSourceGetWatch(
+	req *pb.GetConfigSourceRequest,
+	ws memdb.WatchSet,
+) ([]*pb.ConfigSource, error) {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	var iter memdb.ResultIterator
+	var err error
+	if req.Type == "" {
+		iter, err = memTxn.Get(configSourceIndexTableName, configSourceIndexIdIndexName+"_prefix", "")
+	} else {
+		iter, err = memTxn.Get(configSourceIndexTableName, configSourceIndexIdIndexName, req.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Add to our watchset
+	ws.Add(iter.WatchCh())
+
+	var result []*pb.ConfigSource
+	for {
+		current := iter.Next()
+		if current == nil {
+			break
+		}
+
+		result = append(result, current.(*configSourceIndexRecord).Source)
+	}
+
+	return result, nil
+}
+
+func (s *State) configSourceSet(
+	dbTxn Tx,
+	memTxn *memdb.Txn,
+	value *pb.ConfigSource,
+) error {
+	id := configSourceId(value.Type)
+
+	// Get the global bucket and write the value to it.
+	b := dbTxn.Bucket(configSourceBucket)
+	if value.Delete {
+		if err := b.Delete(id); err != nil {
+			return err
+		}
+	} else {
+		if err := dbPut(b, id, value); err != nil {
+			return err
+		}
+	}
+
+	// Create our index value and write that.
+	return s.configSourceIndexSet(memTxn, id, value)
+}
+
+// configSourceIndexSet writes an index record for a config source.
+func (s *State) configSourceIndexSet(txn *memdb.Txn, id []byte, value *pb.ConfigSource) error {
+	record := &configSourceIndexRecord{
+		Id:     string(id),
+		Source: value,
+	}
+
+	// If we're deleting, we delete from the memdb index. This doesn't
+	// fail if the source wasn't configured.
+	if value.Delete {
+		_, err := txn.DeleteAll(configSourceIndexTableName, configSourceIndexIdIndexName, record.Id)
+		return err
+	}
+
+	// Insert the index
+	return txn.Insert(configSourceIndexTableName, record)
+}
+
+// configSourceIndexInit initializes the config source index from
+// persisted data.
+func (s *State) configSourceIndexInit(dbTxn Tx, memTxn *memdb.Txn) error {
+	bucket := dbTxn.Bucket(configSourceBucket)
+	return bucket.ForEach(func(k, v []byte) error {
+		var value pb.ConfigSource
+		if err := proto.Unmarshal(v, &value); err != nil {
+			return err
+		}
+
+		return s.configSourceIndexSet(memTxn, k, &value)
+	})
+}
+
+func configSourceId(typ string) []byte {
+	return []byte(strings.ToLower(typ))
+}
+
+func configSourceIndexSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: configSourceIndexTableName,
+		Indexes: map[string]*memdb.IndexSchema{
+			configSourceIndexIdIndexName: &memdb.IndexSchema{
+				Name:         configSourceIndexIdIndexName,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field:     "Id",
+					Lowercase: true,
+				},
+			},
+		},
+	}
+}
+
+const (
+	configSourceIndexTableName   = "config-source-index"
+	configSourceIndexIdIndexName = "id"
+)
+
+type configSourceIndexRecord struct {
+	Id     string
+	Source *pb.ConfigSource
+}
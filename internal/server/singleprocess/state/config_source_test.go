@@ -0,0 +1,61 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestConfigSource(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Get it with watch
+	ws := memdb.NewWatchSet()
+	vs, err := s.ConfigSourceGetWatch(&pb.GetConfigSourceRequest{}, ws)
+	require.NoError(err)
+	require.Empty(vs)
+
+	// Watch should block
+	require.True(ws.Watch(time.After(10 * time.Millisecond)))
+
+	// Set some sources
+	require.NoError(s.ConfigSourceSet(&pb.ConfigSource{
+		Type:   "vault",
+		Config: map[string]string{"addr": "https://vault.example.com"},
+	}))
+	require.NoError(s.ConfigSourceSet(&pb.ConfigSource{Type: "aws-ssm"}))
+	require.False(ws.Watch(time.After(100 * time.Millisecond)))
+
+	{
+		// Get all of them
+		vs, err := s.ConfigSourceGet(&pb.GetConfigSourceRequest{})
+		require.NoError(err)
+		require.Len(vs, 2)
+	}
+
+	{
+		// Get one by type, which is case insensitive
+		vs, err := s.ConfigSourceGet(&pb.GetConfigSourceRequest{Type: "Vault"})
+		require.NoError(err)
+		require.Len(vs, 1)
+		require.Equal("https://vault.example.com", vs[0].Config["addr"])
+	}
+
+	// Delete one, which is also fine if it doesn't exist
+	require.NoError(s.ConfigSourceSet(&pb.ConfigSource{Type: "vault", Delete: true}))
+	require.NoError(s.ConfigSourceSet(&pb.ConfigSource{Type: "nope", Delete: true}))
+
+	{
+		vs, err := s.ConfigSourceGet(&pb.GetConfigSourceRequest{})
+		require.NoError(err)
+		require.Len(vs, 1)
+		require.Equal("aws-ssm", vs[0].Type)
+	}
+}
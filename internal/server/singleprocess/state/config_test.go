@@ -144,6 +144,134 @@ func TestConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("workspaces and labels", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		project := &pb.ConfigVar_Project{Project: &pb.Ref_Project{Project: "foo"}}
+		app := &pb.ConfigVar_Application{Application: &pb.Ref_Application{
+			Project:     "foo",
+			Application: "bar",
+		}}
+		require.NoError(s.ConfigSet(
+			&pb.ConfigVar{Scope: project, Name: "hello", Value: "project"},
+			&pb.ConfigVar{
+				Scope:     project,
+				Name:      "hello",
+				Value:     "project-prod",
+				Workspace: &pb.Ref_Workspace{Workspace: "prod"},
+			},
+			&pb.ConfigVar{
+				Scope:  app,
+				Name:   "hello",
+				Value:  "app-canary",
+				Labels: map[string]string{"canary": "true"},
+			},
+			&pb.ConfigVar{
+				Scope:  app,
+				Name:   "region",
+				Value:  "eu",
+				Labels: map[string]string{"canary": "true", "env": "eu"},
+			},
+		))
+
+		get := func(ws string, labels map[string]string) map[string]string {
+			req := &pb.ConfigGetRequest{
+				Scope: &pb.ConfigGetRequest_Application{
+					Application: app.Application,
+				},
+				Labels: labels,
+			}
+			if ws != "" {
+				req.Workspace = &pb.Ref_Workspace{Workspace: ws}
+			}
+
+			vs, err := s.ConfigGet(req)
+			require.NoError(err)
+
+			result := map[string]string{}
+			for _, v := range vs {
+				_, ok := result[v.Name]
+				require.False(ok, "duplicate variable %s", v.Name)
+				result[v.Name] = v.Value
+			}
+
+			return result
+		}
+
+		// Without selectors, all the variants are returned
+		vs, err := s.ConfigGet(&pb.ConfigGetRequest{
+			Scope: &pb.ConfigGetRequest_Application{Application: app.Application},
+		})
+		require.NoError(err)
+		require.Len(vs, 4)
+
+		// The workspace variable applies only to its workspace
+		require.Equal(map[string]string{"hello": "project"}, get("default", nil))
+		require.Equal(map[string]string{"hello": "project-prod"}, get("PROD", nil))
+
+		// Application variables take precedence over project variables
+		require.Equal(map[string]string{"hello": "app-canary"},
+			get("prod", map[string]string{"canary": "true"}))
+
+		// All of the labels must match
+		require.Equal(map[string]string{"hello": "app-canary", "region": "eu"},
+			get("prod", map[string]string{"canary": "true", "env": "eu", "x": "y"}))
+		require.Equal(map[string]string{"hello": "project"},
+			get("default", map[string]string{"env": "eu"}))
+
+		// Project scope only resolves project variables
+		vs, err = s.ConfigGet(&pb.ConfigGetRequest{
+			Scope:     &pb.ConfigGetRequest_Project{Project: project.Project},
+			Workspace: &pb.Ref_Workspace{Workspace: "prod"},
+			Labels:    map[string]string{"canary": "true"},
+		})
+		require.NoError(err)
+		require.Len(vs, 1)
+		require.Equal("project-prod", vs[0].Value)
+
+		// Deleting a variant leaves the others
+		require.NoError(s.ConfigSet(&pb.ConfigVar{
+			Scope:     project,
+			Name:      "hello",
+			Workspace: &pb.Ref_Workspace{Workspace: "prod"},
+		}))
+		require.Equal(map[string]string{"hello": "project"}, get("prod", nil))
+	})
+
+	t.Run("labels with separators", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// These labels would have the same ID if the separators in the
+		// values weren't accounted for.
+		project := &pb.ConfigVar_Project{Project: &pb.Ref_Project{Project: "foo"}}
+		require.NoError(s.ConfigSet(
+			&pb.ConfigVar{
+				Scope:  project,
+				Name:   "hello",
+				Value:  "one",
+				Labels: map[string]string{"a": "1,b=2"},
+			},
+			&pb.ConfigVar{
+				Scope:  project,
+				Name:   "hello",
+				Value:  "two",
+				Labels: map[string]string{"a": "1", "b": "2"},
+			},
+		))
+
+		vs, err := s.ConfigGet(&pb.ConfigGetRequest{
+			Scope: &pb.ConfigGetRequest_Project{Project: project.Project},
+		})
+		require.NoError(err)
+		require.Len(vs, 2)
+	})
+
 	t.Run("delete", func(t *testing.T) {
 		require := require.New(t)
 
@@ -4,7 +4,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -38,8 +37,8 @@ func init() {
 // dbInit sets up the database. This should be called once on all new
 // DB handles before accepting API calls. It is safe to be called multiple
 // times.
-func dbInit(db *bolt.DB) error {
-	return db.Update(func(tx *bolt.Tx) error {
+func dbInit(db DB) error {
+	return db.Update(func(tx Tx) error {
 		// Create all our buckets
 		for _, b := range dbBuckets {
 			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
@@ -86,7 +85,7 @@ On-disk data version: %d
 // dbPut is a helper to insert a proto.Message into a bucket for the given id.
 // Any errors are automatically wrapped into a gRPC status error so they can
 // be sent directly back.
-func dbPut(b *bolt.Bucket, id []byte, msg proto.Message) error {
+func dbPut(b Bucket, id []byte, msg proto.Message) error {
 	enc, err := proto.Marshal(msg)
 	if err != nil {
 		return status.Errorf(codes.Internal, "failed to encode data: %s", err)
@@ -101,7 +100,7 @@ func dbPut(b *bolt.Bucket, id []byte, msg proto.Message) error {
 
 // dbGet is a helper to get a single proto.Message from a bucket. Errors
 // are guaranteed to be in gRPC status format.
-func dbGet(b *bolt.Bucket, id []byte, msg proto.Message) error {
+func dbGet(b Bucket, id []byte, msg proto.Message) error {
 	raw := b.Get(id)
 	if raw == nil {
 		return status.Errorf(codes.NotFound, "record not found for ID: %s", id)
@@ -117,7 +116,7 @@ func dbGet(b *bolt.Bucket, id []byte, msg proto.Message) error {
 // dbUpsert is a helper to upsert a message. The update boolean will cause
 // this to error if the ID is not found. This reflects our API behavior for
 // upserts so that we don't let the end user pick any ID.
-func dbUpsert(b *bolt.Bucket, update bool, id []byte, msg proto.Message) error {
+func dbUpsert(b Bucket, update bool, id []byte, msg proto.Message) error {
 	// If we're updating, the ID must exist
 	if update && b.Get([]byte(id)) == nil {
 		return status.Errorf(codes.NotFound, "record not found for ID: %s", id)
@@ -45,6 +45,45 @@ func (s *State) DeploymentList(
 	return result, nil
 }
 
+// DeploymentSearch finds the deployments that match the request. See
+// SearchOperationsRequest.
+func (s *State) DeploymentSearch(req *pb.SearchOperationsRequest) ([]*pb.Deployment, error) {
+	opts := buildSearchOperationsOptions(req)
+
+	// Deployments don't have the digest of their artifact, so we find the
+	// pushed artifacts with the digest first and then their deployments.
+	if opts.ArtifactDigest != "" {
+		artifacts, err := artifactOp.Search(s, &searchOperationsOptions{
+			Project:        opts.Project,
+			Application:    opts.Application,
+			ArtifactDigest: opts.ArtifactDigest,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(artifacts) == 0 {
+			return nil, nil
+		}
+
+		for _, v := range artifacts {
+			opts.ArtifactIds = append(opts.ArtifactIds, v.(*pb.PushedArtifact).Id)
+		}
+		opts.ArtifactDigest = ""
+	}
+
+	raw, err := deploymentOp.Search(s, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*pb.Deployment, len(raw))
+	for i, v := range raw {
+		result[i] = v.(*pb.Deployment)
+	}
+
+	return result, nil
+}
+
 // DeploymentLatest gets the latest deployment that was completed successfully.
 func (s *State) DeploymentLatest(
 	ref *pb.Ref_Application,
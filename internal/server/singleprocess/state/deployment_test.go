@@ -2,8 +2,107 @@ package state
 
 import (
 	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
 )
 
 func TestDeployment(t *testing.T) {
 	deploymentOp.Test(t)
+
+	t.Run("search", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Two artifacts with different digests
+		artifact := &pb.Artifact{Artifact: &any.Any{Value: []byte("A")}}
+		require.NoError(s.ArtifactPut(false, serverptypes.TestValidArtifact(t, &pb.PushedArtifact{
+			Id:       "A",
+			Artifact: artifact,
+		})))
+		require.NoError(s.ArtifactPut(false, serverptypes.TestValidArtifact(t, &pb.PushedArtifact{
+			Id:       "B",
+			Artifact: &pb.Artifact{Artifact: &any.Any{Value: []byte("B")}},
+		})))
+
+		// Deployments of them
+		require.NoError(s.DeploymentPut(false, serverptypes.TestValidDeployment(t, &pb.Deployment{
+			Id:         "1",
+			ArtifactId: "A",
+			Labels:     map[string]string{"waypoint/git-commit": "abc"},
+		})))
+		require.NoError(s.DeploymentPut(false, serverptypes.TestValidDeployment(t, &pb.Deployment{
+			Id:         "2",
+			ArtifactId: "B",
+			Labels:     map[string]string{"waypoint/git-commit": "abc"},
+		})))
+		require.NoError(s.DeploymentPut(false, serverptypes.TestValidDeployment(t, &pb.Deployment{
+			Id:         "3",
+			ArtifactId: "A",
+			Workspace:  &pb.Ref_Workspace{Workspace: "staging"},
+		})))
+
+		// By artifact digest
+		ds, err := s.DeploymentSearch(&pb.SearchOperationsRequest{
+			ArtifactDigest: ArtifactDigest(artifact),
+		})
+		require.NoError(err)
+		require.Len(ds, 2)
+
+		as, err := s.ArtifactSearch(&pb.SearchOperationsRequest{
+			ArtifactDigest: ArtifactDigest(artifact),
+		})
+		require.NoError(err)
+		require.Len(as, 1)
+		require.Equal("A", as[0].Id)
+
+		// By artifact digest and workspace
+		ds, err = s.DeploymentSearch(&pb.SearchOperationsRequest{
+			Workspace:      &pb.Ref_Workspace{Workspace: "staging"},
+			ArtifactDigest: ArtifactDigest(artifact),
+		})
+		require.NoError(err)
+		require.Len(ds, 1)
+		require.Equal("3", ds[0].Id)
+
+		// By labels
+		ds, err = s.DeploymentSearch(&pb.SearchOperationsRequest{
+			Labels: map[string]string{"waypoint/git-commit": "abc"},
+		})
+		require.NoError(err)
+		require.Len(ds, 2)
+
+		// By labels and artifact digest
+		ds, err = s.DeploymentSearch(&pb.SearchOperationsRequest{
+			ArtifactDigest: ArtifactDigest(artifact),
+			Labels:         map[string]string{"waypoint/git-commit": "abc"},
+		})
+		require.NoError(err)
+		require.Len(ds, 1)
+		require.Equal("1", ds[0].Id)
+
+		// In another project
+		ds, err = s.DeploymentSearch(&pb.SearchOperationsRequest{
+			Project: &pb.Ref_Project{Project: "other"},
+			Labels:  map[string]string{"waypoint/git-commit": "abc"},
+		})
+		require.NoError(err)
+		require.Empty(ds)
+
+		// Unknown digest
+		ds, err = s.DeploymentSearch(&pb.SearchOperationsRequest{
+			ArtifactDigest: "sha256:nope",
+		})
+		require.NoError(err)
+		require.Empty(ds)
+
+		// Without an index
+		_, err = s.DeploymentSearch(&pb.SearchOperationsRequest{})
+		require.Error(err)
+	})
 }
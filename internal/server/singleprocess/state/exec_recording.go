@@ -0,0 +1,121 @@
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var execRecordingBucket = []byte("exec_recording")
+
+func init() {
+	dbBuckets = append(dbBuckets, execRecordingBucket)
+}
+
+// ExecRecordingPut writes the recording of an exec session.
+func (s *State) ExecRecordingPut(r *pb.ExecRecording) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		return dbPut(dbTxn.Bucket(execRecordingBucket), []byte(r.Id), r)
+	})
+}
+
+// ExecRecordingGet gets the recording of an exec session by ID.
+func (s *State) ExecRecordingGet(id string) (*pb.ExecRecording, error) {
+	var result pb.ExecRecording
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbGet(dbTxn.Bucket(execRecordingBucket), []byte(id), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ExecRecordingList returns the recordings that match the request, newest
+// first. The events of the recordings aren't returned.
+func (s *State) ExecRecordingList(req *pb.ListExecRecordingsRequest) ([]*pb.ExecRecording, error) {
+	var result []*pb.ExecRecording
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbTxn.Bucket(execRecordingBucket).ForEach(func(k, v []byte) error {
+			var r pb.ExecRecording
+			if err := proto.Unmarshal(v, &r); err != nil {
+				return err
+			}
+
+			if ref := req.Application; ref != nil {
+				if r.Application == nil ||
+					!strings.EqualFold(r.Application.Project, ref.Project) ||
+					!strings.EqualFold(r.Application.Application, ref.Application) {
+					return nil
+				}
+			}
+			if ref := req.Workspace; ref != nil {
+				if r.Workspace == nil || !strings.EqualFold(r.Workspace.Workspace, ref.Workspace) {
+					return nil
+				}
+			}
+			if req.DeploymentId != "" && r.DeploymentId != req.DeploymentId {
+				return nil
+			}
+
+			r.Events = nil
+			result = append(result, &r)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Newest first
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result, nil
+}
+
+// ExecRecordingPrune deletes the recordings of the sessions that started
+// before the given time and returns how many were deleted.
+func (s *State) ExecRecordingPrune(before time.Time) (int, error) {
+	var count int
+	err := s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(execRecordingBucket)
+
+		// The bucket can't be changed while we iterate over it, so we
+		// collect the keys first.
+		var keys [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			var r pb.ExecRecording
+			if err := proto.Unmarshal(v, &r); err != nil {
+				return err
+			}
+
+			start, err := ptypes.Timestamp(r.StartTime)
+			if err != nil || start.Before(before) {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		count = len(keys)
+		return nil
+	})
+
+	return count, err
+}
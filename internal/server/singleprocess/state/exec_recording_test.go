@@ -0,0 +1,67 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestExecRecording(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	old, err := ptypes.TimestampProto(time.Now().Add(-48 * time.Hour))
+	require.NoError(err)
+	now := ptypes.TimestampNow()
+
+	app := &pb.Ref_Application{Project: "p", Application: "a"}
+	require.NoError(s.ExecRecordingPut(&pb.ExecRecording{
+		Id:           "1",
+		DeploymentId: "A",
+		Application:  app,
+		StartTime:    old,
+		Events: []*pb.ExecRecording_Event{
+			{Time: old, Channel: pb.ExecRecording_STDOUT, Data: []byte("hello")},
+		},
+	}))
+	require.NoError(s.ExecRecordingPut(&pb.ExecRecording{
+		Id:           "2",
+		DeploymentId: "B",
+		Application:  app,
+		StartTime:    now,
+	}))
+
+	// Get has the events
+	r, err := s.ExecRecordingGet("1")
+	require.NoError(err)
+	require.Len(r.Events, 1)
+
+	// List is newest first without events
+	list, err := s.ExecRecordingList(&pb.ListExecRecordingsRequest{Application: app})
+	require.NoError(err)
+	require.Len(list, 2)
+	require.Equal("2", list[0].Id)
+	require.Empty(list[1].Events)
+
+	list, err = s.ExecRecordingList(&pb.ListExecRecordingsRequest{DeploymentId: "A"})
+	require.NoError(err)
+	require.Len(list, 1)
+
+	// Prune the old recording
+	n, err := s.ExecRecordingPrune(time.Now().Add(-24 * time.Hour))
+	require.NoError(err)
+	require.Equal(1, n)
+
+	_, err = s.ExecRecordingGet("1")
+	require.Equal(codes.NotFound, status.Code(err))
+	_, err = s.ExecRecordingGet("2")
+	require.NoError(err)
+}
@@ -5,7 +5,6 @@ import (
 	"io"
 	"sync/atomic"
 
-	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-memdb"
 
@@ -16,6 +15,7 @@ var hmacKeyBucket = []byte("hmac_keys")
 
 func init() {
 	dbBuckets = append(dbBuckets, hmacKeyBucket)
+	encryptedBuckets = append(encryptedBuckets, hmacKeyBucket)
 	dbIndexers = append(dbIndexers, (*State).hmacKeyIndexInit)
 	schemas = append(schemas, hmacKeyIndexSchema)
 }
@@ -33,7 +33,7 @@ func (s *State) HMACKeyCreateIfNotExist(id string, size int) (*pb.HMACKey, error
 	defer memTxn.Abort()
 
 	var result *pb.HMACKey
-	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+	err := s.db.Update(func(dbTxn Tx) error {
 		var err error
 
 		// If we have this key already, then return that.
@@ -57,7 +57,7 @@ func (s *State) HMACKeyCreateIfNotExist(id string, size int) (*pb.HMACKey, error
 }
 
 func (s *State) hmacKeyCreate(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	id string,
 	size int,
@@ -92,7 +92,7 @@ func (s *State) HMACKeyGet(id string) (*pb.HMACKey, error) {
 }
 
 func (s *State) hmacKeyGet(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	id string,
 ) (*pb.HMACKey, error) {
@@ -129,7 +129,7 @@ func (s *State) hmacKeyIndexSet(txn *memdb.Txn, value *pb.HMACKey) error {
 }
 
 // hmacKeyIndexInit initializes the hmacKey index from persisted data.
-func (s *State) hmacKeyIndexInit(dbTxn *bolt.Tx, memTxn *memdb.Txn) error {
+func (s *State) hmacKeyIndexInit(dbTxn Tx, memTxn *memdb.Txn) error {
 	// Reset that we're empty. In practice this doesn't happen but
 	// for tests we might load the state store multiple times in-process.
 	atomic.StoreUint32(&s.hmacKeyNotEmpty, 0)
@@ -0,0 +1,127 @@
+package state
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// idempotencyBucket maps the idempotency keys of requests to the ID of
+// the record that the first request with the key created. Each value is
+// the creation time in Unix nanoseconds followed by the ID. The ID is
+// empty while the record is created, see IdempotencyKeyReserve.
+var idempotencyBucket = []byte("idempotency")
+
+// idempotencyReserveTTL is how long a key stays reserved if the record is
+// never recorded for it, such as when a server stops while it creates the
+// record. After that another request with the key can reserve it.
+var idempotencyReserveTTL = time.Minute
+
+func init() {
+	dbBuckets = append(dbBuckets, idempotencyBucket)
+}
+
+// IdempotencyKeyGet returns the ID of the record that was created with the
+// key, or an empty string if the key wasn't used.
+func (s *State) IdempotencyKeyGet(key string) (string, error) {
+	var id string
+	err := s.db.View(func(dbTxn Tx) error {
+		if v := dbTxn.Bucket(idempotencyBucket).Get([]byte(key)); len(v) > 8 {
+			id = string(v[8:])
+		}
+
+		return nil
+	})
+
+	return id, err
+}
+
+// IdempotencyKeyReserve reserves the key for a request that is about to
+// create a record. The reservation is a write to the DB, so only one
+// request reserves the key even if servers share the DB. If the key was
+// used, this returns the ID of its record. If another request has the key
+// reserved, the ID is empty and reserved is false.
+func (s *State) IdempotencyKeyReserve(key string) (id string, reserved bool, err error) {
+	now := time.Now()
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, uint64(now.UnixNano()))
+
+	err = s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(idempotencyBucket)
+		if existing := b.Get([]byte(key)); len(existing) >= 8 {
+			if len(existing) > 8 {
+				id = string(existing[8:])
+				return nil
+			}
+
+			created := time.Unix(0, int64(binary.BigEndian.Uint64(existing)))
+			if now.Sub(created) < idempotencyReserveTTL {
+				return nil
+			}
+		}
+
+		reserved = true
+		return b.Put([]byte(key), v)
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	return id, reserved, nil
+}
+
+// IdempotencyKeyRelease deletes a reservation of the key if the record
+// couldn't be created, so that a retry can create it.
+func (s *State) IdempotencyKeyRelease(key string) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(idempotencyBucket)
+		if v := b.Get([]byte(key)); len(v) != 8 {
+			return nil
+		}
+
+		return b.Delete([]byte(key))
+	})
+}
+
+// IdempotencyKeyPut records that the record with the ID was created with
+// the key.
+func (s *State) IdempotencyKeyPut(key, id string) error {
+	v := make([]byte, 8, 8+len(id))
+	binary.BigEndian.PutUint64(v, uint64(time.Now().UnixNano()))
+	v = append(v, id...)
+
+	return s.db.Update(func(dbTxn Tx) error {
+		return dbTxn.Bucket(idempotencyBucket).Put([]byte(key), v)
+	})
+}
+
+// IdempotencyKeyPrune deletes the keys that were recorded before the
+// given time and returns how many were deleted.
+func (s *State) IdempotencyKeyPrune(before time.Time) (int, error) {
+	var keys [][]byte
+	err := s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(idempotencyBucket)
+		err := b.ForEach(func(k, v []byte) error {
+			if len(v) < 8 || int64(binary.BigEndian.Uint64(v)) < before.UnixNano() {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(keys), nil
+}
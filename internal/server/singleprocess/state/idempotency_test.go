@@ -0,0 +1,84 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Unused keys have no ID
+	id, err := s.IdempotencyKeyGet("A")
+	require.NoError(err)
+	require.Empty(id)
+
+	require.NoError(s.IdempotencyKeyPut("A", "1"))
+	id, err = s.IdempotencyKeyGet("A")
+	require.NoError(err)
+	require.Equal("1", id)
+
+	// Only keys recorded before the time are pruned
+	n, err := s.IdempotencyKeyPrune(time.Now().Add(-time.Hour))
+	require.NoError(err)
+	require.Equal(0, n)
+
+	n, err = s.IdempotencyKeyPrune(time.Now().Add(time.Second))
+	require.NoError(err)
+	require.Equal(1, n)
+	id, err = s.IdempotencyKeyGet("A")
+	require.NoError(err)
+	require.Empty(id)
+}
+
+func TestIdempotencyKeyReserve(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// The first request reserves the key
+	id, reserved, err := s.IdempotencyKeyReserve("A")
+	require.NoError(err)
+	require.True(reserved)
+	require.Empty(id)
+
+	// Others wait for it
+	id, reserved, err = s.IdempotencyKeyReserve("A")
+	require.NoError(err)
+	require.False(reserved)
+	require.Empty(id)
+
+	// And then get its record
+	require.NoError(s.IdempotencyKeyPut("A", "1"))
+	id, reserved, err = s.IdempotencyKeyReserve("A")
+	require.NoError(err)
+	require.False(reserved)
+	require.Equal("1", id)
+
+	// Released keys can be reserved again, but not used ones
+	_, reserved, err = s.IdempotencyKeyReserve("B")
+	require.NoError(err)
+	require.True(reserved)
+	require.NoError(s.IdempotencyKeyRelease("B"))
+	_, reserved, err = s.IdempotencyKeyReserve("B")
+	require.NoError(err)
+	require.True(reserved)
+
+	require.NoError(s.IdempotencyKeyRelease("A"))
+	id, err = s.IdempotencyKeyGet("A")
+	require.NoError(err)
+	require.Equal("1", id)
+
+	// Reservations expire
+	defer func(v time.Duration) { idempotencyReserveTTL = v }(idempotencyReserveTTL)
+	idempotencyReserveTTL = 0
+	_, reserved, err = s.IdempotencyKeyReserve("B")
+	require.NoError(err)
+	require.True(reserved)
+}
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-memdb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -17,8 +18,14 @@ const (
 	instanceAppWorkspaceIndexName = "app-ws"
 )
 
+// instanceBucket keeps the instances that were connected when the server
+// last shut down, until they're restored when it starts again.
+var instanceBucket = []byte("instance")
+
 func init() {
+	dbBuckets = append(dbBuckets, instanceBucket)
 	schemas = append(schemas, instanceSchema)
+	memOnlyTables[instanceTableName] = struct{}{}
 }
 
 func instanceSchema() *memdb.TableSchema {
@@ -98,6 +105,14 @@ type Instance struct {
 	Application  string
 	Workspace    string
 	LogBuffer    *logbuffer.Buffer
+
+	// Health is the latest health reported by the entrypoint, or nil if it
+	// hasn't reported any.
+	Health *pb.Instance_Health
+
+	// Metrics are the latest metrics reported by the entrypoint, or nil if
+	// it hasn't reported any.
+	Metrics *pb.Instance_Metrics
 }
 
 func (i *Instance) Proto() *pb.Instance {
@@ -111,6 +126,8 @@ func (i *Instance) Proto() *pb.Instance {
 		Workspace: &pb.Ref_Workspace{
 			Workspace: i.Workspace,
 		},
+		Health:  i.Health,
+		Metrics: i.Metrics,
 	}
 }
 
@@ -144,6 +161,40 @@ func (s *State) InstanceDelete(id string) error {
 	return nil
 }
 
+// InstanceHealthSet sets the health of the instance.
+func (s *State) InstanceHealthSet(id string, health *pb.Instance_Health) error {
+	return s.instanceUpdate(id, func(rec *Instance) { rec.Health = health })
+}
+
+// InstanceMetricsSet sets the metrics of the instance.
+func (s *State) InstanceMetricsSet(id string, metrics *pb.Instance_Metrics) error {
+	return s.instanceUpdate(id, func(rec *Instance) { rec.Metrics = metrics })
+}
+
+// instanceUpdate updates the instance with the ID with f.
+func (s *State) instanceUpdate(id string, f func(*Instance)) error {
+	txn := s.inmem.Txn(true)
+	defer txn.Abort()
+
+	raw, err := txn.First(instanceTableName, instanceIdIndexName, id)
+	if err != nil {
+		return status.Errorf(codes.Aborted, err.Error())
+	}
+	if raw == nil {
+		return status.Errorf(codes.NotFound, "instance ID not found")
+	}
+
+	// Records in memdb can't be modified, so we insert a copy.
+	rec := *raw.(*Instance)
+	f(&rec)
+	if err := txn.Insert(instanceTableName, &rec); err != nil {
+		return status.Errorf(codes.Aborted, err.Error())
+	}
+
+	txn.Commit()
+	return nil
+}
+
 func (s *State) InstanceById(id string) (*Instance, error) {
 	txn := s.inmem.Txn(false)
 	raw, err := txn.First(instanceTableName, instanceIdIndexName, id)
@@ -203,3 +254,87 @@ func (s *State) InstancesByApp(
 
 	return result, nil
 }
+
+// InstanceSave writes the connected instances to the database so that they
+// can be restored with InstanceRestore after the server restarts. This is
+// called when the server shuts down, since instances are otherwise only
+// kept in memory.
+func (s *State) InstanceSave() error {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	iter, err := memTxn.Get(instanceTableName, instanceIdIndexName+"_prefix", "")
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(instanceBucket)
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			rec := raw.(*Instance)
+			if err := dbPut(b, []byte(rec.Id), rec.Proto()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// InstanceRestore creates the instances that were saved with InstanceSave
+// and removes them from the database. The restored instances have empty
+// log buffers. When an entrypoint reconnects, its restored instance is
+// replaced by InstanceCreate.
+func (s *State) InstanceRestore() ([]*Instance, error) {
+	var result []*Instance
+	err := s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(instanceBucket)
+
+		var ids [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			var value pb.Instance
+			if err := proto.Unmarshal(v, &value); err != nil {
+				return err
+			}
+
+			ids = append(ids, append([]byte(nil), k...))
+			result = append(result, &Instance{
+				Id:           value.Id,
+				DeploymentId: value.DeploymentId,
+				Project:      value.Application.GetProject(),
+				Application:  value.Application.GetApplication(),
+				Workspace:    value.Workspace.GetWorkspace(),
+				LogBuffer:    logbuffer.New(),
+				Health:       value.Health,
+				Metrics:      value.Metrics,
+			})
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := b.Delete(id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	txn := s.inmem.Txn(true)
+	defer txn.Abort()
+	for _, rec := range result {
+		if err := txn.Insert(instanceTableName, rec); err != nil {
+			return nil, status.Errorf(codes.Aborted, err.Error())
+		}
+	}
+	txn.Commit()
+
+	return result, nil
+}
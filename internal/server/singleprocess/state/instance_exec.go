@@ -18,6 +18,7 @@ const (
 
 func init() {
 	schemas = append(schemas, instanceExecSchema)
+	memOnlyTables[instanceExecTableName] = struct{}{}
 }
 
 var instanceExecId int64
@@ -55,6 +56,9 @@ type InstanceExec struct {
 	Args []string
 	Pty  *pb.ExecStreamRequest_PTY
 
+	// Copy is set if the session copies a file rather than runs Args.
+	Copy *pb.ExecStreamRequest_FileCopy
+
 	ClientEventCh     <-chan *pb.ExecStreamRequest
 	EntrypointEventCh chan<- *pb.EntrypointExecRequest
 	Connected         uint32
@@ -116,6 +120,33 @@ func (s *State) InstanceExecCreateByDeployment(did string, exec *InstanceExec) e
 	return nil
 }
 
+// InstanceExecCreateByInstance registers an exec session with the given
+// instance of the deployment. This fails if the instance doesn't exist or
+// belongs to another deployment.
+func (s *State) InstanceExecCreateByInstance(did, iid string, exec *InstanceExec) error {
+	txn := s.inmem.Txn(true)
+	defer txn.Abort()
+
+	raw, err := txn.First(instanceTableName, instanceIdIndexName, iid)
+	if err != nil {
+		return status.Errorf(codes.Aborted, err.Error())
+	}
+	if raw == nil || raw.(*Instance).DeploymentId != did {
+		return status.Errorf(codes.NotFound,
+			"Instance %q of deployment %q not found.", iid, did)
+	}
+
+	exec.InstanceId = iid
+	exec.Id = atomic.AddInt64(&instanceExecId, 1)
+
+	if err := txn.Insert(instanceExecTableName, exec); err != nil {
+		return status.Errorf(codes.Aborted, err.Error())
+	}
+	txn.Commit()
+
+	return nil
+}
+
 func (s *State) InstanceExecDelete(id int64) error {
 	txn := s.inmem.Txn(true)
 	defer txn.Abort()
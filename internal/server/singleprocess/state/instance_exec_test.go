@@ -83,3 +83,33 @@ func TestInstanceExecCreateByDeploymentId_valid(t *testing.T) {
 		require.NoError(s.InstanceExecDelete(rec.Id))
 	}
 }
+
+func TestInstanceExecCreateByInstance(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create two instances of the deployment
+	instance := testInstance(t, nil)
+	require.NoError(s.InstanceCreate(instance))
+	require.NoError(s.InstanceCreate(testInstance(t, &Instance{Id: "C"})))
+
+	// Both sessions get C even though A is less loaded
+	for i := 0; i < 2; i++ {
+		rec := &InstanceExec{}
+		require.NoError(s.InstanceExecCreateByInstance(instance.DeploymentId, "C", rec))
+		require.NotEmpty(rec.Id)
+		require.Equal("C", rec.InstanceId)
+	}
+
+	// An instance that doesn't exist
+	err := s.InstanceExecCreateByInstance(instance.DeploymentId, "nope", &InstanceExec{})
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+
+	// An instance of another deployment
+	err = s.InstanceExecCreateByInstance("other", "C", &InstanceExec{})
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+}
@@ -35,6 +35,86 @@ func TestInstance_crud(t *testing.T) {
 	require.NoError(s.InstanceDelete(rec.Id))
 }
 
+func TestInstanceSave(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	rec := &Instance{Id: "A", DeploymentId: "B", Project: "C", Application: "D", Workspace: "E"}
+	require.NoError(s.InstanceCreate(rec))
+	require.NoError(s.InstanceSave())
+
+	// Instances are only restored after a restart
+	s2 := TestStateReinit(t, s)
+	defer s2.Close()
+	_, err := s2.InstanceById(rec.Id)
+	require.Equal(codes.NotFound, status.Code(err))
+
+	restored, err := s2.InstanceRestore()
+	require.NoError(err)
+	require.Len(restored, 1)
+	require.Equal(rec.Proto(), restored[0].Proto())
+	require.NotNil(restored[0].LogBuffer)
+
+	found, err := s2.InstanceById(rec.Id)
+	require.NoError(err)
+	require.Equal(restored[0], found)
+
+	// They're only restored once
+	restored, err = s2.InstanceRestore()
+	require.NoError(err)
+	require.Empty(restored)
+}
+
+func TestInstanceHealthSet(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	rec := &Instance{Id: "A", DeploymentId: "B", Project: "C", Application: "D", Workspace: "E"}
+	require.NoError(s.InstanceCreate(rec))
+
+	health := &pb.Instance_Health{
+		State:   pb.Instance_Health_UNHEALTHY,
+		Message: "connection refused",
+	}
+	require.NoError(s.InstanceHealthSet(rec.Id, health))
+
+	found, err := s.InstanceById(rec.Id)
+	require.NoError(err)
+	require.Equal(health, found.Health)
+	require.Equal(health, found.Proto().Health)
+
+	// The record that was created isn't modified
+	require.Nil(rec.Health)
+
+	err = s.InstanceHealthSet("nope", health)
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestInstanceMetricsSet(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	rec := &Instance{Id: "A", DeploymentId: "B", Project: "C", Application: "D", Workspace: "E"}
+	require.NoError(s.InstanceCreate(rec))
+
+	metrics := &pb.Instance_Metrics{RssBytes: 1024, Restarts: 2}
+	require.NoError(s.InstanceMetricsSet(rec.Id, metrics))
+
+	found, err := s.InstanceById(rec.Id)
+	require.NoError(err)
+	require.Equal(metrics, found.Metrics)
+	require.Equal(metrics, found.Proto().Metrics)
+
+	err = s.InstanceMetricsSet("nope", metrics)
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
 func TestInstanceById_notFound(t *testing.T) {
 	require := require.New(t)
 
@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
-	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
@@ -120,12 +120,20 @@ type jobIndex struct {
 	// QueueTime is the time that the job was queued.
 	QueueTime time.Time
 
+	// Priority is the priority of the job. Higher priority jobs are
+	// assigned first.
+	Priority int32
+
 	// TargetAny will be true if this job targets anything
 	TargetAny bool
 
 	// TargetRunnerId is the ID of the runner to target.
 	TargetRunnerId string
 
+	// TargetLabels are the labels that the runner must have if the job
+	// targets runners by labels.
+	TargetLabels map[string]string
+
 	// State is the current state of this job.
 	State pb.Job_State
 
@@ -135,6 +143,9 @@ type jobIndex struct {
 
 	// OutputBuffer stores the terminal output
 	OutputBuffer *logbuffer.Buffer
+
+	// Output records the terminal output to store when the job completes.
+	Output *JobOutput
 }
 
 // Job is the exported structure that is returned for most state APIs
@@ -148,6 +159,12 @@ type Job struct {
 	// time of connection.
 	OutputBuffer *logbuffer.Buffer
 
+	// Output records all the terminal output while the job is running.
+	// Terminal events should be written to this as well as OutputBuffer so
+	// that the full output is stored when the job completes. This is nil
+	// if the job isn't running.
+	Output *JobOutput
+
 	// Blocked is true if this job is blocked on another job for the same
 	// project/app/workspace.
 	Blocked bool
@@ -158,18 +175,34 @@ func (s *State) JobCreate(jobpb *pb.Job) error {
 	txn := s.inmem.Txn(true)
 	defer txn.Abort()
 
-	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+	err := s.db.Update(func(dbTxn Tx) error {
 		return s.jobCreate(dbTxn, txn, jobpb)
 	})
 	if err == nil {
 		txn.Commit()
+		s.jobStateChanged(jobpb)
 	}
 
 	return err
 }
 
-// JobList returns the list of jobs.
-func (s *State) JobList() ([]*pb.Job, error) {
+// SetJobStateHook sets a function that is called with a copy of a job
+// whenever the job is created or its state changes. The function is called
+// synchronously while the state is updated, so it must not block or use
+// the state itself. This must be set before the state is used.
+func (s *State) SetJobStateHook(f func(*pb.Job)) {
+	s.jobStateHook = f
+}
+
+func (s *State) jobStateChanged(jobpb *pb.Job) {
+	if s.jobStateHook != nil {
+		s.jobStateHook(proto.Clone(jobpb).(*pb.Job))
+	}
+}
+
+// JobList returns the jobs that match the filters of req, most recently
+// queued first.
+func (s *State) JobList(req *pb.ListJobsRequest) ([]*pb.Job, error) {
 	memTxn := s.inmem.Txn(false)
 	defer memTxn.Abort()
 
@@ -178,26 +211,112 @@ func (s *State) JobList() ([]*pb.Job, error) {
 		return nil, err
 	}
 
-	var result []*pb.Job
+	var idxs []*jobIndex
 	for {
 		next := iter.Next()
 		if next == nil {
 			break
 		}
 		idx := next.(*jobIndex)
+		if !jobListMatch(idx, req) {
+			continue
+		}
 
-		var job *pb.Job
-		err = s.db.View(func(dbTxn *bolt.Tx) error {
-			job, err = s.jobById(dbTxn, idx.Id)
-			return err
-		})
+		idxs = append(idxs, idx)
+	}
 
-		result = append(result, job)
+	// Most recently queued first so that the limit keeps the latest jobs.
+	sort.Slice(idxs, func(i, j int) bool {
+		return idxs[i].QueueTime.After(idxs[j].QueueTime)
+	})
+	if limit := int(req.GetLimit()); limit > 0 && len(idxs) > limit {
+		idxs = idxs[:limit]
+	}
+
+	var result []*pb.Job
+	err = s.db.View(func(dbTxn Tx) error {
+		for _, idx := range idxs {
+			job, err := s.jobById(dbTxn, idx.Id)
+			if err != nil {
+				return err
+			}
+
+			if idx.State == pb.Job_QUEUED {
+				job.QueuePosition, err = s.jobQueuePosition(memTxn, idx, nil)
+				if err != nil {
+					return err
+				}
+			}
+
+			result = append(result, job)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return result, nil
 }
 
+// jobListMatch returns true if the job matches the filters of the request.
+func jobListMatch(idx *jobIndex, req *pb.ListJobsRequest) bool {
+	if ref := req.GetApplication(); ref != nil {
+		if !strings.EqualFold(idx.Application.GetProject(), ref.Project) {
+			return false
+		}
+		if ref.Application != "" &&
+			!strings.EqualFold(idx.Application.GetApplication(), ref.Application) {
+			return false
+		}
+	}
+
+	if ref := req.GetWorkspace(); ref != nil {
+		if !strings.EqualFold(idx.Workspace.GetWorkspace(), ref.Workspace) {
+			return false
+		}
+	}
+
+	if states := req.GetState(); len(states) > 0 {
+		found := false
+		for _, state := range states {
+			if idx.State == state {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// JobAssignedCount returns the number of jobs that are assigned to runners
+// and haven't completed yet. If a non-nil WatchSet is given, this can be
+// watched for changes to the jobs.
+func (s *State) JobAssignedCount(ws memdb.WatchSet) (int, error) {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	count := 0
+	for _, state := range []pb.Job_State{pb.Job_WAITING, pb.Job_RUNNING} {
+		iter, err := memTxn.Get(jobTableName, jobStateIndexName, state)
+		if err != nil {
+			return 0, err
+		}
+		ws.Add(iter.WatchCh())
+
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
 // JobById looks up a job by ID. The returned Job will be a deep copy
 // of the job so it is safe to read/write. If the job can't be found,
 // a nil result with no error is returned.
@@ -217,21 +336,31 @@ func (s *State) JobById(id string, ws memdb.WatchSet) (*Job, error) {
 	}
 	jobIdx := raw.(*jobIndex)
 
-	// Get blocked status if it is queued.
+	// Get blocked status and queue position if it is queued.
 	var blocked bool
+	var position uint32
 	if jobIdx.State == pb.Job_QUEUED {
 		blocked, err = s.jobIsBlocked(memTxn, jobIdx, ws)
 		if err != nil {
 			return nil, err
 		}
+
+		position, err = s.jobQueuePosition(memTxn, jobIdx, ws)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var job *pb.Job
-	err = s.db.View(func(dbTxn *bolt.Tx) error {
+	err = s.db.View(func(dbTxn Tx) error {
 		job, err = s.jobById(dbTxn, jobIdx.Id)
 		return err
 	})
 
+	if job != nil {
+		job.QueuePosition = position
+	}
+
 	result := jobIdx.Job(job)
 	result.Blocked = blocked
 
@@ -249,6 +378,12 @@ func (s *State) JobById(id string, ws memdb.WatchSet) (*Job, error) {
 // this will cancel when the context is done.
 func (s *State) JobAssignForRunner(ctx context.Context, r *pb.Runner) (*Job, error) {
 RETRY_ASSIGN:
+	// Only the leader assigns jobs so that two servers sharing the same
+	// DB never assign the same job.
+	if !s.IsLeader() {
+		return nil, ErrNotLeader
+	}
+
 	txn := s.inmem.Txn(false)
 	defer txn.Abort()
 
@@ -260,6 +395,7 @@ RETRY_ASSIGN:
 	candidateQuery := []candidateFunc{
 		s.jobCandidateById,
 		s.jobCandidateAny,
+		s.jobCandidateLabels,
 	}
 
 	// If the runner is by id only, then explicitly set it to by id only.
@@ -269,9 +405,19 @@ RETRY_ASSIGN:
 		candidateQuery = []candidateFunc{s.jobCandidateById}
 	}
 
+	// Runners that aren't adopted when the server requires it, or that are
+	// rejected, aren't assigned jobs until that changes.
+	ws := memdb.NewWatchSet()
+	assignable, err := s.runnerAssignableWatch(txn, ws, r)
+	if err != nil {
+		return nil, err
+	}
+	if !assignable {
+		candidateQuery = nil
+	}
+
 	// Build the list of candidates
 	var candidates []*jobIndex
-	ws := memdb.NewWatchSet()
 	for _, f := range candidateQuery {
 		job, err := f(txn, ws, runnerRec)
 		if err != nil {
@@ -293,6 +439,7 @@ RETRY_ASSIGN:
 		}
 
 		ws.Add(iter.WatchCh())
+		s.leaderWatch(ws)
 	}
 
 	// We're done reading so abort the transaction
@@ -309,8 +456,13 @@ RETRY_ASSIGN:
 		goto RETRY_ASSIGN
 	}
 
-	// We sort our candidates by queue time so that we can find the earliest
+	// We sort our candidates by priority and then queue time so that we
+	// assign the highest priority job that was queued first.
 	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Priority != candidates[j].Priority {
+			return candidates[i].Priority > candidates[j].Priority
+		}
+
 		return candidates[i].QueueTime.Before(candidates[j].QueueTime)
 	})
 
@@ -367,10 +519,10 @@ RETRY_ASSIGN:
 		}
 
 		// Create our timer to requeue this if it isn't acked
-		job.StateTimer = time.AfterFunc(jobWaitingTimeout, func() {
+		job.StateTimer = time.AfterFunc(jobWaitingTimeout, s.leaderFunc(func() {
 			s.log.Info("job ack timer expired", "job", job.Id, "timeout", jobWaitingTimeout)
 			s.JobAck(job.Id, false)
-		})
+		}))
 
 		if err := txn.Insert(jobTableName, job); err != nil {
 			return nil, err
@@ -429,6 +581,7 @@ func (s *State) JobAck(id string, ack bool) (*Job, error) {
 			// We also initialize the output buffer here because we can
 			// expect output to begin streaming in.
 			job.OutputBuffer = logbuffer.New()
+			job.Output = &JobOutput{}
 		} else {
 			// Set to queued
 			job.State = pb.Job_QUEUED
@@ -450,14 +603,14 @@ func (s *State) JobAck(id string, ack bool) (*Job, error) {
 
 	// Create a new timer that we'll use for our heartbeat. After this
 	// timer expires, the job will immediately move to an error state.
-	job.StateTimer = time.AfterFunc(jobHeartbeatTimeout, func() {
+	job.StateTimer = time.AfterFunc(jobHeartbeatTimeout, s.leaderFunc(func() {
 		s.log.Info("canceling job due to heartbeat timeout", "job", job.Id)
 		// Force cancel
 		err := s.JobCancel(job.Id, true)
 		if err != nil {
 			s.log.Error("error canceling job due to heartbeat failure", "error", err, "job", job.Id)
 		}
-	})
+	}))
 
 	s.log.Debug("heartbeat timer set", "job", job.Id, "timeout", jobHeartbeatTimeout)
 
@@ -534,6 +687,12 @@ func (s *State) JobComplete(id string, result *pb.Job_Result, cerr error) error
 	// End the job
 	job.End()
 
+	// Store the output before the completion is visible so that anyone
+	// watching the job can read it.
+	if err := s.jobOutputStore(job); err != nil {
+		return err
+	}
+
 	// Insert to update
 	if err := txn.Insert(jobTableName, job); err != nil {
 		return err
@@ -601,6 +760,11 @@ func (s *State) jobCancel(txn *memdb.Txn, job *jobIndex, force bool) error {
 		if err := s.jobAssignedSet(txn, job, false); err != nil {
 			return err
 		}
+
+		// Store whatever output the job had before it was canceled
+		if err := s.jobOutputStore(job); err != nil {
+			return err
+		}
 	}
 
 	// Persist the on-disk data
@@ -725,6 +889,12 @@ func (s *State) JobIsAssignable(ctx context.Context, jobpb *pb.Job) (bool, error
 	memTxn := s.inmem.Txn(false)
 	defer memTxn.Abort()
 
+	// Runners that may not be assigned jobs don't count
+	cfg, err := s.serverConfigGet(memTxn, nil)
+	if err != nil {
+		return false, err
+	}
+
 	// If we have no runners, we cannot be assigned
 	empty, err := s.runnerEmpty(memTxn)
 	if err != nil {
@@ -749,6 +919,13 @@ func (s *State) JobIsAssignable(ctx context.Context, jobpb *pb.Job) (bool, error
 	case *pb.Ref_Runner_Id:
 		iter, err = memTxn.Get(runnerTableName, runnerIdIndexName, v.Id.Id)
 
+	case *pb.Ref_Runner_Labels:
+		targetCheck = func(r *pb.Runner) (bool, error) {
+			return !r.ByIdOnly && runnerHasLabels(r, v.Labels.Labels), nil
+		}
+
+		iter, err = memTxn.LowerBound(runnerTableName, runnerIdIndexName, "")
+
 	default:
 		return false, fmt.Errorf("unknown runner target value: %#v", jobpb.TargetRunner.Target)
 	}
@@ -763,6 +940,9 @@ func (s *State) JobIsAssignable(ctx context.Context, jobpb *pb.Job) (bool, error
 			return false, nil
 		}
 		runner := raw.(*runnerRecord)
+		if !runnerAssignable(runner.Runner, cfg) {
+			continue
+		}
 
 		// Check our target-specific check
 		if targetCheck != nil {
@@ -781,7 +961,7 @@ func (s *State) JobIsAssignable(ctx context.Context, jobpb *pb.Job) (bool, error
 }
 
 // jobIndexInit initializes the config index from persisted data.
-func (s *State) jobIndexInit(dbTxn *bolt.Tx, memTxn *memdb.Txn) error {
+func (s *State) jobIndexInit(dbTxn Tx, memTxn *memdb.Txn) error {
 	bucket := dbTxn.Bucket(jobBucket)
 	return bucket.ForEach(func(k, v []byte) error {
 		var value pb.Job
@@ -813,6 +993,7 @@ func (s *State) jobIndexSet(txn *memdb.Txn, id []byte, jobpb *pb.Job) (*jobIndex
 		Application: jobpb.Application,
 		Workspace:   jobpb.Workspace,
 		OpType:      reflect.TypeOf(jobpb.Operation),
+		Priority:    jobpb.Priority,
 	}
 
 	// Target
@@ -826,6 +1007,12 @@ func (s *State) jobIndexSet(txn *memdb.Txn, id []byte, jobpb *pb.Job) (*jobIndex
 	case *pb.Ref_Runner_Id:
 		rec.TargetRunnerId = v.Id.Id
 
+	case *pb.Ref_Runner_Labels:
+		rec.TargetLabels = v.Labels.Labels
+		if rec.TargetLabels == nil {
+			rec.TargetLabels = map[string]string{}
+		}
+
 	default:
 		return nil, fmt.Errorf("unknown runner target value: %#v", jobpb.TargetRunner.Target)
 	}
@@ -850,18 +1037,18 @@ func (s *State) jobIndexSet(txn *memdb.Txn, id []byte, jobpb *pb.Job) (*jobIndex
 	// We reset the nack timer so it gives runners time to reconnect.
 	if rec.State == pb.Job_WAITING {
 		// Create our timer to requeue this if it isn't acked
-		rec.StateTimer = time.AfterFunc(jobWaitingTimeout, func() {
+		rec.StateTimer = time.AfterFunc(jobWaitingTimeout, s.leaderFunc(func() {
 			s.JobAck(rec.Id, false)
-		})
+		}))
 	}
 
 	// If this job is running, we need to restart a heartbeat timeout.
 	// This should only happen on reinit. This is tested.
 	if rec.State == pb.Job_RUNNING {
-		rec.StateTimer = time.AfterFunc(jobHeartbeatTimeout, func() {
+		rec.StateTimer = time.AfterFunc(jobHeartbeatTimeout, s.leaderFunc(func() {
 			// Force cancel
 			s.JobCancel(rec.Id, true)
-		})
+		}))
 	}
 
 	// If we have an expiry, we need to set a timer to expire this job.
@@ -878,14 +1065,14 @@ func (s *State) jobIndexSet(txn *memdb.Txn, id []byte, jobpb *pb.Job) (*jobIndex
 			dur = 1
 		}
 
-		time.AfterFunc(dur, func() { s.JobExpire(jobpb.Id) })
+		time.AfterFunc(dur, s.leaderFunc(func() { s.JobExpire(jobpb.Id) }))
 	}
 
 	// Insert the index
 	return rec, txn.Insert(jobTableName, rec)
 }
 
-func (s *State) jobCreate(dbTxn *bolt.Tx, memTxn *memdb.Txn, jobpb *pb.Job) error {
+func (s *State) jobCreate(dbTxn Tx, memTxn *memdb.Txn, jobpb *pb.Job) error {
 	// Setup our initial job state
 	var err error
 	jobpb.State = pb.Job_QUEUED
@@ -896,7 +1083,7 @@ func (s *State) jobCreate(dbTxn *bolt.Tx, memTxn *memdb.Txn, jobpb *pb.Job) erro
 
 	id := []byte(jobpb.Id)
 
-	// Insert into bolt
+	// Insert into the db
 	if err := dbPut(dbTxn.Bucket(jobBucket), id, jobpb); err != nil {
 		return err
 	}
@@ -906,7 +1093,7 @@ func (s *State) jobCreate(dbTxn *bolt.Tx, memTxn *memdb.Txn, jobpb *pb.Job) erro
 	return err
 }
 
-func (s *State) jobById(dbTxn *bolt.Tx, id string) (*pb.Job, error) {
+func (s *State) jobById(dbTxn Tx, id string) (*pb.Job, error) {
 	var result pb.Job
 	b := dbTxn.Bucket(jobBucket)
 	return &result, dbGet(b, []byte(id), &result)
@@ -914,12 +1101,14 @@ func (s *State) jobById(dbTxn *bolt.Tx, id string) (*pb.Job, error) {
 
 func (s *State) jobReadAndUpdate(id string, f func(*pb.Job) error) (*pb.Job, error) {
 	var result *pb.Job
+	var oldState pb.Job_State
 	var err error
-	return result, s.db.Update(func(dbTxn *bolt.Tx) error {
+	err = s.db.Update(func(dbTxn Tx) error {
 		result, err = s.jobById(dbTxn, id)
 		if err != nil {
 			return err
 		}
+		oldState = result.State
 
 		// Modify
 		if err := f(result); err != nil {
@@ -929,10 +1118,16 @@ func (s *State) jobReadAndUpdate(id string, f func(*pb.Job) error) (*pb.Job, err
 		// Commit
 		return dbPut(dbTxn.Bucket(jobBucket), []byte(id), result)
 	})
+	if err == nil && result.State != oldState {
+		s.jobStateChanged(result)
+	}
+
+	return result, err
 }
 
 // jobCandidateById returns the most promising candidate job to assign
-// that is targeting a specific runner by ID.
+// that is targeting a specific runner by ID. This is the highest priority
+// job that isn't blocked, and the earliest queued of those.
 func (s *State) jobCandidateById(memTxn *memdb.Txn, ws memdb.WatchSet, r *runnerRecord) (*jobIndex, error) {
 	iter, err := memTxn.LowerBound(
 		jobTableName,
@@ -945,6 +1140,7 @@ func (s *State) jobCandidateById(memTxn *memdb.Txn, ws memdb.WatchSet, r *runner
 		return nil, err
 	}
 
+	var result *jobIndex
 	for {
 		raw := iter.Next()
 		if raw == nil {
@@ -956,6 +1152,12 @@ func (s *State) jobCandidateById(memTxn *memdb.Txn, ws memdb.WatchSet, r *runner
 			continue
 		}
 
+		// Jobs are in queue order so we only need later jobs if they
+		// have a higher priority.
+		if result != nil && job.Priority <= result.Priority {
+			continue
+		}
+
 		// If this job is blocked, it is not a candidate.
 		if blocked, err := s.jobIsBlocked(memTxn, job, ws); err != nil {
 			return nil, err
@@ -963,13 +1165,14 @@ func (s *State) jobCandidateById(memTxn *memdb.Txn, ws memdb.WatchSet, r *runner
 			continue
 		}
 
-		return job, nil
+		result = job
 	}
 
-	return nil, nil
+	return result, nil
 }
 
-// jobCandidateAny returns the first candidate job that targets any runner.
+// jobCandidateAny returns the most promising candidate job to assign that
+// targets any runner, chosen in the same way as jobCandidateById.
 func (s *State) jobCandidateAny(memTxn *memdb.Txn, ws memdb.WatchSet, r *runnerRecord) (*jobIndex, error) {
 	iter, err := memTxn.LowerBound(
 		jobTableName,
@@ -981,6 +1184,7 @@ func (s *State) jobCandidateAny(memTxn *memdb.Txn, ws memdb.WatchSet, r *runnerR
 		return nil, err
 	}
 
+	var result *jobIndex
 	for {
 		raw := iter.Next()
 		if raw == nil {
@@ -992,6 +1196,12 @@ func (s *State) jobCandidateAny(memTxn *memdb.Txn, ws memdb.WatchSet, r *runnerR
 			continue
 		}
 
+		// Jobs are in queue order so we only need later jobs if they
+		// have a higher priority.
+		if result != nil && job.Priority <= result.Priority {
+			continue
+		}
+
 		// If this job is blocked, it is not a candidate.
 		if blocked, err := s.jobIsBlocked(memTxn, job, ws); err != nil {
 			return nil, err
@@ -999,10 +1209,97 @@ func (s *State) jobCandidateAny(memTxn *memdb.Txn, ws memdb.WatchSet, r *runnerR
 			continue
 		}
 
-		return job, nil
+		result = job
+	}
+
+	return result, nil
+}
+
+// jobCandidateLabels returns the most promising candidate job to assign
+// that targets runners by labels that the runner has, chosen in the same
+// way as jobCandidateById.
+func (s *State) jobCandidateLabels(memTxn *memdb.Txn, ws memdb.WatchSet, r *runnerRecord) (*jobIndex, error) {
+	iter, err := memTxn.LowerBound(
+		jobTableName,
+		jobQueueTimeIndexName,
+		pb.Job_QUEUED,
+		time.Unix(0, 0),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *jobIndex
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		job := raw.(*jobIndex)
+		if job.State != pb.Job_QUEUED || job.TargetLabels == nil ||
+			!runnerHasLabels(r.Runner, job.TargetLabels) {
+			continue
+		}
+
+		// Jobs are in queue order so we only need later jobs if they
+		// have a higher priority.
+		if result != nil && job.Priority <= result.Priority {
+			continue
+		}
+
+		// If this job is blocked, it is not a candidate.
+		if blocked, err := s.jobIsBlocked(memTxn, job, ws); err != nil {
+			return nil, err
+		} else if blocked {
+			continue
+		}
+
+		result = job
+	}
+
+	return result, nil
+}
+
+// runnerHasLabels returns true if the runner has all of the labels.
+func runnerHasLabels(r *pb.Runner, labels map[string]string) bool {
+	for k, v := range labels {
+		if r.Labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jobQueuePosition returns the position of a queued job in the queue,
+// starting at 1. This counts the queued jobs with a higher priority or
+// with the same priority that were queued earlier. The runners that jobs
+// target aren't considered so this is only an estimate of the order jobs
+// are assigned in.
+func (s *State) jobQueuePosition(memTxn *memdb.Txn, idx *jobIndex, ws memdb.WatchSet) (uint32, error) {
+	iter, err := memTxn.Get(jobTableName, jobStateIndexName, pb.Job_QUEUED)
+	if err != nil {
+		return 0, err
+	}
+	if ws != nil {
+		ws.Add(iter.WatchCh())
+	}
+
+	position := uint32(1)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*jobIndex)
+		if job.Id == idx.Id {
+			continue
+		}
+
+		if job.Priority > idx.Priority ||
+			(job.Priority == idx.Priority && job.QueueTime.Before(idx.QueueTime)) {
+			position++
+		}
 	}
 
-	return nil, nil
+	return position, nil
 }
 
 // Job returns the Job for an index.
@@ -1010,6 +1307,7 @@ func (idx *jobIndex) Job(jobpb *pb.Job) *Job {
 	return &Job{
 		Job:          jobpb,
 		OutputBuffer: idx.OutputBuffer,
+		Output:       idx.Output,
 	}
 }
 
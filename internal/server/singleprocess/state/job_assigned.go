@@ -2,6 +2,7 @@ package state
 
 import (
 	"reflect"
+	"strings"
 
 	"github.com/hashicorp/go-memdb"
 
@@ -18,6 +19,7 @@ var blockOps = map[reflect.Type]struct{}{
 	reflect.TypeOf((*pb.Job_Deploy)(nil)):  struct{}{},
 	reflect.TypeOf((*pb.Job_Destroy)(nil)): struct{}{},
 	reflect.TypeOf((*pb.Job_Release)(nil)): struct{}{},
+	reflect.TypeOf((*pb.Job_Scale)(nil)):   struct{}{},
 }
 
 func init() {
@@ -67,12 +69,18 @@ type jobAssignedIndex struct {
 }
 
 // jobIsBlocked will return true if the given job is currently blocked because
-// a job with the same project/app/workspace is executing.
+// a job with the same project/app/workspace is executing, or because the
+// project already has its maximum number of concurrent jobs.
 //
 // If ws is set then a watch will be added for any changes in assigned jobs.
 // Note that this trigger doesn't mean that the blocking is necessarily gone
 // but something changed to warrant rechecking.
 func (s *State) jobIsBlocked(memTxn *memdb.Txn, idx *jobIndex, ws memdb.WatchSet) (bool, error) {
+	// The project limit applies to all operation types.
+	if limited, err := s.jobProjectLimited(memTxn, idx, ws); err != nil || limited {
+		return limited, err
+	}
+
 	// If this job represents a parallelizable operation type, then allow it.
 	if _, ok := blockOps[idx.OpType]; !ok {
 		return false, nil
@@ -97,6 +105,51 @@ func (s *State) jobIsBlocked(memTxn *memdb.Txn, idx *jobIndex, ws memdb.WatchSet
 	return value != nil, nil
 }
 
+// jobProjectLimited returns true if the project of the job has reached its
+// maximum number of concurrent jobs. Jobs count towards the limit from when
+// they're assigned to a runner until they complete.
+func (s *State) jobProjectLimited(memTxn *memdb.Txn, idx *jobIndex, ws memdb.WatchSet) (bool, error) {
+	watchCh, raw, err := memTxn.FirstWatch(
+		projectIndexTableName,
+		projectIndexIdIndexName,
+		idx.Application.Project,
+	)
+	if err != nil {
+		return false, err
+	}
+	if ws != nil {
+		ws.Add(watchCh)
+	}
+	if raw == nil {
+		return false, nil
+	}
+
+	limit := raw.(*projectIndexRecord).MaxConcurrentJobs
+	if limit == 0 {
+		return false, nil
+	}
+
+	var count uint32
+	for _, state := range []pb.Job_State{pb.Job_WAITING, pb.Job_RUNNING} {
+		iter, err := memTxn.Get(jobTableName, jobStateIndexName, state)
+		if err != nil {
+			return false, err
+		}
+		if ws != nil {
+			ws.Add(iter.WatchCh())
+		}
+
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			job := raw.(*jobIndex)
+			if strings.EqualFold(job.Application.Project, idx.Application.Project) {
+				count++
+			}
+		}
+	}
+
+	return count >= limit, nil
+}
+
 // jobAssignedSet records the given job as assigned.
 func (s *State) jobAssignedSet(memTxn *memdb.Txn, idx *jobIndex, assigned bool) error {
 	// If this job represents a parallelizable operation type, then do nothing.
@@ -0,0 +1,94 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// jobOutputBucket stores the terminal output of completed jobs by job ID so
+// that it can be read after the job is done, even if the server restarted.
+var jobOutputBucket = []byte("job_output")
+
+// jobOutputMax is the maximum number of terminal events that are stored for
+// a job. If a job has more, only the latest are stored.
+const jobOutputMax = 10000
+
+func init() {
+	dbBuckets = append(dbBuckets, jobOutputBucket)
+}
+
+// JobOutput records the terminal output of a running job so that it can be
+// stored when the job completes. Unlike the output buffer, this doesn't drop
+// output that readers haven't caught up with.
+type JobOutput struct {
+	mu      sync.Mutex
+	events  []*pb.GetJobStreamResponse_Terminal_Event
+	dropped int
+}
+
+// Write records the events. This is safe for concurrent access.
+func (o *JobOutput) Write(events ...*pb.GetJobStreamResponse_Terminal_Event) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.events = append(o.events, events...)
+	if over := len(o.events) - jobOutputMax; over > 0 {
+		o.events = append(o.events[:0:0], o.events[over:]...)
+		o.dropped += over
+	}
+}
+
+// terminal returns the recorded events. If any were dropped, this starts
+// with a line that says how many.
+func (o *JobOutput) terminal() *pb.GetJobStreamResponse_Terminal {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var events []*pb.GetJobStreamResponse_Terminal_Event
+	if o.dropped > 0 {
+		events = append(events, &pb.GetJobStreamResponse_Terminal_Event{
+			Event: &pb.GetJobStreamResponse_Terminal_Event_Line_{
+				Line: &pb.GetJobStreamResponse_Terminal_Event_Line{
+					Msg: fmt.Sprintf(
+						"(%d earlier lines of output weren't stored)", o.dropped),
+				},
+			},
+		})
+	}
+
+	return &pb.GetJobStreamResponse_Terminal{
+		Events: append(events, o.events...),
+	}
+}
+
+// JobOutputGet returns the stored terminal output of a completed job. This
+// returns a NotFound error if there is no output stored for the job, such
+// as when it isn't complete yet.
+func (s *State) JobOutputGet(id string) (*pb.GetJobStreamResponse_Terminal, error) {
+	var result pb.GetJobStreamResponse_Terminal
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbGet(dbTxn.Bucket(jobOutputBucket), []byte(id), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// jobOutputStore stores the recorded output of the job. This should be
+// called once the job is complete. The recorded output is released
+// afterwards since it is no longer written to.
+func (s *State) jobOutputStore(idx *jobIndex) error {
+	if idx.Output == nil {
+		return nil
+	}
+
+	output := idx.Output.terminal()
+	idx.Output = nil
+	return s.db.Update(func(dbTxn Tx) error {
+		return dbPut(dbTxn.Bucket(jobOutputBucket), []byte(idx.Id), output)
+	})
+}
@@ -46,6 +46,57 @@ func TestJobAssign(t *testing.T) {
 		require.Equal(ctx.Err(), err)
 	})
 
+	t.Run("blocking on adoption", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Require adoption and register a runner
+		require.NoError(s.ServerConfigSet(&pb.ServerConfig{RequireRunnerAdoption: true}))
+		r := &pb.Runner{Id: "R_A"}
+		require.NoError(s.RunnerCreate(r))
+
+		// Create a build
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id: "A",
+		})))
+
+		// Get the value in a goroutine
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var job *Job
+		var jerr error
+		doneCh := make(chan struct{})
+		go func() {
+			defer close(doneCh)
+			job, jerr = s.JobAssignForRunner(ctx, r)
+		}()
+
+		// We should be blocking since we're not adopted
+		select {
+		case <-doneCh:
+			t.Fatal("should wait")
+
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		// Adopt the runner
+		require.NoError(s.RunnerAdopt(r.Id, pb.Runner_ADOPTED))
+
+		// We should get a result
+		select {
+		case <-doneCh:
+
+		case <-time.After(500 * time.Millisecond):
+			t.Fatal("should have a result")
+		}
+
+		require.NoError(jerr)
+		require.NotNil(job)
+		require.Equal("A", job.Id)
+	})
+
 	t.Run("blocking on any", func(t *testing.T) {
 		require := require.New(t)
 
@@ -297,6 +348,85 @@ func TestJobAssign(t *testing.T) {
 		}
 	})
 
+	t.Run("higher priority first", func(t *testing.T) {
+		require := require.New(t)
+		ctx := context.Background()
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Create jobs slightly apart, the last with a higher priority
+		for _, id := range []string{"A", "B"} {
+			require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+				Id: id,
+			})))
+			time.Sleep(1 * time.Millisecond)
+		}
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id:       "C",
+			Priority: 10,
+		})))
+
+		// Queue positions follow the assignment order
+		for id, position := range map[string]uint32{"A": 2, "B": 3, "C": 1} {
+			job, err := s.JobById(id, nil)
+			require.NoError(err)
+			require.Equal(position, job.QueuePosition, id)
+		}
+
+		for _, id := range []string{"C", "A", "B"} {
+			job, err := s.JobAssignForRunner(ctx, &pb.Runner{Id: "R_A"})
+			require.NoError(err)
+			require.NotNil(job)
+			require.Equal(id, job.Id)
+		}
+	})
+
+	t.Run("blocking on project concurrency limit", func(t *testing.T) {
+		require := require.New(t)
+		ctx := context.Background()
+
+		s := TestState(t)
+		defer s.Close()
+
+		require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+			Name: "p_test",
+			Jobs: &pb.Project_Jobs{MaxConcurrent: 1},
+		})))
+
+		// Two jobs of the limited project and one of another project
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{Id: "A"})))
+		time.Sleep(1 * time.Millisecond)
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{Id: "B"})))
+		time.Sleep(1 * time.Millisecond)
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id:          "C",
+			Application: &pb.Ref_Application{Project: "other", Application: "app"},
+		})))
+
+		job, err := s.JobAssignForRunner(ctx, &pb.Runner{Id: "R_A"})
+		require.NoError(err)
+		require.Equal("A", job.Id)
+
+		// B is blocked by the limit so the other project goes next
+		job, err = s.JobAssignForRunner(ctx, &pb.Runner{Id: "R_A"})
+		require.NoError(err)
+		require.Equal("C", job.Id)
+
+		blocked, err := s.JobById("B", nil)
+		require.NoError(err)
+		require.True(blocked.Blocked)
+
+		// Completing A unblocks B
+		_, err = s.JobAck("A", true)
+		require.NoError(err)
+		require.NoError(s.JobComplete("A", nil, nil))
+
+		job, err = s.JobAssignForRunner(ctx, &pb.Runner{Id: "R_A"})
+		require.NoError(err)
+		require.Equal("B", job.Id)
+	})
+
 	t.Run("assignment by ID", func(t *testing.T) {
 		require := require.New(t)
 
@@ -387,6 +517,67 @@ func TestJobAssign(t *testing.T) {
 		}
 	})
 
+	t.Run("assignment by labels", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Create a build that targets GPU runners
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id: "A",
+			TargetRunner: &pb.Ref_Runner{
+				Target: &pb.Ref_Runner_Labels{
+					Labels: &pb.Ref_RunnerLabels{
+						Labels: map[string]string{"profile": "gpu"},
+					},
+				},
+			},
+		})))
+		time.Sleep(1 * time.Millisecond)
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id: "B",
+		})))
+
+		// A runner without the labels gets B even though A was queued first.
+		{
+			job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{
+				Id:     "R_A",
+				Labels: map[string]string{"arch": "arm64"},
+			})
+			require.NoError(err)
+			require.NotNil(job)
+			require.Equal("B", job.Id)
+			_, err = s.JobAck(job.Id, true)
+			require.NoError(err)
+			require.NoError(s.JobComplete(job.Id, nil, nil))
+		}
+
+		// A runner by ID only isn't assigned jobs by labels.
+		{
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			job, err := s.JobAssignForRunner(ctx, &pb.Runner{
+				Id:       "R_B",
+				ByIdOnly: true,
+				Labels:   map[string]string{"profile": "gpu"},
+			})
+			require.Error(err)
+			require.Nil(job)
+		}
+
+		// A runner with the labels, and others, gets A.
+		{
+			job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{
+				Id:     "R_C",
+				Labels: map[string]string{"profile": "gpu", "arch": "amd64"},
+			})
+			require.NoError(err)
+			require.NotNil(job)
+			require.Equal("A", job.Id)
+		}
+	})
+
 	t.Run("any cannot be assigned to ByIdOnly runner", func(t *testing.T) {
 		require := require.New(t)
 
@@ -428,6 +619,62 @@ func TestJobAssign(t *testing.T) {
 	})
 }
 
+func TestJobList(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create jobs in two workspaces
+	require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "A",
+	})))
+	require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id:        "B",
+		Workspace: &pb.Ref_Workspace{Workspace: "other"},
+	})))
+	require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "C",
+	})))
+
+	// All jobs, most recent first
+	list, err := s.JobList(&pb.ListJobsRequest{})
+	require.NoError(err)
+	require.Len(list, 3)
+	require.Equal("C", list[0].Id)
+	require.Equal("A", list[2].Id)
+
+	// By workspace
+	list, err = s.JobList(&pb.ListJobsRequest{
+		Workspace: &pb.Ref_Workspace{Workspace: "other"},
+	})
+	require.NoError(err)
+	require.Len(list, 1)
+	require.Equal("B", list[0].Id)
+
+	// By project
+	list, err = s.JobList(&pb.ListJobsRequest{
+		Application: &pb.Ref_Application{Project: "nope"},
+	})
+	require.NoError(err)
+	require.Len(list, 0)
+
+	// By state, with a limit
+	list, err = s.JobList(&pb.ListJobsRequest{
+		State: []pb.Job_State{pb.Job_QUEUED},
+		Limit: 2,
+	})
+	require.NoError(err)
+	require.Len(list, 2)
+	require.Equal("C", list[0].Id)
+
+	list, err = s.JobList(&pb.ListJobsRequest{
+		State: []pb.Job_State{pb.Job_SUCCESS},
+	})
+	require.NoError(err)
+	require.Len(list, 0)
+}
+
 func TestJobAck(t *testing.T) {
 	t.Run("ack", func(t *testing.T) {
 		require := require.New(t)
@@ -595,6 +842,51 @@ func TestJobComplete(t *testing.T) {
 		require.Equal(codes.Unknown, st.Code())
 		require.Contains(st.Message(), "bad")
 	})
+
+	t.Run("stores output", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Create a build
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+			Id: "A",
+		})))
+
+		// Assign it, we should get this build
+		job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+		require.NoError(err)
+		require.NotNil(job)
+
+		// Ack it
+		job, err = s.JobAck(job.Id, true)
+		require.NoError(err)
+		require.NotNil(job.Output)
+
+		// No output is stored while it is running
+		_, err = s.JobOutputGet(job.Id)
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+
+		// Write some output
+		job.Output.Write(&pb.GetJobStreamResponse_Terminal_Event{
+			Event: &pb.GetJobStreamResponse_Terminal_Event_Line_{
+				Line: &pb.GetJobStreamResponse_Terminal_Event_Line{Msg: "hello"},
+			},
+		})
+
+		// Complete it
+		require.NoError(s.JobComplete(job.Id, &pb.Job_Result{
+			Build: &pb.Job_BuildResult{},
+		}, nil))
+
+		// Verify the output is stored
+		output, err := s.JobOutputGet(job.Id)
+		require.NoError(err)
+		require.Len(output.Events, 1)
+		require.Equal("hello", output.Events[0].GetLine().Msg)
+	})
 }
 
 func TestJobIsAssignable(t *testing.T) {
@@ -661,6 +953,79 @@ func TestJobIsAssignable(t *testing.T) {
 		require.False(result)
 	})
 
+	t.Run("any target, runners rejected or not adopted", func(t *testing.T) {
+		require := require.New(t)
+		ctx := context.Background()
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Register a runner and reject it
+		r := serverptypes.TestRunner(t, nil)
+		require.NoError(s.RunnerCreate(r))
+		require.NoError(s.RunnerAdopt(r.Id, pb.Runner_REJECTED))
+
+		job := serverptypes.TestJobNew(t, &pb.Job{
+			Id: "A",
+			TargetRunner: &pb.Ref_Runner{
+				Target: &pb.Ref_Runner_Any{
+					Any: &pb.Ref_RunnerAny{},
+				},
+			},
+		})
+
+		// Should not be assignable
+		result, err := s.JobIsAssignable(ctx, job)
+		require.NoError(err)
+		require.False(result)
+
+		// Require adoption, pending isn't enough
+		require.NoError(s.ServerConfigSet(&pb.ServerConfig{RequireRunnerAdoption: true}))
+		require.NoError(s.RunnerAdopt(r.Id, pb.Runner_PENDING))
+		result, err = s.JobIsAssignable(ctx, job)
+		require.NoError(err)
+		require.False(result)
+
+		// Adopted is assignable
+		require.NoError(s.RunnerAdopt(r.Id, pb.Runner_ADOPTED))
+		result, err = s.JobIsAssignable(ctx, job)
+		require.NoError(err)
+		require.True(result)
+	})
+
+	t.Run("labels target", func(t *testing.T) {
+		require := require.New(t)
+		ctx := context.Background()
+
+		s := TestState(t)
+		defer s.Close()
+
+		// Register a runner
+		require.NoError(s.RunnerCreate(serverptypes.TestRunner(t, &pb.Runner{
+			Labels: map[string]string{"profile": "gpu"},
+		})))
+
+		job := func(labels map[string]string) *pb.Job {
+			return serverptypes.TestJobNew(t, &pb.Job{
+				Id: "A",
+				TargetRunner: &pb.Ref_Runner{
+					Target: &pb.Ref_Runner_Labels{
+						Labels: &pb.Ref_RunnerLabels{Labels: labels},
+					},
+				},
+			})
+		}
+
+		// Should be assignable if the runner has the labels
+		result, err := s.JobIsAssignable(ctx, job(map[string]string{"profile": "gpu"}))
+		require.NoError(err)
+		require.True(result)
+
+		result, err = s.JobIsAssignable(ctx, job(map[string]string{"profile": "docker"}))
+		require.NoError(err)
+		require.False(result)
+	})
+
 	t.Run("ID target, no match", func(t *testing.T) {
 		require := require.New(t)
 		ctx := context.Background()
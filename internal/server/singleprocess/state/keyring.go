@@ -0,0 +1,187 @@
+package state
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// keyringKeySize is the size of the keys of a keyring. The records are
+// encrypted with AES-256-GCM.
+const keyringKeySize = 32
+
+// encryptedPrefix is the first byte of encrypted values. A protobuf message
+// never starts with a zero byte since that is an invalid field tag, so this
+// tells encrypted values apart from the plaintext values written before
+// encryption was enabled.
+const encryptedPrefix = 0x00
+
+// Keyring holds the keys that encrypt the sensitive records of the state,
+// such as config variables, tokens and auth keys. New records are encrypted
+// with the active key. The other keys are kept so that the records written
+// before the active key was rotated can be read, and the server re-encrypts
+// those records with the active key when it starts.
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// keyringFile is the format of a keyring file.
+type keyringFile struct {
+	// Active is the ID of the key to encrypt with. If this is empty, the
+	// records are decrypted and written as plaintext, which disables
+	// encryption.
+	Active string `json:"active"`
+
+	// Keys are the base64 encoded 32 byte keys by ID.
+	Keys map[string]string `json:"keys"`
+}
+
+// NewKeyring returns a keyring with the given keys by ID. New records are
+// encrypted with the active key, which must be one of keys. If active is
+// empty, records are only decrypted.
+func NewKeyring(active string, keys map[string][]byte) (*Keyring, error) {
+	k := &Keyring{active: active, keys: map[string]cipher.AEAD{}}
+	for id, key := range keys {
+		if id == "" || len(id) > 255 {
+			return nil, fmt.Errorf("keyring key IDs must be 1 to 255 characters")
+		}
+		if len(key) != keyringKeySize {
+			return nil, fmt.Errorf("keyring key %q must be %d bytes", id, keyringKeySize)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		k.keys[id] = aead
+	}
+
+	if active != "" {
+		if _, ok := k.keys[active]; !ok {
+			return nil, fmt.Errorf("active keyring key %q doesn't exist", active)
+		}
+	}
+
+	return k, nil
+}
+
+// LoadKeyring loads a keyring from the JSON file at path. The file has the
+// ID of the active key and the base64 encoded keys by ID:
+//
+//	{"active": "2", "keys": {"1": "...", "2": "..."}}
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f keyringFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("error parsing keyring %s: %s", path, err)
+	}
+
+	keys := map[string][]byte{}
+	for id, v := range f.Keys {
+		key, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding keyring key %q: %s", id, err)
+		}
+
+		keys[id] = key
+	}
+
+	return NewKeyring(f.Active, keys)
+}
+
+// Active returns the ID of the key that new records are encrypted with.
+func (k *Keyring) Active() string {
+	if k == nil {
+		return ""
+	}
+
+	return k.active
+}
+
+// encrypt encrypts the value of key in bucket with the active key. The
+// bucket and key are authenticated so that the value can't be moved to
+// another record. The value is returned as-is if there is no active key.
+func (k *Keyring) encrypt(bucket, key, value []byte) ([]byte, error) {
+	if k.Active() == "" {
+		return value, nil
+	}
+
+	aead := k.keys[k.active]
+	result := make([]byte, 0, 2+len(k.active)+aead.NonceSize()+len(value)+aead.Overhead())
+	result = append(result, encryptedPrefix, byte(len(k.active)))
+	result = append(result, k.active...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	result = append(result, nonce...)
+
+	return aead.Seal(result, nonce, value, encryptedData(bucket, key)), nil
+}
+
+// decrypt decrypts the value of key in bucket. Values that aren't
+// encrypted are returned as-is.
+func (k *Keyring) decrypt(bucket, key, value []byte) ([]byte, error) {
+	id, ok := encryptedKeyId(value)
+	if !ok {
+		return value, nil
+	}
+	if k == nil {
+		return nil, fmt.Errorf(
+			"record %q in bucket %q is encrypted but no keyring is configured", key, bucket)
+	}
+
+	aead, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf(
+			"record %q in bucket %q is encrypted with key %q which isn't in the keyring",
+			key, bucket, id)
+	}
+
+	data := value[2+len(id):]
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("record %q in bucket %q is truncated", key, bucket)
+	}
+
+	result, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], encryptedData(bucket, key))
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting record %q in bucket %q: %s", key, bucket, err)
+	}
+
+	return result, nil
+}
+
+// encryptedKeyId returns the ID of the key a value is encrypted with. This
+// returns false if the value isn't encrypted.
+func encryptedKeyId(value []byte) (string, bool) {
+	if len(value) < 2 || value[0] != encryptedPrefix || len(value) < 2+int(value[1]) {
+		return "", false
+	}
+
+	return string(value[2 : 2+int(value[1])]), true
+}
+
+// encryptedData returns the additional data that is authenticated with the
+// value of a record.
+func encryptedData(bucket, key []byte) []byte {
+	result := make([]byte, 0, len(bucket)+1+len(key))
+	result = append(result, bucket...)
+	result = append(result, 0)
+	return append(result, key...)
+}
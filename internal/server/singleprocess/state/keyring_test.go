@@ -0,0 +1,110 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestKeyring(t *testing.T) {
+	require := require.New(t)
+
+	key1 := bytes.Repeat([]byte{1}, keyringKeySize)
+	key2 := bytes.Repeat([]byte{2}, keyringKeySize)
+	k1, err := NewKeyring("1", map[string][]byte{"1": key1})
+	require.NoError(err)
+
+	db := testDB(t)
+	s, err := New(hclog.L(), db, WithKeyring(k1))
+	require.NoError(err)
+	require.NoError(s.ConfigSet(&pb.ConfigVar{
+		Scope: &pb.ConfigVar_Project{
+			Project: &pb.Ref_Project{Project: "foo"},
+		},
+
+		Name:  "foo",
+		Value: "secret",
+	}))
+
+	// rawKeyIds returns the IDs of the keys the config vars are encrypted
+	// with, and fails if a value is stored as plaintext.
+	rawKeyIds := func() []string {
+		var result []string
+		require.NoError(db.View(func(dbTxn Tx) error {
+			return dbTxn.Bucket(configBucket).ForEach(func(k, v []byte) error {
+				id, _ := encryptedKeyId(v)
+				require.Equal(id == "", bytes.Contains(v, []byte("secret")))
+				result = append(result, id)
+				return nil
+			})
+		}))
+
+		return result
+	}
+	configValue := func(s *State) string {
+		vs, err := s.ConfigGet(&pb.ConfigGetRequest{
+			Scope: &pb.ConfigGetRequest_Project{
+				Project: &pb.Ref_Project{Project: "foo"},
+			},
+		})
+		require.NoError(err)
+		require.Len(vs, 1)
+		return vs[0].Value
+	}
+	require.Equal([]string{"1"}, rawKeyIds())
+	require.Equal("secret", configValue(s))
+
+	// The state can't be loaded without the key
+	_, err = New(hclog.L(), db)
+	require.Error(err)
+	k2, err := NewKeyring("2", map[string][]byte{"2": key2})
+	require.NoError(err)
+	_, err = New(hclog.L(), db, WithKeyring(k2))
+	require.Error(err)
+
+	// Rotating the key re-encrypts the records
+	k12, err := NewKeyring("2", map[string][]byte{"1": key1, "2": key2})
+	require.NoError(err)
+	s, err = New(hclog.L(), db, WithKeyring(k12))
+	require.NoError(err)
+	require.Equal([]string{"2"}, rawKeyIds())
+	require.Equal("secret", configValue(s))
+
+	// The old key isn't needed anymore
+	s, err = New(hclog.L(), db, WithKeyring(k2))
+	require.NoError(err)
+	require.Equal("secret", configValue(s))
+
+	// Without an active key the records are decrypted
+	kd, err := NewKeyring("", map[string][]byte{"2": key2})
+	require.NoError(err)
+	s, err = New(hclog.L(), db, WithKeyring(kd))
+	require.NoError(err)
+	require.Equal([]string{""}, rawKeyIds())
+	s, err = New(hclog.L(), db)
+	require.NoError(err)
+	require.Equal("secret", configValue(s))
+}
+
+func TestKeyring_recordMoved(t *testing.T) {
+	require := require.New(t)
+
+	k, err := NewKeyring("1", map[string][]byte{"1": bytes.Repeat([]byte{1}, keyringKeySize)})
+	require.NoError(err)
+
+	v, err := k.encrypt([]byte("config"), []byte("a"), []byte("value"))
+	require.NoError(err)
+	plain, err := k.decrypt([]byte("config"), []byte("a"), v)
+	require.NoError(err)
+	require.Equal([]byte("value"), plain)
+
+	// The value can't be read as another record
+	_, err = k.decrypt([]byte("config"), []byte("b"), v)
+	require.Error(err)
+	_, err = k.decrypt([]byte("token"), []byte("a"), v)
+	require.Error(err)
+}
@@ -0,0 +1,156 @@
+package state
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-memdb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// This file has the methods used when multiple servers share the same
+// SharedDB. Only one server is the leader at a time. The leader runs the
+// duties that must only happen once, like job assignment and the timers
+// that requeue or cancel jobs. Every server serves reads, and rebuilds its
+// in-memory indexes with Reindex when another server writes.
+
+// ErrNotLeader is returned by operations that can only run on the leader.
+var ErrNotLeader = status.Errorf(codes.Unavailable,
+	"this server is not the leader, please retry against the leader")
+
+// leaderState tracks whether the State is the leader. It is embedded in
+// State so the zero value must be usable.
+type leaderState struct {
+	// leader is 1 if this is the leader. This is 1 in New since a server
+	// that doesn't share its DB is always the leader.
+	leader uint32
+
+	// leaderCh is closed and replaced whenever leadership changes so that
+	// blocking queries can notice.
+	leaderCh   chan struct{}
+	leaderLock sync.Mutex
+
+	// reindexLock ensures only one Reindex runs at a time.
+	reindexLock sync.Mutex
+}
+
+// leaderAddrKey is the key in the server config bucket of the address of
+// the leader.
+var leaderAddrKey = []byte("leader-addr")
+
+// LeaderAddrSet writes the address that other servers send clients to
+// when only the leader can serve them. The leader sets this each time it
+// is elected.
+func (s *State) LeaderAddrSet(addr string) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		return dbTxn.Bucket(serverConfigBucket).Put(leaderAddrKey, []byte(addr))
+	})
+}
+
+// LeaderAddrGet gets the address of the leader. This is empty if the
+// leader has no address.
+func (s *State) LeaderAddrGet() (string, error) {
+	var result string
+	err := s.db.View(func(dbTxn Tx) error {
+		result = string(dbTxn.Bucket(serverConfigBucket).Get(leaderAddrKey))
+		return nil
+	})
+
+	return result, err
+}
+
+// IsLeader returns true if this server is the leader.
+func (s *State) IsLeader() bool {
+	return atomic.LoadUint32(&s.leader) == 1
+}
+
+// SetLeader sets whether this server is the leader.
+func (s *State) SetLeader(v bool) {
+	var value uint32
+	if v {
+		value = 1
+	}
+
+	if atomic.SwapUint32(&s.leader, value) == value {
+		// No change
+		return
+	}
+
+	s.leaderLock.Lock()
+	defer s.leaderLock.Unlock()
+	if s.leaderCh != nil {
+		close(s.leaderCh)
+	}
+	s.leaderCh = make(chan struct{})
+}
+
+// leaderWatch adds a watch to ws that triggers when leadership changes.
+func (s *State) leaderWatch(ws memdb.WatchSet) {
+	s.leaderLock.Lock()
+	defer s.leaderLock.Unlock()
+	if s.leaderCh == nil {
+		s.leaderCh = make(chan struct{})
+	}
+
+	ws.Add(s.leaderCh)
+}
+
+// leaderFunc wraps f so that it only runs if this server is the leader
+// when it is called. This is used for timers that modify jobs, since the
+// timers are created on every server when the jobs are indexed.
+func (s *State) leaderFunc(f func()) func() {
+	return func() {
+		if s.IsLeader() {
+			f()
+		}
+	}
+}
+
+// Reindex rebuilds the in-memory indexes from the persisted DB. This must
+// be called when another server may have written to the DB, since the
+// indexes are otherwise only updated by writes from this server. Tables
+// that only exist in memory, such as runners, are kept.
+func (s *State) Reindex() error {
+	s.reindexLock.Lock()
+	defer s.reindexLock.Unlock()
+
+	memTxn := s.inmem.Txn(true)
+	defer memTxn.Abort()
+
+	// Stop the timers of the jobs we're replacing, they'll be recreated
+	// by the job indexer.
+	iter, err := memTxn.Get(jobTableName, jobIdIndexName+"_prefix", "")
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		raw.(*jobIndex).End()
+	}
+
+	for name := range stateStoreSchema().Tables {
+		if _, ok := memOnlyTables[name]; ok {
+			continue
+		}
+
+		if _, err := memTxn.DeleteAll(name, "id"); err != nil {
+			return err
+		}
+	}
+
+	err = s.db.View(func(dbTxn Tx) error {
+		for _, indexer := range dbIndexers {
+			if err := indexer(s, dbTxn, memTxn); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	memTxn.Commit()
+	return nil
+}
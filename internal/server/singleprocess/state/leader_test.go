@@ -0,0 +1,102 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestReindex(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// A second state sharing the same DB, like another server
+	other, err := New(hclog.L(), s.db)
+	require.NoError(err)
+	defer other.Close()
+
+	// Register a runner, which is only in memory
+	runner := serverptypes.TestRunner(t, nil)
+	require.NoError(other.RunnerCreate(runner))
+
+	// Write with the first state
+	require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+		Name: "foo",
+	})))
+	require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "A",
+	})))
+
+	// The other state doesn't see it until it reindexes
+	list, err := other.ProjectList()
+	require.NoError(err)
+	require.Len(list, 0)
+
+	require.NoError(other.Reindex())
+
+	list, err = other.ProjectList()
+	require.NoError(err)
+	require.Len(list, 1)
+
+	job, err := other.JobById("A", nil)
+	require.NoError(err)
+	require.NotNil(job)
+	require.Equal(pb.Job_QUEUED, job.State)
+
+	// The runner is kept
+	found, err := other.RunnerById(runner.Id)
+	require.NoError(err)
+	require.NotNil(found)
+}
+
+func TestJobAssign_notLeader(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	require.True(s.IsLeader())
+	s.SetLeader(false)
+
+	require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{
+		Id: "A",
+	})))
+
+	// Followers don't assign jobs
+	_, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+	require.Equal(ErrNotLeader, err)
+
+	// A blocked assignment returns when leadership is lost
+	s.SetLeader(true)
+	job, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+	require.NoError(err)
+	require.Equal("A", job.Id)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := s.JobAssignForRunner(context.Background(), &pb.Runner{Id: "R_A"})
+		errCh <- err
+	}()
+
+	select {
+	case <-errCh:
+		t.Fatal("should block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.SetLeader(false)
+	select {
+	case err := <-errCh:
+		require.Equal(ErrNotLeader, err)
+	case <-time.After(time.Second):
+		t.Fatal("should return after losing leadership")
+	}
+}
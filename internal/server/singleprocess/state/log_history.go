@@ -0,0 +1,517 @@
+package state
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/waypoint/internal/pkg/structlog"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var logHistoryBucket = []byte("log_history")
+
+const (
+	logHistoryTableName           = "log-history"
+	logHistoryIdIndexName         = "id"
+	logHistoryDeploymentIndexName = "deployment"
+	logHistoryAppIndexName        = "app"
+	logHistoryTimeIndexName       = "time"
+)
+
+func init() {
+	dbBuckets = append(dbBuckets, logHistoryBucket)
+	dbIndexers = append(dbIndexers, (*State).logHistoryIndexInit)
+	schemas = append(schemas, logHistorySchema)
+
+	// Lines that aren't persisted only exist in the memory of the server
+	// that the entrypoint is connected to.
+	memOnlyTables[logHistoryTableName] = struct{}{}
+}
+
+// LogHistoryConfig configures how many log lines are kept for each
+// deployment after they're streamed from the entrypoint.
+type LogHistoryConfig struct {
+	// MaxLines is the maximum number of lines kept for each deployment.
+	// The oldest lines are removed once there are more. If this is zero,
+	// no lines are kept.
+	MaxLines int
+
+	// Persist writes the lines to the database as well, so that they're
+	// kept when the server restarts.
+	Persist bool
+}
+
+// SetLogHistory sets the configuration for keeping log lines. This must
+// be called before any lines are appended.
+func (s *State) SetLogHistory(cfg LogHistoryConfig) {
+	s.logHistory = cfg
+}
+
+// LogHistoryAppend keeps the log lines of an instance. If the lines are
+// persisted, they're written to the database by the next
+// LogHistoryFlush so that each batch of lines doesn't need a write.
+func (s *State) LogHistoryAppend(inst *Instance, entries []*pb.LogBatch_Entry) error {
+	if s.logHistory.MaxLines <= 0 || len(entries) == 0 {
+		return nil
+	}
+
+	memTxn := s.inmem.Txn(true)
+	defer memTxn.Abort()
+
+	c := s.logHistoryChange()
+	if err := s.logHistoryAppend(memTxn, c, inst.LogLines(entries)); err != nil {
+		return err
+	}
+
+	s.logHistoryApply(c)
+	memTxn.Commit()
+	return nil
+}
+
+// LogHistoryFlush writes the changes to the persisted log lines since the
+// last flush to the database in one transaction. If this fails, the
+// changes are kept for the next flush.
+func (s *State) LogHistoryFlush() error {
+	s.logHistoryLock.Lock()
+	ops := s.logHistoryPending
+	s.logHistoryPending = nil
+	s.logHistoryLock.Unlock()
+	if len(ops) == 0 {
+		return nil
+	}
+
+	err := s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(logHistoryBucket)
+		for _, op := range ops {
+			var err error
+			if op.line == nil {
+				err = b.Delete(op.id)
+			} else {
+				err = dbPut(b, op.id, op.line)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		// Put the changes back before the ones made since, so that they
+		// are written in order.
+		s.logHistoryLock.Lock()
+		s.logHistoryPending = append(ops, s.logHistoryPending...)
+		s.logHistoryLock.Unlock()
+	}
+
+	return err
+}
+
+// LogLines returns the log entries of the instance as lines with the
+// deployment, application and workspace they belong to.
+func (i *Instance) LogLines(entries []*pb.LogBatch_Entry) []*pb.GetLogsResponse_Line {
+	result := make([]*pb.GetLogsResponse_Line, len(entries))
+	for j, entry := range entries {
+		result[j] = &pb.GetLogsResponse_Line{
+			DeploymentId: i.DeploymentId,
+			InstanceId:   i.Id,
+			Entry:        entry,
+			Application: &pb.Ref_Application{
+				Project:     i.Project,
+				Application: i.Application,
+			},
+			Workspace: &pb.Ref_Workspace{Workspace: i.Workspace},
+		}
+	}
+
+	return result
+}
+
+// LogHistoryList returns the log lines that match the request, oldest
+// first. If there are more than limit lines, the latest are returned and
+// the result is marked as truncated. The level of the request must be
+// normalized with structlog.ParseLevel.
+func (s *State) LogHistoryList(req *pb.GetLogsRequest, limit int) ([]*pb.GetLogsResponse_Line, bool, error) {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	after := time.Unix(0, 0)
+	var before time.Time
+	if r := req.TimeRange; r != nil {
+		if r.After != nil {
+			if t, err := ptypes.Timestamp(r.After); err == nil && t.After(after) {
+				after = t
+			}
+		}
+		if r.Before != nil {
+			if t, err := ptypes.Timestamp(r.Before); err == nil {
+				before = t
+			}
+		}
+	}
+
+	var iter memdb.ResultIterator
+	var match func(*logHistoryRecord) bool
+	var err error
+	switch scope := req.Scope.(type) {
+	case *pb.GetLogsRequest_DeploymentId:
+		iter, err = memTxn.LowerBound(logHistoryTableName, logHistoryDeploymentIndexName,
+			scope.DeploymentId, after)
+		match = func(rec *logHistoryRecord) bool {
+			return strings.EqualFold(rec.DeploymentId, scope.DeploymentId)
+		}
+
+	case *pb.GetLogsRequest_Application:
+		app := scope.Application.GetApplication()
+		ws := scope.Application.GetWorkspace()
+		if app == nil || ws == nil {
+			return nil, false, nil
+		}
+
+		iter, err = memTxn.LowerBound(logHistoryTableName, logHistoryAppIndexName,
+			app.Project, app.Application, ws.Workspace, after)
+		match = func(rec *logHistoryRecord) bool {
+			return strings.EqualFold(rec.Project, app.Project) &&
+				strings.EqualFold(rec.App, app.Application) &&
+				strings.EqualFold(rec.Workspace, ws.Workspace)
+		}
+
+	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var result []*pb.GetLogsResponse_Line
+	truncated := false
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rec := raw.(*logHistoryRecord)
+		if !match(rec) || (!before.IsZero() && !rec.Time.Before(before)) {
+			break
+		}
+		if req.Level != "" && !structlog.AtLeast(rec.Line.Entry.GetLevel(), req.Level) {
+			continue
+		}
+
+		result = append(result, rec.Line)
+		if limit > 0 && len(result) > limit {
+			result = result[1:]
+			truncated = true
+		}
+	}
+
+	return result, truncated, nil
+}
+
+// LogHistoryPrune removes the log lines that were logged before the given
+// time and returns how many were removed. The persisted lines are removed
+// from the database as well.
+func (s *State) LogHistoryPrune(before time.Time) (int, error) {
+	memTxn := s.inmem.Txn(true)
+	defer memTxn.Abort()
+
+	iter, err := memTxn.LowerBound(logHistoryTableName, logHistoryTimeIndexName, time.Unix(0, 0))
+	if err != nil {
+		return 0, err
+	}
+
+	// We can't delete while we iterate over the index, so we collect
+	// the records first.
+	var recs []*logHistoryRecord
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rec := raw.(*logHistoryRecord)
+		if !rec.Time.Before(before) {
+			break
+		}
+
+		recs = append(recs, rec)
+	}
+
+	c := s.logHistoryChange()
+	for _, rec := range recs {
+		if err := s.logHistoryDelete(memTxn, c, rec); err != nil {
+			return 0, err
+		}
+	}
+
+	s.logHistoryApply(c)
+	memTxn.Commit()
+
+	return len(recs), s.LogHistoryFlush()
+}
+
+func (s *State) logHistoryAppend(memTxn *memdb.Txn, c *logHistoryChange, lines []*pb.GetLogsResponse_Line) error {
+	for _, line := range lines {
+		c.seq++
+		id := logHistoryId(c.seq)
+		if s.logHistory.Persist {
+			c.ops = append(c.ops, logHistoryOp{id: id, line: line})
+		}
+
+		if err := s.logHistoryIndexSet(memTxn, c, id, line, s.logHistory.Persist); err != nil {
+			return err
+		}
+
+		// Remove the oldest lines of the deployment if it has too many.
+		// This can be more than one if the limit was lowered since the
+		// persisted lines were loaded.
+		for c.count(s, line.DeploymentId) > s.logHistory.MaxLines {
+			iter, err := memTxn.LowerBound(logHistoryTableName, logHistoryDeploymentIndexName,
+				line.DeploymentId, time.Unix(0, 0))
+			if err != nil {
+				return err
+			}
+
+			raw := iter.Next()
+			if raw == nil {
+				break
+			}
+
+			if err := s.logHistoryDelete(memTxn, c, raw.(*logHistoryRecord)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// logHistoryDelete removes a line. It is removed from the database as
+// well if it was persisted.
+func (s *State) logHistoryDelete(memTxn *memdb.Txn, c *logHistoryChange, rec *logHistoryRecord) error {
+	if err := memTxn.Delete(logHistoryTableName, rec); err != nil {
+		return err
+	}
+
+	if rec.Persisted {
+		c.ops = append(c.ops, logHistoryOp{id: logHistoryId(rec.Seq)})
+	}
+	c.counts[rec.DeploymentId]--
+	return nil
+}
+
+// logHistoryIndexSet writes an index record for a line. persisted is true
+// if the line is in the database.
+func (s *State) logHistoryIndexSet(
+	txn *memdb.Txn,
+	c *logHistoryChange,
+	id []byte,
+	line *pb.GetLogsResponse_Line,
+	persisted bool,
+) error {
+	t, err := ptypes.Timestamp(line.Entry.GetTimestamp())
+	if err != nil {
+		t = time.Now()
+	}
+
+	seq := binary.BigEndian.Uint64(id)
+	rec := &logHistoryRecord{
+		Id:           fmt.Sprintf("%016x", seq),
+		Seq:          seq,
+		DeploymentId: line.DeploymentId,
+		Project:      line.Application.GetProject(),
+		App:          line.Application.GetApplication(),
+		Workspace:    line.Workspace.GetWorkspace(),
+		Time:         t,
+		Line:         line,
+		Persisted:    persisted,
+	}
+
+	// Lines that are indexed again, such as in Reindex, are only counted
+	// once.
+	existing, err := txn.First(logHistoryTableName, logHistoryIdIndexName, rec.Id)
+	if err != nil {
+		return err
+	}
+	if err := txn.Insert(logHistoryTableName, rec); err != nil {
+		return err
+	}
+	if existing == nil {
+		c.counts[line.DeploymentId]++
+	}
+
+	return nil
+}
+
+// logHistoryIndexInit initializes the log history index from the
+// persisted lines.
+func (s *State) logHistoryIndexInit(dbTxn Tx, memTxn *memdb.Txn) error {
+	c := s.logHistoryChange()
+	bucket := dbTxn.Bucket(logHistoryBucket)
+	err := bucket.ForEach(func(k, v []byte) error {
+		var line pb.GetLogsResponse_Line
+		if err := proto.Unmarshal(v, &line); err != nil {
+			return err
+		}
+
+		if seq := binary.BigEndian.Uint64(k); seq > c.seq {
+			c.seq = seq
+		}
+
+		return s.logHistoryIndexSet(memTxn, c, k, &line, true)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logHistoryApply(c)
+	return nil
+}
+
+// logHistoryChange collects the changes of a memdb write transaction to
+// the counts and the persisted lines. They're applied to the State with
+// logHistoryApply once the transaction can't fail anymore, so a failed
+// transaction leaves the counts as they were.
+type logHistoryChange struct {
+	seq    uint64
+	counts map[string]int
+	ops    []logHistoryOp
+}
+
+// logHistoryOp is a pending write of a persisted line. line is nil if the
+// line is deleted.
+type logHistoryOp struct {
+	id   []byte
+	line *pb.GetLogsResponse_Line
+}
+
+// logHistoryChange starts a change. This must be called with a memdb write
+// transaction open.
+func (s *State) logHistoryChange() *logHistoryChange {
+	return &logHistoryChange{
+		seq:    s.logHistorySeq,
+		counts: map[string]int{},
+	}
+}
+
+// count returns the number of lines of the deployment with the change.
+func (c *logHistoryChange) count(s *State, deploymentId string) int {
+	return s.logHistoryCounts[deploymentId] + c.counts[deploymentId]
+}
+
+// logHistoryApply applies a change to the State. This must be called
+// before the memdb write transaction of the change is committed.
+func (s *State) logHistoryApply(c *logHistoryChange) {
+	if s.logHistoryCounts == nil {
+		s.logHistoryCounts = map[string]int{}
+	}
+	for id, delta := range c.counts {
+		s.logHistoryCounts[id] += delta
+		if s.logHistoryCounts[id] <= 0 {
+			delete(s.logHistoryCounts, id)
+		}
+	}
+	if c.seq > s.logHistorySeq {
+		s.logHistorySeq = c.seq
+	}
+
+	if len(c.ops) > 0 {
+		s.logHistoryLock.Lock()
+		s.logHistoryPending = append(s.logHistoryPending, c.ops...)
+		s.logHistoryLock.Unlock()
+	}
+}
+
+// logHistoryId returns the database key of a line.
+func logHistoryId(seq uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], seq)
+	return buf[:]
+}
+
+func logHistorySchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: logHistoryTableName,
+		Indexes: map[string]*memdb.IndexSchema{
+			logHistoryIdIndexName: &memdb.IndexSchema{
+				Name:         logHistoryIdIndexName,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Id",
+				},
+			},
+
+			logHistoryDeploymentIndexName: &memdb.IndexSchema{
+				Name:         logHistoryDeploymentIndexName,
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field:     "DeploymentId",
+							Lowercase: true,
+						},
+
+						&IndexTime{
+							Field: "Time",
+							Asc:   true,
+						},
+					},
+				},
+			},
+
+			logHistoryAppIndexName: &memdb.IndexSchema{
+				Name:         logHistoryAppIndexName,
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field:     "Project",
+							Lowercase: true,
+						},
+
+						&memdb.StringFieldIndex{
+							Field:     "App",
+							Lowercase: true,
+						},
+
+						&memdb.StringFieldIndex{
+							Field:     "Workspace",
+							Lowercase: true,
+						},
+
+						&IndexTime{
+							Field: "Time",
+							Asc:   true,
+						},
+					},
+				},
+			},
+
+			logHistoryTimeIndexName: &memdb.IndexSchema{
+				Name:         logHistoryTimeIndexName,
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &IndexTime{
+					Field: "Time",
+					Asc:   true,
+				},
+			},
+		},
+	}
+}
+
+// logHistoryRecord is the index record of a line. Id is the hex encoded
+// Seq so that lines logged at the same time are ordered as they arrived.
+type logHistoryRecord struct {
+	Id           string
+	Seq          uint64
+	DeploymentId string
+	Project      string
+	App          string
+	Workspace    string
+	Time         time.Time
+	Line         *pb.GetLogsResponse_Line
+
+	// Persisted is true if the line is in the database.
+	Persisted bool
+}
@@ -0,0 +1,142 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestLogHistory(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+	s.SetLogHistory(LogHistoryConfig{MaxLines: 3})
+
+	inst := &Instance{
+		Id:           "I",
+		DeploymentId: "A",
+		Project:      "p",
+		Application:  "a",
+		Workspace:    "default",
+	}
+
+	start := time.Now().Add(-time.Hour)
+	var entries []*pb.LogBatch_Entry
+	for i := 0; i < 5; i++ {
+		ts, err := ptypes.TimestampProto(start.Add(time.Duration(i) * time.Minute))
+		require.NoError(err)
+		entries = append(entries, &pb.LogBatch_Entry{
+			Timestamp: ts,
+			Line:      string('a' + rune(i)),
+		})
+	}
+	require.NoError(s.LogHistoryAppend(inst, entries))
+
+	// Only the latest lines of the deployment are kept
+	lines, truncated, err := s.LogHistoryList(&pb.GetLogsRequest{
+		Scope: &pb.GetLogsRequest_DeploymentId{DeploymentId: "A"},
+	}, 0)
+	require.NoError(err)
+	require.False(truncated)
+	require.Len(lines, 3)
+	require.Equal("c", lines[0].Entry.Line)
+	require.Equal("e", lines[2].Entry.Line)
+
+	// Limit keeps the latest lines in the range
+	before, err := ptypes.TimestampProto(start.Add(4 * time.Minute))
+	require.NoError(err)
+	lines, truncated, err = s.LogHistoryList(&pb.GetLogsRequest{
+		Scope: &pb.GetLogsRequest_Application{
+			Application: &pb.GetLogStreamRequest_Application{
+				Application: &pb.Ref_Application{Project: "p", Application: "a"},
+				Workspace:   &pb.Ref_Workspace{Workspace: "default"},
+			},
+		},
+		TimeRange: &pb.TimeRange{Before: before},
+	}, 1)
+	require.NoError(err)
+	require.True(truncated)
+	require.Len(lines, 1)
+	require.Equal("d", lines[0].Entry.Line)
+
+	// Prune the lines older than the last one
+	n, err := s.LogHistoryPrune(start.Add(4 * time.Minute))
+	require.NoError(err)
+	require.Equal(2, n)
+
+	lines, _, err = s.LogHistoryList(&pb.GetLogsRequest{
+		Scope: &pb.GetLogsRequest_DeploymentId{DeploymentId: "A"},
+	}, 0)
+	require.NoError(err)
+	require.Len(lines, 1)
+}
+
+func TestLogHistory_persist(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+	s.SetLogHistory(LogHistoryConfig{MaxLines: 3, Persist: true})
+
+	inst := &Instance{
+		Id:           "I",
+		DeploymentId: "A",
+		Project:      "p",
+		Application:  "a",
+		Workspace:    "default",
+	}
+
+	start := time.Now().Add(-time.Hour)
+	var entries []*pb.LogBatch_Entry
+	for i := 0; i < 5; i++ {
+		ts, err := ptypes.TimestampProto(start.Add(time.Duration(i) * time.Minute))
+		require.NoError(err)
+		entries = append(entries, &pb.LogBatch_Entry{
+			Timestamp: ts,
+			Line:      string('a' + rune(i)),
+		})
+	}
+	require.NoError(s.LogHistoryAppend(inst, entries))
+
+	req := &pb.GetLogsRequest{
+		Scope: &pb.GetLogsRequest_DeploymentId{DeploymentId: "A"},
+	}
+
+	// The lines aren't in the database until they're flushed
+	{
+		s2 := TestStateReinit(t, s)
+		defer s2.Close()
+		lines, _, err := s2.LogHistoryList(req, 0)
+		require.NoError(err)
+		require.Empty(lines)
+	}
+
+	require.NoError(s.LogHistoryFlush())
+
+	// Reindexing counts each line once
+	s2 := TestStateReinit(t, s)
+	defer s2.Close()
+	lines, _, err := s2.LogHistoryList(req, 0)
+	require.NoError(err)
+	require.Len(lines, 3)
+	require.Equal("c", lines[0].Entry.Line)
+	require.Equal(3, s2.logHistoryCounts["A"])
+
+	// Pruning removes the lines from the database
+	n, err := s.LogHistoryPrune(start.Add(4 * time.Minute))
+	require.NoError(err)
+	require.Equal(2, n)
+	require.Equal(1, s.logHistoryCounts["A"])
+
+	s3 := TestStateReinit(t, s)
+	defer s3.Close()
+	lines, _, err = s3.LogHistoryList(req, 0)
+	require.NoError(err)
+	require.Len(lines, 1)
+	require.Equal("e", lines[0].Entry.Line)
+}
@@ -0,0 +1,331 @@
+package state
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/boltdb/bolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// boltCompactTxSize is the amount of data after which the new file is
+// committed while it is written during compaction, so that the copy
+// doesn't have to fit in memory.
+const boltCompactTxSize = 64 * 1024 * 1024
+
+// MaintainedDB is implemented by a DB that is stored in a local file and
+// can be checked and compacted while the server runs. Other databases,
+// such as PostgreSQL, are maintained by their own tools.
+type MaintainedDB interface {
+	DB
+
+	// Check verifies the integrity of the database and returns the
+	// problems that were found.
+	Check() ([]string, error)
+
+	// Size returns the size of the database file and how much of it is
+	// free space left by deleted records.
+	Size() (size, free int64, err error)
+
+	// Compact rewrites the database into a new file that has no free
+	// space, and replaces the database with it. Transactions wait while
+	// the database is compacted.
+	Compact() error
+}
+
+// DBCheck verifies the integrity of the database. It checks the structure
+// of the database file and that every bucket of the state exists.
+func (s *State) DBCheck() ([]string, error) {
+	db, err := s.maintainedDB()
+	if err != nil {
+		return nil, err
+	}
+
+	problems, err := db.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.View(func(dbTxn Tx) error {
+		for _, b := range dbBuckets {
+			if dbTxn.Bucket(b) == nil {
+				problems = append(problems, fmt.Sprintf("bucket %q is missing", b))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return problems, nil
+}
+
+// DBSize returns the size of the database file and how much of it is free.
+func (s *State) DBSize() (int64, int64, error) {
+	db, err := s.maintainedDB()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return db.Size()
+}
+
+// DBCompact compacts the database to reclaim the free space.
+func (s *State) DBCompact() error {
+	db, err := s.maintainedDB()
+	if err != nil {
+		return err
+	}
+
+	return db.Compact()
+}
+
+func (s *State) maintainedDB() (MaintainedDB, error) {
+	db, ok := s.rawDB().(MaintainedDB)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented,
+			"the database of the server doesn't support maintenance")
+	}
+
+	return db, nil
+}
+
+func (b *boltDB) Check() ([]string, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	var problems []string
+	err := b.db.View(func(tx *bolt.Tx) error {
+		for err := range tx.Check() {
+			problems = append(problems, err.Error())
+		}
+
+		return nil
+	})
+
+	return problems, err
+}
+
+func (b *boltDB) Size() (int64, int64, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	fi, err := os.Stat(b.db.Path())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// The file is larger than the data since it grows in steps, and the
+	// data includes the pages of deleted records that are free for reuse.
+	var used int64
+	err = b.db.View(func(tx *bolt.Tx) error {
+		used = tx.Size() - int64(b.db.Stats().FreeAlloc)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	free := fi.Size() - used
+	if free < 0 {
+		free = 0
+	}
+
+	return fi.Size(), free, nil
+}
+
+func (b *boltDB) Compact() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	path := b.db.Path()
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	// Write the new file next to the database so that it can be renamed
+	// over it.
+	tmpPath := path + ".compact"
+	os.Remove(tmpPath)
+	dst, err := bolt.Open(tmpPath, fi.Mode(), nil)
+	if err != nil {
+		return err
+	}
+	if err := boltCopy(dst, b.db); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// Check the new file before the database is replaced with it. Until
+	// then the database is left as it is if anything fails.
+	if err := boltVerify(tmpPath, fi.Mode()); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("compacted database is invalid: %w", err)
+	}
+
+	// Keep a link to the original file so that it can be put back if the
+	// new file can't be opened once it is in place.
+	oldPath := path + ".old"
+	os.Remove(oldPath)
+	if err := os.Link(path, oldPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	defer os.Remove(oldPath)
+
+	if err := b.db.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// restore opens the original file again after a failure.
+	restore := func(cause error) error {
+		if err := os.Rename(oldPath, path); err != nil {
+			return fmt.Errorf("%s; error restoring the database: %s", cause, err)
+		}
+
+		db, err := bolt.Open(path, fi.Mode(), nil)
+		if err != nil {
+			return fmt.Errorf("%s; error opening the database again: %s", cause, err)
+		}
+		b.db = db
+
+		return cause
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return restore(err)
+	}
+
+	db, err := bolt.Open(path, fi.Mode(), nil)
+	if err != nil {
+		return restore(err)
+	}
+	b.db = db
+
+	return nil
+}
+
+// boltVerify opens the database at path and checks its integrity.
+func boltVerify(path string, mode os.FileMode) error {
+	db, err := bolt.Open(path, mode, nil)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.View(func(tx *bolt.Tx) error {
+		// The channel must be drained for the check to complete.
+		var result error
+		for err := range tx.Check() {
+			if result == nil {
+				result = err
+			}
+		}
+
+		return result
+	})
+}
+
+// boltCopy copies all the buckets of src to dst.
+func boltCopy(dst, src *bolt.DB) error {
+	return src.View(func(srcTx *bolt.Tx) error {
+		tx, err := dst.Begin(true)
+		if err != nil {
+			return err
+		}
+		defer func() { tx.Rollback() }()
+
+		size := 0
+		err = boltWalk(srcTx, func(keys [][]byte, k, v []byte, seq uint64) error {
+			// Commit in steps so that the copy isn't kept in memory.
+			size += len(k) + len(v)
+			if size > boltCompactTxSize {
+				if err := tx.Commit(); err != nil {
+					return err
+				}
+
+				tx, err = dst.Begin(true)
+				if err != nil {
+					return err
+				}
+				size = 0
+			}
+
+			// Top-level keys are always buckets.
+			if len(keys) == 0 {
+				b, err := tx.CreateBucket(k)
+				if err != nil {
+					return err
+				}
+
+				return b.SetSequence(seq)
+			}
+
+			b := tx.Bucket(keys[0])
+			for _, key := range keys[1:] {
+				b = b.Bucket(key)
+			}
+
+			if v == nil {
+				nb, err := b.CreateBucket(k)
+				if err != nil {
+					return err
+				}
+
+				return nb.SetSequence(seq)
+			}
+
+			return b.Put(k, v)
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
+
+// boltWalkFunc is called for every bucket and key of a database. keys is
+// the path of the bucket that contains k. v is nil if k is a bucket, in
+// which case seq is its sequence.
+type boltWalkFunc func(keys [][]byte, k, v []byte, seq uint64) error
+
+// boltWalk calls fn for every bucket and key of the database, parents
+// before their children.
+func boltWalk(tx *bolt.Tx, fn boltWalkFunc) error {
+	return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		return boltWalkBucket(b, nil, name, nil, b.Sequence(), fn)
+	})
+}
+
+func boltWalkBucket(b *bolt.Bucket, keys [][]byte, k, v []byte, seq uint64, fn boltWalkFunc) error {
+	if err := fn(keys, k, v, seq); err != nil {
+		return err
+	}
+	if v != nil {
+		return nil
+	}
+
+	keys = append(keys, k)
+	return b.ForEach(func(k, v []byte) error {
+		if v == nil {
+			nb := b.Bucket(k)
+			return boltWalkBucket(nb, keys, k, nil, nb.Sequence(), fn)
+		}
+
+		return boltWalkBucket(b, keys, k, v, 0, fn)
+	})
+}
+
+var _ MaintainedDB = (*boltDB)(nil)
@@ -0,0 +1,78 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestDBCompact(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// An intact database has no problems
+	problems, err := s.DBCheck()
+	require.NoError(err)
+	require.Empty(problems)
+
+	// Deleting records leaves free space
+	for i := 0; i < 500; i++ {
+		require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{
+			Name:         fmt.Sprintf("project-%d", i),
+			Organization: strings.Repeat("x", 4096),
+		})))
+	}
+	for i := 1; i < 500; i++ {
+		require.NoError(s.ProjectDelete(&pb.Ref_Project{Project: fmt.Sprintf("project-%d", i)}))
+	}
+	sizeBefore, freeBefore, err := s.DBSize()
+	require.NoError(err)
+	require.True(freeBefore > 0)
+
+	require.NoError(s.DBCompact())
+	sizeAfter, _, err := s.DBSize()
+	require.NoError(err)
+	require.True(sizeAfter < sizeBefore, "%d should be less than %d", sizeAfter, sizeBefore)
+
+	// The data is kept
+	_, err = s.ProjectGet(&pb.Ref_Project{Project: "project-0"})
+	require.NoError(err)
+	problems, err = s.DBCheck()
+	require.NoError(err)
+	require.Empty(problems)
+
+	// No files are left behind
+	path := s.rawDB().(*boltDB).db.Path()
+	for _, suffix := range []string{".compact", ".old"} {
+		_, err := os.Stat(path + suffix)
+		require.True(os.IsNotExist(err), suffix)
+	}
+}
+
+func TestDBCompact_failure(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+	require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{Name: "a"})))
+
+	// The new file can't be created where a directory is in the way
+	path := s.rawDB().(*boltDB).db.Path()
+	require.NoError(os.MkdirAll(filepath.Join(path+".compact", "x"), 0755))
+	defer os.RemoveAll(path + ".compact")
+	require.Error(s.DBCompact())
+
+	// The database is still open
+	_, err := s.ProjectGet(&pb.Ref_Project{Project: "a"})
+	require.NoError(err)
+	require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{Name: "b"})))
+}
@@ -0,0 +1,77 @@
+package state
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var notificationSinkBucket = []byte("notification_sink")
+
+func init() {
+	dbBuckets = append(dbBuckets, notificationSinkBucket)
+	encryptedBuckets = append(encryptedBuckets, notificationSinkBucket)
+}
+
+// NotificationSinkPut creates or updates a notification sink.
+func (s *State) NotificationSinkPut(sink *pb.NotificationSink) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		return dbPut(dbTxn.Bucket(notificationSinkBucket), []byte(sink.Id), sink)
+	})
+}
+
+// NotificationSinkGet gets a notification sink by ID.
+func (s *State) NotificationSinkGet(id string) (*pb.NotificationSink, error) {
+	var result pb.NotificationSink
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbGet(dbTxn.Bucket(notificationSinkBucket), []byte(id), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// NotificationSinkList returns the notification sinks in the order they
+// were created. If ref is set, only the sinks of that project are returned.
+func (s *State) NotificationSinkList(ref *pb.Ref_Project) ([]*pb.NotificationSink, error) {
+	var result []*pb.NotificationSink
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbTxn.Bucket(notificationSinkBucket).ForEach(func(k, v []byte) error {
+			var sink pb.NotificationSink
+			if err := proto.Unmarshal(v, &sink); err != nil {
+				return err
+			}
+
+			if ref != nil && !strings.EqualFold(sink.Project.GetProject(), ref.Project) {
+				return nil
+			}
+
+			result = append(result, &sink)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// NotificationSinkDelete deletes a notification sink. A NotFound error is
+// returned if the sink doesn't exist.
+func (s *State) NotificationSinkDelete(id string) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(notificationSinkBucket)
+		if b.Get([]byte(id)) == nil {
+			return status.Errorf(codes.NotFound, "notification sink not found: %s", id)
+		}
+
+		return b.Delete([]byte(id))
+	})
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var organizationBucket = []byte("organization")
+
+func init() {
+	dbBuckets = append(dbBuckets, organizationBucket)
+}
+
+// OrganizationPut creates or updates the given organization. Names are
+// case insensitive.
+func (s *State) OrganizationPut(o *pb.Organization) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		return dbPut(dbTxn.Bucket(organizationBucket), organizationId(o.Name), o)
+	})
+}
+
+// OrganizationGet gets an organization by name. If the organization
+// doesn't exist, a NotFound error is returned.
+func (s *State) OrganizationGet(name string) (*pb.Organization, error) {
+	var result pb.Organization
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbGet(dbTxn.Bucket(organizationBucket), organizationId(name), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// OrganizationList returns all the organizations.
+func (s *State) OrganizationList() ([]*pb.Organization, error) {
+	var result []*pb.Organization
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbTxn.Bucket(organizationBucket).ForEach(func(k, v []byte) error {
+			var o pb.Organization
+			if err := proto.Unmarshal(v, &o); err != nil {
+				return err
+			}
+
+			result = append(result, &o)
+			return nil
+		})
+	})
+
+	return result, err
+}
+
+// OrganizationDelete deletes an organization. A NotFound error is returned
+// if the organization doesn't exist.
+func (s *State) OrganizationDelete(name string) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(organizationBucket)
+		if b.Get(organizationId(name)) == nil {
+			return status.Errorf(codes.NotFound, "organization not found: %s", name)
+		}
+
+		return b.Delete(organizationId(name))
+	})
+}
+
+func organizationId(name string) []byte {
+	return []byte(strings.ToLower(name))
+}
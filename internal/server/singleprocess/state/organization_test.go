@@ -0,0 +1,43 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestOrganization(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Not found
+	_, err := s.OrganizationGet("red")
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+
+	// Put and Get, names are case insensitive
+	require.NoError(s.OrganizationPut(&pb.Organization{Name: "Red"}))
+	require.NoError(s.OrganizationPut(&pb.Organization{Name: "blue"}))
+
+	o, err := s.OrganizationGet("red")
+	require.NoError(err)
+	require.Equal("Red", o.Name)
+
+	// List
+	list, err := s.OrganizationList()
+	require.NoError(err)
+	require.Len(list, 2)
+
+	// Delete
+	require.NoError(s.OrganizationDelete("RED"))
+	_, err = s.OrganizationGet("red")
+	require.Equal(codes.NotFound, status.Code(err))
+	err = s.OrganizationDelete("red")
+	require.Equal(codes.NotFound, status.Code(err))
+}
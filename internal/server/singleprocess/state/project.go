@@ -3,7 +3,6 @@ package state
 import (
 	"strings"
 
-	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-memdb"
 	"google.golang.org/grpc/codes"
@@ -25,7 +24,7 @@ func (s *State) ProjectPut(p *pb.Project) error {
 	memTxn := s.inmem.Txn(true)
 	defer memTxn.Abort()
 
-	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+	err := s.db.Update(func(dbTxn Tx) error {
 		return s.projectPut(dbTxn, memTxn, p)
 	})
 	if err == nil {
@@ -41,7 +40,7 @@ func (s *State) ProjectGet(ref *pb.Ref_Project) (*pb.Project, error) {
 	defer memTxn.Abort()
 
 	var result *pb.Project
-	err := s.db.View(func(dbTxn *bolt.Tx) error {
+	err := s.db.View(func(dbTxn Tx) error {
 		var err error
 		result, err = s.projectGet(dbTxn, memTxn, ref)
 		return err
@@ -57,7 +56,7 @@ func (s *State) ProjectDelete(ref *pb.Ref_Project) error {
 	memTxn := s.inmem.Txn(true)
 	defer memTxn.Abort()
 
-	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+	err := s.db.Update(func(dbTxn Tx) error {
 		return s.projectDelete(dbTxn, memTxn, ref)
 	})
 	if err == nil {
@@ -75,7 +74,7 @@ func (s *State) ProjectList() ([]*pb.Ref_Project, error) {
 	return s.projectList(memTxn)
 }
 
-func (s *State) projectGetOrCreate(dbTxn *bolt.Tx, memTxn *memdb.Txn, ref *pb.Ref_Project) (*pb.Project, error) {
+func (s *State) projectGetOrCreate(dbTxn Tx, memTxn *memdb.Txn, ref *pb.Ref_Project) (*pb.Project, error) {
 	result, err := s.projectGet(dbTxn, memTxn, ref)
 	if status.Code(err) == codes.NotFound {
 		result = nil
@@ -96,7 +95,7 @@ func (s *State) projectGetOrCreate(dbTxn *bolt.Tx, memTxn *memdb.Txn, ref *pb.Re
 }
 
 func (s *State) projectPut(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	value *pb.Project,
 ) error {
@@ -113,7 +112,7 @@ func (s *State) projectPut(
 }
 
 func (s *State) projectGet(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	ref *pb.Ref_Project,
 ) (*pb.Project, error) {
@@ -147,7 +146,7 @@ func (s *State) projectList(
 }
 
 func (s *State) projectDelete(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	ref *pb.Ref_Project,
 ) error {
@@ -171,7 +170,7 @@ func (s *State) projectDelete(
 		}
 	}
 
-	// Delete from bolt
+	// Delete from the db
 	id := s.projectIdByRef(ref)
 	if err := dbTxn.Bucket(projectBucket).Delete(id); err != nil {
 		return err
@@ -188,7 +187,8 @@ func (s *State) projectDelete(
 // projectIndexSet writes an index record for a single project.
 func (s *State) projectIndexSet(txn *memdb.Txn, id []byte, value *pb.Project) error {
 	record := &projectIndexRecord{
-		Id: string(id),
+		Id:                string(id),
+		MaxConcurrentJobs: value.Jobs.GetMaxConcurrent(),
 	}
 
 	// Insert the index
@@ -196,7 +196,7 @@ func (s *State) projectIndexSet(txn *memdb.Txn, id []byte, value *pb.Project) er
 }
 
 // projectIndexInit initializes the project index from persisted data.
-func (s *State) projectIndexInit(dbTxn *bolt.Tx, memTxn *memdb.Txn) error {
+func (s *State) projectIndexInit(dbTxn Tx, memTxn *memdb.Txn) error {
 	bucket := dbTxn.Bucket(projectBucket)
 	return bucket.ForEach(func(k, v []byte) error {
 		var value pb.Project
@@ -243,4 +243,8 @@ const (
 
 type projectIndexRecord struct {
 	Id string
+
+	// MaxConcurrentJobs is the maximum number of jobs of the project that
+	// are assigned at the same time. Zero means there is no limit.
+	MaxConcurrentJobs uint32
 }
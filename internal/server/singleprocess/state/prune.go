@@ -0,0 +1,326 @@
+package state
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+// pruneRule is a parsed pb.Project_Retention.
+type pruneRule struct {
+	// maxCount is the number of records to keep for each application and
+	// workspace. If this is zero, there is no limit.
+	maxCount int
+
+	// before is the time that records must have started after. If this
+	// is zero, there is no limit.
+	before time.Time
+}
+
+// prune returns true if the record is outside the rule. n is the number
+// of the record for its application and workspace, newest first.
+func (r *pruneRule) prune(n int, start time.Time) bool {
+	if r.maxCount > 0 && n > r.maxCount {
+		return true
+	}
+
+	return !r.before.IsZero() && start.Before(r.before)
+}
+
+// PruneOperations deletes the records of builds, pushed artifacts,
+// deployments, releases and jobs of the project that are outside of its
+// retention policy. Records are always kept if they're still in progress,
+// if their physical resources still exist, if they're the latest
+// successful operation of their application and workspace, or if a record
+// that is kept references them.
+func (s *State) PruneOperations(p *pb.Project) (*pb.PruneOperationsResponse, error) {
+	var result pb.PruneOperationsResponse
+
+	policy := p.Retention
+	if policy == nil || (policy.MaxCount == 0 && policy.MaxAge == "") {
+		return &result, nil
+	}
+
+	rule := &pruneRule{maxCount: int(policy.MaxCount)}
+	if policy.MaxAge != "" {
+		age, err := time.ParseDuration(policy.MaxAge)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"invalid retention max_age %q: %s", policy.MaxAge, err)
+		}
+
+		rule.before = time.Now().Add(-age)
+	}
+
+	// Releases reference deployments, which reference pushed artifacts,
+	// which reference builds. We go in that order so that we know which
+	// records are referenced by the records that we keep.
+	ops := []struct {
+		Op       *appOperation
+		RefField string
+		Count    *uint32
+	}{
+		{releaseOp, "DeploymentId", &result.Releases},
+		{deploymentOp, "ArtifactId", &result.Deployments},
+		{artifactOp, "BuildId", &result.PushedArtifacts},
+		{buildOp, "", &result.Builds},
+	}
+
+	// Find everything to delete first since the reads can't happen within
+	// the write transactions.
+	deletes := make([][]string, len(ops))
+	for _, app := range p.Applications {
+		ref := &pb.Ref_Application{Project: p.Name, Application: app.Name}
+
+		var keep map[string]bool
+		for i, op := range ops {
+			ids, refs, err := op.Op.pruneIds(s, ref, rule, keep, op.RefField)
+			if err != nil {
+				return nil, err
+			}
+
+			deletes[i] = append(deletes[i], ids...)
+			keep = refs
+		}
+	}
+
+	jobIds, err := s.jobPruneIds(p.Name, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	memTxn := s.inmem.Txn(true)
+	defer memTxn.Abort()
+	err = s.db.Update(func(dbTxn Tx) error {
+		for i, op := range ops {
+			if err := op.Op.pruneDelete(dbTxn, memTxn, deletes[i]); err != nil {
+				return err
+			}
+
+			*op.Count = uint32(len(deletes[i]))
+		}
+
+		for _, id := range jobIds {
+			if err := s.jobDelete(dbTxn, memTxn, id); err != nil {
+				return err
+			}
+		}
+		result.Jobs = uint32(len(jobIds))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	memTxn.Commit()
+
+	return &result, nil
+}
+
+// pruneIds returns the IDs of the records of the application that are
+// outside the rule. Records with an ID in keep are kept. The returned map
+// is the set of values of refField of the records that are kept.
+func (op *appOperation) pruneIds(
+	s *State,
+	ref *pb.Ref_Application,
+	rule *pruneRule,
+	keep map[string]bool,
+	refField string,
+) ([]string, map[string]bool, error) {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	// Newest first
+	iter, err := memTxn.LowerBound(
+		op.memTableName(),
+		opStartTimeIndexName,
+		ref.Project,
+		ref.Application,
+		indexTimeLatest{},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []*operationIndexRecord
+	latest := map[string]string{}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		record := raw.(*operationIndexRecord)
+		if !record.MatchRef(ref) {
+			break
+		}
+
+		records = append(records, record)
+		latest[record.Workspace] = ""
+	}
+
+	// The latest successful operation is used by later operations so we
+	// always keep it.
+	for ws := range latest {
+		v, err := op.Latest(s, ref, &pb.Ref_Workspace{Workspace: ws})
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		latest[ws] = op.valueField(v, "Id").(string)
+	}
+
+	var result []string
+	refs := map[string]bool{}
+	counts := map[string]int{}
+	err = s.db.View(func(dbTxn Tx) error {
+		for _, record := range records {
+			counts[record.Workspace]++
+
+			value := op.newStruct()
+			if err := op.dbGet(dbTxn, []byte(record.Id), value); err != nil {
+				return err
+			}
+
+			if rule.prune(counts[record.Workspace], record.StartTime) &&
+				!keep[record.Id] &&
+				latest[record.Workspace] != record.Id &&
+				op.pruneDone(value) {
+				result = append(result, record.Id)
+				continue
+			}
+
+			if refField != "" {
+				if id, ok := op.valueField(value, refField).(string); ok && id != "" {
+					refs[id] = true
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, refs, nil
+}
+
+// pruneDone returns true if the operation is complete and its physical
+// resources, if any, no longer exist.
+func (op *appOperation) pruneDone(value interface{}) bool {
+	st, _ := op.valueField(value, "Status").(*pb.Status)
+	if st == nil {
+		return false
+	}
+	switch st.State {
+	case pb.Status_SUCCESS, pb.Status_ERROR:
+	default:
+		return false
+	}
+
+	if raw := op.valueField(value, "State"); raw != nil {
+		switch raw.(pb.Operation_PhysicalState) {
+		case pb.Operation_PENDING, pb.Operation_CREATED:
+			return false
+		}
+	}
+
+	return true
+}
+
+// pruneDelete deletes the records with the given IDs.
+func (op *appOperation) pruneDelete(dbTxn Tx, memTxn *memdb.Txn, ids []string) error {
+	b := dbTxn.Bucket(op.Bucket)
+	for _, id := range ids {
+		if err := b.Delete([]byte(id)); err != nil {
+			return err
+		}
+
+		raw, err := memTxn.First(op.memTableName(), opIdIndexName, id)
+		if err != nil {
+			return err
+		}
+		if raw != nil {
+			if err := memTxn.Delete(op.memTableName(), raw); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// jobPruneIds returns the IDs of the completed jobs of the project that
+// are outside the rule.
+func (s *State) jobPruneIds(project string, rule *pruneRule) ([]string, error) {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	iter, err := memTxn.Get(jobTableName, jobIdIndexName+"_prefix", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*jobIndex
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		idx := raw.(*jobIndex)
+		if idx.Application == nil || !strings.EqualFold(idx.Application.Project, project) {
+			continue
+		}
+
+		jobs = append(jobs, idx)
+	}
+
+	// Newest first
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].QueueTime.After(jobs[j].QueueTime)
+	})
+
+	var result []string
+	counts := map[string]int{}
+	for _, idx := range jobs {
+		k := strings.ToLower(idx.Application.Application)
+		if idx.Workspace != nil {
+			k += "/" + strings.ToLower(idx.Workspace.Workspace)
+		}
+		counts[k]++
+
+		switch idx.State {
+		case pb.Job_SUCCESS, pb.Job_ERROR:
+		default:
+			continue
+		}
+
+		if rule.prune(counts[k], idx.QueueTime) {
+			result = append(result, idx.Id)
+		}
+	}
+
+	return result, nil
+}
+
+// jobDelete deletes a job.
+func (s *State) jobDelete(dbTxn Tx, memTxn *memdb.Txn, id string) error {
+	if err := dbTxn.Bucket(jobBucket).Delete([]byte(id)); err != nil {
+		return err
+	}
+	if err := dbTxn.Bucket(jobOutputBucket).Delete([]byte(id)); err != nil {
+		return err
+	}
+
+	raw, err := memTxn.First(jobTableName, jobIdIndexName, id)
+	if err != nil {
+		return err
+	}
+	if raw == nil {
+		return nil
+	}
+
+	raw.(*jobIndex).End()
+	return memTxn.Delete(jobTableName, raw)
+}
@@ -0,0 +1,105 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestPruneOperations(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// status returns a status for an operation that started h hours ago.
+	status := func(h int, state pb.Status_State) *pb.Status {
+		pt, err := ptypes.TimestampProto(time.Now().Add(-time.Duration(h) * time.Hour))
+		require.NoError(err)
+
+		return &pb.Status{State: state, StartTime: pt, CompleteTime: pt}
+	}
+
+	// B1 is referenced by the only artifact, B3 is the latest successful
+	// build and B4 is the newest build. Only B2 can be pruned.
+	builds := []*pb.Build{
+		{Id: "B1", Status: status(4, pb.Status_SUCCESS)},
+		{Id: "B2", Status: status(3, pb.Status_SUCCESS)},
+		{Id: "B3", Status: status(2, pb.Status_SUCCESS)},
+		{Id: "B4", Status: status(1, pb.Status_ERROR)},
+	}
+	for _, b := range builds {
+		require.NoError(s.BuildPut(false, serverptypes.TestValidBuild(t, b)))
+	}
+	require.NoError(s.ArtifactPut(false, serverptypes.TestValidArtifact(t, &pb.PushedArtifact{
+		Id:      "A1",
+		BuildId: "B1",
+		Status:  status(4, pb.Status_SUCCESS),
+	})))
+
+	// D1 still exists and D3 is the newest. Only D2 can be pruned.
+	deployments := []*pb.Deployment{
+		{Id: "D1", State: pb.Operation_CREATED, Status: status(3, pb.Status_SUCCESS)},
+		{Id: "D2", State: pb.Operation_DESTROYED, Status: status(2, pb.Status_SUCCESS)},
+		{Id: "D3", State: pb.Operation_CREATED, Status: status(1, pb.Status_SUCCESS)},
+	}
+	for _, d := range deployments {
+		d.ArtifactId = "A1"
+		require.NoError(s.DeploymentPut(false, serverptypes.TestValidDeployment(t, d)))
+	}
+
+	// Two completed jobs and a queued job. Only the older completed job
+	// can be pruned.
+	for _, id := range []string{"J1", "J2", "J3"} {
+		require.NoError(s.JobCreate(serverptypes.TestJobNew(t, &pb.Job{Id: id})))
+	}
+	require.NoError(s.JobCancel("J1", false))
+	require.NoError(s.JobCancel("J2", false))
+
+	p, err := s.ProjectGet(&pb.Ref_Project{Project: "p_test"})
+	require.NoError(err)
+
+	// Without a policy nothing is pruned
+	result, err := s.PruneOperations(p)
+	require.NoError(err)
+	require.Zero(result.Builds)
+
+	p.Retention = &pb.Project_Retention{MaxCount: 1}
+	result, err = s.PruneOperations(p)
+	require.NoError(err)
+	require.Equal(uint32(1), result.Builds)
+	require.Equal(uint32(0), result.PushedArtifacts)
+	require.Equal(uint32(1), result.Deployments)
+	require.Equal(uint32(1), result.Jobs)
+
+	_, err = s.BuildGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "B2"}})
+	require.Error(err)
+	_, err = s.BuildGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "B1"}})
+	require.NoError(err)
+	_, err = s.DeploymentGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "D2"}})
+	require.Error(err)
+
+	job, err := s.JobById("J1", nil)
+	require.NoError(err)
+	require.Nil(job)
+	job, err = s.JobById("J3", nil)
+	require.NoError(err)
+	require.NotNil(job)
+
+	// Lists no longer include the pruned records
+	list, err := s.BuildList(serverptypes.TestValidBuild(t, nil).Application)
+	require.NoError(err)
+	require.Len(list, 3)
+
+	// Pruning again does nothing
+	result, err = s.PruneOperations(p)
+	require.NoError(err)
+	require.Zero(result.Builds)
+	require.Zero(result.Deployments)
+	require.Zero(result.Jobs)
+}
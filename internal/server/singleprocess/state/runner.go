@@ -1,6 +1,11 @@
 package state
 
 import (
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hashicorp/go-memdb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -13,8 +18,15 @@ const (
 	runnerIdIndexName = "id"
 )
 
+// runnerBucket stores the runners that have been seen so that their
+// adoption state is kept when they disconnect. The runners table only
+// has the connected runners.
+var runnerBucket = []byte("runner")
+
 func init() {
+	dbBuckets = append(dbBuckets, runnerBucket)
 	schemas = append(schemas, runnerSchema)
+	memOnlyTables[runnerTableName] = struct{}{}
 }
 
 func runnerSchema() *memdb.TableSchema {
@@ -42,43 +54,276 @@ type runnerRecord struct {
 	Id string
 }
 
+// RunnerCreate registers a connected runner. The fields of r that are set
+// by the server, such as the adoption state, are updated. Runners that
+// aren't by ID only are persisted so that they can be adopted or rejected
+// while they're disconnected.
 func (s *State) RunnerCreate(r *pb.Runner) error {
-	txn := s.inmem.Txn(true)
-	defer txn.Abort()
+	memTxn := s.inmem.Txn(true)
+	defer memTxn.Abort()
+
+	err := s.db.Update(func(dbTxn Tx) error {
+		// The runner can't set its own adoption state, it is always the
+		// state we have or pending if we've never seen it.
+		r.AdoptionState = pb.Runner_PENDING
+		r.FirstSeen = ptypes.TimestampNow()
+		r.LastSeen = r.FirstSeen
+		r.Online = true
+
+		if r.ByIdOnly {
+			return nil
+		}
+
+		b := dbTxn.Bucket(runnerBucket)
+		var existing pb.Runner
+		err := dbGet(b, s.runnerId(r.Id), &existing)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			r.AdoptionState = existing.AdoptionState
+			r.FirstSeen = existing.FirstSeen
+		}
+
+		return s.runnerPut(dbTxn, r)
+	})
+	if err != nil {
+		return err
+	}
 
 	// Create our runner
-	if err := txn.Insert(runnerTableName, newRunnerRecord(r)); err != nil {
+	if err := memTxn.Insert(runnerTableName, newRunnerRecord(r)); err != nil {
 		return status.Errorf(codes.Aborted, err.Error())
 	}
 
-	txn.Commit()
+	memTxn.Commit()
 
 	return nil
 }
 
+// RunnerDelete deregisters a runner that disconnected. The persisted
+// runner is kept, see RunnerForget to delete it.
 func (s *State) RunnerDelete(id string) error {
-	txn := s.inmem.Txn(true)
-	defer txn.Abort()
-	if _, err := txn.DeleteAll(runnerTableName, runnerIdIndexName, id); err != nil {
+	memTxn := s.inmem.Txn(true)
+	defer memTxn.Abort()
+
+	err := s.db.Update(func(dbTxn Tx) error {
+		var r pb.Runner
+		err := dbGet(dbTxn.Bucket(runnerBucket), s.runnerId(id), &r)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		r.LastSeen = ptypes.TimestampNow()
+		return s.runnerPut(dbTxn, &r)
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := memTxn.DeleteAll(runnerTableName, runnerIdIndexName, id); err != nil {
 		return status.Errorf(codes.Aborted, err.Error())
 	}
-	txn.Commit()
+	memTxn.Commit()
 
 	return nil
 }
 
+// RunnerById returns the runner with the given ID. This is the connected
+// runner if it is connected, otherwise the persisted runner with online
+// set to false.
 func (s *State) RunnerById(id string) (*pb.Runner, error) {
-	txn := s.inmem.Txn(false)
-	raw, err := txn.First(runnerTableName, runnerIdIndexName, id)
-	txn.Abort()
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	raw, err := memTxn.First(runnerTableName, runnerIdIndexName, id)
 	if err != nil {
 		return nil, err
 	}
-	if raw == nil {
+	if raw != nil {
+		return raw.(*runnerRecord).Runner, nil
+	}
+
+	var result pb.Runner
+	err = s.db.View(func(dbTxn Tx) error {
+		return dbGet(dbTxn.Bucket(runnerBucket), s.runnerId(id), &result)
+	})
+	if status.Code(err) == codes.NotFound {
 		return nil, status.Errorf(codes.NotFound, "runner ID not found")
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// RunnerList returns the connected runners and the persisted runners that
+// are disconnected, sorted by ID.
+func (s *State) RunnerList() ([]*pb.Runner, error) {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	var result []*pb.Runner
+	seen := map[string]struct{}{}
+	iter, err := memTxn.LowerBound(runnerTableName, runnerIdIndexName, "")
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		r := raw.(*runnerRecord).Runner
+		result = append(result, r)
+		seen[string(s.runnerId(r.Id))] = struct{}{}
+	}
+
+	err = s.db.View(func(dbTxn Tx) error {
+		return dbTxn.Bucket(runnerBucket).ForEach(func(k, v []byte) error {
+			if _, ok := seen[string(k)]; ok {
+				return nil
+			}
+
+			var r pb.Runner
+			if err := proto.Unmarshal(v, &r); err != nil {
+				return err
+			}
+
+			result = append(result, &r)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Id < result[j].Id
+	})
+
+	return result, nil
+}
+
+// RunnerAdopt sets the adoption state of a runner. This is persisted for
+// runners that aren't by ID only, so it is kept if the runner reconnects.
+func (s *State) RunnerAdopt(id string, state pb.Runner_AdoptionState) error {
+	memTxn := s.inmem.Txn(true)
+	defer memTxn.Abort()
+
+	raw, err := memTxn.First(runnerTableName, runnerIdIndexName, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Update(func(dbTxn Tx) error {
+		var r pb.Runner
+		err := dbGet(dbTxn.Bucket(runnerBucket), s.runnerId(id), &r)
+		if status.Code(err) == codes.NotFound && raw != nil {
+			// Connected by ID only, so there is nothing persisted.
+			return nil
+		}
+		if status.Code(err) == codes.NotFound {
+			return status.Errorf(codes.NotFound, "runner ID not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		r.AdoptionState = state
+		return s.runnerPut(dbTxn, &r)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Replace the connected runner so that anything waiting on it to be
+	// assignable sees the change.
+	if raw != nil {
+		r := proto.Clone(raw.(*runnerRecord).Runner).(*pb.Runner)
+		r.AdoptionState = state
+		if err := memTxn.Insert(runnerTableName, newRunnerRecord(r)); err != nil {
+			return status.Errorf(codes.Aborted, err.Error())
+		}
+	}
+
+	memTxn.Commit()
+
+	return nil
+}
+
+// RunnerForget deletes a persisted runner. Connected runners can't be
+// forgotten since they'd be persisted again right away.
+func (s *State) RunnerForget(id string) error {
+	memTxn := s.inmem.Txn(false)
+	defer memTxn.Abort()
+
+	raw, err := memTxn.First(runnerTableName, runnerIdIndexName, id)
+	if err != nil {
+		return err
+	}
+	if raw != nil {
+		return status.Errorf(codes.FailedPrecondition,
+			"runner %q is connected and can't be forgotten", id)
+	}
+
+	return s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(runnerBucket)
+		if b.Get(s.runnerId(id)) == nil {
+			return status.Errorf(codes.NotFound, "runner ID not found")
+		}
+
+		return b.Delete(s.runnerId(id))
+	})
+}
 
-	return raw.(*runnerRecord).Runner, nil
+// runnerPut persists a runner. Whether it is connected is only kept in
+// memory so online is always false on the persisted record.
+func (s *State) runnerPut(dbTxn Tx, r *pb.Runner) error {
+	r = proto.Clone(r).(*pb.Runner)
+	r.Online = false
+
+	return dbPut(dbTxn.Bucket(runnerBucket), s.runnerId(r.Id), r)
+}
+
+func (s *State) runnerId(id string) []byte {
+	return []byte(strings.ToLower(id))
+}
+
+// runnerAssignableWatch returns true if the runner may be assigned jobs.
+// The connected runner is used if there is one so that its latest adoption
+// state is checked. ws is notified when the result may change.
+func (s *State) runnerAssignableWatch(memTxn *memdb.Txn, ws memdb.WatchSet, r *pb.Runner) (bool, error) {
+	cfg, err := s.serverConfigGet(memTxn, ws)
+	if err != nil {
+		return false, err
+	}
+
+	watchCh, raw, err := memTxn.FirstWatch(runnerTableName, runnerIdIndexName, r.Id)
+	if err != nil {
+		return false, err
+	}
+	ws.Add(watchCh)
+	if raw != nil {
+		r = raw.(*runnerRecord).Runner
+	}
+
+	return runnerAssignable(r, cfg), nil
+}
+
+// runnerAssignable returns true if the runner may be assigned jobs given
+// its adoption state and whether the server requires adoption.
+func runnerAssignable(r *pb.Runner, cfg *pb.ServerConfig) bool {
+	switch r.AdoptionState {
+	case pb.Runner_ADOPTED:
+		return true
+
+	case pb.Runner_REJECTED:
+		return false
+
+	default:
+		return r.ByIdOnly || !cfg.GetRequireRunnerAdoption()
+	}
 }
 
 // runnerEmpty returns true if there are no runners registered.
@@ -25,17 +25,114 @@ func TestRunner_crud(t *testing.T) {
 	require.NoError(err)
 	require.Equal(rec, found)
 
+	require.True(found.Online)
+	require.Equal(pb.Runner_PENDING, found.AdoptionState)
+
 	// Delete that instance
 	require.NoError(s.RunnerDelete(rec.Id))
 
+	// We should find it offline
+	found, err = s.RunnerById(rec.Id)
+	require.NoError(err)
+	require.False(found.Online)
+
+	// Delete again should be fine
+	require.NoError(s.RunnerDelete(rec.Id))
+
+	// Forget it
+	require.NoError(s.RunnerForget(rec.Id))
+
 	// We should not find it
 	found, err = s.RunnerById(rec.Id)
 	require.Error(err)
 	require.Nil(found)
 	require.Equal(codes.NotFound, status.Code(err))
+}
 
-	// Delete again should be fine
+func TestRunnerCreate_byIdOnly(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create a local runner
+	rec := &pb.Runner{Id: "A", ByIdOnly: true}
+	require.NoError(s.RunnerCreate(rec))
+
+	// Delete that instance
 	require.NoError(s.RunnerDelete(rec.Id))
+
+	// It isn't kept
+	_, err := s.RunnerById(rec.Id)
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestRunnerAdopt(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create an instance and reject it
+	require.NoError(s.RunnerCreate(&pb.Runner{Id: "A"}))
+	require.NoError(s.RunnerAdopt("A", pb.Runner_REJECTED))
+
+	found, err := s.RunnerById("A")
+	require.NoError(err)
+	require.Equal(pb.Runner_REJECTED, found.AdoptionState)
+
+	// Reconnecting keeps the state even if the runner sets its own
+	require.NoError(s.RunnerDelete("A"))
+	require.NoError(s.RunnerCreate(&pb.Runner{Id: "A", AdoptionState: pb.Runner_ADOPTED}))
+
+	found, err = s.RunnerById("A")
+	require.NoError(err)
+	require.True(found.Online)
+	require.Equal(pb.Runner_REJECTED, found.AdoptionState)
+
+	// Unknown runners can't be adopted
+	err = s.RunnerAdopt("nope", pb.Runner_ADOPTED)
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestRunnerForget(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Connected runners can't be forgotten
+	require.NoError(s.RunnerCreate(&pb.Runner{Id: "A"}))
+	err := s.RunnerForget("A")
+	require.Error(err)
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+
+	// Unknown runners can't be forgotten
+	err = s.RunnerForget("nope")
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+}
+
+func TestRunnerList(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Create a connected and a disconnected runner
+	require.NoError(s.RunnerCreate(&pb.Runner{Id: "B"}))
+	require.NoError(s.RunnerCreate(&pb.Runner{Id: "A"}))
+	require.NoError(s.RunnerDelete("A"))
+
+	list, err := s.RunnerList()
+	require.NoError(err)
+	require.Len(list, 2)
+	require.Equal("A", list[0].Id)
+	require.False(list[0].Online)
+	require.Equal("B", list[1].Id)
+	require.True(list[1].Online)
 }
 
 func TestRunnerById_notFound(t *testing.T) {
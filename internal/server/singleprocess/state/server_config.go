@@ -1,7 +1,6 @@
 package state
 
 import (
-	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-memdb"
 
@@ -24,7 +23,7 @@ func (s *State) ServerConfigSet(c *pb.ServerConfig) error {
 	memTxn := s.inmem.Txn(true)
 	defer memTxn.Abort()
 
-	err := s.db.Update(func(dbTxn *bolt.Tx) error {
+	err := s.db.Update(func(dbTxn Tx) error {
 		return s.serverConfigSet(dbTxn, memTxn, c)
 	})
 	if err == nil {
@@ -39,7 +38,13 @@ func (s *State) ServerConfigGet() (*pb.ServerConfig, error) {
 	memTxn := s.inmem.Txn(false)
 	defer memTxn.Abort()
 
-	v, err := memTxn.First(
+	return s.serverConfigGet(memTxn, nil)
+}
+
+// serverConfigGet gets the server configuration. If ws is non-nil, it is
+// notified when the configuration changes.
+func (s *State) serverConfigGet(memTxn *memdb.Txn, ws memdb.WatchSet) (*pb.ServerConfig, error) {
+	watchCh, v, err := memTxn.FirstWatch(
 		serverConfigIndexTableName,
 		serverConfigIndexIdIndexName,
 		string(serverConfigId),
@@ -47,6 +52,9 @@ func (s *State) ServerConfigGet() (*pb.ServerConfig, error) {
 	if err != nil {
 		return nil, err
 	}
+	if ws != nil {
+		ws.Add(watchCh)
+	}
 	if v == nil {
 		return &pb.ServerConfig{}, nil
 	}
@@ -55,7 +63,7 @@ func (s *State) ServerConfigGet() (*pb.ServerConfig, error) {
 }
 
 func (s *State) serverConfigSet(
-	dbTxn *bolt.Tx,
+	dbTxn Tx,
 	memTxn *memdb.Txn,
 	value *pb.ServerConfig,
 ) error {
@@ -94,7 +102,7 @@ func (s *State) serverConfigIndexSet(txn *memdb.Txn, id []byte, value *pb.Server
 }
 
 // serverConfigIndexInit initializes the server config index from persisted data.
-func (s *State) serverConfigIndexInit(dbTxn *bolt.Tx, memTxn *memdb.Txn) error {
+func (s *State) serverConfigIndexInit(dbTxn Tx, memTxn *memdb.Txn) error {
 	bucket := dbTxn.Bucket(serverConfigBucket)
 
 	data := bucket.Get(serverConfigId)
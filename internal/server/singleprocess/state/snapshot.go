@@ -0,0 +1,292 @@
+package state
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/waypoint/internal/protocolversion"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+const (
+	// snapshotChunkItems is the maximum number of items in a single
+	// BoltChunk record of a snapshot.
+	snapshotChunkItems = 256
+
+	// snapshotRecordMax is the maximum size of a single record. This
+	// protects against reading garbage as a huge length.
+	snapshotRecordMax = 128 * 1024 * 1024
+)
+
+// CreateSnapshot writes a snapshot of the persisted state to w. The data
+// is read in a single transaction so the snapshot is consistent. The
+// in-memory indexes aren't included since they're rebuilt on restore.
+func (s *State) CreateSnapshot(w io.Writer) error {
+	gzw := gzip.NewWriter(w)
+
+	err := writeSnapshotRecord(gzw, &pb.Snapshot_Record{
+		Record: &pb.Snapshot_Record_Header{
+			Header: &pb.Snapshot_Header{
+				Version: protocolversion.Current(),
+				Time:    ptypes.TimestampNow(),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Records are written as they are stored so that the secrets in the
+	// snapshot stay encrypted.
+	var total uint64
+	err = s.rawDB().View(func(dbTxn Tx) error {
+		for _, name := range dbBuckets {
+			b := dbTxn.Bucket(name)
+			if b == nil {
+				continue
+			}
+
+			chunk := &pb.Snapshot_BoltChunk{
+				Bucket: string(name),
+				Items:  map[string][]byte{},
+			}
+			flush := func() error {
+				if len(chunk.Items) == 0 {
+					return nil
+				}
+
+				total += uint64(len(chunk.Items))
+				err := writeSnapshotRecord(gzw, &pb.Snapshot_Record{
+					Record: &pb.Snapshot_Record_BoltChunk{BoltChunk: chunk},
+				})
+				chunk.Items = map[string][]byte{}
+				return err
+			}
+
+			err := b.ForEach(func(k, v []byte) error {
+				// The value is only valid for the life of the transaction
+				// but we write it before the transaction ends.
+				chunk.Items[string(k)] = v
+				if len(chunk.Items) >= snapshotChunkItems {
+					return flush()
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeSnapshotRecord(gzw, &pb.Snapshot_Record{
+		Record: &pb.Snapshot_Record_Trailer{
+			Trailer: &pb.Snapshot_Trailer{Items: total},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return gzw.Close()
+}
+
+// RestoreSnapshot replaces the persisted state with the snapshot read from
+// r and rebuilds the in-memory indexes. The snapshot is read completely
+// and validated before anything is changed, so a truncated or corrupt
+// snapshot leaves the state as it was. Buckets in the snapshot that this
+// server doesn't know about are ignored.
+func (s *State) RestoreSnapshot(r io.Reader) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument,
+			"snapshot is not valid: %s", err)
+	}
+	br := bufio.NewReader(gzr)
+
+	known := map[string]struct{}{}
+	for _, name := range dbBuckets {
+		known[string(name)] = struct{}{}
+	}
+
+	var header *pb.Snapshot_Header
+	var trailer *pb.Snapshot_Trailer
+	var total uint64
+	data := map[string]map[string][]byte{}
+	for trailer == nil {
+		var record pb.Snapshot_Record
+		if err := readSnapshotRecord(br, &record); err != nil {
+			return err
+		}
+
+		if header == nil {
+			h, ok := record.Record.(*pb.Snapshot_Record_Header)
+			if !ok {
+				return status.Errorf(codes.InvalidArgument,
+					"snapshot is not valid: missing header")
+			}
+
+			header = h.Header
+			continue
+		}
+
+		switch v := record.Record.(type) {
+		case *pb.Snapshot_Record_BoltChunk:
+			total += uint64(len(v.BoltChunk.Items))
+			if _, ok := known[v.BoltChunk.Bucket]; !ok {
+				s.log.Warn("ignoring unknown bucket in snapshot",
+					"bucket", v.BoltChunk.Bucket)
+				continue
+			}
+
+			items, ok := data[v.BoltChunk.Bucket]
+			if !ok {
+				items = map[string][]byte{}
+				data[v.BoltChunk.Bucket] = items
+			}
+			for key, value := range v.BoltChunk.Items {
+				items[key] = value
+			}
+
+		case *pb.Snapshot_Record_Trailer:
+			trailer = v.Trailer
+
+		default:
+			return status.Errorf(codes.InvalidArgument,
+				"snapshot is not valid: unexpected record %T", record.Record)
+		}
+	}
+
+	if trailer.Items != total {
+		return status.Errorf(codes.InvalidArgument,
+			"snapshot is not valid: expected %d items, got %d", trailer.Items, total)
+	}
+
+	// The data must be in the format this server reads.
+	vsn, err := strconv.ParseInt(string(data[string(sysBucket)][string(sysVersionKey)]), 10, 64)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument,
+			"snapshot is not valid: failed to read database version: %s", err)
+	}
+	if vsn != dbVersion {
+		return status.Errorf(codes.FailedPrecondition,
+			"snapshot data version %d does not match the server data version %d",
+			vsn, dbVersion)
+	}
+
+	// The encrypted records must be readable with our keyring.
+	for _, name := range encryptedBuckets {
+		for k, v := range data[string(name)] {
+			if err := checkEncrypted(s.keyring, name, []byte(k), v); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.log.Info("restoring snapshot",
+		"version", header.Version.GetVersion(),
+		"items", total)
+
+	err = s.rawDB().Update(func(dbTxn Tx) error {
+		for _, name := range dbBuckets {
+			b, err := dbTxn.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+
+			// Keys can't be deleted while iterating so collect them first.
+			var keys [][]byte
+			err = b.ForEach(func(k, _ []byte) error {
+				keys = append(keys, append([]byte(nil), k...))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range keys {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+
+			for k, v := range data[string(name)] {
+				if err := b.Put([]byte(k), v); err != nil {
+					return status.Errorf(codes.Aborted, "failed to write data: %s", err)
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Encrypt the records of the snapshot with our active key.
+	if err := s.keyringInit(s.rawDB(), s.keyring); err != nil {
+		return err
+	}
+
+	return s.Reindex()
+}
+
+// writeSnapshotRecord writes a single length-prefixed record.
+func writeSnapshotRecord(w io.Writer, record *pb.Snapshot_Record) error {
+	data, err := proto.Marshal(record)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encode data: %s", err)
+	}
+
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(data)))
+	if _, err := w.Write(prefix[:n]); err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// readSnapshotRecord reads a single length-prefixed record.
+func readSnapshotRecord(r *bufio.Reader, record *pb.Snapshot_Record) error {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return status.Errorf(codes.InvalidArgument, "snapshot is not valid: %s", err)
+	}
+	if size > snapshotRecordMax {
+		return status.Errorf(codes.InvalidArgument,
+			"snapshot is not valid: record too large (%d bytes)", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return status.Errorf(codes.InvalidArgument, "snapshot is not valid: %s", err)
+	}
+
+	if err := proto.Unmarshal(data, record); err != nil {
+		return status.Errorf(codes.InvalidArgument, "snapshot is not valid: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	serverptypes "github.com/hashicorp/waypoint/internal/server/ptypes"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Run("create and restore", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{Name: "A"})))
+		require.NoError(s.BuildPut(false, serverptypes.TestValidBuild(t, &pb.Build{Id: "1"})))
+
+		var buf bytes.Buffer
+		require.NoError(s.CreateSnapshot(&buf))
+
+		// Change the state after the snapshot
+		require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{Name: "B"})))
+		require.NoError(s.BuildPut(false, serverptypes.TestValidBuild(t, &pb.Build{Id: "2"})))
+
+		require.NoError(s.RestoreSnapshot(bytes.NewReader(buf.Bytes())))
+
+		projects, err := s.ProjectList()
+		require.NoError(err)
+		require.Len(projects, 1)
+		require.Equal("A", projects[0].Project)
+
+		// The indexes are rebuilt
+		_, err = s.BuildGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "1"}})
+		require.NoError(err)
+		_, err = s.BuildGet(&pb.Ref_Operation{Target: &pb.Ref_Operation_Id{Id: "2"}})
+		require.Error(err)
+		require.Equal(codes.NotFound, status.Code(err))
+	})
+
+	t.Run("invalid snapshots don't change the state", func(t *testing.T) {
+		require := require.New(t)
+
+		s := TestState(t)
+		defer s.Close()
+
+		require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{Name: "A"})))
+
+		var buf bytes.Buffer
+		require.NoError(s.CreateSnapshot(&buf))
+		require.NoError(s.ProjectPut(serverptypes.TestProject(t, &pb.Project{Name: "B"})))
+
+		for _, data := range [][]byte{
+			nil,
+			[]byte("not a snapshot"),
+			buf.Bytes()[:buf.Len()/2],
+		} {
+			err := s.RestoreSnapshot(bytes.NewReader(data))
+			require.Error(err)
+			require.Equal(codes.InvalidArgument, status.Code(err))
+		}
+
+		projects, err := s.ProjectList()
+		require.NoError(err)
+		require.Len(projects, 2)
+	})
+}
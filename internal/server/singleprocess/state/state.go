@@ -5,10 +5,12 @@ package state
 import (
 	"fmt"
 	"reflect"
+	"sync"
 
-	"github.com/boltdb/bolt"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
 )
 
 // The global variables below can be set by init() functions of other
@@ -25,6 +27,10 @@ var (
 	// dbIndexers is the list of functions to call to initialize the
 	// in-memory indexes from the persisted db.
 	dbIndexers []indexFn
+
+	// memOnlyTables are the tables that aren't built from the persisted db,
+	// such as connected runners. These are kept by Reindex.
+	memOnlyTables = map[string]struct{}{}
 )
 
 // State is the primary API for state mutation for the server.
@@ -39,7 +45,11 @@ type State struct {
 	// inmem is used alongside db to store in-memory indexing information
 	// for more efficient lookups into db. This index is built online at
 	// boot.
-	db *bolt.DB
+	db DB
+
+	// keyring encrypts the records of the encryptedBuckets. If this is
+	// set, db is an encryptedDB.
+	keyring *Keyring
 
 	// hmacKeyNotEmpty is flipped to 1 when an hmac entry is set. This is
 	// used to determine if we're in a bootstrap state and can create a
@@ -52,10 +62,37 @@ type State struct {
 
 	// Where to log to
 	log hclog.Logger
+
+	// jobStateHook is called when the state of a job changes. This is set
+	// with SetJobStateHook.
+	jobStateHook func(*pb.Job)
+
+	// logHistory configures keeping log lines. logHistorySeq is the ID of
+	// the last line and logHistoryCounts the number of lines of each
+	// deployment. These are protected by the memdb write transaction.
+	// logHistoryPending are the writes of persisted lines that haven't
+	// been flushed to the db, which are protected by logHistoryLock.
+	logHistory        LogHistoryConfig
+	logHistorySeq     uint64
+	logHistoryCounts  map[string]int
+	logHistoryPending []logHistoryOp
+	logHistoryLock    sync.Mutex
+
+	leaderState
+}
+
+// Option configures a State in New.
+type Option func(*State)
+
+// WithKeyring sets the keyring to encrypt the sensitive records with. If
+// this isn't set, records are stored as plaintext and New fails if there
+// are encrypted records.
+func WithKeyring(k *Keyring) Option {
+	return func(s *State) { s.keyring = k }
 }
 
 // New initializes a new State store.
-func New(log hclog.Logger, db *bolt.DB) (*State, error) {
+func New(log hclog.Logger, db DB, opts ...Option) (*State, error) {
 	// Create the in-memory DB.
 	inmem, err := memdb.NewMemDB(stateStoreSchema())
 	if err != nil {
@@ -68,6 +105,19 @@ func New(log hclog.Logger, db *bolt.DB) (*State, error) {
 	}
 
 	s := &State{inmem: inmem, db: db, log: log}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.SetLeader(true)
+
+	// Make sure we can read every encrypted record and encrypt the ones
+	// that aren't encrypted with the active key yet.
+	if err := s.keyringInit(db, s.keyring); err != nil {
+		return nil, err
+	}
+	if s.keyring != nil {
+		s.db = &encryptedDB{DB: db, keyring: s.keyring}
+	}
 
 	// Initialize our set that'll track what memdb indexers we call.
 	// When we're done we always clear this out since it is never used
@@ -78,7 +128,7 @@ func New(log hclog.Logger, db *bolt.DB) (*State, error) {
 	// Initialize our in-memory indexes
 	memTxn := s.inmem.Txn(true)
 	defer memTxn.Abort()
-	err = s.db.View(func(dbTxn *bolt.Tx) error {
+	err = s.db.View(func(dbTxn Tx) error {
 		for _, indexer := range dbIndexers {
 			// TODO: this should use callIndexer but it's broken as it prevents the multiple op indexers
 			// from properly running.
@@ -100,7 +150,7 @@ func New(log hclog.Logger, db *bolt.DB) (*State, error) {
 // callIndexer calls the specified indexer exactly once. If it has been called
 // before this returns no error. This must not be called concurrently. This
 // can be used from indexers to ensure other data is indexed first.
-func (s *State) callIndexer(fn indexFn, dbTxn *bolt.Tx, memTxn *memdb.Txn) error {
+func (s *State) callIndexer(fn indexFn, dbTxn Tx, memTxn *memdb.Txn) error {
 	fnptr := reflect.ValueOf(fn).Pointer()
 	if _, ok := s.indexers[fnptr]; ok {
 		return nil
@@ -112,8 +162,8 @@ func (s *State) callIndexer(fn indexFn, dbTxn *bolt.Tx, memTxn *memdb.Txn) error
 
 // Close should be called to gracefully close any resources.
 func (s *State) Close() error {
-	// Nothing for now, but we expect to do things one day.
-	return nil
+	// Write the log lines that haven't been flushed yet.
+	return s.LogHistoryFlush()
 }
 
 // schemaFn is an interface function used to create and return new memdb schema
@@ -144,5 +194,5 @@ func stateStoreSchema() *memdb.DBSchema {
 // persisted data. This is usually specified as a method handle to a
 // *State method.
 //
-// The bolt.Tx is read-only while the memdb.Txn is a write transaction.
-type indexFn func(*State, *bolt.Tx, *memdb.Txn) error
+// The Tx is read-only while the memdb.Txn is a write transaction.
+type indexFn func(*State, Tx, *memdb.Txn) error
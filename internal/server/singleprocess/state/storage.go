@@ -0,0 +1,133 @@
+package state
+
+import (
+	"context"
+	"sync"
+
+	"github.com/boltdb/bolt"
+)
+
+// DB is the persisted storage for the state store. The interface matches
+// the subset of BoltDB that the state store uses: a transactional store of
+// buckets, each holding a sorted set of keys and values. This lets the
+// server persist its state to a local file or to an external database.
+//
+// Implementations must serialize write transactions with respect to the
+// in-memory indexes, as BoltDB does by only allowing a single writer.
+type DB interface {
+	// View runs fn in a read-only transaction.
+	View(fn func(Tx) error) error
+
+	// Update runs fn in a read-write transaction. The transaction is
+	// committed if fn returns nil and rolled back otherwise.
+	Update(fn func(Tx) error) error
+
+	// Close closes the database.
+	Close() error
+}
+
+// Tx is a transaction against a DB.
+type Tx interface {
+	// Bucket returns the bucket with the given name, or nil if it
+	// doesn't exist.
+	Bucket(name []byte) Bucket
+
+	// CreateBucketIfNotExists creates the bucket if it doesn't exist.
+	CreateBucketIfNotExists(name []byte) (Bucket, error)
+}
+
+// Bucket is a collection of keys and values within a DB.
+type Bucket interface {
+	// Get returns the value for the key or nil if it is not set. The value
+	// is only valid for the life of the transaction.
+	Get(key []byte) []byte
+
+	// Put sets the value for the key.
+	Put(key, value []byte) error
+
+	// Delete removes the key. It is not an error if the key doesn't exist.
+	Delete(key []byte) error
+
+	// ForEach calls fn for every key in the bucket, in key order.
+	ForEach(fn func(k, v []byte) error) error
+}
+
+// SharedDB is implemented by a DB that multiple servers can use at the same
+// time. The servers elect a leader to run the duties that must only run once,
+// and every server watches for writes from the others to keep its in-memory
+// indexes up to date. A DB that doesn't implement this is only ever used by a
+// single server, which is always the leader.
+type SharedDB interface {
+	DB
+
+	// Lead blocks until this server is the leader or ctx is done. The
+	// returned channel is closed when leadership is lost, after which Lead
+	// can be called again. Leadership is given up when ctx is done.
+	Lead(ctx context.Context) (<-chan struct{}, error)
+
+	// Changed returns true if another server has committed a write since
+	// the last call to Changed.
+	Changed() (bool, error)
+}
+
+// BoltDB returns a DB that stores data in the given BoltDB database.
+func BoltDB(db *bolt.DB) DB {
+	return &boltDB{db: db}
+}
+
+type boltDB struct {
+	// lock is held for writing while the database is compacted, since
+	// that replaces db.
+	lock sync.RWMutex
+	db   *bolt.DB
+}
+
+func (b *boltDB) View(fn func(Tx) error) error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.db.View(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (b *boltDB) Update(fn func(Tx) error) error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (b *boltDB) Close() error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.db.Close()
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (t *boltTx) Bucket(name []byte) Bucket {
+	// Avoid returning a non-nil interface holding a nil bucket.
+	if b := t.tx.Bucket(name); b != nil {
+		return b
+	}
+
+	return nil
+}
+
+func (t *boltTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	b, err := t.tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+var (
+	_ DB     = (*boltDB)(nil)
+	_ Tx     = (*boltTx)(nil)
+	_ Bucket = (*bolt.Bucket)(nil)
+)
@@ -0,0 +1,220 @@
+package state
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// encryptedBuckets are the buckets whose values are encrypted with the
+// keyring, since they hold secrets such as config variables, tokens and
+// auth keys. Other files should use init() to append to this.
+var encryptedBuckets [][]byte
+
+// encryptedDB is a DB that encrypts the values of the encryptedBuckets
+// with a keyring. The keys of the records aren't encrypted.
+type encryptedDB struct {
+	DB
+	keyring *Keyring
+}
+
+func (d *encryptedDB) View(fn func(Tx) error) error {
+	return d.DB.View(func(tx Tx) error {
+		return fn(&encryptedTx{Tx: tx, keyring: d.keyring})
+	})
+}
+
+func (d *encryptedDB) Update(fn func(Tx) error) error {
+	return d.DB.Update(func(tx Tx) error {
+		return fn(&encryptedTx{Tx: tx, keyring: d.keyring})
+	})
+}
+
+type encryptedTx struct {
+	Tx
+	keyring *Keyring
+}
+
+func (t *encryptedTx) Bucket(name []byte) Bucket {
+	b := t.Tx.Bucket(name)
+	if b == nil || !isEncryptedBucket(name) {
+		return b
+	}
+
+	return &encryptedBucket{Bucket: b, name: name, keyring: t.keyring}
+}
+
+func (t *encryptedTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	b, err := t.Tx.CreateBucketIfNotExists(name)
+	if err != nil || !isEncryptedBucket(name) {
+		return b, err
+	}
+
+	return &encryptedBucket{Bucket: b, name: name, keyring: t.keyring}, nil
+}
+
+type encryptedBucket struct {
+	Bucket
+	name    []byte
+	keyring *Keyring
+}
+
+func (b *encryptedBucket) Get(key []byte) []byte {
+	raw := b.Bucket.Get(key)
+	if raw == nil {
+		return nil
+	}
+
+	// The keyring is checked when the state is initialized so this only
+	// fails if the record is corrupt. The encrypted value is returned in
+	// that case, which fails to decode since it starts with a zero byte.
+	v, err := b.keyring.decrypt(b.name, key, raw)
+	if err != nil {
+		return raw
+	}
+
+	return v
+}
+
+func (b *encryptedBucket) Put(key, value []byte) error {
+	v, err := b.keyring.encrypt(b.name, key, value)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to encrypt data: %s", err)
+	}
+
+	return b.Bucket.Put(key, v)
+}
+
+func (b *encryptedBucket) ForEach(fn func(k, v []byte) error) error {
+	return b.Bucket.ForEach(func(k, raw []byte) error {
+		v, err := b.keyring.decrypt(b.name, k, raw)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to decrypt data: %s", err)
+		}
+
+		return fn(k, v)
+	})
+}
+
+// rawDB returns the DB without the encryption of values. This is used to
+// copy the records as they are stored, such as for snapshots.
+func (s *State) rawDB() DB {
+	if d, ok := s.db.(*encryptedDB); ok {
+		return d.DB
+	}
+
+	return s.db
+}
+
+// keyringInit re-encrypts the records that aren't encrypted with the
+// active key of the keyring, which is how keys are rotated and encryption
+// is enabled for an existing database. This fails if a record can't be
+// decrypted with the keyring, so that a missing key is noticed when the
+// server starts rather than when the record is read.
+func (s *State) keyringInit(db DB, keyring *Keyring) error {
+	// Check first so that nothing is written if nothing changes, which is
+	// the case every time the server starts after a rotation.
+	var stale int
+	err := db.View(func(dbTxn Tx) error {
+		return keyringWalk(dbTxn, keyring, func(_ Bucket, _, _ []byte) error {
+			stale++
+			return nil
+		})
+	})
+	if err != nil || stale == 0 {
+		return err
+	}
+
+	s.log.Info("re-encrypting records with the active keyring key",
+		"key", keyring.Active(), "records", stale)
+	return db.Update(func(dbTxn Tx) error {
+		// Keys can't be written while iterating so collect them first.
+		type record struct {
+			b    Bucket
+			k, v []byte
+		}
+		var records []record
+		err := keyringWalk(dbTxn, keyring, func(b Bucket, k, v []byte) error {
+			records = append(records, record{
+				b: b,
+				k: append([]byte(nil), k...),
+				v: append([]byte(nil), v...),
+			})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, r := range records {
+			if err := r.b.Put(r.k, r.v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// keyringWalk calls fn with the decrypted value of every record in the
+// encryptedBuckets that isn't encrypted with the active key. The values of
+// the bucket given to fn are encrypted with the keyring when written.
+func keyringWalk(dbTxn Tx, keyring *Keyring, fn func(b Bucket, k, v []byte) error) error {
+	for _, name := range encryptedBuckets {
+		raw := dbTxn.Bucket(name)
+		if raw == nil {
+			continue
+		}
+		b := &encryptedBucket{Bucket: raw, name: name, keyring: keyring}
+
+		err := raw.ForEach(func(k, v []byte) error {
+			// Plaintext values have no key ID, so they're left as they are
+			// if there is no active key.
+			id, _ := encryptedKeyId(v)
+			if id == keyring.Active() {
+				return nil
+			}
+
+			plain, err := keyring.decrypt(name, k, v)
+			if err != nil {
+				return status.Errorf(codes.FailedPrecondition, "%s", err)
+			}
+
+			return fn(b, k, plain)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkEncrypted returns an error if value is encrypted with a key that
+// isn't in the keyring.
+func checkEncrypted(keyring *Keyring, bucket, key, value []byte) error {
+	if _, ok := encryptedKeyId(value); !ok {
+		return nil
+	}
+
+	if _, err := keyring.decrypt(bucket, key, value); err != nil {
+		return status.Errorf(codes.FailedPrecondition, "%s", err)
+	}
+
+	return nil
+}
+
+func isEncryptedBucket(name []byte) bool {
+	for _, b := range encryptedBuckets {
+		if string(b) == string(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	_ DB     = (*encryptedDB)(nil)
+	_ Tx     = (*encryptedTx)(nil)
+	_ Bucket = (*encryptedBucket)(nil)
+)
@@ -0,0 +1,65 @@
+package state
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresDB(t *testing.T) {
+	url := os.Getenv("WAYPOINT_TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("WAYPOINT_TEST_POSTGRES_URL must be set to test PostgreSQL")
+	}
+
+	require := require.New(t)
+
+	db, err := PostgresDB(url)
+	require.NoError(err)
+	defer db.Close()
+
+	bucket := []byte("test")
+	require.NoError(db.Update(func(tx Tx) error {
+		b := tx.Bucket(bucket)
+
+		// Clear anything left over from a previous run
+		require.NoError(b.ForEach(func(k, v []byte) error {
+			return b.Delete(k)
+		}))
+
+		require.NoError(b.Put([]byte("b"), []byte("2")))
+		require.NoError(b.Put([]byte("a"), []byte("1")))
+		return b.Put([]byte("b"), []byte("3"))
+	}))
+
+	require.NoError(db.View(func(tx Tx) error {
+		b := tx.Bucket(bucket)
+		require.Equal([]byte("1"), b.Get([]byte("a")))
+		require.Equal([]byte("3"), b.Get([]byte("b")))
+		require.Nil(b.Get([]byte("c")))
+
+		var keys []string
+		require.NoError(b.ForEach(func(k, v []byte) error {
+			keys = append(keys, string(k))
+			return nil
+		}))
+		require.Equal([]string{"a", "b"}, keys)
+		return nil
+	}))
+
+	// A failed transaction is rolled back
+	require.Error(db.Update(func(tx Tx) error {
+		require.NoError(tx.Bucket(bucket).Delete([]byte("a")))
+		return os.ErrInvalid
+	}))
+	require.NoError(db.View(func(tx Tx) error {
+		require.Equal([]byte("1"), tx.Bucket(bucket).Get([]byte("a")))
+		return nil
+	}))
+
+	// The state store can be initialized on top of it
+	_, err = New(hclog.L(), db)
+	require.NoError(err)
+}
@@ -32,7 +32,7 @@ func TestStateReinit(t testing.T, s *State) *State {
 	path := filepath.Join(td, "test.db")
 
 	// Start db copy
-	require.NoError(t, s.db.View(func(tx *bolt.Tx) error {
+	require.NoError(t, s.rawDB().(*boltDB).db.View(func(tx *bolt.Tx) error {
 		return tx.CopyFile(path, 0600)
 	}))
 
@@ -42,12 +42,12 @@ func TestStateReinit(t testing.T, s *State) *State {
 	t.Cleanup(func() { db.Close() })
 
 	// Init new state
-	result, err := New(hclog.L(), db)
+	result, err := New(hclog.L(), BoltDB(db), WithKeyring(s.keyring))
 	require.NoError(t, err)
 	return result
 }
 
-func testDB(t testing.T) *bolt.DB {
+func testDB(t testing.T) DB {
 	t.Helper()
 
 	// Temporary directory for the database
@@ -60,5 +60,5 @@ func testDB(t testing.T) *bolt.DB {
 	require.NoError(t, err)
 	t.Cleanup(func() { db.Close() })
 
-	return db
+	return BoltDB(db)
 }
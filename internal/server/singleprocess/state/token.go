@@ -0,0 +1,112 @@
+package state
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var tokenBucket = []byte("token")
+
+func init() {
+	dbBuckets = append(dbBuckets, tokenBucket)
+	encryptedBuckets = append(encryptedBuckets, tokenBucket)
+}
+
+// TokenPut creates or updates the record of a token.
+func (s *State) TokenPut(t *pb.TokenInfo) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		return dbPut(dbTxn.Bucket(tokenBucket), []byte(t.Id), t)
+	})
+}
+
+// TokenGet gets the record of a token by ID. If the token doesn't have a
+// record, a NotFound error is returned.
+func (s *State) TokenGet(id string) (*pb.TokenInfo, error) {
+	var result pb.TokenInfo
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbGet(dbTxn.Bucket(tokenBucket), []byte(id), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// TokenList returns the records of all tokens, oldest first.
+func (s *State) TokenList() ([]*pb.TokenInfo, error) {
+	var result []*pb.TokenInfo
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbTxn.Bucket(tokenBucket).ForEach(func(k, v []byte) error {
+			var t pb.TokenInfo
+			if err := proto.Unmarshal(v, &t); err != nil {
+				return err
+			}
+
+			result = append(result, &t)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		ti, tj := result[i].CreateTime, result[j].CreateTime
+		if ti.GetSeconds() != tj.GetSeconds() {
+			return ti.GetSeconds() < tj.GetSeconds()
+		}
+
+		return ti.GetNanos() < tj.GetNanos()
+	})
+
+	return result, nil
+}
+
+// TokenRevoke marks the token with the given ID as revoked. Tokens without
+// a record get a record that only marks them as revoked. Revoking a token
+// that is already revoked returns a FailedPrecondition error.
+func (s *State) TokenRevoke(id string) (*pb.TokenInfo, error) {
+	var result pb.TokenInfo
+	err := s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(tokenBucket)
+		err := dbGet(b, []byte(id), &result)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+
+		result.Id = id
+		if result.RevokeTime != nil {
+			return status.Errorf(codes.FailedPrecondition,
+				"token is already revoked: %s", id)
+		}
+		result.RevokeTime = ptypes.TimestampNow()
+
+		return dbPut(b, []byte(id), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// TokenRevoked returns true if the token with the given ID was revoked.
+// Tokens without a record aren't revoked.
+func (s *State) TokenRevoked(id string) (bool, error) {
+	t, err := s.TokenGet(id)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return t.RevokeTime != nil, nil
+}
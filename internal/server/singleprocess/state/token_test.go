@@ -0,0 +1,70 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestToken(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Not found
+	_, err := s.TokenGet("A")
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+
+	revoked, err := s.TokenRevoked("A")
+	require.NoError(err)
+	require.False(revoked)
+
+	// Put and Get
+	require.NoError(s.TokenPut(&pb.TokenInfo{
+		Id:         "B",
+		Name:       "ci",
+		CreateTime: &timestamp.Timestamp{Seconds: 2},
+	}))
+	require.NoError(s.TokenPut(&pb.TokenInfo{
+		Id:         "C",
+		CreateTime: &timestamp.Timestamp{Seconds: 1},
+	}))
+
+	tok, err := s.TokenGet("B")
+	require.NoError(err)
+	require.Equal("ci", tok.Name)
+
+	// List is oldest first
+	list, err := s.TokenList()
+	require.NoError(err)
+	require.Len(list, 2)
+	require.Equal("C", list[0].Id)
+	require.Equal("B", list[1].Id)
+
+	// Revoke keeps the record
+	tok, err = s.TokenRevoke("B")
+	require.NoError(err)
+	require.Equal("ci", tok.Name)
+	require.NotNil(tok.RevokeTime)
+
+	revoked, err = s.TokenRevoked("B")
+	require.NoError(err)
+	require.True(revoked)
+
+	_, err = s.TokenRevoke("B")
+	require.Equal(codes.FailedPrecondition, status.Code(err))
+
+	// Revoking a token without a record creates one
+	_, err = s.TokenRevoke("A")
+	require.NoError(err)
+	revoked, err = s.TokenRevoked("A")
+	require.NoError(err)
+	require.True(revoked)
+}
@@ -0,0 +1,76 @@
+package state
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var triggerBucket = []byte("trigger")
+
+func init() {
+	dbBuckets = append(dbBuckets, triggerBucket)
+}
+
+// TriggerPut creates or updates a trigger.
+func (s *State) TriggerPut(t *pb.Trigger) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		return dbPut(dbTxn.Bucket(triggerBucket), []byte(t.Id), t)
+	})
+}
+
+// TriggerGet gets a trigger by ID.
+func (s *State) TriggerGet(id string) (*pb.Trigger, error) {
+	var result pb.Trigger
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbGet(dbTxn.Bucket(triggerBucket), []byte(id), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// TriggerList returns the triggers in the order they were created. If ref
+// is set, only the triggers of that project are returned.
+func (s *State) TriggerList(ref *pb.Ref_Project) ([]*pb.Trigger, error) {
+	var result []*pb.Trigger
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbTxn.Bucket(triggerBucket).ForEach(func(k, v []byte) error {
+			var t pb.Trigger
+			if err := proto.Unmarshal(v, &t); err != nil {
+				return err
+			}
+
+			if ref != nil && !strings.EqualFold(t.Application.GetProject(), ref.Project) {
+				return nil
+			}
+
+			result = append(result, &t)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// TriggerDelete deletes a trigger. A NotFound error is returned if the
+// trigger doesn't exist.
+func (s *State) TriggerDelete(id string) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(triggerBucket)
+		if b.Get([]byte(id)) == nil {
+			return status.Errorf(codes.NotFound, "trigger not found: %s", id)
+		}
+
+		return b.Delete([]byte(id))
+	})
+}
@@ -0,0 +1,52 @@
+package state
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var userBucket = []byte("user")
+
+func init() {
+	dbBuckets = append(dbBuckets, userBucket)
+}
+
+// UserPut creates or updates the given user.
+func (s *State) UserPut(u *pb.User) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		return dbPut(dbTxn.Bucket(userBucket), []byte(u.Id), u)
+	})
+}
+
+// UserGet gets a user by ID. If the user doesn't exist, a NotFound error
+// is returned.
+func (s *State) UserGet(id string) (*pb.User, error) {
+	var result pb.User
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbGet(dbTxn.Bucket(userBucket), []byte(id), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// UserList returns all the users.
+func (s *State) UserList() ([]*pb.User, error) {
+	var result []*pb.User
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbTxn.Bucket(userBucket).ForEach(func(k, v []byte) error {
+			var u pb.User
+			if err := proto.Unmarshal(v, &u); err != nil {
+				return err
+			}
+
+			result = append(result, &u)
+			return nil
+		})
+	})
+
+	return result, err
+}
@@ -0,0 +1,42 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+func TestUser(t *testing.T) {
+	require := require.New(t)
+
+	s := TestState(t)
+	defer s.Close()
+
+	// Not found
+	_, err := s.UserGet("alice")
+	require.Error(err)
+	require.Equal(codes.NotFound, status.Code(err))
+
+	// Put and Get
+	require.NoError(s.UserPut(&pb.User{Id: "alice", Username: "Alice"}))
+	require.NoError(s.UserPut(&pb.User{Id: "bob"}))
+
+	u, err := s.UserGet("alice")
+	require.NoError(err)
+	require.Equal("Alice", u.Username)
+
+	// Update
+	require.NoError(s.UserPut(&pb.User{Id: "alice", Username: "Alice B"}))
+	u, err = s.UserGet("alice")
+	require.NoError(err)
+	require.Equal("Alice B", u.Username)
+
+	// List
+	list, err := s.UserList()
+	require.NoError(err)
+	require.Len(list, 2)
+}
@@ -0,0 +1,141 @@
+package state
+
+import (
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+)
+
+var webhookBucket = []byte("webhook")
+
+// webhookDeliveryBucket records the deliveries that webhooks received so
+// that a captured request can't be replayed. Each key is the ID of the
+// webhook and the ID of the delivery, and the value is the time the
+// delivery was received in Unix nanoseconds.
+var webhookDeliveryBucket = []byte("webhook_delivery")
+
+func init() {
+	dbBuckets = append(dbBuckets, webhookBucket, webhookDeliveryBucket)
+	encryptedBuckets = append(encryptedBuckets, webhookBucket)
+}
+
+// WebhookPut creates or updates a webhook.
+func (s *State) WebhookPut(w *pb.Webhook) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		return dbPut(dbTxn.Bucket(webhookBucket), []byte(w.Id), w)
+	})
+}
+
+// WebhookGet gets a webhook by ID.
+func (s *State) WebhookGet(id string) (*pb.Webhook, error) {
+	var result pb.Webhook
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbGet(dbTxn.Bucket(webhookBucket), []byte(id), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WebhookList returns the webhooks in the order they were created. If ref
+// is set, only the webhooks of that project are returned.
+func (s *State) WebhookList(ref *pb.Ref_Project) ([]*pb.Webhook, error) {
+	var result []*pb.Webhook
+	err := s.db.View(func(dbTxn Tx) error {
+		return dbTxn.Bucket(webhookBucket).ForEach(func(k, v []byte) error {
+			var w pb.Webhook
+			if err := proto.Unmarshal(v, &w); err != nil {
+				return err
+			}
+
+			if ref != nil && !strings.EqualFold(w.Project.GetProject(), ref.Project) {
+				return nil
+			}
+
+			result = append(result, &w)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// WebhookDelete deletes a webhook. A NotFound error is returned if the
+// webhook doesn't exist.
+func (s *State) WebhookDelete(id string) error {
+	return s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(webhookBucket)
+		if b.Get([]byte(id)) == nil {
+			return status.Errorf(codes.NotFound, "webhook not found: %s", id)
+		}
+
+		return b.Delete([]byte(id))
+	})
+}
+
+// WebhookDeliveryAdd records a delivery of the webhook. This returns false
+// if the delivery was already recorded.
+func (s *State) WebhookDeliveryAdd(hookId, deliveryId string) (bool, error) {
+	key := []byte(hookId + "/" + deliveryId)
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, uint64(time.Now().UnixNano()))
+
+	added := false
+	err := s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(webhookDeliveryBucket)
+		if b.Get(key) != nil {
+			return nil
+		}
+
+		added = true
+		return b.Put(key, v)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return added, nil
+}
+
+// WebhookDeliveryPrune deletes the deliveries that were received before
+// the given time and returns how many were deleted.
+func (s *State) WebhookDeliveryPrune(before time.Time) (int, error) {
+	var keys [][]byte
+	err := s.db.Update(func(dbTxn Tx) error {
+		b := dbTxn.Bucket(webhookDeliveryBucket)
+		err := b.ForEach(func(k, v []byte) error {
+			if len(v) < 8 || int64(binary.BigEndian.Uint64(v)) < before.UnixNano() {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(keys), nil
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ClientCAs are the certificate authorities that the client certificates
+// of runners and entrypoints must be signed by. This allows runners and
+// entrypoints to use distinct authorities so that the certificate of a
+// deployment can't be used to run jobs. If a pool is nil, client
+// certificates aren't required for those endpoints.
+type ClientCAs struct {
+	Runner     *x509.CertPool
+	Entrypoint *x509.CertPool
+}
+
+// tlsConnCredentials exposes the TLS state of connections accepted by a
+// TLS listener to gRPC so that the client certificates are available from
+// the peer of calls. The TLS handshake itself is done by the listener.
+type tlsConnCredentials struct{}
+
+func (tlsConnCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	tc, ok := conn.(*tls.Conn)
+	if !ok {
+		return conn, nil, nil
+	}
+
+	if err := tc.Handshake(); err != nil {
+		return nil, nil, err
+	}
+
+	return conn, credentials.TLSInfo{State: tc.ConnectionState()}, nil
+}
+
+func (tlsConnCredentials) ClientHandshake(
+	ctx context.Context, authority string, conn net.Conn,
+) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, fmt.Errorf("tlsConnCredentials can only be used by servers")
+}
+
+func (tlsConnCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "tls"}
+}
+
+func (c tlsConnCredentials) Clone() credentials.TransportCredentials { return c }
+
+func (tlsConnCredentials) OverrideServerName(string) error { return nil }
+
+// clientCertUnaryInterceptor returns a gRPC unary interceptor that requires
+// the client certificates from the ClientCAs for runner and entrypoint
+// endpoints.
+func clientCertUnaryInterceptor(f func() *ClientCAs) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := verifyClientCert(ctx, f(), filepath.Base(info.FullMethod)); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// clientCertStreamInterceptor is the stream equivalent of
+// clientCertUnaryInterceptor.
+func clientCertStreamInterceptor(f func() *ClientCAs) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if err := verifyClientCert(ss.Context(), f(), filepath.Base(info.FullMethod)); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// verifyClientCert checks that the client of the call presented a
+// certificate signed by the authority for the endpoint.
+func verifyClientCert(ctx context.Context, cas *ClientCAs, endpoint string) error {
+	if cas == nil {
+		return nil
+	}
+
+	var pool *x509.CertPool
+	var kind string
+	switch {
+	case strings.HasPrefix(endpoint, "Runner"):
+		pool, kind = cas.Runner, "runner"
+	case strings.HasPrefix(endpoint, "Entrypoint"):
+		pool, kind = cas.Entrypoint, "entrypoint"
+	}
+	if pool == nil {
+		return nil
+	}
+
+	var certs []*x509.Certificate
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			certs = info.State.PeerCertificates
+		}
+	}
+	if len(certs) == 0 {
+		return status.Errorf(codes.Unauthenticated,
+			"a client certificate signed by the %s CA is required", kind)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return status.Errorf(codes.Unauthenticated,
+			"the client certificate isn't signed by the %s CA: %s", kind, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestVerifyClientCert(t *testing.T) {
+	require := require.New(t)
+
+	runnerCA, runnerKey := testCA(t, "runner")
+	entrypointCA, _ := testCA(t, "entrypoint")
+	runnerPool := x509.NewCertPool()
+	runnerPool.AddCert(runnerCA)
+	entrypointPool := x509.NewCertPool()
+	entrypointPool.AddCert(entrypointCA)
+	cas := &ClientCAs{Runner: runnerPool, Entrypoint: entrypointPool}
+
+	// peerCtx returns the context of a call with the client certificates.
+	peerCtx := func(certs ...*x509.Certificate) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{
+			AuthInfo: credentials.TLSInfo{
+				State: tls.ConnectionState{PeerCertificates: certs},
+			},
+		})
+	}
+	runnerCert := testClientCert(t, runnerCA, runnerKey)
+
+	// Runners need a certificate from the runner CA
+	require.NoError(verifyClientCert(peerCtx(runnerCert), cas, "RunnerJobStream"))
+	err := verifyClientCert(peerCtx(), cas, "RunnerJobStream")
+	require.Equal(codes.Unauthenticated, status.Code(err))
+
+	// The runner certificate can't be used for entrypoints
+	err = verifyClientCert(peerCtx(runnerCert), cas, "EntrypointConfig")
+	require.Equal(codes.Unauthenticated, status.Code(err))
+
+	// Other endpoints and servers without CAs don't need certificates
+	require.NoError(verifyClientCert(peerCtx(), cas, "ListProjects"))
+	require.NoError(verifyClientCert(peerCtx(), nil, "RunnerJobStream"))
+	require.NoError(verifyClientCert(peerCtx(), &ClientCAs{Runner: runnerPool}, "EntrypointConfig"))
+}
+
+// testCA creates a self-signed CA certificate.
+func testCA(t *testing.T, name string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+// testClientCert creates a client certificate signed by the CA.
+func testClientCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
@@ -3,7 +3,9 @@ package serverclient
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"time"
 
@@ -53,11 +55,14 @@ func Connect(ctx context.Context, opts ...ConnectOption) (*grpc.ClientConn, erro
 	if !cfg.Tls {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
 	} else {
-		if cfg.TlsSkipVerify {
-			grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(
-				credentials.NewTLS(&tls.Config{InsecureSkipVerify: true}),
-			))
+		tlsConfig, err := clientTLSConfig(cfg.TlsSkipVerify)
+		if err != nil {
+			return nil, err
 		}
+
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(
+			credentials.NewTLS(tlsConfig),
+		))
 	}
 	if cfg.Auth {
 		token := cfg.Token
@@ -199,6 +204,16 @@ func Optional() ConnectOption {
 	}
 }
 
+// Addr sets the address of the server, replacing the address from the
+// options before it. The other settings, such as TLS and the token, are
+// kept. This is used to reconnect to another server of the same cluster.
+func Addr(addr string) ConnectOption {
+	return func(c *connectConfig) error {
+		c.Addr = addr
+		return nil
+	}
+}
+
 // Timeout specifies a connection timeout. This defaults to 5 seconds.
 func Timeout(t time.Duration) ConnectOption {
 	return func(c *connectConfig) error {
@@ -218,6 +233,14 @@ const (
 	EnvServerTls           = "WAYPOINT_SERVER_TLS"
 	EnvServerTlsSkipVerify = "WAYPOINT_SERVER_TLS_SKIP_VERIFY"
 
+	// EnvServerTlsCAFile is the path to the CA certificates to verify the
+	// server with instead of the system roots. EnvServerTlsCertFile and
+	// EnvServerTlsKeyFile are the paths to a client certificate to connect
+	// with, which servers can require for runners.
+	EnvServerTlsCAFile   = "WAYPOINT_SERVER_TLS_CA_FILE"
+	EnvServerTlsCertFile = "WAYPOINT_SERVER_TLS_CERT_FILE"
+	EnvServerTlsKeyFile  = "WAYPOINT_SERVER_TLS_KEY_FILE"
+
 	// EnvServerToken is the token for authenticated with the server.
 	EnvServerToken = "WAYPOINT_SERVER_TOKEN"
 
@@ -225,6 +248,35 @@ const (
 	EnvContext = "WAYPOINT_CONTEXT"
 )
 
+// clientTLSConfig returns the TLS configuration to connect to the server
+// with the CA and client certificate from the environment.
+func clientTLSConfig(skipVerify bool) (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: skipVerify}
+
+	if path := os.Getenv(EnvServerTlsCAFile); path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %s", EnvServerTlsCAFile, err)
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", path)
+		}
+	}
+
+	if certFile := os.Getenv(EnvServerTlsCertFile); certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, os.Getenv(EnvServerTlsKeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("error loading the client certificate: %s", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
 // This is a weird type that only exists to satisify the interface required by
 // grpc.WithPerRPCCredentials. That api is designed to incorporate things like OAuth
 // but in our case, we really just want to send this static token through, but we still
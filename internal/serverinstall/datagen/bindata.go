@@ -77,7 +77,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _k8sInstallAppYaml = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x54\xcb\x6e\xdb\x3a\x10\xdd\xeb\x2b\x06\x46\x96\x57\x89\x6d\xe0\xb6\x28\x81\x2c\xda\x2c\xd2\x02\x49\x6a\xd4\x41\xbb\x28\xba\x18\x53\x13\x9b\x08\x5f\x25\x47\x6a\x0d\xc3\xff\x5e\x50\x96\x65\xca\x8f\x30\x40\x60\xf2\xcc\x9c\x33\x4f\x95\x65\x59\xa0\x57\xdf\x29\x44\xe5\xac\x80\x66\x52\xbc\x2a\x5b\x09\x98\x53\x68\x94\xa4\xc2\x10\x63\x85\x8c\xa2\x00\xb0\x68\x48\xc0\x66\x03\xd7\x1d\xfa\x84\x86\x60\xbb\xed\xa0\xe8\x51\x76\xf8\xd3\xfe\xba\x43\x37\x1b\x50\x2f\xbd\xd7\x47\x6b\x1d\x23\x2b\x67\x23\x94\x2d\x8e\x87\x17\xd1\x9a\x97\x10\xd0\x2e\x09\xae\x5e\x69\xfd\x1f\x5c\x35\xa8\x6b\x02\x71\x7b\x96\xa2\x65\x68\x35\x92\x35\x6c\xb7\x02\x46\xe9\xb2\x73\xda\x6e\x47\x1d\x23\xd9\x6a\x1f\x4d\x7f\x89\x9e\x64\x52\xf4\x2e\x70\x2b\x5d\xb6\x3f\x05\x7c\x78\x3f\x9e\xb4\xb4\xbb\x9c\x97\xc1\xcb\x21\x3a\xcd\xd0\x15\xb3\x2f\x00\x22\x69\x92\xec\x82\x68\x21\xf4\x5e\xc0\x1f\x5c\x7b\xa7\x2c\x97\x91\x42\x43\xa1\x00\xe0\xb5\x27\x01\x0f\x0e\xab\x4f\xa8\xd1\x4a\x0a\xc5\x71\x0f\xd0\xfb\x78\x73\x68\x04\x23\xd3\x4b\xad\xe7\xc4\x67\x9a\x71\x2a\xf0\x76\x27\x34\x2e\x48\xc7\x37\x22\xdc\x57\x64\x98\x8c\x41\x96\xab\x87\xcc\xf7\x62\x7e\xf1\x30\x19\x67\xd3\x27\xe3\x35\x32\x75\xb4\x59\x3e\xe9\xe8\x81\xc2\x45\x0d\x80\x7d\x94\x5d\xe3\xd3\x70\x7d\x31\xb8\xa4\x59\xad\xf5\x9c\x64\x20\xee\x26\x2b\x1d\x35\x44\x7a\xfe\x32\x1b\xe8\x63\xef\xde\x79\x30\x39\xe9\x48\x67\x19\x95\xa5\x70\xc2\x93\xc5\xd7\xcb\x1e\xd6\x85\x42\xab\x71\x20\xca\x02\x9b\x39\xad\xe4\xfa\x28\x92\xdd\x63\x6e\x2f\x9d\x31\x68\xab\x43\x7d\x4a\x18\xed\xab\x33\x3a\x14\x2d\x2c\x63\x6e\x72\x14\x56\x09\xa1\xb6\xd9\xad\x44\x29\xc9\x73\xc9\x2e\xe6\xaf\x4d\xd3\xe4\xd7\x6a\x71\x7b\x93\x3a\xd5\xfe\xbb\xae\x16\x39\xa6\x55\x64\xb2\x65\xda\x91\xdb\xf1\x75\xfb\x27\xfa\xfd\x19\xda\xa4\x4d\xc9\x6d\xa6\xbd\x4d\xbf\x81\x7b\x97\xbe\xce\xb3\xe1\x42\xee\xce\x60\x2d\x2f\xbb\x4c\x4f\x5c\xba\x5d\xed\xe6\x4d\x35\x64\x29\xc6\x59\x70\x0b\x12\x99\x2d\x4b\x3f\x77\xf2\x95\x38\x7f\x84\x6e\xfb\x07\xaa\x17\x29\x92\xd0\xfd\x09\x01\xf2\x4a\xc0\xcd\x19\xd2\x41\x5c\xe9\x44\xb9\xa2\x14\xf0\xe7\xe7\xe7\xd9\xbc\x47\x02\x45\x57\x07\x49\x31\xe7\x0d\xf4\xbb\xa6\xc8\x71\xa8\x25\x7d\x2d\x60\x32\x1e\x9b\xc1\xab\x21\xe3\xc2\x5a\xc0\xf4\xff\x77\x8f\xaa\x47\x1a\xa7\x6b\x43\x8f\xae\xb6\xc3\x26\xec\x6a\x96\x7a\x9e\x91\x98\x64\x36\xdb\x65\x92\x41\x91\x64\x1d\x14\xaf\xef\x9c\x65\xfa\x9b\x25\xfe\x12\xef\x83\xab\x7d\x1b\xcc\xb8\xd8\x8b\xdd\x69\x54\xe6\xb9\xfb\x1c\x74\xdf\xde\xe3\xef\xc1\x91\x7c\xbe\xf5\x69\x6a\x63\x7c\x74\x15\x45\x01\x3f\x61\xf4\x8d\xb0\xfa\x11\x14\xd3\x57\x2b\x69\x04\xbf\x8a\x8b\xf5\x3a\x57\xad\xc8\x2e\xb4\xdb\x3a\xb9\x57\xc5\xbf\x00\x00\x00\xff\xff\xc2\xa7\x1b\xde\x14\x07\x00\x00"
+var _k8sInstallAppYaml = "\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x54\x4b\x6f\x13\x31\x10\xbe\xef\xaf\xb0\xa2\x1e\x71\xf3\x90\x00\x61\xa9\x07\xe8\xa1\x20\xb5\x25\xa2\x15\x1c\x10\x07\xc7\x3b\x4d\xac\xfa\x85\xed\x5d\x08\x55\xfe\x3b\xb6\x77\xb3\xb1\xb7\x49\x25\x84\x23\x45\xeb\xf9\x66\xe6\x9b\xa7\x31\xc6\x15\x35\xfc\x2b\x58\xc7\xb5\x22\xa8\x9d\x57\x8f\x5c\xd5\x04\xdd\x81\x6d\x39\x83\x4a\x82\xa7\x35\xf5\x94\x54\x08\x29\x2a\x81\xa0\xa7\x27\x74\xde\xa3\xb7\x41\x80\x76\xbb\x1e\x72\x86\xb2\x1e\xbf\xdd\x5f\x3b\x34\x88\xf8\xc3\x60\xf5\x5e\x29\xed\xa9\x0f\x7c\x0e\xe1\x84\xd3\x83\x84\x24\x75\x8c\x2c\x55\x6b\x40\x67\x8f\xb0\x7d\x85\xce\x5a\x2a\x1a\x40\xe4\xe2\xa8\x8b\xe4\x21\x71\x44\xed\x70\x25\x68\x12\x2f\x9d\xd1\x6e\x37\xe9\x3d\x82\xaa\xf7\xd1\x0c\x17\x67\x80\x45\x46\xa3\xad\x4f\xd4\x38\x7d\x12\xf4\xee\xed\x6c\x9e\xdc\x76\x39\xaf\xad\x61\x25\xba\xc8\xd0\x8d\xf7\x26\x5c\x1d\x08\x60\x5e\x5b\x92\x20\x6a\x0c\x41\xbf\xe8\xd6\x68\xae\x3c\x76\x21\x6e\xb0\x01\xf0\x5b\x13\x2c\xae\x35\xad\x3f\x50\x41\x15\x0b\x42\x3c\xea\x41\xb0\x74\xd3\x43\x23\x42\x9e\xf0\xd0\x88\x3b\xf0\x47\x9a\xf1\x9c\xe0\xe5\x4e\x08\xba\x02\xe1\x5e\x88\x70\x5f\x91\x32\x19\x49\x3d\xdb\x5c\x67\xb6\x27\xf3\x73\x87\xc9\x38\x9a\x3e\x48\x23\x42\x42\xbd\xdb\x2c\x9f\x78\x44\xc1\x70\x92\x23\xb0\xf4\x51\xf6\x8d\x8f\xc3\xf5\x49\xd2\x35\x2c\x1b\x11\x0a\xc5\x2c\xf8\x7e\xb2\xe2\xe1\x25\x32\xf8\xc7\xd9\x40\x8f\xad\x07\xe3\x62\x72\xe2\x61\x5a\x79\xca\x55\x68\xd6\xd8\x4f\x16\xdf\x40\x7b\x58\x17\xb0\x89\xe3\xe0\x28\x0b\x6c\xa9\x05\x67\xdb\x51\x24\x9d\x30\xd7\x67\x5a\x4a\x1a\x66\x62\x10\x60\x34\xd9\x57\x67\x72\x28\x9a\x5d\xbb\x5c\x65\x14\x56\x58\xad\x46\x65\x37\x4c\x19\x03\xe3\xb1\xd7\x2e\x97\xb6\x6d\x9b\x5f\xeb\xd5\xc5\x34\x76\x2a\xfd\x9d\xd7\xab\x1c\x13\xdc\x79\x50\x38\xee\xc8\xc5\xec\x3c\xfd\xc8\xb0\x3f\xa5\x4e\xdc\x94\x5c\x67\x31\xe8\x0c\x1b\xb8\x37\x19\xea\xbc\x2c\x17\xb2\x3b\xc5\x5a\x9e\x36\x59\x3c\x33\xe9\x77\xb5\x3b\x82\xb7\xa0\xc0\xb9\xa5\xd5\x2b\x20\x99\x6e\xd4\xba\x02\x9f\x8b\x42\x88\xd4\x6f\x08\x9a\x6e\x80\x0a\xbf\xf9\x53\x42\x89\xb0\xf0\x1d\x8f\x63\x1b\x88\xa4\x1f\xef\xef\x97\x77\x03\x62\x81\xd6\xfc\x9f\x69\xa3\xd5\xf6\xbf\x58\x9d\x6e\x2c\x03\x97\x7b\xb7\xf0\xb3\x01\xe7\x5d\xc9\xc8\x4c\x43\xd0\x7c\x36\x93\x85\x54\x82\xd4\x36\x0c\xe9\xe2\xf5\x9b\x1b\x3e\x20\xad\x16\x8d\x84\x1b\xdd\xa8\xb2\x7d\x5d\xb5\xe3\xb4\x64\x4e\x64\x54\x5b\x76\xf9\x64\x90\x03\xd6\x58\xee\xb7\x97\xa1\x7f\xf0\x3b\x4b\xff\xc1\x5d\x59\xdd\x98\x14\xcc\xac\xda\x93\x5d\x0a\xca\xe5\x7d\xff\x90\xf4\xaf\xf6\xf8\x25\x19\xd1\xe7\xef\x45\x9c\x77\xe7\x6e\x74\x1d\x6c\xd1\x77\x34\xf9\x12\x0a\xfb\x2d\xd0\xc3\xe7\xf0\x1a\x4f\xd0\x8f\xea\x64\xbd\x8e\x55\xcb\x85\x37\x32\xed\xf9\xfc\x8a\x57\x7f\x01\xf2\xe8\xac\x37\x4e\x07\x00\x00"
 
 func k8sInstallAppYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -2,6 +2,10 @@ package serverinstall
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -169,3 +173,86 @@ func InstallDocker(
 
 	return &clicfg, &addr, httpAddr, nil
 }
+
+// UpgradeDocker replaces the Waypoint server container with one that runs
+// the image in scfg.ServerImage. The configuration of the container, such
+// as the data volume and ports, is kept.
+func UpgradeDocker(ctx context.Context, ui terminal.UI, scfg *Config) (*UpgradeResult, error) {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	s := sg.Add("Initializing Docker client...")
+	defer func() { s.Abort() }()
+
+	cli, err := client.NewClientWithOpts(client.FromEnv)
+	if err != nil {
+		return nil, err
+	}
+	cli.NegotiateAPIVersion(ctx)
+
+	s.Update("Finding the existing Waypoint server...")
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All: true,
+		Filters: filters.NewArgs(filters.KeyValuePair{
+			Key:   "label",
+			Value: "waypoint-type=server",
+		}),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no Waypoint server container was found")
+	}
+
+	info, err := cli.ContainerInspect(ctx, containers[0].ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Update("Pulling image %s...", scfg.ServerImage)
+	out, err := cli.ImagePull(ctx, scfg.ServerImage, types.ImagePullOptions{})
+	if err == nil {
+		_, err = io.Copy(ioutil.Discard, out)
+		out.Close()
+	}
+	if err != nil {
+		// The image may only exist locally, such as for development builds
+		if _, _, ierr := cli.ImageInspectWithRaw(ctx, scfg.ServerImage); ierr != nil {
+			return nil, err
+		}
+	}
+
+	s.Update("Replacing the server container...")
+	if err := cli.ContainerRemove(ctx, info.ID, types.ContainerRemoveOptions{
+		Force: true,
+	}); err != nil {
+		return nil, err
+	}
+
+	cfg := *info.Config
+	cfg.Image = scfg.ServerImage
+
+	netconfig := network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{},
+	}
+	for name := range info.NetworkSettings.Networks {
+		netconfig.EndpointsConfig[name] = &network.EndpointSettings{}
+	}
+
+	cr, err := cli.ContainerCreate(ctx, &cfg, info.HostConfig, &netconfig,
+		strings.TrimPrefix(info.Name, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	err = cli.ContainerStart(ctx, cr.ID, types.ContainerStartOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Update("Server container started with %s", scfg.ServerImage)
+	s.Done()
+
+	return &UpgradeResult{PreviousImage: info.Config.Image}, nil
+}
@@ -0,0 +1,85 @@
+package serverinstall
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+)
+
+// UpgradeKubernetes updates the waypoint-server StatefulSet in
+// scfg.Namespace to run the image in scfg.ServerImage and waits for the
+// updated pods to be ready.
+func UpgradeKubernetes(ctx context.Context, ui terminal.UI, scfg *Config) (*UpgradeResult, error) {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	s := sg.Add("Initializing Kubernetes client...")
+	defer func() { s.Abort() }()
+
+	clientconfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{},
+	).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(clientconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Update("Finding the existing Waypoint server...")
+	statefulSets := clientset.AppsV1().StatefulSets(scfg.Namespace)
+	ss, err := statefulSets.Get(ctx, "waypoint-server", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var result UpgradeResult
+	containers := ss.Spec.Template.Spec.Containers
+	for i := range containers {
+		if containers[i].Name == "server" {
+			result.PreviousImage = containers[i].Image
+			containers[i].Image = scfg.ServerImage
+		}
+	}
+	if result.PreviousImage == "" {
+		return nil, fmt.Errorf("waypoint-server StatefulSet found but it has no server container")
+	}
+
+	s.Update("Updating the waypoint-server StatefulSet to %s", scfg.ServerImage)
+	ss, err = statefulSets.Update(ctx, ss, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Update("Waiting for Kubernetes StatefulSet to be ready...")
+	generation := ss.Generation
+	err = wait.PollImmediate(2*time.Second, 10*time.Minute, func() (bool, error) {
+		ss, err := statefulSets.Get(ctx, "waypoint-server", metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		status := ss.Status
+		return status.ObservedGeneration >= generation &&
+			status.UpdateRevision == status.CurrentRevision &&
+			status.ReadyReplicas == status.Replicas, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Update("Kubernetes StatefulSet reporting ready")
+	s.Done()
+
+	return &result, nil
+}
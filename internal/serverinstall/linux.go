@@ -0,0 +1,480 @@
+package serverinstall
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/waypoint-plugin-sdk/terminal"
+	"github.com/hashicorp/waypoint/internal/clicontext"
+	configpkg "github.com/hashicorp/waypoint/internal/config"
+	"github.com/hashicorp/waypoint/internal/pkg/flag"
+	pb "github.com/hashicorp/waypoint/internal/server/gen"
+	"github.com/hashicorp/waypoint/internal/serverclient"
+)
+
+var (
+	linuxBinaryF  string
+	linuxUserF    string
+	linuxDataDirF string
+	linuxLogDirF  string
+)
+
+const (
+	linuxUnitDir      = "/etc/systemd/system"
+	linuxLogrotateDir = "/etc/logrotate.d"
+	linuxConfigDir    = "/etc/waypoint"
+)
+
+// linuxService describes a Waypoint binary that is run as a systemd
+// service on a Linux host.
+type linuxService struct {
+	Name        string // systemd unit name, without the ".service" suffix
+	Description string
+	Binary      string
+	Args        []string
+	User        string
+	Groups      []string
+	DataDir     string
+	LogDir      string
+	EnvFile     string
+}
+
+func (svc *linuxService) LogFile() string {
+	return filepath.Join(svc.LogDir, svc.Name+".log")
+}
+
+func (svc *linuxService) UnitPath() string {
+	return filepath.Join(linuxUnitDir, svc.Name+".service")
+}
+
+func (svc *linuxService) ExecStart() string {
+	return strings.Join(append([]string{svc.Binary}, svc.Args...), " ")
+}
+
+// InstallLinux installs the Waypoint server as a systemd service on the
+// Linux host the CLI is running on.
+func InstallLinux(
+	ctx context.Context, ui terminal.UI, scfg *Config) (
+	*clicontext.Config, *pb.ServerConfig_AdvertiseAddr, string, error,
+) {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	s := sg.Add("Checking host requirements...")
+	defer func() { s.Abort() }()
+
+	if err := linuxPreflight(); err != nil {
+		return nil, nil, "", err
+	}
+
+	grpcPort := "9701"
+	httpPort := "9702"
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var (
+		clicfg   clicontext.Config
+		addr     pb.ServerConfig_AdvertiseAddr
+		httpAddr string
+	)
+
+	clicfg.Server = configpkg.Server{
+		Address:       "localhost:" + grpcPort,
+		Tls:           true,
+		TlsSkipVerify: true,
+	}
+
+	addr.Addr = hostname + ":" + grpcPort
+	addr.Tls = true
+	addr.TlsSkipVerify = true
+
+	httpAddr = hostname + ":" + httpPort
+
+	svc := &linuxService{
+		Name:        "waypoint-server",
+		Description: "Waypoint Server",
+		Binary:      linuxBinaryF,
+		Args: []string{
+			"server", "run", "-accept-tos", "-vvv",
+			"-db=" + filepath.Join(linuxDataDirF, "data.db"),
+			"-listen-grpc=0.0.0.0:" + grpcPort,
+			"-listen-http=0.0.0.0:" + httpPort,
+		},
+		User:    linuxUserF,
+		DataDir: linuxDataDirF,
+		LogDir:  linuxLogDirF,
+	}
+
+	// If we already have a server, bolt.
+	if _, err := os.Stat(svc.UnitPath()); err == nil {
+		s.Update("Detected existing Waypoint server.")
+		s.Status(terminal.StatusWarn)
+		s.Done()
+		return &clicfg, &addr, httpAddr, nil
+	}
+
+	if err := installLinuxService(ctx, s, svc); err != nil {
+		return nil, nil, "", err
+	}
+
+	s.Done()
+	s = sg.Add("Waiting for Waypoint server to become ready...")
+
+	err = waitForAddr(ctx, clicfg.Server.Address, 2*time.Minute)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf(
+			"server did not become ready, check %s or 'journalctl -u %s': %s",
+			svc.LogFile(), svc.Name, err)
+	}
+
+	s.Update("Waypoint server is running as systemd service %q", svc.Name)
+	s.Done()
+
+	return &clicfg, &addr, httpAddr, nil
+}
+
+// InstallLinuxRunner installs a Waypoint runner as a systemd service on the
+// Linux host the CLI is running on. The runner is configured to connect to
+// the server described by cfg.
+func InstallLinuxRunner(ctx context.Context, ui terminal.UI, cfg *clicontext.Config) error {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	s := sg.Add("Checking host requirements...")
+	defer func() { s.Abort() }()
+
+	if err := linuxPreflight(); err != nil {
+		return err
+	}
+
+	svc := &linuxService{
+		Name:        "waypoint-runner",
+		Description: "Waypoint Runner",
+		Binary:      linuxBinaryF,
+		Args:        []string{"runner", "agent", "-vv"},
+		User:        linuxUserF,
+		DataDir:     filepath.Join(linuxDataDirF, "runner"),
+		LogDir:      linuxLogDirF,
+		EnvFile:     filepath.Join(linuxConfigDir, "runner.env"),
+	}
+
+	// The runner does most of its work through Docker, so if the host
+	// has a docker group we allow the runner to talk to the daemon.
+	if _, err := user.LookupGroup("docker"); err == nil {
+		svc.Groups = append(svc.Groups, "docker")
+	}
+
+	if _, err := os.Stat(svc.UnitPath()); err == nil {
+		s.Update("Detected existing Waypoint runner.")
+		s.Status(terminal.StatusWarn)
+		s.Done()
+		return nil
+	}
+
+	// Write the connection information for the runner. This contains the
+	// auth token so it is only readable by root, systemd reads it before
+	// dropping privileges.
+	s.Update("Writing runner configuration to %s...", svc.EnvFile)
+	if err := os.MkdirAll(linuxConfigDir, 0755); err != nil {
+		return err
+	}
+
+	var env bytes.Buffer
+	fmt.Fprintf(&env, "%s=%s\n", serverclient.EnvServerAddr, cfg.Server.Address)
+	if cfg.Server.Tls {
+		fmt.Fprintf(&env, "%s=1\n", serverclient.EnvServerTls)
+	}
+	if cfg.Server.TlsSkipVerify {
+		fmt.Fprintf(&env, "%s=1\n", serverclient.EnvServerTlsSkipVerify)
+	}
+	if cfg.Server.AuthToken != "" {
+		fmt.Fprintf(&env, "%s=%s\n", serverclient.EnvServerToken, cfg.Server.AuthToken)
+	}
+	if err := ioutil.WriteFile(svc.EnvFile, env.Bytes(), 0600); err != nil {
+		return err
+	}
+
+	if err := installLinuxService(ctx, s, svc); err != nil {
+		return err
+	}
+
+	s.Update("Waypoint runner is running as systemd service %q", svc.Name)
+	s.Done()
+
+	return nil
+}
+
+// installLinuxService creates the service user and directories, writes the
+// systemd unit and logrotate configuration, and starts the service.
+func installLinuxService(ctx context.Context, s terminal.Step, svc *linuxService) error {
+	s.Update("Installing %s binary to %s...", svc.Name, svc.Binary)
+	if err := installLinuxBinary(svc.Binary); err != nil {
+		return err
+	}
+
+	s.Update("Creating system user %q...", svc.User)
+	u, err := user.Lookup(svc.User)
+	if err != nil {
+		if _, ok := err.(user.UnknownUserError); !ok {
+			return err
+		}
+
+		if err := runLinuxCmd(ctx, s.TermOutput(), "useradd",
+			"--system",
+			"--user-group",
+			"--no-create-home",
+			"--home-dir", linuxDataDirF,
+			"--shell", "/usr/sbin/nologin",
+			svc.User,
+		); err != nil {
+			return err
+		}
+
+		if u, err = user.Lookup(svc.User); err != nil {
+			return err
+		}
+	}
+
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return err
+	}
+
+	// The data dir root is included since services may use a subdirectory
+	// of it and the root must be traversable by the service user.
+	s.Update("Creating data and log directories...")
+	for _, dir := range []string{linuxDataDirF, svc.DataDir, svc.LogDir} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return err
+		}
+
+		if err := os.Chown(dir, uid, gid); err != nil {
+			return err
+		}
+	}
+
+	s.Update("Writing systemd unit %s...", svc.UnitPath())
+	if err := renderLinuxFile(svc.UnitPath(), linuxUnitTpl, svc); err != nil {
+		return err
+	}
+
+	logrotatePath := filepath.Join(linuxLogrotateDir, svc.Name)
+	s.Update("Writing log rotation config %s...", logrotatePath)
+	if err := renderLinuxFile(logrotatePath, linuxLogrotateTpl, svc); err != nil {
+		return err
+	}
+
+	s.Update("Starting %s...", svc.Name)
+	if err := runLinuxCmd(ctx, s.TermOutput(), "systemctl", "daemon-reload"); err != nil {
+		return err
+	}
+
+	return runLinuxCmd(ctx, s.TermOutput(), "systemctl", "enable", "--now", svc.Name)
+}
+
+// installLinuxBinary copies the currently running binary to path unless
+// a binary already exists there.
+func installLinuxBinary(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(self)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
+
+func linuxPreflight() error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("the linux platform must be installed from the Linux host itself")
+	}
+
+	if os.Geteuid() != 0 {
+		return fmt.Errorf("installing systemd services requires root, please re-run with sudo")
+	}
+
+	for _, bin := range []string{"systemctl", "useradd"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			return fmt.Errorf("%q was not found on this host, is this a systemd based distribution?", bin)
+		}
+	}
+
+	return nil
+}
+
+func renderLinuxFile(path, tpl string, svc *linuxService) error {
+	t, err := template.New(filepath.Base(path)).Parse(tpl)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, svc); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+func runLinuxCmd(ctx context.Context, out io.Writer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// waitForAddr waits until a TCP connection can be made to addr.
+func waitForAddr(ctx context.Context, addr string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		conn, err := net.DialTimeout("tcp", addr, 1*time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+
+		select {
+		case <-time.After(1 * time.Second):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// LinuxFlags config values for Linux
+func LinuxFlags(f *flag.Set) {
+	f.StringVar(&flag.StringVar{
+		Name:    "linux-binary",
+		Target:  &linuxBinaryF,
+		Default: "/usr/local/bin/waypoint",
+		Usage: "Path the waypoint binary is run from if using the Linux platform. " +
+			"If no binary exists at this path, the running binary is copied there.",
+	})
+
+	f.StringVar(&flag.StringVar{
+		Name:    "linux-user",
+		Target:  &linuxUserF,
+		Default: "waypoint",
+		Usage:   "System user to run as if using the Linux platform. It is created if it doesn't exist.",
+	})
+
+	f.StringVar(&flag.StringVar{
+		Name:    "linux-data-dir",
+		Target:  &linuxDataDirF,
+		Default: "/var/lib/waypoint",
+		Usage:   "Directory for persistent data if using the Linux platform",
+	})
+
+	f.StringVar(&flag.StringVar{
+		Name:    "linux-log-dir",
+		Target:  &linuxLogDirF,
+		Default: "/var/log/waypoint",
+		Usage:   "Directory for log files if using the Linux platform",
+	})
+}
+
+const linuxUnitTpl = `[Unit]
+Description={{.Description}}
+Documentation=https://waypointproject.io/docs
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=simple
+User={{.User}}
+Group={{.User}}
+{{- if .Groups}}
+SupplementaryGroups={{range $i, $g := .Groups}}{{if $i}} {{end}}{{$g}}{{end}}
+{{- end}}
+{{- if .EnvFile}}
+EnvironmentFile={{.EnvFile}}
+{{- end}}
+WorkingDirectory={{.DataDir}}
+ExecStart={{.ExecStart}}
+Restart=on-failure
+RestartSec=5
+LimitNOFILE=65536
+StandardOutput=append:{{.LogFile}}
+StandardError=inherit
+
+# Hardening. The service may only write to its data and log directories.
+NoNewPrivileges=yes
+PrivateTmp=yes
+PrivateDevices=yes
+ProtectSystem=strict
+ProtectHome=yes
+ProtectKernelTunables=yes
+ProtectKernelModules=yes
+ProtectControlGroups=yes
+RestrictSUIDSGID=yes
+RestrictRealtime=yes
+LockPersonality=yes
+CapabilityBoundingSet=
+AmbientCapabilities=
+ReadWritePaths={{.DataDir}} {{.LogDir}}
+
+[Install]
+WantedBy=multi-user.target
+`
+
+const linuxLogrotateTpl = `{{.LogFile}} {
+    daily
+    rotate 7
+    maxsize 100M
+    missingok
+    notifempty
+    compress
+    delaycompress
+    copytruncate
+    su {{.User}} {{.User}}
+}
+`
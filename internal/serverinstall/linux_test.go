@@ -0,0 +1,121 @@
+package serverinstall
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderLinuxFile(t *testing.T) {
+	svc := &linuxService{
+		Name:        "waypoint-server",
+		Description: "Waypoint Server",
+		Binary:      "/usr/local/bin/waypoint",
+		Args:        []string{"server", "run", "-accept-tos"},
+		User:        "waypoint",
+		DataDir:     "/var/lib/waypoint",
+		LogDir:      "/var/log/waypoint",
+	}
+
+	withGroups := *svc
+	withGroups.Groups = []string{"docker", "adm"}
+	withGroups.EnvFile = "/etc/waypoint/runner.env"
+
+	cases := []struct {
+		Name     string
+		Tpl      string
+		Svc      *linuxService
+		Contains []string
+		Excludes []string
+		Err      bool
+	}{
+		{
+			"unit",
+			linuxUnitTpl,
+			svc,
+			[]string{
+				"Description=Waypoint Server\n",
+				"User=waypoint\nGroup=waypoint\nWorkingDirectory=/var/lib/waypoint\n",
+				"ExecStart=/usr/local/bin/waypoint server run -accept-tos\n",
+				"StandardOutput=append:/var/log/waypoint/waypoint-server.log\n",
+				"ReadWritePaths=/var/lib/waypoint /var/log/waypoint\n",
+			},
+			[]string{"SupplementaryGroups", "EnvironmentFile"},
+			false,
+		},
+
+		{
+			"unit with groups and env file",
+			linuxUnitTpl,
+			&withGroups,
+			[]string{
+				"Group=waypoint\nSupplementaryGroups=docker adm\n",
+				"EnvironmentFile=/etc/waypoint/runner.env\nWorkingDirectory=",
+			},
+			nil,
+			false,
+		},
+
+		{
+			"logrotate",
+			linuxLogrotateTpl,
+			svc,
+			[]string{
+				"/var/log/waypoint/waypoint-server.log {\n",
+				"su waypoint waypoint\n",
+			},
+			nil,
+			false,
+		},
+
+		{
+			"invalid template",
+			"{{.Name",
+			svc,
+			nil,
+			nil,
+			true,
+		},
+
+		{
+			"unknown field",
+			"{{.Nope}}",
+			svc,
+			nil,
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			require := require.New(t)
+
+			td, err := ioutil.TempDir("", "waypoint")
+			require.NoError(err)
+			defer os.RemoveAll(td)
+
+			path := filepath.Join(td, "out")
+			err = renderLinuxFile(path, tt.Tpl, tt.Svc)
+			if tt.Err {
+				require.Error(err)
+				_, err := os.Stat(path)
+				require.True(os.IsNotExist(err))
+				return
+			}
+			require.NoError(err)
+
+			data, err := ioutil.ReadFile(path)
+			require.NoError(err)
+			for _, v := range tt.Contains {
+				require.Contains(string(data), v)
+			}
+			for _, v := range tt.Excludes {
+				require.NotContains(string(data), v)
+			}
+		})
+	}
+}
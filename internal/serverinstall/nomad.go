@@ -96,6 +96,115 @@ func InstallNomad(
 		return nil, nil, "", err
 	}
 
+	allocID, err := nomadWaitForAlloc(ctx, s, client, resp)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	serverAddr, err := getAddrFromAllocID(allocID, client)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	hAddr, err := getHTTPFromAllocID(allocID, client)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	httpAddr = hAddr
+	addr.Addr = serverAddr
+	clicfg = clicontext.Config{
+		Server: configpkg.Server{
+			Address:       addr.Addr,
+			Tls:           true,
+			TlsSkipVerify: true, // always for now
+		},
+	}
+
+	s.Update("Nomad allocation ready")
+	s.Done()
+
+	return &clicfg, &addr, httpAddr, nil
+}
+
+// UpgradeNomad updates the waypoint-server job to run the image in
+// scfg.ServerImage and waits for the new allocation to be running.
+func UpgradeNomad(ctx context.Context, ui terminal.UI, scfg *Config) (*UpgradeResult, error) {
+	sg := ui.StepGroup()
+	defer sg.Wait()
+
+	s := sg.Add("Initializing Nomad client...")
+	defer func() { s.Abort() }()
+
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	s.Update("Finding the existing Waypoint server...")
+	job, _, err := client.Jobs().Info("waypoint-server", &api.QueryOptions{
+		Namespace: nomadNamespaceF,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var task *api.Task
+	for _, tg := range job.TaskGroups {
+		for _, t := range tg.Tasks {
+			if t.Name == "server" {
+				task = t
+
+				// Keep the data directory on the node across allocations
+				// for servers installed before this was set.
+				tg.EphemeralDisk = nomadEphemeralDisk()
+			}
+		}
+	}
+	if task == nil {
+		return nil, fmt.Errorf("waypoint-server job found but it has no server task")
+	}
+
+	var result UpgradeResult
+	result.PreviousImage, _ = task.Config["image"].(string)
+	task.Config["image"] = scfg.ServerImage
+
+	s.Update("Updating the waypoint-server job to %s", scfg.ServerImage)
+	resp, _, err := client.Jobs().Register(job, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	allocID, err := nomadWaitForAlloc(ctx, s, client, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// The server port is dynamic so the address changes with the allocation
+	result.Address, err = getAddrFromAllocID(allocID, client)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Update("Nomad allocation ready")
+	s.Done()
+
+	return &result, nil
+}
+
+// nomadEphemeralDisk returns the ephemeral disk of the server task group.
+// The data is in the alloc dir so it must move with new allocations.
+func nomadEphemeralDisk() *api.EphemeralDisk {
+	sticky := true
+	return &api.EphemeralDisk{
+		Sticky:  &sticky,
+		Migrate: &sticky,
+	}
+}
+
+// nomadWaitForAlloc waits for the allocation of the evaluation of a job
+// registration to be running and returns its ID.
+func nomadWaitForAlloc(
+	ctx context.Context, s terminal.Step, client *api.Client, resp *api.JobRegisterResponse,
+) (string, error) {
 	s.Update("Waiting for allocation to be scheduled")
 EVAL:
 	qopts := &api.QueryOptions{
@@ -104,7 +213,7 @@ EVAL:
 
 	eval, meta, err := client.Evaluations().Info(resp.EvalID, qopts)
 	if err != nil {
-		return nil, nil, "", err
+		return "", err
 	}
 	qopts.WaitIndex = meta.LastIndex
 	switch eval.Status {
@@ -115,21 +224,20 @@ EVAL:
 	case "failed", "canceled", "blocked":
 		s.Update("Nomad failed to schedule the waypoint-server")
 		s.Status(terminal.StatusError)
-		return nil, nil, "", fmt.Errorf("nomad evaluation did not transition to 'complete'")
+		return "", fmt.Errorf("nomad evaluation did not transition to 'complete'")
 	default:
-		return nil, nil, "", fmt.Errorf("unknown eval status: %q", eval.Status)
+		return "", fmt.Errorf("unknown eval status: %q", eval.Status)
 	}
 
 	var allocID string
-
 	for {
 		allocs, qmeta, err := client.Evaluations().Allocations(eval.ID, qopts)
 		if err != nil {
-			return nil, nil, "", err
+			return "", err
 		}
 		qopts.WaitIndex = qmeta.LastIndex
 		if len(allocs) == 0 {
-			return nil, nil, "", fmt.Errorf("no allocations found after evaluation completed")
+			return "", fmt.Errorf("no allocations found after evaluation completed")
 		}
 
 		switch allocs[0].ClientStatus {
@@ -140,7 +248,7 @@ EVAL:
 			s.Update(fmt.Sprintf("Waiting for allocation %q to start", allocs[0].ID))
 			// retry
 		default:
-			return nil, nil, "", fmt.Errorf("allocation failed")
+			return "", fmt.Errorf("allocation failed")
 
 		}
 
@@ -151,32 +259,11 @@ EVAL:
 		select {
 		case <-time.After(500 * time.Millisecond):
 		case <-ctx.Done():
-			return nil, nil, "", ctx.Err()
+			return "", ctx.Err()
 		}
 	}
 
-	serverAddr, err := getAddrFromAllocID(allocID, client)
-	if err != nil {
-		return nil, nil, "", err
-	}
-	hAddr, err := getHTTPFromAllocID(allocID, client)
-	if err != nil {
-		return nil, nil, "", err
-	}
-	httpAddr = hAddr
-	addr.Addr = serverAddr
-	clicfg = clicontext.Config{
-		Server: configpkg.Server{
-			Address:       addr.Addr,
-			Tls:           true,
-			TlsSkipVerify: true, // always for now
-		},
-	}
-
-	s.Update("Nomad allocation ready")
-	s.Done()
-
-	return &clicfg, &addr, httpAddr, nil
+	return allocID, nil
 }
 
 func waypointNomadJob(scfg *Config) *api.Job {
@@ -185,6 +272,7 @@ func waypointNomadJob(scfg *Config) *api.Job {
 	job.Datacenters = nomadDatacentersF
 	job.Meta = scfg.ServiceAnnotations
 	tg := api.NewTaskGroup("waypoint-server", 1)
+	tg.EphemeralDisk = nomadEphemeralDisk()
 	tg.Networks = []*api.NetworkResource{
 		{
 			Mode: "host",
@@ -0,0 +1,12 @@
+package serverinstall
+
+// UpgradeResult is the result of upgrading an installed server.
+type UpgradeResult struct {
+	// PreviousImage is the image the server ran before the upgrade. The
+	// upgrade is rolled back by upgrading to this image.
+	PreviousImage string
+
+	// Address is the gRPC address of the server if the upgrade changed
+	// it. This is empty if the address is unchanged.
+	Address string
+}
@@ -1,12 +1,15 @@
 export default [
   'build',
+  'cp',
   'deploy',
   'destroy',
+  'events',
   'exec',
   'init',
   'install',
   'logs',
   'release',
+  'status',
   'ui',
   'up',
   '---------',
@@ -14,8 +17,15 @@ export default [
   'artifact-list-builds',
   'artifact-list',
   'artifact-push',
+  'audit-list',
+  'build-inspect',
+  'build-list',
   'config-get',
   'config-set',
+  'config-source-get',
+  'config-source-set',
+  'config-sync',
+  'config-unset',
   'context-clear',
   'context-create',
   'context-delete',
@@ -25,18 +35,67 @@ export default [
   'context-verify',
   'deployment-deploy',
   'deployment-destroy',
+  'deployment-diff',
+  'deployment-inspect',
   'deployment-list',
+  'deployment-scale',
+  'doctor',
+  'exec-recording-get',
+  'exec-recording-list',
   'hostname-delete',
   'hostname-list',
   'hostname-register',
+  'job-cancel',
+  'job-get-stream',
+  'job-inspect',
+  'job-list',
+  'login',
+  'notification-create',
+  'notification-delete',
+  'notification-list',
+  'organization-create',
+  'organization-delete',
+  'organization-list',
+  'pipeline-run',
   'plugin',
+  'project-apply',
+  'project-inspect',
+  'project-list',
+  'release-demote',
+  'release-list',
+  'release-promote',
+  'runner-adopt',
   'runner-agent',
+  'runner-forget',
+  'runner-inspect',
+  'runner-install',
+  'runner-list',
+  'runner-profile-delete',
+  'runner-profile-list',
+  'runner-profile-set',
+  'runner-reject',
   'server-bootstrap',
   'server-config-set',
   'server-install',
+  'server-maintenance',
+  'server-prune',
+  'server-restore',
   'server-run',
+  'server-snapshot',
+  'server-upgrade',
   'token-exchange',
   'token-invite',
+  'token-list',
   'token-new',
+  'token-revoke',
+  'trigger-create',
+  'trigger-delete',
+  'trigger-list',
+  'user-grant',
+  'user-list',
+  'user-revoke',
   'version',
+  'webhook-create',
+  'webhook-delete',
+  'webhook-list',
 ]
@@ -31,19 +31,32 @@ export default [
     content: [
       'app',
       'build',
+      'config',
+      'data',
       'deploy',
+      'functions',
       'hook',
+      'import',
+      'jobs',
+      'pipeline',
       'plugin',
       'registry',
       'release',
+      'required_plugins',
+      'retention',
+      'runner',
+      'scan',
       'url',
       'use',
+      'variable',
     ],
   },
   {
     category: 'server',
     content: [
       'auth',
+      'http-api',
+      'notifications',
       {
         category: 'run',
         content: ['maintenance', 'production', 'security'],
@@ -91,11 +104,11 @@ export default [
   },
   {
     category: 'entrypoint',
-    content: ['disable'],
+    content: ['disable', 'health', 'restart'],
   },
   {
     category: 'automating-execution',
-    content: ['github-actions', 'gitlab-cicd', 'circle-ci', 'jenkins'],
+    content: ['github-actions', 'gitlab-cicd', 'circle-ci', 'jenkins', 'webhooks'],
   },
   'troubleshooting',
   'glossary',
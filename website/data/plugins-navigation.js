@@ -1,12 +1,16 @@
 export default [
   'aws-ec2',
   'aws-ecs',
+  'azure-app-service',
   'azure-container-instance',
   'docker',
   'exec',
+  'files',
+  'go',
   'google-cloud-run',
   'kubernetes',
   'netlify',
   'nomad',
+  'oci',
   'pack',
 ]